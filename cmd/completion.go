@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0xmhha/txhammer/internal/config"
+)
+
+// registerCompletions adds dynamic shell completion for flags with a fixed
+// set of values. The completion command itself is provided by cobra.
+func registerCompletions(cmd *cobra.Command) {
+	modes := make([]string, 0, len(config.Modes()))
+	for _, mode := range append(config.Modes(), config.CustomModes()...) {
+		modes = append(modes, string(mode))
+	}
+
+	strategies := make([]string, 0, len(config.RecipientStrategies()))
+	for _, strategy := range config.RecipientStrategies() {
+		strategies = append(strategies, string(strategy))
+	}
+
+	values := map[string][]string{
+		"mode":               modes,
+		"recipient-strategy": strategies,
+		"color":              {"auto", "always", "never"},
+		"denomination":       {"auto", "wei", "gwei", "ether"},
+		"export-format":      {"csv", "parquet"},
+		"compress":           {"none", "zstd", "gzip"},
+		"analyze-output":     {"summary", "table", "csv", "json"},
+	}
+	for name, completions := range values {
+		fn := cobra.FixedCompletions(completions, cobra.ShellCompDirectiveNoFileComp)
+		if err := cmd.RegisterFlagCompletionFunc(name, fn); err != nil {
+			panic(fmt.Sprintf("failed to register completion for %s: %v", name, err))
+		}
+	}
+
+	for _, name := range []string{"key-file", "output", "nonce-snapshot", "fixture-cache", "journal", "trace", "scenario"} {
+		if err := cmd.MarkFlagFilename(name); err != nil {
+			panic(fmt.Sprintf("failed to mark %s as a file flag: %v", name, err))
+		}
+	}
+	for _, name := range []string{"output-dir", "spill-dir"} {
+		if err := cmd.MarkFlagDirname(name); err != nil {
+			panic(fmt.Sprintf("failed to mark %s as a directory flag: %v", name, err))
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// defaultConfigFile is the file config init writes when given none
+const defaultConfigFile = "txhammer.yaml"
+
+var (
+	configFile      string
+	configInitForce bool
+)
+
+// applyConfigFile sets the flags of cmd that are not given on the command
+// line from the --config file. Keys that name no flag of cmd are an error.
+func applyConfigFile(cmd *cobra.Command, _ []string) error {
+	flags := cmd.Flags()
+	if flags.Lookup("config") == nil || configFile == "" {
+		return nil
+	}
+	settings, err := config.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range config.SortedKeys(settings) {
+		f := flags.Lookup(name)
+		if f == nil || name == "config" || name == "help" {
+			return fmt.Errorf("config file %s: unknown flag %q", configFile, name)
+		}
+		if f.Changed {
+			// The command line overrides the file
+			continue
+		}
+		if err := setFlag(flags, f, settings[name]); err != nil {
+			return fmt.Errorf("config file %s: %s: %w", configFile, name, err)
+		}
+	}
+	return nil
+}
+
+// setFlag sets flag f to a config file setting
+func setFlag(flags *pflag.FlagSet, f *pflag.Flag, setting config.Setting) error {
+	if !setting.List {
+		return flags.Set(f.Name, setting.Values[0])
+	}
+	if slice, ok := f.Value.(pflag.SliceValue); ok {
+		if err := slice.Replace(setting.Values); err != nil {
+			return err
+		}
+		f.Changed = true
+		return nil
+	}
+	if len(setting.Values) != 1 {
+		return errors.New("takes a single value, not a list")
+	}
+	return flags.Set(f.Name, setting.Values[0])
+}
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage txhammer config files",
+	}
+
+	initCmd := &cobra.Command{
+		Use:   "init [file]",
+		Short: "Write a commented config file template",
+		Long: `Writes a config file listing every flag of a run, grouped as in --help, with its
+description and default value. Keys are flag names; uncomment and edit the ones to
+set and pass the file with --config. The format follows the extension: .yaml, .yml
+or .toml (default ` + defaultConfigFile + `).`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runConfigInit,
+	}
+	initCmd.Flags().BoolVar(&configInitForce, "force", false, "Overwrite an existing file")
+	cmd.AddCommand(initCmd)
+	return cmd
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	path := defaultConfigFile
+	if len(args) > 0 {
+		path = args[0]
+	}
+	format, err := config.FileFormat(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil && !configInitForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+
+	var b strings.Builder
+	writeConfigTemplate(&b, cmd.Root().Flags(), format)
+	// The file may end up holding keys
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	console.OKf("Wrote %s\n", path)
+	return nil
+}
+
+// writeConfigTemplate writes a config file in format with every flag of
+// flags commented out at its default, by help group. Only --url is set.
+func writeConfigTemplate(w io.Writer, flags *pflag.FlagSet, format string) {
+	separator := ": "
+	if format == config.FileFormatTOML {
+		separator = " = "
+	}
+
+	fmt.Fprintf(w, "# txhammer config file, passed with --config.\n")
+	fmt.Fprintf(w, "# Keys are flag names; flags given on the command line override them.\n")
+	fmt.Fprintf(w, "# Uncomment a key to set it; the values shown are the defaults.\n")
+
+	groups := make(map[string][]*pflag.Flag)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "config" || f.Name == "help" {
+			return
+		}
+		var group string
+		if g := f.Annotations[flagGroupAnnotation]; len(g) > 0 {
+			group = g[0]
+		}
+		groups[group] = append(groups[group], f)
+	})
+
+	for _, group := range append(flagGroupOrder, "") {
+		if len(groups[group]) == 0 {
+			continue
+		}
+		title := "Other"
+		if group != "" {
+			title = group
+		}
+		fmt.Fprintf(w, "\n# %s\n# %s\n", title, strings.Repeat("-", len(title)))
+		for _, f := range groups[group] {
+			fmt.Fprintf(w, "\n# %s\n", f.Usage)
+			if f.Name == "url" {
+				fmt.Fprintf(w, "%s%s%q\n", f.Name, separator, "http://localhost:8545")
+				continue
+			}
+			fmt.Fprintf(w, "# %s%s%s\n", f.Name, separator, templateValue(f))
+		}
+	}
+}
+
+// templateValue formats the default of f as a config file value, which
+// reads the same in YAML and TOML
+func templateValue(f *pflag.Flag) string {
+	quoted := strings.Contains(f.Value.Type(), "string")
+	if slice, ok := f.Value.(pflag.SliceValue); ok {
+		elements := append([]string(nil), slice.GetSlice()...)
+		if quoted {
+			for i, e := range elements {
+				elements[i] = strconv.Quote(e)
+			}
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	}
+
+	switch f.Value.Type() {
+	case "bool", "int", "int64", "uint64", "float64":
+		return f.DefValue
+	default:
+		return strconv.Quote(f.DefValue)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/0xmhha/txhammer/internal/config"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.yaml")
+	content := "url: http://file:8545\nmode: CONFLICT\ntransactions: 500\nworkload: [TRANSFER:500, CONTRACT_CALL:50]\nfund-retries: 0\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{Use: "txhammer"}
+	registerFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--config", path, "--mode", "TRANSFER"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfigFile(cmd, nil); err != nil {
+		t.Fatalf("applyConfigFile() error: %v", err)
+	}
+
+	if cfg.URL != "http://file:8545" || cfg.Transactions != 500 || runCfg.FundRetries != 0 {
+		t.Errorf("file values not applied: url %s, transactions %d, fund-retries %d", cfg.URL, cfg.Transactions, runCfg.FundRetries)
+	}
+	if cfg.Mode != "TRANSFER" {
+		t.Errorf("mode = %s, want the command line's TRANSFER", cfg.Mode)
+	}
+	if !slices.Equal(runCfg.Workloads, []string{"TRANSFER:500", "CONTRACT_CALL:50"}) {
+		t.Errorf("workload = %v", runCfg.Workloads)
+	}
+	if err := cmd.ValidateRequiredFlags(); err != nil {
+		t.Errorf("url from the file does not satisfy the required flag: %v", err)
+	}
+
+	// Keys that name no flag are refused
+	if err := os.WriteFile(path, []byte("gas-prise: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd = &cobra.Command{Use: "txhammer"}
+	registerFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--config", path}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfigFile(cmd, nil); err == nil || !strings.Contains(err.Error(), "gas-prise") {
+		t.Errorf("applyConfigFile() error = %v, want unknown flag gas-prise", err)
+	}
+}
+
+func TestWriteConfigTemplate(t *testing.T) {
+	for _, format := range []string{config.FileFormatYAML, config.FileFormatTOML} {
+		cmd := &cobra.Command{Use: "txhammer"}
+		registerFlags(cmd)
+		var b strings.Builder
+		writeConfigTemplate(&b, cmd.Flags(), format)
+
+		// Uncommenting every key must give a file that sets every flag to its default
+		key := regexp.MustCompile(`(?m)^# ([a-z0-9-]+)(: | = )`)
+		path := filepath.Join(t.TempDir(), "full."+format)
+		if err := os.WriteFile(path, []byte(key.ReplaceAllString(b.String(), "$1$2")), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		settings, err := config.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: template does not read back: %v", format, err)
+		}
+		want := 0
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if f.Name != "config" && f.Name != "help" {
+				want++
+			}
+		})
+		if len(settings) != want {
+			t.Errorf("%s: template has %d keys, want %d", format, len(settings), want)
+		}
+
+		if err := cmd.ParseFlags([]string{"--config", path}); err != nil {
+			t.Fatal(err)
+		}
+		if err := applyConfigFile(cmd, nil); err != nil {
+			t.Errorf("%s: applyConfigFile() error: %v", format, err)
+		}
+		if cfg.Transactions != 100 || cfg.Mode != "TRANSFER" {
+			t.Errorf("%s: defaults not kept: transactions %d, mode %s", format, cfg.Transactions, cfg.Mode)
+		}
+	}
+}
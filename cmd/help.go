@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagGroupAnnotation is the flag annotation naming a flag's help group
+const flagGroupAnnotation = "txhammer_flag_group"
+
+// Flag help groups
+const (
+	groupConnection = "Connection"
+	groupWorkload   = "Workload"
+	groupGas        = "Gas & Fees"
+	groupOutput     = "Output"
+	groupMetrics    = "Metrics"
+	groupMode       = "Mode-Specific"
+	groupBench      = "Bench"
+	groupFork       = "Fork"
+)
+
+// flagGroupOrder is the order groups are printed in; ungrouped flags such as
+// --help follow under "Flags"
+var flagGroupOrder = []string{groupConnection, groupWorkload, groupGas, groupOutput, groupMetrics, groupMode, groupBench, groupFork}
+
+// defaultFlagsSection is the local flags section of cobra's usage template
+const defaultFlagsSection = "\n\nFlags:\n{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}"
+
+// useGroupedHelp prints the local flags of cmd and its subcommands by group
+func useGroupedHelp(cmd *cobra.Command) {
+	cobra.AddTemplateFunc("groupedFlagUsages", groupedFlagUsages)
+	cmd.SetUsageTemplate(strings.Replace(cmd.UsageTemplate(), defaultFlagsSection, "{{groupedFlagUsages .LocalFlags}}", 1))
+}
+
+// setFlagGroup assigns flags to a help group
+func setFlagGroup(flags *pflag.FlagSet, group string, names ...string) {
+	for _, name := range names {
+		if err := flags.SetAnnotation(name, flagGroupAnnotation, []string{group}); err != nil {
+			panic(fmt.Sprintf("failed to group flag %s: %v", name, err))
+		}
+	}
+}
+
+// groupedFlagUsages renders flags in one section per help group
+func groupedFlagUsages(flags *pflag.FlagSet) string {
+	groups := make(map[string]*pflag.FlagSet)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		var group string
+		if g := f.Annotations[flagGroupAnnotation]; len(g) > 0 {
+			group = g[0]
+		}
+		if groups[group] == nil {
+			groups[group] = pflag.NewFlagSet(group, pflag.ContinueOnError)
+			groups[group].SortFlags = flags.SortFlags
+		}
+		groups[group].AddFlag(f)
+	})
+
+	var b strings.Builder
+	for _, group := range append(flagGroupOrder, "") {
+		set := groups[group]
+		if set == nil {
+			continue
+		}
+		title := "Flags"
+		if group != "" {
+			title = group + " Flags"
+		}
+		fmt.Fprintf(&b, "\n\n%s:\n%s", title, strings.TrimRight(set.FlagUsages(), " \n"))
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestRegisterFlags_AllGrouped(t *testing.T) {
+	cmd := &cobra.Command{Use: "txhammer"}
+	registerFlags(cmd)
+	registerBenchFlags(cmd)
+	registerAccountBenchFlags(cmd)
+	registerForkFlags(cmd)
+
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if len(f.Annotations[flagGroupAnnotation]) == 0 {
+			t.Errorf("flag --%s has no help group", f.Name)
+		}
+	})
+}
+
+func TestGroupedFlagUsages(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("url", "", "RPC endpoint URL")
+	flags.Bool("metrics", false, "Enable metrics")
+	flags.Bool("help", false, "help")
+	setFlagGroup(flags, groupConnection, "url")
+	setFlagGroup(flags, groupMetrics, "metrics")
+
+	usage := groupedFlagUsages(flags)
+	connection := strings.Index(usage, "Connection Flags:")
+	metrics := strings.Index(usage, "Metrics Flags:")
+	other := strings.Index(usage, "\nFlags:")
+	if connection < 0 || metrics < connection || other < metrics {
+		t.Fatalf("sections missing or out of order:\n%s", usage)
+	}
+	if url := strings.Index(usage, "--url"); url < connection || url > metrics {
+		t.Errorf("--url not in the Connection section:\n%s", usage)
+	}
+}
+
+func TestUseGroupedHelp(t *testing.T) {
+	cmd := &cobra.Command{Use: "txhammer", Run: func(*cobra.Command, []string) {}}
+	registerFlags(cmd)
+	useGroupedHelp(cmd)
+
+	var out strings.Builder
+	cmd.SetOut(&out)
+	if err := cmd.Usage(); err != nil {
+		t.Fatal(err)
+	}
+	for _, group := range []string{groupConnection, groupWorkload, groupGas, groupOutput, groupMetrics, groupMode} {
+		if !strings.Contains(out.String(), group+" Flags:") {
+			t.Errorf("usage has no %s section", group)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/0xmhha/txhammer/internal/buildinfo"
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/journal"
+	"github.com/0xmhha/txhammer/internal/pipeline"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+var (
+	reconcileURL   string
+	reconcileLimit int
+
+	collectCfg     = &config.Config{}
+	collectRunCfg  = pipeline.DefaultRunConfig()
+	collectJournal string
+)
+
+func newReconcileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reconcile <journal>",
+		Short: "Look up the transactions of a --journal file on chain",
+		Long: `Reads a write-ahead journal written with --journal and looks up every recorded
+transaction, so a run that crashed or was killed after sending can still be
+accounted for. Each transaction is reported as confirmed, reverted, pending in
+the pool, replaced (its nonce was used by another transaction) or missing.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runReconcile,
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&reconcileURL, "url", "", "RPC endpoint URL of the chain the journal was written on")
+	flags.IntVar(&reconcileLimit, "limit", 20, "Unresolved transactions listed (0 = all)")
+	if err := cmd.MarkFlagRequired("url"); err != nil {
+		panic(fmt.Sprintf("failed to mark url flag as required: %v", err))
+	}
+	return cmd
+}
+
+func newCollectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collect",
+		Short: "Collect receipts and export a report for the transactions of a --journal file",
+		Long: `Runs only the collect and report stages of txhammer: loads the hashes and send
+times recorded with --journal, polls their receipts and exports the same report
+files as a normal run. Use it for post-mortem accounting after a crash or a
+--skip-collection run.`,
+		Args: cobra.NoArgs,
+		RunE: runCollect,
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&collectJournal, "from-journal", "", "Journal file written with --journal")
+	flags.StringVar(&collectCfg.URL, "url", "", "RPC endpoint URL of the chain the journal was written on")
+	flags.DurationVar(&collectCfg.Timeout, "timeout", time.Minute, "How long to wait for receipts of unmined transactions")
+	flags.BoolVar(&collectRunCfg.ExportReport, "export", true, "Export report to files")
+	flags.StringVar(&collectRunCfg.OutputDir, "output-dir", "./reports", "Output directory for reports")
+	flags.StringVar(&collectRunCfg.DatasetFormat, "export-format", "csv", "Format for transaction and block datasets (csv, parquet)")
+	for _, name := range []string{"from-journal", "url"} {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(fmt.Sprintf("failed to mark %s flag as required: %v", name, err))
+		}
+	}
+	return cmd
+}
+
+func runReconcile(_ *cobra.Command, args []string) error {
+	j, err := loadJournal(args[0])
+	if err != nil {
+		return err
+	}
+	chainID, err := j.ChainID()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	c, err := client.New(reconcileURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer c.Close()
+
+	idCtx, idCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer idCancel()
+	nodeChainID, err := c.ChainID(idCtx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if nodeChainID.Uint64() != chainID {
+		return fmt.Errorf("journal was written on chain %d, but %s is chain %d", chainID, reconcileURL, nodeChainID)
+	}
+
+	console.Printf("Reconciling %d transactions from %s...\n", len(j.Entries), args[0])
+	result, err := journal.Reconcile(ctx, c, j.Entries)
+	if err != nil {
+		return fmt.Errorf("reconcile failed: %w", err)
+	}
+	journal.PrintResult(result, j, reconcileLimit)
+	return nil
+}
+
+func runCollect(_ *cobra.Command, _ []string) error {
+	if err := collectRunCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	j, err := loadJournal(collectJournal)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	console.Printf("Collecting %d transactions from %s...\n", len(j.Entries), collectJournal)
+	report, err := pipeline.CollectJournal(ctx, collectCfg, collectRunCfg, buildinfo.New(version, commit, date), j)
+	if err != nil {
+		return err
+	}
+	if report.Metrics.TotalFailed > 0 || report.Metrics.TotalTimeout > 0 {
+		return fmt.Errorf("%d transactions failed and %d were not mined", report.Metrics.TotalFailed, report.Metrics.TotalTimeout)
+	}
+	return nil
+}
+
+// loadJournal reads a journal file, warning about a cut-off last line
+func loadJournal(path string) (*journal.Journal, error) {
+	j, err := journal.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if j.Truncated {
+		console.Warnf("The last journal line was cut off and is skipped\n")
+	}
+	return j, nil
+}
@@ -2,34 +2,99 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/0xmhha/txhammer/internal/bench"
+	"github.com/0xmhha/txhammer/internal/buildinfo"
 	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/fork"
+	"github.com/0xmhha/txhammer/internal/manifest"
 	"github.com/0xmhha/txhammer/internal/pipeline"
+	"github.com/0xmhha/txhammer/internal/util/console"
 )
 
 var (
-	version = "dev"
-	cfg     = &config.Config{}
-	runCfg  = &pipeline.RunConfig{}
+	version  = "dev"
+	commit   = "unknown"
+	date     = "unknown"
+	cfg      = &config.Config{}
+	runCfg   = &pipeline.RunConfig{}
+	benchCfg = bench.DefaultConfig()
+	acctCfg  = bench.DefaultAccountConfig()
+	forkCfg  = fork.DefaultConfig()
 )
 
 func main() {
+	build := buildinfo.New(version, commit, date)
 	rootCmd := &cobra.Command{
 		Use:     "txhammer",
 		Short:   "StableNet stress testing tool",
 		Long:    `TxHammer is a CLI tool for stress testing StableNet L1 blockchain networks.`,
-		Version: version,
+		Version: build.String(),
 		RunE:    run,
+
+		PersistentPreRunE: applyConfigFile,
 	}
+	rootCmd.SetVersionTemplate("{{.Name}} {{.Version}}\n" + build.Details())
 
 	// Register flags
 	registerFlags(rootCmd)
+	useGroupedHelp(rootCmd)
+
+	benchCmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Sweep batch sizes and concurrency levels and recommend the fastest setting",
+		Long: `Runs a series of short sends against the target node with varying batch sizes and
+concurrency levels, prints the achieved throughput and error rate of each setting,
+and recommends --batch and --max-concurrent values.`,
+		RunE: runBench,
+	}
+	registerFlags(benchCmd)
+	registerBenchFlags(benchCmd)
+	rootCmd.AddCommand(benchCmd)
+
+	accountsCmd := &cobra.Command{
+		Use:   "accounts",
+		Short: "Sweep sub-account counts at a fixed transaction total",
+		Long: `Sends the same number of transactions from increasing numbers of sub-accounts and
+reports how chain throughput scales with sender parallelism, showing where nonce
+serialization stops being the bottleneck.`,
+		RunE: runAccountBench,
+	}
+	registerFlags(accountsCmd)
+	registerAccountBenchFlags(accountsCmd)
+	benchCmd.AddCommand(accountsCmd)
+
+	forkCmd := &cobra.Command{
+		Use:   "fork",
+		Short: "Compare the workload right before and right after a fork activation",
+		Long: `Runs the configured workload twice against a chain with a scheduled upgrade: once
+in the blocks leading up to --activation-block, stopping at it, and once as soon as
+the chain has reached it. Prints TPS, latency and gas usage of both phases side by
+side with the change of each metric.`,
+		RunE: runFork,
+	}
+	registerFlags(forkCmd)
+	registerForkFlags(forkCmd)
+	rootCmd.AddCommand(forkCmd)
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "verify <manifest>",
+		Short: "Check the artifacts of a run against its manifest",
+		Long: `Re-hashes every file listed in a run manifest (manifest_*.json in the output
+directory) and reports files that are missing or whose SHA-256 no longer matches.
+Exits with an error if any artifact fails the check.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runVerify,
+	})
+	rootCmd.AddCommand(newReconcileCmd(), newCollectCmd(), newConfigCmd(), newVerifyReportCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -40,54 +105,132 @@ func main() {
 func registerFlags(cmd *cobra.Command) {
 	flags := cmd.Flags()
 
+	flags.StringVar(&configFile, "config", "", "YAML or TOML file of flag values keyed by flag name; flags given on the command line override it")
+
 	// Required flags
-	flags.StringVar(&cfg.URL, "url", "", "RPC endpoint URL (required)")
+	flags.StringVar(&cfg.URL, "url", "", "RPC endpoint URL (required); further comma-separated URLs join --endpoints")
 	flags.StringVar(&cfg.PrivateKey, "private-key", "", "Master account private key (hex)")
 	flags.StringVar(&cfg.Mnemonic, "mnemonic", "", "BIP39 mnemonic (alternative to private-key)")
+	flags.StringVar(&cfg.KeyFile, "key-file", "", "File of sub-account private keys (one hex key per line or JSON array); overrides --sub-accounts")
+	flags.BoolVar(&cfg.EphemeralAccounts, "ephemeral-accounts", false, "Generate fresh sub-account keys for this run and sweep their funds back to the master at the end")
+	flags.StringVar(&cfg.MasterKMS, "master-kms", "", "Sign for the master account with a KMS key instead of a local key (awskms://<key-id> or gcpkms://<key-version>); credentials from the AWS environment, profile or task/instance role, or from GOOGLE_OAUTH_ACCESS_TOKEN or Google Application Default Credentials")
 
 	// Test configuration
-	flags.StringVar(&cfg.Mode, "mode", "TRANSFER", "Test mode: TRANSFER, FEE_DELEGATION, CONTRACT_DEPLOY, CONTRACT_CALL, ERC20_TRANSFER, LONG_SENDER, ANALYZE_BLOCKS, ERC721_MINT")
+	flags.StringVar(&cfg.Mode, "mode", "TRANSFER", "Test mode: TRANSFER, FEE_DELEGATION, CONTRACT_DEPLOY, CONTRACT_CALL, ERC20_TRANSFER, LONG_SENDER, ANALYZE_BLOCKS, ERC721_MINT, TARGET_UTILIZATION, CONFLICT, CREATE2_CHURN, ACCOUNT_GROWTH, DEPLOY_THEN_CALL, CONTENTION, CHAIN, APPROVE_TRANSFERFROM, SWAP, SCENARIO")
 	flags.Uint64Var(&cfg.SubAccounts, "sub-accounts", 10, "Number of sub-accounts")
 	flags.Uint64Var(&cfg.Transactions, "transactions", 100, "Total number of transactions")
 	flags.Uint64Var(&cfg.BatchSize, "batch", 100, "Batch size for JSON-RPC requests")
+	flags.StringSliceVar(&runCfg.Workloads, "workload", nil, "Run MODE:TPS workloads concurrently from separate sub-accounts, splitting --transactions and --sub-accounts by rate (repeatable, e.g. TRANSFER:500,CONTRACT_CALL:50)")
+	flags.Uint64Var(&runCfg.StopAtBlock, "stop-at-block", 0, "Stop sending once the chain head reaches this block and report metrics before and after it separately (0 = disabled)")
 
 	// Chain configuration
 	flags.Uint64Var(&cfg.ChainID, "chain-id", 0, "Chain ID (auto-detect if not specified)")
-	flags.Uint64Var(&cfg.GasLimit, "gas-limit", 21000, "Gas limit per transaction")
+	flags.Uint64Var(&cfg.GasLimit, "gas-limit", 0, "Gas limit per transaction (0 = the mode's default)")
 	flags.StringVar(&cfg.GasPrice, "gas-price", "", "Gas price (auto if not specified)")
-	flags.StringVar(&cfg.Value, "value", "1", "Transfer value in wei (default: 1)")
+	flags.StringVar(&cfg.TxType, "tx-type", "", "Transaction type in TRANSFER, CONTRACT_CALL and ERC20_TRANSFER modes: legacy, eip2930 or eip1559 (default: legacy for TRANSFER, else eip1559)")
+	flags.StringVar(&cfg.Value, "value", "", "Value in wei sent with each transaction in TRANSFER, FEE_DELEGATION, LONG_SENDER, TARGET_UTILIZATION and CONTRACT_CALL modes (default: 1, or 0 for CONTRACT_CALL)")
+	flags.StringVar(&cfg.Recipient, "recipient", "", "Account or contract every transfer is sent to (implies --recipient-strategy fixed)")
+	flags.StringVar(&cfg.RecipientStrategy, "recipient-strategy", "", "Transfer recipient: self, fixed (--recipient) or ring (next sub-account) (default: self)")
 
 	// Fee Delegation mode
 	flags.StringVar(&cfg.FeePayerKey, "fee-payer-key", "", "Fee payer private key for FEE_DELEGATION mode")
+	flags.Uint64Var(&cfg.FeePayerIndex, "fee-payer-index", 0, "Derive the FEE_DELEGATION fee payer from --mnemonic at this index (must exceed --sub-accounts)")
 
 	// Contract mode
 	flags.StringVar(&cfg.Contract, "contract", "", "Target contract address")
 	flags.StringVar(&cfg.Method, "method", "", "Contract method signature")
 	flags.StringVar(&cfg.Args, "args", "", "Method arguments (JSON array)")
+	flags.StringArrayVar(&runCfg.ExpectEvents, "expect-event", nil, "Event signature a successful tx should emit, e.g. \"TransferSingle(address,address,address,uint256,uint256)\" (repeatable; replaces the mode's default, none = no check)")
 
 	// Output
 	flags.StringVar(&cfg.Output, "output", "", "Output JSON file path")
 	flags.BoolVar(&cfg.Verbose, "verbose", false, "Enable verbose logging")
+	flags.BoolVarP(&cfg.Quiet, "quiet", "q", false, "Print only the final summary, warnings and errors")
+	flags.StringVar(&cfg.Color, "color", "auto", "Color status markers: auto (when stdout is a terminal), always, never")
+	flags.BoolVar(&runCfg.JSONSummary, "json-summary", false, "Print the result as one JSON document on stdout; all other output goes to stderr as with --quiet")
+	flags.StringVar(&runCfg.SummaryLevel, "summary-level", "normal", "Detail of the final summary: minimal (one-line verdict only), normal or full (with per-tx and per-producer tables)")
 
 	// Advanced
 	flags.DurationVar(&cfg.Timeout, "timeout", 0, "Timeout duration (default: 5m)")
 	flags.Uint64Var(&cfg.RateLimit, "rate-limit", 0, "Max transactions per second (0 = unlimited)")
 
 	// Run configuration flags
-	flags.BoolVar(&runCfg.SkipDistribution, "skip-distribution", false, "Skip fund distribution (assume accounts are funded)")
+	flags.BoolVar(&runCfg.SkipDistribution, "skip-distribution", false, "Skip fund distribution and only verify that accounts are funded")
+	flags.StringVar(&runCfg.FundAmount, "fund-amount", "", "Fund each sub-account up to this amount (e.g. 0.1ether, or wei) instead of the computed gas and value cost")
+	flags.StringVar(&runCfg.FundExtra, "fund-extra", "", "Extra amount (e.g. 0.01ether, or wei) added to each sub-account's funding target")
+	flags.IntVar(&runCfg.FundRetries, "fund-retries", 2, "Times sub-accounts whose funding failed or did not confirm are funded again, at a raised gas price")
+	flags.IntVar(&runCfg.PriceBump, "price-bump", 10, "Percent a funding retry raises the gas price at least, to meet the node's replacement price bump (txpool.pricebump)")
 	flags.BoolVar(&runCfg.SkipCollection, "skip-collection", false, "Skip receipt collection (fire-and-forget mode)")
+	flags.BoolVar(&runCfg.CollectDuringSend, "collect-during-send", false, "Poll receipts while sending, timing each tx from the node's acknowledgement")
+	flags.BoolVar(&runCfg.SubscribeReceipts, "subscribe-receipts", false, "Collect receipts per block on a newHeads subscription instead of polling each tx (WebSocket URLs)")
 	flags.BoolVar(&runCfg.ExportReport, "export", true, "Export report to files")
 	flags.StringVar(&runCfg.OutputDir, "output-dir", "./reports", "Output directory for reports")
+	flags.StringVar(&runCfg.DatasetFormat, "export-format", "csv", "Format for transaction and block datasets (csv, parquet)")
+	flags.StringVar(&runCfg.Compression, "compress", "none", "Compress the CSV transaction and block datasets and nonce samples (none, zstd, gzip)")
+	flags.IntVar(&runCfg.MaxConcurrent, "max-concurrent", 100, "Max concurrent batch requests in batch mode")
+	flags.IntVar(&runCfg.ConfirmConcurrency, "confirm-concurrency", 20, "Max concurrent receipt queries while collecting")
+	flags.BoolVar(&runCfg.AutoConcurrency, "auto-concurrency", false, "Probe the node at start and set --max-concurrent and --confirm-concurrency from its latency and error rate")
+	flags.IntVar(&runCfg.QuarantineAfter, "quarantine-after", 0, "Consecutive account failures (nonce too low, insufficient funds, underpriced replacement) after which the long sender quarantines an account (0 = never)")
+	flags.Float64Var(&runCfg.AlertFailureRate, "alert-failure-rate", 0, "Warn during the run when more than this percent of sends fail within --alert-window (0 = disabled)")
+	flags.DurationVar(&runCfg.AlertWindow, "alert-window", 10*time.Second, "Sliding window for --alert-failure-rate")
+	flags.IntVar(&runCfg.TxsPerBlock, "per-block", 0, "Release this many txs right after each new head instead of sending free-running (0 = disabled)")
 	flags.BoolVar(&runCfg.StreamingMode, "streaming", false, "Use streaming mode instead of batch mode")
 	flags.Float64Var(&runCfg.StreamingRate, "streaming-rate", 1000, "Rate limit for streaming mode (tx/s)")
+	flags.IntVar(&runCfg.Burst, "burst", 0, "Rate limiter burst for streaming and LONG_SENDER/TARGET_UTILIZATION modes (0 = 100 when streaming, tps/10 but at least 10 for the long sender)")
+	flags.IntVar(&runCfg.ConnectionsPerWorker, "connections-per-worker", 0, "Dedicated RPC connections per streaming worker (0 = share one client)")
+	flags.IntVar(&runCfg.Redundancy, "redundancy", 0, "Submit every tx to this many of --url and --endpoints at once and report duplicate acceptance (0 = --url only)")
+	flags.StringVar(&runCfg.Balance, "balance", "", "Spread sends over --url and --endpoints with failover: round-robin or least-latency (empty = round-robin for a comma-separated --url, else --url only)")
+	flags.BoolVar(&runCfg.Prewarm, "prewarm", false, "Resolve DNS once and open and warm the send connections before the send stage")
+	flags.IntVar(&runCfg.PrewarmCalls, "prewarm-calls", 3, "No-op calls made on each connection when pre-warming")
+	flags.DurationVar(&runCfg.ThrottleBackoff, "throttle-backoff", 10*time.Second, "Longest pause of an endpoint after it rate-limits requests (0 = only report throttling)")
+	flags.StringVar(&runCfg.P2PEnode, "p2p-enode", "", "Send transactions to this enode over devp2p (eth/68) instead of JSON-RPC (experimental)")
+	flags.BoolVar(&runCfg.P2PCompare, "p2p-compare", false, "Send from half the accounts over JSON-RPC and half over devp2p, and compare the two")
+	flags.BoolVar(&runCfg.MempoolDiff, "mempool-diff", false, "Snapshot txpool_content before and after the run and report how foreign txs were included, delayed or displaced")
+	flags.BoolVar(&runCfg.AllowSyncing, "allow-syncing", false, "Start even when the node reports it is still syncing (eth_syncing)")
+	flags.Uint64Var(&runCfg.MinPeers, "min-peers", 0, "Abort unless the node reports at least this many peers (net_peerCount; 0 = no check)")
+	flags.BoolVar(&runCfg.Strict, "strict", false, "Abort when the preflight finds the gas limit and target rate cannot fit into the chain's blocks")
+	flags.StringVar(&runCfg.MinTip, "min-tip", "", "Floor for the priority fee (tip) of built transactions (e.g. 1gwei)")
+	flags.StringVar(&runCfg.MaxTip, "max-tip", "", "Ceiling for the priority fee (tip) of built transactions")
+	flags.StringVar(&runCfg.MinFeeCap, "min-fee-cap", "", "Floor for the max fee per gas of built transactions")
+	flags.StringVar(&runCfg.MaxFeeCap, "max-fee-cap", "", "Ceiling for the max fee per gas of built transactions")
+	flags.BoolVar(&runCfg.L1Fees, "l1-fees", false, "Read the L1 data fee of rollup receipts (l1Fee, l1GasUsed) and include it in tx and total costs")
+	flags.BoolVar(&runCfg.ZeroFee, "zero-fee", false, "Send every tx with a zero gas price and fund sub-accounts only for the value they send (detected automatically on zero-fee networks)")
+	flags.StringVar(&runCfg.MaxSpend, "max-spend", "", "Fee budget (e.g. 0.5ether, 200gwei, or wei); sending stops before it would be exceeded")
 	flags.BoolVar(&runCfg.DryRun, "dry-run", false, "Build transactions but don't send them")
+	flags.StringVar(&runCfg.SaveTxs, "save-txs", "", "Write the built signed transactions to this file for --replay-txs (.zst or .gz compresses it)")
+	flags.StringVar(&runCfg.ReplayTxs, "replay-txs", "", "Send the signed transactions of a --save-txs file instead of building new ones")
+	flags.BoolVar(&runCfg.PruneInvalid, "prune-invalid", false, "Drop duplicate, nonce-conflicting and wrong-chain txs before sending instead of only reporting them")
+	flags.StringVar(&runCfg.FixtureCache, "fixture-cache", ".txhammer-fixtures.json", "File recording deployed helper contracts per chain ID for reuse across runs (empty = always deploy)")
+	flags.BoolVar(&runCfg.Redeploy, "redeploy", false, "Deploy helper contracts even if the fixture cache holds a usable deployment")
+	flags.DurationVar(&runCfg.SendDeadline, "send-deadline", 0, "Drop txs not accepted by the RPC within this long of their send slot and count them as expired (0 = no deadline)")
+	flags.DurationVar(&runCfg.MaxRuntime, "max-runtime", 0, "Deadline for the whole run; a partial report is produced when exceeded (0 = unlimited)")
+	flags.DurationVar(&runCfg.HaltWindow, "halt-window", 0, "Pause sending when no new block is seen for this long (0 = disabled)")
+	flags.StringVar(&runCfg.Denomination, "denomination", "auto", "Unit for wei amounts in console output (wei, gwei, ether, auto)")
+	flags.IntVar(&runCfg.Decimals, "decimals", 6, "Decimal places for gwei and ether amounts in console output")
+	flags.IntVar(&runCfg.TopTxs, "top-txs", 10, "Number of slowest and fastest confirmed txs listed in the report (0 = disabled)")
+	flags.DurationVar(&runCfg.PeakWindow, "peak-window", 10*time.Second, "Chain-time window over which the peak confirmed TPS is reported (0 = disabled)")
+	flags.DurationVar(&runCfg.HeatmapInterval, "heatmap-interval", 10*time.Second, "Confirmation time interval of the latency heatmap in the JSON report (0 = disabled)")
+	flags.StringVar(&runCfg.Region, "region", "", "Label of the region or worker running the test, added to reports, datasets and metrics")
+	flags.DurationVar(&runCfg.NonceSampleInterval, "nonce-sample-interval", 0, "Sample every sub-account's nonce at this interval during send and collection and export the progression (0 = disabled)")
+	flags.BoolVar(&runCfg.FlightRecorder, "flight-recorder", true, "Append sent, confirmed, failed, TPS, pending and pool depth to a CSV in the output directory once per second")
+	flags.BoolVar(&runCfg.ConsistencyCheck, "consistency-check", false, "After collection, compare hashes and state roots of the blocks holding the test txs across --url and --endpoints")
+	flags.IntVar(&runCfg.CaptureFailures, "capture-failures", 0, "Record the full JSON-RPC request and response of the first N failed sends over HTTP to failures_<timestamp>.jsonl (0 = disabled)")
+	flags.StringVar(&runCfg.TraceFile, "trace", "", "Write stage, batch and receipt polling timings to this Chrome trace-event file (open in Perfetto)")
+	flags.Float64Var(&runCfg.PoisonRate, "poison-rate", 0, "Percent of extra, deliberately invalid txs sent alongside the load (0 = disabled)")
+	flags.IntVar(&runCfg.MemoryCap, "collector-memory-cap", 0, "Tx records kept in memory during collection; finished ones beyond it spill to disk (0 = unlimited)")
+	flags.StringVar(&runCfg.SpillDir, "spill-dir", "", "Directory for the collector's temporary spill store (default: system temp dir)")
+	flags.Uint64Var(&runCfg.EvictionBlocks, "eviction-blocks", 0, "Classify txs as evicted when unknown to the node after N blocks (0 = disabled)")
+	flags.StringVar(&runCfg.NonceSnapshot, "nonce-snapshot", "", "Nonce snapshot JSON file (written after sending)")
+	flags.BoolVar(&runCfg.TrustNonceSnapshot, "trust-nonce-snapshot", false, "Load initial nonces from --nonce-snapshot instead of querying the node")
+	flags.StringVar(&runCfg.Journal, "journal", "", "Append every acknowledged tx hash to this write-ahead journal (check later with txhammer reconcile)")
 
 	// Prometheus metrics flags
 	flags.BoolVar(&cfg.MetricsEnabled, "metrics", false, "Enable Prometheus metrics endpoint")
 	flags.IntVar(&cfg.MetricsPort, "metrics-port", 9090, "Port for Prometheus metrics endpoint")
+	flags.StringVar(&cfg.PushGateway, "pushgateway", "", "Pushgateway URL to push the end-of-run summary metrics to (e.g. http://localhost:9091)")
 
 	// Long Sender mode flags
-	flags.DurationVar(&cfg.Duration, "duration", 0, "Test duration for LONG_SENDER mode (e.g., 5m, 1h, 24h)")
+	flags.DurationVar(&cfg.Duration, "duration", 0, "Test duration for LONG_SENDER and ACCOUNT_GROWTH modes (e.g., 5m, 1h, 24h)")
 	flags.Float64Var(&cfg.TargetTPS, "tps", 100, "Target TPS for LONG_SENDER mode")
 	flags.IntVar(&cfg.Workers, "workers", 10, "Number of concurrent workers for LONG_SENDER mode")
 
@@ -95,36 +238,250 @@ func registerFlags(cmd *cobra.Command) {
 	flags.Int64Var(&cfg.BlockStart, "block-start", 0, "Start block number for ANALYZE_BLOCKS mode")
 	flags.Int64Var(&cfg.BlockEnd, "block-end", 0, "End block number for ANALYZE_BLOCKS mode")
 	flags.Int64Var(&cfg.BlockRange, "block-range", 100, "Number of recent blocks to analyze for ANALYZE_BLOCKS mode")
+	flags.StringSliceVar(&cfg.AnalyzeOutput, "analyze-output", []string{"table", "csv"}, "ANALYZE_BLOCKS outputs, any of: summary, table, csv, json")
+	flags.IntVar(&cfg.TableLimit, "table-limit", 100, "Blocks per page of the ANALYZE_BLOCKS table (0 = all)")
+	flags.IntVar(&cfg.TablePage, "table-page", 1, "Page of the ANALYZE_BLOCKS table to print")
+	flags.IntVar(&cfg.EmptyStreak, "empty-streak", 3, "Report runs of at least this many empty blocks as anomalies (0 = off)")
+	flags.Float64Var(&cfg.BlockTimeSpike, "block-time-spike", 3, "Report block times above this multiple of the median as anomalies (0 = off)")
+	flags.Float64Var(&cfg.UtilizationCliff, "utilization-cliff", 50, "Report utilization drops of at least this many percentage points as anomalies (0 = off)")
 
 	// ERC721 Mint mode flags
 	flags.StringVar(&cfg.NFTName, "nft-name", "TxHammerNFT", "NFT collection name for ERC721_MINT mode")
 	flags.StringVar(&cfg.NFTSymbol, "nft-symbol", "TXHNFT", "NFT collection symbol for ERC721_MINT mode")
 	flags.StringVar(&cfg.TokenURI, "token-uri", "https://txhammer.io/nft/", "Base token URI for ERC721_MINT mode")
 
+	// Target Utilization mode flags
+	flags.Float64Var(&cfg.TargetUtilization, "target-utilization", 80, "Target average block gas utilization percent for TARGET_UTILIZATION mode")
+
+	// Conflict mode flags
+	flags.StringSliceVar(&cfg.Endpoints, "endpoints", nil, "Extra RPC endpoints that CONFLICT mode spreads same-nonce variants over and --redundancy submits to")
+	flags.IntVar(&cfg.ConflictVariants, "conflict-variants", 2, "Differing transactions sent per nonce in CONFLICT mode")
+
+	// CREATE2 churn mode flags
+	flags.IntVar(&cfg.ChurnCount, "churn-count", 10, "Contracts created and self-destructed per transaction in CREATE2_CHURN mode")
+
+	// Deploy-then-call mode flags
+	flags.IntVar(&cfg.DeployCount, "deploy-count", 10, "Contracts deployed before DEPLOY_THEN_CALL spreads its calls over them")
+
+	// Account growth mode flags
+	flags.Float64Var(&cfg.GrowthRate, "growth-rate", 1, "New accounts created and funded per second in ACCOUNT_GROWTH mode")
+	flags.Float64Var(&cfg.GrowthAcceleration, "growth-acceleration", 0, "Percent the ACCOUNT_GROWTH account rate grows per minute (0 = constant)")
+	flags.IntVar(&cfg.GrowthTxs, "growth-txs", 3, "Transactions each new account sends before retiring in ACCOUNT_GROWTH mode")
+
+	// Swap mode flags
+	flags.StringSliceVar(&cfg.SwapPath, "swap-path", nil, "Token path SWAP mode swaps along through the Uniswap V2 router at --contract")
+	flags.StringVar(&cfg.SwapAmount, "swap-amount", "", "Input amount of each SWAP transaction, in token base units (default 10^15)")
+
+	// Scenario mode flags
+	flags.StringVar(&cfg.Scenario, "scenario", "", "YAML file of the phases SCENARIO mode runs one after the other")
+
+	// Help groups
+	setFlagGroup(flags, groupConnection,
+		"config", "url", "private-key", "mnemonic", "key-file", "master-kms", "ephemeral-accounts", "chain-id", "timeout",
+		"connections-per-worker", "endpoints", "consistency-check", "redundancy", "balance", "prewarm", "prewarm-calls", "throttle-backoff", "p2p-enode")
+	setFlagGroup(flags, groupWorkload,
+		"mode", "workload", "stop-at-block", "sub-accounts", "transactions", "batch", "value", "recipient", "recipient-strategy", "rate-limit",
+		"skip-distribution", "fund-amount", "fund-extra", "fund-retries", "price-bump", "skip-collection", "collect-during-send", "subscribe-receipts", "max-concurrent", "confirm-concurrency", "auto-concurrency",
+		"per-block", "streaming", "streaming-rate", "burst", "p2p-compare", "strict", "allow-syncing", "min-peers", "dry-run", "save-txs", "replay-txs", "prune-invalid", "fixture-cache",
+		"redeploy", "max-runtime", "send-deadline", "halt-window", "poison-rate", "collector-memory-cap",
+		"spill-dir", "eviction-blocks", "nonce-snapshot", "trust-nonce-snapshot", "journal", "quarantine-after", "alert-failure-rate", "alert-window")
+	setFlagGroup(flags, groupGas,
+		"gas-limit", "gas-price", "tx-type", "min-tip", "max-tip", "min-fee-cap", "max-fee-cap", "max-spend", "l1-fees", "zero-fee")
+	setFlagGroup(flags, groupOutput,
+		"output", "verbose", "quiet", "color", "json-summary", "summary-level", "export", "output-dir", "export-format", "compress",
+		"denomination", "decimals", "top-txs", "peak-window", "heatmap-interval", "trace", "capture-failures", "nonce-sample-interval", "flight-recorder", "region")
+	setFlagGroup(flags, groupMetrics, "metrics", "metrics-port", "pushgateway", "mempool-diff")
+	setFlagGroup(flags, groupMode,
+		"fee-payer-key", "fee-payer-index", "contract", "method", "args", "expect-event",
+		"duration", "tps", "workers",
+		"block-start", "block-end", "block-range", "analyze-output", "table-limit", "table-page",
+		"empty-streak", "block-time-spike", "utilization-cliff",
+		"nft-name", "nft-symbol", "token-uri", "target-utilization",
+		"conflict-variants", "churn-count", "deploy-count",
+		"growth-rate", "growth-acceleration", "growth-txs", "swap-path", "swap-amount", "scenario")
+	registerCompletions(cmd)
+
 	// Mark required flags
 	if err := cmd.MarkFlagRequired("url"); err != nil {
 		panic(fmt.Sprintf("failed to mark url flag as required: %v", err))
 	}
 }
 
+func registerBenchFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.IntSliceVar(&benchCfg.BatchSizes, "batch-sizes", benchCfg.BatchSizes, "Batch sizes to sweep")
+	flags.IntSliceVar(&benchCfg.Concurrency, "concurrency", benchCfg.Concurrency, "MaxConcurrent values to sweep")
+	flags.IntVar(&benchCfg.TxsPerTrial, "txs-per-trial", benchCfg.TxsPerTrial, "Transactions sent per setting")
+	flags.Float64Var(&benchCfg.MaxErrorRate, "max-error-rate", benchCfg.MaxErrorRate, "Highest error rate (percent) a recommended setting may have")
+	setFlagGroup(flags, groupBench, "batch-sizes", "concurrency", "txs-per-trial", "max-error-rate")
+}
+
+func registerAccountBenchFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.IntSliceVar(&acctCfg.Counts, "sub-account-counts", acctCfg.Counts, "Sub-account counts to sweep")
+	flags.IntVar(&acctCfg.TotalTxs, "total-txs", acctCfg.TotalTxs, "Transactions sent per trial, split across the sub-accounts")
+	flags.Float64Var(&acctCfg.MinGain, "min-gain", acctCfg.MinGain, "Smallest chain TPS gain (percent) that still counts as scaling")
+	setFlagGroup(flags, groupBench, "sub-account-counts", "total-txs", "min-gain")
+}
+
+func registerForkFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	flags.Uint64Var(&forkCfg.ActivationBlock, "activation-block", 0, "First block of the new fork (required)")
+	flags.Uint64Var(&forkCfg.LeadBlocks, "lead-blocks", forkCfg.LeadBlocks, "Start the before phase once the chain is this many blocks short of the activation")
+	setFlagGroup(flags, groupFork, "activation-block", "lead-blocks")
+}
+
+// validateBenchRun checks the run flags of a bench sweep. Its trials build
+// and send their own transactions and the sweep table is its only summary.
+func validateBenchRun() error {
+	if runCfg.JSONSummary || runCfg.DryRun || runCfg.SaveTxs != "" || runCfg.ReplayTxs != "" ||
+		runCfg.StopAtBlock > 0 || len(runCfg.Workloads) > 0 {
+		return fmt.Errorf("bench runs its own trials: drop --json-summary, --dry-run, --save-txs, --replay-txs, --stop-at-block and --workload")
+	}
+	if err := runCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return nil
+}
+
+func runAccountBench(_ *cobra.Command, _ []string) error {
+	if err := acctCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid bench config: %w", err)
+	}
+	// The wallet must derive enough sub-accounts for the largest trial
+	cfg.SubAccounts = uint64(acctCfg.MaxCount())
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	console.Configure(cfg.Quiet, console.ColorMode(cfg.Color))
+	if err := validateBenchRun(); err != nil {
+		return err
+	}
+
+	// Create context canceled on interrupt
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	p, err := pipeline.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create pipeline: %w", err)
+	}
+	defer p.Close()
+	p.WithRunConfig(runCfg)
+
+	if _, err := p.ExecuteAccountBench(ctx, acctCfg); err != nil {
+		return fmt.Errorf("bench failed: %w", err)
+	}
+	return nil
+}
+
+func runBench(_ *cobra.Command, _ []string) error {
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	console.Configure(cfg.Quiet, console.ColorMode(cfg.Color))
+	if err := validateBenchRun(); err != nil {
+		return err
+	}
+
+	// Create context canceled on interrupt
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	p, err := pipeline.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create pipeline: %w", err)
+	}
+	defer p.Close()
+	p.WithRunConfig(runCfg)
+
+	result, err := p.ExecuteBench(ctx, benchCfg)
+	if err != nil {
+		return fmt.Errorf("bench failed: %w", err)
+	}
+	if result.Recommended == nil {
+		return fmt.Errorf("no setting stayed within the %.2f%% error budget", benchCfg.MaxErrorRate)
+	}
+	return nil
+}
+
+func runFork(_ *cobra.Command, _ []string) error {
+	if err := forkCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid fork config: %w", err)
+	}
+	if runCfg.StopAtBlock > 0 || len(runCfg.Workloads) > 0 {
+		return fmt.Errorf("fork sets the stop block itself and runs a single workload: drop --stop-at-block and --workload")
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if runCfg.JSONSummary {
+		// Keep stdout for the JSON document
+		console.SetOutput(os.Stderr)
+	}
+	console.Configure(cfg.Quiet || runCfg.JSONSummary, console.ColorMode(cfg.Color))
+	if err := runCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Create context canceled on interrupt
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	p, err := pipeline.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create pipeline: %w", err)
+	}
+	defer p.Close()
+	p.WithRunConfig(runCfg).WithBuildInfo(buildinfo.New(version, commit, date))
+
+	result, err := p.ExecuteFork(ctx, forkCfg)
+	if runCfg.JSONSummary && result != nil {
+		if err := json.NewEncoder(os.Stdout).Encode(result.Summary()); err != nil {
+			return fmt.Errorf("failed to write JSON summary: %w", err)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("fork comparison failed: %w", err)
+	}
+	if !result.Comparable() {
+		return fmt.Errorf("fork comparison incomplete: both phases must confirm transactions")
+	}
+	return nil
+}
+
 func run(_ *cobra.Command, _ []string) error {
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
+	if runCfg.JSONSummary {
+		// Keep stdout for the JSON document
+		console.SetOutput(os.Stderr)
+	}
+	console.Configure(cfg.Quiet || runCfg.JSONSummary, console.ColorMode(cfg.Color))
+	if err := runCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
 
-	// Create context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
+	// Create context canceled on interrupt
+	ctx, cancel := signalContext()
 	defer cancel()
 
-	// Handle signals
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		fmt.Println("\nReceived interrupt signal, shutting down...")
-		cancel()
-	}()
+	if cfg.GetMode() == config.ModeScenario {
+		return runScenario(ctx)
+	}
+	if len(runCfg.Workloads) > 0 {
+		return runMix(ctx)
+	}
 
 	// Create and run pipeline
 	p, err := pipeline.New(cfg)
@@ -134,10 +491,15 @@ func run(_ *cobra.Command, _ []string) error {
 	defer p.Close()
 
 	// Apply run configuration
-	p.WithRunConfig(runCfg)
+	p.WithRunConfig(runCfg).WithBuildInfo(buildinfo.New(version, commit, date))
 
 	// Execute pipeline
 	result, err := p.Execute(ctx)
+	if runCfg.JSONSummary && result != nil {
+		if err := json.NewEncoder(os.Stdout).Encode(result.Summary(err)); err != nil {
+			return fmt.Errorf("failed to write JSON summary: %w", err)
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("pipeline execution failed: %w", err)
 	}
@@ -149,3 +511,67 @@ func run(_ *cobra.Command, _ []string) error {
 
 	return nil
 }
+
+// runMix runs the --workload workloads concurrently and reports them together
+func runMix(ctx context.Context) error {
+	result, err := pipeline.ExecuteMix(ctx, cfg, runCfg, buildinfo.New(version, commit, date))
+	if err != nil {
+		return fmt.Errorf("workload mix failed: %w", err)
+	}
+	if runCfg.JSONSummary {
+		if err := json.NewEncoder(os.Stdout).Encode(result.Summary()); err != nil {
+			return fmt.Errorf("failed to write JSON summary: %w", err)
+		}
+	}
+	if !result.Success() {
+		return fmt.Errorf("stress test completed with errors")
+	}
+	return nil
+}
+
+// runScenario runs the phases of the --scenario file one after the other
+func runScenario(ctx context.Context) error {
+	result, err := pipeline.ExecuteScenario(ctx, cfg, runCfg, buildinfo.New(version, commit, date))
+	if result != nil && runCfg.JSONSummary {
+		if err := json.NewEncoder(os.Stdout).Encode(result.Summary()); err != nil {
+			return fmt.Errorf("failed to write JSON summary: %w", err)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("scenario failed: %w", err)
+	}
+	if !result.Success() {
+		return fmt.Errorf("scenario completed with errors")
+	}
+	return nil
+}
+
+func runVerify(_ *cobra.Command, args []string) error {
+	m, problems, err := manifest.Verify(args[0])
+	if err != nil {
+		return err
+	}
+	console.Printf("Manifest: %s (txhammer %s, commit %s, %s)\n", args[0], m.Version, m.Commit, m.CreatedAt.Format(time.RFC3339))
+	for _, p := range problems {
+		console.Failf("%s: %s\n", p.Path, p.Reason)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d of %d artifacts failed verification", len(problems), len(m.Artifacts))
+	}
+	console.OKf("All %d artifacts match\n", len(m.Artifacts))
+	return nil
+}
+
+// signalContext returns a context that is canceled on SIGINT or SIGTERM
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		console.Warnf("\nReceived interrupt signal, shutting down...\n")
+		cancel()
+	}()
+	return ctx, cancel
+}
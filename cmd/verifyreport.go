@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+
+	"github.com/0xmhha/txhammer/internal/audit"
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+var (
+	verifyReportURL    string
+	verifyReportSample int
+	verifyReportKey    string
+	verifyReportOutput string
+	verifyReportLimit  int
+)
+
+func newVerifyReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-report <report.json>",
+		Short: "Check the transactions of an exported report against the chain",
+		Long: `Reads an exported report and the transactions dataset written next to it, and
+looks up a random sample (or all) of the transactions it lists as confirmed or
+failed: each must have a receipt, in the recorded block, with the recorded status.
+Writes a verification record with the report's SHA-256 next to the report
+(verification_<timestamp>.json), signed with --private-key if given, so the
+report can be shared with a sign-off. Without a dataset only the latency
+outliers of the report are checked. Exits with an error if any check fails.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runVerifyReport,
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&verifyReportURL, "url", "", "RPC endpoint URL of the chain the report was measured on")
+	flags.IntVar(&verifyReportSample, "sample", 100, "Transactions looked up, picked at random (0 = all)")
+	flags.StringVar(&verifyReportKey, "private-key", "", "Key (hex) that signs the verification record (EIP-191)")
+	flags.StringVar(&verifyReportOutput, "output", "", "Verification record file (default verification_<timestamp>.json next to the report)")
+	flags.IntVar(&verifyReportLimit, "limit", 20, "Mismatches listed (0 = all)")
+	if err := cmd.MarkFlagRequired("url"); err != nil {
+		panic(fmt.Sprintf("failed to mark url flag as required: %v", err))
+	}
+	return cmd
+}
+
+func runVerifyReport(_ *cobra.Command, args []string) error {
+	if verifyReportSample < 0 {
+		return fmt.Errorf("sample must not be negative")
+	}
+	key, err := loadSignerKey(verifyReportKey)
+	if err != nil {
+		return err
+	}
+	claims, err := audit.LoadClaims(args[0])
+	if err != nil {
+		return err
+	}
+	if len(claims.Claims) == 0 {
+		return fmt.Errorf("%s lists no confirmed transactions", args[0])
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	c, err := client.New(verifyReportURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer c.Close()
+
+	idCtx, idCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer idCancel()
+	chainID, err := c.ChainID(idCtx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	if claims.Dataset == "" {
+		console.Warnf("No transactions dataset next to %s; checking only its latency outliers\n", args[0])
+	}
+	sample := audit.Sample(claims.Claims, verifyReportSample)
+	console.Printf("Checking %d of %d transactions from %s...\n", len(sample), len(claims.Claims), args[0])
+	mismatches, err := audit.Check(ctx, c, sample)
+	if err != nil {
+		return fmt.Errorf("verify failed: %w", err)
+	}
+
+	a, err := audit.NewAttestation(args[0], version, claims, chainID.Uint64(), len(sample), mismatches)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		if err := a.Sign(key); err != nil {
+			return err
+		}
+	}
+	output := verifyReportOutput
+	if output == "" {
+		output = audit.AttestationPath(args[0])
+	}
+	if err := a.Write(output); err != nil {
+		return err
+	}
+
+	for _, p := range a.Problems {
+		console.Failf("%s\n", p)
+	}
+	for i, m := range mismatches {
+		if verifyReportLimit > 0 && i == verifyReportLimit {
+			console.Printf("... and %d more\n", len(mismatches)-i)
+			break
+		}
+		console.Failf("%s: %s\n", m.Hash, m.Reason)
+	}
+	console.Printf("Verification record written to: %s\n", output)
+	if a.Signer != "" {
+		console.Printf("Signed by: %s\n", a.Signer)
+	}
+	if !a.Verified {
+		return fmt.Errorf("report failed verification: %d of %d checked transactions mismatch, %d report problems",
+			len(mismatches), len(sample), len(a.Problems))
+	}
+	console.OKf("All %d checked transactions match the chain (chain %d)\n", len(sample), chainID)
+	return nil
+}
+
+// loadSignerKey parses the hex key that signs a verification record, or
+// returns nil when none is given
+func loadSignerKey(keyHex string) (*ecdsa.PrivateKey, error) {
+	if keyHex == "" {
+		return nil, nil
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(keyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,185 @@
+package alert
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Config holds configuration for the error-rate alert
+type Config struct {
+	Window    time.Duration // Sliding window the failure rate is measured over
+	Threshold float64       // Failure rate in percent above which an alert is raised
+	MinTxs    int           // Fewest sends in the window for the rate to count
+}
+
+// Alert describes a period during which the send failure rate stayed above the threshold
+type Alert struct {
+	Start     time.Time     `json:"start"`
+	End       time.Time     `json:"end,omitempty"` // Zero if the rate never recovered
+	Duration  time.Duration `json:"duration"`
+	PeakRate  float64       `json:"peak_rate"` // Highest windowed failure rate, in percent
+	Failed    int           `json:"failed"`    // Failures in the window at the peak
+	Total     int           `json:"total"`     // Sends in the window at the peak
+	LastError string        `json:"last_error,omitempty"`
+}
+
+// Callbacks for alert notifications
+type Callbacks struct {
+	OnAlert   func(a Alert)
+	OnRecover func(a Alert)
+}
+
+// bucket counts the sends of one second
+type bucket struct {
+	second int64
+	sent   int
+	failed int
+}
+
+// Monitor raises an alert while the failure rate of recent sends exceeds a threshold
+type Monitor struct {
+	config    Config
+	callbacks *Callbacks
+
+	mu        sync.Mutex
+	buckets   []bucket // Ring of per-second counts covering the window
+	lastError string
+	alerting  bool
+	alerts    []Alert
+}
+
+// New creates a new Monitor instance
+func New(config Config) *Monitor {
+	seconds := int((config.Window + time.Second - 1) / time.Second)
+	return &Monitor{
+		config:  config,
+		buckets: make([]bucket, max(seconds, 1)),
+		alerts:  make([]Alert, 0),
+	}
+}
+
+// WithCallbacks sets the alert notification callbacks
+func (m *Monitor) WithCallbacks(callbacks *Callbacks) *Monitor {
+	m.callbacks = callbacks
+	return m
+}
+
+// Sent records a send the node acknowledged; it is safe for concurrent use
+func (m *Monitor) Sent() {
+	m.record(time.Now(), nil)
+}
+
+// Failed records a send that failed; it is safe for concurrent use
+func (m *Monitor) Failed(err error) {
+	m.record(time.Now(), err)
+}
+
+func (m *Monitor) record(now time.Time, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := m.bucket(now.Unix())
+	if err != nil {
+		b.failed++
+		m.lastError = err.Error()
+		return
+	}
+	b.sent++
+}
+
+// bucket returns the bucket of second, clearing it if it last held an older second
+func (m *Monitor) bucket(second int64) *bucket {
+	b := &m.buckets[second%int64(len(m.buckets))]
+	if b.second != second {
+		*b = bucket{second: second}
+	}
+	return b
+}
+
+// Run evaluates the failure rate every second until the context is done
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.check(now)
+		}
+	}
+}
+
+// check updates the alert state from the sends of the window ending at now
+func (m *Monitor) check(now time.Time) {
+	m.mu.Lock()
+
+	var failed, total int
+	oldest := now.Unix() - int64(len(m.buckets))
+	for _, b := range m.buckets {
+		if b.second > oldest && b.second <= now.Unix() {
+			failed += b.failed
+			total += b.sent + b.failed
+		}
+	}
+	rate := 0.0
+	if total > 0 {
+		rate = float64(failed) / float64(total) * 100
+	}
+	above := total >= m.config.MinTxs && total > 0 && rate > m.config.Threshold
+
+	switch {
+	case above && !m.alerting:
+		m.alerting = true
+		a := Alert{Start: now, PeakRate: rate, Failed: failed, Total: total, LastError: m.lastError}
+		m.alerts = append(m.alerts, a)
+		m.mu.Unlock()
+
+		console.Failf("\n*** ALERT: %.1f%% of sends failed in the last %s (%d of %d, threshold %.1f%%) ***\n",
+			rate, m.config.Window, failed, total, m.config.Threshold)
+		if a.LastError != "" {
+			console.Failf("*** Last error: %s ***\n", a.LastError)
+		}
+		if m.callbacks != nil && m.callbacks.OnAlert != nil {
+			m.callbacks.OnAlert(a)
+		}
+		return
+
+	case above:
+		a := &m.alerts[len(m.alerts)-1]
+		if rate > a.PeakRate {
+			a.PeakRate, a.Failed, a.Total, a.LastError = rate, failed, total, m.lastError
+		}
+
+	case m.alerting && total >= m.config.MinTxs:
+		m.alerting = false
+		a := &m.alerts[len(m.alerts)-1]
+		a.End = now
+		a.Duration = a.End.Sub(a.Start)
+		recovered := *a
+		m.mu.Unlock()
+
+		console.OKf("\nSend failure rate back to %.1f%% after %s\n", rate, recovered.Duration)
+		if m.callbacks != nil && m.callbacks.OnRecover != nil {
+			m.callbacks.OnRecover(recovered)
+		}
+		return
+	}
+	m.mu.Unlock()
+}
+
+// Alerts returns every alert raised so far, including one still active
+func (m *Monitor) Alerts() []Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	alerts := make([]Alert, len(m.alerts))
+	copy(alerts, m.alerts)
+	for i := range alerts {
+		if alerts[i].End.IsZero() {
+			alerts[i].Duration = time.Since(alerts[i].Start)
+		}
+	}
+	return alerts
+}
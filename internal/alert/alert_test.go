@@ -0,0 +1,57 @@
+package alert
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitor_Check(t *testing.T) {
+	var raised, recovered []Alert
+	m := New(Config{Window: 5 * time.Second, Threshold: 20, MinTxs: 10}).WithCallbacks(&Callbacks{
+		OnAlert:   func(a Alert) { raised = append(raised, a) },
+		OnRecover: func(a Alert) { recovered = append(recovered, a) },
+	})
+	start := time.Unix(1700000000, 0)
+	send := func(at time.Time, sent, failed int) {
+		for i := 0; i < sent; i++ {
+			m.record(at, nil)
+		}
+		for i := 0; i < failed; i++ {
+			m.record(at, errors.New("txpool is full"))
+		}
+	}
+
+	// Too few sends in the window to judge, even though all failed
+	send(start, 0, 5)
+	m.check(start)
+	if len(raised) != 0 {
+		t.Fatalf("alert raised below MinTxs: %+v", raised)
+	}
+
+	// 15 of 25 failed in the window
+	send(start.Add(time.Second), 10, 10)
+	m.check(start.Add(time.Second))
+	if len(raised) != 1 || raised[0].Failed != 15 || raised[0].Total != 25 || raised[0].LastError != "txpool is full" {
+		t.Fatalf("raised = %+v, want one alert at 15 of 25", raised)
+	}
+
+	// Still above the threshold: no second alert
+	send(start.Add(2*time.Second), 10, 5)
+	m.check(start.Add(2 * time.Second))
+	if len(raised) != 1 {
+		t.Errorf("raised %d alerts, want 1 while the rate stays high", len(raised))
+	}
+
+	// The failures leave the window
+	later := start.Add(10 * time.Second)
+	send(later, 20, 0)
+	m.check(later)
+	if len(recovered) != 1 || recovered[0].Duration != 9*time.Second {
+		t.Fatalf("recovered = %+v, want one recovery after 9s", recovered)
+	}
+	alerts := m.Alerts()
+	if len(alerts) != 1 || alerts[0].End.IsZero() || alerts[0].PeakRate != 60 {
+		t.Errorf("Alerts() = %+v, want one ended alert peaking at 60%%", alerts)
+	}
+}
@@ -1,19 +1,23 @@
 package analyzer
 
 import (
+	"cmp"
 	"context"
 	"encoding/csv"
 	"fmt"
 	"math/big"
 	"os"
 	"runtime"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/mathutil"
 )
 
@@ -48,7 +52,7 @@ func (a *Analyzer) Analyze(ctx context.Context) (*AnalysisResult, error) {
 		return nil, fmt.Errorf("failed to resolve block range: %w", err)
 	}
 
-	fmt.Printf("Analyzing blocks %d to %d (%d blocks)...\n", startBlock, endBlock, endBlock-startBlock+1)
+	console.Printf("Analyzing blocks %d to %d (%d blocks)...\n", startBlock, endBlock, endBlock-startBlock+1)
 
 	// Fetch blocks in parallel
 	eg, egCtx := errgroup.WithContext(ctx)
@@ -164,6 +168,9 @@ func (a *Analyzer) calculateMetrics() (*AnalysisResult, error) {
 
 	var totalGasUsed uint64
 	var totalBlockTime time.Duration
+	blockTimes := make([]time.Duration, 0, len(a.blocks))
+	txCounts := make([]int, 0, len(a.blocks))
+	utilizations := make([]float64, 0, len(a.blocks))
 
 	for i, block := range a.blocks {
 		txCount, err := mathutil.IntToUint64(block.TxCount)
@@ -182,9 +189,19 @@ func (a *Analyzer) calculateMetrics() (*AnalysisResult, error) {
 
 		if i > 0 {
 			totalBlockTime += block.BlockTime
+			blockTimes = append(blockTimes, block.BlockTime)
 		}
+		txCounts = append(txCounts, block.TxCount)
+		utilizations = append(utilizations, block.Utilization)
 	}
 
+	result.P50BlockTime = percentile(blockTimes, 50)
+	result.P95BlockTime = percentile(blockTimes, 95)
+	result.P50TxPerBlock = percentile(txCounts, 50)
+	result.P95TxPerBlock = percentile(txCounts, 95)
+	result.P95Utilization = percentile(utilizations, 95)
+	result.Anomalies = detectAnomalies(a.blocks, result.P50BlockTime, a.config.Anomalies)
+
 	// Calculate averages
 	blockCount := len(a.blocks)
 	if blockCount > 0 {
@@ -204,13 +221,34 @@ func (a *Analyzer) calculateMetrics() (*AnalysisResult, error) {
 	return result, nil
 }
 
-// PrintTable prints the analysis results as a table
+// percentile returns the p-th percentile (nearest rank) of values, or the
+// zero value when there are none
+func percentile[T cmp.Ordered](values []T, p int) T {
+	var zero T
+	if len(values) == 0 {
+		return zero
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+// PrintTable prints one page of the per-block table followed by the summary.
+// Config.TableLimit rows are shown per page (0 = all blocks).
 func (a *Analyzer) PrintTable(result *AnalysisResult) {
-	table := tablewriter.NewWriter(os.Stdout)
+	rows, first, pages := a.tablePage(result.Blocks)
+	if pages > 1 {
+		console.Summaryf("Blocks %d-%d of %d (page %d of %d)\n",
+			first+1, first+len(rows), len(result.Blocks), a.config.TablePage, pages)
+	}
+
+	// Rendered through console so that --json-summary keeps stdout for the JSON
+	var buf strings.Builder
+	table := tablewriter.NewWriter(&buf)
 	table.SetHeader([]string{"Block", "Time", "TxCount", "Gas Used", "Gas Limit", "Utilization", "Block Time"})
 	table.SetBorder(true)
 
-	for _, block := range result.Blocks {
+	for _, block := range rows {
 		blockTime := "-"
 		if block.BlockTime > 0 {
 			blockTime = fmt.Sprintf("%.2fs", block.BlockTime.Seconds())
@@ -239,17 +277,81 @@ func (a *Analyzer) PrintTable(result *AnalysisResult) {
 	})
 
 	table.Render()
+	console.Summaryf("%s", buf.String())
 
-	// Print summary
-	fmt.Println()
-	fmt.Printf("Summary:\n")
-	fmt.Printf("  Block Range: %d - %d (%d blocks)\n", result.StartBlock, result.EndBlock, len(result.Blocks))
-	fmt.Printf("  Total Duration: %s\n", result.TotalDuration)
-	fmt.Printf("  Total Transactions: %d\n", result.TotalTxs)
-	fmt.Printf("  Average TPS: %.2f\n", result.AverageTPS)
-	fmt.Printf("  Avg Block Time: %.2fs\n", result.AvgBlockTime.Seconds())
-	fmt.Printf("  Avg Tx/Block: %.2f (min: %d, max: %d)\n", result.AvgTxPerBlock, result.MinTxPerBlock, result.MaxTxPerBlock)
-	fmt.Printf("  Avg Gas Used: %.0f\n", result.AvgGasUsed)
+	if pages > 1 && a.config.TablePage < pages {
+		console.Summaryf("... %d more blocks (use --table-page %d for the next page)\n",
+			len(result.Blocks)-first-len(rows), a.config.TablePage+1)
+	}
+
+	console.Summaryln()
+	a.PrintSummary(result)
+}
+
+// tablePage returns the rows of the configured table page, the index of its
+// first row and the number of pages
+func (a *Analyzer) tablePage(blocks []BlockInfo) (rows []BlockInfo, first, pages int) {
+	limit := a.config.TableLimit
+	if limit <= 0 || len(blocks) <= limit {
+		return blocks, 0, 1
+	}
+
+	pages = (len(blocks) + limit - 1) / limit
+	page := min(max(a.config.TablePage, 1), pages)
+	first = (page - 1) * limit
+	return blocks[first:min(first+limit, len(blocks))], first, pages
+}
+
+// PrintSummary prints the aggregate analysis results
+func (a *Analyzer) PrintSummary(result *AnalysisResult) {
+	console.Summaryf("Summary:\n")
+	console.Summaryf("  Block Range: %d - %d (%d blocks)\n", result.StartBlock, result.EndBlock, len(result.Blocks))
+	console.Summaryf("  Total Duration: %s\n", result.TotalDuration)
+	console.Summaryf("  Total Transactions: %d\n", result.TotalTxs)
+	console.Summaryf("  Average TPS: %.2f\n", result.AverageTPS)
+	console.Summaryf("  Avg Block Time: %.2fs (p50: %.2fs, p95: %.2fs)\n",
+		result.AvgBlockTime.Seconds(), result.P50BlockTime.Seconds(), result.P95BlockTime.Seconds())
+	console.Summaryf("  Avg Tx/Block: %.2f (min: %d, p50: %d, p95: %d, max: %d)\n",
+		result.AvgTxPerBlock, result.MinTxPerBlock, result.P50TxPerBlock, result.P95TxPerBlock, result.MaxTxPerBlock)
+	console.Summaryf("  Avg Gas Used: %.0f\n", result.AvgGasUsed)
+	console.Summaryf("  P95 Utilization: %.2f%%\n", result.P95Utilization)
+
+	a.printAnomalies(result.Anomalies)
+}
+
+// maxPrintedAnomalies caps the anomalies listed in the summary
+const maxPrintedAnomalies = 20
+
+// printAnomalies lists the anomalies found in the range
+func (a *Analyzer) printAnomalies(anomalies []Anomaly) {
+	if len(anomalies) == 0 {
+		console.Summaryf("\nAnomalies: none\n")
+		return
+	}
+
+	counts := make(map[AnomalyKind]int)
+	for _, anomaly := range anomalies {
+		counts[anomaly.Kind]++
+	}
+	console.Summaryf("\nAnomalies: %d\n", len(anomalies))
+	for _, kind := range []AnomalyKind{AnomalyEmptyStreak, AnomalyBlockTimeSpike, AnomalyUtilizationCliff, AnomalyTimestampRegression} {
+		if counts[kind] > 0 {
+			console.Summaryf("  %-22s %d\n", kind, counts[kind])
+		}
+	}
+
+	console.Summaryln()
+	for i, anomaly := range anomalies {
+		if i == maxPrintedAnomalies {
+			console.Summaryf("  ... and %d more (see the JSON export)\n", len(anomalies)-maxPrintedAnomalies)
+			break
+		}
+		blocks := fmt.Sprintf("#%d", anomaly.StartBlock)
+		if anomaly.EndBlock != anomaly.StartBlock {
+			blocks = fmt.Sprintf("#%d-#%d", anomaly.StartBlock, anomaly.EndBlock)
+		}
+		console.Warnf("%-22s %-17s %s\n", anomaly.Kind, blocks, anomaly.Detail)
+	}
 }
 
 // ExportCSV exports the results to a CSV file
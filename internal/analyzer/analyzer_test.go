@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+func TestAnalyzer_CalculateMetrics_Percentiles(t *testing.T) {
+	// 20 blocks at a steady 2s with one 30s stall and one busy block
+	a := New(nil, nil)
+	start := time.Unix(1000, 0)
+	at := start
+	for i := 0; i < 20; i++ {
+		block := BlockInfo{Number: uint64(i + 1), TxCount: 10, Utilization: 40}
+		if i > 0 {
+			block.BlockTime = 2 * time.Second
+			if i == 10 {
+				block.BlockTime = 30 * time.Second
+			}
+		}
+		if i == 5 {
+			block.TxCount = 100
+			block.Utilization = 95
+		}
+		at = at.Add(block.BlockTime)
+		block.Timestamp = at
+		a.blocks = append(a.blocks, block)
+	}
+
+	result, err := a.calculateMetrics()
+	if err != nil {
+		t.Fatalf("calculateMetrics() error = %v", err)
+	}
+
+	if result.P50BlockTime != 2*time.Second {
+		t.Errorf("P50BlockTime = %s, want 2s", result.P50BlockTime)
+	}
+	if result.P95BlockTime != 2*time.Second {
+		t.Errorf("P95BlockTime = %s, want 2s (one stall in 19 intervals is past p95)", result.P95BlockTime)
+	}
+	if result.AvgBlockTime <= 2*time.Second {
+		t.Errorf("AvgBlockTime = %s, want it skewed above 2s by the stall", result.AvgBlockTime)
+	}
+	if result.P50TxPerBlock != 10 || result.P95TxPerBlock != 10 {
+		t.Errorf("tx per block p50/p95 = %d/%d, want 10/10", result.P50TxPerBlock, result.P95TxPerBlock)
+	}
+	if result.P95Utilization != 40 {
+		t.Errorf("P95Utilization = %.2f, want 40", result.P95Utilization)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+	if got := percentile(values, 50); got != 5 {
+		t.Errorf("p50 = %d, want 5", got)
+	}
+	if got := percentile(values, 95); got != 9 {
+		t.Errorf("p95 = %d, want 9", got)
+	}
+	if got := percentile(values, 100); got != 10 {
+		t.Errorf("p100 = %d, want 10", got)
+	}
+	if values[0] != 5 {
+		t.Error("percentile must not reorder its input")
+	}
+	if got := percentile([]time.Duration{}, 95); got != 0 {
+		t.Errorf("empty p95 = %s, want 0", got)
+	}
+}
+
+func TestAnalyzer_PrintTable_ConsoleOutput(t *testing.T) {
+	var buf bytes.Buffer
+	console.SetOutput(&buf)
+	defer console.SetOutput(os.Stdout)
+
+	a := New(nil, nil)
+	a.PrintTable(&AnalysisResult{Blocks: []BlockInfo{{Number: 7, TxCount: 3, Timestamp: time.Unix(1000, 0)}}, TotalTxs: 3})
+
+	// --json-summary moves console output to stderr, so the table must follow it
+	if out := buf.String(); !strings.Contains(out, "GAS LIMIT") || !strings.Contains(out, "|     7 |") {
+		t.Errorf("table not written to the console output:\n%s", out)
+	}
+}
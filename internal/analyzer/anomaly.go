@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AnomalyKind identifies the kind of an anomaly found in the analyzed range
+type AnomalyKind string
+
+const (
+	AnomalyEmptyStreak         AnomalyKind = "empty_streak"         // Consecutive blocks without transactions
+	AnomalyBlockTimeSpike      AnomalyKind = "block_time_spike"     // Block time far above the median
+	AnomalyUtilizationCliff    AnomalyKind = "utilization_cliff"    // Sudden drop in gas utilization
+	AnomalyTimestampRegression AnomalyKind = "timestamp_regression" // Block timestamp earlier than its parent's
+)
+
+// Anomaly is an irregularity over one block or a run of blocks
+type Anomaly struct {
+	Kind       AnomalyKind
+	StartBlock uint64
+	EndBlock   uint64
+	Detail     string
+}
+
+// AnomalyThresholds controls when irregularities are reported
+type AnomalyThresholds struct {
+	EmptyStreak      int     // Minimum run of empty blocks reported (0 = disabled)
+	BlockTimeSpike   float64 // Block time above this multiple of the median block time (0 = disabled)
+	UtilizationCliff float64 // Utilization drop between consecutive blocks, in percentage points (0 = disabled)
+}
+
+// DefaultAnomalyThresholds returns the default anomaly thresholds
+func DefaultAnomalyThresholds() AnomalyThresholds {
+	return AnomalyThresholds{
+		EmptyStreak:      3,
+		BlockTimeSpike:   3,
+		UtilizationCliff: 50,
+	}
+}
+
+// detectAnomalies scans blocks sorted by number. medianBlockTime is the
+// baseline for block-time spikes.
+func detectAnomalies(blocks []BlockInfo, medianBlockTime time.Duration, th AnomalyThresholds) []Anomaly {
+	var anomalies []Anomaly
+	emptyStart := -1
+
+	flushEmpty := func(end int) {
+		if emptyStart >= 0 && th.EmptyStreak > 0 && end-emptyStart >= th.EmptyStreak {
+			anomalies = append(anomalies, Anomaly{
+				Kind:       AnomalyEmptyStreak,
+				StartBlock: blocks[emptyStart].Number,
+				EndBlock:   blocks[end-1].Number,
+				Detail:     fmt.Sprintf("%d consecutive empty blocks", end-emptyStart),
+			})
+		}
+		emptyStart = -1
+	}
+
+	for i, block := range blocks {
+		if block.TxCount == 0 {
+			if emptyStart < 0 {
+				emptyStart = i
+			}
+		} else {
+			flushEmpty(i)
+		}
+
+		if i == 0 {
+			continue
+		}
+		prev := blocks[i-1]
+
+		if block.BlockTime < 0 {
+			anomalies = append(anomalies, Anomaly{
+				Kind:       AnomalyTimestampRegression,
+				StartBlock: block.Number,
+				EndBlock:   block.Number,
+				Detail:     fmt.Sprintf("timestamp %s before block %d", -block.BlockTime, prev.Number),
+			})
+		}
+
+		if th.BlockTimeSpike > 0 && medianBlockTime > 0 &&
+			float64(block.BlockTime) > th.BlockTimeSpike*float64(medianBlockTime) {
+			anomalies = append(anomalies, Anomaly{
+				Kind:       AnomalyBlockTimeSpike,
+				StartBlock: block.Number,
+				EndBlock:   block.Number,
+				Detail: fmt.Sprintf("block time %s is %.1fx the median %s",
+					block.BlockTime, float64(block.BlockTime)/float64(medianBlockTime), medianBlockTime),
+			})
+		}
+
+		if th.UtilizationCliff > 0 && prev.Utilization-block.Utilization >= th.UtilizationCliff {
+			anomalies = append(anomalies, Anomaly{
+				Kind:       AnomalyUtilizationCliff,
+				StartBlock: block.Number,
+				EndBlock:   block.Number,
+				Detail:     fmt.Sprintf("utilization fell from %.2f%% to %.2f%%", prev.Utilization, block.Utilization),
+			})
+		}
+	}
+	flushEmpty(len(blocks))
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return anomalies[i].StartBlock < anomalies[j].StartBlock
+	})
+	return anomalies
+}
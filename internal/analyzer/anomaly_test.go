@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	blocks := []BlockInfo{
+		{Number: 1, TxCount: 10, Utilization: 80},
+		{Number: 2, TxCount: 10, Utilization: 80, BlockTime: 2 * time.Second},
+		{Number: 3, TxCount: 0, Utilization: 0, BlockTime: 2 * time.Second},
+		{Number: 4, TxCount: 0, Utilization: 0, BlockTime: 2 * time.Second},
+		{Number: 5, TxCount: 0, Utilization: 0, BlockTime: 2 * time.Second},
+		{Number: 6, TxCount: 10, Utilization: 70, BlockTime: 20 * time.Second},
+		{Number: 7, TxCount: 10, Utilization: 70, BlockTime: -time.Second},
+		{Number: 8, TxCount: 0, Utilization: 0, BlockTime: 2 * time.Second},
+	}
+
+	got := detectAnomalies(blocks, 2*time.Second, DefaultAnomalyThresholds())
+
+	want := []struct {
+		kind       AnomalyKind
+		start, end uint64
+	}{
+		{AnomalyUtilizationCliff, 3, 3},
+		{AnomalyEmptyStreak, 3, 5},
+		{AnomalyBlockTimeSpike, 6, 6},
+		{AnomalyTimestampRegression, 7, 7},
+		{AnomalyUtilizationCliff, 8, 8},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("detectAnomalies() returned %d anomalies, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Kind != w.kind || got[i].StartBlock != w.start || got[i].EndBlock != w.end {
+			t.Errorf("anomaly %d = %s %d-%d, want %s %d-%d",
+				i, got[i].Kind, got[i].StartBlock, got[i].EndBlock, w.kind, w.start, w.end)
+		}
+	}
+}
+
+func TestDetectAnomalies_Disabled(t *testing.T) {
+	blocks := []BlockInfo{
+		{Number: 1, TxCount: 10, Utilization: 90},
+		{Number: 2, TxCount: 0, BlockTime: 30 * time.Second},
+		{Number: 3, TxCount: 0, BlockTime: 2 * time.Second},
+		{Number: 4, TxCount: 0, BlockTime: 2 * time.Second},
+	}
+
+	// Timestamp regressions are always reported; the other kinds are off
+	if got := detectAnomalies(blocks, 2*time.Second, AnomalyThresholds{}); len(got) != 0 {
+		t.Errorf("detectAnomalies() with zero thresholds = %+v, want none", got)
+	}
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// jsonBlock is the JSON form of a BlockInfo
+type jsonBlock struct {
+	Number      uint64  `json:"number"`
+	Timestamp   string  `json:"timestamp"`
+	TxCount     int     `json:"tx_count"`
+	GasLimit    uint64  `json:"gas_limit"`
+	GasUsed     uint64  `json:"gas_used"`
+	Utilization float64 `json:"utilization"`
+	BlockTime   string  `json:"block_time"` // Go duration string
+}
+
+// jsonAnomaly is the JSON form of an Anomaly
+type jsonAnomaly struct {
+	Kind       AnomalyKind `json:"kind"`
+	StartBlock uint64      `json:"start_block"`
+	EndBlock   uint64      `json:"end_block"`
+	Detail     string      `json:"detail"`
+}
+
+// jsonResult is the JSON form of an AnalysisResult
+type jsonResult struct {
+	StartBlock    uint64  `json:"start_block"`
+	EndBlock      uint64  `json:"end_block"`
+	BlockCount    int     `json:"block_count"`
+	TotalTxs      uint64  `json:"total_txs"`
+	TotalDuration string  `json:"total_duration"` // Go duration string
+	AverageTPS    float64 `json:"average_tps"`
+	AvgBlockTime  string  `json:"avg_block_time"` // Go duration string
+	AvgGasUsed    float64 `json:"avg_gas_used"`
+	AvgTxPerBlock float64 `json:"avg_tx_per_block"`
+	MaxTxPerBlock int     `json:"max_tx_per_block"`
+	MinTxPerBlock int     `json:"min_tx_per_block"`
+
+	P50BlockTime   string  `json:"p50_block_time"` // Go duration string
+	P95BlockTime   string  `json:"p95_block_time"` // Go duration string
+	P50TxPerBlock  int     `json:"p50_tx_per_block"`
+	P95TxPerBlock  int     `json:"p95_tx_per_block"`
+	P95Utilization float64 `json:"p95_utilization"`
+
+	Anomalies []jsonAnomaly `json:"anomalies"`
+	Blocks    []jsonBlock   `json:"blocks"`
+}
+
+// ExportJSON exports the full analysis result, including every block, to a JSON file
+func (a *Analyzer) ExportJSON(result *AnalysisResult, filename string) error {
+	out := jsonResult{
+		StartBlock:    result.StartBlock,
+		EndBlock:      result.EndBlock,
+		BlockCount:    len(result.Blocks),
+		TotalTxs:      result.TotalTxs,
+		TotalDuration: result.TotalDuration.String(),
+		AverageTPS:    result.AverageTPS,
+		AvgBlockTime:  result.AvgBlockTime.String(),
+		AvgGasUsed:    result.AvgGasUsed,
+		AvgTxPerBlock: result.AvgTxPerBlock,
+		MaxTxPerBlock: result.MaxTxPerBlock,
+		MinTxPerBlock: result.MinTxPerBlock,
+
+		P50BlockTime:   result.P50BlockTime.String(),
+		P95BlockTime:   result.P95BlockTime.String(),
+		P50TxPerBlock:  result.P50TxPerBlock,
+		P95TxPerBlock:  result.P95TxPerBlock,
+		P95Utilization: result.P95Utilization,
+
+		Anomalies: make([]jsonAnomaly, len(result.Anomalies)),
+		Blocks:    make([]jsonBlock, len(result.Blocks)),
+	}
+	for i, anomaly := range result.Anomalies {
+		out.Anomalies[i] = jsonAnomaly(anomaly)
+	}
+	for i, block := range result.Blocks {
+		out.Blocks[i] = jsonBlock{
+			Number:      block.Number,
+			Timestamp:   block.Timestamp.Format(time.RFC3339),
+			TxCount:     block.TxCount,
+			GasLimit:    block.GasLimit,
+			GasUsed:     block.GasUsed,
+			Utilization: block.Utilization,
+			BlockTime:   block.BlockTime.String(),
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
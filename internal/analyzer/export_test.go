@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testResult(n int) *AnalysisResult {
+	result := &AnalysisResult{StartBlock: 1, EndBlock: uint64(n), TotalTxs: uint64(n) * 10, AvgBlockTime: 2 * time.Second}
+	for i := 0; i < n; i++ {
+		result.Blocks = append(result.Blocks, BlockInfo{
+			Number:    uint64(i + 1),
+			Timestamp: time.Unix(int64(1000+2*i), 0),
+			TxCount:   10,
+			GasLimit:  30000000,
+			GasUsed:   210000,
+			BlockTime: 2 * time.Second,
+		})
+	}
+	return result
+}
+
+func TestAnalyzer_TablePage(t *testing.T) {
+	blocks := testResult(250).Blocks
+
+	tests := []struct {
+		limit, page         int
+		wantRows, wantFirst int
+		wantPages           int
+	}{
+		{0, 1, 250, 0, 1},
+		{100, 1, 100, 0, 3},
+		{100, 3, 50, 200, 3},
+		{100, 9, 50, 200, 3}, // Past the end shows the last page
+		{500, 1, 250, 0, 1},
+	}
+	for _, tt := range tests {
+		a := New(nil, &Config{TableLimit: tt.limit, TablePage: tt.page})
+		rows, first, pages := a.tablePage(blocks)
+		if len(rows) != tt.wantRows || first != tt.wantFirst || pages != tt.wantPages {
+			t.Errorf("limit %d page %d: got %d rows from %d of %d pages, want %d rows from %d of %d pages",
+				tt.limit, tt.page, len(rows), first, pages, tt.wantRows, tt.wantFirst, tt.wantPages)
+		}
+	}
+}
+
+func TestAnalyzer_ExportJSON(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "analysis.json")
+	if err := New(nil, nil).ExportJSON(testResult(3), filename); err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	var got jsonResult
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.BlockCount != 3 || len(got.Blocks) != 3 {
+		t.Errorf("blocks = %d (count %d), want 3", len(got.Blocks), got.BlockCount)
+	}
+	if got.AvgBlockTime != "2s" || got.Blocks[0].BlockTime != "2s" {
+		t.Errorf("durations = %q, %q; want \"2s\"", got.AvgBlockTime, got.Blocks[0].BlockTime)
+	}
+	if got.TotalTxs != 30 {
+		t.Errorf("TotalTxs = %d, want 30", got.TotalTxs)
+	}
+}
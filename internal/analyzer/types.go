@@ -22,6 +22,9 @@ type Config struct {
 	EndBlock    int64 // End block number (0 = latest)
 	BlockRange  int64 // Number of recent blocks to analyze
 	Concurrency int   // Number of concurrent block fetches
+	TableLimit  int   // Rows per page of the printed table (0 = all blocks)
+	TablePage   int   // 1-based page of the printed table
+	Anomalies   AnomalyThresholds
 }
 
 // DefaultConfig returns default analyzer configuration
@@ -31,6 +34,9 @@ func DefaultConfig() *Config {
 		EndBlock:    0,
 		BlockRange:  100,
 		Concurrency: 50,
+		TableLimit:  100,
+		TablePage:   1,
+		Anomalies:   DefaultAnomalyThresholds(),
 	}
 }
 
@@ -58,4 +64,14 @@ type AnalysisResult struct {
 	AvgTxPerBlock float64
 	MaxTxPerBlock int
 	MinTxPerBlock int
+
+	// Percentiles expose long-tail stalls that averages hide
+	P50BlockTime   time.Duration
+	P95BlockTime   time.Duration
+	P50TxPerBlock  int
+	P95TxPerBlock  int
+	P95Utilization float64
+
+	// Irregularities found in the range, ordered by block
+	Anomalies []Anomaly
 }
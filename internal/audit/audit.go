@@ -0,0 +1,353 @@
+// Package audit checks the transactions a txhammer report claims were
+// included against the chain and writes a verification record for it
+package audit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/util/compress"
+	schema "github.com/0xmhha/txhammer/pkg/report"
+)
+
+// Statuses a report claims an included transaction ended with
+const (
+	StatusSuccess = "SUCCESS"
+	StatusFailed  = "FAILED" // Included but reverted
+)
+
+// Client is the subset of the RPC client the check needs
+type Client interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Claim is a transaction the report says was included on chain
+type Claim struct {
+	Hash   common.Hash
+	Block  uint64 // 0 = not recorded
+	Status string // StatusSuccess, StatusFailed, or "" when not recorded
+}
+
+// Claims are the claims of one report
+type Claims struct {
+	Report  *schema.Report
+	Dataset string  // Transactions dataset the claims were read from ("" = outliers only)
+	Claims  []Claim // Included transactions, in dataset order
+}
+
+// Mismatch is a claim the chain does not back
+type Mismatch struct {
+	Hash   string `json:"hash"`
+	Reason string `json:"reason"`
+}
+
+// Attestation is the verification record of a report
+type Attestation struct {
+	Tool         string     `json:"tool"`
+	Version      string     `json:"version"`
+	Report       string     `json:"report"`        // File name of the report
+	ReportSHA256 string     `json:"report_sha256"` // Hex
+	Dataset      string     `json:"dataset,omitempty"`
+	ChainID      uint64     `json:"chain_id"`
+	Claimed      int        `json:"claimed"` // Included transactions the report lists
+	Checked      int        `json:"checked"` // Of those, looked up on chain
+	Mismatches   []Mismatch `json:"mismatches,omitempty"`
+	Problems     []string   `json:"problems,omitempty"` // Disagreements within the report itself
+	Verified     bool       `json:"verified"`
+	VerifiedAt   string     `json:"verified_at"` // RFC3339
+
+	// Statement is the text the signer signed with EIP-191 personal_sign
+	Statement string `json:"statement,omitempty"`
+	Signer    string `json:"signer,omitempty"`
+	Signature string `json:"signature,omitempty"` // Hex, 65 bytes
+}
+
+// LoadClaims reads the report at path and the claims it makes. The included
+// transactions come from the transactions dataset written next to it with
+// the same timestamp (CSV, compressed CSV or Parquet). Without a dataset,
+// only the latency outliers of the report can be checked.
+func LoadClaims(path string) (*Claims, error) {
+	report, err := schema.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	claims := &Claims{Report: report}
+
+	dataset := findDataset(path)
+	if dataset == "" {
+		seen := make(map[string]bool)
+		for _, tx := range append(append([]schema.Tx(nil), report.SlowestTxs...), report.FastestTxs...) {
+			if seen[tx.Hash] {
+				continue
+			}
+			seen[tx.Hash] = true
+			claims.Claims = append(claims.Claims, Claim{Hash: common.HexToHash(tx.Hash), Block: tx.BlockNumber})
+		}
+		return claims, nil
+	}
+
+	claims.Dataset = dataset
+	if strings.HasSuffix(dataset, ".parquet") {
+		claims.Claims, err = readParquet(dataset)
+	} else {
+		claims.Claims, err = readCSV(dataset)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dataset, err)
+	}
+	return claims, nil
+}
+
+// findDataset returns the transactions dataset of the report at path, or ""
+func findDataset(path string) string {
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, "report_") || !strings.HasSuffix(base, ".json") {
+		return ""
+	}
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(base, "report_"), ".json")
+	for _, ext := range []string{".csv", ".csv.zst", ".csv.gz", ".parquet"} {
+		candidate := filepath.Join(filepath.Dir(path), "transactions_"+timestamp+ext)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// readCSV reads the included transactions of a transactions CSV. Files
+// written before the BlockNumber column existed yield claims without a block.
+func readCSV(path string) ([]Claim, error) {
+	f, err := compress.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	hashCol, ok := columns["Hash"]
+	statusCol, ok2 := columns["Status"]
+	if !ok || !ok2 {
+		return nil, errors.New("missing Hash or Status column")
+	}
+	blockCol, hasBlock := columns["BlockNumber"]
+
+	var claims []Claim
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return claims, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		status := record[statusCol]
+		if status != StatusSuccess && status != StatusFailed {
+			continue
+		}
+		claim := Claim{Hash: common.HexToHash(record[hashCol]), Status: status}
+		if hasBlock && record[blockCol] != "" {
+			if claim.Block, err = strconv.ParseUint(record[blockCol], 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid block number %q of %s", record[blockCol], record[hashCol])
+			}
+		}
+		claims = append(claims, claim)
+	}
+}
+
+// readParquet reads the included transactions of a transactions Parquet file
+func readParquet(path string) ([]Claim, error) {
+	rows, err := parquet.ReadFile[collector.ParquetTx](path)
+	if err != nil {
+		return nil, err
+	}
+	var claims []Claim
+	for _, row := range rows {
+		if row.Status != StatusSuccess && row.Status != StatusFailed {
+			continue
+		}
+		claim := Claim{Hash: common.HexToHash(row.Hash), Status: row.Status}
+		if row.BlockNumber != nil {
+			claim.Block = *row.BlockNumber
+		}
+		claims = append(claims, claim)
+	}
+	return claims, nil
+}
+
+// Problems returns where the dataset disagrees with the report's own counts
+func (c *Claims) Problems() []string {
+	if c.Dataset == "" {
+		return nil
+	}
+	var success, failed int
+	for _, claim := range c.Claims {
+		if claim.Status == StatusSuccess {
+			success++
+		} else {
+			failed++
+		}
+	}
+	var problems []string
+	if s := c.Report.Summary; success != s.TotalConfirmed || failed != s.TotalFailed {
+		problems = append(problems, fmt.Sprintf("dataset lists %d confirmed and %d failed transactions, the report claims %d and %d",
+			success, failed, s.TotalConfirmed, s.TotalFailed))
+	}
+	return problems
+}
+
+// Sample returns n claims picked at random (n <= 0 or beyond the claims = all)
+func Sample(claims []Claim, n int) []Claim {
+	if n <= 0 || n >= len(claims) {
+		return claims
+	}
+	sample := make([]Claim, 0, n)
+	for _, i := range rand.Perm(len(claims))[:n] {
+		sample = append(sample, claims[i])
+	}
+	return sample
+}
+
+// Check looks up the receipt of each claim and returns the claims the chain
+// does not back: no receipt, another block or another status
+func Check(ctx context.Context, client Client, claims []Claim) ([]Mismatch, error) {
+	var mismatches []Mismatch
+	for _, claim := range claims {
+		receipt, err := client.TransactionReceipt(ctx, claim.Hash)
+		if errors.Is(err, ethereum.NotFound) || (err == nil && receipt == nil) {
+			mismatches = append(mismatches, Mismatch{Hash: claim.Hash.Hex(), Reason: "no receipt on chain"})
+			continue
+		}
+		if err != nil {
+			return mismatches, fmt.Errorf("failed to get receipt of %s: %w", claim.Hash.Hex(), err)
+		}
+
+		if claim.Block > 0 && receipt.BlockNumber != nil && receipt.BlockNumber.Uint64() != claim.Block {
+			mismatches = append(mismatches, Mismatch{Hash: claim.Hash.Hex(),
+				Reason: fmt.Sprintf("included in block %d, the report says %d", receipt.BlockNumber, claim.Block)})
+			continue
+		}
+		status := StatusSuccess
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			status = StatusFailed
+		}
+		if claim.Status != "" && status != claim.Status {
+			mismatches = append(mismatches, Mismatch{Hash: claim.Hash.Hex(),
+				Reason: fmt.Sprintf("status %s, the report says %s", status, claim.Status)})
+		}
+	}
+	return mismatches, nil
+}
+
+// NewAttestation returns the verification record of the report at path
+func NewAttestation(path, version string, claims *Claims, chainID uint64, checked int, mismatches []Mismatch) (*Attestation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	a := &Attestation{
+		Tool:         "txhammer",
+		Version:      version,
+		Report:       filepath.Base(path),
+		ReportSHA256: hex.EncodeToString(sum[:]),
+		ChainID:      chainID,
+		Claimed:      len(claims.Claims),
+		Checked:      checked,
+		Mismatches:   mismatches,
+		Problems:     claims.Problems(),
+		VerifiedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	if claims.Dataset != "" {
+		a.Dataset = filepath.Base(claims.Dataset)
+	}
+	a.Verified = checked > 0 && len(mismatches) == 0 && len(a.Problems) == 0
+	return a, nil
+}
+
+// statement returns the text a signer signs off on
+func (a *Attestation) statement() string {
+	verdict := "verified"
+	if !a.Verified {
+		verdict = "not verified"
+	}
+	return fmt.Sprintf("txhammer report %s\nsha256: %s\nchain id: %d\nchecked %d of %d included transactions, %d mismatches\n%s at %s",
+		a.Report, a.ReportSHA256, a.ChainID, a.Checked, a.Claimed, len(a.Mismatches), verdict, a.VerifiedAt)
+}
+
+// Sign signs the statement of the attestation with key (EIP-191 personal_sign)
+func (a *Attestation) Sign(key *ecdsa.PrivateKey) error {
+	a.Statement = a.statement()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(a.Statement)), key)
+	if err != nil {
+		return fmt.Errorf("failed to sign attestation: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	a.Signer = crypto.PubkeyToAddress(key.PublicKey).Hex()
+	a.Signature = hexutil.Encode(sig)
+	return nil
+}
+
+// VerifySignature reports whether the signature of the attestation is
+// valid and made by its signer
+func (a *Attestation) VerifySignature() bool {
+	sig, err := hexutil.Decode(a.Signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return false
+	}
+	sig = append([]byte(nil), sig...)
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(a.Statement)), sig)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*pub).Hex() == a.Signer
+}
+
+// Write writes the attestation as JSON to path
+func (a *Attestation) Write(path string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal attestation: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write attestation: %w", err)
+	}
+	return nil
+}
+
+// AttestationPath returns the default path of the attestation of the report at path
+func AttestationPath(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), ".json")
+	base = strings.TrimPrefix(base, "report_")
+	return filepath.Join(filepath.Dir(path), "verification_"+base+".json")
+}
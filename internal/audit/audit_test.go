@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	schema "github.com/0xmhha/txhammer/pkg/report"
+)
+
+type mockClient struct {
+	receipts map[common.Hash]*types.Receipt
+}
+
+func (m *mockClient) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	if r, ok := m.receipts[hash]; ok {
+		return r, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func receipt(block, status uint64) *types.Receipt {
+	return &types.Receipt{BlockNumber: new(big.Int).SetUint64(block), Status: status}
+}
+
+// writeReport writes a report claiming confirmed and failed transactions and
+// a transactions CSV of rows next to it, and returns the report path
+func writeReport(t *testing.T, confirmed, failed int, rows string) string {
+	t.Helper()
+	dir := t.TempDir()
+	report := schema.Report{Summary: schema.Summary{TotalConfirmed: confirmed, TotalFailed: failed}}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "report_20260101_120000.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	csv := "Hash,Status,BlockNumber\n" + rows
+	if err := os.WriteFile(filepath.Join(dir, "transactions_20260101_120000.csv"), []byte(csv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadClaimsAndCheck(t *testing.T) {
+	h1, h2, h3, h4 := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"), common.HexToHash("0x04")
+	path := writeReport(t, 3, 1, h1.Hex()+",SUCCESS,10\n"+h2.Hex()+",SUCCESS,11\n"+h3.Hex()+",FAILED,12\n"+
+		h4.Hex()+",SUCCESS,12\n"+common.HexToHash("0x05").Hex()+",TIMEOUT,\n")
+
+	claims, err := LoadClaims(path)
+	if err != nil {
+		t.Fatalf("LoadClaims() error = %v", err)
+	}
+	if len(claims.Claims) != 4 {
+		t.Fatalf("claims = %d, want 4 (timeouts are not claims)", len(claims.Claims))
+	}
+	if p := claims.Problems(); len(p) != 0 {
+		t.Errorf("Problems() = %v, want none", p)
+	}
+
+	client := &mockClient{receipts: map[common.Hash]*types.Receipt{
+		h1: receipt(10, types.ReceiptStatusSuccessful),
+		h2: receipt(13, types.ReceiptStatusSuccessful), // Other block
+		h3: receipt(12, types.ReceiptStatusSuccessful), // Other status
+	}}
+	mismatches, err := Check(context.Background(), client, claims.Claims)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	want := map[string]bool{h2.Hex(): true, h3.Hex(): true, h4.Hex(): true}
+	if len(mismatches) != len(want) {
+		t.Fatalf("mismatches = %v, want %d", mismatches, len(want))
+	}
+	for _, m := range mismatches {
+		if !want[m.Hash] {
+			t.Errorf("unexpected mismatch %v", m)
+		}
+	}
+
+	a, err := NewAttestation(path, "test", claims, 1, len(claims.Claims), mismatches)
+	if err != nil {
+		t.Fatalf("NewAttestation() error = %v", err)
+	}
+	if a.Verified {
+		t.Error("Verified = true with mismatches")
+	}
+	if a.Dataset != "transactions_20260101_120000.csv" {
+		t.Errorf("Dataset = %q", a.Dataset)
+	}
+}
+
+func TestClaims_Problems(t *testing.T) {
+	path := writeReport(t, 5, 0, common.HexToHash("0x01").Hex()+",SUCCESS,10\n")
+	claims, err := LoadClaims(path)
+	if err != nil {
+		t.Fatalf("LoadClaims() error = %v", err)
+	}
+	if p := claims.Problems(); len(p) != 1 {
+		t.Errorf("Problems() = %v, want the count mismatch", p)
+	}
+}
+
+func TestSample(t *testing.T) {
+	claims := make([]Claim, 10)
+	if got := len(Sample(claims, 0)); got != 10 {
+		t.Errorf("Sample(0) = %d, want all", got)
+	}
+	if got := len(Sample(claims, 3)); got != 3 {
+		t.Errorf("Sample(3) = %d, want 3", got)
+	}
+	if got := len(Sample(claims, 20)); got != 10 {
+		t.Errorf("Sample(20) = %d, want all", got)
+	}
+}
+
+func TestAttestation_Sign(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &Attestation{Report: "report.json", ReportSHA256: "00", ChainID: 1, Claimed: 1, Checked: 1, Verified: true}
+	if err := a.Sign(key); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if a.Signer != crypto.PubkeyToAddress(key.PublicKey).Hex() {
+		t.Errorf("Signer = %s", a.Signer)
+	}
+	if !a.VerifySignature() {
+		t.Error("VerifySignature() = false for a fresh signature")
+	}
+	a.Statement += " tampered"
+	if a.VerifySignature() {
+		t.Error("VerifySignature() = true for a changed statement")
+	}
+}
+
+func TestAttestationPath(t *testing.T) {
+	got := AttestationPath(filepath.Join("reports", "report_20260101_120000.json"))
+	if want := filepath.Join("reports", "verification_20260101_120000.json"); got != want {
+		t.Errorf("AttestationPath() = %s, want %s", got, want)
+	}
+}
@@ -2,17 +2,22 @@ package batcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/schollz/progressbar/v3"
 
+	"github.com/0xmhha/txhammer/internal/budget"
 	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/progress"
+	"github.com/0xmhha/txhammer/internal/util/units"
 )
 
 // Client interface for batch operations
@@ -23,8 +28,12 @@ type Client interface {
 
 // Batcher handles batch transaction sending
 type Batcher struct {
-	client Client
-	config *Config
+	client    Client
+	config    *Config
+	gate      Gate
+	cutoff    Cutoff
+	budget    Budget
+	callbacks *Callbacks
 
 	// Metrics
 	sentCount   atomic.Int64
@@ -46,55 +55,102 @@ func New(client Client, config *Config) (*Batcher, error) {
 	}, nil
 }
 
+// WithGate sets a gate that is waited on before each batch is sent
+func (b *Batcher) WithGate(gate Gate) *Batcher {
+	b.gate = gate
+	return b
+}
+
+// WithCutoff sets a cutoff after which no further batch is sent
+func (b *Batcher) WithCutoff(cutoff Cutoff) *Batcher {
+	b.cutoff = cutoff
+	return b
+}
+
+// WithBudget sets a budget that must admit each batch before it is sent
+func (b *Batcher) WithBudget(budget Budget) *Batcher {
+	b.budget = budget
+	return b
+}
+
+// WithCallbacks sets the send notification callbacks
+func (b *Batcher) WithCallbacks(callbacks *Callbacks) *Batcher {
+	b.callbacks = callbacks
+	return b
+}
+
 // SendAll sends all transactions in batches
 func (b *Batcher) SendAll(ctx context.Context, txs []*txbuilder.SignedTx) (*Summary, error) {
 	if len(txs) == 0 {
 		return &Summary{}, nil
 	}
 
-	fmt.Printf("\nStarting Batch Transaction Sending\n\n")
-	fmt.Printf("Total transactions: %d\n", len(txs))
-	fmt.Printf("Batch size: %d\n", b.config.BatchSize)
-	fmt.Printf("Max concurrent: %d\n", b.config.MaxConcurrent)
-	fmt.Printf("Batch interval: %s\n\n", b.config.BatchInterval)
+	console.Printf("\nStarting Batch Transaction Sending\n\n")
+	console.Printf("Total transactions: %d\n", len(txs))
+	console.Printf("Batch size: %d\n", b.config.BatchSize)
+	console.Printf("Max concurrent: %d\n", b.config.MaxConcurrent)
+	console.Printf("Batch interval: %s\n", b.config.BatchInterval)
+	if b.config.SendDeadline > 0 {
+		console.Printf("Send deadline: %s\n", b.config.SendDeadline)
+	}
+	console.Println()
 
 	startTime := time.Now()
 
 	// Split into batches
 	batches := b.splitIntoBatches(txs)
-	fmt.Printf("Total batches: %d\n\n", len(batches))
+	console.Printf("Total batches: %d\n\n", len(batches))
 
-	// Create progress bar
-	bar := progressbar.Default(int64(len(txs)), "sending txs")
+	// Progress is counted atomically and redrawn off the send path
+	ticker := progress.NewTicker(progress.New(int64(len(txs)), "sending txs"), progressInterval)
 
-	// Process batches with concurrency control
+	// Serialization workers encode batches ahead of the sender workers
 	batchResults := make([]*BatchResult, len(batches))
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, b.config.MaxConcurrent)
+	indices := make(chan int)
+	prepared := make(chan *preparedBatch, 2*b.config.MaxConcurrent)
 
-	for i, batch := range batches {
-		wg.Add(1)
-		go func(idx int, batchTxs []*txbuilder.SignedTx) {
-			defer wg.Done()
-
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			result := b.sendBatch(ctx, idx, batchTxs)
-			batchResults[idx] = result
-
-			// Update progress
-			progress.Add(bar, len(batchTxs))
-
-			// Wait between batches
-			if b.config.BatchInterval > 0 {
-				time.Sleep(b.config.BatchInterval)
+	go func() {
+		defer close(indices)
+		for i := range batches {
+			indices <- i
+		}
+	}()
+
+	var encoders sync.WaitGroup
+	for w := 0; w < b.config.EncodeWorkers; w++ {
+		encoders.Add(1)
+		go func() {
+			defer encoders.Done()
+			for idx := range indices {
+				prepared <- b.prepareBatch(idx, batches[idx])
+			}
+		}()
+	}
+	go func() {
+		encoders.Wait()
+		close(prepared)
+	}()
+
+	var senders sync.WaitGroup
+	for w := 0; w < b.config.MaxConcurrent; w++ {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for batch := range prepared {
+				batchResults[batch.result.BatchIndex] = b.sendBatch(ctx, batch)
+				ticker.Add(batch.result.TxCount)
+
+				// Wait between batches
+				if b.config.BatchInterval > 0 {
+					time.Sleep(b.config.BatchInterval)
+				}
 			}
-		}(i, batch)
+		}()
 	}
 
-	wg.Wait()
-	fmt.Println()
+	senders.Wait()
+	ticker.Stop()
+	console.Println()
 
 	// Build summary
 	summary := b.buildSummary(batchResults, time.Since(startTime))
@@ -124,48 +180,122 @@ func (b *Batcher) splitIntoBatches(txs []*txbuilder.SignedTx) [][]*txbuilder.Sig
 	return batches
 }
 
-// sendBatch sends a single batch of transactions
-func (b *Batcher) sendBatch(ctx context.Context, batchIdx int, txs []*txbuilder.SignedTx) *BatchResult {
-	startTime := time.Now()
+// progressInterval is how often the sending progress bar is redrawn
+const progressInterval = 100 * time.Millisecond
 
-	result := &BatchResult{
-		BatchIndex: batchIdx,
-		TxCount:    len(txs),
-		StartTime:  startTime,
-		Results:    make([]*TxResult, len(txs)),
+// preparedBatch is a batch serialized and ready to send
+type preparedBatch struct {
+	txs     []*txbuilder.SignedTx
+	rawTxs  [][]byte
+	encoded []string // nil unless the client accepts encoded transactions
+	result  *BatchResult
+	readyAt time.Time // When the batch was ready to send; its send deadline counts from here
+}
+
+// prepareBatch builds the pending results of a batch and serializes it for
+// the client
+func (b *Batcher) prepareBatch(batchIdx int, txs []*txbuilder.SignedTx) *preparedBatch {
+	batch := &preparedBatch{
+		txs:     txs,
+		rawTxs:  make([][]byte, len(txs)),
+		readyAt: time.Now(),
+		result: &BatchResult{
+			BatchIndex: batchIdx,
+			TxCount:    len(txs),
+			Results:    make([]*TxResult, len(txs)),
+		},
+	}
+
+	_, encode := b.client.(EncodedClient)
+	if encode {
+		batch.encoded = make([]string, len(txs))
 	}
 
-	// Prepare raw transactions
-	rawTxs := make([][]byte, len(txs))
 	for i, tx := range txs {
-		rawTxs[i] = tx.RawTx
-		result.Results[i] = &TxResult{
+		batch.rawTxs[i] = tx.RawTx
+		if encode {
+			batch.encoded[i] = hexutil.Encode(tx.RawTx)
+		}
+		batch.result.Results[i] = &TxResult{
 			Tx:       tx,
 			Status:   TxStatusPending,
 			BatchIdx: batchIdx,
 		}
 	}
 
+	return batch
+}
+
+// sendBatch sends a single prepared batch of transactions. A batch not
+// accepted within the send deadline of becoming ready, whether it waited for
+// a free sender, the gate or retries, expires instead.
+func (b *Batcher) sendBatch(ctx context.Context, batch *preparedBatch) *BatchResult {
+	startTime := time.Now()
+	var deadline time.Time
+	if b.config.SendDeadline > 0 {
+		deadline = batch.readyAt.Add(b.config.SendDeadline)
+	}
+	txs := batch.txs
+	result := batch.result
+	result.StartTime = startTime
+
+	// Pause while the gate is closed
+	if b.gate != nil {
+		if err := b.gate.Wait(ctx); err != nil {
+			result.EndTime = time.Now()
+			result.Duration = result.EndTime.Sub(startTime)
+			return b.failBatch(result, err)
+		}
+	}
+
+	// Refuse the batch once the cutoff has tripped
+	if b.cutoff != nil {
+		if err := b.cutoff.Err(); err != nil {
+			result.EndTime = time.Now()
+			result.Duration = result.EndTime.Sub(startTime)
+			return b.failBatch(result, err)
+		}
+	}
+
+	// Drop the batch if waiting for its turn or the gate took too long
+	if expired(deadline) {
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(startTime)
+		return b.expireBatch(result)
+	}
+
+	// Refuse the batch if its fees would exceed the budget, and every batch
+	// after the first refusal, so that no later nonce is sent past a gap
+	if b.budget != nil {
+		err := budget.ErrExceeded
+		if b.budget.Stopped() == nil {
+			err = b.budget.Reserve(batchTxs(txs)...)
+		}
+		if err != nil {
+			result.EndTime = time.Now()
+			result.Duration = result.EndTime.Sub(startTime)
+			return b.failBatch(result, err)
+		}
+	}
+
 	// Create timeout context
 	sendCtx, cancel := context.WithTimeout(ctx, b.config.Timeout)
 	defer cancel()
 
 	// Send batch
-	hashes, err := b.sendBatchWithRetry(sendCtx, rawTxs)
+	hashes, err := b.sendBatchWithRetry(sendCtx, batch, deadline)
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(startTime)
 
 	if err != nil {
-		result.Error = err
-		// Mark all as failed
-		for i := range result.Results {
-			result.Results[i].Status = TxStatusFailed
-			result.Results[i].Error = err
-			result.FailedCount++
-			b.failedCount.Add(1)
+		if b.budget != nil {
+			b.budget.Release(batchTxs(txs)...)
 		}
-		return result
+		if errors.Is(err, ErrExpired) {
+			return b.expireBatch(result)
+		}
+		return b.failBatch(result, err)
 	}
 
 	// Process results
@@ -175,29 +305,94 @@ func (b *Batcher) sendBatch(ctx context.Context, batchIdx int, txs []*txbuilder.
 		result.Results[i].SentAt = now
 
 		if hash == (common.Hash{}) {
+			if b.budget != nil {
+				b.budget.Release(txs[i].Tx)
+			}
 			result.Results[i].Status = TxStatusFailed
 			result.FailedCount++
 			b.failedCount.Add(1)
+			b.notifyFailed(result.Results[i])
 		} else {
 			result.Results[i].Status = TxStatusSent
 			result.SuccessCount++
 			b.sentCount.Add(1)
+			if b.callbacks != nil && b.callbacks.OnSent != nil {
+				b.callbacks.OnSent(result.Results[i])
+			}
 		}
 	}
 
 	return result
 }
 
-// sendBatchWithRetry sends a batch with retry logic
-func (b *Batcher) sendBatchWithRetry(ctx context.Context, rawTxs [][]byte) ([]common.Hash, error) {
+// notifyFailed passes a transaction the node did not take to the OnFailed callback
+func (b *Batcher) notifyFailed(r *TxResult) {
+	if b.callbacks != nil && b.callbacks.OnFailed != nil {
+		b.callbacks.OnFailed(r)
+	}
+}
+
+// batchTxs returns the transactions of a batch
+func batchTxs(txs []*txbuilder.SignedTx) []*types.Transaction {
+	out := make([]*types.Transaction, len(txs))
+	for i, tx := range txs {
+		out[i] = tx.Tx
+	}
+	return out
+}
+
+// failBatch marks all transactions in the batch as failed and reports each
+// to the OnFailed callback, whether the send failed or never started
+func (b *Batcher) failBatch(result *BatchResult, err error) *BatchResult {
+	result.Error = err
+	for i := range result.Results {
+		result.Results[i].Status = TxStatusFailed
+		result.Results[i].Error = err
+		result.FailedCount++
+		b.failedCount.Add(1)
+		b.notifyFailed(result.Results[i])
+	}
+	return result
+}
+
+// expireBatch marks all transactions in the batch as expired before send
+func (b *Batcher) expireBatch(result *BatchResult) *BatchResult {
+	result.Error = ErrExpired
+	for i := range result.Results {
+		result.Results[i].Status = TxStatusExpired
+		result.Results[i].Error = ErrExpired
+		result.ExpiredCount++
+	}
+	return result
+}
+
+// expired reports whether a send deadline has passed (zero = no deadline)
+func expired(deadline time.Time) bool {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
+
+// sendBatchWithRetry sends a batch with retry logic. No retry is started
+// once the deadline has passed; the batch then expires.
+func (b *Batcher) sendBatchWithRetry(ctx context.Context, batch *preparedBatch, deadline time.Time) ([]common.Hash, error) {
 	var lastErr error
+	encodedClient, encoded := b.client.(EncodedClient)
+	encoded = encoded && batch.encoded != nil
 
 	for attempt := 0; attempt <= b.config.RetryCount; attempt++ {
 		if attempt > 0 {
 			time.Sleep(b.config.RetryDelay)
+			if expired(deadline) {
+				return nil, fmt.Errorf("%w after %d attempts: %w", ErrExpired, attempt, lastErr)
+			}
 		}
 
-		hashes, err := b.client.BatchSendRawTransactions(ctx, rawTxs)
+		var hashes []common.Hash
+		var err error
+		if encoded {
+			hashes, err = encodedClient.BatchSendEncodedTransactions(ctx, batch.encoded)
+		} else {
+			hashes, err = b.client.BatchSendRawTransactions(ctx, batch.rawTxs)
+		}
 		if err == nil {
 			return hashes, nil
 		}
@@ -228,12 +423,15 @@ func (b *Batcher) buildSummary(batchResults []*BatchResult, totalDuration time.D
 		summary.TotalTxs += br.TxCount
 		summary.SuccessCount += br.SuccessCount
 		summary.FailedCount += br.FailedCount
+		summary.ExpiredCount += br.ExpiredCount
 		totalBatchTime += br.Duration
 
-		// Collect failed transactions
+		// Collect failed and expired transactions and count the bytes of sent ones
 		for _, tr := range br.Results {
-			if tr.Status == TxStatusFailed {
+			if tr.Status == TxStatusFailed || tr.Status == TxStatusExpired {
 				summary.FailedTxs = append(summary.FailedTxs, tr)
+			} else {
+				summary.TotalBytes += int64(len(tr.Tx.RawTx))
 			}
 		}
 	}
@@ -244,6 +442,7 @@ func (b *Batcher) buildSummary(batchResults []*BatchResult, totalDuration time.D
 
 	if totalDuration.Seconds() > 0 {
 		summary.TxPerSecond = float64(summary.SuccessCount) / totalDuration.Seconds()
+		summary.BytesPerSec = float64(summary.TotalBytes) / totalDuration.Seconds()
 	}
 
 	return summary
@@ -251,19 +450,24 @@ func (b *Batcher) buildSummary(batchResults []*BatchResult, totalDuration time.D
 
 // printSummary prints the batch operation summary
 func (b *Batcher) printSummary(summary *Summary) {
-	fmt.Printf("\nBatch Sending Summary\n\n")
-	fmt.Printf("Total batches: %d\n", summary.TotalBatches)
-	fmt.Printf("Total transactions: %d\n", summary.TotalTxs)
-	fmt.Printf("Successful: %d (%.2f%%)\n", summary.SuccessCount,
+	console.Printf("\nBatch Sending Summary\n\n")
+	console.Printf("Total batches: %d\n", summary.TotalBatches)
+	console.Printf("Total transactions: %d\n", summary.TotalTxs)
+	console.Printf("Successful: %d (%.2f%%)\n", summary.SuccessCount,
 		float64(summary.SuccessCount)/float64(summary.TotalTxs)*100)
-	fmt.Printf("Failed: %d (%.2f%%)\n", summary.FailedCount,
+	console.Printf("Failed: %d (%.2f%%)\n", summary.FailedCount,
 		float64(summary.FailedCount)/float64(summary.TotalTxs)*100)
-	fmt.Printf("Total duration: %s\n", summary.TotalDuration)
-	fmt.Printf("Avg batch time: %s\n", summary.AvgBatchTime)
-	fmt.Printf("Throughput: %.2f tx/s\n", summary.TxPerSecond)
+	if summary.ExpiredCount > 0 {
+		console.Printf("Expired before send: %d (%.2f%%)\n", summary.ExpiredCount,
+			float64(summary.ExpiredCount)/float64(summary.TotalTxs)*100)
+	}
+	console.Printf("Total duration: %s\n", summary.TotalDuration)
+	console.Printf("Avg batch time: %s\n", summary.AvgBatchTime)
+	console.Printf("Throughput: %.2f tx/s\n", summary.TxPerSecond)
+	console.Printf("Bandwidth: %s sent (%s/s)\n", units.FormatBytes(float64(summary.TotalBytes)), units.FormatBytes(summary.BytesPerSec))
 
 	if len(summary.FailedTxs) > 0 {
-		fmt.Printf("\n[WARN] Failed Transactions: %d\n", len(summary.FailedTxs))
+		console.Warnf("\nFailed Transactions: %d\n", len(summary.FailedTxs))
 		// Show first 5 failed txs
 		showCount := 5
 		if len(summary.FailedTxs) < showCount {
@@ -271,11 +475,11 @@ func (b *Batcher) printSummary(summary *Summary) {
 		}
 		for i := 0; i < showCount; i++ {
 			ft := summary.FailedTxs[i]
-			fmt.Printf("  - Batch %d, From: %s, Error: %v\n",
+			console.Summaryf("  - Batch %d, From: %s, Error: %v\n",
 				ft.BatchIdx, ft.Tx.From.Hex()[:10], ft.Error)
 		}
 		if len(summary.FailedTxs) > showCount {
-			fmt.Printf("  ... and %d more\n", len(summary.FailedTxs)-showCount)
+			console.Summaryf("  ... and %d more\n", len(summary.FailedTxs)-showCount)
 		}
 	}
 }
@@ -5,13 +5,17 @@ import (
 	"errors"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 
+	"github.com/0xmhha/txhammer/internal/budget"
 	"github.com/0xmhha/txhammer/internal/txbuilder"
 )
 
@@ -26,12 +30,14 @@ type mockBatchClient struct {
 	batchSendErr    error
 	batchCallErr    error
 	callCount       int
+	delay           time.Duration // Per-call latency
 }
 
 func (m *mockBatchClient) BatchSendRawTransactions(ctx context.Context, rawTxs [][]byte) ([]common.Hash, error) {
 	m.mu.Lock()
 	m.callCount++
 	m.mu.Unlock()
+	time.Sleep(m.delay)
 	if m.batchSendErr != nil {
 		return nil, m.batchSendErr
 	}
@@ -98,6 +104,7 @@ func TestTxStatus_String(t *testing.T) {
 		{TxStatusSent, "SENT"},
 		{TxStatusConfirmed, "CONFIRMED"},
 		{TxStatusFailed, "FAILED"},
+		{TxStatusExpired, "EXPIRED"},
 		{TxStatus(99), "UNKNOWN"},
 	}
 
@@ -259,6 +266,65 @@ func TestBatcher_SendAll_Success(t *testing.T) {
 	if summary.TotalBatches != 3 {
 		t.Errorf("TotalBatches = %d, want 3", summary.TotalBatches)
 	}
+	if summary.TotalBytes != 25*3 {
+		t.Errorf("TotalBytes = %d, want %d", summary.TotalBytes, 25*3)
+	}
+	if summary.BytesPerSec <= 0 {
+		t.Errorf("BytesPerSec = %.2f, want > 0", summary.BytesPerSec)
+	}
+}
+
+// encodedMockClient also accepts pre-encoded transactions
+type encodedMockClient struct {
+	mockBatchClient
+	encodedCalls atomic.Int64
+}
+
+func (m *encodedMockClient) BatchSendEncodedTransactions(ctx context.Context, encoded []string) ([]common.Hash, error) {
+	m.encodedCalls.Add(1)
+	hashes := make([]common.Hash, len(encoded))
+	for i, raw := range encoded {
+		hashes[i] = crypto.Keccak256Hash(hexutil.MustDecode(raw))
+	}
+	return hashes, nil
+}
+
+func TestBatcher_SendAll_Encoded(t *testing.T) {
+	client := &encodedMockClient{}
+	cfg := &Config{
+		BatchSize:     3,
+		MaxConcurrent: 3,
+		EncodeWorkers: 2,
+		Timeout:       5 * time.Second,
+	}
+	batcher := mustNewBatcher(t, client, cfg)
+
+	txs := createTestTxs(20)
+	summary, err := batcher.SendAll(context.Background(), txs)
+	if err != nil {
+		t.Fatalf("SendAll() error = %v", err)
+	}
+
+	if summary.SuccessCount != 20 {
+		t.Errorf("SuccessCount = %d, want 20", summary.SuccessCount)
+	}
+	if got := client.encodedCalls.Load(); got != 7 {
+		t.Errorf("encoded batch calls = %d, want 7", got)
+	}
+	if client.callCount != 0 {
+		t.Errorf("raw batch calls = %d, want 0 when the client accepts encoded txs", client.callCount)
+	}
+	for i, br := range summary.BatchResults {
+		if br == nil || br.BatchIndex != i {
+			t.Fatalf("BatchResults[%d] = %+v, want batch %d", i, br, i)
+		}
+		for j, tr := range br.Results {
+			tx := txs[i*cfg.BatchSize+j]
+			if tr.Tx != tx || tr.Hash != crypto.Keccak256Hash(tx.RawTx) {
+				t.Errorf("batch %d result %d does not match its transaction", i, j)
+			}
+		}
+	}
 }
 
 func TestBatcher_SendAll_WithFailures(t *testing.T) {
@@ -290,6 +356,9 @@ func TestBatcher_SendAll_WithFailures(t *testing.T) {
 	if len(summary.FailedTxs) != 10 {
 		t.Errorf("FailedTxs = %d, want 10", len(summary.FailedTxs))
 	}
+	if summary.TotalBytes != 0 {
+		t.Errorf("TotalBytes = %d, want 0 when nothing was sent", summary.TotalBytes)
+	}
 }
 
 func TestBatcher_splitIntoBatches(t *testing.T) {
@@ -436,7 +505,7 @@ func TestBatcher_sendBatchWithRetry(t *testing.T) {
 	batcher := mustNewBatcher(t, client, cfg)
 
 	rawTxs := [][]byte{{0x01}, {0x02}}
-	hashes, err := batcher.sendBatchWithRetry(context.Background(), rawTxs)
+	hashes, err := batcher.sendBatchWithRetry(context.Background(), &preparedBatch{rawTxs: rawTxs}, time.Time{})
 	if err != nil {
 		t.Fatalf("sendBatchWithRetry() error = %v", err)
 	}
@@ -527,6 +596,72 @@ func TestStreamer_Stream_Success(t *testing.T) {
 	if result.FailedCount != 0 {
 		t.Errorf("FailedCount = %d, want 0", result.FailedCount)
 	}
+	if result.TotalBytes != 10*3 {
+		t.Errorf("TotalBytes = %d, want %d", result.TotalBytes, 10*3)
+	}
+}
+
+func TestStreamer_Stream_WorkerClients(t *testing.T) {
+	shared := &mockStreamClient{}
+	cfg := &StreamerConfig{
+		Rate:    10000,
+		Burst:   100,
+		Workers: 2,
+		Timeout: 5 * time.Second,
+	}
+	conns := make([]*mockStreamClient, 4)
+	clients := make([]StreamClient, len(conns))
+	for i := range conns {
+		conns[i] = &mockStreamClient{}
+		clients[i] = conns[i]
+	}
+	streamer := NewStreamer(shared, cfg).WithWorkerClients(clients)
+
+	result, err := streamer.Stream(context.Background(), createTestTxs(20))
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+
+	if result.SuccessCount != 20 {
+		t.Errorf("SuccessCount = %d, want 20", result.SuccessCount)
+	}
+	if result.Connections != 4 {
+		t.Errorf("Connections = %d, want 4", result.Connections)
+	}
+	if shared.callCount != 0 {
+		t.Errorf("shared client used %d times, want 0", shared.callCount)
+	}
+	total := 0
+	for _, conn := range conns {
+		total += conn.callCount
+	}
+	if total != 20 {
+		t.Errorf("connection calls = %d, want 20", total)
+	}
+}
+
+func TestStreamer_WorkerClientsPinned(t *testing.T) {
+	clients := make([]StreamClient, 6)
+	for i := range clients {
+		clients[i] = &mockStreamClient{}
+	}
+	streamer := NewStreamer(&mockStreamClient{}, &StreamerConfig{Workers: 3}).WithWorkerClients(clients)
+
+	seen := make(map[StreamClient]int)
+	for w := 0; w < 3; w++ {
+		pinned := streamer.workerClients(w)
+		if len(pinned) != 2 {
+			t.Fatalf("worker %d has %d connections, want 2", w, len(pinned))
+		}
+		for _, c := range pinned {
+			seen[c]++
+		}
+	}
+	for i, c := range clients {
+		if seen[c] != 1 {
+			t.Errorf("connection %d pinned to %d workers, want 1", i, seen[c])
+		}
+	}
 }
 
 func TestStreamer_Stream_WithFailures(t *testing.T) {
@@ -740,3 +875,409 @@ func TestTxResult(t *testing.T) {
 
 // Prevent unused import error
 var _ = big.NewInt(1)
+
+// mockBudget admits a fixed number of transactions
+type mockBudget struct {
+	mu       sync.Mutex
+	left     int
+	released int
+	stop     *budget.Stop
+}
+
+func (m *mockBudget) Reserve(txs ...*types.Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(txs) > m.left {
+		if m.stop == nil {
+			m.stop = &budget.Stop{At: time.Now()}
+		}
+		return errors.New("spend budget exceeded")
+	}
+	m.left -= len(txs)
+	return nil
+}
+
+func (m *mockBudget) Release(txs ...*types.Transaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.released += len(txs)
+}
+
+func (m *mockBudget) Stopped() *budget.Stop {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stop
+}
+
+func TestBatcher_SendAll_WithBudget(t *testing.T) {
+	client := &mockBatchClient{}
+	cfg := DefaultConfig()
+	cfg.BatchSize = 5
+	cfg.MaxConcurrent = 1
+	b, _ := New(client, cfg)
+	b.WithBudget(&mockBudget{left: 10})
+
+	summary, err := b.SendAll(context.Background(), createTestTxs(20))
+	if err != nil {
+		t.Fatalf("SendAll() error = %v", err)
+	}
+	if summary.SuccessCount != 10 || summary.FailedCount != 10 {
+		t.Errorf("sent=%d failed=%d, want 10/10", summary.SuccessCount, summary.FailedCount)
+	}
+	if client.callCount != 2 {
+		t.Errorf("callCount = %d, want 2 batches sent", client.callCount)
+	}
+}
+
+func TestBatcher_SendAll_StopsAtFirstBudgetRefusal(t *testing.T) {
+	client := &mockBatchClient{}
+	cfg := DefaultConfig()
+	cfg.BatchSize = 5
+	cfg.MaxConcurrent = 1
+	b, _ := New(client, cfg)
+	b.WithBudget(&mockBudget{left: 8})
+
+	// The second batch of 5 is refused; the tail batch of 3 would fit but
+	// would leave a nonce gap
+	summary, err := b.SendAll(context.Background(), createTestTxs(13))
+	if err != nil {
+		t.Fatalf("SendAll() error = %v", err)
+	}
+	if summary.SuccessCount != 5 || summary.FailedCount != 8 {
+		t.Errorf("sent=%d failed=%d, want 5/8", summary.SuccessCount, summary.FailedCount)
+	}
+	if client.callCount != 1 {
+		t.Errorf("callCount = %d, want 1 batch sent", client.callCount)
+	}
+}
+
+func TestStreamer_Stream_WithBudget(t *testing.T) {
+	client := &mockStreamClient{}
+	streamer := NewStreamer(client, &StreamerConfig{Rate: 10000, Burst: 100, Workers: 1, Timeout: time.Second})
+	streamer.WithBudget(&mockBudget{left: 3})
+
+	result, err := streamer.Stream(context.Background(), createTestTxs(10))
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if result.SuccessCount != 3 || result.FailedCount != 7 {
+		t.Errorf("sent=%d failed=%d, want 3/7", result.SuccessCount, result.FailedCount)
+	}
+	if client.callCount != 3 {
+		t.Errorf("callCount = %d, want 3", client.callCount)
+	}
+}
+
+// mockCutoff trips after a fixed number of checks
+type mockCutoff struct {
+	mu   sync.Mutex
+	left int
+}
+
+func (m *mockCutoff) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.left == 0 {
+		return errors.New("stop block reached")
+	}
+	m.left--
+	return nil
+}
+
+func TestBatcher_SendAll_WithCutoff(t *testing.T) {
+	client := &mockBatchClient{}
+	cfg := DefaultConfig()
+	cfg.BatchSize = 5
+	cfg.MaxConcurrent = 1
+	b, _ := New(client, cfg)
+	b.WithCutoff(&mockCutoff{left: 2})
+	var notified atomic.Int64
+	b.WithCallbacks(&Callbacks{OnFailed: func(*TxResult) { notified.Add(1) }})
+
+	summary, err := b.SendAll(context.Background(), createTestTxs(20))
+	if err != nil {
+		t.Fatalf("SendAll() error = %v", err)
+	}
+	if summary.SuccessCount != 10 || summary.FailedCount != 10 {
+		t.Errorf("sent=%d failed=%d, want 10/10", summary.SuccessCount, summary.FailedCount)
+	}
+	if notified.Load() != 10 {
+		t.Errorf("OnFailed called %d times, want 10 for the txs refused unsent", notified.Load())
+	}
+	if client.callCount != 2 {
+		t.Errorf("callCount = %d, want 2 batches sent", client.callCount)
+	}
+}
+
+func TestStreamer_Stream_WithCutoff(t *testing.T) {
+	client := &mockStreamClient{}
+	streamer := NewStreamer(client, &StreamerConfig{Rate: 10000, Burst: 100, Workers: 1, Timeout: time.Second})
+	streamer.WithCutoff(&mockCutoff{left: 4})
+	var notified atomic.Int64
+	streamer.WithCallbacks(&Callbacks{OnFailed: func(*TxResult) { notified.Add(1) }})
+
+	result, err := streamer.Stream(context.Background(), createTestTxs(10))
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if result.SuccessCount != 4 || result.FailedCount != 6 {
+		t.Errorf("sent=%d failed=%d, want 4/6", result.SuccessCount, result.FailedCount)
+	}
+	if notified.Load() != 6 {
+		t.Errorf("OnFailed called %d times, want 6 for the txs refused unsent", notified.Load())
+	}
+}
+
+func TestBatcher_SendAll_SendDeadline(t *testing.T) {
+	client := &mockBatchClient{batchSendErr: errors.New("connection refused")}
+	cfg := DefaultConfig()
+	cfg.BatchSize = 5
+	cfg.MaxConcurrent = 1
+	cfg.BatchInterval = 0
+	cfg.RetryDelay = 20 * time.Millisecond
+	cfg.SendDeadline = 10 * time.Millisecond
+	b, _ := New(client, cfg)
+
+	summary, err := b.SendAll(context.Background(), createTestTxs(10))
+	if err != nil {
+		t.Fatalf("SendAll() error = %v", err)
+	}
+	// The first batch stops retrying at its deadline, the second waited
+	// behind it past its own and is never sent
+	if summary.ExpiredCount != 10 || summary.FailedCount != 0 {
+		t.Errorf("expired=%d failed=%d, want 10/0", summary.ExpiredCount, summary.FailedCount)
+	}
+	if client.callCount != 1 {
+		t.Errorf("callCount = %d, want 1", client.callCount)
+	}
+	if len(summary.FailedTxs) != 10 || !errors.Is(summary.FailedTxs[0].Error, ErrExpired) {
+		t.Errorf("FailedTxs = %d, want 10 expired", len(summary.FailedTxs))
+	}
+}
+
+func TestBatcher_SendAll_SendDeadlinePerBatch(t *testing.T) {
+	client := &mockBatchClient{delay: 20 * time.Millisecond}
+	cfg := DefaultConfig()
+	cfg.BatchSize = 5
+	cfg.MaxConcurrent = 1
+	cfg.BatchInterval = 0
+	cfg.SendDeadline = 100 * time.Millisecond
+	b, _ := New(client, cfg)
+
+	// The run takes longer than the deadline, but no batch waits that long
+	summary, err := b.SendAll(context.Background(), createTestTxs(40))
+	if err != nil {
+		t.Fatalf("SendAll() error = %v", err)
+	}
+	if summary.TotalDuration < cfg.SendDeadline {
+		t.Fatalf("TotalDuration = %s, want longer than the deadline", summary.TotalDuration)
+	}
+	if summary.SuccessCount != 40 || summary.ExpiredCount != 0 {
+		t.Errorf("sent=%d expired=%d, want 40/0", summary.SuccessCount, summary.ExpiredCount)
+	}
+}
+
+// stallGate stalls once, on the n-th wait
+type stallGate struct {
+	n     int
+	stall time.Duration
+	waits int
+}
+
+func (g *stallGate) Wait(ctx context.Context) error {
+	g.waits++
+	if g.waits == g.n {
+		time.Sleep(g.stall)
+	}
+	return nil
+}
+
+func TestStreamer_Stream_SendDeadline(t *testing.T) {
+	client := &mockStreamClient{}
+	streamer := NewStreamer(client, &StreamerConfig{
+		Rate:         10000,
+		Burst:        100,
+		Workers:      1,
+		Timeout:      time.Second,
+		SendDeadline: 10 * time.Millisecond,
+	})
+	streamer.WithGate(&stallGate{n: 3, stall: 50 * time.Millisecond})
+
+	result, err := streamer.Stream(context.Background(), createTestTxs(10))
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if result.SuccessCount != 2 || result.ExpiredCount != 8 || result.FailedCount != 0 {
+		t.Errorf("sent=%d expired=%d failed=%d, want 2/8/0", result.SuccessCount, result.ExpiredCount, result.FailedCount)
+	}
+	if client.callCount != 2 {
+		t.Errorf("callCount = %d, want 2", client.callCount)
+	}
+}
+
+// redundantMockClient answers each eth_sendRawTransaction with the tx hash,
+// or with err when set
+type redundantMockClient struct {
+	err     error         // Per-tx error
+	callErr error         // Whole-call error
+	delay   time.Duration // Time before answering
+	calls   atomic.Int64
+}
+
+func (m *redundantMockClient) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	m.calls.Add(1)
+	if m.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.delay):
+		}
+	}
+	if m.callErr != nil {
+		return m.callErr
+	}
+	for i := range batch {
+		if m.err != nil {
+			batch[i].Error = m.err
+			continue
+		}
+		raw := hexutil.MustDecode(batch[i].Args[0].(string))
+		*batch[i].Result.(*common.Hash) = crypto.Keccak256Hash(raw)
+	}
+	return nil
+}
+
+func TestNewRedundant(t *testing.T) {
+	endpoints := []RedundantEndpoint{{Name: "a", Client: &redundantMockClient{}}}
+	if _, err := NewRedundant(endpoints, 2); err == nil {
+		t.Error("NewRedundant() with fewer endpoints than copies should fail")
+	}
+	if _, err := NewRedundant(endpoints, 1); err != nil {
+		t.Errorf("NewRedundant() error = %v", err)
+	}
+}
+
+func TestRedundant_BatchSend(t *testing.T) {
+	a := &redundantMockClient{}
+	b := &redundantMockClient{err: errors.New("already known")}
+	c := &redundantMockClient{err: errors.New("txpool is full")}
+	r, err := NewRedundant([]RedundantEndpoint{{Name: "a", Client: a}, {Name: "b", Client: b}, {Name: "c", Client: c}}, 2)
+	if err != nil {
+		t.Fatalf("NewRedundant() error = %v", err)
+	}
+
+	rawTxs := [][]byte{{0x01}, {0x02}}
+	// Sends rotate: a+b, b+c, c+a
+	for i := 0; i < 3; i++ {
+		hashes, err := r.BatchSendRawTransactions(context.Background(), rawTxs)
+		if err != nil {
+			t.Fatalf("send %d error = %v", i, err)
+		}
+		for j, raw := range rawTxs {
+			if hashes[j] != crypto.Keccak256Hash(raw) {
+				t.Errorf("send %d hash[%d] = %s, want the tx hash", i, j, hashes[j].Hex())
+			}
+		}
+	}
+	if a.calls.Load() != 2 || b.calls.Load() != 2 || c.calls.Load() != 2 {
+		t.Errorf("calls = %d, %d, %d, want 2 each", a.calls.Load(), b.calls.Load(), c.calls.Load())
+	}
+
+	stats := r.Stats()
+	if stats.Txs != 6 || stats.AckedByAll != 2 || stats.AckedBySome != 4 || stats.AckedByNone != 0 {
+		t.Errorf("stats = %+v, want 6 txs, 2 acked by all, 4 by some", stats)
+	}
+	if stats.DuplicateAcks != 2 {
+		t.Errorf("DuplicateAcks = %d, want 2", stats.DuplicateAcks)
+	}
+	if e := stats.Endpoints[1]; e.Submitted != 4 || e.AlreadyKnown != 4 || e.Accepted != 0 {
+		t.Errorf("endpoint b = %+v", e)
+	}
+	if e := stats.Endpoints[2]; e.Failed != 4 || e.FirstAck != 0 {
+		t.Errorf("endpoint c = %+v", e)
+	}
+}
+
+func TestRedundant_AllFail(t *testing.T) {
+	a := &redundantMockClient{callErr: errors.New("connection refused")}
+	b := &redundantMockClient{err: errors.New("nonce too low")}
+	r, _ := NewRedundant([]RedundantEndpoint{{Name: "a", Client: a}, {Name: "b", Client: b}}, 2)
+
+	// One endpoint answered, so the tx fails alone instead of the call
+	hashes, err := r.BatchSendRawTransactions(context.Background(), [][]byte{{0x01}})
+	if err != nil || len(hashes) != 1 || hashes[0] != (common.Hash{}) {
+		t.Errorf("BatchSendRawTransactions() = %v, %v, want a zero hash for the refused tx", hashes, err)
+	}
+
+	b.err, b.callErr = nil, errors.New("timeout")
+	if _, err := r.BatchSendRawTransactions(context.Background(), [][]byte{{0x01}}); err == nil {
+		t.Error("BatchSendRawTransactions() should fail when every endpoint fails")
+	}
+	if stats := r.Stats(); stats.AckedByNone != 2 {
+		t.Errorf("AckedByNone = %d, want 2", stats.AckedByNone)
+	}
+}
+
+// stepHeads advances the head by one on every poll after the first
+type stepHeads struct {
+	mu   sync.Mutex
+	head uint64
+	jump uint64 // Extra heads added on each advance
+}
+
+func (h *stepHeads) BlockNumber(ctx context.Context) (uint64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := h.head
+	h.head += 1 + h.jump
+	return n, nil
+}
+
+func TestBatcher_SendPaced(t *testing.T) {
+	client := &mockBatchClient{}
+	cfg := &Config{BatchSize: 2, MaxConcurrent: 2, Timeout: 5 * time.Second}
+	batcher := mustNewBatcher(t, client, cfg)
+
+	heads := &stepHeads{head: 100}
+	paced, err := batcher.SendPaced(context.Background(), heads, createTestTxs(10), &PacerConfig{TxsPerBlock: 4, PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("SendPaced() error = %v", err)
+	}
+
+	if paced.SuccessCount != 10 || paced.TotalBatches != 5 {
+		t.Errorf("summary = %d sent in %d batches, want 10 in 5", paced.SuccessCount, paced.TotalBatches)
+	}
+	if len(paced.Tranches) != 3 {
+		t.Fatalf("tranches = %d, want 3", len(paced.Tranches))
+	}
+	for i, tr := range paced.Tranches {
+		if tr.AfterBlock != uint64(101+i) || tr.MissedHeads != 0 {
+			t.Errorf("tranche %d after block %d (%d missed), want block %d", i, tr.AfterBlock, tr.MissedHeads, 101+i)
+		}
+	}
+	if last := paced.Tranches[2]; last.Txs != 2 || last.Sent != 2 {
+		t.Errorf("last tranche = %+v, want the 2 remaining txs", last)
+	}
+	for i, br := range paced.BatchResults {
+		if br.BatchIndex != i {
+			t.Errorf("batch %d has index %d", i, br.BatchIndex)
+		}
+	}
+}
+
+func TestBatcher_SendPaced_MissedHeads(t *testing.T) {
+	batcher := mustNewBatcher(t, &mockBatchClient{}, &Config{BatchSize: 10, MaxConcurrent: 1, Timeout: time.Second})
+	heads := &stepHeads{head: 1, jump: 2}
+
+	paced, err := batcher.SendPaced(context.Background(), heads, createTestTxs(4), &PacerConfig{TxsPerBlock: 2, PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("SendPaced() error = %v", err)
+	}
+	if len(paced.Tranches) != 2 || paced.Tranches[1].MissedHeads != 2 {
+		t.Errorf("tranches = %+v, want 2 heads missed before the second", paced.Tranches)
+	}
+
+	if _, err := batcher.SendPaced(context.Background(), heads, createTestTxs(1), &PacerConfig{}); err == nil {
+		t.Error("SendPaced() without txs per block should fail")
+	}
+}
@@ -0,0 +1,182 @@
+package batcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/progress"
+)
+
+// HeadClient reports the chain head a paced send follows
+type HeadClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// PacerConfig configures a block-paced send
+type PacerConfig struct {
+	// TxsPerBlock is the number of transactions released after each new head
+	TxsPerBlock int
+
+	// PollInterval is how often the head is polled
+	PollInterval time.Duration
+}
+
+// DefaultPollInterval is how often a paced send polls the head by default
+const DefaultPollInterval = 200 * time.Millisecond
+
+// Tranche is the transactions released after one new head
+type Tranche struct {
+	Index       int
+	AfterBlock  uint64    // Head the tranche was released after
+	MissedHeads uint64    // Heads passed while the previous tranche was still sending
+	ReleasedAt  time.Time // When the new head was seen
+	Txs         int
+	Sent        int
+	Failed      int
+	Duration    time.Duration // Time to send the whole tranche
+}
+
+// PacedSummary is the outcome of a block-paced send
+type PacedSummary struct {
+	*Summary
+	Tranches []*Tranche
+}
+
+// SendPaced sends txs in tranches of TxsPerBlock, releasing each tranche
+// right after a new head, so every block has roughly the same load to
+// include. A tranche is sent like SendAll sends its transactions, in
+// concurrent batches. When sending a tranche outlasts a block, the next
+// tranche goes out as soon as it is done, and the heads in between count as
+// missed.
+func (b *Batcher) SendPaced(ctx context.Context, heads HeadClient, txs []*txbuilder.SignedTx, cfg *PacerConfig) (*PacedSummary, error) {
+	if len(txs) == 0 {
+		return &PacedSummary{Summary: &Summary{}}, nil
+	}
+	if cfg == nil || cfg.TxsPerBlock <= 0 {
+		return nil, fmt.Errorf("txs per block must be positive")
+	}
+	pollInterval := cfg.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	trancheCount := (len(txs) + cfg.TxsPerBlock - 1) / cfg.TxsPerBlock
+	console.Printf("\nStarting Block-Paced Transaction Sending\n\n")
+	console.Printf("Total transactions: %d\n", len(txs))
+	console.Printf("Txs per block: %d\n", cfg.TxsPerBlock)
+	console.Printf("Tranches: %d\n", trancheCount)
+	console.Printf("Batch size: %d\n", b.config.BatchSize)
+	console.Printf("Max concurrent: %d\n", b.config.MaxConcurrent)
+	console.Println()
+
+	head, err := heads.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	startTime := time.Now()
+
+	ticker := progress.NewTicker(progress.New(int64(len(txs)), "pacing txs"), progressInterval)
+	defer ticker.Stop()
+
+	result := &PacedSummary{Tranches: make([]*Tranche, 0, trancheCount)}
+	var batchResults []*BatchResult
+
+	for start := 0; start < len(txs); start += cfg.TxsPerBlock {
+		next, err := waitNewHead(ctx, heads, head, pollInterval)
+		if err != nil {
+			ticker.Stop()
+			console.Println()
+			result.Summary = b.buildSummary(batchResults, time.Since(startTime))
+			return result, err
+		}
+
+		end := min(start+cfg.TxsPerBlock, len(txs))
+		tranche := &Tranche{
+			Index:       len(result.Tranches),
+			AfterBlock:  next,
+			MissedHeads: next - head - 1,
+			ReleasedAt:  time.Now(),
+			Txs:         end - start,
+		}
+		head = next
+
+		trancheResults := b.sendTranche(ctx, txs[start:end], len(batchResults))
+		tranche.Duration = time.Since(tranche.ReleasedAt)
+		for _, br := range trancheResults {
+			tranche.Sent += br.SuccessCount
+			tranche.Failed += br.FailedCount + br.ExpiredCount
+		}
+		batchResults = append(batchResults, trancheResults...)
+		result.Tranches = append(result.Tranches, tranche)
+		ticker.Add(tranche.Txs)
+	}
+
+	ticker.Stop()
+	console.Println()
+
+	result.Summary = b.buildSummary(batchResults, time.Since(startTime))
+	b.printSummary(result.Summary)
+	printTranches(result.Tranches)
+	return result, nil
+}
+
+// sendTranche sends the txs of one tranche in concurrent batches, numbering
+// the batches from firstBatch
+func (b *Batcher) sendTranche(ctx context.Context, txs []*txbuilder.SignedTx, firstBatch int) []*BatchResult {
+	batches := b.splitIntoBatches(txs)
+	results := make([]*BatchResult, len(batches))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, b.config.MaxConcurrent)
+	for i, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, batch []*txbuilder.SignedTx) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = b.sendBatch(ctx, b.prepareBatch(firstBatch+i, batch))
+		}(i, batch)
+	}
+	wg.Wait()
+	return results
+}
+
+// waitNewHead polls until the head moves past head and returns the new head
+func waitNewHead(ctx context.Context, heads HeadClient, head uint64, interval time.Duration) (uint64, error) {
+	for {
+		latest, err := heads.BlockNumber(ctx)
+		if err == nil && latest > head {
+			return latest, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// printTranches summarizes how well the tranches kept up with the chain
+func printTranches(tranches []*Tranche) {
+	if len(tranches) == 0 {
+		return
+	}
+	var missed uint64
+	var slowest time.Duration
+	for _, t := range tranches {
+		missed += t.MissedHeads
+		slowest = max(slowest, t.Duration)
+	}
+	console.Printf("Released %d tranches after blocks #%d to #%d\n",
+		len(tranches), tranches[0].AfterBlock, tranches[len(tranches)-1].AfterBlock)
+	console.Printf("Slowest tranche: %s\n", slowest)
+	if missed > 0 {
+		console.Warnf("Missed heads: %d (a tranche took longer than a block to send)\n", missed)
+	}
+}
@@ -0,0 +1,256 @@
+package batcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xmhha/txhammer/internal/client"
+)
+
+// RedundantClient is the RPC client of one endpoint of a Redundant sender
+type RedundantClient interface {
+	BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error
+}
+
+// RedundantEndpoint is an endpoint a Redundant sender submits to
+type RedundantEndpoint struct {
+	Name   string // Label used in the statistics
+	Client RedundantClient
+}
+
+// EndpointStats counts the submissions to one endpoint
+type EndpointStats struct {
+	Name         string `json:"name"`
+	Submitted    int    `json:"submitted"`
+	Accepted     int    `json:"accepted"`
+	AlreadyKnown int    `json:"already_known"` // Rejected because the node already had the tx
+	Failed       int    `json:"failed"`
+	FirstAck     int    `json:"first_ack"` // Txs this endpoint acknowledged before the others
+}
+
+// RedundancyStats summarizes redundant submission. A tx counts as
+// acknowledged by an endpoint that accepted it or already knew it.
+type RedundancyStats struct {
+	Copies        int              `json:"copies"` // Endpoints each tx was submitted to
+	Txs           int              `json:"txs"`
+	AckedByAll    int              `json:"acked_by_all"`
+	AckedBySome   int              `json:"acked_by_some"` // By at least one endpoint, but not all
+	AckedByNone   int              `json:"acked_by_none"`
+	DuplicateAcks int              `json:"duplicate_acks"` // Acknowledgements beyond the first of each tx
+	MeanFirstAck  string           `json:"mean_first_ack"` // Go duration to the first acknowledgement
+	MeanLastAck   string           `json:"mean_last_ack"`  // Go duration to the last acknowledgement
+	Endpoints     []*EndpointStats `json:"endpoints"`
+}
+
+// Redundant submits every transaction to several endpoints at once and
+// returns its hash, deduplicated, once every endpoint has answered.
+// Consecutive sends start at consecutive endpoints, so with more endpoints
+// than copies the load rotates over all of them. It implements Client,
+// EncodedClient and StreamClient.
+type Redundant struct {
+	endpoints []RedundantEndpoint
+	copies    int
+	next      atomic.Uint64
+
+	mu       sync.Mutex
+	stats    RedundancyStats
+	firstAck time.Duration // Sum over acknowledged txs
+	lastAck  time.Duration
+}
+
+// NewRedundant creates a sender that submits each tx to copies of endpoints
+func NewRedundant(endpoints []RedundantEndpoint, copies int) (*Redundant, error) {
+	if copies < 1 || copies > len(endpoints) {
+		return nil, fmt.Errorf("redundancy of %d needs at least as many endpoints, got %d", copies, len(endpoints))
+	}
+	r := &Redundant{
+		endpoints: endpoints,
+		copies:    copies,
+	}
+	r.stats.Copies = copies
+	r.stats.Endpoints = make([]*EndpointStats, len(endpoints))
+	for i, e := range endpoints {
+		r.stats.Endpoints[i] = &EndpointStats{Name: e.Name}
+	}
+	return r, nil
+}
+
+// BatchSendRawTransactions submits a batch of raw transactions
+func (r *Redundant) BatchSendRawTransactions(ctx context.Context, rawTxs [][]byte) ([]common.Hash, error) {
+	encoded := make([]string, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		encoded[i] = hexutil.Encode(rawTx)
+	}
+	return r.BatchSendEncodedTransactions(ctx, encoded)
+}
+
+// BatchSendEncodedTransactions submits a batch of hex-encoded raw
+// transactions. A tx no endpoint acknowledged gets a zero hash; the batch
+// fails only if every endpoint failed as a whole.
+func (r *Redundant) BatchSendEncodedTransactions(ctx context.Context, encoded []string) ([]common.Hash, error) {
+	hashes, _, err := r.submit(ctx, encoded)
+	return hashes, err
+}
+
+// SendRawTransaction submits one raw transaction
+func (r *Redundant) SendRawTransaction(ctx context.Context, rawTx []byte) (common.Hash, error) {
+	hashes, errs, err := r.submit(ctx, []string{hexutil.Encode(rawTx)})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if hashes[0] == (common.Hash{}) {
+		return common.Hash{}, errs[0]
+	}
+	return hashes[0], nil
+}
+
+// BatchCall runs other batch calls on the first endpoint
+func (r *Redundant) BatchCall(batch []rpc.BatchElem) error {
+	return r.endpoints[0].Client.BatchCallContext(context.Background(), batch)
+}
+
+// Stats returns the submission statistics so far
+func (r *Redundant) Stats() *RedundancyStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.stats
+	stats.Endpoints = make([]*EndpointStats, len(r.stats.Endpoints))
+	for i, e := range r.stats.Endpoints {
+		copied := *e
+		stats.Endpoints[i] = &copied
+	}
+	if acked := stats.AckedByAll + stats.AckedBySome; acked > 0 {
+		stats.MeanFirstAck = (r.firstAck / time.Duration(acked)).String()
+		stats.MeanLastAck = (r.lastAck / time.Duration(acked)).String()
+	}
+	return &stats
+}
+
+// answer is the reply of one endpoint to a submission
+type answer struct {
+	endpoint int
+	elems    []rpc.BatchElem
+	hashes   []common.Hash
+	err      error
+	elapsed  time.Duration
+}
+
+// submit sends encoded to the next copies endpoints concurrently and merges
+// their answers per tx. It returns the hash and, for unacknowledged txs, the
+// first error of each tx.
+func (r *Redundant) submit(ctx context.Context, encoded []string) ([]common.Hash, []error, error) {
+	first := int(r.next.Add(1)-1) % len(r.endpoints)
+	start := time.Now()
+
+	answers := make([]*answer, r.copies)
+	var wg sync.WaitGroup
+	for j := 0; j < r.copies; j++ {
+		a := &answer{
+			endpoint: (first + j) % len(r.endpoints),
+			elems:    make([]rpc.BatchElem, len(encoded)),
+			hashes:   make([]common.Hash, len(encoded)),
+		}
+		for i, raw := range encoded {
+			a.elems[i] = rpc.BatchElem{
+				Method: "eth_sendRawTransaction",
+				Args:   []interface{}{raw},
+				Result: &a.hashes[i],
+			}
+		}
+		answers[j] = a
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.err = r.endpoints[a.endpoint].Client.BatchCallContext(ctx, a.elems)
+			a.elapsed = time.Since(start)
+		}()
+	}
+	wg.Wait()
+
+	hashes := make([]common.Hash, len(encoded))
+	errs := make([]error, len(encoded))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var callErr error
+	failedCalls := 0
+	for _, a := range answers {
+		r.stats.Endpoints[a.endpoint].Submitted += len(encoded)
+		if a.err != nil {
+			failedCalls++
+			callErr = errors.Join(callErr, a.err)
+		}
+	}
+
+	for i := range encoded {
+		acks := 0
+		var firstAck, lastAck *answer
+		for _, a := range answers {
+			es := r.stats.Endpoints[a.endpoint]
+			err := a.err
+			if err == nil {
+				err = a.elems[i].Error
+			}
+			switch {
+			case err == nil:
+				es.Accepted++
+				if hashes[i] == (common.Hash{}) {
+					hashes[i] = a.hashes[i]
+				}
+			case client.IsAlreadyKnown(err):
+				es.AlreadyKnown++
+			default:
+				es.Failed++
+				if errs[i] == nil {
+					errs[i] = err
+				}
+				continue
+			}
+			acks++
+			if firstAck == nil || a.elapsed < firstAck.elapsed {
+				firstAck = a
+			}
+			if lastAck == nil || a.elapsed > lastAck.elapsed {
+				lastAck = a
+			}
+		}
+
+		r.stats.Txs++
+		switch {
+		case acks == 0:
+			r.stats.AckedByNone++
+			continue
+		case acks == len(answers):
+			r.stats.AckedByAll++
+		default:
+			r.stats.AckedBySome++
+		}
+		r.stats.DuplicateAcks += acks - 1
+		r.stats.Endpoints[firstAck.endpoint].FirstAck++
+		r.firstAck += firstAck.elapsed
+		r.lastAck += lastAck.elapsed
+
+		// Only duplicates were reported; the hash is the tx's own
+		if hashes[i] == (common.Hash{}) {
+			hashes[i] = crypto.Keccak256Hash(hexutil.MustDecode(encoded[i]))
+		}
+		errs[i] = nil
+	}
+
+	if failedCalls == len(answers) {
+		return nil, errs, fmt.Errorf("all %d endpoints failed: %w", len(answers), callErr)
+	}
+	return hashes, errs, nil
+}
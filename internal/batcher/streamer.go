@@ -8,11 +8,12 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/schollz/progressbar/v3"
 	"golang.org/x/time/rate"
 
 	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/progress"
+	"github.com/0xmhha/txhammer/internal/util/units"
 )
 
 // StreamerConfig holds streamer configuration
@@ -28,6 +29,10 @@ type StreamerConfig struct {
 
 	// Timeout per transaction
 	Timeout time.Duration
+
+	// SendDeadline is how long after its rate-limited send slot a transaction
+	// may still be sent; later ones expire unsent (0 = no deadline)
+	SendDeadline time.Duration
 }
 
 // DefaultStreamerConfig returns default streamer configuration
@@ -47,13 +52,22 @@ type StreamClient interface {
 
 // Streamer sends transactions in a streaming fashion with rate limiting
 type Streamer struct {
-	client  StreamClient
-	config  *StreamerConfig
-	limiter *rate.Limiter
+	client    StreamClient
+	config    *StreamerConfig
+	limiter   *rate.Limiter
+	gate      Gate
+	cutoff    Cutoff
+	budget    Budget
+	callbacks *Callbacks
+
+	// Dedicated connections, pinned to workers round-robin (nil = share client)
+	workerConns []StreamClient
 
 	// Metrics
 	sentCount   atomic.Int64
 	failedCount atomic.Int64
+	sendCalls   atomic.Int64
+	sendTime    atomic.Int64 // Total time spent in SendRawTransaction (ns)
 }
 
 // NewStreamer creates a new Streamer instance
@@ -69,15 +83,52 @@ func NewStreamer(client StreamClient, config *StreamerConfig) *Streamer {
 	}
 }
 
+// WithGate sets a gate that is waited on before each transaction is sent
+func (s *Streamer) WithGate(gate Gate) *Streamer {
+	s.gate = gate
+	return s
+}
+
+// WithCutoff sets a cutoff after which no further transaction is sent
+func (s *Streamer) WithCutoff(cutoff Cutoff) *Streamer {
+	s.cutoff = cutoff
+	return s
+}
+
+// WithBudget sets a budget that must admit each transaction before it is sent
+func (s *Streamer) WithBudget(budget Budget) *Streamer {
+	s.budget = budget
+	return s
+}
+
+// WithCallbacks sets the send notification callbacks
+func (s *Streamer) WithCallbacks(callbacks *Callbacks) *Streamer {
+	s.callbacks = callbacks
+	return s
+}
+
+// WithWorkerClients gives the workers dedicated connections. Worker w sends
+// through clients w, w+Workers, w+2*Workers, ... in turn, so each connection
+// is used by exactly one worker.
+func (s *Streamer) WithWorkerClients(clients []StreamClient) *Streamer {
+	s.workerConns = clients
+	return s
+}
+
 // StreamResult represents the result of streaming operation
 type StreamResult struct {
 	TotalTxs      int
 	SuccessCount  int
 	FailedCount   int
+	ExpiredCount  int // Transactions dropped unsent at the send deadline
 	TotalDuration time.Duration
 	TxPerSecond   float64
+	Connections   int           // RPC connections used (1 = shared client)
+	AvgSendTime   time.Duration // Mean SendRawTransaction round trip
+	TotalBytes    int64         // Raw bytes of the successfully sent transactions
+	BytesPerSec   float64       // TotalBytes over TotalDuration
 	Results       []*TxResult
-	FailedTxs     []*TxResult
+	FailedTxs     []*TxResult // Failed and expired transactions
 }
 
 // Stream sends all transactions with rate limiting
@@ -86,44 +137,90 @@ func (s *Streamer) Stream(ctx context.Context, txs []*txbuilder.SignedTx) (*Stre
 		return &StreamResult{}, nil
 	}
 
-	fmt.Printf("\nStarting Streaming Transaction Sending\n\n")
-	fmt.Printf("Total transactions: %d\n", len(txs))
-	fmt.Printf("Rate limit: %.0f tx/s\n", s.config.Rate)
-	fmt.Printf("Workers: %d\n", s.config.Workers)
-	fmt.Printf("Burst: %d\n\n", s.config.Burst)
+	console.Printf("\nStarting Streaming Transaction Sending\n\n")
+	console.Printf("Total transactions: %d\n", len(txs))
+	console.Printf("Rate limit: %.0f tx/s\n", s.config.Rate)
+	console.Printf("Workers: %d\n", s.config.Workers)
+	console.Printf("Connections: %d\n", s.connections())
+	console.Printf("Burst: %d\n", s.config.Burst)
+	if s.config.SendDeadline > 0 {
+		console.Printf("Send deadline: %s\n", s.config.SendDeadline)
+	}
+	console.Println()
 
 	startTime := time.Now()
 
-	// Create progress bar
-	bar := progressbar.Default(int64(len(txs)), "streaming txs")
+	// Progress is counted atomically and redrawn off the send path
+	ticker := progress.NewTicker(progress.New(int64(len(txs)), "streaming txs"), progressInterval)
 
-	// Create result channels
+	// A fixed pool of workers, each sending through its own connections
 	results := make([]*TxResult, len(txs))
+	jobs := make(chan int)
 	var wg sync.WaitGroup
-	sem := make(chan struct{}, s.config.Workers)
+	for w := 0; w < max(s.config.Workers, 1); w++ {
+		wg.Add(1)
+		go func(clients []StreamClient) {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				idx, ok := <-jobs
+				if !ok {
+					return
+				}
+				results[idx] = s.sendSingle(ctx, clients[n%len(clients)], txs[idx])
+				ticker.Add(1)
+			}
+		}(s.workerClients(w))
+	}
 
+	var sendErr error
 	for i, tx := range txs {
+		// Pause while the gate is closed
+		if s.gate != nil {
+			if err := s.gate.Wait(ctx); err != nil {
+				sendErr = fmt.Errorf("send gate error: %w", err)
+				break
+			}
+		}
+
 		// Wait for rate limiter
 		if err := s.limiter.Wait(ctx); err != nil {
-			return nil, fmt.Errorf("rate limiter error: %w", err)
+			sendErr = fmt.Errorf("rate limiter error: %w", err)
+			break
 		}
 
-		wg.Add(1)
-		go func(idx int, signedTx *txbuilder.SignedTx) {
-			defer wg.Done()
+		// Drop the tx if stalls left it too far behind its send slot
+		if s.expired(startTime, i) {
+			results[i] = &TxResult{Tx: tx, Status: TxStatusExpired, Error: ErrExpired}
+			ticker.Add(1)
+			continue
+		}
 
-			sem <- struct{}{}
-			defer func() { <-sem }()
+		// Stop sending once the cutoff trips; the rest fail unsent
+		if s.cutoff != nil {
+			if err := s.cutoff.Err(); err != nil {
+				s.failRest(results, txs, i, err)
+				break
+			}
+		}
 
-			result := s.sendSingle(ctx, signedTx)
-			results[idx] = result
+		// Stop sending once the budget refuses; the rest fail unsent
+		if s.budget != nil {
+			if err := s.budget.Reserve(tx.Tx); err != nil {
+				s.failRest(results, txs, i, err)
+				break
+			}
+		}
 
-			progress.Add(bar, 1)
-		}(i, tx)
+		jobs <- i
 	}
+	close(jobs)
 
 	wg.Wait()
-	fmt.Println()
+	ticker.Stop()
+	if sendErr != nil {
+		return nil, sendErr
+	}
+	console.Println()
 
 	// Build result
 	totalDuration := time.Since(startTime)
@@ -135,8 +232,35 @@ func (s *Streamer) Stream(ctx context.Context, txs []*txbuilder.SignedTx) (*Stre
 	return streamResult, nil
 }
 
-// sendSingle sends a single transaction
-func (s *Streamer) sendSingle(ctx context.Context, tx *txbuilder.SignedTx) *TxResult {
+// expired reports whether the send deadline of the i-th transaction has
+// passed, counted from the slot the rate limit schedules it in
+func (s *Streamer) expired(start time.Time, i int) bool {
+	if s.config.SendDeadline <= 0 {
+		return false
+	}
+	slot := start
+	if s.config.Rate > 0 {
+		slot = start.Add(time.Duration(float64(i) / s.config.Rate * float64(time.Second)))
+	}
+	return expired(slot.Add(s.config.SendDeadline))
+}
+
+// workerClients returns the connections pinned to a worker: every
+// Workers-th dedicated client starting at the worker's index, or the shared
+// client when no dedicated clients are set
+func (s *Streamer) workerClients(worker int) []StreamClient {
+	var clients []StreamClient
+	for i := worker; i < len(s.workerConns); i += max(s.config.Workers, 1) {
+		clients = append(clients, s.workerConns[i])
+	}
+	if len(clients) == 0 {
+		clients = []StreamClient{s.client}
+	}
+	return clients
+}
+
+// sendSingle sends a single transaction through the given connection
+func (s *Streamer) sendSingle(ctx context.Context, client StreamClient, tx *txbuilder.SignedTx) *TxResult {
 	result := &TxResult{
 		Tx:     tx,
 		Status: TxStatusPending,
@@ -146,22 +270,46 @@ func (s *Streamer) sendSingle(ctx context.Context, tx *txbuilder.SignedTx) *TxRe
 	sendCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
 	defer cancel()
 
-	hash, err := s.client.SendRawTransaction(sendCtx, tx.RawTx)
+	start := time.Now()
+	hash, err := client.SendRawTransaction(sendCtx, tx.RawTx)
 	result.SentAt = time.Now()
+	s.sendCalls.Add(1)
+	s.sendTime.Add(int64(result.SentAt.Sub(start)))
 
 	if err != nil {
+		if s.budget != nil {
+			s.budget.Release(tx.Tx)
+		}
 		result.Status = TxStatusFailed
 		result.Error = err
 		s.failedCount.Add(1)
+		if s.callbacks != nil && s.callbacks.OnFailed != nil {
+			s.callbacks.OnFailed(result)
+		}
 	} else {
 		result.Hash = hash
 		result.Status = TxStatusSent
 		s.sentCount.Add(1)
+		if s.callbacks != nil && s.callbacks.OnSent != nil {
+			s.callbacks.OnSent(result)
+		}
 	}
 
 	return result
 }
 
+// failRest fails the transactions from index from on without sending them
+// and reports each to the OnFailed callback
+func (s *Streamer) failRest(results []*TxResult, txs []*txbuilder.SignedTx, from int, err error) {
+	for j := from; j < len(txs); j++ {
+		results[j] = &TxResult{Tx: txs[j], Status: TxStatusFailed, Error: err}
+		s.failedCount.Add(1)
+		if s.callbacks != nil && s.callbacks.OnFailed != nil {
+			s.callbacks.OnFailed(results[j])
+		}
+	}
+}
+
 // buildResult builds the stream result
 func (s *Streamer) buildResult(results []*TxResult, duration time.Duration) *StreamResult {
 	sr := &StreamResult{
@@ -172,16 +320,26 @@ func (s *Streamer) buildResult(results []*TxResult, duration time.Duration) *Str
 	}
 
 	for _, r := range results {
-		if r.Status == TxStatusFailed {
+		switch r.Status {
+		case TxStatusFailed:
 			sr.FailedCount++
 			sr.FailedTxs = append(sr.FailedTxs, r)
-		} else {
+		case TxStatusExpired:
+			sr.ExpiredCount++
+			sr.FailedTxs = append(sr.FailedTxs, r)
+		default:
 			sr.SuccessCount++
+			sr.TotalBytes += int64(len(r.Tx.RawTx))
 		}
 	}
 
 	if duration.Seconds() > 0 {
 		sr.TxPerSecond = float64(sr.SuccessCount) / duration.Seconds()
+		sr.BytesPerSec = float64(sr.TotalBytes) / duration.Seconds()
+	}
+	sr.Connections = s.connections()
+	if calls := s.sendCalls.Load(); calls > 0 {
+		sr.AvgSendTime = time.Duration(s.sendTime.Load() / calls)
 	}
 
 	return sr
@@ -189,27 +347,34 @@ func (s *Streamer) buildResult(results []*TxResult, duration time.Duration) *Str
 
 // printSummary prints the streaming summary
 func (s *Streamer) printSummary(result *StreamResult) {
-	fmt.Printf("\nStreaming Summary\n\n")
-	fmt.Printf("Total transactions: %d\n", result.TotalTxs)
-	fmt.Printf("Successful: %d (%.2f%%)\n", result.SuccessCount,
+	console.Printf("\nStreaming Summary\n\n")
+	console.Printf("Total transactions: %d\n", result.TotalTxs)
+	console.Printf("Successful: %d (%.2f%%)\n", result.SuccessCount,
 		float64(result.SuccessCount)/float64(result.TotalTxs)*100)
-	fmt.Printf("Failed: %d (%.2f%%)\n", result.FailedCount,
+	console.Printf("Failed: %d (%.2f%%)\n", result.FailedCount,
 		float64(result.FailedCount)/float64(result.TotalTxs)*100)
-	fmt.Printf("Total duration: %s\n", result.TotalDuration)
-	fmt.Printf("Actual throughput: %.2f tx/s\n", result.TxPerSecond)
+	if result.ExpiredCount > 0 {
+		console.Printf("Expired before send: %d (%.2f%%)\n", result.ExpiredCount,
+			float64(result.ExpiredCount)/float64(result.TotalTxs)*100)
+	}
+	console.Printf("Total duration: %s\n", result.TotalDuration)
+	console.Printf("Actual throughput: %.2f tx/s\n", result.TxPerSecond)
+	console.Printf("Bandwidth: %s sent (%s/s)\n", units.FormatBytes(float64(result.TotalBytes)), units.FormatBytes(result.BytesPerSec))
+	console.Printf("Connections: %d (%.2f tx/s each)\n", result.Connections, result.TxPerSecond/float64(result.Connections))
+	console.Printf("Avg send round trip: %s\n", result.AvgSendTime)
 
 	if len(result.FailedTxs) > 0 {
-		fmt.Printf("\n[WARN] Failed Transactions: %d\n", len(result.FailedTxs))
+		console.Warnf("\nFailed Transactions: %d\n", len(result.FailedTxs))
 		showCount := 5
 		if len(result.FailedTxs) < showCount {
 			showCount = len(result.FailedTxs)
 		}
 		for i := 0; i < showCount; i++ {
 			ft := result.FailedTxs[i]
-			fmt.Printf("  - From: %s, Error: %v\n", ft.Tx.From.Hex()[:10], ft.Error)
+			console.Summaryf("  - From: %s, Error: %v\n", ft.Tx.From.Hex()[:10], ft.Error)
 		}
 		if len(result.FailedTxs) > showCount {
-			fmt.Printf("  ... and %d more\n", len(result.FailedTxs)-showCount)
+			console.Summaryf("  ... and %d more\n", len(result.FailedTxs)-showCount)
 		}
 	}
 }
@@ -228,4 +393,14 @@ func (s *Streamer) GetFailedCount() int64 {
 func (s *Streamer) Reset() {
 	s.sentCount.Store(0)
 	s.failedCount.Store(0)
+	s.sendCalls.Store(0)
+	s.sendTime.Store(0)
+}
+
+// connections returns the number of RPC connections the workers send through
+func (s *Streamer) connections() int {
+	if len(s.workerConns) == 0 {
+		return 1
+	}
+	return len(s.workerConns)
 }
@@ -1,13 +1,61 @@
 package batcher
 
 import (
+	"context"
+	"errors"
+	"runtime"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 
+	"github.com/0xmhha/txhammer/internal/budget"
 	"github.com/0xmhha/txhammer/internal/txbuilder"
 )
 
+// ErrExpired marks transactions dropped because they were not accepted
+// before their send deadline
+var ErrExpired = errors.New("expired before send")
+
+// Gate pauses sending while closed (e.g. while the chain is halted)
+type Gate interface {
+	// Wait blocks until sending may proceed
+	Wait(ctx context.Context) error
+}
+
+// Cutoff ends sending for good once it trips (e.g. at a block height)
+type Cutoff interface {
+	// Err returns why sending must stop, or nil while it may go on
+	Err() error
+}
+
+// Budget admits transactions while spending stays within a limit
+type Budget interface {
+	// Reserve sets aside the max fee of the transactions, or fails if that would exceed the limit
+	Reserve(txs ...*types.Transaction) error
+	// Release returns the reservation of transactions that were not sent
+	Release(txs ...*types.Transaction)
+	// Stopped returns where the budget first refused transactions (nil if not yet)
+	Stopped() *budget.Stop
+}
+
+// EncodedClient is implemented by clients that can send transactions already
+// hex-encoded for eth_sendRawTransaction. The batcher then encodes batches on
+// its serialization workers, off the sending path.
+type EncodedClient interface {
+	BatchSendEncodedTransactions(ctx context.Context, encoded []string) ([]common.Hash, error)
+}
+
+// Callbacks for send notifications
+type Callbacks struct {
+	// OnSent is called for every transaction the node acknowledged; it must be safe for concurrent use
+	OnSent func(result *TxResult)
+
+	// OnFailed is called for every transaction the node refused or that could
+	// not be delivered; it must be safe for concurrent use
+	OnFailed func(result *TxResult)
+}
+
 // TxStatus represents the status of a transaction
 type TxStatus int
 
@@ -16,6 +64,7 @@ const (
 	TxStatusSent
 	TxStatusConfirmed
 	TxStatusFailed
+	TxStatusExpired
 )
 
 func (s TxStatus) String() string {
@@ -28,6 +77,8 @@ func (s TxStatus) String() string {
 		return "CONFIRMED"
 	case TxStatusFailed:
 		return "FAILED"
+	case TxStatusExpired:
+		return "EXPIRED"
 	default:
 		return "UNKNOWN"
 	}
@@ -49,6 +100,7 @@ type BatchResult struct {
 	TxCount      int
 	SuccessCount int
 	FailedCount  int
+	ExpiredCount int // Transactions dropped unsent at the send deadline
 	StartTime    time.Time
 	EndTime      time.Time
 	Duration     time.Duration
@@ -62,11 +114,14 @@ type Summary struct {
 	TotalTxs      int
 	SuccessCount  int
 	FailedCount   int
+	ExpiredCount  int // Transactions dropped unsent at the send deadline
 	TotalDuration time.Duration
 	AvgBatchTime  time.Duration
 	TxPerSecond   float64
+	TotalBytes    int64   // Raw bytes of the successfully sent transactions
+	BytesPerSec   float64 // TotalBytes over TotalDuration
 	BatchResults  []*BatchResult
-	FailedTxs     []*TxResult
+	FailedTxs     []*TxResult // Failed and expired transactions
 }
 
 // Config holds batcher configuration
@@ -77,6 +132,10 @@ type Config struct {
 	// MaxConcurrent is the max concurrent batch requests
 	MaxConcurrent int
 
+	// EncodeWorkers is the number of workers serializing batches ahead of
+	// the senders (0 = GOMAXPROCS)
+	EncodeWorkers int
+
 	// BatchInterval is the delay between batches
 	BatchInterval time.Duration
 
@@ -88,6 +147,10 @@ type Config struct {
 
 	// Timeout is the timeout for batch operations
 	Timeout time.Duration
+
+	// SendDeadline is how long after its batch is ready to send a transaction
+	// may still be sent; batches not accepted by then expire unsent (0 = no deadline)
+	SendDeadline time.Duration
 }
 
 // DefaultConfig returns default batcher configuration
@@ -110,6 +173,9 @@ func (c *Config) Validate() error {
 	if c.MaxConcurrent <= 0 {
 		c.MaxConcurrent = 5
 	}
+	if c.EncodeWorkers <= 0 {
+		c.EncodeWorkers = runtime.GOMAXPROCS(0)
+	}
 	if c.BatchInterval < 0 {
 		c.BatchInterval = 100 * time.Millisecond
 	}
@@ -119,5 +185,8 @@ func (c *Config) Validate() error {
 	if c.Timeout <= 0 {
 		c.Timeout = 30 * time.Second
 	}
+	if c.SendDeadline < 0 {
+		c.SendDeadline = 0
+	}
 	return nil
 }
@@ -0,0 +1,126 @@
+package bench
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// AccountConfig holds configuration for a sub-account count sweep
+type AccountConfig struct {
+	Counts   []int   // Sub-account counts to try
+	TotalTxs int     // Transactions sent per trial, split across the sub-accounts
+	MinGain  float64 // Smallest chain TPS gain (percent) that still counts as scaling
+}
+
+// DefaultAccountConfig returns default account sweep configuration
+func DefaultAccountConfig() *AccountConfig {
+	return &AccountConfig{
+		Counts:   []int{10, 50, 200, 1000},
+		TotalTxs: 5000,
+		MinGain:  10,
+	}
+}
+
+// Validate validates the configuration and sorts the counts ascending
+func (c *AccountConfig) Validate() error {
+	if len(c.Counts) == 0 {
+		return fmt.Errorf("at least one sub-account count is required")
+	}
+	for _, n := range c.Counts {
+		if n <= 0 {
+			return fmt.Errorf("sub-account counts must be greater than 0")
+		}
+	}
+	if c.TotalTxs <= 0 {
+		return fmt.Errorf("total-txs must be greater than 0")
+	}
+	if c.MinGain < 0 {
+		return fmt.Errorf("min-gain must not be negative")
+	}
+	slices.Sort(c.Counts)
+	c.Counts = slices.Compact(c.Counts)
+	return nil
+}
+
+// MaxCount returns the largest sub-account count in the sweep
+func (c *AccountConfig) MaxCount() int {
+	return slices.Max(c.Counts)
+}
+
+// TxsPerAccount returns how many transactions a sub-account sends across the
+// whole sweep; the first accounts take part in every trial
+func (c *AccountConfig) TxsPerAccount() int {
+	total := 0
+	for _, n := range c.Counts {
+		total += (c.TotalTxs + n - 1) / n
+	}
+	return total
+}
+
+// AccountTrial holds the measured outcome of one sub-account count
+type AccountTrial struct {
+	SubAccounts int
+	Sent        int
+	Confirmed   int
+	SendTPS     float64       // Transactions accepted by the node per second
+	ChainTPS    float64       // Confirmed transactions per second of block time
+	AvgLatency  time.Duration // Average confirmation latency
+	Gain        float64       // Chain TPS change versus the previous trial (percent)
+	Err         error         // Set if the trial could not run
+}
+
+// AccountResult holds the outcome of an account sweep
+type AccountResult struct {
+	Trials []*AccountTrial
+
+	// Saturation is the smallest count after which adding sub-accounts no longer
+	// raises chain TPS by MinGain; nil if throughput kept scaling
+	Saturation *AccountTrial
+}
+
+// Analyze computes the gain of each trial over the previous one and finds the
+// point where sender parallelism stops improving chain throughput
+func Analyze(trials []*AccountTrial, minGain float64) *AccountTrial {
+	var prev, saturation *AccountTrial
+	for _, t := range trials {
+		if t.Err != nil || t.ChainTPS <= 0 {
+			continue
+		}
+		if prev != nil {
+			t.Gain = (t.ChainTPS - prev.ChainTPS) / prev.ChainTPS * 100
+			if t.Gain < minGain && saturation == nil {
+				saturation = prev
+			}
+		}
+		prev = t
+	}
+	return saturation
+}
+
+// PrintAccountTable prints the account sweep results and saturation point
+func PrintAccountTable(result *AccountResult) {
+	console.Summaryf("\nSub-Account Sweep Results\n\n")
+	console.Summaryf("  %-9s %8s %10s %10s %10s %12s %8s\n", "Accounts", "Sent", "Confirmed", "Send Tx/s", "Chain TPS", "Avg Latency", "Gain")
+	for i, t := range result.Trials {
+		if t.Err != nil {
+			console.Summaryf("  %-9d %s\n", t.SubAccounts, t.Err)
+			continue
+		}
+		gain := "-"
+		if i > 0 && t.ChainTPS > 0 {
+			gain = fmt.Sprintf("%+.1f%%", t.Gain)
+		}
+		console.Summaryf("  %-9d %8d %10d %10.2f %10.2f %12s %8s\n",
+			t.SubAccounts, t.Sent, t.Confirmed, t.SendTPS, t.ChainTPS, t.AvgLatency.Round(time.Millisecond), gain)
+	}
+
+	if result.Saturation == nil {
+		console.Summaryf("\n%s Chain TPS kept scaling with sender parallelism; nonce serialization is still the bottleneck at the largest count\n", console.Marker("[OK]"))
+		return
+	}
+	console.Summaryf("\n%s Scaling flattens beyond %d sub-accounts (%.2f chain TPS); nonce serialization is no longer the bottleneck\n", console.Marker("[OK]"),
+		result.Saturation.SubAccounts, result.Saturation.ChainTPS)
+}
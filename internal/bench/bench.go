@@ -0,0 +1,145 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Config holds configuration for a batch-size sweep
+type Config struct {
+	BatchSizes   []int   // Batch sizes to try
+	Concurrency  []int   // MaxConcurrent values to try
+	TxsPerTrial  int     // Transactions sent per setting
+	MaxErrorRate float64 // Highest error rate (percent) a setting may have to be recommended
+}
+
+// DefaultConfig returns default sweep configuration
+func DefaultConfig() *Config {
+	return &Config{
+		BatchSizes:   []int{50, 100, 200, 500},
+		Concurrency:  []int{10, 50, 100},
+		TxsPerTrial:  2000,
+		MaxErrorRate: 1,
+	}
+}
+
+// Validate validates the configuration
+func (c *Config) Validate() error {
+	if len(c.BatchSizes) == 0 || len(c.Concurrency) == 0 {
+		return fmt.Errorf("at least one batch size and concurrency level are required")
+	}
+	for _, v := range c.BatchSizes {
+		if v <= 0 {
+			return fmt.Errorf("batch sizes must be greater than 0")
+		}
+	}
+	for _, v := range c.Concurrency {
+		if v <= 0 {
+			return fmt.Errorf("concurrency levels must be greater than 0")
+		}
+	}
+	if c.TxsPerTrial <= 0 {
+		return fmt.Errorf("txs-per-trial must be greater than 0")
+	}
+	if c.MaxErrorRate < 0 || c.MaxErrorRate > 100 {
+		return fmt.Errorf("max-error-rate must be between 0 and 100")
+	}
+	return nil
+}
+
+// Setting is a single batch size and concurrency combination
+type Setting struct {
+	BatchSize     int
+	MaxConcurrent int
+}
+
+// Settings returns every combination of the configured batch sizes and concurrency levels
+func (c *Config) Settings() []Setting {
+	settings := make([]Setting, 0, len(c.BatchSizes)*len(c.Concurrency))
+	for _, batchSize := range c.BatchSizes {
+		for _, concurrent := range c.Concurrency {
+			settings = append(settings, Setting{BatchSize: batchSize, MaxConcurrent: concurrent})
+		}
+	}
+	return settings
+}
+
+// Trial holds the measured outcome of one setting
+type Trial struct {
+	Setting
+	Sent        int
+	Failed      int
+	Duration    time.Duration
+	TxPerSecond float64 // Transactions accepted by the node per second
+	ErrorRate   float64 // Percent of transactions rejected
+	Err         error   // Set if the trial could not run
+}
+
+// NewTrial computes the throughput and error rate of a finished trial
+func NewTrial(setting Setting, sent, failed int, duration time.Duration) *Trial {
+	t := &Trial{Setting: setting, Sent: sent, Failed: failed, Duration: duration}
+	if total := sent + failed; total > 0 {
+		t.ErrorRate = float64(failed) / float64(total) * 100
+	}
+	if duration.Seconds() > 0 {
+		t.TxPerSecond = float64(sent) / duration.Seconds()
+	}
+	return t
+}
+
+// Result holds the outcome of a sweep
+type Result struct {
+	Trials      []*Trial
+	Recommended *Trial // Nil if no setting stayed within the error budget
+}
+
+// Recommend returns the trial with the highest throughput whose error rate is within
+// maxErrorRate. Ties prefer the smaller concurrency, then the smaller batch size.
+func Recommend(trials []*Trial, maxErrorRate float64) *Trial {
+	var best *Trial
+	for _, t := range trials {
+		if t.Err != nil || t.Sent == 0 || t.ErrorRate > maxErrorRate {
+			continue
+		}
+		if best == nil || t.TxPerSecond > best.TxPerSecond ||
+			(t.TxPerSecond == best.TxPerSecond && lighter(t.Setting, best.Setting)) {
+			best = t
+		}
+	}
+	return best
+}
+
+// lighter reports whether a puts less load on the node than b
+func lighter(a, b Setting) bool {
+	if a.MaxConcurrent != b.MaxConcurrent {
+		return a.MaxConcurrent < b.MaxConcurrent
+	}
+	return a.BatchSize < b.BatchSize
+}
+
+// PrintTable prints the sweep results and recommendation
+func PrintTable(result *Result) {
+	console.Summaryf("\nBatch Sweep Results\n\n")
+	console.Summaryf("  %-8s %-11s %8s %8s %12s %10s\n", "Batch", "Concurrent", "Sent", "Failed", "Tx/s", "Errors")
+	for _, t := range result.Trials {
+		if t.Err != nil {
+			console.Summaryf("  %-8d %-11d %s\n", t.BatchSize, t.MaxConcurrent, t.Err)
+			continue
+		}
+		marker := ""
+		if t == result.Recommended {
+			marker = "  <- recommended"
+		}
+		console.Summaryf("  %-8d %-11d %8d %8d %12.2f %9.2f%%%s\n",
+			t.BatchSize, t.MaxConcurrent, t.Sent, t.Failed, t.TxPerSecond, t.ErrorRate, marker)
+	}
+
+	if result.Recommended == nil {
+		console.Warnf("\nNo setting stayed within the error budget\n")
+		return
+	}
+	console.Summaryf("\n%s Recommended: --batch %d --max-concurrent %d (%.2f tx/s)\n", console.Marker("[OK]"),
+		result.Recommended.BatchSize, result.Recommended.MaxConcurrent, result.Recommended.TxPerSecond)
+}
@@ -0,0 +1,103 @@
+package bench
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfig_Settings(t *testing.T) {
+	cfg := &Config{BatchSizes: []int{50, 100}, Concurrency: []int{10, 20, 30}, TxsPerTrial: 100}
+	settings := cfg.Settings()
+	if len(settings) != 6 {
+		t.Fatalf("expected 6 settings, got %d", len(settings))
+	}
+	if settings[0] != (Setting{BatchSize: 50, MaxConcurrent: 10}) {
+		t.Errorf("unexpected first setting: %+v", settings[0])
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("default config should be valid: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.BatchSizes = []int{0}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for zero batch size")
+	}
+}
+
+func TestNewTrial(t *testing.T) {
+	trial := NewTrial(Setting{BatchSize: 100, MaxConcurrent: 10}, 990, 10, 2*time.Second)
+	if trial.TxPerSecond != 495 {
+		t.Errorf("TxPerSecond = %v, want 495", trial.TxPerSecond)
+	}
+	if trial.ErrorRate != 1 {
+		t.Errorf("ErrorRate = %v, want 1", trial.ErrorRate)
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	trials := []*Trial{
+		{Setting: Setting{BatchSize: 50, MaxConcurrent: 10}, Sent: 100, TxPerSecond: 500},
+		{Setting: Setting{BatchSize: 500, MaxConcurrent: 100}, Sent: 100, TxPerSecond: 900, ErrorRate: 5},
+		{Setting: Setting{BatchSize: 200, MaxConcurrent: 50}, Sent: 100, TxPerSecond: 800, ErrorRate: 0.5},
+		{Setting: Setting{BatchSize: 100, MaxConcurrent: 20}, Sent: 100, TxPerSecond: 800},
+		{Setting: Setting{BatchSize: 100, MaxConcurrent: 10}, Err: errors.New("build failed")},
+	}
+
+	best := Recommend(trials, 1)
+	if best == nil {
+		t.Fatal("expected a recommendation")
+	}
+	// 900 tx/s exceeds the error budget; the 800 tx/s tie goes to the lower concurrency
+	if best.BatchSize != 100 || best.MaxConcurrent != 20 {
+		t.Errorf("Recommend() = %+v, want batch=100 concurrent=20", best.Setting)
+	}
+
+	if Recommend(trials[1:2], 1) != nil {
+		t.Error("expected no recommendation when every trial exceeds the error budget")
+	}
+}
+
+func TestAccountConfig_Validate(t *testing.T) {
+	cfg := &AccountConfig{Counts: []int{200, 10, 50, 10}, TotalTxs: 1000, MinGain: 10}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(cfg.Counts) != 3 || cfg.Counts[0] != 10 || cfg.Counts[2] != 200 {
+		t.Errorf("Counts = %v, want [10 50 200]", cfg.Counts)
+	}
+	if cfg.MaxCount() != 200 {
+		t.Errorf("MaxCount() = %d, want 200", cfg.MaxCount())
+	}
+	// 1000/10 + 1000/50 + ceil(1000/200)
+	if cfg.TxsPerAccount() != 125 {
+		t.Errorf("TxsPerAccount() = %d, want 125", cfg.TxsPerAccount())
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	trials := []*AccountTrial{
+		{SubAccounts: 10, ChainTPS: 100},
+		{SubAccounts: 50, ChainTPS: 400},
+		{SubAccounts: 200, ChainTPS: 420},
+		{SubAccounts: 1000, ChainTPS: 410},
+	}
+
+	saturation := Analyze(trials, 10)
+	if saturation == nil || saturation.SubAccounts != 50 {
+		t.Fatalf("Analyze() = %+v, want 50 sub-accounts", saturation)
+	}
+	if trials[1].Gain != 300 {
+		t.Errorf("Gain = %v, want 300", trials[1].Gain)
+	}
+	if trials[3].Gain >= 0 {
+		t.Errorf("Gain after saturation = %v, want negative", trials[3].Gain)
+	}
+
+	if Analyze(trials[:2], 10) != nil {
+		t.Error("expected no saturation while throughput keeps scaling")
+	}
+}
@@ -0,0 +1,121 @@
+package blockstop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// ErrReached marks transactions left unsent because the chain reached the stop block
+var ErrReached = errors.New("stop block reached")
+
+// Client defines the interface for observing block progress
+type Client interface {
+	// BlockNumber returns the latest block number
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Config holds configuration for the stopper
+type Config struct {
+	Height       uint64        // Sending stops once the chain head reaches this block
+	PollInterval time.Duration // How often to poll the block number
+}
+
+// Stop records where the chain reached the stop block
+type Stop struct {
+	Height uint64    `json:"height"` // Configured stop block
+	Head   uint64    `json:"head"`   // Chain head when the stop was observed
+	At     time.Time `json:"at"`
+}
+
+// Stopper ends sending once the chain reaches a block height
+type Stopper struct {
+	client Client
+	config *Config
+
+	mu   sync.Mutex
+	stop *Stop
+	done chan struct{}
+}
+
+// New creates a new Stopper instance
+func New(client Client, config *Config) *Stopper {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	return &Stopper{
+		client: client,
+		config: config,
+		done:   make(chan struct{}),
+	}
+}
+
+// Run polls the chain head until the stop block is reached or the context is done
+func (s *Stopper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			head, err := s.client.BlockNumber(ctx)
+			if err != nil {
+				continue
+			}
+			if s.observe(head, time.Now()) {
+				return
+			}
+		}
+	}
+}
+
+// observe records the stop if head reached the stop block and reports whether it has
+func (s *Stopper) observe(head uint64, now time.Time) bool {
+	s.mu.Lock()
+	if s.stop != nil {
+		s.mu.Unlock()
+		return true
+	}
+	if head < s.config.Height {
+		s.mu.Unlock()
+		return false
+	}
+	s.stop = &Stop{Height: s.config.Height, Head: head, At: now}
+	close(s.done)
+	s.mu.Unlock()
+
+	console.Warnf("\nChain reached stop block #%d (head #%d), stopping sending\n", s.config.Height, head)
+	return true
+}
+
+// Err returns an error wrapping ErrReached once the stop block is reached, nil before
+func (s *Stopper) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: #%d", ErrReached, s.config.Height)
+}
+
+// Done returns a channel that is closed once the stop block is reached
+func (s *Stopper) Done() <-chan struct{} {
+	return s.done
+}
+
+// Stopped returns where the stop block was reached (nil if not yet)
+func (s *Stopper) Stopped() *Stop {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return nil
+	}
+	stop := *s.stop
+	return &stop
+}
@@ -0,0 +1,67 @@
+package blockstop
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockClient returns a chain head that advances by one per call
+type mockClient struct {
+	head atomic.Uint64
+}
+
+func (m *mockClient) BlockNumber(context.Context) (uint64, error) {
+	return m.head.Add(1), nil
+}
+
+func TestStopper_Observe(t *testing.T) {
+	s := New(nil, &Config{Height: 100})
+	now := time.Now()
+
+	if s.observe(99, now) {
+		t.Fatal("observe() below the stop block should not stop")
+	}
+	if s.Err() != nil || s.Stopped() != nil {
+		t.Fatal("stopper should be open below the stop block")
+	}
+
+	if !s.observe(102, now) {
+		t.Fatal("observe() past the stop block should stop")
+	}
+	if err := s.Err(); !errors.Is(err, ErrReached) {
+		t.Errorf("Err() = %v, want ErrReached", err)
+	}
+	stop := s.Stopped()
+	if stop == nil || stop.Height != 100 || stop.Head != 102 {
+		t.Errorf("Stopped() = %+v", stop)
+	}
+	select {
+	case <-s.Done():
+	default:
+		t.Error("Done() should be closed after the stop")
+	}
+
+	// Later heads keep the first stop
+	s.observe(110, now)
+	if s.Stopped().Head != 102 {
+		t.Error("a later head should not replace the recorded stop")
+	}
+}
+
+func TestStopper_Run(t *testing.T) {
+	client := &mockClient{}
+	client.head.Store(10)
+	s := New(client, &Config{Height: 13, PollInterval: time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.Run(ctx)
+
+	stop := s.Stopped()
+	if stop == nil || stop.Head != 13 {
+		t.Errorf("Stopped() = %+v, want head 13", stop)
+	}
+}
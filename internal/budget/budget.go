@@ -0,0 +1,148 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrExceeded is returned when admitting more transactions would exceed the spend budget
+var ErrExceeded = errors.New("spend budget exceeded")
+
+// Stop records the point at which the budget first refused transactions
+type Stop struct {
+	At       time.Time `json:"at"`
+	Admitted int       `json:"admitted"` // Transactions admitted before the stop
+	Spent    *big.Int  `json:"spent"`    // Actual fees of confirmed transactions
+	Reserved *big.Int  `json:"reserved"` // Max fees of admitted, unconfirmed transactions
+}
+
+// Budget caps the fees a run may spend. Sent transactions are counted at their
+// max fee until a receipt settles them at their actual cost.
+type Budget struct {
+	limit *big.Int
+
+	mu       sync.Mutex
+	spent    *big.Int
+	reserved *big.Int
+	holds    map[common.Hash]*big.Int
+	admitted int
+	stop     *Stop
+}
+
+// New creates a new Budget with the given limit in wei
+func New(limit *big.Int) *Budget {
+	return &Budget{
+		limit:    new(big.Int).Set(limit),
+		spent:    new(big.Int),
+		reserved: new(big.Int),
+		holds:    make(map[common.Hash]*big.Int),
+	}
+}
+
+// MaxFee returns the most a transaction can cost in fees (gas limit × fee cap)
+func MaxFee(tx *types.Transaction) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
+}
+
+// PlanCost returns the combined max fee of the transactions
+func PlanCost(txs []*types.Transaction) *big.Int {
+	total := new(big.Int)
+	for _, tx := range txs {
+		total.Add(total, MaxFee(tx))
+	}
+	return total
+}
+
+// Reserve sets aside the max fee of all transactions, or none of them if that
+// would exceed the limit. The first refusal is recorded as the stop point.
+func (b *Budget) Reserve(txs ...*types.Transaction) error {
+	cost := PlanCost(txs)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	total := new(big.Int).Add(b.spent, b.reserved)
+	if total.Add(total, cost).Cmp(b.limit) > 0 {
+		if b.stop == nil {
+			b.stop = &Stop{
+				At:       time.Now(),
+				Admitted: b.admitted,
+				Spent:    new(big.Int).Set(b.spent),
+				Reserved: new(big.Int).Set(b.reserved),
+			}
+		}
+		return fmt.Errorf("%w: %s wei limit", ErrExceeded, b.limit)
+	}
+
+	for _, tx := range txs {
+		fee := MaxFee(tx)
+		b.holds[tx.Hash()] = fee
+		b.reserved.Add(b.reserved, fee)
+	}
+	b.admitted += len(txs)
+	return nil
+}
+
+// Release returns the reservation of transactions that were not sent
+func (b *Budget) Release(txs ...*types.Transaction) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, tx := range txs {
+		if fee, ok := b.holds[tx.Hash()]; ok {
+			b.reserved.Sub(b.reserved, fee)
+			delete(b.holds, tx.Hash())
+			b.admitted--
+		}
+	}
+}
+
+// Settle replaces the reservation of a mined transaction with its actual cost
+func (b *Budget) Settle(hash common.Hash, actual *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if fee, ok := b.holds[hash]; ok {
+		b.reserved.Sub(b.reserved, fee)
+		delete(b.holds, hash)
+	}
+	if actual != nil {
+		b.spent.Add(b.spent, actual)
+	}
+}
+
+// Limit returns the budget limit in wei
+func (b *Budget) Limit() *big.Int {
+	return new(big.Int).Set(b.limit)
+}
+
+// Spent returns the actual fees of settled transactions
+func (b *Budget) Spent() *big.Int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return new(big.Int).Set(b.spent)
+}
+
+// Reserved returns the max fees of admitted transactions not yet settled
+func (b *Budget) Reserved() *big.Int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return new(big.Int).Set(b.reserved)
+}
+
+// Stopped returns the point at which the budget first refused transactions, or nil
+func (b *Budget) Stopped() *Stop {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stop == nil {
+		return nil
+	}
+	stop := *b.stop
+	return &stop
+}
@@ -0,0 +1,55 @@
+package budget
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newTx(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, Gas: 21000, GasPrice: big.NewInt(10)})
+}
+
+func TestBudget_ReserveSettle(t *testing.T) {
+	// Room for two transactions at their max fee of 210000 wei
+	b := New(big.NewInt(500000))
+
+	tx1, tx2, tx3 := newTx(0), newTx(1), newTx(2)
+	if err := b.Reserve(tx1, tx2); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := b.Reserve(tx3); !errors.Is(err, ErrExceeded) {
+		t.Fatalf("Reserve() error = %v, want ErrExceeded", err)
+	}
+
+	stop := b.Stopped()
+	if stop == nil || stop.Admitted != 2 || stop.Reserved.Int64() != 420000 {
+		t.Fatalf("unexpected stop point: %+v", stop)
+	}
+
+	// Actual costs below the max fee free up room
+	b.Settle(tx1.Hash(), big.NewInt(100000))
+	b.Settle(tx2.Hash(), big.NewInt(100000))
+	if b.Spent().Int64() != 200000 || b.Reserved().Sign() != 0 {
+		t.Errorf("spent=%s reserved=%s, want 200000/0", b.Spent(), b.Reserved())
+	}
+	if err := b.Reserve(tx3); err != nil {
+		t.Errorf("Reserve() after settling error = %v", err)
+	}
+
+	b.Release(tx3)
+	if b.Reserved().Sign() != 0 {
+		t.Errorf("reserved = %s after release, want 0", b.Reserved())
+	}
+	if b.Stopped().Admitted != 2 {
+		t.Error("stop point should not change after the first refusal")
+	}
+}
+
+func TestPlanCost(t *testing.T) {
+	if got := PlanCost([]*types.Transaction{newTx(0), newTx(1)}); got.Int64() != 420000 {
+		t.Errorf("PlanCost() = %s, want 420000", got)
+	}
+}
@@ -0,0 +1,108 @@
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// keyModules are the dependencies whose versions affect results, listed by --version
+var keyModules = []string{
+	"github.com/ethereum/go-ethereum",
+	"github.com/prometheus/client_golang",
+	"github.com/parquet-go/parquet-go",
+	"github.com/cockroachdb/pebble",
+	"github.com/spf13/cobra",
+}
+
+// Info describes the txhammer build
+type Info struct {
+	Version   string
+	Commit    string
+	Date      string
+	GoVersion string
+}
+
+// Module is a dependency and the version it was built with
+type Module struct {
+	Path    string
+	Version string
+}
+
+// New returns the build info from the ldflags values. Values not set by
+// ldflags fall back to the VCS stamp Go embeds in binaries built from a checkout.
+func New(version, commit, date string) Info {
+	info := Info{Version: version, Commit: commit, Date: date, GoVersion: runtime.Version()}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range bi.Settings {
+		switch {
+		case s.Key == "vcs.revision" && unset(info.Commit):
+			info.Commit = s.Value
+			if len(info.Commit) > 12 {
+				info.Commit = info.Commit[:12]
+			}
+		case s.Key == "vcs.time" && unset(info.Date):
+			info.Date = s.Value
+		}
+	}
+	return info
+}
+
+// unset reports whether an ldflags value was left at its default
+func unset(v string) bool {
+	return v == "" || v == "unknown"
+}
+
+// String returns the one-line version, e.g. "v1.2.0 (commit 1a2b3c4, built 2025-01-01T00:00:00Z)"
+func (i Info) String() string {
+	s := i.Version
+	var parts []string
+	if !unset(i.Commit) {
+		parts = append(parts, "commit "+i.Commit)
+	}
+	if !unset(i.Date) {
+		parts = append(parts, "built "+i.Date)
+	}
+	if len(parts) > 0 {
+		s += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return s
+}
+
+// Details returns the Go toolchain, platform and key dependency versions, one per line
+func (i Info) Details() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %-36s %s %s/%s\n", "go", i.GoVersion, runtime.GOOS, runtime.GOARCH)
+	for _, m := range Dependencies() {
+		fmt.Fprintf(&b, "  %-36s %s\n", m.Path, m.Version)
+	}
+	return b.String()
+}
+
+// Dependencies returns the versions of the key modules linked into the binary
+func Dependencies() []Module {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	versions := make(map[string]string, len(bi.Deps))
+	for _, dep := range bi.Deps {
+		version := dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Path + " " + dep.Replace.Version
+		}
+		versions[dep.Path] = version
+	}
+
+	var modules []Module
+	for _, path := range keyModules {
+		if version, ok := versions[path]; ok {
+			modules = append(modules, Module{Path: path, Version: version})
+		}
+	}
+	return modules
+}
@@ -0,0 +1,36 @@
+package buildinfo
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInfo_String(t *testing.T) {
+	tests := []struct {
+		info Info
+		want string
+	}{
+		{Info{Version: "v1.2.0", Commit: "1a2b3c4", Date: "2025-01-01T00:00:00Z"}, "v1.2.0 (commit 1a2b3c4, built 2025-01-01T00:00:00Z)"},
+		{Info{Version: "v1.2.0", Commit: "1a2b3c4"}, "v1.2.0 (commit 1a2b3c4)"},
+		{Info{Version: "dev", Commit: "unknown"}, "dev"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	info := New("v1.2.0", "1a2b3c4", "2025-01-01T00:00:00Z")
+	if info.Version != "v1.2.0" || info.Commit != "1a2b3c4" || info.Date != "2025-01-01T00:00:00Z" {
+		t.Errorf("New() overrode ldflags values: %+v", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", info.GoVersion, runtime.Version())
+	}
+	if details := info.Details(); !strings.Contains(details, runtime.Version()) {
+		t.Errorf("Details() = %q, want the Go version", details)
+	}
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchCaller is the RPC connection of one endpoint of a Balanced client
+type BatchCaller interface {
+	BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error
+}
+
+// Endpoint is an RPC node a Balanced client sends to
+type Endpoint struct {
+	Name   string // Label used in the statistics
+	Client BatchCaller
+}
+
+// Policies a Balanced client picks the endpoint of each send with
+const (
+	BalanceRoundRobin   = "round-robin"   // Consecutive sends go to consecutive endpoints
+	BalanceLeastLatency = "least-latency" // Each send goes to the endpoint that answered fastest lately
+)
+
+const (
+	// DefaultFailoverTimeout is how long a Balanced client waits for an
+	// endpoint before it fails the send over to the next one
+	DefaultFailoverTimeout = 5 * time.Second
+
+	// failoverCooldown is how long an endpoint that failed a call is passed over
+	failoverCooldown = 5 * time.Second
+
+	// latencyWeight is the weight of the newest call in an endpoint's latency average
+	latencyWeight = 0.2
+)
+
+// BalancedEndpointStats counts the sends of one endpoint of a Balanced client
+type BalancedEndpointStats struct {
+	Name        string `json:"name"`
+	Calls       int    `json:"calls"`        // Calls the endpoint answered
+	Sent        int    `json:"sent"`         // Txs it accepted
+	Failed      int    `json:"failed"`       // Txs it refused
+	CallErrors  int    `json:"call_errors"`  // Calls that errored or timed out and were failed over
+	MeanLatency string `json:"mean_latency"` // Go duration of an answered call on average
+
+	latency time.Duration // Sum over answered calls
+}
+
+// BalanceStats summarizes a Balanced send
+type BalanceStats struct {
+	Policy    string                   `json:"policy"`
+	Failovers int                      `json:"failovers"` // Calls sent again to another endpoint
+	Endpoints []*BalancedEndpointStats `json:"endpoints"`
+}
+
+// balancedEndpoint is the state of one endpoint of a Balanced client
+type balancedEndpoint struct {
+	Endpoint
+	ewma      time.Duration // Recent call latency (0 = not measured yet)
+	downUntil time.Time     // Passed over until then after a failed call
+}
+
+// Balanced sends each transaction batch to one of several endpoints, picked
+// round-robin or by least latency. A call that errors or does not answer
+// within the failover timeout is sent again to the next endpoint. It has
+// the send methods of Client, so every send path can take it in its place.
+type Balanced struct {
+	policy  string
+	timeout time.Duration
+	next    atomic.Uint64
+
+	mu        sync.Mutex
+	endpoints []*balancedEndpoint
+	stats     BalanceStats
+}
+
+// NewBalanced creates a client that spreads sends over endpoints by policy,
+// failing a call over after timeout (0 = only on errors)
+func NewBalanced(endpoints []Endpoint, policy string, timeout time.Duration) (*Balanced, error) {
+	if policy != BalanceRoundRobin && policy != BalanceLeastLatency {
+		return nil, fmt.Errorf("unknown balance policy %q (valid: %s, %s)", policy, BalanceRoundRobin, BalanceLeastLatency)
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("balancing needs at least one endpoint")
+	}
+	b := &Balanced{policy: policy, timeout: timeout}
+	b.stats.Policy = policy
+	for _, e := range endpoints {
+		b.endpoints = append(b.endpoints, &balancedEndpoint{Endpoint: e})
+		b.stats.Endpoints = append(b.stats.Endpoints, &BalancedEndpointStats{Name: e.Name})
+	}
+	return b, nil
+}
+
+// BatchSendRawTransactions sends a batch of raw transactions
+func (b *Balanced) BatchSendRawTransactions(ctx context.Context, rawTxs [][]byte) ([]common.Hash, error) {
+	encoded := make([]string, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		encoded[i] = hexutil.Encode(rawTx)
+	}
+	return b.BatchSendEncodedTransactions(ctx, encoded)
+}
+
+// BatchSendEncodedTransactions sends a batch of hex-encoded raw transactions.
+// A tx the endpoint refused gets a zero hash; the batch fails only if every
+// endpoint failed the call.
+func (b *Balanced) BatchSendEncodedTransactions(ctx context.Context, encoded []string) ([]common.Hash, error) {
+	hashes, _, err := b.submit(ctx, encoded)
+	return hashes, err
+}
+
+// SendRawTransaction sends one raw transaction
+func (b *Balanced) SendRawTransaction(ctx context.Context, rawTx []byte) (common.Hash, error) {
+	hashes, errs, err := b.submit(ctx, []string{hexutil.Encode(rawTx)})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if hashes[0] == (common.Hash{}) {
+		return common.Hash{}, errs[0]
+	}
+	return hashes[0], nil
+}
+
+// SendTransaction sends a signed transaction
+func (b *Balanced) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	_, err = b.SendRawTransaction(ctx, raw)
+	return err
+}
+
+// BatchCall runs other batch calls on the endpoints in turn, trying the
+// next one if a call fails
+func (b *Balanced) BatchCall(batch []rpc.BatchElem) error {
+	var callErr error
+	for _, idx := range b.order() {
+		err := b.endpoints[idx].Client.BatchCallContext(context.Background(), batch)
+		if err == nil {
+			return nil
+		}
+		callErr = errors.Join(callErr, fmt.Errorf("%s: %w", b.endpoints[idx].Name, err))
+	}
+	return fmt.Errorf("all endpoints failed: %w", callErr)
+}
+
+// Stats returns the send statistics so far
+func (b *Balanced) Stats() *BalanceStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.Endpoints = make([]*BalancedEndpointStats, len(b.stats.Endpoints))
+	for i, e := range b.stats.Endpoints {
+		copied := *e
+		if e.Calls > 0 {
+			copied.MeanLatency = (e.latency / time.Duration(e.Calls)).String()
+		}
+		stats.Endpoints[i] = &copied
+	}
+	return &stats
+}
+
+// order returns the endpoints in the order a send tries them: by policy,
+// with endpoints that recently failed a call last
+func (b *Balanced) order() []int {
+	first := int(b.next.Add(1)-1) % len(b.endpoints)
+	order := make([]int, len(b.endpoints))
+	for i := range order {
+		order[i] = (first + i) % len(b.endpoints)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	sort.SliceStable(order, func(i, j int) bool {
+		ei, ej := b.endpoints[order[i]], b.endpoints[order[j]]
+		if downI, downJ := now.Before(ei.downUntil), now.Before(ej.downUntil); downI != downJ {
+			return downJ
+		}
+		if b.policy == BalanceLeastLatency {
+			return ei.ewma < ej.ewma
+		}
+		return false
+	})
+	return order
+}
+
+// submit sends encoded to one endpoint after the other until one answers
+// the call. It returns the hash and, for refused txs, the error of each tx.
+func (b *Balanced) submit(ctx context.Context, encoded []string) ([]common.Hash, []error, error) {
+	var callErr error
+	for attempt, idx := range b.order() {
+		if attempt > 0 {
+			b.mu.Lock()
+			b.stats.Failovers++
+			b.mu.Unlock()
+		}
+		hashes, errs, err := b.call(ctx, idx, encoded)
+		if err == nil {
+			return hashes, errs, nil
+		}
+		callErr = errors.Join(callErr, fmt.Errorf("%s: %w", b.endpoints[idx].Name, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, nil, fmt.Errorf("all endpoints failed: %w", callErr)
+}
+
+// call sends encoded to endpoint idx and records the outcome
+func (b *Balanced) call(ctx context.Context, idx int, encoded []string) ([]common.Hash, []error, error) {
+	hashes := make([]common.Hash, len(encoded))
+	elems := make([]rpc.BatchElem, len(encoded))
+	for i, raw := range encoded {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_sendRawTransaction",
+			Args:   []interface{}{raw},
+			Result: &hashes[i],
+		}
+	}
+
+	callCtx := ctx
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := b.endpoints[idx].Client.BatchCallContext(callCtx, elems)
+	elapsed := time.Since(start)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, es := b.endpoints[idx], b.stats.Endpoints[idx]
+	if err != nil {
+		es.CallErrors++
+		e.downUntil = time.Now().Add(failoverCooldown)
+		return nil, nil, err
+	}
+
+	es.Calls++
+	es.latency += elapsed
+	if e.ewma == 0 {
+		e.ewma = elapsed
+	} else {
+		e.ewma = time.Duration(latencyWeight*float64(elapsed) + (1-latencyWeight)*float64(e.ewma))
+	}
+	errs := make([]error, len(encoded))
+	for i := range encoded {
+		switch err := elems[i].Error; {
+		case err == nil:
+			es.Sent++
+		case IsAlreadyKnown(err):
+			// An endpoint tried before may have taken it before failing
+			es.Sent++
+			hashes[i] = crypto.Keccak256Hash(hexutil.MustDecode(encoded[i]))
+		default:
+			es.Failed++
+			hashes[i] = common.Hash{}
+			errs[i] = err
+		}
+	}
+	return hashes, errs, nil
+}
+
+// IsAlreadyKnown reports whether a node refused a tx because its pool already has it
+func IsAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "already imported") ||
+		strings.Contains(msg, "known transaction")
+}
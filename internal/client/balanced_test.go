@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// mockCaller answers each eth_sendRawTransaction with the tx hash, or with
+// err for every tx
+type mockCaller struct {
+	err     error         // Per-tx error
+	callErr error         // Whole-call error
+	delay   time.Duration // Time before answering
+	calls   atomic.Int64
+}
+
+func (m *mockCaller) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	m.calls.Add(1)
+	if m.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.delay):
+		}
+	}
+	if m.callErr != nil {
+		return m.callErr
+	}
+	for i := range batch {
+		if m.err != nil {
+			batch[i].Error = m.err
+			continue
+		}
+		raw := hexutil.MustDecode(batch[i].Args[0].(string))
+		*batch[i].Result.(*common.Hash) = crypto.Keccak256Hash(raw)
+	}
+	return nil
+}
+
+func TestNewBalanced(t *testing.T) {
+	endpoints := []Endpoint{{Name: "a", Client: &mockCaller{}}}
+	if _, err := NewBalanced(endpoints, "random", 0); err == nil {
+		t.Error("NewBalanced() with an unknown policy should fail")
+	}
+	if _, err := NewBalanced(nil, BalanceRoundRobin, 0); err == nil {
+		t.Error("NewBalanced() without endpoints should fail")
+	}
+}
+
+func TestBalanced_RoundRobin(t *testing.T) {
+	a := &mockCaller{}
+	b := &mockCaller{err: errors.New("txpool is full")}
+	bal, err := NewBalanced([]Endpoint{{Name: "a", Client: a}, {Name: "b", Client: b}}, BalanceRoundRobin, 0)
+	if err != nil {
+		t.Fatalf("NewBalanced() error = %v", err)
+	}
+
+	rawTxs := [][]byte{{0x01}, {0x02}}
+	for i := 0; i < 4; i++ {
+		hashes, err := bal.BatchSendRawTransactions(context.Background(), rawTxs)
+		if err != nil {
+			t.Fatalf("send %d error = %v", i, err)
+		}
+		// Sends alternate a, b, a, b; b refuses every tx
+		want := crypto.Keccak256Hash(rawTxs[0])
+		if i%2 == 1 {
+			want = common.Hash{}
+		}
+		if hashes[0] != want {
+			t.Errorf("send %d hash = %s, want %s", i, hashes[0].Hex(), want.Hex())
+		}
+	}
+	if a.calls.Load() != 2 || b.calls.Load() != 2 {
+		t.Errorf("calls = %d, %d, want 2 each", a.calls.Load(), b.calls.Load())
+	}
+
+	stats := bal.Stats()
+	if stats.Failovers != 0 {
+		t.Errorf("Failovers = %d, want 0", stats.Failovers)
+	}
+	if e := stats.Endpoints[0]; e.Calls != 2 || e.Sent != 4 || e.Failed != 0 || e.MeanLatency == "" {
+		t.Errorf("endpoint a = %+v", e)
+	}
+	if e := stats.Endpoints[1]; e.Sent != 0 || e.Failed != 4 {
+		t.Errorf("endpoint b = %+v", e)
+	}
+}
+
+func TestBalanced_Failover(t *testing.T) {
+	a := &mockCaller{callErr: errors.New("connection refused")}
+	b := &mockCaller{delay: time.Second}
+	c := &mockCaller{}
+	bal, _ := NewBalanced([]Endpoint{{Name: "a", Client: a}, {Name: "b", Client: b}, {Name: "c", Client: c}},
+		BalanceRoundRobin, 20*time.Millisecond)
+
+	// a errors and b times out, so c takes the tx
+	hash, err := bal.SendRawTransaction(context.Background(), []byte{0x01})
+	if err != nil || hash != crypto.Keccak256Hash([]byte{0x01}) {
+		t.Fatalf("SendRawTransaction() = %s, %v, want the tx hash", hash.Hex(), err)
+	}
+	stats := bal.Stats()
+	if stats.Failovers != 2 || stats.Endpoints[0].CallErrors != 1 || stats.Endpoints[1].CallErrors != 1 {
+		t.Errorf("stats = %+v, want 2 failovers", stats)
+	}
+
+	// a and b cool down, so c goes first
+	if _, err := bal.SendRawTransaction(context.Background(), []byte{0x02}); err != nil {
+		t.Fatalf("SendRawTransaction() error = %v", err)
+	}
+	if a.calls.Load() != 1 || b.calls.Load() != 1 || c.calls.Load() != 2 {
+		t.Errorf("calls = %d, %d, %d, want 1, 1, 2", a.calls.Load(), b.calls.Load(), c.calls.Load())
+	}
+
+	c.callErr = errors.New("timeout")
+	if _, err := bal.SendRawTransaction(context.Background(), []byte{0x03}); err == nil {
+		t.Error("SendRawTransaction() should fail when every endpoint fails")
+	}
+}
+
+func TestBalanced_BatchCall(t *testing.T) {
+	a := &mockCaller{}
+	b := &mockCaller{}
+	bal, _ := NewBalanced([]Endpoint{{Name: "a", Client: a}, {Name: "b", Client: b}}, BalanceRoundRobin, 0)
+
+	// Calls rotate over the endpoints
+	for i := 0; i < 4; i++ {
+		if err := bal.BatchCall(nil); err != nil {
+			t.Fatalf("BatchCall() error = %v", err)
+		}
+	}
+	if a.calls.Load() != 2 || b.calls.Load() != 2 {
+		t.Errorf("calls = %d, %d, want 2 each", a.calls.Load(), b.calls.Load())
+	}
+
+	// A failing endpoint hands the call to the next one
+	a.callErr = errors.New("connection refused")
+	for i := 0; i < 2; i++ {
+		if err := bal.BatchCall(nil); err != nil {
+			t.Fatalf("BatchCall() error = %v", err)
+		}
+	}
+	b.callErr = errors.New("timeout")
+	if err := bal.BatchCall(nil); err == nil {
+		t.Error("BatchCall() should fail when every endpoint fails")
+	}
+}
+
+func TestBalanced_LeastLatency(t *testing.T) {
+	slow := &mockCaller{delay: 20 * time.Millisecond}
+	fast := &mockCaller{}
+	bal, _ := NewBalanced([]Endpoint{{Name: "slow", Client: slow}, {Name: "fast", Client: fast}},
+		BalanceLeastLatency, 0)
+
+	// Both are measured once, then the fast one takes every send
+	for i := 0; i < 6; i++ {
+		if _, err := bal.SendRawTransaction(context.Background(), []byte{byte(i)}); err != nil {
+			t.Fatalf("send %d error = %v", i, err)
+		}
+	}
+	if slow.calls.Load() != 1 || fast.calls.Load() != 5 {
+		t.Errorf("calls = %d slow, %d fast, want 1, 5", slow.calls.Load(), fast.calls.Load())
+	}
+}
+
+func TestBalanced_SendTransaction(t *testing.T) {
+	a := &mockCaller{}
+	bal, _ := NewBalanced([]Endpoint{{Name: "a", Client: a}}, BalanceRoundRobin, 0)
+
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1)})
+	if err := bal.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("SendTransaction() error = %v", err)
+	}
+	if stats := bal.Stats(); a.calls.Load() != 1 || stats.Endpoints[0].Sent != 1 {
+		t.Errorf("calls = %d, stats = %+v, want one sent tx", a.calls.Load(), stats.Endpoints[0])
+	}
+
+	a.err = errors.New("nonce too low")
+	if err := bal.SendTransaction(context.Background(), tx); err == nil {
+		t.Error("SendTransaction() should return the refusal")
+	}
+}
+
+func TestIsAlreadyKnown(t *testing.T) {
+	for _, msg := range []string{"already known", "ALREADY IMPORTED", "known transaction: 0xabc"} {
+		if !IsAlreadyKnown(errors.New(msg)) {
+			t.Errorf("IsAlreadyKnown(%q) = false, want true", msg)
+		}
+	}
+	if IsAlreadyKnown(errors.New("nonce too low")) {
+		t.Error("IsAlreadyKnown(\"nonce too low\") = true, want false")
+	}
+}
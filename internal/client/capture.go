@@ -0,0 +1,155 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Failure is a captured eth_sendRawTransaction exchange that failed
+type Failure struct {
+	Time     time.Time       `json:"time"`
+	Endpoint string          `json:"endpoint"`         // Scheme and host only
+	Status   int             `json:"status,omitempty"` // HTTP status (0 = no response)
+	Error    string          `json:"error,omitempty"`  // Transport error
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"` // Non-JSON bodies are kept as a string
+}
+
+// Capture keeps the raw JSON-RPC request and response bodies of the first
+// failed eth_sendRawTransaction calls over HTTP
+type Capture struct {
+	limit int
+
+	mu       sync.Mutex
+	failures []Failure
+}
+
+// NewCapture creates a capture that keeps at most limit failures
+func NewCapture(limit int) *Capture {
+	return &Capture{limit: limit}
+}
+
+// Failures returns the captured failures in the order they occurred
+func (c *Capture) Failures() []Failure {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Failure(nil), c.failures...)
+}
+
+// full reports whether the capture holds its limit of failures
+func (c *Capture) full() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.failures) >= c.limit
+}
+
+// add records f unless the capture is already full
+func (c *Capture) add(f Failure) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.failures) < c.limit {
+		c.failures = append(c.failures, f)
+	}
+}
+
+// capture is the active capture of every HTTP client (nil = disabled)
+var capture atomic.Pointer[Capture]
+
+// CaptureFailures makes all HTTP clients, including those already created,
+// record failed sends into c. A nil c stops capturing.
+func CaptureFailures(c *Capture) {
+	capture.Store(c)
+}
+
+// capturingTransport hands failed send exchanges to the active capture
+type capturingTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper. It reads the bodies only while a
+// capture is active and has room left.
+func (t *capturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := capture.Load()
+	if c == nil || c.full() || req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	reqBody, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	if !bytes.Contains(reqBody, []byte(`"eth_sendRawTransaction"`)) {
+		return t.next.RoundTrip(req)
+	}
+
+	failure := Failure{
+		Time:     time.Now(),
+		Endpoint: req.URL.Scheme + "://" + req.URL.Host,
+		Request:  rawJSON(reqBody),
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		failure.Error = err.Error()
+		c.add(failure)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		failure.Error = err.Error()
+	}
+	if err != nil || resp.StatusCode != http.StatusOK || hasRPCError(respBody) {
+		failure.Status = resp.StatusCode
+		failure.Response = rawJSON(respBody)
+		c.add(failure)
+	}
+	return resp, nil
+}
+
+// hasRPCError reports whether a JSON-RPC response, or any response of a
+// batch, carries an error
+func hasRPCError(body []byte) bool {
+	type response struct {
+		Error json.RawMessage `json:"error"`
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var batch []response
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return true
+		}
+		for _, r := range batch {
+			if len(r.Error) > 0 && string(r.Error) != "null" {
+				return true
+			}
+		}
+		return false
+	}
+	var r response
+	if err := json.Unmarshal(body, &r); err != nil {
+		return true
+	}
+	return len(r.Error) > 0 && string(r.Error) != "null"
+}
+
+// rawJSON returns body as embedded JSON, or as a JSON string if it is not
+// valid JSON
+func rawJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body)
+	}
+	s, _ := json.Marshal(string(body))
+	return s
+}
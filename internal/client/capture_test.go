@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCaptureFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method == "eth_sendRawTransaction" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":-32000,"message":"nonce too low"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":"0x1"}`))
+	}))
+	defer server.Close()
+
+	cli, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer cli.Close()
+
+	capture := NewCapture(2)
+	CaptureFailures(capture)
+	defer CaptureFailures(nil)
+
+	ctx := context.Background()
+	if _, err := cli.ChainID(ctx); err != nil {
+		t.Fatalf("ChainID() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := cli.SendRawTransaction(ctx, []byte{0x01, 0x02}); err == nil {
+			t.Fatal("SendRawTransaction() should fail")
+		}
+	}
+
+	failures := capture.Failures()
+	if len(failures) != 2 {
+		t.Fatalf("captured %d failures, want the limit of 2", len(failures))
+	}
+	f := failures[0]
+	if f.Endpoint != server.URL || f.Status != http.StatusOK {
+		t.Errorf("failure = %s %d, want %s 200", f.Endpoint, f.Status, server.URL)
+	}
+	if !strings.Contains(string(f.Request), `"0x0102"`) || !strings.Contains(string(f.Response), "nonce too low") {
+		t.Errorf("failure bodies = %s / %s", f.Request, f.Response)
+	}
+}
+
+func TestHasRPCError(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{`{"jsonrpc":"2.0","id":1,"result":"0x1"}`, false},
+		{`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"x"}}`, true},
+		{`[{"id":1,"result":"0x1"},{"id":2,"result":"0x2"}]`, false},
+		{`[{"id":1,"result":"0x1"},{"id":2,"error":{"code":-32000}}]`, true},
+		{`<html>bad gateway</html>`, true},
+	}
+	for _, tt := range tests {
+		if got := hasRPCError([]byte(tt.body)); got != tt.want {
+			t.Errorf("hasRPCError(%s) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
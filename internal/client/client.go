@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net/http"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -18,17 +19,38 @@ type Client struct {
 	rpc *rpc.Client
 }
 
-// New creates a new client instance
+// New creates a new client instance. Clients created with New share one
+// pool of HTTP connections. HTTP clients back off from endpoints that throttle
+// them (see SetThrottleBackoff).
 func New(url string) (*Client, error) {
-	rpcClient, err := rpc.Dial(url)
+	var opts []rpc.ClientOption
+	if IsHTTP(url) {
+		opts = append(opts, rpc.WithHTTPClient(&http.Client{Transport: &capturingTransport{next: &throttlingTransport{next: sharedTransport}}}))
+	}
+	return dial(url, opts)
+}
+
+// NewDedicated creates a client that does not share HTTP connections with
+// other clients. Each HTTP client gets its own transport, so its requests
+// never wait for a connection another client holds. WebSocket URLs already
+// get a connection of their own.
+func NewDedicated(url string) (*Client, error) {
+	var opts []rpc.ClientOption
+	if IsHTTP(url) {
+		opts = append(opts, rpc.WithHTTPClient(&http.Client{Transport: &capturingTransport{next: &throttlingTransport{next: newTransport()}}}))
+	}
+	return dial(url, opts)
+}
+
+// dial connects to url with opts
+func dial(url string, opts []rpc.ClientOption) (*Client, error) {
+	rpcClient, err := rpc.DialOptions(context.Background(), url, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
 	}
 
-	ethClient := ethclient.NewClient(rpcClient)
-
 	return &Client{
-		eth: ethClient,
+		eth: ethclient.NewClient(rpcClient),
 		rpc: rpcClient,
 	}, nil
 }
@@ -63,6 +85,16 @@ func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (ui
 	return c.eth.PendingNonceAt(ctx, account)
 }
 
+// NonceAt returns the nonce of an account at a given block (nil = latest)
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.eth.NonceAt(ctx, account, blockNumber)
+}
+
+// CodeAt returns the contract code of an account at a given block
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.eth.CodeAt(ctx, account, blockNumber)
+}
+
 // SuggestGasPrice returns the suggested gas price
 func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 	return c.eth.SuggestGasPrice(ctx)
@@ -88,16 +120,37 @@ func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*t
 	return c.eth.TransactionReceipt(ctx, txHash)
 }
 
+// TransactionByHash returns a transaction by hash and whether it is still pending
+func (c *Client) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	return c.eth.TransactionByHash(ctx, txHash)
+}
+
 // HeaderByNumber returns the header of a block by number
 func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
 	return c.eth.HeaderByNumber(ctx, number)
 }
 
+// SubscribeNewHead subscribes to new chain heads. It needs a WebSocket or
+// IPC endpoint; over HTTP it returns rpc.ErrNotificationsUnsupported.
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return c.eth.SubscribeNewHead(ctx, ch)
+}
+
+// CallContext performs a raw JSON-RPC call
+func (c *Client) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	return c.rpc.CallContext(ctx, result, method, args...)
+}
+
 // BatchCall executes multiple RPC calls in a single request
 func (c *Client) BatchCall(b []rpc.BatchElem) error {
 	return c.rpc.BatchCall(b)
 }
 
+// BatchCallContext executes multiple RPC calls in a single request, bounded by ctx
+func (c *Client) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return c.rpc.BatchCallContext(ctx, b)
+}
+
 // SendRawTransaction sends a raw transaction via RPC
 func (c *Client) SendRawTransaction(ctx context.Context, rawTx []byte) (common.Hash, error) {
 	var hash common.Hash
@@ -107,13 +160,22 @@ func (c *Client) SendRawTransaction(ctx context.Context, rawTx []byte) (common.H
 
 // BatchSendRawTransactions sends multiple raw transactions in a batch
 func (c *Client) BatchSendRawTransactions(ctx context.Context, rawTxs [][]byte) ([]common.Hash, error) {
-	batch := make([]rpc.BatchElem, len(rawTxs))
-	results := make([]common.Hash, len(rawTxs))
-
+	encoded := make([]string, len(rawTxs))
 	for i, rawTx := range rawTxs {
+		encoded[i] = "0x" + common.Bytes2Hex(rawTx)
+	}
+	return c.BatchSendEncodedTransactions(ctx, encoded)
+}
+
+// BatchSendEncodedTransactions sends multiple 0x-prefixed hex raw transactions in a batch
+func (c *Client) BatchSendEncodedTransactions(ctx context.Context, encoded []string) ([]common.Hash, error) {
+	batch := make([]rpc.BatchElem, len(encoded))
+	results := make([]common.Hash, len(encoded))
+
+	for i, rawTx := range encoded {
 		batch[i] = rpc.BatchElem{
 			Method: "eth_sendRawTransaction",
-			Args:   []interface{}{"0x" + common.Bytes2Hex(rawTx)},
+			Args:   []interface{}{rawTx},
 			Result: &results[i],
 		}
 	}
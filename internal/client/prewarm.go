@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// pinned maps a host to the IP address every new connection to it dials,
+// so DNS is resolved once per run instead of once per connection
+var pinned sync.Map
+
+// dialer is the net.Dialer of http.DefaultTransport
+var dialer = &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+// dialPinned dials addr, replacing its host with the pinned address if there
+// is one. TLS still verifies and sends SNI for the original host name.
+func dialPinned(ctx context.Context, network, addr string) (net.Conn, error) {
+	if host, port, err := net.SplitHostPort(addr); err == nil {
+		if ip, ok := pinned.Load(host); ok {
+			addr = net.JoinHostPort(ip.(string), port)
+		}
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+// newTransport returns an HTTP transport that dials pinned addresses and
+// keeps as many idle connections per host as it keeps in total, so
+// concurrent requests reuse their connections instead of redialing
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialPinned
+	transport.MaxIdleConnsPerHost = transport.MaxIdleConns
+	return transport
+}
+
+// sharedTransport serves all clients created with New
+var sharedTransport = newTransport()
+
+// IsHTTP reports whether rawURL is an HTTP(S) endpoint
+func IsHTTP(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// PinDNS resolves the host of an HTTP(S) URL once and makes new connections
+// of every client dial the resolved address. It returns the pinned address,
+// or "" when the URL has no host name to resolve.
+func PinDNS(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid RPC URL: %w", err)
+	}
+	host := u.Hostname()
+	if !IsHTTP(rawURL) || host == "" || net.ParseIP(host) != nil {
+		return "", nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("no addresses found for %s", host)
+	}
+
+	ip := addrs[0].IP.String()
+	pinned.Store(host, ip)
+	return ip, nil
+}
+
+// PrewarmResult describes a connection pre-warm
+type PrewarmResult struct {
+	Connections int           // Connections warmed
+	Calls       int           // No-op calls that succeeded
+	Duration    time.Duration // Wall time of the pre-warm
+	FirstCall   time.Duration // Slowest first call, including connection setup
+	WarmCall    time.Duration // Mean latency of the calls after the first
+}
+
+// Prewarm opens conns connections by making calls concurrent no-op calls on
+// each, so TLS handshakes and TCP slow start are paid before the send stage.
+// Connections over HTTP stay in the idle pool for the requests that follow.
+func (c *Client) Prewarm(ctx context.Context, conns, calls int) (*PrewarmResult, error) {
+	if conns <= 0 || calls <= 0 {
+		return &PrewarmResult{}, nil
+	}
+
+	start := time.Now()
+	result := &PrewarmResult{Connections: conns}
+
+	// All first calls start together, so each needs its own connection;
+	// later calls reuse them
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		warm     time.Duration
+		warmN    int
+		firstErr error
+		ready    sync.WaitGroup
+	)
+	begin := make(chan struct{})
+	ready.Add(conns)
+	for i := 0; i < conns; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-begin
+			for n := 0; n < calls; n++ {
+				callStart := time.Now()
+				_, err := c.eth.ChainID(ctx)
+				elapsed := time.Since(callStart)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					if firstErr == nil {
+						firstErr = err
+					}
+				case n == 0:
+					result.Calls++
+					result.FirstCall = max(result.FirstCall, elapsed)
+				default:
+					result.Calls++
+					warm += elapsed
+					warmN++
+				}
+				mu.Unlock()
+
+				// Hold the connection until every first call is done
+				if n == 0 {
+					ready.Done()
+					ready.Wait()
+				}
+			}
+		}()
+	}
+	close(begin)
+	wg.Wait()
+
+	result.Duration = time.Since(start)
+	if warmN > 0 {
+		result.WarmCall = warm / time.Duration(warmN)
+	}
+	if result.Calls == 0 && firstErr != nil {
+		return result, fmt.Errorf("pre-warm calls failed: %w", firstErr)
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newChainIDServer answers every JSON-RPC request with chain ID 1 and counts
+// the connections it accepts
+func newChainIDServer(t *testing.T, conns *atomic.Int64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Prewarm(t *testing.T) {
+	var conns atomic.Int64
+	server := newChainIDServer(t, &conns)
+
+	cli, err := NewDedicated(server.URL)
+	if err != nil {
+		t.Fatalf("NewDedicated() error = %v", err)
+	}
+	defer cli.Close()
+
+	result, err := cli.Prewarm(context.Background(), 4, 3)
+	if err != nil {
+		t.Fatalf("Prewarm() error = %v", err)
+	}
+	if result.Calls != 12 {
+		t.Errorf("Calls = %d, want 12", result.Calls)
+	}
+	if got := conns.Load(); got != 4 {
+		t.Errorf("connections opened = %d, want 4", got)
+	}
+
+	// Warmed connections serve later requests without redialing
+	for i := 0; i < 4; i++ {
+		if _, err := cli.ChainID(context.Background()); err != nil {
+			t.Fatalf("ChainID() error = %v", err)
+		}
+	}
+	if got := conns.Load(); got != 4 {
+		t.Errorf("connections after warm calls = %d, want 4", got)
+	}
+}
+
+func TestPinDNS(t *testing.T) {
+	var conns atomic.Int64
+	server := newChainIDServer(t, &conns)
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	url := "http://localhost:" + port
+	ip, err := PinDNS(context.Background(), url)
+	if err != nil {
+		t.Fatalf("PinDNS() error = %v", err)
+	}
+	defer pinned.Delete("localhost")
+	if net.ParseIP(ip) == nil {
+		t.Fatalf("PinDNS() = %q, want an IP address", ip)
+	}
+
+	// IP literals and non-HTTP URLs are left alone
+	for _, u := range []string{server.URL, "ws://localhost:8546"} {
+		if ip, err := PinDNS(context.Background(), u); err != nil || ip != "" {
+			t.Errorf("PinDNS(%q) = %q, %v, want no pin", u, ip, err)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// minThrottleBackoff is the pause after the first throttling response in a row
+	minThrottleBackoff = 250 * time.Millisecond
+
+	// DefaultThrottleBackoff is the default longest pause after throttling responses
+	DefaultThrottleBackoff = 10 * time.Second
+
+	// scanOverlap is how much of a response body is kept between reads, more
+	// than the error key and any throttling marker
+	scanOverlap = 32
+)
+
+// throttleMarkers are lowercase fragments of the errors providers return
+// when they throttle a client, in HTTP bodies and JSON-RPC error messages
+var throttleMarkers = []string{
+	"too many requests",
+	"rate limit",
+	"rate-limit",
+	"ratelimit",
+	"request limit",
+	"exceeded the rate",
+	"throttled",
+}
+
+// EndpointThrottle is the provider throttling of one endpoint during the run
+type EndpointThrottle struct {
+	Endpoint  string        `json:"endpoint"`   // Scheme and host only
+	Responses int           `json:"responses"`  // Throttling responses received
+	HeldBack  time.Duration `json:"held_back"`  // Wall-clock time requests to the endpoint were held back
+	LastError string        `json:"last_error"` // Status line or error of the last throttling response
+}
+
+// endpointThrottle is the backoff state of one endpoint
+type endpointThrottle struct {
+	EndpointThrottle
+	backoff time.Duration // Doubles with every throttling response in a row
+	until   time.Time     // Requests wait until then
+}
+
+// Throttle detects provider throttling per endpoint and holds back further
+// requests to a throttled endpoint with exponential backoff
+type Throttle struct {
+	mu         sync.Mutex
+	maxBackoff time.Duration
+	endpoints  map[string]*endpointThrottle
+}
+
+// newThrottle creates a throttle that backs off for at most maxBackoff
+func newThrottle(maxBackoff time.Duration) *Throttle {
+	return &Throttle{maxBackoff: maxBackoff, endpoints: make(map[string]*endpointThrottle)}
+}
+
+// throttle tracks the throttling of every HTTP client
+var throttle = newThrottle(DefaultThrottleBackoff)
+
+// SetThrottleBackoff sets the longest pause of an endpoint after throttling
+// responses (0 = record throttling without holding back requests)
+func SetThrottleBackoff(d time.Duration) {
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+	throttle.maxBackoff = d
+}
+
+// Throttling returns the endpoints that throttled requests so far, by endpoint
+func Throttling() []EndpointThrottle {
+	return throttle.stats()
+}
+
+// stats returns the endpoints that throttled requests, by endpoint
+func (t *Throttle) stats() []EndpointThrottle {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]EndpointThrottle, 0, len(t.endpoints))
+	for _, e := range t.endpoints {
+		if e.Responses > 0 {
+			out = append(out, e.EndpointThrottle)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Endpoint < out[j].Endpoint })
+	return out
+}
+
+// wait blocks until the backoff of endpoint has passed
+func (t *Throttle) wait(ctx context.Context, endpoint string) error {
+	t.mu.Lock()
+	var until time.Time
+	if e := t.endpoints[endpoint]; e != nil {
+		until = e.until
+	}
+	t.mu.Unlock()
+
+	delay := time.Until(until)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// record notes a response of endpoint at now. A throttling response extends
+// the backoff, at least to retryAfter; any other response resets it.
+func (t *Throttle) record(endpoint string, throttled bool, retryAfter time.Duration, reason string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.endpoints[endpoint]
+	if !throttled {
+		if e != nil {
+			e.backoff = 0
+		}
+		return
+	}
+	if e == nil {
+		e = &endpointThrottle{EndpointThrottle: EndpointThrottle{Endpoint: endpoint}}
+		t.endpoints[endpoint] = e
+	}
+	e.Responses++
+	e.LastError = reason
+	if t.maxBackoff <= 0 {
+		return
+	}
+
+	e.backoff = min(max(2*e.backoff, minThrottleBackoff, retryAfter), t.maxBackoff)
+	until := now.Add(e.backoff)
+	if !until.After(e.until) {
+		return
+	}
+	// Only the part of the window not already held back counts
+	from := now
+	if e.until.After(now) {
+		from = e.until
+	}
+	e.HeldBack += until.Sub(from)
+	e.until = until
+}
+
+// throttlingTransport holds back requests to throttled endpoints and
+// records throttling responses
+type throttlingTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Scheme + "://" + req.URL.Host
+	if err := throttle.wait(req.Context(), endpoint); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := retryAfter(resp.Header.Get("Retry-After"))
+	if resp.StatusCode != http.StatusOK {
+		throttled := resp.StatusCode == http.StatusTooManyRequests
+		throttle.record(endpoint, throttled, retry, resp.Status, time.Now())
+		return resp, nil
+	}
+
+	// A 200 response throttles only in a JSON-RPC error, which the body
+	// reveals as the caller reads it
+	resp.Body = &scanningBody{ReadCloser: resp.Body, done: func(marker string) {
+		throttle.record(endpoint, marker != "", retry, marker, time.Now())
+	}}
+	return resp, nil
+}
+
+// errorKey marks the JSON-RPC error objects of a response body
+var errorKey = []byte(`"error"`)
+
+// scanningBody looks for throttling markers in a response body as it is
+// read. Bodies without an error object are only searched for one, so
+// successful responses are neither buffered nor lowercased. done is called
+// once with the marker found ("" if none) when the body is drained or closed.
+type scanningBody struct {
+	io.ReadCloser
+	window  []byte // Tail of the bytes read so far, to find text split across reads
+	isError bool   // An error object was seen; markers are searched from here on
+	marker  string
+	done    func(marker string)
+	once    sync.Once
+}
+
+// Read implements io.Reader
+func (b *scanningBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && b.marker == "" {
+		b.scan(p[:n])
+	}
+	if err != nil {
+		b.finish()
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (b *scanningBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+// finish reports the outcome of the body
+func (b *scanningBody) finish() {
+	b.once.Do(func() { b.done(b.marker) })
+}
+
+// scan searches chunk, and its seam with the previous reads
+func (b *scanningBody) scan(chunk []byte) {
+	seam := append(b.window, chunk[:min(len(chunk), scanOverlap)]...)
+	if !b.isError {
+		b.isError = bytes.Contains(seam, errorKey) || bytes.Contains(chunk, errorKey)
+	}
+	if b.isError {
+		if b.marker = throttleMarker(seam); b.marker == "" {
+			b.marker = throttleMarker(chunk)
+		}
+	}
+	if len(chunk) >= scanOverlap {
+		b.window = append(b.window[:0], chunk[len(chunk)-scanOverlap:]...)
+	} else {
+		b.window = append(b.window[:0], seam[max(len(seam)-scanOverlap, 0):]...)
+	}
+}
+
+// throttleMarker returns the throttling marker found in a response body ("" if none)
+func throttleMarker(body []byte) string {
+	lower := bytes.ToLower(body)
+	for _, marker := range throttleMarkers {
+		if bytes.Contains(lower, []byte(marker)) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// retryAfter parses a Retry-After header given in seconds (0 if absent or a date)
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// IsThrottled reports whether err is a provider throttling response rather
+// than a rejection by the node
+func IsThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return throttleMarker([]byte(err.Error())) != ""
+}
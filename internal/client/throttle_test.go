@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestThrottle_Record(t *testing.T) {
+	th := newThrottle(time.Second)
+	now := time.Unix(1000, 0)
+	const endpoint = "https://rpc.example.com"
+
+	th.record(endpoint, true, 0, "429 Too Many Requests", now)
+	th.record(endpoint, true, 0, "429 Too Many Requests", now)
+	stats := th.stats()
+	if len(stats) != 1 || stats[0].Responses != 2 {
+		t.Fatalf("stats = %+v, want 2 responses", stats)
+	}
+	// 250ms, then 500ms from the same instant: the windows overlap
+	if got := stats[0].HeldBack; got != 500*time.Millisecond {
+		t.Errorf("HeldBack = %s, want 500ms", got)
+	}
+
+	// Retry-After raises the pause, within the cap
+	th.record(endpoint, true, 5*time.Second, "429 Too Many Requests", now)
+	if got := th.stats()[0].HeldBack; got != time.Second {
+		t.Errorf("HeldBack = %s, want the 1s cap", got)
+	}
+
+	// A good response resets the backoff but keeps the counts
+	th.record(endpoint, false, 0, "", now.Add(2*time.Second))
+	th.record(endpoint, true, 0, "rate limit", now.Add(2*time.Second))
+	stats = th.stats()
+	if stats[0].Responses != 4 || stats[0].HeldBack != 1250*time.Millisecond || stats[0].LastError != "rate limit" {
+		t.Errorf("stats = %+v", stats[0])
+	}
+
+	th.record("https://other.example.com", false, 0, "", now)
+	if got := len(th.stats()); got != 1 {
+		t.Errorf("stats lists %d endpoints, want only the throttling one", got)
+	}
+}
+
+func TestThrottle_RecordOnly(t *testing.T) {
+	th := newThrottle(0)
+	th.record("https://rpc.example.com", true, time.Second, "429 Too Many Requests", time.Now())
+	stats := th.stats()
+	if len(stats) != 1 || stats[0].HeldBack != 0 {
+		t.Errorf("stats = %+v, want a response without held back time", stats)
+	}
+	if err := th.wait(context.Background(), "https://rpc.example.com"); err != nil {
+		t.Errorf("wait() error = %v", err)
+	}
+}
+
+func TestThrottlingTransport(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		calls++
+		if calls == 1 {
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 2 {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":-32005,"message":"Too Many Requests"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":"0x1"}`))
+	}))
+	defer server.Close()
+
+	saved := throttle
+	throttle = newThrottle(DefaultThrottleBackoff)
+	defer func() { throttle = saved }()
+
+	cli, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := cli.ChainID(ctx)
+		if !IsThrottled(err) {
+			t.Fatalf("call %d error = %v, want a throttling error", i+1, err)
+		}
+	}
+	start := time.Now()
+	if _, err := cli.ChainID(ctx); err != nil {
+		t.Fatalf("ChainID() error = %v", err)
+	}
+	if waited := time.Since(start); waited < 400*time.Millisecond {
+		t.Errorf("third call waited %s, want the 500ms backoff", waited)
+	}
+
+	stats := Throttling()
+	if len(stats) != 1 || stats[0].Endpoint != server.URL || stats[0].Responses != 2 {
+		t.Errorf("Throttling() = %+v", stats)
+	}
+}
+
+func TestIsThrottled(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}, true},
+		{rpc.HTTPError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}, false},
+		{errors.New("daily request limit reached"), true},
+		{errors.New("nonce too low"), false},
+		{errors.New("insufficient funds for gas * price + value"), false},
+	}
+	for _, tt := range tests {
+		if got := IsThrottled(tt.err); got != tt.want {
+			t.Errorf("IsThrottled(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestScanningBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"result", `{"jsonrpc":"2.0","id":1,"result":"rate limit"}`, ""},
+		{"other error", `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"nonce too low"}}`, ""},
+		{"throttled", `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"Rate Limit exceeded"}}`, "rate limit"},
+		{"throttled in a batch", `[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":2,"error":{"code":429,"message":"Too Many Requests"}}]`, "too many requests"},
+	}
+	for _, tt := range tests {
+		// Reading a byte at a time splits the error key and the markers across reads
+		for _, oneByte := range []bool{false, true} {
+			var r io.Reader = strings.NewReader(tt.body)
+			if oneByte {
+				r = iotest.OneByteReader(r)
+			}
+			calls, got := 0, ""
+			body := &scanningBody{ReadCloser: io.NopCloser(r), done: func(marker string) {
+				calls++
+				got = marker
+			}}
+			if _, err := io.ReadAll(body); err != nil {
+				t.Fatal(err)
+			}
+			_ = body.Close()
+			if calls != 1 || got != tt.want {
+				t.Errorf("%s (one byte %v): done called %d times with %q, want once with %q", tt.name, oneByte, calls, got, tt.want)
+			}
+		}
+	}
+}
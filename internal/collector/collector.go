@@ -2,17 +2,20 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/schollz/progressbar/v3"
 
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/mathutil"
 	"github.com/0xmhha/txhammer/internal/util/progress"
 )
@@ -22,13 +25,16 @@ type Client interface {
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
 	BlockNumber(ctx context.Context) (uint64, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
 	BatchCall(batch []rpc.BatchElem) error
 }
 
 // Collector handles transaction receipt collection and metrics
 type Collector struct {
-	client Client
-	config *Config
+	client    Client
+	config    *Config
+	budget    Budget
+	callbacks *Callbacks
 
 	// Tracking state
 	txMap   map[common.Hash]*TxInfo
@@ -36,6 +42,22 @@ type Collector struct {
 	blocks  []*BlockInfo
 	blockMu sync.RWMutex
 
+	// Finished records moved to disk once MemoryCap is reached
+	spill   *spillStore
+	spilled map[common.Hash]struct{}
+
+	// Logs of each expected event in successful receipts, guarded by txMutex
+	eventLogs []int
+
+	// New head subscription receipts are collected from (nil = polling)
+	heads       *headFeed
+	headsFailed bool
+
+	// Background polling started by Start and block tracking (nil = not running)
+	pollCancel  context.CancelFunc
+	pollDone    chan struct{}
+	blockCancel context.CancelFunc
+
 	// Metrics
 	confirmed atomic.Int64
 	failed    atomic.Int64
@@ -49,13 +71,26 @@ func New(client Client, config *Config) *Collector {
 	}
 
 	return &Collector{
-		client: client,
-		config: config,
-		txMap:  make(map[common.Hash]*TxInfo),
-		blocks: make([]*BlockInfo, 0),
+		client:    client,
+		config:    config,
+		txMap:     make(map[common.Hash]*TxInfo),
+		blocks:    make([]*BlockInfo, 0),
+		eventLogs: make([]int, len(config.ExpectedEvents)),
 	}
 }
 
+// WithBudget sets a budget that mined transactions are settled against
+func (c *Collector) WithBudget(budget Budget) *Collector {
+	c.budget = budget
+	return c
+}
+
+// WithCallbacks sets the receipt notification callbacks
+func (c *Collector) WithCallbacks(callbacks *Callbacks) *Collector {
+	c.callbacks = callbacks
+	return c
+}
+
 // TrackTransaction adds a transaction to be tracked
 func (c *Collector) TrackTransaction(hash common.Hash, from common.Address, nonce, gasLimit uint64, sentAt time.Time) {
 	c.txMutex.Lock()
@@ -84,37 +119,121 @@ func (c *Collector) TrackTransactions(txInfos []*TxInfo) {
 	}
 }
 
-// Collect starts the collection process and waits for all transactions
+// Untrack stops tracking transactions that were never sent
+func (c *Collector) Untrack(hashes ...common.Hash) {
+	c.txMutex.Lock()
+	defer c.txMutex.Unlock()
+
+	for _, hash := range hashes {
+		if info, ok := c.txMap[hash]; ok {
+			if info.Status == TxConfirmPending {
+				c.pending.Add(-1)
+			}
+			delete(c.txMap, hash)
+		}
+	}
+}
+
+// Start polls receipts and tracks blocks in the background, so transactions
+// tracked while the load is still being sent are confirmed as they are mined.
+// Collect takes over from the background poller; Stop ends it without collecting.
+func (c *Collector) Start(ctx context.Context) {
+	if c.pollCancel != nil {
+		return
+	}
+	c.startBlockTracking(ctx)
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	c.pollCancel = cancel
+	c.pollDone = make(chan struct{})
+	go func() {
+		defer close(c.pollDone)
+		c.subscribeHeads(ctx)
+		for pollCtx.Err() == nil {
+			c.collectCycle(pollCtx)
+			c.spillFinished()
+			c.wait(pollCtx)
+		}
+	}()
+}
+
+// Stop ends background polling, the head subscription and block tracking
+func (c *Collector) Stop() {
+	c.stopPolling()
+	c.dropHeads()
+	if c.blockCancel != nil {
+		c.blockCancel()
+		c.blockCancel = nil
+	}
+}
+
+// stopPolling ends the background poller started by Start and waits for it
+func (c *Collector) stopPolling() {
+	if c.pollCancel == nil {
+		return
+	}
+	c.pollCancel()
+	<-c.pollDone
+	c.pollCancel = nil
+	c.pollDone = nil
+}
+
+// startBlockTracking starts block tracking if enabled and not already running
+func (c *Collector) startBlockTracking(ctx context.Context) {
+	if !c.config.BlockTrackingEnabled || c.blockCancel != nil {
+		return
+	}
+	blockCtx, cancel := context.WithCancel(ctx)
+	c.blockCancel = cancel
+	go c.trackBlocks(blockCtx)
+}
+
+// Collect starts the collection process and waits for all transactions.
+// If ctx reaches its deadline, the report so far is returned, marked Partial,
+// together with the context error.
 func (c *Collector) Collect(ctx context.Context) (*Report, error) {
+	c.stopPolling()
+	defer c.Stop()
+
 	c.txMutex.RLock()
-	totalTxs := len(c.txMap)
+	totalTxs := len(c.txMap) + len(c.spilled)
 	c.txMutex.RUnlock()
 
 	if totalTxs == 0 {
 		return NewReport("empty"), nil
 	}
 
-	fmt.Printf("\nStarting Receipt Collection\n\n")
-	fmt.Printf("Total transactions to collect: %d\n", totalTxs)
-	fmt.Printf("Poll interval: %s\n", c.config.PollInterval)
-	fmt.Printf("Confirm timeout: %s\n\n", c.config.ConfirmTimeout)
+	c.subscribeHeads(ctx)
+
+	// Transactions finished by the background poller count as collected
+	collected := totalTxs - int(c.pending.Load())
+
+	console.Printf("\nStarting Receipt Collection\n\n")
+	console.Printf("Total transactions to collect: %d\n", totalTxs)
+	if collected > 0 {
+		console.Printf("Already collected during send: %d\n", collected)
+	}
+	if c.heads != nil {
+		console.Printf("Receipts: per block on new heads\n")
+	} else {
+		console.Printf("Poll interval: %s\n", c.config.PollInterval)
+	}
+	console.Printf("Confirm timeout: %s\n\n", c.config.ConfirmTimeout)
 
 	report := NewReport("stress-test")
 
 	// Create progress bar
-	bar := progressbar.Default(int64(totalTxs), "collecting receipts")
+	bar := progress.New(int64(totalTxs), "collecting receipts")
+	progress.Add(bar, collected)
 
-	// Start block tracking if enabled
-	var blockCtx context.Context
-	var blockCancel context.CancelFunc
-	if c.config.BlockTrackingEnabled {
-		blockCtx, blockCancel = context.WithCancel(ctx)
-		go c.trackBlocks(blockCtx)
-	}
+	c.startBlockTracking(ctx)
 
 	// Collection loop
 	deadline := time.Now().Add(c.config.ConfirmTimeout)
-	collected := 0
+
+	if c.config.EvictionBlocks > 0 {
+		c.markSentBlock(ctx)
+	}
 
 	for collected < totalTxs {
 		if time.Now().After(deadline) {
@@ -123,30 +242,31 @@ func (c *Collector) Collect(ctx context.Context) (*Report, error) {
 			break
 		}
 
-		select {
-		case <-ctx.Done():
-			if blockCancel != nil {
-				blockCancel()
+		if ctx.Err() != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// Report what was collected before the deadline
+				report.Partial = true
+				break
 			}
 			return nil, ctx.Err()
-		default:
 		}
 
 		// Collect pending receipts
-		newCollected := c.collectBatch(ctx)
+		newCollected := c.collectCycle(ctx)
+		if c.config.EvictionBlocks > 0 {
+			newCollected += c.checkEvictions(ctx)
+		}
+		c.spillFinished()
 		if newCollected > 0 {
 			progress.Add(bar, newCollected)
 			collected += newCollected
 		}
 
-		time.Sleep(c.config.PollInterval)
+		c.wait(ctx)
 	}
 
-	if blockCancel != nil {
-		blockCancel()
-	}
-
-	fmt.Println()
+	c.Stop()
+	console.Println()
 
 	// Build report
 	report = c.buildReport(report)
@@ -154,27 +274,56 @@ func (c *Collector) Collect(ctx context.Context) (*Report, error) {
 	// Print summary
 	c.printSummary(report)
 
+	if report.Partial {
+		return report, ctx.Err()
+	}
 	return report, nil
 }
 
+// collectCycle runs one polling cycle and reports its timing to OnCycle
+func (c *Collector) collectCycle(ctx context.Context) int {
+	start := time.Now()
+	var finished int
+	if c.heads != nil {
+		finished = c.collectHeads(ctx)
+	} else {
+		finished = c.collectBatch(ctx)
+	}
+	if c.callbacks != nil && c.callbacks.OnCycle != nil {
+		c.callbacks.OnCycle(start, time.Since(start), finished)
+	}
+	return finished
+}
+
 // collectBatch collects receipts for pending transactions
 func (c *Collector) collectBatch(ctx context.Context) int {
-	// Get pending transactions
+	return c.collectPending(ctx, c.pendingTxs(c.config.BatchSize))
+}
+
+// pendingTxs returns up to limit pending transactions (0 = all)
+func (c *Collector) pendingTxs(limit int) []*TxInfo {
 	c.txMutex.RLock()
+	defer c.txMutex.RUnlock()
 	pending := make([]*TxInfo, 0)
 	for _, tx := range c.txMap {
 		if tx.Status == TxConfirmPending {
 			pending = append(pending, tx)
-			if len(pending) >= c.config.BatchSize {
+			if limit > 0 && len(pending) >= limit {
 				break
 			}
 		}
 	}
-	c.txMutex.RUnlock()
+	return pending
+}
 
+// collectPending queries the receipts of the given pending transactions
+func (c *Collector) collectPending(ctx context.Context, pending []*TxInfo) int {
 	if len(pending) == 0 {
 		return 0
 	}
+	if c.config.FeeDelegation || c.config.L1Fees {
+		return c.collectRaw(pending)
+	}
 
 	// Query receipts concurrently
 	var wg sync.WaitGroup
@@ -195,21 +344,7 @@ func (c *Collector) collectBatch(ctx context.Context) int {
 				return
 			}
 
-			c.txMutex.Lock()
-			info.ConfirmedAt = time.Now()
-			info.Latency = info.ConfirmedAt.Sub(info.SentAt)
-			info.Receipt = receipt
-
-			if receipt.Status == types.ReceiptStatusSuccessful {
-				info.Status = TxConfirmSuccess
-				c.confirmed.Add(1)
-			} else {
-				info.Status = TxConfirmFailed
-				c.failed.Add(1)
-			}
-			c.pending.Add(-1)
-			c.txMutex.Unlock()
-
+			c.recordReceipt(info, receipt, nil)
 			collected.Add(1)
 		}(txInfo)
 	}
@@ -218,6 +353,92 @@ func (c *Collector) collectBatch(ctx context.Context) int {
 	return int(collected.Load())
 }
 
+// recordReceipt confirms a pending transaction with its receipt and the
+// extra fields decoded from it (nil = standard receipt)
+func (c *Collector) recordReceipt(info *TxInfo, receipt *types.Receipt, extras *receiptExtras) {
+	c.txMutex.Lock()
+	info.ConfirmedAt = time.Now()
+	info.Latency = info.ConfirmedAt.Sub(info.SentAt)
+	info.Receipt = receipt
+	if extras != nil {
+		extras.apply(info)
+	}
+	l1Fee := info.L1Fee
+
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		info.Status = TxConfirmSuccess
+		info.EmittedEvent = c.matchEvents(receipt)
+		c.confirmed.Add(1)
+	} else {
+		info.Status = TxConfirmFailed
+		c.failed.Add(1)
+	}
+	c.pending.Add(-1)
+	c.txMutex.Unlock()
+
+	if c.callbacks != nil && c.callbacks.OnReceipt != nil {
+		c.callbacks.OnReceipt(receipt)
+	}
+	if c.budget != nil {
+		if cost := txCost(receipt, l1Fee); cost != nil {
+			c.budget.Settle(info.Hash, cost)
+		}
+	}
+}
+
+// isTracked reports whether a transaction is tracked in memory or was spilled.
+// The caller must hold txMutex.
+func (c *Collector) isTracked(hash common.Hash) bool {
+	if _, ok := c.txMap[hash]; ok {
+		return true
+	}
+	_, ok := c.spilled[hash]
+	return ok
+}
+
+// spillFinished moves finished records to disk while more than MemoryCap are in memory
+func (c *Collector) spillFinished() {
+	if c.config.MemoryCap <= 0 {
+		return
+	}
+
+	c.txMutex.Lock()
+	defer c.txMutex.Unlock()
+
+	if len(c.txMap) <= c.config.MemoryCap {
+		return
+	}
+	finished := make([]*TxInfo, 0, len(c.txMap)-c.config.MemoryCap)
+	for _, tx := range c.txMap {
+		if tx.Status != TxConfirmPending && tx.Status != TxConfirmNotFound {
+			finished = append(finished, tx)
+		}
+	}
+	if len(finished) == 0 {
+		return
+	}
+
+	if c.spill == nil {
+		store, err := openSpillStore(c.config.SpillDir)
+		if err != nil {
+			console.Warnf("Memory cap disabled: %v\n", err)
+			c.config.MemoryCap = 0
+			return
+		}
+		c.spill = store
+		c.spilled = make(map[common.Hash]struct{})
+	}
+	if err := c.spill.put(finished); err != nil {
+		console.Warnf("Memory cap disabled: %v\n", err)
+		c.config.MemoryCap = 0
+		return
+	}
+	for _, tx := range finished {
+		c.spilled[tx.Hash] = struct{}{}
+		delete(c.txMap, tx.Hash)
+	}
+}
+
 // markTimeouts marks remaining pending transactions as timeout
 func (c *Collector) markTimeouts() {
 	c.txMutex.Lock()
@@ -232,6 +453,78 @@ func (c *Collector) markTimeouts() {
 	}
 }
 
+// markSentBlock records the current chain height on transactions that do not have one yet
+func (c *Collector) markSentBlock(ctx context.Context) {
+	blockNum, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return
+	}
+
+	c.txMutex.Lock()
+	defer c.txMutex.Unlock()
+	for _, tx := range c.txMap {
+		if tx.SentBlock == 0 {
+			tx.SentBlock = blockNum
+		}
+	}
+}
+
+// checkEvictions probes transactions that have stayed unmined for at least
+// EvictionBlocks blocks and marks those the node no longer knows as evicted
+func (c *Collector) checkEvictions(ctx context.Context) int {
+	blockNum, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return 0
+	}
+
+	c.txMutex.RLock()
+	candidates := make([]*TxInfo, 0)
+	for _, tx := range c.txMap {
+		if tx.Status == TxConfirmPending && tx.ProbedBlock < blockNum && blockNum >= tx.SentBlock+c.config.EvictionBlocks {
+			candidates = append(candidates, tx)
+			if len(candidates) >= c.config.BatchSize {
+				break
+			}
+		}
+	}
+	c.txMutex.RUnlock()
+
+	if len(candidates) == 0 {
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.config.MaxConcurrent)
+	evicted := atomic.Int32{}
+
+	for _, txInfo := range candidates {
+		wg.Add(1)
+		go func(info *TxInfo) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			_, _, err := c.client.TransactionByHash(ctx, info.Hash)
+
+			c.txMutex.Lock()
+			defer c.txMutex.Unlock()
+			info.ProbedBlock = blockNum
+			if !errors.Is(err, ethereum.NotFound) || info.Status != TxConfirmPending {
+				// Still known to the node (or probe failed), keep waiting
+				return
+			}
+			info.Status = TxConfirmEvicted
+			info.Error = fmt.Errorf("evicted from mempool after %d blocks", blockNum-info.SentBlock)
+			c.pending.Add(-1)
+			evicted.Add(1)
+		}(txInfo)
+	}
+
+	wg.Wait()
+	return int(evicted.Load())
+}
+
 // trackBlocks tracks block-level metrics
 func (c *Collector) trackBlocks(ctx context.Context) {
 	ticker := time.NewTicker(c.config.BlockPollInterval)
@@ -269,6 +562,8 @@ func (c *Collector) trackBlocks(ctx context.Context) {
 						GasUsed:   block.GasUsed(),
 						TxCount:   len(block.Transactions()),
 						BaseFee:   block.BaseFee(),
+						Miner:     block.Coinbase(),
+						ExtraData: block.Extra(),
 					}
 
 					if blockInfo.GasLimit > 0 {
@@ -278,7 +573,7 @@ func (c *Collector) trackBlocks(ctx context.Context) {
 					// Count our transactions in this block
 					c.txMutex.RLock()
 					for _, tx := range block.Transactions() {
-						if _, exists := c.txMap[tx.Hash()]; exists {
+						if c.isTracked(tx.Hash()) {
 							blockInfo.OurTxCount++
 						}
 					}
@@ -312,6 +607,15 @@ func (c *Collector) buildReport(report *Report) *Report {
 	c.applySuccessRate(report)
 	c.applyBlockMetrics(report)
 	c.applyBlockBasedTPS(report)
+	c.applyChainTPS(report)
+	c.applyPeakTPS(report)
+	c.applyLatencyOutliers(report)
+	c.applyUtilizationLatency(report)
+	c.applyLatencyHeatmap(report)
+	c.applyFairness(report)
+	c.applyActivation(report)
+	c.applyProducers(report)
+	c.applyEvents(report)
 
 	return report
 }
@@ -321,19 +625,29 @@ func (c *Collector) populateTransactionMetrics(report *Report) ([]time.Duration,
 	var totalGasUsed uint64
 	totalGasCost := big.NewInt(0)
 
+	// Spilled records move to the report, which reads them back from disk
 	for _, tx := range c.txMap {
 		report.Transactions = append(report.Transactions, tx)
+	}
+	report.spill = c.spill
+	c.spill = nil
+
+	err := report.EachTransaction(func(tx *TxInfo) error {
 		switch tx.Status {
 		case TxConfirmSuccess:
 			report.Metrics.TotalConfirmed++
 			latencies = append(latencies, tx.Latency)
 			if tx.Receipt != nil {
 				totalGasUsed += tx.Receipt.GasUsed
-				cost := new(big.Int).Mul(
-					new(big.Int).SetUint64(tx.Receipt.GasUsed),
-					tx.Receipt.EffectiveGasPrice,
-				)
-				totalGasCost.Add(totalGasCost, cost)
+				if cost := tx.Cost(); cost != nil {
+					totalGasCost.Add(totalGasCost, cost)
+				}
+			}
+			if tx.L1Fee != nil {
+				if report.Metrics.TotalL1Fee == nil {
+					report.Metrics.TotalL1Fee = new(big.Int)
+				}
+				report.Metrics.TotalL1Fee.Add(report.Metrics.TotalL1Fee, tx.L1Fee)
 			}
 		case TxConfirmFailed:
 			report.Metrics.TotalFailed++
@@ -344,12 +658,18 @@ func (c *Collector) populateTransactionMetrics(report *Report) ([]time.Duration,
 			report.Metrics.TotalPending++
 		case TxConfirmTimeout:
 			report.Metrics.TotalTimeout++
+		case TxConfirmEvicted:
+			report.Metrics.TotalEvicted++
 		case TxConfirmNotFound:
 			report.Metrics.TotalPending++
 		}
+		return nil
+	})
+	if err != nil {
+		console.Warnf("Failed to read spilled transactions: %v\n", err)
 	}
 
-	report.Metrics.TotalSent = len(c.txMap)
+	report.Metrics.TotalSent = len(c.txMap) + len(c.spilled)
 	report.Metrics.EndTime = report.EndTime
 	report.Metrics.TotalDuration = report.Duration
 	return latencies, totalGasUsed, totalGasCost
@@ -371,8 +691,8 @@ func (c *Collector) applyTPSMetrics(report *Report) {
 	if report.Duration.Seconds() <= 0 {
 		return
 	}
-	report.Metrics.TPS = float64(report.Metrics.TotalSent) / report.Duration.Seconds()
-	report.Metrics.ConfirmedTPS = float64(report.Metrics.TotalConfirmed) / report.Duration.Seconds()
+	report.Metrics.WallClockTPS = float64(report.Metrics.TotalSent) / report.Duration.Seconds()
+	report.Metrics.WallClockConfirmedTPS = float64(report.Metrics.TotalConfirmed) / report.Duration.Seconds()
 }
 
 func (c *Collector) applyGasMetrics(report *Report, totalGasUsed uint64, totalGasCost *big.Int) {
@@ -451,6 +771,220 @@ func (c *Collector) applyBlockBasedTPS(report *Report) {
 	}
 }
 
+// applyChainTPS computes throughput from block timestamps alone. The window starts at
+// the parent of the first block including our txs, so that block's interval is counted.
+func (c *Collector) applyChainTPS(report *Report) {
+	first, last := -1, -1
+	for i, block := range c.blocks {
+		if block.OurTxCount > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || report.Metrics.TotalConfirmed == 0 {
+		return
+	}
+
+	window := c.blocks[last].Timestamp.Sub(c.parentTimestamp(first, report.Metrics.AvgBlockTime))
+	if window <= 0 {
+		return
+	}
+	report.Metrics.ChainWindow = window
+	report.Metrics.ChainTPS = float64(report.Metrics.TotalConfirmed) / window.Seconds()
+}
+
+// parentTimestamp returns the timestamp of the parent of the i-th observed block.
+// It falls back to the average block time when the parent block was not observed.
+func (c *Collector) parentTimestamp(i int, avgBlockTime time.Duration) time.Time {
+	if i > 0 && c.blocks[i-1].Number+1 == c.blocks[i].Number {
+		return c.blocks[i-1].Timestamp
+	}
+	return c.blocks[i].Timestamp.Add(-avgBlockTime)
+}
+
+// applyPeakTPS finds the PeakWindow of chain time that included the most of our
+// txs. Each candidate window opens at the parent of an observed block, like the
+// chain TPS window. Runs whose chain window is shorter than PeakWindow have no peak.
+func (c *Collector) applyPeakTPS(report *Report) {
+	window := c.config.PeakWindow
+	if window <= 0 || report.Metrics.ChainWindow < window {
+		return
+	}
+
+	for i := range c.blocks {
+		end := c.parentTimestamp(i, report.Metrics.AvgBlockTime).Add(window)
+		included, last := 0, -1
+		for j := i; j < len(c.blocks) && !c.blocks[j].Timestamp.After(end); j++ {
+			included += c.blocks[j].OurTxCount
+			last = j
+		}
+		if last < 0 || included == 0 {
+			continue
+		}
+		if tps := float64(included) / window.Seconds(); tps > report.Metrics.PeakTPS {
+			report.Metrics.PeakTPS = tps
+			report.Metrics.PeakStartBlock = c.blocks[i].Number
+			report.Metrics.PeakEndBlock = c.blocks[last].Number
+		}
+	}
+	if report.Metrics.PeakTPS > 0 {
+		report.Metrics.PeakWindow = window
+	}
+}
+
+// applyLatencyOutliers records the TopTxs slowest and fastest confirmed transactions
+func (c *Collector) applyLatencyOutliers(report *Report) {
+	n := c.config.TopTxs
+	if n <= 0 {
+		return
+	}
+
+	// Keep only the n slowest and fastest so spilled runs stay within memory
+	slowest := make([]*TxInfo, 0, n+1)
+	fastest := make([]*TxInfo, 0, n+1)
+	_ = report.EachTransaction(func(tx *TxInfo) error {
+		if tx.Status != TxConfirmSuccess {
+			return nil
+		}
+		slowest = insertBounded(slowest, tx, n, func(a, b *TxInfo) bool { return a.Latency > b.Latency })
+		fastest = insertBounded(fastest, tx, n, func(a, b *TxInfo) bool { return a.Latency < b.Latency })
+		return nil
+	})
+
+	report.SlowestTxs = slowest
+	report.FastestTxs = fastest
+}
+
+// insertBounded inserts tx into txs, kept sorted by before, and trims it to n entries
+func insertBounded(txs []*TxInfo, tx *TxInfo, n int, before func(a, b *TxInfo) bool) []*TxInfo {
+	i := sort.Search(len(txs), func(i int) bool { return before(tx, txs[i]) })
+	if i >= n {
+		return txs
+	}
+	txs = append(txs, nil)
+	copy(txs[i+1:], txs[i:])
+	txs[i] = tx
+	if len(txs) > n {
+		txs = txs[:n]
+	}
+	return txs
+}
+
+// utilizationBinWidth is the width of each utilization bucket in percentage points
+const utilizationBinWidth = 10
+
+// applyUtilizationLatency bins confirmed transactions by the utilization of the
+// fuller of their inclusion block and the block before it, and records the
+// median and P95 latency of each bin
+func (c *Collector) applyUtilizationLatency(report *Report) {
+	if len(c.blocks) == 0 {
+		return
+	}
+	utilization := make(map[uint64]float64, len(c.blocks))
+	for _, block := range c.blocks {
+		utilization[block.Number] = block.Utilization
+	}
+
+	binCount := 100 / utilizationBinWidth
+	latencies := make([][]time.Duration, binCount)
+	_ = report.EachTransaction(func(tx *TxInfo) error {
+		if tx.Status != TxConfirmSuccess || tx.Receipt == nil || tx.Receipt.BlockNumber == nil {
+			return nil
+		}
+		num := tx.Receipt.BlockNumber.Uint64()
+		util, ok := utilization[num]
+		if !ok {
+			return nil
+		}
+		if prev, ok := utilization[num-1]; ok && prev > util {
+			util = prev
+		}
+		bin := min(int(util)/utilizationBinWidth, binCount-1)
+		latencies[bin] = append(latencies[bin], tx.Latency)
+		return nil
+	})
+
+	for i, bin := range latencies {
+		if len(bin) == 0 {
+			continue
+		}
+		report.UtilizationLatency = append(report.UtilizationLatency, &UtilizationBin{
+			Low:           float64(i * utilizationBinWidth),
+			High:          float64((i + 1) * utilizationBinWidth),
+			Txs:           len(bin),
+			MedianLatency: c.calculatePercentile(bin, 50),
+			P95Latency:    c.calculatePercentile(bin, 95),
+		})
+	}
+}
+
+// applyActivation splits the included transactions at the ActivationBlock.
+// The split is only reported when transactions landed on both sides of it.
+func (c *Collector) applyActivation(report *Report) {
+	height := c.config.ActivationBlock
+	if height == 0 {
+		return
+	}
+
+	before, after := &ActivationPhase{}, &ActivationPhase{}
+	var beforeLatencies, afterLatencies []time.Duration
+	_ = report.EachTransaction(func(tx *TxInfo) error {
+		if tx.Receipt == nil || tx.Receipt.BlockNumber == nil {
+			return nil
+		}
+		phase, latencies := before, &beforeLatencies
+		if tx.Receipt.BlockNumber.Uint64() >= height {
+			phase, latencies = after, &afterLatencies
+		}
+		switch tx.Status {
+		case TxConfirmSuccess:
+			phase.Confirmed++
+			*latencies = append(*latencies, tx.Latency)
+		case TxConfirmFailed:
+			phase.Reverted++
+		}
+		return nil
+	})
+	if before.Confirmed+before.Reverted == 0 || after.Confirmed+after.Reverted == 0 {
+		return
+	}
+
+	for _, phase := range []struct {
+		*ActivationPhase
+		latencies []time.Duration
+		from, to  uint64
+	}{
+		{before, beforeLatencies, 0, height},
+		{after, afterLatencies, height, ^uint64(0)},
+	} {
+		if len(phase.latencies) > 0 {
+			phase.AvgLatency = c.calculateAvgLatency(phase.latencies)
+			phase.P95Latency = c.calculatePercentile(phase.latencies, 95)
+		}
+		first, last := -1, -1
+		for i, block := range c.blocks {
+			if block.OurTxCount == 0 || block.Number < phase.from || block.Number >= phase.to {
+				continue
+			}
+			phase.Blocks++
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+		if first < 0 {
+			continue
+		}
+		window := c.blocks[last].Timestamp.Sub(c.parentTimestamp(first, report.Metrics.AvgBlockTime))
+		if window > 0 {
+			phase.ChainTPS = float64(phase.Confirmed) / window.Seconds()
+		}
+	}
+	report.Activation = &Activation{Block: height, Before: before, After: after}
+}
+
 // calculateAvgLatency calculates average latency
 func (c *Collector) calculateAvgLatency(latencies []time.Duration) time.Duration {
 	var total time.Duration
@@ -502,116 +1036,283 @@ func (c *Collector) calculatePercentile(latencies []time.Duration, p int) time.D
 	return sorted[idx]
 }
 
+// latencyBuckets are the latency histogram buckets, in order. Each holds the
+// latencies below its max; the last one has no upper bound.
+var latencyBuckets = []struct {
+	label string
+	max   time.Duration
+}{
+	{"<100ms", 100 * time.Millisecond},
+	{"100-500ms", 500 * time.Millisecond},
+	{"500ms-1s", 1 * time.Second},
+	{"1-2s", 2 * time.Second},
+	{"2-5s", 5 * time.Second},
+	{">5s", 0},
+}
+
+// latencyBucket returns the index of the latency bucket holding l
+func latencyBucket(l time.Duration) int {
+	for i, bucket := range latencyBuckets {
+		if bucket.max == 0 || l < bucket.max {
+			return i
+		}
+	}
+	return len(latencyBuckets) - 1
+}
+
 // buildLatencyHistogram builds latency distribution histogram
 func (c *Collector) buildLatencyHistogram(latencies []time.Duration) map[string]int {
 	histogram := make(map[string]int)
-	buckets := []struct {
-		label string
-		max   time.Duration
-	}{
-		{"<100ms", 100 * time.Millisecond},
-		{"100-500ms", 500 * time.Millisecond},
-		{"500ms-1s", 1 * time.Second},
-		{"1-2s", 2 * time.Second},
-		{"2-5s", 5 * time.Second},
-		{">5s", 0},
+	for _, l := range latencies {
+		histogram[latencyBuckets[latencyBucket(l)].label]++
 	}
+	return histogram
+}
 
-	for _, l := range latencies {
-		for i, bucket := range buckets {
-			if bucket.max == 0 || l < bucket.max {
-				histogram[bucket.label]++
-				break
-			}
-			if i == len(buckets)-1 {
-				histogram[bucket.label]++
-			}
+// applyLatencyHeatmap counts confirmed transactions per HeatmapInterval of
+// confirmation time and latency bucket, from the first confirmation on
+func (c *Collector) applyLatencyHeatmap(report *Report) {
+	interval := c.config.HeatmapInterval
+	if interval <= 0 {
+		return
+	}
+
+	type confirmation struct {
+		at     time.Time
+		bucket int
+	}
+	var confirmed []confirmation
+	var start time.Time
+	_ = report.EachTransaction(func(tx *TxInfo) error {
+		if tx.Status != TxConfirmSuccess {
+			return nil
 		}
+		at := tx.SentAt.Add(tx.Latency)
+		if start.IsZero() || at.Before(start) {
+			start = at
+		}
+		confirmed = append(confirmed, confirmation{at: at, bucket: latencyBucket(tx.Latency)})
+		return nil
+	})
+	if len(confirmed) == 0 {
+		return
 	}
 
-	return histogram
+	heatmap := &LatencyHeatmap{Interval: interval, Start: start}
+	for _, bucket := range latencyBuckets {
+		heatmap.Buckets = append(heatmap.Buckets, bucket.label)
+	}
+	for _, conf := range confirmed {
+		row := int(conf.at.Sub(start) / interval)
+		for len(heatmap.Rows) <= row {
+			heatmap.Rows = append(heatmap.Rows, &HeatmapRow{
+				Offset: time.Duration(len(heatmap.Rows)) * interval,
+				Counts: make([]int, len(latencyBuckets)),
+			})
+		}
+		heatmap.Rows[row].Counts[conf.bucket]++
+		heatmap.Rows[row].Txs++
+	}
+	report.LatencyHeatmap = heatmap
 }
 
-// printSummary prints the collection summary
+// printSummary prints the collection summary. The tables of per-bucket,
+// per-tx and per-producer detail are only printed at the full summary level.
 func (c *Collector) printSummary(report *Report) {
-	fmt.Printf("\nCollection Summary\n\n")
+	if c.config.SummaryLevel == SummaryMinimal {
+		return
+	}
+	full := c.config.SummaryLevel == SummaryFull
+
+	console.Printf("\nCollection Summary\n\n")
+	if report.Partial {
+		console.Warnf("Partial results: collection stopped at the run deadline\n\n")
+	}
 
 	// Transaction summary
-	fmt.Printf("Transactions:\n")
-	fmt.Printf("  Total Sent:      %d\n", report.Metrics.TotalSent)
-	fmt.Printf("  Confirmed:       %d (%.2f%%)\n", report.Metrics.TotalConfirmed, report.Metrics.SuccessRate)
-	fmt.Printf("  Failed:          %d\n", report.Metrics.TotalFailed)
-	fmt.Printf("  Timeout:         %d\n", report.Metrics.TotalTimeout)
-	fmt.Printf("  Pending:         %d\n", report.Metrics.TotalPending)
+	console.Printf("Transactions:\n")
+	console.Printf("  Total Sent:      %d\n", report.Metrics.TotalSent)
+	console.Printf("  Confirmed:       %d (%.2f%%)\n", report.Metrics.TotalConfirmed, report.Metrics.SuccessRate)
+	console.Printf("  Failed:          %d\n", report.Metrics.TotalFailed)
+	console.Printf("  Timeout:         %d\n", report.Metrics.TotalTimeout)
+	if report.Metrics.TotalEvicted > 0 {
+		console.Printf("  Evicted:         %d\n", report.Metrics.TotalEvicted)
+	}
+	console.Printf("  Pending:         %d\n", report.Metrics.TotalPending)
 
 	// Timing
-	fmt.Printf("\nTiming:\n")
-	fmt.Printf("  Total Duration:  %s\n", report.Duration)
-	fmt.Printf("  TPS (sent):      %.2f\n", report.Metrics.TPS)
-	fmt.Printf("  TPS (confirmed): %.2f\n", report.Metrics.ConfirmedTPS)
+	console.Printf("\nTiming:\n")
+	console.Printf("  Total Duration:  %s\n", report.Duration)
+	if report.Metrics.ChainTPS > 0 {
+		console.Printf("  Chain TPS:       %.2f tx/s (over %s of block time)\n", report.Metrics.ChainTPS, report.Metrics.ChainWindow)
+	} else {
+		console.Printf("  Chain TPS:       n/a (no inclusion blocks observed)\n")
+	}
+	if report.Metrics.PeakTPS > 0 {
+		console.Printf("  Peak TPS:        %.2f tx/s (best %s, blocks #%d-#%d)\n",
+			report.Metrics.PeakTPS, report.Metrics.PeakWindow, report.Metrics.PeakStartBlock, report.Metrics.PeakEndBlock)
+	}
+	console.Printf("  Wall-Clock TPS (sent):      %.2f\n", report.Metrics.WallClockTPS)
+	console.Printf("  Wall-Clock TPS (confirmed): %.2f\n", report.Metrics.WallClockConfirmedTPS)
 
 	// Latency
 	if report.Metrics.TotalConfirmed > 0 {
-		fmt.Printf("\nLatency:\n")
-		fmt.Printf("  Average:         %s\n", report.Metrics.AvgLatency)
-		fmt.Printf("  Min:             %s\n", report.Metrics.MinLatency)
-		fmt.Printf("  Max:             %s\n", report.Metrics.MaxLatency)
-		fmt.Printf("  P50:             %s\n", report.Metrics.P50Latency)
-		fmt.Printf("  P95:             %s\n", report.Metrics.P95Latency)
-		fmt.Printf("  P99:             %s\n", report.Metrics.P99Latency)
+		console.Printf("\nLatency:\n")
+		console.Printf("  Average:         %s\n", report.Metrics.AvgLatency)
+		console.Printf("  Min:             %s\n", report.Metrics.MinLatency)
+		console.Printf("  Max:             %s\n", report.Metrics.MaxLatency)
+		console.Printf("  P50:             %s\n", report.Metrics.P50Latency)
+		console.Printf("  P95:             %s\n", report.Metrics.P95Latency)
+		console.Printf("  P99:             %s\n", report.Metrics.P99Latency)
 	}
 
 	// Gas
 	if report.Metrics.TotalGasUsed > 0 {
-		fmt.Printf("\nGas:\n")
-		fmt.Printf("  Total Used:      %d\n", report.Metrics.TotalGasUsed)
-		fmt.Printf("  Average Used:    %d\n", report.Metrics.AvgGasUsed)
-		fmt.Printf("  Total Cost:      %s wei\n", report.Metrics.TotalGasCost.String())
+		console.Printf("\nGas:\n")
+		console.Printf("  Total Used:      %d\n", report.Metrics.TotalGasUsed)
+		console.Printf("  Average Used:    %d\n", report.Metrics.AvgGasUsed)
+		console.Printf("  Total Cost:      %s\n", c.config.Units.Format(report.Metrics.TotalGasCost))
+		if l1Fee := report.Metrics.TotalL1Fee; l1Fee != nil {
+			execution := new(big.Int).Sub(report.Metrics.TotalGasCost, l1Fee)
+			console.Printf("    Execution:     %s\n", c.config.Units.Format(execution))
+			console.Printf("    L1 Data Fee:   %s\n", c.config.Units.Format(l1Fee))
+		}
 	}
 
 	// Blocks
 	if report.Metrics.BlocksObserved > 0 {
-		fmt.Printf("\nBlocks:\n")
-		fmt.Printf("  Observed:        %d\n", report.Metrics.BlocksObserved)
-		fmt.Printf("  Avg Block Time:  %s\n", report.Metrics.AvgBlockTime)
-		fmt.Printf("  Avg Tx/Block:    %.2f\n", report.Metrics.AvgTxPerBlock)
-		fmt.Printf("  Avg Utilization: %.2f%%\n", report.Metrics.AvgUtilization)
+		console.Printf("\nBlocks:\n")
+		console.Printf("  Observed:        %d\n", report.Metrics.BlocksObserved)
+		console.Printf("  Avg Block Time:  %s\n", report.Metrics.AvgBlockTime)
+		console.Printf("  Avg Tx/Block:    %.2f\n", report.Metrics.AvgTxPerBlock)
+		console.Printf("  Avg Utilization: %.2f%%\n", report.Metrics.AvgUtilization)
 
 		// Block-based TPS (real throughput)
-		if report.Metrics.BlockSpan > 0 {
-			fmt.Printf("\nBlock-Based Throughput:\n")
-			fmt.Printf("  First Block:     #%d\n", report.Metrics.FirstBlockWithTx)
-			fmt.Printf("  Last Block:      #%d\n", report.Metrics.LastBlockWithTx)
-			fmt.Printf("  Block Span:      %d blocks\n", report.Metrics.BlockSpan)
-			fmt.Printf("  Blocks w/ Tx:    %d blocks\n", report.Metrics.BlocksWithOurTx)
-			fmt.Printf("  Block-Based TPS: %.2f tx/s\n", report.Metrics.BlockBasedTPS)
+		if full && report.Metrics.BlockSpan > 0 {
+			console.Printf("\nBlock-Based Throughput:\n")
+			console.Printf("  First Block:     #%d\n", report.Metrics.FirstBlockWithTx)
+			console.Printf("  Last Block:      #%d\n", report.Metrics.LastBlockWithTx)
+			console.Printf("  Block Span:      %d blocks\n", report.Metrics.BlockSpan)
+			console.Printf("  Blocks w/ Tx:    %d blocks\n", report.Metrics.BlocksWithOurTx)
+			console.Printf("  Block-Based TPS: %.2f tx/s\n", report.Metrics.BlockBasedTPS)
 		}
 	}
 
 	// Latency histogram
-	if len(report.LatencyHistogram) > 0 {
-		fmt.Printf("\nLatency Distribution:\n")
+	if full && len(report.LatencyHistogram) > 0 {
+		console.Printf("\nLatency Distribution:\n")
 		bucketOrder := []string{"<100ms", "100-500ms", "500ms-1s", "1-2s", "2-5s", ">5s"}
 		for _, bucket := range bucketOrder {
 			if count, ok := report.LatencyHistogram[bucket]; ok {
 				pct := float64(count) / float64(report.Metrics.TotalConfirmed) * 100
-				fmt.Printf("  %-12s %5d (%.1f%%)\n", bucket, count, pct)
+				console.Printf("  %-12s %5d (%.1f%%)\n", bucket, count, pct)
 			}
 		}
 	}
 
+	// Latency by block utilization
+	if full && len(report.UtilizationLatency) > 0 {
+		console.Printf("\nLatency by Block Utilization:\n")
+		console.Printf("  %-12s %8s %12s %12s\n", "Utilization", "Txs", "Median", "P95")
+		for _, bin := range report.UtilizationLatency {
+			console.Printf("  %-12s %8d %12s %12s\n", fmt.Sprintf("%.0f-%.0f%%", bin.Low, bin.High), bin.Txs,
+				bin.MedianLatency.Round(time.Millisecond), bin.P95Latency.Round(time.Millisecond))
+		}
+	}
+
+	// Latency outliers
+	if full && len(report.SlowestTxs) > 0 {
+		console.Printf("\nSlowest Transactions:\n")
+		c.printTxOutliers(report.SlowestTxs)
+		console.Printf("\nFastest Transactions:\n")
+		c.printTxOutliers(report.FastestTxs)
+	}
+
+	// Inclusion order
+	if f := report.Fairness; full && f != nil {
+		c.printFairness(f)
+	}
+
+	// Before and after the activation block
+	if a := report.Activation; a != nil {
+		c.printActivation(a)
+	}
+
+	// Inclusion by block producer
+	if full && len(report.Producers) > 0 {
+		c.printProducers(report.Producers)
+	}
+
+	// Semantic success
+	if e := report.Events; e != nil {
+		c.printEvents(e)
+	}
+
 	// Errors
 	if len(report.ErrorSummary) > 0 {
-		fmt.Printf("\n[WARN] Errors:\n")
+		console.Warnf("\nErrors:\n")
 		for errMsg, count := range report.ErrorSummary {
 			if len(errMsg) > 50 {
 				errMsg = errMsg[:47] + "..."
 			}
-			fmt.Printf("  %s: %d\n", errMsg, count)
+			console.Summaryf("  %s: %d\n", errMsg, count)
 		}
 	}
 }
 
+// printTxOutliers prints transactions with their sender, nonce, block and gas price
+func (c *Collector) printTxOutliers(txs []*TxInfo) {
+	for _, tx := range txs {
+		var block uint64
+		gasPrice := "-"
+		if tx.Receipt != nil {
+			if tx.Receipt.BlockNumber != nil {
+				block = tx.Receipt.BlockNumber.Uint64()
+			}
+			if tx.Receipt.EffectiveGasPrice != nil {
+				gasPrice = c.config.Units.Format(tx.Receipt.EffectiveGasPrice)
+			}
+		}
+		console.Printf("  %-10s %s  from %s  nonce %-6d block #%-8d gas price %s\n",
+			tx.Latency.Round(time.Millisecond), tx.Hash.Hex()[:18], tx.From.Hex(), tx.Nonce, block, gasPrice)
+	}
+}
+
+// printActivation prints the metrics before and from the activation block
+func (c *Collector) printActivation(a *Activation) {
+	console.Printf("\nActivation at Block #%d:\n", a.Block)
+	console.Printf("  %-8s %10s %9s %7s %12s %12s %10s\n", "Phase", "Confirmed", "Reverted", "Blocks", "Avg Latency", "P95 Latency", "Chain TPS")
+	for _, phase := range []struct {
+		name string
+		*ActivationPhase
+	}{{"Before", a.Before}, {"After", a.After}} {
+		console.Printf("  %-8s %10d %9d %7d %12s %12s %10.2f\n", phase.name, phase.Confirmed, phase.Reverted, phase.Blocks,
+			phase.AvgLatency.Round(time.Millisecond), phase.P95Latency.Round(time.Millisecond), phase.ChainTPS)
+	}
+}
+
+// printFairness prints how inclusion order relates to submission time, tip and sender
+func (c *Collector) printFairness(f *Fairness) {
+	console.Printf("\nInclusion Order Fairness:\n")
+	if f.Pairs > 0 {
+		console.Printf("  FIFO Score:      %.2f%% (%d of %d cross-sender pairs out of submission order)\n",
+			f.Score, f.Inversions, f.Pairs)
+	} else {
+		console.Printf("  FIFO Score:      n/a (no txs from different senders submitted at different times)\n")
+	}
+	if f.TipPairs > 0 {
+		console.Printf("  Tip Ordering:    %.2f%% of %d same-block pairs put the higher tip first\n", f.TipOrdered, f.TipPairs)
+	}
+	if f.SenderBias > 0 {
+		console.Printf("  Sender Bias:     %.2f (favored %s)\n", f.SenderBias, f.FavoredSender.Hex())
+	}
+	for _, inv := range f.Examples {
+		console.Printf("  %s at #%d/%d included after %s at #%d/%d, submitted %s earlier\n",
+			inv.Early.Hex()[:18], inv.EarlyBlock, inv.EarlyIndex, inv.Late.Hex()[:18], inv.LateBlock, inv.LateIndex,
+			inv.SentGap.Round(time.Millisecond))
+	}
+}
+
 // GetConfirmedCount returns the number of confirmed transactions
 func (c *Collector) GetConfirmedCount() int64 {
 	return c.confirmed.Load()
@@ -629,8 +1330,15 @@ func (c *Collector) GetPendingCount() int64 {
 
 // Reset resets the collector state
 func (c *Collector) Reset() {
+	c.Stop()
+
 	c.txMutex.Lock()
 	c.txMap = make(map[common.Hash]*TxInfo)
+	if c.spill != nil {
+		_ = c.spill.close()
+		c.spill = nil
+	}
+	c.spilled = nil
 	c.txMutex.Unlock()
 
 	c.blockMu.Lock()
@@ -1,20 +1,32 @@
 package collector
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"math"
 	"math/big"
+	"math/rand"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
 )
 
 // mockCollectorClient implements Client interface for testing
 type mockCollectorClient struct {
 	receipts    map[common.Hash]*types.Receipt
+	pendingTxs  map[common.Hash]bool
 	blocks      map[uint64]*types.Block
 	blockNumber uint64
 	receiptErr  error
@@ -25,6 +37,7 @@ type mockCollectorClient struct {
 func newMockCollectorClient() *mockCollectorClient {
 	return &mockCollectorClient{
 		receipts:    make(map[common.Hash]*types.Receipt),
+		pendingTxs:  make(map[common.Hash]bool),
 		blocks:      make(map[uint64]*types.Block),
 		blockNumber: 1000,
 	}
@@ -69,6 +82,16 @@ func (m *mockCollectorClient) BlockNumber(ctx context.Context) (uint64, error) {
 	return m.blockNumber, nil
 }
 
+func (m *mockCollectorClient) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	if m.pendingTxs[txHash] {
+		return types.NewTx(&types.LegacyTx{}), true, nil
+	}
+	if _, ok := m.receipts[txHash]; ok {
+		return types.NewTx(&types.LegacyTx{}), false, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
 func (m *mockCollectorClient) BatchCall(batch []rpc.BatchElem) error {
 	return nil
 }
@@ -93,6 +116,7 @@ func TestTxConfirmStatus_String(t *testing.T) {
 		{TxConfirmFailed, "FAILED"},
 		{TxConfirmTimeout, "TIMEOUT"},
 		{TxConfirmNotFound, "NOT_FOUND"},
+		{TxConfirmEvicted, "EVICTED"},
 		{TxConfirmStatus(99), "UNKNOWN"},
 	}
 
@@ -237,6 +261,340 @@ func TestCollector_Collect_WithReceipts(t *testing.T) {
 	}
 }
 
+// rawReceiptClient answers batched eth_getTransactionReceipt calls with raw JSON receipts
+type rawReceiptClient struct {
+	*mockCollectorClient
+	raw map[common.Hash]string
+}
+
+func (m *rawReceiptClient) BatchCall(batch []rpc.BatchElem) error {
+	for i := range batch {
+		raw, ok := m.raw[batch[i].Args[0].(common.Hash)]
+		if !ok {
+			raw = "null"
+		}
+		*batch[i].Result.(*json.RawMessage) = json.RawMessage(raw)
+	}
+	return nil
+}
+
+// feeDelegatedReceipt returns the JSON of a StableNet fee-delegated receipt
+func feeDelegatedReceipt(t *testing.T, hash common.Hash, feePayer string) string {
+	data, err := json.Marshal(&types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		GasUsed:           21000,
+		TxHash:            hash,
+		Logs:              []*types.Log{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	fields["type"] = "0x16"
+	if feePayer != "" {
+		fields["feePayer"] = feePayer
+		fields["feePayerSignatures"] = []any{map[string]string{"V": "0x1", "R": "0x2", "S": "0x3"}}
+	}
+	data, err = json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCollector_Collect_FeeDelegated(t *testing.T) {
+	hash1 := common.HexToHash("0x1111")
+	hash2 := common.HexToHash("0x2222")
+	hash3 := common.HexToHash("0x3333")
+	feePayer := common.HexToAddress("0xfee")
+	client := &rawReceiptClient{
+		mockCollectorClient: newMockCollectorClient(),
+		raw: map[common.Hash]string{
+			hash1: feeDelegatedReceipt(t, hash1, feePayer.Hex()),
+			hash2: feeDelegatedReceipt(t, hash2, ""),
+		},
+	}
+
+	collector := New(client, &Config{MaxConcurrent: 5, BatchSize: 10, FeeDelegation: true})
+	for i, hash := range []common.Hash{hash1, hash2, hash3} {
+		collector.TrackTransaction(hash, common.Address{}, uint64(i), 21000, time.Now())
+	}
+
+	if got := collector.collectBatch(context.Background()); got != 2 {
+		t.Fatalf("collectBatch() = %d, want 2", got)
+	}
+	if tx := collector.txMap[hash1]; tx.Status != TxConfirmSuccess || tx.FeePayer != feePayer || tx.Receipt.Type != 0x16 {
+		t.Errorf("tx 1 = %v, fee payer %s, type %d", tx.Status, tx.FeePayer.Hex(), tx.Receipt.Type)
+	}
+	if tx := collector.txMap[hash2]; tx.Status != TxConfirmSuccess || tx.FeePayer != (common.Address{}) {
+		t.Errorf("tx 2 = %v, fee payer %s", tx.Status, tx.FeePayer.Hex())
+	}
+	if tx := collector.txMap[hash3]; tx.Status != TxConfirmPending {
+		t.Errorf("tx 3 = %v, want pending", tx.Status)
+	}
+
+	// The fee payer survives a spill to disk
+	rec := toSpilledTx(collector.txMap[hash1]).toTxInfo()
+	if rec.FeePayer != feePayer {
+		t.Errorf("spilled fee payer = %s, want %s", rec.FeePayer.Hex(), feePayer.Hex())
+	}
+}
+
+// rollupReceipt returns the JSON of a receipt paying 1 gwei per gas, with
+// the given extra fields of a rollup receipt
+func rollupReceipt(t *testing.T, hash common.Hash, extra map[string]any) string {
+	data, err := json.Marshal(&types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(1000000000),
+		TxHash:            hash,
+		Logs:              []*types.Log{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	data, err = json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCollector_Collect_L1Fees(t *testing.T) {
+	hash1 := common.HexToHash("0x1111")
+	hash2 := common.HexToHash("0x2222")
+	client := &rawReceiptClient{
+		mockCollectorClient: newMockCollectorClient(),
+		raw: map[common.Hash]string{
+			hash1: rollupReceipt(t, hash1, map[string]any{"l1Fee": "0x5f5e100", "l1GasUsed": "0x640", "l1FeeScalar": "0.684"}),
+			hash2: rollupReceipt(t, hash2, nil),
+		},
+	}
+
+	collector := New(client, &Config{MaxConcurrent: 5, BatchSize: 10, L1Fees: true})
+	for i, hash := range []common.Hash{hash1, hash2} {
+		collector.TrackTransaction(hash, common.Address{}, uint64(i), 21000, time.Now())
+	}
+	if got := collector.collectBatch(context.Background()); got != 2 {
+		t.Fatalf("collectBatch() = %d, want 2", got)
+	}
+
+	tx := collector.txMap[hash1]
+	if tx.L1Fee == nil || tx.L1Fee.Int64() != 100000000 || tx.L1GasUsed != 1600 {
+		t.Errorf("tx 1 L1 fee = %v, L1 gas = %d", tx.L1Fee, tx.L1GasUsed)
+	}
+	if cost := tx.Cost(); cost == nil || cost.String() != "21000100000000" {
+		t.Errorf("tx 1 cost = %v, want 21000100000000", cost)
+	}
+	if tx := collector.txMap[hash2]; tx.L1Fee != nil || tx.Cost().String() != "21000000000000" {
+		t.Errorf("tx 2 L1 fee = %v, cost = %v", tx.L1Fee, tx.Cost())
+	}
+
+	report := collector.buildReport(NewReport("l1"))
+	if report.Metrics.TotalGasCost.String() != "42000100000000" {
+		t.Errorf("TotalGasCost = %s, want 42000100000000", report.Metrics.TotalGasCost)
+	}
+	if report.Metrics.TotalL1Fee == nil || report.Metrics.TotalL1Fee.Int64() != 100000000 {
+		t.Errorf("TotalL1Fee = %v, want 100000000", report.Metrics.TotalL1Fee)
+	}
+	jr := NewJSONReport(report)
+	if jr.Gas.ExecutionCost != "42000000000000" || jr.Gas.L1Fee != "100000000" {
+		t.Errorf("JSON gas = %+v", jr.Gas)
+	}
+
+	// The L1 fee survives a spill to disk
+	if rec := toSpilledTx(tx).toTxInfo(); rec.L1Fee == nil || rec.L1Fee.Cmp(tx.L1Fee) != 0 || rec.L1GasUsed != 1600 {
+		t.Errorf("spilled L1 fee = %v, L1 gas = %d", rec.L1Fee, rec.L1GasUsed)
+	}
+}
+
+func TestCollector_Start_CollectsWhileSending(t *testing.T) {
+	client := newMockCollectorClient()
+	hash1 := common.HexToHash("0x1111")
+	hash2 := common.HexToHash("0x2222")
+	client.addReceipt(hash1, types.ReceiptStatusSuccessful, 21000)
+	client.addReceipt(hash2, types.ReceiptStatusSuccessful, 21000)
+
+	cfg := &Config{
+		PollInterval:   10 * time.Millisecond,
+		ConfirmTimeout: 1 * time.Second,
+		MaxConcurrent:  5,
+		BatchSize:      10,
+	}
+	collector := New(client, cfg)
+	collector.Start(context.Background())
+
+	// A tx tracked after Start is confirmed by the background poller
+	collector.TrackTransaction(hash1, common.Address{}, 0, 21000, time.Now())
+	deadline := time.Now().Add(time.Second)
+	for collector.GetConfirmedCount() < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("background poller did not confirm the tracked tx")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Collect takes over and finishes the rest
+	collector.TrackTransaction(hash2, common.Address{}, 1, 21000, time.Now())
+	report, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if report.Metrics.TotalConfirmed != 2 {
+		t.Errorf("TotalConfirmed = %d, want 2", report.Metrics.TotalConfirmed)
+	}
+	if collector.pollCancel != nil {
+		t.Error("background poller still running after Collect")
+	}
+}
+
+// headClient serves newHeads subscriptions and eth_getBlockReceipts
+type headClient struct {
+	*mockCollectorClient
+	heads         chan<- *types.Header
+	blockReceipts map[uint64][]string
+	unsupported   bool
+	receiptCalls  int
+}
+
+func (m *headClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	m.receiptCalls++
+	return m.mockCollectorClient.TransactionReceipt(ctx, txHash)
+}
+
+func (m *headClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	m.heads = ch
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	}), nil
+}
+
+func (m *headClient) BatchCall(batch []rpc.BatchElem) error {
+	if m.unsupported {
+		batch[0].Error = methodNotFoundError{}
+		return nil
+	}
+	number, err := hexutil.DecodeUint64(batch[0].Args[0].(string))
+	if err != nil {
+		return err
+	}
+	raws := batch[0].Result.(*[]json.RawMessage)
+	for _, raw := range m.blockReceipts[number] {
+		*raws = append(*raws, json.RawMessage(raw))
+	}
+	if *raws == nil {
+		*raws = []json.RawMessage{}
+	}
+	return nil
+}
+
+// methodNotFoundError is the JSON-RPC error of a method the node does not serve
+type methodNotFoundError struct{}
+
+func (methodNotFoundError) Error() string  { return "the method eth_getBlockReceipts does not exist" }
+func (methodNotFoundError) ErrorCode() int { return -32601 }
+
+func TestCollector_SubscribeHeads(t *testing.T) {
+	hash1 := common.HexToHash("0x1111")
+	hash2 := common.HexToHash("0x2222")
+	hash3 := common.HexToHash("0x3333")
+	client := &headClient{
+		mockCollectorClient: newMockCollectorClient(),
+		blockReceipts: map[uint64][]string{
+			1001: {rollupReceipt(t, hash2, nil), rollupReceipt(t, common.HexToHash("0x9999"), nil)},
+			1003: {rollupReceipt(t, hash3, nil)},
+		},
+	}
+	// Mined before the subscription, found by the catch-up poll
+	client.addReceipt(hash1, types.ReceiptStatusSuccessful, 21000)
+
+	collector := New(client, &Config{PollInterval: time.Second, MaxConcurrent: 5, BatchSize: 10, SubscribeHeads: true})
+	for i, hash := range []common.Hash{hash1, hash2, hash3} {
+		collector.TrackTransaction(hash, common.Address{}, uint64(i), 21000, time.Now())
+	}
+	ctx := context.Background()
+	collector.subscribeHeads(ctx)
+	defer collector.Stop()
+	if collector.heads == nil {
+		t.Fatal("subscribeHeads() did not subscribe")
+	}
+	if got := collector.GetConfirmedCount(); got != 1 {
+		t.Fatalf("confirmed after catch-up = %d, want 1", got)
+	}
+	calls := client.receiptCalls
+
+	// No new head, no queries
+	if got := collector.collectCycle(ctx); got != 0 {
+		t.Errorf("collectCycle() without heads = %d, want 0", got)
+	}
+
+	// The head wakes the wait, and a skipped head is fetched too
+	client.heads <- &types.Header{Number: big.NewInt(1003)}
+	start := time.Now()
+	collector.wait(ctx)
+	if waited := time.Since(start); waited > 500*time.Millisecond {
+		t.Errorf("wait() took %s, want to return on the new head", waited)
+	}
+	if got := collector.collectCycle(ctx); got != 2 {
+		t.Fatalf("collectCycle() = %d, want 2", got)
+	}
+	if client.receiptCalls != calls {
+		t.Errorf("%d receipt queries after the catch-up, want none", client.receiptCalls-calls)
+	}
+	for _, hash := range []common.Hash{hash2, hash3} {
+		if tx := collector.txMap[hash]; tx.Status != TxConfirmSuccess {
+			t.Errorf("tx %s = %v, want success", hash.Hex(), tx.Status)
+		}
+	}
+
+	collector.Stop()
+	if collector.heads != nil {
+		t.Error("subscription still held after Stop")
+	}
+}
+
+func TestCollector_SubscribeHeads_NoBlockReceipts(t *testing.T) {
+	hash := common.HexToHash("0x1111")
+	client := &headClient{mockCollectorClient: newMockCollectorClient(), unsupported: true}
+
+	collector := New(client, &Config{MaxConcurrent: 5, BatchSize: 10, SubscribeHeads: true})
+	collector.TrackTransaction(hash, common.Address{}, 0, 21000, time.Now())
+	ctx := context.Background()
+	collector.subscribeHeads(ctx)
+	defer collector.Stop()
+
+	// Mined after the subscription: polled once the head arrives
+	client.addReceipt(hash, types.ReceiptStatusSuccessful, 21000)
+	client.heads <- &types.Header{Number: big.NewInt(1001)}
+	if got := collector.collectCycle(ctx); got != 1 {
+		t.Fatalf("collectCycle() = %d, want 1", got)
+	}
+	if collector.heads == nil || collector.heads.blockReceipts {
+		t.Error("want the subscription kept with eth_getBlockReceipts off")
+	}
+}
+
+func TestCollector_SubscribeHeads_Unsupported(t *testing.T) {
+	collector := New(newMockCollectorClient(), &Config{BatchSize: 10, SubscribeHeads: true})
+	collector.subscribeHeads(context.Background())
+	if collector.heads != nil || !collector.headsFailed {
+		t.Error("want polling for a client that cannot subscribe")
+	}
+}
+
 func TestCollector_Collect_WithFailedReceipts(t *testing.T) {
 	client := newMockCollectorClient()
 
@@ -291,6 +649,40 @@ func TestCollector_Collect_Timeout(t *testing.T) {
 	}
 }
 
+func TestCollector_Collect_Deadline(t *testing.T) {
+	client := newMockCollectorClient()
+
+	cfg := &Config{
+		PollInterval:   10 * time.Millisecond,
+		ConfirmTimeout: 10 * time.Second,
+		MaxConcurrent:  5,
+		BatchSize:      10,
+	}
+	collector := New(client, cfg)
+
+	confirmed := common.HexToHash("0x5555")
+	collector.TrackTransaction(confirmed, common.Address{}, 0, 21000, time.Now())
+	client.addReceipt(confirmed, types.ReceiptStatusSuccessful, 21000)
+	collector.TrackTransaction(common.HexToHash("0x6666"), common.Address{}, 1, 21000, time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	report, err := collector.Collect(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Collect() error = %v, want deadline exceeded", err)
+	}
+	if report == nil || !report.Partial {
+		t.Fatalf("expected a partial report, got %+v", report)
+	}
+	if report.Metrics.TotalConfirmed != 1 {
+		t.Errorf("TotalConfirmed = %d, want 1", report.Metrics.TotalConfirmed)
+	}
+	if report.Metrics.TotalPending != 1 {
+		t.Errorf("TotalPending = %d, want 1", report.Metrics.TotalPending)
+	}
+}
+
 func TestCollector_GetCounts(t *testing.T) {
 	client := newMockCollectorClient()
 	collector := New(client, DefaultConfig())
@@ -575,17 +967,17 @@ func TestBlockInfo(t *testing.T) {
 // Tests for Metrics
 func TestMetrics(t *testing.T) {
 	metrics := &Metrics{
-		TotalSent:      100,
-		TotalConfirmed: 95,
-		TotalFailed:    3,
-		TotalPending:   1,
-		TotalTimeout:   1,
-		TPS:            50.0,
-		ConfirmedTPS:   47.5,
-		SuccessRate:    95.0,
-		TotalGasUsed:   2100000,
-		AvgGasUsed:     21000,
-		BlocksObserved: 10,
+		TotalSent:             100,
+		TotalConfirmed:        95,
+		TotalFailed:           3,
+		TotalPending:          1,
+		TotalTimeout:          1,
+		WallClockTPS:          50.0,
+		WallClockConfirmedTPS: 47.5,
+		SuccessRate:           95.0,
+		TotalGasUsed:          2100000,
+		AvgGasUsed:            21000,
+		BlocksObserved:        10,
 	}
 
 	if metrics.TotalSent != 100 {
@@ -603,11 +995,11 @@ func TestMetrics(t *testing.T) {
 	if metrics.TotalTimeout != 1 {
 		t.Errorf("TotalTimeout = %d, want 1", metrics.TotalTimeout)
 	}
-	if metrics.TPS != 50.0 {
-		t.Errorf("TPS = %f, want 50.0", metrics.TPS)
+	if metrics.WallClockTPS != 50.0 {
+		t.Errorf("WallClockTPS = %f, want 50.0", metrics.WallClockTPS)
 	}
-	if metrics.ConfirmedTPS != 47.5 {
-		t.Errorf("ConfirmedTPS = %f, want 47.5", metrics.ConfirmedTPS)
+	if metrics.WallClockConfirmedTPS != 47.5 {
+		t.Errorf("WallClockConfirmedTPS = %f, want 47.5", metrics.WallClockConfirmedTPS)
 	}
 	if metrics.SuccessRate != 95.0 {
 		t.Errorf("SuccessRate = %f, want 95.0", metrics.SuccessRate)
@@ -653,3 +1045,546 @@ func TestConfig(t *testing.T) {
 		t.Errorf("BlockPollInterval = %v, want 2s", cfg.BlockPollInterval)
 	}
 }
+
+func TestCollector_CheckEvictions(t *testing.T) {
+	client := newMockCollectorClient()
+	cfg := DefaultConfig()
+	cfg.EvictionBlocks = 5
+	collector := New(client, cfg)
+
+	evicted := common.HexToHash("0x01")
+	stillPending := common.HexToHash("0x02")
+	tooRecent := common.HexToHash("0x03")
+	client.pendingTxs[stillPending] = true
+
+	collector.TrackTransaction(evicted, common.Address{}, 0, 21000, time.Now())
+	collector.TrackTransaction(stillPending, common.Address{}, 1, 21000, time.Now())
+	collector.TrackTransaction(tooRecent, common.Address{}, 2, 21000, time.Now())
+
+	collector.txMap[evicted].SentBlock = 990
+	collector.txMap[stillPending].SentBlock = 990
+	collector.txMap[tooRecent].SentBlock = 998
+
+	if got := collector.checkEvictions(context.Background()); got != 1 {
+		t.Fatalf("checkEvictions() = %d, want 1", got)
+	}
+	if collector.txMap[evicted].Status != TxConfirmEvicted {
+		t.Errorf("evicted tx status = %v, want EVICTED", collector.txMap[evicted].Status)
+	}
+	if collector.txMap[stillPending].Status != TxConfirmPending {
+		t.Errorf("pending tx status = %v, want PENDING", collector.txMap[stillPending].Status)
+	}
+	if collector.txMap[tooRecent].Status != TxConfirmPending {
+		t.Errorf("recent tx status = %v, want PENDING", collector.txMap[tooRecent].Status)
+	}
+	if collector.GetPendingCount() != 2 {
+		t.Errorf("GetPendingCount() = %d, want 2", collector.GetPendingCount())
+	}
+
+	// Already probed at this height: no further RPC work
+	if got := collector.checkEvictions(context.Background()); got != 0 {
+		t.Errorf("second checkEvictions() = %d, want 0", got)
+	}
+}
+
+func TestCollector_ApplyChainTPS(t *testing.T) {
+	collector := New(newMockCollectorClient(), nil)
+	base := time.Unix(1700000000, 0)
+	collector.blocks = []*BlockInfo{
+		{Number: 10, Timestamp: base},
+		{Number: 11, Timestamp: base.Add(2 * time.Second), OurTxCount: 40},
+		{Number: 12, Timestamp: base.Add(4 * time.Second), OurTxCount: 40},
+		{Number: 13, Timestamp: base.Add(6 * time.Second)},
+	}
+
+	report := NewReport("chain-tps")
+	report.Metrics.TotalConfirmed = 80
+	report.Metrics.AvgBlockTime = 2 * time.Second
+	collector.applyChainTPS(report)
+
+	// Window runs from parent block #10 to last inclusion block #12
+	if report.Metrics.ChainWindow != 4*time.Second {
+		t.Errorf("ChainWindow = %v, want 4s", report.Metrics.ChainWindow)
+	}
+	if report.Metrics.ChainTPS != 20 {
+		t.Errorf("ChainTPS = %v, want 20", report.Metrics.ChainTPS)
+	}
+
+	// Without the parent block, the average block time stands in for its interval
+	collector.blocks = collector.blocks[1:]
+	report = NewReport("chain-tps")
+	report.Metrics.TotalConfirmed = 80
+	report.Metrics.AvgBlockTime = 2 * time.Second
+	collector.applyChainTPS(report)
+	if report.Metrics.ChainWindow != 4*time.Second {
+		t.Errorf("ChainWindow without parent = %v, want 4s", report.Metrics.ChainWindow)
+	}
+}
+
+func TestCollector_ApplyPeakTPS(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PeakWindow = 4 * time.Second
+	collector := New(newMockCollectorClient(), cfg)
+	base := time.Unix(1700000000, 0)
+	collector.blocks = []*BlockInfo{
+		{Number: 10, Timestamp: base},
+		{Number: 11, Timestamp: base.Add(2 * time.Second), OurTxCount: 10},
+		{Number: 12, Timestamp: base.Add(4 * time.Second), OurTxCount: 50},
+		{Number: 13, Timestamp: base.Add(6 * time.Second), OurTxCount: 70},
+		{Number: 14, Timestamp: base.Add(8 * time.Second), OurTxCount: 20},
+		{Number: 15, Timestamp: base.Add(10 * time.Second)},
+	}
+
+	report := NewReport("peak-tps")
+	report.Metrics.AvgBlockTime = 2 * time.Second
+	report.Metrics.ChainWindow = 8 * time.Second
+	collector.applyPeakTPS(report)
+
+	// Blocks #12 and #13 fill the best 4s window: 120 txs / 4s
+	if report.Metrics.PeakTPS != 30 {
+		t.Errorf("PeakTPS = %v, want 30", report.Metrics.PeakTPS)
+	}
+	if report.Metrics.PeakStartBlock != 12 || report.Metrics.PeakEndBlock != 13 {
+		t.Errorf("peak blocks = #%d-#%d, want #12-#13", report.Metrics.PeakStartBlock, report.Metrics.PeakEndBlock)
+	}
+	if report.Metrics.PeakWindow != 4*time.Second {
+		t.Errorf("PeakWindow = %v, want 4s", report.Metrics.PeakWindow)
+	}
+
+	// A run shorter than the window has no peak
+	report = NewReport("peak-tps")
+	report.Metrics.AvgBlockTime = 2 * time.Second
+	report.Metrics.ChainWindow = 3 * time.Second
+	collector.applyPeakTPS(report)
+	if report.Metrics.PeakTPS != 0 || report.Metrics.PeakWindow != 0 {
+		t.Errorf("short run peak = %v over %v, want none", report.Metrics.PeakTPS, report.Metrics.PeakWindow)
+	}
+}
+
+func TestCollector_ApplyLatencyOutliers(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TopTxs = 2
+	collector := New(newMockCollectorClient(), cfg)
+
+	report := NewReport("outliers")
+	for i, ms := range []int{300, 100, 500, 200, 400} {
+		report.Transactions = append(report.Transactions, &TxInfo{
+			Nonce:   uint64(i),
+			Status:  TxConfirmSuccess,
+			Latency: time.Duration(ms) * time.Millisecond,
+		})
+	}
+	report.Transactions = append(report.Transactions, &TxInfo{Nonce: 9, Status: TxConfirmTimeout, Latency: time.Hour})
+	report.Metrics.TotalConfirmed = 5
+
+	collector.applyLatencyOutliers(report)
+
+	if len(report.SlowestTxs) != 2 || report.SlowestTxs[0].Nonce != 2 || report.SlowestTxs[1].Nonce != 4 {
+		t.Errorf("unexpected slowest txs: %+v", report.SlowestTxs)
+	}
+	if len(report.FastestTxs) != 2 || report.FastestTxs[0].Nonce != 1 || report.FastestTxs[1].Nonce != 3 {
+		t.Errorf("unexpected fastest txs: %+v", report.FastestTxs)
+	}
+}
+
+func TestCollector_ApplyUtilizationLatency(t *testing.T) {
+	collector := New(newMockCollectorClient(), DefaultConfig())
+	collector.blocks = []*BlockInfo{
+		{Number: 10, Utilization: 20},
+		{Number: 11, Utilization: 30},
+		{Number: 12, Utilization: 96},
+		{Number: 13, Utilization: 40},
+	}
+
+	report := NewReport("utilization")
+	for i, tx := range []struct {
+		block uint64
+		ms    int
+	}{{10, 100}, {11, 200}, {11, 400}, {13, 900}} {
+		report.Transactions = append(report.Transactions, &TxInfo{
+			Nonce:   uint64(i),
+			Status:  TxConfirmSuccess,
+			Latency: time.Duration(tx.ms) * time.Millisecond,
+			Receipt: &types.Receipt{BlockNumber: new(big.Int).SetUint64(tx.block)},
+		})
+	}
+
+	collector.applyUtilizationLatency(report)
+
+	bins := report.UtilizationLatency
+	if len(bins) != 3 {
+		t.Fatalf("expected 3 bins, got %d", len(bins))
+	}
+	if bins[0].Low != 20 || bins[0].Txs != 1 {
+		t.Errorf("unexpected first bin: %+v", bins[0])
+	}
+	// Block 11 takes the utilization of block 11 itself; block 10 was emptier
+	if bins[1].Low != 30 || bins[1].Txs != 2 {
+		t.Errorf("unexpected second bin: %+v", bins[1])
+	}
+	// Block 13 takes the utilization of the full block 12 before it
+	if bins[2].Low != 90 || bins[2].High != 100 || bins[2].MedianLatency != 900*time.Millisecond {
+		t.Errorf("unexpected last bin: %+v", bins[2])
+	}
+}
+
+func TestCollector_ApplyActivation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ActivationBlock = 12
+	collector := New(newMockCollectorClient(), cfg)
+	start := time.Unix(1_700_000_000, 0)
+	for n := uint64(10); n <= 13; n++ {
+		collector.blocks = append(collector.blocks, &BlockInfo{Number: n, Timestamp: start.Add(time.Duration(n-10) * 2 * time.Second), OurTxCount: 1})
+	}
+
+	report := NewReport("activation")
+	report.Metrics.AvgBlockTime = 2 * time.Second
+	for i, tx := range []struct {
+		block  uint64
+		status TxConfirmStatus
+		ms     int
+	}{{10, TxConfirmSuccess, 100}, {11, TxConfirmSuccess, 300}, {12, TxConfirmFailed, 0}, {13, TxConfirmSuccess, 900}} {
+		report.Transactions = append(report.Transactions, &TxInfo{
+			Nonce:   uint64(i),
+			Status:  tx.status,
+			Latency: time.Duration(tx.ms) * time.Millisecond,
+			Receipt: &types.Receipt{BlockNumber: new(big.Int).SetUint64(tx.block)},
+		})
+	}
+
+	collector.applyActivation(report)
+
+	a := report.Activation
+	if a == nil {
+		t.Fatal("expected an activation split")
+	}
+	if a.Before.Confirmed != 2 || a.Before.Blocks != 2 || a.Before.AvgLatency != 200*time.Millisecond {
+		t.Errorf("unexpected before phase: %+v", a.Before)
+	}
+	// Blocks 10 and 11 span 4s counting from the parent of block 10
+	if a.Before.ChainTPS != 0.5 {
+		t.Errorf("before ChainTPS = %v, want 0.5", a.Before.ChainTPS)
+	}
+	if a.After.Confirmed != 1 || a.After.Reverted != 1 || a.After.Blocks != 2 || a.After.P95Latency != 900*time.Millisecond {
+		t.Errorf("unexpected after phase: %+v", a.After)
+	}
+
+	// A height outside the run has nothing to split
+	collector.config.ActivationBlock = 20
+	report.Activation = nil
+	collector.applyActivation(report)
+	if report.Activation != nil {
+		t.Error("expected no split when every tx was included before the height")
+	}
+}
+
+func TestCollector_ApplyProducers(t *testing.T) {
+	collector := New(newMockCollectorClient(), DefaultConfig())
+	alice := common.HexToAddress("0xa1")
+	bob := common.HexToAddress("0xb2")
+	collector.blocks = []*BlockInfo{
+		{Number: 10, Miner: alice, ExtraData: []byte("alice-builder"), OurTxCount: 2},
+		{Number: 11, Miner: bob, ExtraData: []byte{0xd8, 0x83, 0x01}, OurTxCount: 1},
+		{Number: 12, Miner: alice, ExtraData: []byte("alice-builder"), OurTxCount: 1},
+	}
+
+	report := NewReport("producers")
+	for i, tx := range []struct {
+		block uint64
+		ms    int
+	}{{10, 100}, {10, 300}, {11, 900}, {12, 200}} {
+		report.Transactions = append(report.Transactions, &TxInfo{
+			Nonce:   uint64(i),
+			Status:  TxConfirmSuccess,
+			Latency: time.Duration(tx.ms) * time.Millisecond,
+			Receipt: &types.Receipt{BlockNumber: new(big.Int).SetUint64(tx.block)},
+		})
+	}
+
+	collector.applyProducers(report)
+
+	if len(report.Producers) != 2 {
+		t.Fatalf("got %d producers, want 2", len(report.Producers))
+	}
+	a, b := report.Producers[0], report.Producers[1]
+	if a.Miner != alice || a.Label != "alice-builder" || a.Blocks != 2 || a.Txs != 3 || a.Share != 75 || a.AvgLatency != 200*time.Millisecond {
+		t.Errorf("unexpected first producer: %+v", a)
+	}
+	if b.Miner != bob || b.Label != "" || b.Blocks != 1 || b.Txs != 1 || b.P95Latency != 900*time.Millisecond {
+		t.Errorf("unexpected second producer: %+v", b)
+	}
+
+	// A single producer has nothing to compare
+	collector.blocks[1].Miner = alice
+	report.Producers = nil
+	collector.applyProducers(report)
+	if report.Producers != nil {
+		t.Error("expected no breakdown with a single producer")
+	}
+}
+
+func TestCollector_ApplyEvents(t *testing.T) {
+	transfer := NewExpectedEvent("Transfer(address, address, uint256)")
+	if transfer.Signature != "Transfer(address,address,uint256)" {
+		t.Errorf("Signature = %q, want spaces removed", transfer.Signature)
+	}
+	cfg := DefaultConfig()
+	cfg.ExpectedEvents = []ExpectedEvent{transfer, NewExpectedEvent("Approval(address,address,uint256)")}
+	collector := New(newMockCollectorClient(), cfg)
+
+	logs := func(topics ...common.Hash) []*types.Log {
+		var out []*types.Log
+		for _, topic := range topics {
+			out = append(out, &types.Log{Topics: []common.Hash{topic}})
+		}
+		return append(out, &types.Log{}) // Anonymous log without topics
+	}
+	other := common.HexToHash("0x01")
+	receipts := []*types.Receipt{
+		{Status: types.ReceiptStatusSuccessful, Logs: logs(transfer.Topic)},
+		{Status: types.ReceiptStatusSuccessful, Logs: logs(transfer.Topic, transfer.Topic)},
+		{Status: types.ReceiptStatusSuccessful, Logs: logs(other)},
+		{Status: types.ReceiptStatusFailed, Logs: logs(transfer.Topic)},
+	}
+	report := NewReport("events")
+	for i, receipt := range receipts {
+		info := &TxInfo{Hash: common.BigToHash(big.NewInt(int64(i + 1))), SentAt: time.Now()}
+		collector.recordReceipt(info, receipt, nil)
+		report.Transactions = append(report.Transactions, info)
+	}
+
+	collector.applyEvents(report)
+	e := report.Events
+	if e == nil {
+		t.Fatal("expected event stats")
+	}
+	if e.Checked != 3 || e.Emitted != 2 || e.Missing != 1 {
+		t.Errorf("stats = %d checked, %d emitted, %d missing; want 3, 2, 1", e.Checked, e.Emitted, e.Missing)
+	}
+	if e.Events[0].Logs != 3 || e.Events[1].Logs != 0 {
+		t.Errorf("logs = %d transfers, %d approvals; want 3, 0", e.Events[0].Logs, e.Events[1].Logs)
+	}
+
+	// Without expectations nothing is checked
+	unchecked := New(newMockCollectorClient(), DefaultConfig())
+	report.Events = nil
+	unchecked.applyEvents(report)
+	if report.Events != nil {
+		t.Error("expected no event stats without expected events")
+	}
+}
+
+func TestExtraLabel(t *testing.T) {
+	tests := []struct {
+		extra []byte
+		want  string
+	}{
+		{[]byte("beaverbuild.org"), "beaverbuild.org"},
+		{append([]byte("vanity  "), 0, 0, 0), "vanity"},
+		{[]byte{0xd8, 0x83, 'g', 'e', 't', 'h'}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := extraLabel(tt.extra); got != tt.want {
+			t.Errorf("extraLabel(%x) = %q, want %q", tt.extra, got, tt.want)
+		}
+	}
+}
+
+func TestCollector_ApplyLatencyHeatmap(t *testing.T) {
+	collector := New(newMockCollectorClient(), DefaultConfig())
+	start := time.Unix(1_700_000_000, 0)
+
+	report := NewReport("heatmap")
+	for i, tx := range []struct {
+		sent int // Seconds after start
+		ms   int
+	}{{0, 50}, {2, 300}, {5, 1500}, {31, 6000}, {35, 200}} {
+		report.Transactions = append(report.Transactions, &TxInfo{
+			Nonce:   uint64(i),
+			Status:  TxConfirmSuccess,
+			SentAt:  start.Add(time.Duration(tx.sent) * time.Second),
+			Latency: time.Duration(tx.ms) * time.Millisecond,
+		})
+	}
+	report.Transactions = append(report.Transactions, &TxInfo{Nonce: 9, Status: TxConfirmTimeout, SentAt: start, Latency: time.Hour})
+
+	collector.applyLatencyHeatmap(report)
+
+	heatmap := report.LatencyHeatmap
+	if heatmap == nil {
+		t.Fatal("expected a heatmap")
+	}
+	if !heatmap.Start.Equal(start.Add(50*time.Millisecond)) || len(heatmap.Buckets) != len(latencyBuckets) {
+		t.Errorf("start = %s, %d buckets", heatmap.Start, len(heatmap.Buckets))
+	}
+	// Confirmed 0.05s, 2.3s, 6.5s, 37s and 35.2s after start: rows 0 and 3, with empty rows between
+	want := [][]int{
+		{1, 1, 0, 1, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+		{0, 1, 0, 0, 0, 1},
+	}
+	if len(heatmap.Rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(heatmap.Rows), len(want))
+	}
+	for i, row := range heatmap.Rows {
+		if row.Offset != time.Duration(i)*10*time.Second {
+			t.Errorf("row %d offset = %s", i, row.Offset)
+		}
+		for j, count := range want[i] {
+			if row.Counts[j] != count {
+				t.Errorf("row %d = %v, want %v", i, row.Counts, want[i])
+				break
+			}
+		}
+	}
+
+	collector.config.HeatmapInterval = 0
+	report.LatencyHeatmap = nil
+	collector.applyLatencyHeatmap(report)
+	if report.LatencyHeatmap != nil {
+		t.Error("a zero interval should disable the heatmap")
+	}
+}
+
+func TestCollector_ApplyFairness(t *testing.T) {
+	collector := New(newMockCollectorClient(), DefaultConfig())
+	collector.blocks = []*BlockInfo{
+		{Number: 10, BaseFee: big.NewInt(10)},
+		{Number: 11, BaseFee: big.NewInt(10)},
+	}
+
+	a, b := common.HexToAddress("0xa"), common.HexToAddress("0xb")
+	base := time.Unix(1700000000, 0)
+	report := NewReport("fairness")
+	for i, tx := range []struct {
+		from   common.Address
+		sentMs int
+		block  uint64
+		index  uint
+		price  int64
+	}{
+		{a, 0, 10, 0, 12},
+		{b, 10, 10, 1, 15},
+		{a, 20, 10, 2, 12},
+		{b, 50, 11, 0, 15},
+		{a, 40, 11, 1, 12}, // Submitted before the previous tx but included after it
+	} {
+		report.Transactions = append(report.Transactions, &TxInfo{
+			Hash:   common.BigToHash(big.NewInt(int64(i + 1))),
+			From:   tx.from,
+			SentAt: base.Add(time.Duration(tx.sentMs) * time.Millisecond),
+			Status: TxConfirmSuccess,
+			Receipt: &types.Receipt{
+				BlockNumber:       new(big.Int).SetUint64(tx.block),
+				TransactionIndex:  tx.index,
+				EffectiveGasPrice: big.NewInt(tx.price),
+			},
+		})
+	}
+
+	collector.applyFairness(report)
+	f := report.Fairness
+	if f == nil {
+		t.Fatal("Fairness not set")
+	}
+	if f.Pairs != 6 || f.Inversions != 1 || math.Abs(f.Score-100*5.0/6) > 1e-9 {
+		t.Errorf("FIFO = %d of %d inverted, score %v; want 1 of 6, 83.33", f.Inversions, f.Pairs, f.Score)
+	}
+	// Block #10 puts the lower tip first once, block #11 orders by tip
+	if f.TipPairs != 3 || math.Abs(f.TipOrdered-100*2.0/3) > 1e-9 {
+		t.Errorf("tip ordering = %v of %d pairs, want 66.67 of 3", f.TipOrdered, f.TipPairs)
+	}
+	if len(f.Examples) != 1 {
+		t.Fatalf("got %d examples, want 1", len(f.Examples))
+	}
+	ex := f.Examples[0]
+	if ex.Early != common.BigToHash(big.NewInt(5)) || ex.Late != common.BigToHash(big.NewInt(4)) || ex.SentGap != 10*time.Millisecond {
+		t.Errorf("example = %+v, want tx 5 overtaken by tx 4 by 10ms", ex)
+	}
+
+	// One sender has nothing to compare against
+	report = NewReport("fairness")
+	report.Transactions = append(report.Transactions, &TxInfo{From: a, Status: TxConfirmSuccess,
+		Receipt: &types.Receipt{BlockNumber: big.NewInt(10)}})
+	collector.applyFairness(report)
+	if report.Fairness != nil {
+		t.Errorf("single-sender Fairness = %+v, want nil", report.Fairness)
+	}
+}
+
+func TestDiscordance_MatchesPairwise(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	senders := []common.Address{common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")}
+	keys := make([]orderKey, 200)
+	for i := range keys {
+		keys[i] = orderKey{
+			rank: int64(rng.Intn(50)), // Plenty of ties
+			tx:   &orderedTx{from: senders[rng.Intn(3)], block: uint64(rng.Intn(10)), index: uint(i)},
+		}
+	}
+
+	var wantDiscordant, wantPairs int64
+	for i := range keys {
+		for j := i + 1; j < len(keys); j++ {
+			x, y := keys[i], keys[j]
+			if x.rank == y.rank || x.tx.from == y.tx.from {
+				continue
+			}
+			wantPairs++
+			if (x.rank < y.rank) != x.tx.before(y.tx) {
+				wantDiscordant++
+			}
+		}
+	}
+
+	discordant, pairs := crossSenderDiscordance(keys)
+	if discordant != wantDiscordant || pairs != wantPairs {
+		t.Errorf("crossSenderDiscordance() = %d of %d, want %d of %d", discordant, pairs, wantDiscordant, wantPairs)
+	}
+}
+
+func TestSenderBias(t *testing.T) {
+	early, late := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	positions := map[common.Address][]float64{
+		early:                      {0, 0, 0.2, 0, 0.3},
+		late:                       {1, 0.8, 1, 1, 0.7},
+		common.HexToAddress("0x3"): {0.5}, // Too few samples to count
+	}
+	bias, favored := senderBias(positions)
+	if math.Abs(bias-0.8) > 1e-9 || favored != early {
+		t.Errorf("senderBias() = %v favoring %s, want 0.8 favoring %s", bias, favored.Hex(), early.Hex())
+	}
+}
+
+func TestCollector_SummaryLevel(t *testing.T) {
+	if _, err := ParseSummaryLevel("verbose"); err == nil {
+		t.Error("ParseSummaryLevel() should reject an unknown level")
+	}
+	if level, err := ParseSummaryLevel("FULL"); err != nil || level != SummaryFull {
+		t.Errorf("ParseSummaryLevel(FULL) = %q, %v", level, err)
+	}
+
+	report := &Report{
+		Metrics:          &Metrics{TotalSent: 10, TotalConfirmed: 10, SuccessRate: 100},
+		LatencyHistogram: map[string]int{"<100ms": 10},
+	}
+	summary := func(level SummaryLevel) string {
+		var buf bytes.Buffer
+		console.SetOutput(&buf)
+		defer console.SetOutput(os.Stdout)
+		New(&mockCollectorClient{}, &Config{SummaryLevel: level}).printSummary(report)
+		return buf.String()
+	}
+
+	if out := summary(SummaryMinimal); out != "" {
+		t.Errorf("minimal summary = %q, want nothing", out)
+	}
+	if out := summary(""); !strings.Contains(out, "Confirmed:") || strings.Contains(out, "Latency Distribution") {
+		t.Errorf("normal summary should have the counts and no tables:\n%s", out)
+	}
+	if out := summary(SummaryFull); !strings.Contains(out, "Latency Distribution") {
+		t.Errorf("full summary should have the latency table:\n%s", out)
+	}
+}
@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// ExpectedEvent is an event log that a successful test transaction should emit
+type ExpectedEvent struct {
+	Signature string      // Canonical signature, e.g. Transfer(address,address,uint256)
+	Topic     common.Hash // Keccak-256 of the signature, the first topic of its logs
+}
+
+// NewExpectedEvent returns the expectation of the event with the given signature
+func NewExpectedEvent(signature string) ExpectedEvent {
+	signature = strings.ReplaceAll(signature, " ", "")
+	return ExpectedEvent{Signature: signature, Topic: crypto.Keccak256Hash([]byte(signature))}
+}
+
+// EventStats counts the successful test transactions that emitted an
+// expected event, separating semantic success from receipt status
+type EventStats struct {
+	Checked int     // Successful receipts checked
+	Emitted int     // Of them, emitted at least one expected event
+	Missing int     // Of them, emitted none
+	Rate    float64 // Emitted / Checked in percent
+	Events  []*EventCount
+}
+
+// EventCount is the number of logs of one expected event in successful receipts
+type EventCount struct {
+	Signature string
+	Logs      int
+}
+
+// matchEvents returns whether receipt holds a log of an expected event and
+// counts those logs per event. The caller must hold txMutex.
+func (c *Collector) matchEvents(receipt *types.Receipt) bool {
+	emitted := false
+	for _, log := range receipt.Logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		for i, event := range c.config.ExpectedEvents {
+			if log.Topics[0] == event.Topic {
+				c.eventLogs[i]++
+				emitted = true
+			}
+		}
+	}
+	return emitted
+}
+
+// applyEvents counts the successful transactions that emitted an expected event
+func (c *Collector) applyEvents(report *Report) {
+	if len(c.config.ExpectedEvents) == 0 {
+		return
+	}
+	stats := &EventStats{}
+	_ = report.EachTransaction(func(tx *TxInfo) error {
+		if tx.Status != TxConfirmSuccess {
+			return nil
+		}
+		stats.Checked++
+		if tx.EmittedEvent {
+			stats.Emitted++
+		}
+		return nil
+	})
+	if stats.Checked == 0 {
+		return
+	}
+	stats.Missing = stats.Checked - stats.Emitted
+	stats.Rate = float64(stats.Emitted) / float64(stats.Checked) * 100
+	for i, event := range c.config.ExpectedEvents {
+		stats.Events = append(stats.Events, &EventCount{Signature: event.Signature, Logs: c.eventLogs[i]})
+	}
+	report.Events = stats
+}
+
+// printEvents prints how many successful transactions emitted an expected event
+func (c *Collector) printEvents(stats *EventStats) {
+	console.Printf("\nExpected Events:\n")
+	for _, event := range stats.Events {
+		console.Printf("  %-50s %d logs\n", event.Signature, event.Logs)
+	}
+	console.Printf("  Emitted:          %d of %d successful txs (%.2f%%)\n", stats.Emitted, stats.Checked, stats.Rate)
+	if stats.Missing > 0 {
+		console.Warnf("  %d successful txs emitted no expected event\n", stats.Missing)
+	}
+}
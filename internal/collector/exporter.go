@@ -4,31 +4,69 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/0xmhha/txhammer/internal/util/compress"
+	schema "github.com/0xmhha/txhammer/pkg/report"
 )
 
 // ExportFormat represents the export format
 type ExportFormat string
 
 const (
-	FormatJSON ExportFormat = "json"
-	FormatCSV  ExportFormat = "csv"
+	FormatJSON    ExportFormat = "json"
+	FormatCSV     ExportFormat = "csv"
+	FormatParquet ExportFormat = "parquet"
 )
 
+// ParseDatasetFormat validates a format for the per-transaction and per-block datasets
+func ParseDatasetFormat(s string) (ExportFormat, error) {
+	switch format := ExportFormat(strings.ToLower(s)); format {
+	case FormatCSV, FormatParquet:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid export format %q: must be csv or parquet", s)
+	}
+}
+
 // Exporter handles report export functionality
 type Exporter struct {
-	outputDir string
+	outputDir     string
+	datasetFormat ExportFormat
+	compression   compress.Codec // Applied to the CSV transaction and block datasets
+	written       []string       // Every file written so far
 }
 
 // NewExporter creates a new Exporter
 func NewExporter(outputDir string) *Exporter {
 	return &Exporter{
-		outputDir: outputDir,
+		outputDir:     outputDir,
+		datasetFormat: FormatCSV,
+		compression:   compress.None,
 	}
 }
 
+// WithDatasetFormat sets the format used by ExportAll for the transaction and block datasets
+func (e *Exporter) WithDatasetFormat(format ExportFormat) *Exporter {
+	e.datasetFormat = format
+	return e
+}
+
+// WithCompression sets the codec the CSV transaction and block datasets are
+// compressed with; their file names get the codec's suffix, e.g. .csv.zst
+func (e *Exporter) WithCompression(codec compress.Codec) *Exporter {
+	e.compression = codec
+	return e
+}
+
 // Export exports the report to the specified format
 func (e *Exporter) Export(report *Report, format ExportFormat) (string, error) {
 	// Create output directory if it doesn't exist
@@ -43,6 +81,8 @@ func (e *Exporter) Export(report *Report, format ExportFormat) (string, error) {
 		return e.exportJSON(report, timestamp)
 	case FormatCSV:
 		return e.exportCSV(report, timestamp)
+	case FormatParquet:
+		return e.exportParquet(report, timestamp)
 	default:
 		return "", fmt.Errorf("unsupported format: %s", format)
 	}
@@ -53,7 +93,7 @@ func (e *Exporter) exportJSON(report *Report, timestamp string) (string, error)
 	filename := filepath.Join(e.outputDir, fmt.Sprintf("report_%s.json", timestamp))
 
 	// Create JSON-serializable report
-	jsonReport := e.createJSONReport(report)
+	jsonReport := NewJSONReport(report)
 
 	data, err := json.MarshalIndent(jsonReport, "", "  ")
 	if err != nil {
@@ -63,82 +103,64 @@ func (e *Exporter) exportJSON(report *Report, timestamp string) (string, error)
 	if err := os.WriteFile(filename, data, 0o600); err != nil {
 		return "", fmt.Errorf("failed to write report: %w", err)
 	}
+	e.written = append(e.written, filename)
 
 	return filename, nil
 }
 
-// JSONReport is a JSON-serializable version of Report
-type JSONReport struct {
-	TestName  string      `json:"test_name"`
-	StartTime string      `json:"start_time"`
-	EndTime   string      `json:"end_time"`
-	Duration  string      `json:"duration"`
-	Summary   JSONSummary `json:"summary"`
-	Latency   JSONLatency `json:"latency"`
-	Gas       JSONGas     `json:"gas"`
-	Blocks    JSONBlocks  `json:"blocks"`
-}
+// JSONReport is the JSON-serializable version of Report
+type JSONReport = schema.Report
+
+// JSONHalt is a JSON-serializable chain halt window
+type JSONHalt = schema.Halt
+
+// JSONTx is a JSON-serializable latency outlier transaction
+type JSONTx = schema.Tx
+
+// JSONWorkload is a JSON-serializable workload description
+type JSONWorkload = schema.Workload
+
+// JSONUtilizationBin is a JSON-serializable utilization latency bin
+type JSONUtilizationBin = schema.UtilizationBin
 
 // JSONSummary is a JSON-serializable summary
-type JSONSummary struct {
-	TotalSent      int     `json:"total_sent"`
-	TotalConfirmed int     `json:"total_confirmed"`
-	TotalFailed    int     `json:"total_failed"`
-	TotalTimeout   int     `json:"total_timeout"`
-	TotalPending   int     `json:"total_pending"`
-	SuccessRate    float64 `json:"success_rate"`
-	TPS            float64 `json:"tps"`
-	ConfirmedTPS   float64 `json:"confirmed_tps"`
-}
+type JSONSummary = schema.Summary
 
 // JSONLatency is a JSON-serializable latency metrics
-type JSONLatency struct {
-	Average   string         `json:"average"`
-	Min       string         `json:"min"`
-	Max       string         `json:"max"`
-	P50       string         `json:"p50"`
-	P95       string         `json:"p95"`
-	P99       string         `json:"p99"`
-	Histogram map[string]int `json:"histogram"`
-}
+type JSONLatency = schema.Latency
 
 // JSONGas is a JSON-serializable gas metrics
-type JSONGas struct {
-	TotalUsed   uint64 `json:"total_used"`
-	AverageUsed uint64 `json:"average_used"`
-	TotalCost   string `json:"total_cost"`
-	AverageCost string `json:"average_cost"`
-}
+type JSONGas = schema.Gas
 
 // JSONBlocks is a JSON-serializable block metrics
-type JSONBlocks struct {
-	Observed         int     `json:"observed"`
-	AvgBlockTime     string  `json:"avg_block_time"`
-	AvgTxPerBlock    float64 `json:"avg_tx_per_block"`
-	AvgUtilization   float64 `json:"avg_utilization"`
-	FirstBlockWithTx uint64  `json:"first_block_with_tx,omitempty"`
-	LastBlockWithTx  uint64  `json:"last_block_with_tx,omitempty"`
-	BlockSpan        int     `json:"block_span,omitempty"`
-	BlocksWithOurTx  int     `json:"blocks_with_our_tx,omitempty"`
-	BlockBasedTPS    float64 `json:"block_based_tps,omitempty"`
-}
+type JSONBlocks = schema.Blocks
 
-// createJSONReport creates a JSON-serializable report
-func (e *Exporter) createJSONReport(report *Report) *JSONReport {
+// NewJSONReport creates the JSON-serializable report written to report_<timestamp>.json
+func NewJSONReport(report *Report) *JSONReport {
 	jr := &JSONReport{
-		TestName:  report.TestName,
-		StartTime: report.StartTime.Format(time.RFC3339),
-		EndTime:   report.EndTime.Format(time.RFC3339),
-		Duration:  report.Duration.String(),
+		SchemaVersion: schema.SchemaVersion,
+		TestName:      report.TestName,
+		StartTime:     report.StartTime.Format(time.RFC3339),
+		EndTime:       report.EndTime.Format(time.RFC3339),
+		Duration:      report.Duration.String(),
+		Partial:       report.Partial,
+		Region:        report.Region,
+		Workload:      report.Workload,
 		Summary: JSONSummary{
-			TotalSent:      report.Metrics.TotalSent,
-			TotalConfirmed: report.Metrics.TotalConfirmed,
-			TotalFailed:    report.Metrics.TotalFailed,
-			TotalTimeout:   report.Metrics.TotalTimeout,
-			TotalPending:   report.Metrics.TotalPending,
-			SuccessRate:    report.Metrics.SuccessRate,
-			TPS:            report.Metrics.TPS,
-			ConfirmedTPS:   report.Metrics.ConfirmedTPS,
+			TotalSent:             report.Metrics.TotalSent,
+			TotalConfirmed:        report.Metrics.TotalConfirmed,
+			TotalFailed:           report.Metrics.TotalFailed,
+			TotalTimeout:          report.Metrics.TotalTimeout,
+			TotalEvicted:          report.Metrics.TotalEvicted,
+			TotalPending:          report.Metrics.TotalPending,
+			SuccessRate:           report.Metrics.SuccessRate,
+			ChainTPS:              report.Metrics.ChainTPS,
+			ChainWindow:           report.Metrics.ChainWindow.String(),
+			WallClockTPS:          report.Metrics.WallClockTPS,
+			WallClockConfirmedTPS: report.Metrics.WallClockConfirmedTPS,
+			PeakTPS:               report.Metrics.PeakTPS,
+			PeakStartBlock:        report.Metrics.PeakStartBlock,
+			PeakEndBlock:          report.Metrics.PeakEndBlock,
 		},
 		Latency: JSONLatency{
 			Average:   report.Metrics.AvgLatency.String(),
@@ -172,10 +194,166 @@ func (e *Exporter) createJSONReport(report *Report) *JSONReport {
 	if report.Metrics.AvgGasCost != nil {
 		jr.Gas.AverageCost = report.Metrics.AvgGasCost.String()
 	}
+	if l1Fee := report.Metrics.TotalL1Fee; l1Fee != nil && report.Metrics.TotalGasCost != nil {
+		jr.Gas.ExecutionCost = new(big.Int).Sub(report.Metrics.TotalGasCost, l1Fee).String()
+		jr.Gas.L1Fee = l1Fee.String()
+	}
+	if report.Metrics.PeakWindow > 0 {
+		jr.Summary.PeakWindow = report.Metrics.PeakWindow.String()
+	}
+
+	jr.SlowestTxs = createJSONTxs(report.SlowestTxs)
+	jr.FastestTxs = createJSONTxs(report.FastestTxs)
+
+	for _, bin := range report.UtilizationLatency {
+		jr.UtilizationLatency = append(jr.UtilizationLatency, JSONUtilizationBin{
+			Low:           bin.Low,
+			High:          bin.High,
+			Txs:           bin.Txs,
+			MedianLatency: bin.MedianLatency.String(),
+			P95Latency:    bin.P95Latency.String(),
+		})
+	}
+
+	if b := report.Build; b != nil {
+		jr.Build = &schema.Build{Version: b.Version, Commit: b.Commit, Date: b.Date, GoVersion: b.GoVersion}
+	}
+	jr.Fairness = createJSONFairness(report.Fairness)
+	jr.LatencyHeatmap = createJSONHeatmap(report.LatencyHeatmap)
+	jr.Activation = createJSONActivation(report.Activation)
+	jr.Events = createJSONEvents(report.Events)
+	for _, p := range report.Producers {
+		jr.Producers = append(jr.Producers, schema.Producer{
+			Miner:      p.Miner.Hex(),
+			Label:      p.Label,
+			Blocks:     p.Blocks,
+			Txs:        p.Txs,
+			Share:      p.Share,
+			AvgLatency: p.AvgLatency.String(),
+			P95Latency: p.P95Latency.String(),
+		})
+	}
+
+	for _, h := range report.Halts {
+		jh := JSONHalt{
+			Start:     h.Start.Format(time.RFC3339),
+			LastBlock: h.LastBlock,
+		}
+		if h.End != nil {
+			jh.End = h.End.Format(time.RFC3339)
+			jh.Duration = h.Duration.String()
+		}
+		jr.Halts = append(jr.Halts, jh)
+	}
 
 	return jr
 }
 
+// createJSONEvents converts the expected event counts to their JSON form
+func createJSONEvents(e *EventStats) *schema.Events {
+	if e == nil {
+		return nil
+	}
+	events := &schema.Events{Checked: e.Checked, Emitted: e.Emitted, Missing: e.Missing, Rate: e.Rate}
+	for _, event := range e.Events {
+		events.Events = append(events.Events, schema.EventCount{Signature: event.Signature, Logs: event.Logs})
+	}
+	return events
+}
+
+// createJSONActivation converts the activation split to its JSON form
+func createJSONActivation(a *Activation) *schema.Activation {
+	if a == nil {
+		return nil
+	}
+	phase := func(p *ActivationPhase) schema.ActivationPhase {
+		return schema.ActivationPhase{
+			Confirmed:  p.Confirmed,
+			Reverted:   p.Reverted,
+			Blocks:     p.Blocks,
+			AvgLatency: p.AvgLatency.String(),
+			P95Latency: p.P95Latency.String(),
+			ChainTPS:   p.ChainTPS,
+		}
+	}
+	return &schema.Activation{Block: a.Block, Before: phase(a.Before), After: phase(a.After)}
+}
+
+// createJSONHeatmap converts the latency heatmap to its JSON form
+func createJSONHeatmap(h *LatencyHeatmap) *schema.LatencyHeatmap {
+	if h == nil {
+		return nil
+	}
+	jh := &schema.LatencyHeatmap{
+		Interval: h.Interval.String(),
+		Start:    h.Start.Format(time.RFC3339Nano),
+		Buckets:  h.Buckets,
+		Rows:     make([]schema.HeatmapRow, len(h.Rows)),
+	}
+	for i, row := range h.Rows {
+		jh.Rows[i] = schema.HeatmapRow{Offset: row.Offset.String(), Txs: row.Txs, Counts: row.Counts}
+	}
+	return jh
+}
+
+// createJSONFairness converts the inclusion order analysis to its JSON form
+func createJSONFairness(f *Fairness) *schema.Fairness {
+	if f == nil {
+		return nil
+	}
+	jf := &schema.Fairness{
+		Txs:        f.Txs,
+		Score:      f.Score,
+		Pairs:      f.Pairs,
+		Inversions: f.Inversions,
+		TipOrdered: f.TipOrdered,
+		TipPairs:   f.TipPairs,
+		SenderBias: f.SenderBias,
+	}
+	if f.SenderBias > 0 {
+		jf.FavoredSender = f.FavoredSender.Hex()
+	}
+	for _, inv := range f.Examples {
+		jf.Examples = append(jf.Examples, schema.OrderInversion{
+			Early:      inv.Early.Hex(),
+			EarlyFrom:  inv.EarlyFrom.Hex(),
+			EarlyBlock: inv.EarlyBlock,
+			EarlyIndex: inv.EarlyIndex,
+			Late:       inv.Late.Hex(),
+			LateFrom:   inv.LateFrom.Hex(),
+			LateBlock:  inv.LateBlock,
+			LateIndex:  inv.LateIndex,
+			SentGap:    inv.SentGap.String(),
+		})
+	}
+	return jf
+}
+
+// createJSONTxs converts latency outlier transactions to their JSON form
+func createJSONTxs(txs []*TxInfo) []JSONTx {
+	if len(txs) == 0 {
+		return nil
+	}
+	jtxs := make([]JSONTx, len(txs))
+	for i, tx := range txs {
+		jtxs[i] = JSONTx{
+			Hash:    tx.Hash.Hex(),
+			From:    tx.From.Hex(),
+			Nonce:   tx.Nonce,
+			Latency: tx.Latency.String(),
+		}
+		if tx.Receipt != nil {
+			if tx.Receipt.BlockNumber != nil {
+				jtxs[i].BlockNumber = tx.Receipt.BlockNumber.Uint64()
+			}
+			if tx.Receipt.EffectiveGasPrice != nil {
+				jtxs[i].GasPrice = tx.Receipt.EffectiveGasPrice.String()
+			}
+		}
+	}
+	return jtxs
+}
+
 // exportCSV exports the report as CSV files
 func (e *Exporter) exportCSV(report *Report, timestamp string) (string, error) {
 	// Create summary CSV
@@ -183,19 +361,22 @@ func (e *Exporter) exportCSV(report *Report, timestamp string) (string, error) {
 	if err := e.exportSummaryCSV(report, summaryFile); err != nil {
 		return "", err
 	}
+	e.written = append(e.written, summaryFile)
 
 	// Create transactions CSV
-	txFile := filepath.Join(e.outputDir, fmt.Sprintf("transactions_%s.csv", timestamp))
+	txFile := filepath.Join(e.outputDir, fmt.Sprintf("transactions_%s.csv%s", timestamp, e.compression.Ext()))
 	if err := e.exportTransactionsCSV(report, txFile); err != nil {
 		return "", err
 	}
+	e.written = append(e.written, txFile)
 
 	// Create blocks CSV if available
 	if len(report.Blocks) > 0 {
-		blocksFile := filepath.Join(e.outputDir, fmt.Sprintf("blocks_%s.csv", timestamp))
+		blocksFile := filepath.Join(e.outputDir, fmt.Sprintf("blocks_%s.csv%s", timestamp, e.compression.Ext()))
 		if err := e.exportBlocksCSV(report, blocksFile); err != nil {
 			return "", err
 		}
+		e.written = append(e.written, blocksFile)
 	}
 
 	return summaryFile, nil
@@ -216,6 +397,8 @@ func (e *Exporter) exportSummaryCSV(report *Report, filename string) error {
 	records := [][]string{
 		{"Metric", "Value"},
 		{"Test Name", report.TestName},
+		{"Region", report.Region},
+		{"Workload Hash", workloadHash(report.Workload)},
 		{"Start Time", report.StartTime.Format(time.RFC3339)},
 		{"End Time", report.EndTime.Format(time.RFC3339)},
 		{"Duration", report.Duration.String()},
@@ -223,9 +406,14 @@ func (e *Exporter) exportSummaryCSV(report *Report, filename string) error {
 		{"Total Confirmed", fmt.Sprintf("%d", report.Metrics.TotalConfirmed)},
 		{"Total Failed", fmt.Sprintf("%d", report.Metrics.TotalFailed)},
 		{"Total Timeout", fmt.Sprintf("%d", report.Metrics.TotalTimeout)},
+		{"Total Evicted", fmt.Sprintf("%d", report.Metrics.TotalEvicted)},
 		{"Success Rate", fmt.Sprintf("%.2f%%", report.Metrics.SuccessRate)},
-		{"TPS (Sent)", fmt.Sprintf("%.2f", report.Metrics.TPS)},
-		{"TPS (Confirmed)", fmt.Sprintf("%.2f", report.Metrics.ConfirmedTPS)},
+		{"Chain TPS", fmt.Sprintf("%.2f", report.Metrics.ChainTPS)},
+		{"Chain Window", report.Metrics.ChainWindow.String()},
+		{"Peak TPS", fmt.Sprintf("%.2f", report.Metrics.PeakTPS)},
+		{"Peak Window", report.Metrics.PeakWindow.String()},
+		{"Wall-Clock TPS (Sent)", fmt.Sprintf("%.2f", report.Metrics.WallClockTPS)},
+		{"Wall-Clock TPS (Confirmed)", fmt.Sprintf("%.2f", report.Metrics.WallClockConfirmedTPS)},
 		{"Block-Based TPS", fmt.Sprintf("%.2f", report.Metrics.BlockBasedTPS)},
 		{"First Block", fmt.Sprintf("%d", report.Metrics.FirstBlockWithTx)},
 		{"Last Block", fmt.Sprintf("%d", report.Metrics.LastBlockWithTx)},
@@ -250,28 +438,42 @@ func (e *Exporter) exportSummaryCSV(report *Report, filename string) error {
 	return nil
 }
 
+// workloadHash returns the hash of workload, or "" without one
+func workloadHash(workload *JSONWorkload) string {
+	if workload == nil {
+		return ""
+	}
+	return workload.Hash
+}
+
 // exportTransactionsCSV exports transactions as CSV
-func (e *Exporter) exportTransactionsCSV(report *Report, filename string) error {
-	file, err := os.Create(filename)
+func (e *Exporter) exportTransactionsCSV(report *Report, filename string) (err error) {
+	file, err := compress.Create(filename, e.compression)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer file.Close()
-
 	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	defer func() { err = closeCSV(writer, file, err) }()
 
 	// Write header
-	header := []string{"Hash", "From", "Nonce", "GasLimit", "SentAt", "ConfirmedAt", "Status", "Latency", "GasUsed", "Error"}
+	header := []string{"Hash", "From", "Nonce", "GasLimit", "SentAt", "ConfirmedAt", "Status", "Latency", "GasUsed", "FeePayer", "Error", "Region", "Cost", "L1Fee", "BlockNumber"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
 
 	// Write transactions
-	for _, tx := range report.Transactions {
-		var gasUsed string
+	return report.EachTransaction(func(tx *TxInfo) error {
+		var gasUsed, blockNumber string
 		if tx.Receipt != nil {
 			gasUsed = fmt.Sprintf("%d", tx.Receipt.GasUsed)
+			if tx.Receipt.BlockNumber != nil {
+				blockNumber = tx.Receipt.BlockNumber.String()
+			}
+		}
+
+		var feePayer string
+		if tx.FeePayer != (common.Address{}) {
+			feePayer = tx.FeePayer.Hex()
 		}
 
 		var errStr string
@@ -279,6 +481,14 @@ func (e *Exporter) exportTransactionsCSV(report *Report, filename string) error
 			errStr = tx.Error.Error()
 		}
 
+		var cost, l1Fee string
+		if c := tx.Cost(); c != nil {
+			cost = c.String()
+		}
+		if tx.L1Fee != nil {
+			l1Fee = tx.L1Fee.String()
+		}
+
 		record := []string{
 			tx.Hash.Hex(),
 			tx.From.Hex(),
@@ -289,30 +499,32 @@ func (e *Exporter) exportTransactionsCSV(report *Report, filename string) error
 			tx.Status.String(),
 			tx.Latency.String(),
 			gasUsed,
+			feePayer,
 			errStr,
+			report.Region,
+			cost,
+			l1Fee,
+			blockNumber,
 		}
 
 		if err := writer.Write(record); err != nil {
 			return fmt.Errorf("failed to write record: %w", err)
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // exportBlocksCSV exports blocks as CSV
-func (e *Exporter) exportBlocksCSV(report *Report, filename string) error {
-	file, err := os.Create(filename)
+func (e *Exporter) exportBlocksCSV(report *Report, filename string) (err error) {
+	file, err := compress.Create(filename, e.compression)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
-	defer file.Close()
-
 	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	defer func() { err = closeCSV(writer, file, err) }()
 
 	// Write header
-	header := []string{"Number", "Hash", "Timestamp", "GasLimit", "GasUsed", "TxCount", "OurTxCount", "Utilization"}
+	header := []string{"Number", "Hash", "Timestamp", "GasLimit", "GasUsed", "TxCount", "OurTxCount", "Utilization", "Miner", "ExtraData"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
@@ -328,6 +540,8 @@ func (e *Exporter) exportBlocksCSV(report *Report, filename string) error {
 			fmt.Sprintf("%d", block.TxCount),
 			fmt.Sprintf("%d", block.OurTxCount),
 			fmt.Sprintf("%.2f%%", block.Utilization),
+			block.Miner.Hex(),
+			hexutil.Encode(block.ExtraData),
 		}
 
 		if err := writer.Write(record); err != nil {
@@ -338,6 +552,25 @@ func (e *Exporter) exportBlocksCSV(report *Report, filename string) error {
 	return nil
 }
 
+// closeCSV flushes writer and closes file, which completes a compressed
+// stream, and returns err or else the first error doing so
+func closeCSV(writer *csv.Writer, file io.Closer, err error) error {
+	writer.Flush()
+	if flushErr := writer.Error(); err == nil && flushErr != nil {
+		err = fmt.Errorf("failed to write file: %w", flushErr)
+	}
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close file: %w", closeErr)
+	}
+	return err
+}
+
+// Written returns every file the exporter has written, including the
+// transaction and block datasets that Export does not return
+func (e *Exporter) Written() []string {
+	return e.written
+}
+
 // ExportAll exports the report in all formats
 func (e *Exporter) ExportAll(report *Report) ([]string, error) {
 	files := make([]string, 0)
@@ -348,11 +581,11 @@ func (e *Exporter) ExportAll(report *Report) ([]string, error) {
 	}
 	files = append(files, jsonFile)
 
-	csvFile, err := e.Export(report, FormatCSV)
+	datasetFile, err := e.Export(report, e.datasetFormat)
 	if err != nil {
-		return nil, fmt.Errorf("failed to export CSV: %w", err)
+		return nil, fmt.Errorf("failed to export %s: %w", strings.ToUpper(string(e.datasetFormat)), err)
 	}
-	files = append(files, csvFile)
+	files = append(files, datasetFile)
 
 	return files, nil
 }
@@ -0,0 +1,321 @@
+package collector
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// fairnessExamples is the number of order inversions listed in the report
+	fairnessExamples = 5
+
+	// minBiasSamples is the number of in-block positions a sender needs before
+	// it counts toward the sender bias
+	minBiasSamples = 5
+)
+
+// orderedTx is the part of a confirmed transaction the fairness analysis needs
+type orderedTx struct {
+	hash  common.Hash
+	from  common.Address
+	sent  int64 // Submission time (unix ns)
+	block uint64
+	index uint
+	tip   *big.Int // Effective tip; nil if unknown
+}
+
+// before reports whether a was included before b
+func (a *orderedTx) before(b *orderedTx) bool {
+	if a.block != b.block {
+		return a.block < b.block
+	}
+	return a.index < b.index
+}
+
+// orderKey ranks a transaction by one criterion next to its inclusion position
+type orderKey struct {
+	rank int64
+	tx   *orderedTx
+}
+
+// applyFairness analyzes whether inclusion order follows submission time,
+// tip or sender. Submission time is when the collector started tracking the
+// transaction, the node's acknowledgement with --collect-during-send.
+func (c *Collector) applyFairness(report *Report) {
+	baseFees := make(map[uint64]*big.Int, len(c.blocks))
+	for _, block := range c.blocks {
+		baseFees[block.Number] = block.BaseFee
+	}
+
+	var txs []*orderedTx
+	senders := make(map[common.Address]bool)
+	_ = report.EachTransaction(func(tx *TxInfo) error {
+		if tx.Status != TxConfirmSuccess || tx.Receipt == nil || tx.Receipt.BlockNumber == nil {
+			return nil
+		}
+		otx := &orderedTx{
+			hash:  tx.Hash,
+			from:  tx.From,
+			sent:  tx.SentAt.UnixNano(),
+			block: tx.Receipt.BlockNumber.Uint64(),
+			index: tx.Receipt.TransactionIndex,
+		}
+		if price := tx.Receipt.EffectiveGasPrice; price != nil {
+			otx.tip = new(big.Int).Set(price)
+			if baseFee := baseFees[otx.block]; baseFee != nil {
+				otx.tip.Sub(otx.tip, baseFee)
+			}
+		}
+		txs = append(txs, otx)
+		senders[tx.From] = true
+		return nil
+	})
+	if len(senders) < 2 {
+		return
+	}
+
+	fairness := &Fairness{Txs: len(txs)}
+
+	// Submission order across the whole run
+	keys := make([]orderKey, len(txs))
+	for i, tx := range txs {
+		keys[i] = orderKey{rank: tx.sent, tx: tx}
+	}
+	fairness.Inversions, fairness.Pairs = crossSenderDiscordance(keys)
+	if fairness.Pairs > 0 {
+		fairness.Score = 100 * (1 - float64(fairness.Inversions)/float64(fairness.Pairs))
+	}
+
+	// Tip order and relative position within each block
+	byBlock := make(map[uint64][]*orderedTx)
+	for _, tx := range txs {
+		byBlock[tx.block] = append(byBlock[tx.block], tx)
+	}
+	var tipDiscordant int64
+	positions := make(map[common.Address][]float64)
+	for _, blockTxs := range byBlock {
+		discordant, pairs := crossSenderDiscordance(tipKeys(blockTxs))
+		tipDiscordant += discordant
+		fairness.TipPairs += pairs
+		addRelativePositions(positions, blockTxs)
+	}
+	if fairness.TipPairs > 0 {
+		fairness.TipOrdered = 100 * (1 - float64(tipDiscordant)/float64(fairness.TipPairs))
+	}
+	fairness.SenderBias, fairness.FavoredSender = senderBias(positions)
+
+	fairness.Examples = orderInversions(txs, fairnessExamples)
+	report.Fairness = fairness
+}
+
+// tipKeys ranks transactions by tip, highest first; txs without a known tip are skipped
+func tipKeys(txs []*orderedTx) []orderKey {
+	keys := make([]orderKey, 0, len(txs))
+	for _, tx := range txs {
+		if tx.tip == nil {
+			continue
+		}
+		keys = append(keys, orderKey{tx: tx})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].tx.tip.Cmp(keys[j].tx.tip) > 0 })
+	for i := range keys {
+		if i > 0 && keys[i].tx.tip.Cmp(keys[i-1].tx.tip) == 0 {
+			keys[i].rank = keys[i-1].rank
+		} else {
+			keys[i].rank = int64(i)
+		}
+	}
+	return keys
+}
+
+// crossSenderDiscordance counts pairs from different senders whose inclusion
+// order contradicts their rank, and all such pairs with different ranks
+func crossSenderDiscordance(keys []orderKey) (discordant, pairs int64) {
+	discordant, pairs = discordance(keys)
+
+	// Remove the pairs within each sender
+	bySender := make(map[common.Address][]orderKey)
+	for _, k := range keys {
+		bySender[k.tx.from] = append(bySender[k.tx.from], k)
+	}
+	for _, senderKeys := range bySender {
+		d, p := discordance(senderKeys)
+		discordant -= d
+		pairs -= p
+	}
+	return discordant, pairs
+}
+
+// discordance counts the pairs with different ranks, and those among them
+// included in the opposite order of their ranks, in O(n log n)
+func discordance(keys []orderKey) (discordant, pairs int64) {
+	sorted := make([]orderKey, len(keys))
+	copy(sorted, keys)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].rank != sorted[j].rank {
+			return sorted[i].rank < sorted[j].rank
+		}
+		return sorted[i].tx.before(sorted[j].tx)
+	})
+
+	n := int64(len(sorted))
+	pairs = n * (n - 1) / 2
+	for i := 0; i < len(sorted); {
+		j := i
+		for j < len(sorted) && sorted[j].rank == sorted[i].rank {
+			j++
+		}
+		tied := int64(j - i)
+		pairs -= tied * (tied - 1) / 2
+		i = j
+	}
+
+	// Within a tie the txs are already in inclusion order, so every inversion
+	// left is a pair with different ranks
+	txs := make([]*orderedTx, len(sorted))
+	for i, k := range sorted {
+		txs[i] = k.tx
+	}
+	return countInversions(txs, make([]*orderedTx, len(txs))), pairs
+}
+
+// countInversions merge sorts txs into inclusion order, counting the pairs it
+// has to swap
+func countInversions(txs, buf []*orderedTx) int64 {
+	if len(txs) < 2 {
+		return 0
+	}
+	mid := len(txs) / 2
+	inversions := countInversions(txs[:mid], buf[:mid]) + countInversions(txs[mid:], buf[mid:])
+
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(txs) {
+		if txs[j].before(txs[i]) {
+			inversions += int64(mid - i)
+			buf[k] = txs[j]
+			j++
+		} else {
+			buf[k] = txs[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], txs[i:mid])
+	copy(buf[k:], txs[j:])
+	copy(txs, buf[:len(txs)])
+	return inversions
+}
+
+// addRelativePositions records where in the block each sender's txs landed,
+// from 0 (first of ours) to 1 (last of ours), for blocks shared by senders
+func addRelativePositions(positions map[common.Address][]float64, blockTxs []*orderedTx) {
+	if len(blockTxs) < 2 {
+		return
+	}
+	shared := false
+	for _, tx := range blockTxs[1:] {
+		if tx.from != blockTxs[0].from {
+			shared = true
+			break
+		}
+	}
+	if !shared {
+		return
+	}
+
+	sorted := make([]*orderedTx, len(blockTxs))
+	copy(sorted, blockTxs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].index < sorted[j].index })
+	last := float64(len(sorted) - 1)
+	for i, tx := range sorted {
+		positions[tx.from] = append(positions[tx.from], float64(i)/last)
+	}
+}
+
+// senderBias returns the spread of the mean relative position over senders
+// with enough samples, and the sender placed earliest on average
+func senderBias(positions map[common.Address][]float64) (float64, common.Address) {
+	var favored common.Address
+	lowest, highest := 2.0, -1.0
+	counted := 0
+	for sender, pos := range positions {
+		if len(pos) < minBiasSamples {
+			continue
+		}
+		var sum float64
+		for _, p := range pos {
+			sum += p
+		}
+		mean := sum / float64(len(pos))
+		if mean < lowest || (mean == lowest && sender.Cmp(favored) < 0) {
+			lowest, favored = mean, sender
+		}
+		highest = max(highest, mean)
+		counted++
+	}
+	if counted < 2 {
+		return 0, common.Address{}
+	}
+	return highest - lowest, favored
+}
+
+// orderInversions returns up to n txs included after a tx another sender
+// submitted later, largest submission gap first. Each early tx is paired with
+// the latest submission from another sender included before it.
+func orderInversions(txs []*orderedTx, n int) []*OrderInversion {
+	sorted := make([]*orderedTx, len(txs))
+	copy(sorted, txs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].before(sorted[j]) })
+
+	// Latest submission included so far, and the latest from any other sender
+	var latest, other *orderedTx
+	var examples []*OrderInversion
+	for _, tx := range sorted {
+		late := latest
+		if late != nil && late.from == tx.from {
+			late = other
+		}
+		if late != nil && late.sent > tx.sent {
+			examples = insertInversion(examples, &OrderInversion{
+				Early:      tx.hash,
+				Late:       late.hash,
+				EarlyFrom:  tx.from,
+				LateFrom:   late.from,
+				EarlyBlock: tx.block,
+				EarlyIndex: tx.index,
+				LateBlock:  late.block,
+				LateIndex:  late.index,
+				SentGap:    time.Duration(late.sent - tx.sent),
+			}, n)
+		}
+
+		switch {
+		case latest == nil || tx.sent > latest.sent:
+			if latest != nil && latest.from != tx.from {
+				other = latest
+			}
+			latest = tx
+		case tx.from != latest.from && (other == nil || tx.sent > other.sent):
+			other = tx
+		}
+	}
+	return examples
+}
+
+// insertInversion inserts inv into examples, kept sorted by gap, and trims it to n entries
+func insertInversion(examples []*OrderInversion, inv *OrderInversion, n int) []*OrderInversion {
+	i := sort.Search(len(examples), func(i int) bool { return inv.SentGap > examples[i].SentGap })
+	if i >= n {
+		return examples
+	}
+	examples = append(examples, nil)
+	copy(examples[i+1:], examples[i:])
+	examples[i] = inv
+	if len(examples) > n {
+		examples = examples[:n]
+	}
+	return examples
+}
@@ -0,0 +1,197 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// headBuffer is the number of new heads buffered between collection cycles
+const headBuffer = 64
+
+// errMethodNotFound is the JSON-RPC error code of an unknown method
+const errMethodNotFound = -32601
+
+// errBlockUnavailable means the node returned no receipts for a block it announced
+var errBlockUnavailable = errors.New("block receipts not available yet")
+
+// HeadSubscriber is implemented by clients that can push new chain heads,
+// which needs a WebSocket or IPC connection
+type HeadSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// headFeed collects receipts block by block as new heads arrive, instead of
+// querying the receipt of every pending transaction on each poll
+type headFeed struct {
+	sub           ethereum.Subscription
+	heads         chan *types.Header
+	next          uint64 // Next block whose receipts are fetched
+	latest        uint64 // Highest head received
+	blockReceipts bool   // eth_getBlockReceipts is served; else pending txs are polled once per head
+}
+
+// see records a new head
+func (f *headFeed) see(head *types.Header) {
+	if head != nil && head.Number != nil && head.Number.IsUint64() {
+		f.latest = max(f.latest, head.Number.Uint64())
+	}
+}
+
+// subscribeHeads switches receipt collection to new head notifications if
+// SubscribeHeads is set and the client can subscribe; otherwise the collector
+// keeps polling. Transactions mined before the subscription are caught up with
+// one receipt query each.
+func (c *Collector) subscribeHeads(ctx context.Context) {
+	if !c.config.SubscribeHeads || c.heads != nil || c.headsFailed {
+		return
+	}
+	subscriber, ok := c.client.(HeadSubscriber)
+	if !ok {
+		c.headsFailed = true
+		return
+	}
+
+	start, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return
+	}
+	heads := make(chan *types.Header, headBuffer)
+	sub, err := subscriber.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		console.Warnf("Head subscription unavailable, polling receipts: %v\n", err)
+		c.headsFailed = true
+		return
+	}
+	c.heads = &headFeed{sub: sub, heads: heads, next: start + 1, latest: start, blockReceipts: true}
+
+	// Blocks up to start are not announced; their transactions are polled once
+	pending := c.pendingTxs(0)
+	for i := 0; i < len(pending) && ctx.Err() == nil; i += c.config.BatchSize {
+		c.collectPending(ctx, pending[i:min(i+c.config.BatchSize, len(pending))])
+	}
+}
+
+// dropHeads ends the head subscription
+func (c *Collector) dropHeads() {
+	if c.heads == nil {
+		return
+	}
+	c.heads.sub.Unsubscribe()
+	c.heads = nil
+}
+
+// collectHeads collects the receipts of the blocks announced since the last
+// cycle. If the subscription failed, it falls back to polling.
+func (c *Collector) collectHeads(ctx context.Context) int {
+	feed := c.heads
+	select {
+	case err := <-feed.sub.Err():
+		console.Warnf("Head subscription ended, polling receipts: %v\n", err)
+		c.dropHeads()
+		c.headsFailed = true
+		return c.collectBatch(ctx)
+	default:
+	}
+	for drained := false; !drained; {
+		select {
+		case head := <-feed.heads:
+			feed.see(head)
+		default:
+			drained = true
+		}
+	}
+	if feed.next > feed.latest {
+		return 0
+	}
+
+	if !feed.blockReceipts {
+		feed.next = feed.latest + 1
+		return c.collectBatch(ctx)
+	}
+	collected := 0
+	for feed.next <= feed.latest && ctx.Err() == nil {
+		n, err := c.collectBlock(feed.next)
+		if err != nil {
+			var rpcErr rpc.Error
+			if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errMethodNotFound {
+				console.Warnf("eth_getBlockReceipts not served, polling pending receipts once per block\n")
+				feed.blockReceipts = false
+				feed.next = feed.latest + 1
+				return collected + c.collectBatch(ctx)
+			}
+			// Retry the block on the next cycle
+			break
+		}
+		collected += n
+		feed.next++
+	}
+	return collected
+}
+
+// collectBlock fetches all receipts of a block and records those of
+// pending transactions
+func (c *Collector) collectBlock(number uint64) (int, error) {
+	var raws []json.RawMessage
+	batch := []rpc.BatchElem{{
+		Method: "eth_getBlockReceipts",
+		Args:   []any{hexutil.EncodeUint64(number)},
+		Result: &raws,
+	}}
+	if err := c.client.BatchCall(batch); err != nil {
+		return 0, err
+	}
+	if batch[0].Error != nil {
+		return 0, batch[0].Error
+	}
+	if raws == nil {
+		return 0, errBlockUnavailable
+	}
+
+	rawFields := c.config.FeeDelegation || c.config.L1Fees
+	collected := 0
+	for _, raw := range raws {
+		receipt, extras, err := decodeReceipt(raw)
+		if err != nil || receipt == nil {
+			continue
+		}
+		c.txMutex.RLock()
+		info := c.txMap[receipt.TxHash]
+		pending := info != nil && info.Status == TxConfirmPending
+		c.txMutex.RUnlock()
+		if !pending {
+			continue
+		}
+		if !rawFields {
+			extras = nil
+		}
+		c.recordReceipt(info, receipt, extras)
+		collected++
+	}
+	return collected, nil
+}
+
+// wait blocks until the next collection cycle is due: after PollInterval, or
+// as soon as a new head arrives when subscribed
+func (c *Collector) wait(ctx context.Context) {
+	timer := time.NewTimer(c.config.PollInterval)
+	defer timer.Stop()
+	var heads chan *types.Header
+	if c.heads != nil {
+		heads = c.heads.heads
+	}
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	case head := <-heads:
+		c.heads.see(head)
+	}
+}
@@ -0,0 +1,201 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetChunkSize is the number of rows buffered before each write
+const parquetChunkSize = 10000
+
+// ParquetTx is a per-transaction Parquet row
+type ParquetTx struct {
+	Hash        string    `parquet:"hash"`
+	From        string    `parquet:"from"`
+	Nonce       uint64    `parquet:"nonce"`
+	GasLimit    uint64    `parquet:"gas_limit"`
+	SentAt      time.Time `parquet:"sent_at,timestamp(nanosecond)"`
+	ConfirmedAt *int64    `parquet:"confirmed_at_ns,optional"` // Unix nanoseconds, null when unconfirmed
+	Status      string    `parquet:"status"`
+	LatencyNs   int64     `parquet:"latency_ns"`
+	GasUsed     *uint64   `parquet:"gas_used,optional"`
+	FeePayer    *string   `parquet:"fee_payer,optional"` // Null unless fee-delegated
+	Error       *string   `parquet:"error,optional"`
+	Region      *string   `parquet:"region,optional"`       // Null unless the run has a region label
+	Cost        *string   `parquet:"cost,optional"`         // Wei, including the L1 data fee; null when unconfirmed
+	L1Fee       *string   `parquet:"l1_fee,optional"`       // Wei, null unless the receipt reported one
+	BlockNumber *uint64   `parquet:"block_number,optional"` // Null when unconfirmed
+}
+
+// ParquetBlock is a per-block Parquet row
+type ParquetBlock struct {
+	Number      uint64    `parquet:"number"`
+	Hash        string    `parquet:"hash"`
+	Timestamp   time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	GasLimit    uint64    `parquet:"gas_limit"`
+	GasUsed     uint64    `parquet:"gas_used"`
+	TxCount     int64     `parquet:"tx_count"`
+	OurTxCount  int64     `parquet:"our_tx_count"`
+	BaseFee     *string   `parquet:"base_fee,optional"`
+	Utilization float64   `parquet:"utilization"`
+	Miner       string    `parquet:"miner"`
+	ExtraData   string    `parquet:"extra_data"` // Hex
+}
+
+// exportParquet exports the summary as CSV and the transaction and block datasets as Parquet
+func (e *Exporter) exportParquet(report *Report, timestamp string) (string, error) {
+	summaryFile := filepath.Join(e.outputDir, fmt.Sprintf("summary_%s.csv", timestamp))
+	if err := e.exportSummaryCSV(report, summaryFile); err != nil {
+		return "", err
+	}
+	e.written = append(e.written, summaryFile)
+
+	txFile := filepath.Join(e.outputDir, fmt.Sprintf("transactions_%s.parquet", timestamp))
+	if err := writeParquet(txFile, report.EachTransaction, parquetTxConverter(report.Region)); err != nil {
+		return "", err
+	}
+	e.written = append(e.written, txFile)
+
+	if len(report.Blocks) > 0 {
+		blocksFile := filepath.Join(e.outputDir, fmt.Sprintf("blocks_%s.parquet", timestamp))
+		if err := writeParquet(blocksFile, eachOf(report.Blocks), toParquetBlock); err != nil {
+			return "", err
+		}
+		e.written = append(e.written, blocksFile)
+	}
+
+	return txFile, nil
+}
+
+// eachOf adapts a slice to the iterator form writeParquet takes
+func eachOf[S any](items []S) func(func(S) error) error {
+	return func(fn func(S) error) error {
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// writeParquet converts items to rows in chunks and writes them to a zstd-compressed Parquet file
+func writeParquet[S any, T any](filename string, each func(func(S) error) error, convert func(S) T) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	writer := parquet.NewGenericWriter[T](file, parquet.Compression(&parquet.Zstd))
+	rows := make([]T, 0, parquetChunkSize)
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		if _, err := writer.Write(rows); err != nil {
+			return fmt.Errorf("failed to write rows: %w", err)
+		}
+		rows = rows[:0]
+		return nil
+	}
+	err = each(func(item S) error {
+		rows = append(rows, convert(item))
+		if len(rows) == cap(rows) {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finalize parquet file: %w", err)
+	}
+	return nil
+}
+
+// parquetTxConverter returns the row converter for transactions submitted from region
+func parquetTxConverter(region string) func(*TxInfo) ParquetTx {
+	if region == "" {
+		return toParquetTx
+	}
+	return func(tx *TxInfo) ParquetTx {
+		row := toParquetTx(tx)
+		row.Region = &region
+		return row
+	}
+}
+
+// toParquetTx converts a transaction to a Parquet row
+func toParquetTx(tx *TxInfo) ParquetTx {
+	row := ParquetTx{
+		Hash:      tx.Hash.Hex(),
+		From:      tx.From.Hex(),
+		Nonce:     tx.Nonce,
+		GasLimit:  tx.GasLimit,
+		SentAt:    tx.SentAt,
+		Status:    tx.Status.String(),
+		LatencyNs: tx.Latency.Nanoseconds(),
+	}
+	if !tx.ConfirmedAt.IsZero() {
+		confirmedAt := tx.ConfirmedAt.UnixNano()
+		row.ConfirmedAt = &confirmedAt
+	}
+	if tx.Receipt != nil {
+		gasUsed := tx.Receipt.GasUsed
+		row.GasUsed = &gasUsed
+		if tx.Receipt.BlockNumber != nil && tx.Receipt.BlockNumber.IsUint64() {
+			blockNumber := tx.Receipt.BlockNumber.Uint64()
+			row.BlockNumber = &blockNumber
+		}
+	}
+	if tx.FeePayer != (common.Address{}) {
+		feePayer := tx.FeePayer.Hex()
+		row.FeePayer = &feePayer
+	}
+	if tx.Error != nil {
+		errStr := tx.Error.Error()
+		row.Error = &errStr
+	}
+	if cost := tx.Cost(); cost != nil {
+		costStr := cost.String()
+		row.Cost = &costStr
+	}
+	if tx.L1Fee != nil {
+		l1Fee := tx.L1Fee.String()
+		row.L1Fee = &l1Fee
+	}
+	return row
+}
+
+// toParquetBlock converts a block to a Parquet row
+func toParquetBlock(block *BlockInfo) ParquetBlock {
+	row := ParquetBlock{
+		Number:      block.Number,
+		Hash:        block.Hash.Hex(),
+		Timestamp:   block.Timestamp,
+		GasLimit:    block.GasLimit,
+		GasUsed:     block.GasUsed,
+		TxCount:     int64(block.TxCount),
+		OurTxCount:  int64(block.OurTxCount),
+		Utilization: block.Utilization,
+		Miner:       block.Miner.Hex(),
+		ExtraData:   hexutil.Encode(block.ExtraData),
+	}
+	if block.BaseFee != nil {
+		baseFee := block.BaseFee.String()
+		row.BaseFee = &baseFee
+	}
+	return row
+}
@@ -0,0 +1,134 @@
+package collector
+
+import (
+	"encoding/csv"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/0xmhha/txhammer/internal/util/compress"
+)
+
+func TestExporter_ExportParquet(t *testing.T) {
+	now := time.Now()
+	report := NewReport("parquet")
+	report.Region = "eu-west"
+	report.Transactions = []*TxInfo{
+		{
+			Hash:        common.HexToHash("0x01"),
+			Nonce:       1,
+			SentAt:      now,
+			ConfirmedAt: now.Add(time.Second),
+			Status:      TxConfirmSuccess,
+			Receipt:     &types.Receipt{GasUsed: 21000},
+			Latency:     time.Second,
+		},
+		{
+			Hash:   common.HexToHash("0x02"),
+			Nonce:  2,
+			SentAt: now,
+			Status: TxConfirmTimeout,
+			Error:  errors.New("timeout"),
+		},
+	}
+	report.Blocks = []*BlockInfo{
+		{Number: 10, Timestamp: now, GasLimit: 30000000, GasUsed: 21000, TxCount: 1, BaseFee: big.NewInt(7)},
+	}
+
+	dir := t.TempDir()
+	exporter := NewExporter(dir).WithDatasetFormat(FormatParquet)
+	files, err := exporter.ExportAll(report)
+	if err != nil {
+		t.Fatalf("ExportAll() error = %v", err)
+	}
+	if len(files) != 2 || !strings.HasSuffix(files[1], ".parquet") {
+		t.Fatalf("unexpected files: %v", files)
+	}
+	// report, summary, transactions and blocks
+	if written := exporter.Written(); len(written) != 4 {
+		t.Errorf("Written() = %v, want 4 files", written)
+	}
+
+	txs, err := parquet.ReadFile[ParquetTx](files[1])
+	if err != nil {
+		t.Fatalf("failed to read transactions: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(txs))
+	}
+	if txs[0].GasUsed == nil || *txs[0].GasUsed != 21000 || txs[0].ConfirmedAt == nil {
+		t.Errorf("unexpected confirmed row: %+v", txs[0])
+	}
+	if txs[0].Region == nil || *txs[0].Region != "eu-west" {
+		t.Errorf("row region = %v, want eu-west", txs[0].Region)
+	}
+	if txs[1].ConfirmedAt != nil || txs[1].Error == nil || *txs[1].Error != "timeout" {
+		t.Errorf("unexpected timeout row: %+v", txs[1])
+	}
+
+	blockFiles, _ := filepath.Glob(filepath.Join(dir, "blocks_*.parquet"))
+	if len(blockFiles) != 1 {
+		t.Fatalf("expected 1 blocks file, got %v", blockFiles)
+	}
+	blocks, err := parquet.ReadFile[ParquetBlock](blockFiles[0])
+	if err != nil {
+		t.Fatalf("failed to read blocks: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].Number != 10 || blocks[0].BaseFee == nil || *blocks[0].BaseFee != "7" {
+		t.Errorf("unexpected blocks: %+v", blocks)
+	}
+}
+
+func TestParseDatasetFormat(t *testing.T) {
+	if f, err := ParseDatasetFormat("Parquet"); err != nil || f != FormatParquet {
+		t.Errorf("ParseDatasetFormat(Parquet) = %v, %v", f, err)
+	}
+	if _, err := ParseDatasetFormat("json"); err == nil {
+		t.Error("expected error for json dataset format")
+	}
+}
+
+func TestExporter_ExportCSV_Compressed(t *testing.T) {
+	now := time.Now()
+	report := NewReport("csv")
+	report.Transactions = []*TxInfo{
+		{Hash: common.HexToHash("0x01"), Nonce: 1, SentAt: now, Status: TxConfirmSuccess, Receipt: &types.Receipt{GasUsed: 21000}},
+	}
+	report.Blocks = []*BlockInfo{{Number: 10, Timestamp: now, GasLimit: 30000000, GasUsed: 21000, TxCount: 1}}
+
+	exporter := NewExporter(t.TempDir()).WithCompression(compress.Zstd)
+	if _, err := exporter.ExportAll(report); err != nil {
+		t.Fatalf("ExportAll() error = %v", err)
+	}
+
+	var datasets int
+	for _, file := range exporter.Written() {
+		base := filepath.Base(file)
+		if !strings.HasPrefix(base, "transactions_") && !strings.HasPrefix(base, "blocks_") {
+			continue
+		}
+		datasets++
+		if !strings.HasSuffix(base, ".csv.zst") {
+			t.Errorf("dataset %s should end in .csv.zst", base)
+		}
+		r, err := compress.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(r).ReadAll()
+		r.Close()
+		if err != nil || len(records) != 2 {
+			t.Errorf("%s: read %d records, %v, want header and 1 row", base, len(records), err)
+		}
+	}
+	if datasets != 2 {
+		t.Errorf("found %d compressed datasets in %v, want 2", datasets, exporter.Written())
+	}
+}
@@ -0,0 +1,116 @@
+package collector
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// producerBin collects the test transactions included by one block producer
+type producerBin struct {
+	stats     *ProducerStats
+	latencies []time.Duration
+}
+
+// applyProducers attributes the confirmed transactions to the producers of
+// their blocks. A producer is its coinbase; blocks without one, as on clique
+// chains, are told apart by their extraData label. The breakdown is only
+// reported when at least two producers included test transactions.
+func (c *Collector) applyProducers(report *Report) {
+	blocks := make(map[uint64]*BlockInfo, len(c.blocks))
+	for _, block := range c.blocks {
+		blocks[block.Number] = block
+	}
+
+	bins := make(map[string]*producerBin)
+	total := 0
+	_ = report.EachTransaction(func(tx *TxInfo) error {
+		if tx.Status != TxConfirmSuccess || tx.Receipt == nil || tx.Receipt.BlockNumber == nil {
+			return nil
+		}
+		block, ok := blocks[tx.Receipt.BlockNumber.Uint64()]
+		if !ok {
+			return nil
+		}
+		key := producerKey(block)
+		bin := bins[key]
+		if bin == nil {
+			bin = &producerBin{stats: &ProducerStats{Miner: block.Miner, Label: extraLabel(block.ExtraData)}}
+			bins[key] = bin
+		}
+		bin.stats.Txs++
+		bin.latencies = append(bin.latencies, tx.Latency)
+		total++
+		return nil
+	})
+	if len(bins) < 2 {
+		return
+	}
+
+	// Count the blocks of each producer that included test transactions
+	for _, block := range c.blocks {
+		if block.OurTxCount == 0 {
+			continue
+		}
+		if bin := bins[producerKey(block)]; bin != nil {
+			bin.stats.Blocks++
+		}
+	}
+
+	for _, bin := range bins {
+		bin.stats.Share = float64(bin.stats.Txs) / float64(total) * 100
+		bin.stats.AvgLatency = c.calculateAvgLatency(bin.latencies)
+		bin.stats.P95Latency = c.calculatePercentile(bin.latencies, 95)
+		report.Producers = append(report.Producers, bin.stats)
+	}
+	sort.Slice(report.Producers, func(i, j int) bool {
+		a, b := report.Producers[i], report.Producers[j]
+		if a.Txs != b.Txs {
+			return a.Txs > b.Txs
+		}
+		if a.Miner != b.Miner {
+			return bytes.Compare(a.Miner[:], b.Miner[:]) < 0
+		}
+		return a.Label < b.Label
+	})
+}
+
+// producerKey identifies the producer of block by its coinbase, or by its
+// extraData label when the coinbase is empty
+func producerKey(block *BlockInfo) string {
+	if block.Miner == (common.Address{}) {
+		return "extra:" + extraLabel(block.ExtraData)
+	}
+	return block.Miner.Hex()
+}
+
+// extraLabel returns the printable text at the start of a block's extraData,
+// where builders and clients put their name ("" if it starts with binary data)
+func extraLabel(extra []byte) string {
+	end := 0
+	for end < len(extra) && extra[end] >= 0x20 && extra[end] < 0x7f {
+		end++
+	}
+	return string(bytes.TrimSpace(extra[:end]))
+}
+
+// printProducers prints the inclusion statistics per block producer
+func (c *Collector) printProducers(producers []*ProducerStats) {
+	console.Printf("\nBlock Producers:\n")
+	console.Printf("  %-42s %-20s %7s %8s %7s %12s %12s\n", "Miner", "Label", "Blocks", "Txs", "Share", "Avg Latency", "P95 Latency")
+	for _, p := range producers {
+		label := p.Label
+		if len(label) > 20 {
+			label = label[:17] + "..."
+		}
+		if label == "" {
+			label = "-"
+		}
+		console.Printf("  %-42s %-20s %7d %8d %6.1f%% %12s %12s\n", p.Miner.Hex(), label, p.Blocks, p.Txs, p.Share,
+			p.AvgLatency.Round(time.Millisecond), p.P95Latency.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// receiptExtras are the non-standard receipt fields the collector reads.
+// StableNet adds the fee payer to the receipts of fee-delegated txs; its
+// signature is returned as well but is not needed for attribution.
+// OP Stack and Scroll style rollups add the L1 data fee, which is charged on
+// top of gasUsed × effectiveGasPrice. The scalars and L1 prices they report
+// alongside only explain how the fee was derived and are not kept.
+type receiptExtras struct {
+	FeePayer  *common.Address `json:"feePayer"`
+	L1Fee     *hexutil.Big    `json:"l1Fee"`
+	L1GasUsed *hexutil.Big    `json:"l1GasUsed"`
+}
+
+// apply copies the extras to a confirmed transaction record
+func (e *receiptExtras) apply(info *TxInfo) {
+	if e.FeePayer != nil {
+		info.FeePayer = *e.FeePayer
+	}
+	if e.L1Fee != nil {
+		info.L1Fee = e.L1Fee.ToInt()
+	}
+	if e.L1GasUsed != nil && e.L1GasUsed.ToInt().IsUint64() {
+		info.L1GasUsed = e.L1GasUsed.ToInt().Uint64()
+	}
+}
+
+// decodeReceipt decodes a raw eth_getTransactionReceipt result into the
+// standard receipt and its extra fields. A null result yields a nil receipt.
+func decodeReceipt(raw json.RawMessage) (*types.Receipt, *receiptExtras, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil, nil
+	}
+
+	receipt := new(types.Receipt)
+	if err := json.Unmarshal(raw, receipt); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode receipt: %w", err)
+	}
+	extras := new(receiptExtras)
+	if err := json.Unmarshal(raw, extras); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode receipt extras: %w", err)
+	}
+	return receipt, extras, nil
+}
+
+// collectRaw fetches the receipts of pending transactions in one batch call
+// and decodes their extra fields
+func (c *Collector) collectRaw(pending []*TxInfo) int {
+	raws := make([]json.RawMessage, len(pending))
+	batch := make([]rpc.BatchElem, len(pending))
+	for i, info := range pending {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []any{info.Hash},
+			Result: &raws[i],
+		}
+	}
+	if err := c.client.BatchCall(batch); err != nil {
+		return 0
+	}
+
+	collected := 0
+	for i, info := range pending {
+		if batch[i].Error != nil {
+			continue
+		}
+		receipt, extras, err := decodeReceipt(raws[i])
+		if err != nil || receipt == nil {
+			// Not yet mined or undecodable, keep pending
+			continue
+		}
+		c.recordReceipt(info, receipt, extras)
+		collected++
+	}
+	return collected
+}
+
+// txCost returns what a confirmed transaction cost its payer: gasUsed ×
+// effectiveGasPrice plus the L1 data fee, if any. gasUsed is already net of
+// refunds. It returns nil when the receipt does not carry a gas price.
+func txCost(receipt *types.Receipt, l1Fee *big.Int) *big.Int {
+	if receipt == nil || receipt.EffectiveGasPrice == nil {
+		return nil
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	if l1Fee != nil {
+		cost.Add(cost, l1Fee)
+	}
+	return cost
+}
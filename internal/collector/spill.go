@@ -0,0 +1,186 @@
+package collector
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// spillStore holds finished TxInfo records on disk once the collector's memory cap is reached
+type spillStore struct {
+	dir   string
+	db    *pebble.DB
+	count int
+}
+
+// spilledTx is the on-disk form of a finished TxInfo; only the receipt fields
+// the report uses are kept
+type spilledTx struct {
+	Hash        common.Hash     `json:"h"`
+	From        common.Address  `json:"f"`
+	Nonce       uint64          `json:"n"`
+	GasLimit    uint64          `json:"gl"`
+	SentAt      time.Time       `json:"s"`
+	ConfirmedAt time.Time       `json:"c"`
+	Status      TxConfirmStatus `json:"st"`
+	Latency     time.Duration   `json:"l"`
+	Error       string          `json:"e,omitempty"`
+	SentBlock   uint64          `json:"sb,omitempty"`
+	FeePayer    *common.Address `json:"fp,omitempty"`
+	L1Fee       *big.Int        `json:"l1,omitempty"`
+	L1GasUsed   uint64          `json:"l1g,omitempty"`
+	Emitted     bool            `json:"ev,omitempty"`
+
+	HasReceipt        bool     `json:"r,omitempty"`
+	ReceiptStatus     uint64   `json:"rs,omitempty"`
+	GasUsed           uint64   `json:"gu,omitempty"`
+	EffectiveGasPrice *big.Int `json:"gp,omitempty"`
+	BlockNumber       *big.Int `json:"bn,omitempty"`
+	TransactionIndex  uint     `json:"ti,omitempty"`
+}
+
+// quietLogger discards pebble's informational logging
+type quietLogger struct{}
+
+func (quietLogger) Infof(string, ...interface{}) {}
+
+func (quietLogger) Fatalf(format string, args ...interface{}) {
+	panic(fmt.Sprintf(format, args...))
+}
+
+// openSpillStore creates a spill store in a fresh temporary directory under parent
+func openSpillStore(parent string) (*spillStore, error) {
+	dir, err := os.MkdirTemp(parent, "txhammer-spill-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+	db, err := pebble.Open(dir, &pebble.Options{Logger: quietLogger{}})
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to open spill store: %w", err)
+	}
+	return &spillStore{dir: dir, db: db}, nil
+}
+
+// put writes finished transactions to the store
+func (s *spillStore) put(txs []*TxInfo) error {
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	key := make([]byte, 8)
+	for _, tx := range txs {
+		value, err := json.Marshal(toSpilledTx(tx))
+		if err != nil {
+			return fmt.Errorf("failed to encode tx %s: %w", tx.Hash.Hex(), err)
+		}
+		binary.BigEndian.PutUint64(key, uint64(s.count))
+		if err := batch.Set(key, value, nil); err != nil {
+			return fmt.Errorf("failed to spill tx %s: %w", tx.Hash.Hex(), err)
+		}
+		s.count++
+	}
+	if err := batch.Commit(pebble.NoSync); err != nil {
+		return fmt.Errorf("failed to commit spill batch: %w", err)
+	}
+	return nil
+}
+
+// each calls fn for every spilled transaction in the order they were spilled
+func (s *spillStore) each(fn func(*TxInfo) error) error {
+	iter, err := s.db.NewIter(nil)
+	if err != nil {
+		return fmt.Errorf("failed to read spill store: %w", err)
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		var rec spilledTx
+		if err := json.Unmarshal(iter.Value(), &rec); err != nil {
+			return fmt.Errorf("failed to decode spilled tx: %w", err)
+		}
+		if err := fn(rec.toTxInfo()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// close closes the store and removes its directory
+func (s *spillStore) close() error {
+	err := s.db.Close()
+	return errors.Join(err, os.RemoveAll(s.dir))
+}
+
+func toSpilledTx(tx *TxInfo) *spilledTx {
+	rec := &spilledTx{
+		Hash:        tx.Hash,
+		From:        tx.From,
+		Nonce:       tx.Nonce,
+		GasLimit:    tx.GasLimit,
+		SentAt:      tx.SentAt,
+		ConfirmedAt: tx.ConfirmedAt,
+		Status:      tx.Status,
+		Latency:     tx.Latency,
+		SentBlock:   tx.SentBlock,
+		L1Fee:       tx.L1Fee,
+		L1GasUsed:   tx.L1GasUsed,
+		Emitted:     tx.EmittedEvent,
+	}
+	if tx.Error != nil {
+		rec.Error = tx.Error.Error()
+	}
+	if tx.FeePayer != (common.Address{}) {
+		rec.FeePayer = &tx.FeePayer
+	}
+	if r := tx.Receipt; r != nil {
+		rec.HasReceipt = true
+		rec.ReceiptStatus = r.Status
+		rec.GasUsed = r.GasUsed
+		rec.EffectiveGasPrice = r.EffectiveGasPrice
+		rec.BlockNumber = r.BlockNumber
+		rec.TransactionIndex = r.TransactionIndex
+	}
+	return rec
+}
+
+func (rec *spilledTx) toTxInfo() *TxInfo {
+	tx := &TxInfo{
+		Hash:         rec.Hash,
+		From:         rec.From,
+		Nonce:        rec.Nonce,
+		GasLimit:     rec.GasLimit,
+		SentAt:       rec.SentAt,
+		ConfirmedAt:  rec.ConfirmedAt,
+		Status:       rec.Status,
+		Latency:      rec.Latency,
+		SentBlock:    rec.SentBlock,
+		L1Fee:        rec.L1Fee,
+		L1GasUsed:    rec.L1GasUsed,
+		EmittedEvent: rec.Emitted,
+	}
+	if rec.Error != "" {
+		tx.Error = errors.New(rec.Error)
+	}
+	if rec.FeePayer != nil {
+		tx.FeePayer = *rec.FeePayer
+	}
+	if rec.HasReceipt {
+		tx.Receipt = &types.Receipt{
+			TxHash:            rec.Hash,
+			Status:            rec.ReceiptStatus,
+			GasUsed:           rec.GasUsed,
+			EffectiveGasPrice: rec.EffectiveGasPrice,
+			BlockNumber:       rec.BlockNumber,
+			TransactionIndex:  rec.TransactionIndex,
+		}
+	}
+	return tx
+}
@@ -0,0 +1,176 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestSpillStore_RoundTrip(t *testing.T) {
+	store, err := openSpillStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("openSpillStore() error = %v", err)
+	}
+
+	sentAt := time.Now().Round(0)
+	txs := []*TxInfo{
+		{
+			Hash:    common.HexToHash("0x01"),
+			Nonce:   1,
+			SentAt:  sentAt,
+			Status:  TxConfirmSuccess,
+			Latency: 2 * time.Second,
+			Receipt: &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				GasUsed:           21000,
+				EffectiveGasPrice: big.NewInt(1e9),
+				BlockNumber:       big.NewInt(42),
+			},
+		},
+		{
+			Hash:   common.HexToHash("0x02"),
+			Nonce:  2,
+			Status: TxConfirmTimeout,
+			Error:  errors.New("confirmation timeout"),
+		},
+	}
+	if err := store.put(txs); err != nil {
+		t.Fatalf("put() error = %v", err)
+	}
+
+	var got []*TxInfo
+	if err := store.each(func(tx *TxInfo) error {
+		got = append(got, tx)
+		return nil
+	}); err != nil {
+		t.Fatalf("each() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d records, want 2", len(got))
+	}
+	if got[0].Hash != txs[0].Hash || !got[0].SentAt.Equal(sentAt) || got[0].Latency != 2*time.Second {
+		t.Errorf("first record = %+v", got[0])
+	}
+	if got[0].Receipt == nil || got[0].Receipt.GasUsed != 21000 || got[0].Receipt.BlockNumber.Uint64() != 42 {
+		t.Errorf("receipt not restored: %+v", got[0].Receipt)
+	}
+	if got[1].Status != TxConfirmTimeout || got[1].Error == nil || got[1].Error.Error() != "confirmation timeout" {
+		t.Errorf("second record = %+v", got[1])
+	}
+
+	dir := store.dir
+	if err := store.close(); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("spill directory %s not removed", dir)
+	}
+}
+
+func TestCollector_Collect_WithMemoryCap(t *testing.T) {
+	client := newMockCollectorClient()
+
+	cfg := &Config{
+		PollInterval:   10 * time.Millisecond,
+		ConfirmTimeout: 1 * time.Second,
+		MaxConcurrent:  5,
+		BatchSize:      10,
+		MemoryCap:      2,
+		SpillDir:       t.TempDir(),
+	}
+	collector := New(client, cfg)
+
+	for i := range 5 {
+		hash := common.BigToHash(big.NewInt(int64(i + 1)))
+		collector.TrackTransaction(hash, common.Address{}, uint64(i), 21000, time.Now())
+		client.addReceipt(hash, types.ReceiptStatusSuccessful, 21000)
+	}
+
+	report, err := collector.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	defer report.Close()
+
+	if len(report.Transactions) != 0 {
+		t.Errorf("in-memory transactions = %d, want 0", len(report.Transactions))
+	}
+	if report.Metrics.TotalSent != 5 {
+		t.Errorf("TotalSent = %d, want 5", report.Metrics.TotalSent)
+	}
+	if report.Metrics.TotalConfirmed != 5 {
+		t.Errorf("TotalConfirmed = %d, want 5", report.Metrics.TotalConfirmed)
+	}
+
+	count := 0
+	if err := report.EachTransaction(func(*TxInfo) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("EachTransaction() error = %v", err)
+	}
+	if count != 5 {
+		t.Errorf("EachTransaction visited %d, want 5", count)
+	}
+}
+
+func TestSpillStore_KeepsInclusionOrder(t *testing.T) {
+	store, err := openSpillStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("openSpillStore() error = %v", err)
+	}
+	collector := New(newMockCollectorClient(), DefaultConfig())
+	collector.blocks = []*BlockInfo{{Number: 10, BaseFee: big.NewInt(10)}}
+
+	// a's second tx is submitted first but included last
+	a, b := common.HexToAddress("0xa"), common.HexToAddress("0xb")
+	base := time.Unix(1700000000, 0)
+	var txs []*TxInfo
+	for i, tx := range []struct {
+		from   common.Address
+		sentMs int
+		index  uint
+	}{
+		{a, 0, 2},
+		{b, 10, 0},
+		{b, 20, 1},
+	} {
+		txs = append(txs, &TxInfo{
+			Hash:   common.BigToHash(big.NewInt(int64(i + 1))),
+			From:   tx.from,
+			SentAt: base.Add(time.Duration(tx.sentMs) * time.Millisecond),
+			Status: TxConfirmSuccess,
+			Receipt: &types.Receipt{
+				BlockNumber:       big.NewInt(10),
+				TransactionIndex:  tx.index,
+				EffectiveGasPrice: big.NewInt(12),
+			},
+		})
+	}
+	if err := store.put(txs); err != nil {
+		t.Fatalf("put() error = %v", err)
+	}
+
+	report := NewReport("spilled fairness")
+	report.spill = store
+	defer report.Close()
+	collector.applyFairness(report)
+
+	f := report.Fairness
+	if f == nil {
+		t.Fatal("Fairness not set")
+	}
+	if f.Pairs != 2 || f.Inversions != 2 {
+		t.Errorf("FIFO = %d of %d inverted, want 2 of 2", f.Inversions, f.Pairs)
+	}
+	if len(f.Examples) == 0 || f.Examples[0].Early != txs[0].Hash || f.Examples[0].EarlyIndex != 2 {
+		t.Errorf("examples = %+v, want tx 1 included at index 2 after b's txs", f.Examples)
+	}
+}
@@ -1,13 +1,38 @@
 package collector
 
 import (
+	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/buildinfo"
+	"github.com/0xmhha/txhammer/internal/util/units"
+	"github.com/0xmhha/txhammer/internal/watchdog"
+)
+
+// SummaryLevel selects how much of the collection summary is printed
+type SummaryLevel string
+
+const (
+	SummaryMinimal SummaryLevel = "minimal" // None; the run ends with a one-line verdict
+	SummaryNormal  SummaryLevel = "normal"  // Counts, timing, latency, gas, blocks and errors
+	SummaryFull    SummaryLevel = "full"    // Also the per-bucket, per-tx and per-producer tables
 )
 
+// ParseSummaryLevel validates a --summary-level value
+func ParseSummaryLevel(s string) (SummaryLevel, error) {
+	switch level := SummaryLevel(strings.ToLower(s)); level {
+	case SummaryMinimal, SummaryNormal, SummaryFull:
+		return level, nil
+	default:
+		return "", fmt.Errorf("invalid summary-level %q: must be minimal, normal or full", s)
+	}
+}
+
 // TxConfirmStatus represents the confirmation status of a transaction
 type TxConfirmStatus int
 
@@ -17,6 +42,7 @@ const (
 	TxConfirmFailed
 	TxConfirmTimeout
 	TxConfirmNotFound
+	TxConfirmEvicted
 )
 
 func (s TxConfirmStatus) String() string {
@@ -31,6 +57,8 @@ func (s TxConfirmStatus) String() string {
 		return "TIMEOUT"
 	case TxConfirmNotFound:
 		return "NOT_FOUND"
+	case TxConfirmEvicted:
+		return "EVICTED"
 	default:
 		return "UNKNOWN"
 	}
@@ -48,6 +76,22 @@ type TxInfo struct {
 	Receipt     *types.Receipt
 	Latency     time.Duration
 	Error       error
+	FeePayer    common.Address // Fee payer of a fee-delegated tx, from its receipt (zero = none)
+	L1Fee       *big.Int       // L1 data fee of a rollup tx, from its receipt (nil = none reported)
+	L1GasUsed   uint64         // L1 gas its data was charged for (0 = none reported)
+
+	// Its receipt holds a log of an expected event (only set when events are expected)
+	EmittedEvent bool
+
+	// Eviction probing state
+	SentBlock   uint64 // Chain height when collection of the tx started
+	ProbedBlock uint64 // Chain height of the last eviction probe
+}
+
+// Cost returns what the transaction cost its payer, including the L1 data
+// fee (nil until a receipt with a gas price is recorded)
+func (tx *TxInfo) Cost() *big.Int {
+	return txCost(tx.Receipt, tx.L1Fee)
 }
 
 // BlockInfo represents block-level metrics
@@ -61,6 +105,8 @@ type BlockInfo struct {
 	OurTxCount  int
 	BaseFee     *big.Int
 	Utilization float64
+	Miner       common.Address // Coinbase of the block
+	ExtraData   []byte
 }
 
 // Metrics represents collected performance metrics
@@ -71,6 +117,7 @@ type Metrics struct {
 	TotalFailed    int
 	TotalPending   int
 	TotalTimeout   int
+	TotalEvicted   int
 
 	// Timing metrics
 	StartTime     time.Time
@@ -84,15 +131,21 @@ type Metrics struct {
 	P99Latency    time.Duration
 
 	// Throughput metrics
-	TPS          float64
-	ConfirmedTPS float64
-	PeakTPS      float64
+	ChainTPS              float64       // TotalConfirmed / ChainWindow (canonical)
+	ChainWindow           time.Duration // Chain time from the parent of the first inclusion block to the last inclusion block
+	WallClockTPS          float64       // TotalSent / collection wall-clock duration
+	WallClockConfirmedTPS float64       // TotalConfirmed / collection wall-clock duration
+	PeakTPS               float64       // Txs included in the best PeakWindow of chain time / PeakWindow
+	PeakWindow            time.Duration // Length of the peak window (0 = no peak, run shorter than the window)
+	PeakStartBlock        uint64        // First block of the peak window
+	PeakEndBlock          uint64        // Last block of the peak window
 
 	// Gas metrics
 	TotalGasUsed uint64
 	AvgGasUsed   uint64
-	TotalGasCost *big.Int
+	TotalGasCost *big.Int // Execution cost plus L1 data fees
 	AvgGasCost   *big.Int
+	TotalL1Fee   *big.Int // L1 data fees of rollup txs (nil = none reported)
 
 	// Block metrics
 	BlocksObserved int
@@ -111,6 +164,21 @@ type Metrics struct {
 	SuccessRate float64
 }
 
+// Budget settles the actual cost of mined transactions against a spend budget
+type Budget interface {
+	Settle(hash common.Hash, actual *big.Int)
+}
+
+// Callbacks for receipt notifications
+type Callbacks struct {
+	// OnReceipt is called for every receipt collected; it must be safe for concurrent use
+	OnReceipt func(receipt *types.Receipt)
+
+	// OnCycle is called after every receipt polling cycle with its start,
+	// duration and the number of transactions it finished
+	OnCycle func(start time.Time, duration time.Duration, finished int)
+}
+
 // Config holds collector configuration
 type Config struct {
 	// PollInterval is the interval for polling receipts
@@ -130,6 +198,56 @@ type Config struct {
 
 	// BlockPollInterval is the interval for polling blocks
 	BlockPollInterval time.Duration
+
+	// EvictionBlocks is the number of blocks after which a transaction that
+	// is neither pending nor mined is classified as evicted (0 = disabled)
+	EvictionBlocks uint64
+
+	// TopTxs is the number of slowest and fastest confirmed transactions
+	// listed in the report (0 = disabled)
+	TopTxs int
+
+	// PeakWindow is the length of chain time over which the peak TPS is
+	// reported (0 = disabled)
+	PeakWindow time.Duration
+
+	// HeatmapInterval is the length of the confirmation time intervals of the
+	// latency heatmap (0 = disabled)
+	HeatmapInterval time.Duration
+
+	// Units formats wei amounts in the console summary; exports keep raw wei
+	Units units.Formatter
+
+	// MemoryCap is the number of transaction records kept in memory; beyond it,
+	// finished records are spilled to a temporary on-disk store (0 = unlimited)
+	MemoryCap int
+
+	// SpillDir is the parent directory of the spill store (empty = system temp dir)
+	SpillDir string
+
+	// FeeDelegation fetches receipts as raw JSON in batched calls so the
+	// StableNet fee payer fields of fee-delegated (type 0x16) txs are decoded
+	FeeDelegation bool
+
+	// L1Fees fetches receipts the same way to decode the L1 data fee fields
+	// of rollup receipts and include them in the costs
+	L1Fees bool
+
+	// ActivationBlock splits the report's confirmed transactions into those
+	// included before this block and those included from it on (0 = disabled)
+	ActivationBlock uint64
+
+	// ExpectedEvents are the event logs a successful test transaction should
+	// emit; the report counts those that emitted none of them (empty = unchecked)
+	ExpectedEvents []ExpectedEvent
+
+	// SubscribeHeads collects receipts per block as new heads arrive on a
+	// subscription (eth_getBlockReceipts) instead of polling each pending
+	// transaction. Clients that cannot subscribe, such as HTTP ones, keep polling.
+	SubscribeHeads bool
+
+	// SummaryLevel selects how much of the collection summary is printed ("" = normal)
+	SummaryLevel SummaryLevel
 }
 
 // DefaultConfig returns default collector configuration
@@ -141,6 +259,9 @@ func DefaultConfig() *Config {
 		BatchSize:            100,
 		BlockTrackingEnabled: true,
 		BlockPollInterval:    1 * time.Second,
+		TopTxs:               10,
+		PeakWindow:           10 * time.Second,
+		HeatmapInterval:      10 * time.Second,
 	}
 }
 
@@ -164,6 +285,167 @@ type Report struct {
 
 	// Error summary
 	ErrorSummary map[string]int
+
+	// Chain halt windows observed during the run
+	Halts []watchdog.Halt
+
+	// Slowest and fastest confirmed transactions by latency
+	SlowestTxs []*TxInfo
+	FastestTxs []*TxInfo
+
+	// Confirmation latency binned by block utilization around inclusion
+	UtilizationLatency []*UtilizationBin
+
+	// Latency distribution over the run (nil when disabled or nothing confirmed)
+	LatencyHeatmap *LatencyHeatmap
+
+	// Inclusion order analysis (nil when fewer than two senders had txs confirmed)
+	Fairness *Fairness
+
+	// Metrics before and from the activation block (nil unless txs were included on both sides)
+	Activation *Activation
+
+	// Inclusion statistics per block producer (nil unless at least two producers included our txs)
+	Producers []*ProducerStats
+
+	// Successful txs that emitted an expected event (nil unless events are expected)
+	Events *EventStats
+
+	// Collection was cut short by the context deadline; unchecked txs remain pending
+	Partial bool
+
+	// Build of txhammer that ran the test (nil when not set)
+	Build *buildinfo.Info
+
+	// Label of the region or worker that submitted the transactions ("" = untagged)
+	Region string
+
+	// Parameters and hash of the workload the transactions were built from (nil when not set)
+	Workload *JSONWorkload
+
+	// Records spilled to disk during collection; not part of Transactions
+	spill *spillStore
+}
+
+// EachTransaction calls fn for every transaction in the report, including
+// records the collector spilled to disk
+func (r *Report) EachTransaction(fn func(*TxInfo) error) error {
+	for _, tx := range r.Transactions {
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	if r.spill != nil {
+		return r.spill.each(fn)
+	}
+	return nil
+}
+
+// Close releases the on-disk store of spilled records. Afterwards only the
+// in-memory Transactions remain available.
+func (r *Report) Close() error {
+	if r.spill == nil {
+		return nil
+	}
+	err := r.spill.close()
+	r.spill = nil
+	return err
+}
+
+// UtilizationBin holds the latency of transactions confirmed while block
+// utilization was within [Low, High) percent
+type UtilizationBin struct {
+	Low           float64
+	High          float64
+	Txs           int
+	MedianLatency time.Duration
+	P95Latency    time.Duration
+}
+
+// Activation splits the included transactions at a block height, such as a
+// fork activation, into those included before it and those included from it on
+type Activation struct {
+	Block  uint64
+	Before *ActivationPhase
+	After  *ActivationPhase
+}
+
+// ActivationPhase holds the metrics of the transactions included on one side
+// of the activation block
+type ActivationPhase struct {
+	Confirmed  int
+	Reverted   int
+	Blocks     int // Blocks including our transactions
+	AvgLatency time.Duration
+	P95Latency time.Duration
+	ChainTPS   float64 // Confirmed txs over the block-timestamp window of their inclusion
+}
+
+// ProducerStats holds the inclusion statistics of the test transactions in
+// the blocks of one producer, identified by its coinbase, or by its extraData
+// label when blocks carry no coinbase
+type ProducerStats struct {
+	Miner      common.Address
+	Label      string  // Printable extraData of its blocks ("" if binary or empty)
+	Blocks     int     // Blocks including our transactions
+	Txs        int     // Confirmed test transactions
+	Share      float64 // Percent of all confirmed test transactions
+	AvgLatency time.Duration
+	P95Latency time.Duration
+}
+
+// LatencyHeatmap counts confirmed transactions per interval of confirmation
+// time and latency bucket
+type LatencyHeatmap struct {
+	Interval time.Duration
+	Start    time.Time // First confirmation; rows start here
+	Buckets  []string  // Latency bucket labels, as in the latency histogram
+	Rows     []*HeatmapRow
+}
+
+// HeatmapRow holds the latency bucket counts of one interval; intervals
+// without confirmations have a row of zeros
+type HeatmapRow struct {
+	Offset time.Duration // Start of the interval relative to LatencyHeatmap.Start
+	Txs    int
+	Counts []int // Per latency bucket
+}
+
+// Fairness relates the order in which confirmed transactions were included
+// to their submission time, tip and sender. Only pairs from different senders
+// count, since the nonce fixes the order of one sender's transactions.
+type Fairness struct {
+	Txs int // Confirmed txs with a known block position
+
+	// Percent of comparable pairs included in submission order (100 = strict FIFO)
+	Score      float64
+	Pairs      int64 // Pairs submitted at different times
+	Inversions int64 // Pairs where the later submission was included first
+
+	// Percent of pairs in one block with different tips where the higher tip
+	// came first (0 when TipPairs is 0)
+	TipOrdered float64
+	TipPairs   int64
+
+	// Spread between the mean relative in-block position of the most and least
+	// favored sender (0 = no sender favored, 1 = one always first, another always last)
+	SenderBias    float64
+	FavoredSender common.Address
+
+	// Largest submission-order inversions
+	Examples []*OrderInversion
+}
+
+// OrderInversion is a transaction included before one that another sender
+// submitted earlier
+type OrderInversion struct {
+	Early, Late         common.Hash // Early was submitted first but included after Late
+	EarlyFrom, LateFrom common.Address
+	EarlyBlock          uint64
+	EarlyIndex          uint
+	LateBlock           uint64
+	LateIndex           uint
+	SentGap             time.Duration // How much earlier Early was submitted
 }
 
 // NewReport creates a new report
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnalyzeFormat selects how ANALYZE_BLOCKS presents its results
+type AnalyzeFormat string
+
+const (
+	AnalyzeSummary AnalyzeFormat = "summary" // Aggregate metrics only
+	AnalyzeTable   AnalyzeFormat = "table"   // Paginated per-block table plus the summary
+	AnalyzeCSV     AnalyzeFormat = "csv"     // Per-block CSV file
+	AnalyzeJSON    AnalyzeFormat = "json"    // JSON file with the full analysis result
+)
+
+// ParseAnalyzeFormat parses an analyze output name (case-insensitive)
+func ParseAnalyzeFormat(s string) (AnalyzeFormat, error) {
+	switch format := AnalyzeFormat(strings.ToLower(strings.TrimSpace(s))); format {
+	case AnalyzeSummary, AnalyzeTable, AnalyzeCSV, AnalyzeJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid analyze output %q: must be summary, table, csv or json", s)
+	}
+}
@@ -2,25 +2,49 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
 )
 
 // Mode represents the stress test mode
 type Mode string
 
 const (
-	ModeTransfer       Mode = "TRANSFER"
-	ModeFeeDelegation  Mode = "FEE_DELEGATION"
-	ModeContractDeploy Mode = "CONTRACT_DEPLOY"
-	ModeContractCall   Mode = "CONTRACT_CALL"
-	ModeERC20Transfer  Mode = "ERC20_TRANSFER"
-	ModeLongSender     Mode = "LONG_SENDER"
-	ModeAnalyzeBlocks  Mode = "ANALYZE_BLOCKS"
-	ModeERC721Mint     Mode = "ERC721_MINT"
+	ModeTransfer            Mode = "TRANSFER"
+	ModeFeeDelegation       Mode = "FEE_DELEGATION"
+	ModeContractDeploy      Mode = "CONTRACT_DEPLOY"
+	ModeContractCall        Mode = "CONTRACT_CALL"
+	ModeERC20Transfer       Mode = "ERC20_TRANSFER"
+	ModeLongSender          Mode = "LONG_SENDER"
+	ModeAnalyzeBlocks       Mode = "ANALYZE_BLOCKS"
+	ModeERC721Mint          Mode = "ERC721_MINT"
+	ModeTargetUtilization   Mode = "TARGET_UTILIZATION"
+	ModeConflict            Mode = "CONFLICT"
+	ModeCreate2Churn        Mode = "CREATE2_CHURN"
+	ModeAccountGrowth       Mode = "ACCOUNT_GROWTH"
+	ModeDeployThenCall      Mode = "DEPLOY_THEN_CALL"
+	ModeContention          Mode = "CONTENTION"
+	ModeChain               Mode = "CHAIN"
+	ModeApproveTransferFrom Mode = "APPROVE_TRANSFERFROM"
+	ModeSwap                Mode = "SWAP"
+	ModeScenario            Mode = "SCENARIO"
 )
 
+// Modes returns all test modes, in the order they are documented
+func Modes() []Mode {
+	return []Mode{
+		ModeTransfer, ModeFeeDelegation, ModeContractDeploy, ModeContractCall, ModeERC20Transfer,
+		ModeLongSender, ModeAnalyzeBlocks, ModeERC721Mint, ModeTargetUtilization, ModeConflict, ModeCreate2Churn,
+		ModeAccountGrowth, ModeDeployThenCall, ModeContention, ModeChain, ModeApproveTransferFrom, ModeSwap,
+		ModeScenario,
+	}
+}
+
 // Config holds all configuration for the stress test
 type Config struct {
 	// RPC connection
@@ -29,6 +53,12 @@ type Config struct {
 	// Account configuration
 	PrivateKey string
 	Mnemonic   string
+	KeyFile    string // Sub-account keys loaded from a file instead of derived
+	MasterKMS  string // awskms:// or gcpkms:// key the master account signs with instead of a local key
+
+	EphemeralAccounts bool // Generate sub-account keys at run start and reclaim their funds at the end
+
+	SubAccountOffset uint64 // Derivation index of the first sub-account (set per workload of a --workload run)
 
 	// Test configuration
 	Mode         string
@@ -38,12 +68,18 @@ type Config struct {
 
 	// Chain configuration
 	ChainID  uint64
-	GasLimit uint64
+	GasLimit uint64 // 0 = the mode's default
 	GasPrice string
-	Value    string // Transfer value in wei (default: 1)
+	Value    string // Value in wei sent with each transaction ("" = 1 for transfers, 0 for contract calls)
+	TxType   string // legacy, eip2930 or eip1559 ("" = the mode's default)
+
+	// Transfer recipients
+	Recipient         string // Account or contract every transfer is sent to
+	RecipientStrategy string // self, fixed or ring ("" = fixed with Recipient, else self)
 
 	// Fee Delegation mode
-	FeePayerKey string
+	FeePayerKey   string
+	FeePayerIndex uint64 // Derive the fee payer from the mnemonic at this index (0 = disabled)
 
 	// Contract mode
 	Contract string
@@ -53,6 +89,8 @@ type Config struct {
 	// Output
 	Output  string
 	Verbose bool
+	Quiet   bool   // Only the final summary, warnings and errors
+	Color   string // Status marker colors: auto, always or never
 
 	// Advanced
 	Timeout   time.Duration
@@ -61,6 +99,7 @@ type Config struct {
 	// Prometheus metrics
 	MetricsEnabled bool
 	MetricsPort    int
+	PushGateway    string // Pushgateway URL the end-of-run summary is pushed to ("" = no push)
 
 	// Long Sender mode
 	Duration  time.Duration
@@ -72,10 +111,44 @@ type Config struct {
 	BlockEnd   int64
 	BlockRange int64
 
+	AnalyzeOutput []string // Any of summary, table, csv and json
+	TableLimit    int      // Rows per page of the block table (0 = all)
+	TablePage     int      // 1-based page of the block table
+
+	EmptyStreak      int     // Shortest run of empty blocks reported as an anomaly (0 = off)
+	BlockTimeSpike   float64 // Block time above this multiple of the median is an anomaly (0 = off)
+	UtilizationCliff float64 // Utilization drop in percentage points reported as an anomaly (0 = off)
+
 	// ERC721 Mint mode
 	NFTName   string
 	NFTSymbol string
 	TokenURI  string
+
+	// Target Utilization mode
+	TargetUtilization float64 // Target average block gas utilization (percent)
+
+	// Conflict mode
+	Endpoints        []string // Extra RPC endpoints that conflicting variants are spread over and --redundancy submits to
+	MultiURL         bool     // --url listed several nodes, so sends are balanced over them without --balance
+	ConflictVariants int      // Differing transactions sent per nonce
+
+	// CREATE2 churn mode
+	ChurnCount int // Contracts created and self-destructed per transaction
+
+	// Deploy-then-call mode
+	DeployCount int // Contracts deployed before the calls are spread over them
+
+	// Account growth mode
+	GrowthRate         float64 // New accounts per second at the start
+	GrowthAcceleration float64 // Percent the account rate grows per minute (0 = constant)
+	GrowthTxs          int     // Transactions each new account sends before retiring
+
+	// Swap mode
+	SwapPath   []string // Token path through the router at Contract (empty = embedded pair)
+	SwapAmount string   // Input amount of each swap, in token base units ("" = builder default)
+
+	// Scenario mode
+	Scenario string // YAML file of the phases run one after the other
 }
 
 var (
@@ -83,6 +156,7 @@ var (
 	wsRegex      = regexp.MustCompile(`^wss?://`)
 	hexKeyRegex  = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
 	addressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	kmsURIRegex  = regexp.MustCompile(`^(awskms|gcpkms)://.+`)
 )
 
 // Validate validates the configuration
@@ -98,18 +172,70 @@ func (c *Config) Validate() error {
 	if err := c.validateMode(mode); err != nil {
 		return err
 	}
+	if mode == ModeScenario {
+		// Each phase is validated with its own mode when the scenario runs
+		if c.Scenario == "" {
+			return errors.New("scenario file is required for SCENARIO mode")
+		}
+		return c.validateOutput()
+	}
 	if err := c.validateModeSpecific(mode); err != nil {
 		return err
 	}
+	if err := c.validateValue(mode); err != nil {
+		return err
+	}
+	if err := c.validateRecipient(mode); err != nil {
+		return err
+	}
+	if err := c.validateTxType(mode); err != nil {
+		return err
+	}
+	if err := c.validateSwap(mode); err != nil {
+		return err
+	}
 	if err := c.validateNumeric(mode); err != nil {
 		return err
 	}
+	if err := c.validateOutput(); err != nil {
+		return err
+	}
+	if c.PushGateway != "" && !httpRegex.MatchString(c.PushGateway) {
+		return errors.New("pushgateway must be a valid HTTP URL")
+	}
 
 	c.applyDefaults(mode)
 	return nil
 }
 
+func (c *Config) validateOutput() error {
+	if c.Quiet && c.Verbose {
+		return errors.New("quiet and verbose cannot be used together")
+	}
+	if c.Color == "" {
+		return nil
+	}
+	mode, err := console.ParseColorMode(c.Color)
+	if err != nil {
+		return err
+	}
+	c.Color = string(mode)
+	return nil
+}
+
 func (c *Config) validateURL() error {
+	// Further comma-separated URLs join --endpoints ahead of its own entries
+	if urls := strings.Split(c.URL, ","); len(urls) > 1 {
+		c.URL = strings.TrimSpace(urls[0])
+		c.MultiURL = true
+		var extra []string
+		for _, u := range urls[1:] {
+			if u = strings.TrimSpace(u); u != "" {
+				extra = append(extra, u)
+			}
+		}
+		c.Endpoints = append(extra, c.Endpoints...)
+	}
 	if c.URL == "" {
 		return errors.New("url is required")
 	}
@@ -123,38 +249,75 @@ func (c *Config) validateCredentials(mode Mode) error {
 	if mode == ModeAnalyzeBlocks {
 		return nil
 	}
+	if c.EphemeralAccounts && c.KeyFile != "" {
+		return errors.New("ephemeral-accounts and key-file are mutually exclusive")
+	}
+	// These modes never distribute funds, so generated sub-accounts would
+	// send without any
+	if mode == ModeLongSender || mode == ModeTargetUtilization {
+		if c.EphemeralAccounts {
+			return fmt.Errorf("ephemeral-accounts is not supported in %s mode, which does not fund sub-accounts", mode)
+		}
+		if c.MasterKMS != "" && c.KeyFile == "" {
+			return fmt.Errorf("master-kms in %s mode needs funded sub-accounts: load them with key-file", mode)
+		}
+	}
+	if c.MasterKMS != "" {
+		if !kmsURIRegex.MatchString(c.MasterKMS) {
+			return errors.New("master-kms must be awskms://<key-id> or gcpkms://<key-version-name>")
+		}
+		if c.PrivateKey != "" || c.Mnemonic != "" {
+			return errors.New("master-kms replaces private-key and mnemonic; load sub-accounts with key-file instead")
+		}
+		return nil
+	}
 	if c.PrivateKey == "" && c.Mnemonic == "" {
-		return errors.New("either private-key or mnemonic is required")
+		return errors.New("either private-key, mnemonic or master-kms is required")
 	}
 	if c.PrivateKey != "" && !hexKeyRegex.MatchString(c.PrivateKey) {
 		return errors.New("private-key must be a valid 64-character hex string with 0x prefix")
 	}
+	if c.KeyFile != "" && c.PrivateKey == "" {
+		return errors.New("key-file requires private-key or master-kms for the master account")
+	}
 	return nil
 }
 
 func (c *Config) validateMode(mode Mode) error {
-	switch mode {
-	case ModeTransfer, ModeFeeDelegation, ModeContractDeploy, ModeContractCall, ModeERC20Transfer,
-		ModeLongSender, ModeAnalyzeBlocks, ModeERC721Mint:
+	modes := Modes()
+	if slices.Contains(modes, mode) || IsCustomMode(mode) {
 		return nil
-	default:
-		return errors.New("invalid mode: must be TRANSFER, FEE_DELEGATION, CONTRACT_DEPLOY, CONTRACT_CALL, ERC20_TRANSFER, LONG_SENDER, ANALYZE_BLOCKS, or ERC721_MINT")
 	}
+	modes = append(modes, CustomModes()...)
+	names := make([]string, len(modes))
+	for i, m := range modes {
+		names[i] = string(m)
+	}
+	return fmt.Errorf("invalid mode: must be %s, or %s", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
 }
 
 func (c *Config) validateModeSpecific(mode Mode) error {
 	if mode == ModeFeeDelegation {
-		if c.FeePayerKey == "" {
-			return errors.New("fee-payer-key is required for FEE_DELEGATION mode")
-		}
-		if !hexKeyRegex.MatchString(c.FeePayerKey) {
+		if c.FeePayerIndex > 0 {
+			if c.FeePayerKey != "" {
+				return errors.New("fee-payer-key and fee-payer-index are mutually exclusive")
+			}
+			if c.Mnemonic == "" {
+				return errors.New("fee-payer-index requires mnemonic")
+			}
+			if c.FeePayerIndex <= c.SubAccounts {
+				return fmt.Errorf("fee-payer-index must be greater than sub-accounts (%d) to avoid sharing a sub-account", c.SubAccounts)
+			}
+		} else if c.FeePayerKey == "" {
+			return errors.New("fee-payer-key is required for FEE_DELEGATION mode (or fee-payer-index with mnemonic)")
+		} else if !hexKeyRegex.MatchString(c.FeePayerKey) {
 			return errors.New("fee-payer-key must be a valid 64-character hex string with 0x prefix")
 		}
 	}
 
-	if mode == ModeContractCall || mode == ModeERC20Transfer {
+	if mode == ModeContractCall || mode == ModeERC20Transfer || mode == ModeApproveTransferFrom {
 		if c.Contract == "" {
-			return errors.New("contract address is required for CONTRACT_CALL and ERC20_TRANSFER modes")
+			return errors.New("contract address is required for CONTRACT_CALL, ERC20_TRANSFER and APPROVE_TRANSFERFROM modes")
 		}
 		if !addressRegex.MatchString(c.Contract) {
 			return errors.New("contract must be a valid 40-character hex address with 0x prefix")
@@ -168,6 +331,63 @@ func (c *Config) validateModeSpecific(mode Mode) error {
 		if c.BlockStart > 0 && c.BlockEnd > 0 && c.BlockStart > c.BlockEnd {
 			return errors.New("block-start must be less than or equal to block-end")
 		}
+		for i, name := range c.AnalyzeOutput {
+			format, err := ParseAnalyzeFormat(name)
+			if err != nil {
+				return err
+			}
+			c.AnalyzeOutput[i] = string(format)
+		}
+		if c.TableLimit < 0 {
+			return errors.New("table-limit must not be negative")
+		}
+		if c.TablePage < 0 {
+			return errors.New("table-page must not be negative")
+		}
+		if c.EmptyStreak < 0 || c.BlockTimeSpike < 0 || c.UtilizationCliff < 0 {
+			return errors.New("empty-streak, block-time-spike and utilization-cliff must not be negative")
+		}
+	}
+
+	if mode == ModeTargetUtilization {
+		if c.TargetUtilization < 0 || c.TargetUtilization > 100 {
+			return errors.New("target-utilization must be between 0 and 100")
+		}
+	}
+
+	if mode == ModeConflict {
+		if c.ConflictVariants != 0 && c.ConflictVariants < 2 {
+			return errors.New("conflict-variants must be at least 2")
+		}
+	}
+
+	for _, endpoint := range c.Endpoints {
+		if !httpRegex.MatchString(endpoint) && !wsRegex.MatchString(endpoint) {
+			return fmt.Errorf("endpoint %q must be a valid HTTP or WebSocket URL", endpoint)
+		}
+	}
+
+	if mode == ModeCreate2Churn || mode == ModeContention {
+		if mode == ModeCreate2Churn && c.ChurnCount < 0 {
+			return errors.New("churn-count must not be negative")
+		}
+		if c.Contract != "" && !addressRegex.MatchString(c.Contract) {
+			return errors.New("contract must be a valid 40-character hex address with 0x prefix")
+		}
+	}
+
+	if IsCustomMode(mode) && c.Contract != "" && !addressRegex.MatchString(c.Contract) {
+		return errors.New("contract must be a valid 40-character hex address with 0x prefix")
+	}
+
+	if mode == ModeDeployThenCall && c.DeployCount < 0 {
+		return errors.New("deploy-count must not be negative")
+	}
+
+	if mode == ModeAccountGrowth {
+		if c.GrowthRate < 0 || c.GrowthAcceleration < 0 || c.GrowthTxs < 0 {
+			return errors.New("growth-rate, growth-acceleration and growth-txs must not be negative")
+		}
 	}
 
 	return nil
@@ -186,9 +406,6 @@ func (c *Config) validateNumeric(mode Mode) error {
 	if c.BatchSize == 0 {
 		return errors.New("batch size must be greater than 0")
 	}
-	if c.GasLimit == 0 {
-		return errors.New("gas-limit must be greater than 0")
-	}
 	return nil
 }
 
@@ -196,7 +413,7 @@ func (c *Config) applyDefaults(mode Mode) {
 	if c.Timeout == 0 {
 		c.Timeout = 5 * time.Minute
 	}
-	if mode == ModeLongSender {
+	if mode == ModeLongSender || mode == ModeTargetUtilization {
 		if c.TargetTPS <= 0 {
 			c.TargetTPS = 100
 		}
@@ -204,10 +421,36 @@ func (c *Config) applyDefaults(mode Mode) {
 			c.Workers = 10
 		}
 	}
+	if mode == ModeTargetUtilization && c.TargetUtilization == 0 {
+		c.TargetUtilization = 80
+	}
+	if mode == ModeConflict && c.ConflictVariants == 0 {
+		c.ConflictVariants = 2
+	}
+	if mode == ModeCreate2Churn && c.ChurnCount == 0 {
+		c.ChurnCount = 10
+	}
+	if mode == ModeDeployThenCall && c.DeployCount == 0 {
+		c.DeployCount = 10
+	}
+	if mode == ModeAccountGrowth {
+		if c.GrowthRate == 0 {
+			c.GrowthRate = 1
+		}
+		if c.GrowthTxs == 0 {
+			c.GrowthTxs = 3
+		}
+	}
 	if mode == ModeAnalyzeBlocks {
 		if c.BlockStart == 0 && c.BlockEnd == 0 && c.BlockRange == 0 {
 			c.BlockRange = 100
 		}
+		if len(c.AnalyzeOutput) == 0 {
+			c.AnalyzeOutput = []string{"table", "csv"}
+		}
+		if c.TablePage == 0 {
+			c.TablePage = 1
+		}
 	}
 	if mode == ModeERC721Mint {
 		if c.NFTName == "" {
@@ -223,6 +466,8 @@ func (c *Config) applyDefaults(mode Mode) {
 	if c.MetricsEnabled && c.MetricsPort == 0 {
 		c.MetricsPort = 9090
 	}
+	c.applyValue(mode)
+	c.applyGasLimit(mode)
 }
 
 // GetMode returns the parsed mode
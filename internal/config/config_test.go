@@ -1,8 +1,15 @@
 package config
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
 )
 
 func TestConfig_Validate(t *testing.T) {
@@ -89,7 +96,7 @@ func TestConfig_Validate(t *testing.T) {
 				GasLimit:     21000,
 			},
 			wantErr: true,
-			errMsg:  "either private-key or mnemonic is required",
+			errMsg:  "either private-key, mnemonic or master-kms is required",
 		},
 		{
 			name: "invalid private key format",
@@ -105,6 +112,136 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "private-key must be a valid 64-character hex string",
 		},
+		{
+			name: "master kms",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				MasterKMS:    "awskms://alias/treasury",
+				Mode:         "TRANSFER",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+			},
+			wantErr: false,
+		},
+		{
+			name: "ephemeral accounts with key file",
+			config: &Config{
+				URL:               "http://localhost:8545",
+				PrivateKey:        "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				KeyFile:           "keys.txt",
+				EphemeralAccounts: true,
+				Mode:              "TRANSFER",
+				SubAccounts:       10,
+				Transactions:      100,
+				BatchSize:         50,
+				GasLimit:          21000,
+			},
+			wantErr: true,
+			errMsg:  "ephemeral-accounts and key-file are mutually exclusive",
+		},
+		{
+			name: "ephemeral accounts in LONG_SENDER mode",
+			config: &Config{
+				URL:               "http://localhost:8545",
+				PrivateKey:        "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				EphemeralAccounts: true,
+				Mode:              "LONG_SENDER",
+				SubAccounts:       10,
+				Transactions:      100,
+				BatchSize:         50,
+				GasLimit:          21000,
+			},
+			wantErr: true,
+			errMsg:  "ephemeral-accounts is not supported in LONG_SENDER mode",
+		},
+		{
+			name: "master kms in LONG_SENDER mode",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				MasterKMS:    "awskms://alias/treasury",
+				Mode:         "LONG_SENDER",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+			},
+			wantErr: true,
+			errMsg:  "master-kms in LONG_SENDER mode needs funded sub-accounts",
+		},
+		{
+			name: "ephemeral accounts in TARGET_UTILIZATION mode",
+			config: &Config{
+				URL:               "http://localhost:8545",
+				PrivateKey:        "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				EphemeralAccounts: true,
+				Mode:              "TARGET_UTILIZATION",
+				SubAccounts:       10,
+				Transactions:      100,
+				BatchSize:         50,
+				GasLimit:          21000,
+			},
+			wantErr: true,
+			errMsg:  "ephemeral-accounts is not supported in TARGET_UTILIZATION mode",
+		},
+		{
+			name: "master kms in TARGET_UTILIZATION mode",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				MasterKMS:    "awskms://alias/treasury",
+				Mode:         "TARGET_UTILIZATION",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+			},
+			wantErr: true,
+			errMsg:  "master-kms in TARGET_UTILIZATION mode needs funded sub-accounts",
+		},
+		{
+			name: "master kms with key file in LONG_SENDER mode",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				MasterKMS:    "awskms://alias/treasury",
+				KeyFile:      "keys.txt",
+				Mode:         "LONG_SENDER",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+			},
+			wantErr: false,
+		},
+		{
+			name: "master kms with unknown scheme",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				MasterKMS:    "vault://treasury",
+				Mode:         "TRANSFER",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+			},
+			wantErr: true,
+			errMsg:  "master-kms must be awskms://<key-id> or gcpkms://<key-version-name>",
+		},
+		{
+			name: "master kms with private key",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				MasterKMS:    "gcpkms://projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1",
+				Mode:         "TRANSFER",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+			},
+			wantErr: true,
+			errMsg:  "master-kms replaces private-key and mnemonic",
+		},
 		{
 			name: "invalid mode",
 			config: &Config{
@@ -147,6 +284,50 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "fee payer derived from mnemonic",
+			config: &Config{
+				URL:           "http://localhost:8545",
+				Mnemonic:      "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+				FeePayerIndex: 11,
+				Mode:          "FEE_DELEGATION",
+				SubAccounts:   10,
+				Transactions:  100,
+				BatchSize:     50,
+				GasLimit:      21000,
+			},
+			wantErr: false,
+		},
+		{
+			name: "fee payer index overlapping sub-accounts",
+			config: &Config{
+				URL:           "http://localhost:8545",
+				Mnemonic:      "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+				FeePayerIndex: 5,
+				Mode:          "FEE_DELEGATION",
+				SubAccounts:   10,
+				Transactions:  100,
+				BatchSize:     50,
+				GasLimit:      21000,
+			},
+			wantErr: true,
+			errMsg:  "fee-payer-index must be greater than sub-accounts",
+		},
+		{
+			name: "fee payer index without mnemonic",
+			config: &Config{
+				URL:           "http://localhost:8545",
+				PrivateKey:    "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				FeePayerIndex: 11,
+				Mode:          "FEE_DELEGATION",
+				SubAccounts:   10,
+				Transactions:  100,
+				BatchSize:     50,
+				GasLimit:      21000,
+			},
+			wantErr: true,
+			errMsg:  "fee-payer-index requires mnemonic",
+		},
 		{
 			name: "contract call without contract address",
 			config: &Config{
@@ -161,6 +342,19 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "contract address is required",
 		},
+		{
+			name: "approve transferFrom without token",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         "APPROVE_TRANSFERFROM",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+			},
+			wantErr: true,
+			errMsg:  "contract address is required",
+		},
 		{
 			name: "contract call without method",
 			config: &Config{
@@ -204,6 +398,110 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "transactions must be greater than 0",
 		},
+		{
+			name: "conflict mode with extra endpoints",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         "CONFLICT",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+				Endpoints:    []string{"http://localhost:8546", "ws://localhost:8547"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "conflict mode with invalid endpoint",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         "CONFLICT",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+				Endpoints:    []string{"localhost:8546"},
+			},
+			wantErr: true,
+			errMsg:  "must be a valid HTTP or WebSocket URL",
+		},
+		{
+			name: "conflict mode with a single variant",
+			config: &Config{
+				URL:              "http://localhost:8545",
+				PrivateKey:       "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:             "CONFLICT",
+				SubAccounts:      10,
+				Transactions:     100,
+				BatchSize:        50,
+				GasLimit:         21000,
+				ConflictVariants: 1,
+			},
+			wantErr: true,
+			errMsg:  "conflict-variants must be at least 2",
+		},
+		{
+			name: "account growth mode with negative rate",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         "ACCOUNT_GROWTH",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GrowthRate:   -1,
+			},
+			wantErr: true,
+			errMsg:  "growth-rate, growth-acceleration and growth-txs must not be negative",
+		},
+		{
+			name: "quiet with verbose",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         "TRANSFER",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+				Quiet:        true,
+				Verbose:      true,
+			},
+			wantErr: true,
+			errMsg:  "quiet and verbose cannot be used together",
+		},
+		{
+			name: "invalid color",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         "TRANSFER",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+				Color:        "rainbow",
+			},
+			wantErr: true,
+			errMsg:  "invalid color mode",
+		},
+		{
+			name: "invalid pushgateway",
+			config: &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         "TRANSFER",
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				GasLimit:     21000,
+				PushGateway:  "localhost:9091",
+			},
+			wantErr: true,
+			errMsg:  "pushgateway must be a valid HTTP URL",
+		},
 	}
 
 	for _, tt := range tests {
@@ -306,3 +604,455 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestConfig_DefaultGasLimit(t *testing.T) {
+	tests := []struct {
+		mode       string
+		churnCount int
+		expected   uint64
+	}{
+		{"TRANSFER", 0, 21000},
+		{"CONTRACT_DEPLOY", 0, 200000},
+		{"ERC721_MINT", 0, 150000},
+		{"CREATE2_CHURN", 0, 21000 + 10*45000},
+		{"CREATE2_CHURN", 4, 21000 + 4*45000},
+		{"ACCOUNT_GROWTH", 0, 21000},
+		{"DEPLOY_THEN_CALL", 0, 100000},
+		{"CONTENTION", 0, 50000},
+		{"CHAIN", 0, 21000},
+		{"SWAP", 0, 150000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         tt.mode,
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				ChurnCount:   tt.churnCount,
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() failed: %v", err)
+			}
+			if cfg.GasLimit != tt.expected {
+				t.Errorf("GasLimit = %d, want %d", cfg.GasLimit, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfig_GasLimitBelowEstimate(t *testing.T) {
+	var buf bytes.Buffer
+	console.SetOutput(&buf)
+	defer console.SetOutput(os.Stdout)
+
+	cfg := &Config{
+		URL:          "http://localhost:8545",
+		PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Mode:         "CONTRACT_DEPLOY",
+		SubAccounts:  10,
+		Transactions: 100,
+		BatchSize:    50,
+		GasLimit:     21000,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if cfg.GasLimit != 21000 {
+		t.Errorf("configured GasLimit was overridden: %d", cfg.GasLimit)
+	}
+	if !strings.Contains(buf.String(), "below the estimated 200000") {
+		t.Errorf("expected a gas limit warning, got %q", buf.String())
+	}
+}
+
+func TestParseAnalyzeFormat(t *testing.T) {
+	for _, name := range []string{"summary", "TABLE", " csv", "Json"} {
+		if _, err := ParseAnalyzeFormat(name); err != nil {
+			t.Errorf("ParseAnalyzeFormat(%q) error = %v", name, err)
+		}
+	}
+	if _, err := ParseAnalyzeFormat("xml"); err == nil {
+		t.Error("ParseAnalyzeFormat(\"xml\") expected error")
+	}
+}
+
+func TestConfig_AnalyzeOutput(t *testing.T) {
+	cfg := &Config{URL: "http://localhost:8545", Mode: "ANALYZE_BLOCKS"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if len(cfg.AnalyzeOutput) != 2 || cfg.AnalyzeOutput[0] != "table" || cfg.AnalyzeOutput[1] != "csv" {
+		t.Errorf("default AnalyzeOutput = %v, want [table csv]", cfg.AnalyzeOutput)
+	}
+
+	cfg = &Config{URL: "http://localhost:8545", Mode: "ANALYZE_BLOCKS", AnalyzeOutput: []string{"Summary", "JSON"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if cfg.AnalyzeOutput[0] != "summary" || cfg.AnalyzeOutput[1] != "json" {
+		t.Errorf("AnalyzeOutput = %v, want normalized [summary json]", cfg.AnalyzeOutput)
+	}
+
+	cfg = &Config{URL: "http://localhost:8545", Mode: "ANALYZE_BLOCKS", AnalyzeOutput: []string{"xml"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for an unknown analyze output")
+	}
+}
+
+func TestConfig_Value(t *testing.T) {
+	tests := []struct {
+		mode    string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{"TRANSFER", "", "1", ""},
+		{"FEE_DELEGATION", "", "1", ""},
+		{"CONTRACT_CALL", "", "0", ""},
+		{"CONTRACT_CALL", "1000", "1000", ""},
+		{"ERC20_TRANSFER", "", "0", ""},
+		{"ERC20_TRANSFER", "0", "0", ""},
+		{"ERC20_TRANSFER", "5", "", "value is not supported in ERC20_TRANSFER mode"},
+		{"TRANSFER", "-1", "", "value must be a non-negative integer amount in wei"},
+		{"TRANSFER", "1e18", "", "value must be a non-negative integer amount in wei"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+"/"+tt.value, func(t *testing.T) {
+			cfg := &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         tt.mode,
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				Value:        tt.value,
+				FeePayerKey:  "0xfedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+				Contract:     "0x1234567890123456789012345678901234567890",
+				Method:       "ping()",
+			}
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() failed: %v", err)
+			}
+			if cfg.Value != tt.want || cfg.ValueWei().String() != tt.want {
+				t.Errorf("Value = %s (%s wei), want %s", cfg.Value, cfg.ValueWei(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Recipient(t *testing.T) {
+	const recipient = "0x1234567890123456789012345678901234567890"
+	tests := []struct {
+		name      string
+		mode      string
+		recipient string
+		strategy  string
+		want      RecipientStrategy
+		wantErr   string
+	}{
+		{"default self", "TRANSFER", "", "", RecipientSelf, ""},
+		{"recipient implies fixed", "LONG_SENDER", recipient, "", RecipientFixed, ""},
+		{"explicit fixed", "TRANSFER", recipient, "FIXED", RecipientFixed, ""},
+		{"ring", "TARGET_UTILIZATION", "", "ring", RecipientRing, ""},
+		{"fixed without recipient", "TRANSFER", "", "fixed", "", "recipient-strategy fixed requires --recipient"},
+		{"recipient with ring", "TRANSFER", recipient, "ring", "", "recipient cannot be combined with recipient-strategy ring"},
+		{"unknown strategy", "TRANSFER", "", "random", "", `invalid recipient-strategy "random": must be self, fixed or ring`},
+		{"bad address", "TRANSFER", "0x1234", "", "", "recipient must be a valid 40-character hex address with 0x prefix"},
+		{"unsupported mode", "CONTRACT_DEPLOY", "", "ring", "", "recipient and recipient-strategy are not supported in CONTRACT_DEPLOY mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				URL:               "http://localhost:8545",
+				PrivateKey:        "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:              tt.mode,
+				SubAccounts:       10,
+				Transactions:      100,
+				BatchSize:         50,
+				Duration:          time.Minute,
+				Recipient:         tt.recipient,
+				RecipientStrategy: tt.strategy,
+			}
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() failed: %v", err)
+			}
+			if got := cfg.GetRecipientStrategy(); got != tt.want {
+				t.Errorf("GetRecipientStrategy() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_MultipleURLs(t *testing.T) {
+	cfg := &Config{
+		URL:          "http://node1:8545, http://node2:8545,ws://node3:8546",
+		Endpoints:    []string{"http://node4:8545"},
+		PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Mode:         "TRANSFER",
+		SubAccounts:  10,
+		Transactions: 100,
+		BatchSize:    50,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if cfg.URL != "http://node1:8545" || !cfg.MultiURL {
+		t.Errorf("URL = %q, MultiURL = %v, want the first entry of several", cfg.URL, cfg.MultiURL)
+	}
+	want := []string{"http://node2:8545", "ws://node3:8546", "http://node4:8545"}
+	if !slices.Equal(cfg.Endpoints, want) {
+		t.Errorf("Endpoints = %v, want %v", cfg.Endpoints, want)
+	}
+
+	cfg.URL = "http://node1:8545,node2"
+	cfg.Endpoints = nil
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject an invalid extra URL")
+	}
+}
+
+func TestConfig_TxType(t *testing.T) {
+	tests := []struct {
+		mode    string
+		txType  string
+		want    string
+		wantErr string
+	}{
+		{"TRANSFER", "", "", ""},
+		{"TRANSFER", "EIP2930", "eip2930", ""},
+		{"ERC20_TRANSFER", "legacy", "legacy", ""},
+		{"TRANSFER", "eip4844", "", `invalid tx-type "eip4844": must be legacy, eip2930 or eip1559`},
+		{"CONTRACT_DEPLOY", "legacy", "", "tx-type is not supported in CONTRACT_DEPLOY mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+"/"+tt.txType, func(t *testing.T) {
+			cfg := &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         tt.mode,
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				Contract:     "0x1234567890123456789012345678901234567890",
+				TxType:       tt.txType,
+			}
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() failed: %v", err)
+			}
+			if cfg.TxType != tt.want {
+				t.Errorf("TxType = %q, want %q", cfg.TxType, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Swap(t *testing.T) {
+	const router = "0x1234567890123456789012345678901234567890"
+	tokens := []string{"0x2222222222222222222222222222222222222222", "0x3333333333333333333333333333333333333333"}
+	tests := []struct {
+		name     string
+		mode     string
+		contract string
+		path     []string
+		amount   string
+		wantErr  string
+	}{
+		{"embedded pair", "SWAP", "", nil, "", ""},
+		{"existing pair", "SWAP", router, nil, "5000", ""},
+		{"router path", "SWAP", router, tokens, "", ""},
+		{"path without router", "SWAP", "", tokens, "", "swap-path requires --contract set to the router address"},
+		{"single-token path", "SWAP", router, tokens[:1], "", "swap-path needs at least two token addresses"},
+		{"bad token", "SWAP", router, []string{tokens[0], "0x12"}, "", `swap-path token "0x12" must be a valid 40-character hex address with 0x prefix`},
+		{"zero amount", "SWAP", "", nil, "0", "swap-amount must be a positive integer amount in token base units"},
+		{"unsupported mode", "TRANSFER", "", nil, "10", "swap-path and swap-amount are not supported in TRANSFER mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				URL:          "http://localhost:8545",
+				PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				Mode:         tt.mode,
+				SubAccounts:  10,
+				Transactions: 100,
+				BatchSize:    50,
+				Contract:     tt.contract,
+				SwapPath:     tt.path,
+				SwapAmount:   tt.amount,
+			}
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() failed: %v", err)
+			}
+			if tt.amount != "" && cfg.SwapAmountWei().String() != tt.amount {
+				t.Errorf("SwapAmountWei() = %s, want %s", cfg.SwapAmountWei(), tt.amount)
+			}
+		})
+	}
+}
+
+func TestRegisterMode(t *testing.T) {
+	if err := RegisterMode("custom_config_test", 70000); err != nil {
+		t.Fatalf("RegisterMode() error = %v", err)
+	}
+	if err := RegisterMode("CUSTOM_CONFIG_TEST", 70000); err == nil {
+		t.Error("expected error registering a mode twice")
+	}
+	if err := RegisterMode("transfer", 21000); err == nil {
+		t.Error("expected error shadowing a built-in mode")
+	}
+	if err := RegisterMode("NO_GAS", 0); err == nil {
+		t.Error("expected error without a gas limit")
+	}
+	if !slices.Contains(CustomModes(), Mode("CUSTOM_CONFIG_TEST")) {
+		t.Errorf("CustomModes() = %v, want CUSTOM_CONFIG_TEST", CustomModes())
+	}
+
+	cfg := &Config{
+		URL:          "http://localhost:8545",
+		PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Mode:         "Custom_Config_Test",
+		SubAccounts:  10,
+		Transactions: 100,
+		BatchSize:    50,
+		Value:        "5",
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() rejected a registered mode: %v", err)
+	}
+	if cfg.GasLimit != 70000 {
+		t.Errorf("GasLimit = %d, want the registered 70000", cfg.GasLimit)
+	}
+
+	cfg.Mode = "UNREGISTERED"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "CUSTOM_CONFIG_TEST") {
+		t.Errorf("Validate() error = %v, want it to list the registered mode", err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"run.yaml": `
+url: http://localhost:8545
+transactions: 1000
+streaming: true
+gas-price: "2gwei"
+workload: [TRANSFER:500, CONTRACT_CALL:50]
+expect-event:
+  - Transfer(address,address,uint256)
+`,
+		"run.toml": `
+url = "http://localhost:8545" # the node
+transactions = 1_000
+streaming = true
+gas-price = '2gwei'
+workload = [
+  "TRANSFER:500",
+  "CONTRACT_CALL:50",
+]
+expect-event = ["Transfer(address,address,uint256)"]
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		settings, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: ReadFile() error: %v", name, err)
+		}
+		want := map[string]Setting{
+			"url":          {Values: []string{"http://localhost:8545"}},
+			"transactions": {Values: []string{"1000"}},
+			"streaming":    {Values: []string{"true"}},
+			"gas-price":    {Values: []string{"2gwei"}},
+			"workload":     {Values: []string{"TRANSFER:500", "CONTRACT_CALL:50"}, List: true},
+			"expect-event": {Values: []string{"Transfer(address,address,uint256)"}, List: true},
+		}
+		if len(settings) != len(want) {
+			t.Errorf("%s: keys = %v, want %d", name, SortedKeys(settings), len(want))
+		}
+		for key, w := range want {
+			if got := settings[key]; got.List != w.List || !slices.Equal(got.Values, w.Values) {
+				t.Errorf("%s: %s = %+v, want %+v", name, key, got, w)
+			}
+		}
+	}
+
+	invalid := map[string]string{
+		"table.toml":    "[gas]\ngas-price = \"1\"\n",
+		"bare.toml":     "mode = TRANSFER\n",
+		"dup.toml":      "batch = 1\nbatch = 2\n",
+		"nested.yaml":   "gas:\n  price: 1\n",
+		"settings.json": "{}",
+	}
+	for name, content := range invalid {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadFile(path); err == nil {
+			t.Errorf("%s: ReadFile() expected an error", name)
+		}
+	}
+}
+
+func TestConfig_Scenario(t *testing.T) {
+	cfg := &Config{
+		URL:          "http://localhost:8545",
+		PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Mode:         "SCENARIO",
+		SubAccounts:  10,
+		Transactions: 100,
+		BatchSize:    50,
+		TxType:       "eip1559",
+	}
+	if err := cfg.Validate(); err == nil || err.Error() != "scenario file is required for SCENARIO mode" {
+		t.Fatalf("Validate() error = %v, want the missing scenario file", err)
+	}
+
+	// Mode-specific settings are left to the phases
+	cfg.Scenario = "ramp.yaml"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if cfg.GasLimit != 0 || cfg.Value != "" {
+		t.Errorf("mode defaults applied to the scenario: gas limit %d, value %q", cfg.GasLimit, cfg.Value)
+	}
+}
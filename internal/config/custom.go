@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	customModesMu sync.RWMutex
+	customModes   = make(map[Mode]uint64) // Default gas limit per custom mode
+)
+
+// RegisterMode adds a custom mode that Validate accepts, with the default gas
+// limit used when --gas-limit is not set. Names are case-insensitive and may
+// not shadow a built-in or already registered mode.
+func RegisterMode(mode Mode, gasLimit uint64) error {
+	mode = Mode(strings.ToUpper(strings.TrimSpace(string(mode))))
+	if mode == "" {
+		return fmt.Errorf("custom mode name must not be empty")
+	}
+	if gasLimit == 0 {
+		return fmt.Errorf("custom mode %s needs a default gas limit", mode)
+	}
+	if slices.Contains(Modes(), mode) {
+		return fmt.Errorf("mode %s is built in", mode)
+	}
+
+	customModesMu.Lock()
+	defer customModesMu.Unlock()
+	if _, ok := customModes[mode]; ok {
+		return fmt.Errorf("mode %s is already registered", mode)
+	}
+	customModes[mode] = gasLimit
+	return nil
+}
+
+// IsCustomMode reports whether the mode was added with RegisterMode
+func IsCustomMode(mode Mode) bool {
+	customModesMu.RLock()
+	defer customModesMu.RUnlock()
+	_, ok := customModes[mode]
+	return ok
+}
+
+// CustomModes returns the registered custom modes, sorted by name
+func CustomModes() []Mode {
+	customModesMu.RLock()
+	defer customModesMu.RUnlock()
+	modes := make([]Mode, 0, len(customModes))
+	for mode := range customModes {
+		modes = append(modes, mode)
+	}
+	slices.Sort(modes)
+	return modes
+}
+
+// customGasLimit returns the default gas limit of a custom mode, or 0
+func customGasLimit(mode Mode) uint64 {
+	customModesMu.RLock()
+	defer customModesMu.RUnlock()
+	return customModes[mode]
+}
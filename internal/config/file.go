@@ -0,0 +1,250 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats
+const (
+	FileFormatYAML = "yaml"
+	FileFormatTOML = "toml"
+)
+
+// Setting is the value of one key of a config file, in flag syntax: a scalar
+// has one value, a list one value per element
+type Setting struct {
+	Values []string
+	List   bool
+}
+
+// FileFormat returns the config file format of path by its extension
+func FileFormat(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return FileFormatYAML, nil
+	case ".toml":
+		return FileFormatTOML, nil
+	default:
+		return "", fmt.Errorf("config file %s: unknown extension %q (valid: .yaml, .yml, .toml)", path, ext)
+	}
+}
+
+// ReadFile reads a YAML or TOML config file of top-level keys named after
+// the command-line flags, such as url, mode or gas-price
+func ReadFile(path string) (map[string]Setting, error) {
+	format, err := FileFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var settings map[string]Setting
+	if format == FileFormatYAML {
+		settings, err = parseYAML(data)
+	} else {
+		settings, err = parseTOML(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// SortedKeys returns the keys of settings in order
+func SortedKeys(settings map[string]Setting) []string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseYAML reads a YAML mapping of scalars and lists of scalars
+func parseYAML(data []byte) (map[string]Setting, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]Setting, len(doc))
+	for key, value := range doc {
+		if list, ok := value.([]any); ok {
+			setting := Setting{List: true}
+			for _, element := range list {
+				s, err := yamlScalar(element)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", key, err)
+				}
+				setting.Values = append(setting.Values, s)
+			}
+			settings[key] = setting
+			continue
+		}
+		s, err := yamlScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		settings[key] = Setting{Values: []string{s}}
+	}
+	return settings, nil
+}
+
+// yamlScalar formats a decoded YAML scalar in flag syntax
+func yamlScalar(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool, int, int64, uint64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("nested values are not supported")
+	}
+}
+
+// parseTOML reads the TOML subset a flag file needs: top-level keys with
+// string, number, boolean and array values. Tables are not supported.
+func parseTOML(data []byte) (map[string]Setting, error) {
+	settings := make(map[string]Setting)
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(stripTOMLComment(lines[i]))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			return nil, fmt.Errorf("line %d: tables are not supported, use top-level keys", lineNo)
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(key); err == nil {
+			key = unquoted
+		}
+		if key == "" {
+			return nil, fmt.Errorf("line %d: missing key", lineNo)
+		}
+		if _, dup := settings[key]; dup {
+			return nil, fmt.Errorf("line %d: %s set twice", lineNo, key)
+		}
+
+		// An array may span lines up to its closing bracket
+		for strings.HasPrefix(value, "[") && !strings.HasSuffix(value, "]") && i+1 < len(lines) {
+			i++
+			value += " " + strings.TrimSpace(stripTOMLComment(lines[i]))
+		}
+
+		setting, err := tomlValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", lineNo, key, err)
+		}
+		settings[key] = setting
+	}
+	return settings, nil
+}
+
+// tomlValue parses a TOML scalar or array of scalars
+func tomlValue(value string) (Setting, error) {
+	if !strings.HasPrefix(value, "[") {
+		s, err := tomlScalar(value)
+		return Setting{Values: []string{s}}, err
+	}
+	if !strings.HasSuffix(value, "]") {
+		return Setting{}, fmt.Errorf("unterminated array")
+	}
+
+	setting := Setting{List: true}
+	for _, element := range splitTOMLArray(value[1 : len(value)-1]) {
+		s, err := tomlScalar(element)
+		if err != nil {
+			return Setting{}, err
+		}
+		setting.Values = append(setting.Values, s)
+	}
+	return setting, nil
+}
+
+// tomlScalar parses a TOML string, number or boolean
+func tomlScalar(value string) (string, error) {
+	switch {
+	case value == "":
+		return "", fmt.Errorf("missing value")
+	case strings.HasPrefix(value, `"`):
+		return strconv.Unquote(value)
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		return value[1 : len(value)-1], nil
+	case value == "true" || value == "false":
+		return value, nil
+	case strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{"):
+		return "", fmt.Errorf("nested values are not supported")
+	}
+	number := strings.ReplaceAll(value, "_", "")
+	if _, err := strconv.ParseFloat(number, 64); err != nil {
+		return "", fmt.Errorf("invalid value %s (strings must be quoted)", value)
+	}
+	return number, nil
+}
+
+// splitTOMLArray splits the inside of an array at the commas between elements
+func splitTOMLArray(s string) []string {
+	var elements []string
+	var quote rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || i == 0 || s[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			elements = append(elements, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	// A trailing comma is allowed
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		elements = append(elements, last)
+	}
+	return elements
+}
+
+// stripTOMLComment removes a # comment that is not inside a string
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || line[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
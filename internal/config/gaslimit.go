@@ -0,0 +1,53 @@
+package config
+
+import "github.com/0xmhha/txhammer/internal/util/console"
+
+// defaultGasLimits is the estimated gas per transaction of each mode, used
+// as its gas limit when --gas-limit is not set
+var defaultGasLimits = map[Mode]uint64{
+	ModeTransfer:            21000,
+	ModeFeeDelegation:       21000,
+	ModeContractDeploy:      200000,
+	ModeContractCall:        100000,
+	ModeERC20Transfer:       65000,
+	ModeLongSender:          21000,
+	ModeERC721Mint:          150000,
+	ModeTargetUtilization:   21000,
+	ModeConflict:            21000,
+	ModeAccountGrowth:       21000,
+	ModeDeployThenCall:      100000,
+	ModeContention:          50000,
+	ModeChain:               21000,
+	ModeApproveTransferFrom: 80000,
+	ModeSwap:                150000,
+}
+
+// churnGasPerContract approximates the gas of one CREATE2 plus the child's self-destruct
+const churnGasPerContract = 45000
+
+// RequiredGasLimit returns the estimated gas one transaction of the mode
+// needs, which is also its default gas limit. CREATE2_CHURN scales with the
+// churn count, and custom modes use the limit they were registered with.
+// Modes that send no transactions return 0.
+func (c *Config) RequiredGasLimit(mode Mode) uint64 {
+	if mode == ModeCreate2Churn {
+		return 21000 + uint64(c.ChurnCount)*churnGasPerContract
+	}
+	if gasLimit := customGasLimit(mode); gasLimit > 0 {
+		return gasLimit
+	}
+	return defaultGasLimits[mode]
+}
+
+// applyGasLimit fills in the mode's default gas limit when none is configured
+// and warns when the configured limit is below the mode's estimate
+func (c *Config) applyGasLimit(mode Mode) {
+	required := c.RequiredGasLimit(mode)
+	if c.GasLimit == 0 {
+		c.GasLimit = required
+		return
+	}
+	if c.GasLimit < required {
+		console.Warnf("gas-limit %d is below the estimated %d that %s transactions need\n", c.GasLimit, required, mode)
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RecipientStrategy selects where the value transfers of a run are sent
+type RecipientStrategy string
+
+const (
+	// RecipientSelf has every account transfer to itself
+	RecipientSelf RecipientStrategy = "self"
+	// RecipientFixed has every account transfer to --recipient, an account or a contract
+	RecipientFixed RecipientStrategy = "fixed"
+	// RecipientRing has every sub-account transfer to the next one, the last to the first
+	RecipientRing RecipientStrategy = "ring"
+)
+
+// RecipientStrategies returns all recipient strategies
+func RecipientStrategies() []RecipientStrategy {
+	return []RecipientStrategy{RecipientSelf, RecipientFixed, RecipientRing}
+}
+
+// recipientModes are the modes whose transfers follow --recipient-strategy
+var recipientModes = []Mode{ModeTransfer, ModeFeeDelegation, ModeLongSender, ModeTargetUtilization}
+
+// validateRecipient checks --recipient and --recipient-strategy against each other and the mode
+func (c *Config) validateRecipient(mode Mode) error {
+	if c.Recipient != "" && !addressRegex.MatchString(c.Recipient) {
+		return errors.New("recipient must be a valid 40-character hex address with 0x prefix")
+	}
+	if c.Recipient == "" && c.RecipientStrategy == "" {
+		return nil
+	}
+	if !slices.Contains(recipientModes, mode) {
+		return fmt.Errorf("recipient and recipient-strategy are not supported in %s mode", mode)
+	}
+
+	strategy := c.GetRecipientStrategy()
+	switch {
+	case c.RecipientStrategy == "":
+		return nil
+	case !slices.Contains(RecipientStrategies(), strategy):
+		return fmt.Errorf("invalid recipient-strategy %q: must be self, fixed or ring", c.RecipientStrategy)
+	case strategy == RecipientFixed && c.Recipient == "":
+		return errors.New("recipient-strategy fixed requires --recipient")
+	case strategy != RecipientFixed && c.Recipient != "":
+		return fmt.Errorf("recipient cannot be combined with recipient-strategy %s", strategy)
+	}
+	return nil
+}
+
+// GetRecipientStrategy returns the parsed recipient strategy. Without
+// --recipient-strategy, transfers go to --recipient if set and to self otherwise.
+func (c *Config) GetRecipientStrategy() RecipientStrategy {
+	if c.RecipientStrategy == "" {
+		if c.Recipient != "" {
+			return RecipientFixed
+		}
+		return RecipientSelf
+	}
+	return RecipientStrategy(strings.ToLower(c.RecipientStrategy))
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// validateSwap checks --swap-path and --swap-amount. A path routes the swaps
+// through the router at --contract; without one, --contract names an
+// already deployed embedded pair.
+func (c *Config) validateSwap(mode Mode) error {
+	if mode != ModeSwap {
+		if len(c.SwapPath) > 0 || c.SwapAmount != "" {
+			return fmt.Errorf("swap-path and swap-amount are not supported in %s mode", mode)
+		}
+		return nil
+	}
+
+	if c.Contract != "" && !addressRegex.MatchString(c.Contract) {
+		return errors.New("contract must be a valid 40-character hex address with 0x prefix")
+	}
+	if len(c.SwapPath) > 0 {
+		if c.Contract == "" {
+			return errors.New("swap-path requires --contract set to the router address")
+		}
+		if len(c.SwapPath) < 2 {
+			return errors.New("swap-path needs at least two token addresses")
+		}
+		for _, token := range c.SwapPath {
+			if !addressRegex.MatchString(token) {
+				return fmt.Errorf("swap-path token %q must be a valid 40-character hex address with 0x prefix", token)
+			}
+		}
+	}
+	if c.SwapAmount != "" {
+		amount, ok := new(big.Int).SetString(c.SwapAmount, 10)
+		if !ok || amount.Sign() <= 0 {
+			return errors.New("swap-amount must be a positive integer amount in token base units")
+		}
+	}
+	return nil
+}
+
+// SwapAmountWei returns the input amount of each swap, or nil for the builder's default
+func (c *Config) SwapAmountWei() *big.Int {
+	amount, ok := new(big.Int).SetString(c.SwapAmount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil
+	}
+	return amount
+}
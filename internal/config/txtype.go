@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TxType selects the envelope of the transactions a builder signs
+type TxType string
+
+const (
+	// TxTypeLegacy is a type-0 transaction with a single gas price
+	TxTypeLegacy TxType = "legacy"
+	// TxTypeEIP2930 is a type-1 transaction with an access list
+	TxTypeEIP2930 TxType = "eip2930"
+	// TxTypeEIP1559 is a type-2 transaction with a tip and a fee cap
+	TxTypeEIP1559 TxType = "eip1559"
+)
+
+// TxTypes returns all transaction types
+func TxTypes() []TxType {
+	return []TxType{TxTypeLegacy, TxTypeEIP2930, TxTypeEIP1559}
+}
+
+// txTypeModes are the modes whose builders follow --tx-type
+var txTypeModes = []Mode{ModeTransfer, ModeContractCall, ModeERC20Transfer}
+
+// validateTxType checks --tx-type against the mode and normalizes it
+func (c *Config) validateTxType(mode Mode) error {
+	if c.TxType == "" {
+		return nil
+	}
+	c.TxType = strings.ToLower(c.TxType)
+	if !slices.Contains(TxTypes(), TxType(c.TxType)) {
+		return fmt.Errorf("invalid tx-type %q: must be legacy, eip2930 or eip1559", c.TxType)
+	}
+	if !slices.Contains(txTypeModes, mode) {
+		return fmt.Errorf("tx-type is not supported in %s mode", mode)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// valueModes are the modes whose transactions carry --value, along with
+// custom modes; the others send no native value, or a value they compute
+// themselves
+var valueModes = []Mode{ModeTransfer, ModeFeeDelegation, ModeLongSender, ModeTargetUtilization, ModeContractCall}
+
+// validateValue checks --value and rejects a non-zero value in modes that
+// would ignore it
+func (c *Config) validateValue(mode Mode) error {
+	if c.Value == "" {
+		return nil
+	}
+	value, ok := new(big.Int).SetString(c.Value, 10)
+	if !ok || value.Sign() < 0 {
+		return errors.New("value must be a non-negative integer amount in wei")
+	}
+	if value.Sign() > 0 && !slices.Contains(valueModes, mode) && !IsCustomMode(mode) {
+		return fmt.Errorf("value is not supported in %s mode", mode)
+	}
+	return nil
+}
+
+// applyValue fills in the mode's default value: 1 wei for transfers, and
+// nothing for contract calls, which would revert on a non-payable method
+func (c *Config) applyValue(mode Mode) {
+	if c.Value != "" {
+		return
+	}
+	c.Value = "0"
+	if mode != ModeContractCall && slices.Contains(valueModes, mode) {
+		c.Value = "1"
+	}
+}
+
+// ValueWei returns the value each transaction sends, in wei
+func (c *Config) ValueWei() *big.Int {
+	value, ok := new(big.Int).SetString(c.Value, 10)
+	if !ok || value.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return value
+}
@@ -0,0 +1,238 @@
+package conflict
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Tester sends differing transactions with the same nonce and records which
+// one the chain keeps
+type Tester struct {
+	client    Client
+	endpoints []Endpoint
+	config    *Config
+}
+
+// New creates a new Tester instance. Variants are spread round-robin over the
+// endpoints; with no endpoints every variant goes to the client.
+func New(client Client, endpoints []Endpoint, config *Config) *Tester {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if len(endpoints) == 0 {
+		endpoints = []Endpoint{{URL: "primary", Sender: client}}
+	}
+	return &Tester{
+		client:    client,
+		endpoints: endpoints,
+		config:    config,
+	}
+}
+
+// Run contests Rounds nonces, spread round-robin over the keys. Each account
+// plays its rounds in sequence; accounts run concurrently.
+func (t *Tester) Run(ctx context.Context, keys []*ecdsa.PrivateKey) (*Result, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys provided")
+	}
+	if t.config.Variants < 2 {
+		return nil, fmt.Errorf("at least 2 variants are required")
+	}
+
+	perKey := make([][]*Round, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		rounds := t.config.Rounds / len(keys)
+		if i < t.config.Rounds%len(keys) {
+			rounds++
+		}
+		if rounds == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, key *ecdsa.PrivateKey, rounds int) {
+			defer wg.Done()
+			for r := 0; r < rounds && ctx.Err() == nil; r++ {
+				perKey[i] = append(perKey[i], t.playRound(ctx, key))
+			}
+		}(i, key, rounds)
+	}
+	wg.Wait()
+
+	var rounds []*Round
+	for _, rs := range perKey {
+		rounds = append(rounds, rs...)
+	}
+	return Summarize(rounds, t.config.Variants), ctx.Err()
+}
+
+// playRound sends every variant for the account's next nonce and waits for one to be mined
+func (t *Tester) playRound(ctx context.Context, key *ecdsa.PrivateKey) *Round {
+	start := time.Now()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	round := &Round{Account: from}
+
+	nonce, err := t.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		round.Err = fmt.Errorf("failed to get nonce: %w", err)
+		return round
+	}
+	round.Nonce = nonce
+
+	rawTxs := make([][]byte, t.config.Variants)
+	for i := range rawTxs {
+		variant, raw, err := t.buildVariant(key, nonce, i)
+		if err != nil {
+			round.Err = err
+			return round
+		}
+		round.Variants = append(round.Variants, variant)
+		rawTxs[i] = raw
+	}
+
+	// Release every variant at once so they race to the pool
+	var wg sync.WaitGroup
+	for i, variant := range round.Variants {
+		wg.Add(1)
+		go func(v *Variant, raw []byte) {
+			defer wg.Done()
+			endpoint := t.endpoints[v.Index%len(t.endpoints)]
+			v.Endpoint = endpoint.URL
+			_, v.SendErr = endpoint.Sender.SendRawTransaction(ctx, raw)
+		}(variant, rawTxs[i])
+	}
+	wg.Wait()
+
+	t.waitMined(ctx, round)
+	round.Duration = time.Since(start)
+	return round
+}
+
+// buildVariant signs a self-transfer whose value identifies the variant
+func (t *Tester) buildVariant(key *ecdsa.PrivateKey, nonce uint64, index int) (*Variant, []byte, error) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: t.config.GasPrice,
+		Gas:      t.config.GasLimit,
+		To:       &from,
+		Value:    big.NewInt(int64(index + 1)),
+	})
+	signedTx, err := types.SignTx(tx, types.NewLondonSigner(t.config.ChainID), key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign variant %d: %w", index, err)
+	}
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode variant %d: %w", index, err)
+	}
+	return &Variant{Index: index, Hash: signedTx.Hash()}, raw, nil
+}
+
+// waitMined polls the receipts of every variant until one is mined or the timeout elapses
+func (t *Tester) waitMined(ctx context.Context, round *Round) {
+	waitCtx, cancel := context.WithTimeout(ctx, t.config.ReceiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Check every variant each time so a second mined variant is not missed
+		for _, v := range round.Variants {
+			if v.Mined {
+				continue
+			}
+			receipt, err := t.client.TransactionReceipt(waitCtx, v.Hash)
+			if err != nil || receipt == nil {
+				continue
+			}
+			v.Mined = true
+			if receipt.BlockNumber != nil {
+				v.BlockNumber = receipt.BlockNumber.Uint64()
+			}
+			round.Mined = append(round.Mined, v)
+		}
+		if len(round.Mined) > 0 {
+			return
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Summarize tallies which variant won each round and how the losers were handled
+func Summarize(rounds []*Round, variants int) *Result {
+	result := &Result{
+		Rounds:         rounds,
+		MinedByVariant: make([]int, variants),
+		Outcomes:       make(map[string]int),
+	}
+	for _, round := range rounds {
+		if round.Err != nil {
+			result.Errors++
+			continue
+		}
+		switch len(round.Mined) {
+		case 0:
+			result.NoneMined++
+		case 1:
+			result.MinedByVariant[round.Mined[0].Index]++
+		default:
+			result.MultiMined++
+		}
+		for _, v := range round.Variants {
+			if !v.Mined {
+				result.Outcomes[v.Outcome()]++
+			}
+		}
+	}
+	return result
+}
+
+// PrintResult prints the conflict test summary
+func PrintResult(result *Result) {
+	console.Summaryf("\nConflict Results\n\n")
+	console.Summaryf("  Rounds:            %d\n", len(result.Rounds))
+	for i, n := range result.MinedByVariant {
+		console.Summaryf("  Variant %d mined:   %d\n", i, n)
+	}
+	console.Summaryf("  None mined:        %d\n", result.NoneMined)
+	console.Summaryf("  Double spends:     %d\n", result.MultiMined)
+	if result.Errors > 0 {
+		console.Summaryf("  Round errors:      %d\n", result.Errors)
+	}
+
+	if len(result.Outcomes) > 0 {
+		outcomes := make([]string, 0, len(result.Outcomes))
+		for o := range result.Outcomes {
+			outcomes = append(outcomes, o)
+		}
+		sort.Slice(outcomes, func(i, j int) bool { return result.Outcomes[outcomes[i]] > result.Outcomes[outcomes[j]] })
+
+		console.Summaryf("\nLosing Variants:\n")
+		for _, o := range outcomes {
+			console.Summaryf("  %6d  %s\n", result.Outcomes[o], o)
+		}
+	}
+
+	if result.MultiMined > 0 {
+		console.Warnf("\n%d nonce(s) were mined more than once\n", result.MultiMined)
+	} else if result.NoneMined == 0 && result.Errors == 0 {
+		console.Summaryf("\n%s Exactly one variant was mined for every nonce\n", console.Marker("[OK]"))
+	}
+}
@@ -0,0 +1,128 @@
+package conflict
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// mockClient accepts the first transaction per nonce and mines it immediately
+type mockClient struct {
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+	seen   map[common.Address]map[uint64]bool
+	mined  map[common.Hash]bool
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{
+		nonces: make(map[common.Address]uint64),
+		seen:   make(map[common.Address]map[uint64]bool),
+		mined:  make(map[common.Hash]bool),
+	}
+}
+
+func (m *mockClient) SendRawTransaction(_ context.Context, rawTx []byte) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return common.Hash{}, err
+	}
+	from, err := types.Sender(types.NewLondonSigner(tx.ChainId()), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.seen[from] == nil {
+		m.seen[from] = make(map[uint64]bool)
+	}
+	if m.seen[from][tx.Nonce()] {
+		return common.Hash{}, errors.New("replacement transaction underpriced")
+	}
+	m.seen[from][tx.Nonce()] = true
+	m.mined[tx.Hash()] = true
+	m.nonces[from] = tx.Nonce() + 1
+	return tx.Hash(), nil
+}
+
+func (m *mockClient) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nonces[account], nil
+}
+
+func (m *mockClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.mined[txHash] {
+		return nil, errors.New("not found")
+	}
+	return &types.Receipt{TxHash: txHash, BlockNumber: big.NewInt(1)}, nil
+}
+
+func TestTester_Run(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.ChainID = big.NewInt(1337)
+	cfg.GasPrice = big.NewInt(1)
+	cfg.Rounds = 3
+	cfg.Variants = 3
+	cfg.ReceiptTimeout = time.Second
+	cfg.PollInterval = 10 * time.Millisecond
+
+	result, err := New(newMockClient(), nil, cfg).Run(context.Background(), []*ecdsa.PrivateKey{key})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(result.Rounds) != 3 {
+		t.Fatalf("expected 3 rounds, got %d", len(result.Rounds))
+	}
+	for i, round := range result.Rounds {
+		if round.Nonce != uint64(i) {
+			t.Errorf("round %d nonce = %d, want %d", i, round.Nonce, i)
+		}
+		if len(round.Mined) != 1 {
+			t.Errorf("round %d: expected exactly one mined variant, got %d", i, len(round.Mined))
+		}
+	}
+	if result.NoneMined != 0 || result.MultiMined != 0 {
+		t.Errorf("unexpected none=%d multi=%d", result.NoneMined, result.MultiMined)
+	}
+	if got := result.Outcomes["rejected: replacement transaction underpriced"]; got != 6 {
+		t.Errorf("rejected losers = %d, want 6", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	a := &Variant{Index: 0, Mined: true}
+	b := &Variant{Index: 1, Mined: true}
+	dropped := &Variant{Index: 1}
+	rounds := []*Round{
+		{Variants: []*Variant{a, dropped}, Mined: []*Variant{a}},
+		{Variants: []*Variant{a, b}, Mined: []*Variant{a, b}},
+		{Variants: []*Variant{{Index: 0}, {Index: 1}}},
+		{Err: errors.New("no nonce")},
+	}
+
+	result := Summarize(rounds, 2)
+	if result.MinedByVariant[0] != 1 || result.MultiMined != 1 || result.NoneMined != 1 || result.Errors != 1 {
+		t.Errorf("unexpected summary: %+v", result)
+	}
+	if result.Outcomes["accepted, not mined"] != 3 {
+		t.Errorf("accepted losers = %d, want 3", result.Outcomes["accepted, not mined"])
+	}
+}
@@ -0,0 +1,96 @@
+package conflict
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Sender submits raw transactions to a node
+type Sender interface {
+	// SendRawTransaction sends a signed, encoded transaction
+	SendRawTransaction(ctx context.Context, rawTx []byte) (common.Hash, error)
+}
+
+// Client defines the interface for sending conflicting transactions and
+// observing which one is mined
+type Client interface {
+	Sender
+	// PendingNonceAt returns the next nonce of an account including pending txs
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	// TransactionReceipt returns the receipt of a mined transaction
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Endpoint is a named node that conflicting variants are sent to
+type Endpoint struct {
+	URL    string
+	Sender Sender
+}
+
+// Config holds configuration for conflict testing
+type Config struct {
+	ChainID        *big.Int
+	GasPrice       *big.Int
+	GasLimit       uint64
+	Rounds         int           // Number of nonces contested
+	Variants       int           // Differing transactions sent per nonce
+	ReceiptTimeout time.Duration // How long to wait for one variant to be mined
+	PollInterval   time.Duration // How often to poll for receipts
+}
+
+// DefaultConfig returns default conflict configuration
+func DefaultConfig() *Config {
+	return &Config{
+		GasLimit:       21000,
+		Rounds:         10,
+		Variants:       2,
+		ReceiptTimeout: 60 * time.Second,
+		PollInterval:   500 * time.Millisecond,
+	}
+}
+
+// Variant is one of the transactions competing for a nonce
+type Variant struct {
+	Index       int
+	Hash        common.Hash
+	Endpoint    string
+	SendErr     error  // Set if the endpoint rejected the variant
+	Mined       bool   // Whether a receipt was found for the variant
+	BlockNumber uint64 // Inclusion block if mined
+}
+
+// Outcome describes how the node handled a variant that was not mined
+func (v *Variant) Outcome() string {
+	switch {
+	case v.Mined:
+		return "mined"
+	case v.SendErr != nil:
+		return "rejected: " + v.SendErr.Error()
+	default:
+		return "accepted, not mined"
+	}
+}
+
+// Round is one contested nonce of one account
+type Round struct {
+	Account  common.Address
+	Nonce    uint64
+	Variants []*Variant
+	Mined    []*Variant // More than one entry means the chain accepted a double spend
+	Duration time.Duration
+	Err      error // Set if the round could not run
+}
+
+// Result holds the outcome of a conflict test
+type Result struct {
+	Rounds         []*Round
+	MinedByVariant []int          // Rounds won by each variant index
+	NoneMined      int            // Rounds in which no variant was mined
+	MultiMined     int            // Rounds in which more than one variant was mined
+	Outcomes       map[string]int // How losing variants were handled
+	Errors         int            // Rounds that could not run
+}
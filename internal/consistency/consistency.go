@@ -0,0 +1,154 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxConcurrent bounds the header requests in flight per endpoint
+const maxConcurrent = 8
+
+// Client defines the interface for reading block headers from one endpoint
+type Client interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Endpoint is a node whose view of the chain is compared
+type Endpoint struct {
+	Name   string
+	Client Client
+}
+
+// View is what one endpoint reports for a block
+type View struct {
+	Endpoint  string      `json:"endpoint"`
+	Hash      common.Hash `json:"hash,omitempty"`
+	StateRoot common.Hash `json:"state_root,omitempty"`
+	Missing   bool        `json:"missing,omitempty"` // The endpoint does not have the block (yet)
+	Error     string      `json:"error,omitempty"`
+}
+
+// Divergence is a block for which endpoints report different hashes or state roots
+type Divergence struct {
+	Block       uint64 `json:"block"`
+	HashDiffers bool   `json:"hash_differs"`       // Endpoints are on different forks
+	RootDiffers bool   `json:"state_root_differs"` // Executing the block gave different state
+	Views       []View `json:"views"`              // One per endpoint, in endpoint order
+}
+
+// EndpointStats counts the blocks one endpoint could not be compared on
+type EndpointStats struct {
+	Name    string `json:"name"`
+	Missing int    `json:"missing"` // Blocks the endpoint did not have
+	Errors  int    `json:"errors"`  // Blocks the endpoint failed to return
+}
+
+// Result is the outcome of a consistency check
+type Result struct {
+	Blocks     int              `json:"blocks"`   // Blocks checked
+	Compared   int              `json:"compared"` // Blocks at least two endpoints returned
+	Divergent  []*Divergence    `json:"divergent,omitempty"`
+	Endpoints  []*EndpointStats `json:"endpoints"`
+	Consistent bool             `json:"consistent"`
+}
+
+// Check reads the header of every block from every endpoint and reports the
+// blocks on which endpoints disagree about the hash or the state root.
+// Endpoints that do not have a block are counted as missing, not divergent,
+// since they may just be behind.
+func Check(ctx context.Context, endpoints []Endpoint, blocks []uint64) *Result {
+	views := make([][]View, len(blocks))
+	for i := range views {
+		views[i] = make([]View, len(endpoints))
+	}
+
+	var wg sync.WaitGroup
+	for e, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem := make(chan struct{}, maxConcurrent)
+			var inner sync.WaitGroup
+			for b, number := range blocks {
+				inner.Add(1)
+				sem <- struct{}{}
+				go func() {
+					defer inner.Done()
+					defer func() { <-sem }()
+					views[b][e] = readView(ctx, endpoint, number)
+				}()
+			}
+			inner.Wait()
+		}()
+	}
+	wg.Wait()
+
+	result := &Result{Blocks: len(blocks), Endpoints: make([]*EndpointStats, len(endpoints))}
+	for e, endpoint := range endpoints {
+		result.Endpoints[e] = &EndpointStats{Name: endpoint.Name}
+	}
+	for b, number := range blocks {
+		var present []View
+		for e, v := range views[b] {
+			switch {
+			case v.Error != "":
+				result.Endpoints[e].Errors++
+			case v.Missing:
+				result.Endpoints[e].Missing++
+			default:
+				present = append(present, v)
+			}
+		}
+		if len(present) < 2 {
+			continue
+		}
+		result.Compared++
+		if d := compare(number, present); d != nil {
+			d.Views = views[b]
+			result.Divergent = append(result.Divergent, d)
+		}
+	}
+	sort.Slice(result.Divergent, func(i, j int) bool { return result.Divergent[i].Block < result.Divergent[j].Block })
+	result.Consistent = len(result.Divergent) == 0
+	return result
+}
+
+// readView reads one block header from an endpoint
+func readView(ctx context.Context, endpoint Endpoint, number uint64) View {
+	view := View{Endpoint: endpoint.Name}
+	header, err := endpoint.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	switch {
+	case errors.Is(err, ethereum.NotFound) || (err == nil && header == nil):
+		view.Missing = true
+	case err != nil:
+		view.Error = err.Error()
+	default:
+		view.Hash = header.Hash()
+		view.StateRoot = header.Root
+	}
+	return view
+}
+
+// compare returns the divergence among the views of a block, or nil if they agree
+func compare(number uint64, views []View) *Divergence {
+	d := &Divergence{Block: number}
+	for _, v := range views[1:] {
+		if v.Hash != views[0].Hash {
+			d.HashDiffers = true
+		}
+		if v.StateRoot != views[0].StateRoot {
+			d.RootDiffers = true
+		}
+	}
+	if !d.HashDiffers && !d.RootDiffers {
+		return nil
+	}
+	return d
+}
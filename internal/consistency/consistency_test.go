@@ -0,0 +1,74 @@
+package consistency
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeChain serves headers up to head, with the state root of some blocks replaced
+type fakeChain struct {
+	head  uint64
+	roots map[uint64]common.Hash
+	fail  bool
+}
+
+func (f *fakeChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if f.fail {
+		return nil, errors.New("connection refused")
+	}
+	n := number.Uint64()
+	if n > f.head {
+		return nil, ethereum.NotFound
+	}
+	root := common.BigToHash(number)
+	if r, ok := f.roots[n]; ok {
+		root = r
+	}
+	return &types.Header{Number: number, Root: root, Difficulty: big.NewInt(0)}, nil
+}
+
+func TestCheck_Consistent(t *testing.T) {
+	endpoints := []Endpoint{
+		{Name: "a", Client: &fakeChain{head: 20}},
+		{Name: "b", Client: &fakeChain{head: 20}},
+	}
+	result := Check(context.Background(), endpoints, []uint64{10, 11, 12})
+	if !result.Consistent || result.Blocks != 3 || result.Compared != 3 {
+		t.Errorf("result = %+v, want 3 consistent blocks", result)
+	}
+}
+
+func TestCheck_Divergent(t *testing.T) {
+	endpoints := []Endpoint{
+		{Name: "a", Client: &fakeChain{head: 20}},
+		{Name: "b", Client: &fakeChain{head: 20, roots: map[uint64]common.Hash{11: common.HexToHash("0xbad")}}},
+		{Name: "c", Client: &fakeChain{head: 11}},
+		{Name: "d", Client: &fakeChain{fail: true}},
+	}
+	result := Check(context.Background(), endpoints, []uint64{12, 11, 10})
+
+	if result.Consistent || len(result.Divergent) != 1 {
+		t.Fatalf("result = %+v, want one divergent block", result)
+	}
+	d := result.Divergent[0]
+	if d.Block != 11 || !d.RootDiffers || !d.HashDiffers || len(d.Views) != 4 {
+		t.Errorf("divergence = %+v", d)
+	}
+	if d.Views[1].StateRoot != common.HexToHash("0xbad") {
+		t.Errorf("view of b = %+v", d.Views[1])
+	}
+
+	// c is behind on block 12, d failed everywhere
+	if result.Endpoints[2].Missing != 1 || result.Endpoints[3].Errors != 3 {
+		t.Errorf("endpoint stats = %+v, %+v", result.Endpoints[2], result.Endpoints[3])
+	}
+	if result.Compared != 3 {
+		t.Errorf("Compared = %d, want 3", result.Compared)
+	}
+}
@@ -0,0 +1,278 @@
+package contention
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// CounterBytecode deploys a hand-assembled counter for CONTENTION mode.
+//
+// increment() adds one to storage slot 0, which every sender shares.
+// incrementSender() adds one to the slot keyed by the caller's address, so
+// each sender writes its own slot. Any other calldata reverts.
+const CounterBytecode = "0x6031600c60003960316000f360003560e01c8063d09de08a1461001f57631f614b6f1461002657600080fd5b6000610028565b335b8054600101905500"
+
+var (
+	// incrementSelector is the selector of increment()
+	incrementSelector = crypto.Keccak256([]byte("increment()"))[:4]
+
+	// incrementSenderSelector is the selector of incrementSender()
+	incrementSenderSelector = crypto.Keccak256([]byte("incrementSender()"))[:4]
+)
+
+// CallData returns the calldata of one increment in the variant
+func CallData(variant Variant) []byte {
+	if variant == VariantContended {
+		return incrementSelector
+	}
+	return incrementSenderSelector
+}
+
+// Tester measures how increments of a shared storage slot compare to
+// increments of one slot per sender
+type Tester struct {
+	client Client
+	config *Config
+}
+
+// New creates a new Tester instance
+func New(client Client, config *Config) *Tester {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Tester{
+		client: client,
+		config: config,
+	}
+}
+
+// DeployTx returns the unsigned counter deployment transaction
+func (t *Tester) DeployTx(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: t.config.GasPrice,
+		Gas:      100000,
+		Value:    big.NewInt(0),
+		Data:     common.FromHex(CounterBytecode),
+	})
+}
+
+// Run sends the baseline variant and then the contended variant, waiting
+// for every receipt of a variant before starting the next
+func (t *Tester) Run(ctx context.Context, keys []*ecdsa.PrivateKey) (*Result, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys provided")
+	}
+	if t.config.Contract == (common.Address{}) {
+		return nil, fmt.Errorf("counter contract address is required")
+	}
+
+	result := &Result{}
+	var err error
+	console.Printf("\nSending %d increments, one slot per sender...\n", t.config.Transactions)
+	if result.Baseline, err = t.runPhase(ctx, keys, VariantBaseline); err != nil {
+		return result, err
+	}
+	console.Printf("Sending %d increments to one shared slot...\n", t.config.Transactions)
+	if result.Contended, err = t.runPhase(ctx, keys, VariantContended); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// sentTx is an accepted increment waiting for its receipt
+type sentTx struct {
+	hash   common.Hash
+	sentAt time.Time
+}
+
+// runPhase sends the variant's increments, spread over the keys, and waits for their receipts
+func (t *Tester) runPhase(ctx context.Context, keys []*ecdsa.PrivateKey, variant Variant) (*PhaseResult, error) {
+	phase := &PhaseResult{Variant: variant}
+	start := time.Now()
+
+	perKey := make([][]sentTx, len(keys))
+	errs := make([]error, len(keys))
+	rejected := make([]int, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		count := t.config.Transactions / len(keys)
+		if i < t.config.Transactions%len(keys) {
+			count++
+		}
+		if count == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, key *ecdsa.PrivateKey, count int) {
+			defer wg.Done()
+			perKey[i], rejected[i], errs[i] = t.sendIncrements(ctx, key, variant, count)
+		}(i, key, count)
+	}
+	wg.Wait()
+
+	var sent []sentTx
+	for i := range keys {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		sent = append(sent, perKey[i]...)
+		phase.Rejected += rejected[i]
+	}
+	phase.Sent = len(sent)
+
+	latencies, last := t.waitReceipts(ctx, sent, phase)
+	Summarize(phase, latencies, start, last)
+	return phase, ctx.Err()
+}
+
+// sendIncrements sends count increments from one account in nonce order
+func (t *Tester) sendIncrements(ctx context.Context, key *ecdsa.PrivateKey, variant Variant, count int) ([]sentTx, int, error) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := t.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get nonce of %s: %w", from.Hex(), err)
+	}
+
+	signer := types.NewLondonSigner(t.config.ChainID)
+	sent := make([]sentTx, 0, count)
+	rejected := 0
+	for i := 0; i < count && ctx.Err() == nil; i++ {
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: t.config.GasPrice,
+			Gas:      t.config.GasLimit,
+			To:       &t.config.Contract,
+			Value:    big.NewInt(0),
+			Data:     CallData(variant),
+		})
+		signedTx, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to sign increment: %w", err)
+		}
+		raw, err := signedTx.MarshalBinary()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode increment: %w", err)
+		}
+
+		sentAt := time.Now()
+		if _, err := t.client.SendRawTransaction(ctx, raw); err != nil {
+			// Later nonces would only queue behind the gap
+			rejected += count - i
+			break
+		}
+		sent = append(sent, sentTx{hash: signedTx.Hash(), sentAt: sentAt})
+		nonce++
+	}
+	return sent, rejected, nil
+}
+
+// waitReceipts polls the receipts of sent until all are mined or the timeout
+// elapses, counting outcomes into phase. It returns the latency of each mined
+// increment and when the last receipt was seen.
+func (t *Tester) waitReceipts(ctx context.Context, sent []sentTx, phase *PhaseResult) ([]time.Duration, time.Time) {
+	waitCtx, cancel := context.WithTimeout(ctx, t.config.ReceiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+
+	latencies := make([]time.Duration, 0, len(sent))
+	var last time.Time
+	pending := sent
+	for len(pending) > 0 {
+		waiting := pending[:0]
+		for _, tx := range pending {
+			receipt, err := t.client.TransactionReceipt(waitCtx, tx.hash)
+			if err != nil || receipt == nil {
+				waiting = append(waiting, tx)
+				continue
+			}
+			last = time.Now()
+			latencies = append(latencies, last.Sub(tx.sentAt))
+			if receipt.Status == types.ReceiptStatusSuccessful {
+				phase.Confirmed++
+			} else {
+				phase.Reverted++
+			}
+		}
+		pending = waiting
+		if len(pending) == 0 {
+			break
+		}
+
+		select {
+		case <-waitCtx.Done():
+			phase.Pending = len(pending)
+			return latencies, last
+		case <-ticker.C:
+		}
+	}
+	return latencies, last
+}
+
+// Summarize fills in the timing of a phase from the latencies of its mined
+// increments, the phase start and the last receipt
+func Summarize(phase *PhaseResult, latencies []time.Duration, start, last time.Time) {
+	if len(latencies) == 0 {
+		return
+	}
+	phase.Duration = last.Sub(start)
+	if phase.Duration > 0 {
+		phase.Throughput = float64(phase.Confirmed) / phase.Duration.Seconds()
+	}
+
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+	phase.AvgLatency = total / time.Duration(len(sorted))
+	phase.P95Latency = sorted[(len(sorted)-1)*95/100]
+}
+
+// PrintResult prints both variants side by side and how the shared slot compares
+func PrintResult(result *Result) {
+	console.Summaryf("\nContention Results\n\n")
+	console.Summaryf("  %-14s %14s %14s\n", "", VariantBaseline, VariantContended)
+	row := func(label string, value func(*PhaseResult) string) {
+		baseline, contended := "-", "-"
+		if result.Baseline != nil {
+			baseline = value(result.Baseline)
+		}
+		if result.Contended != nil {
+			contended = value(result.Contended)
+		}
+		console.Summaryf("  %-14s %14s %14s\n", label, baseline, contended)
+	}
+	row("Sent:", func(p *PhaseResult) string { return fmt.Sprint(p.Sent) })
+	row("Rejected:", func(p *PhaseResult) string { return fmt.Sprint(p.Rejected) })
+	row("Confirmed:", func(p *PhaseResult) string { return fmt.Sprint(p.Confirmed) })
+	row("Reverted:", func(p *PhaseResult) string { return fmt.Sprint(p.Reverted) })
+	row("Not mined:", func(p *PhaseResult) string { return fmt.Sprint(p.Pending) })
+	row("Duration:", func(p *PhaseResult) string { return p.Duration.Round(time.Millisecond).String() })
+	row("Throughput:", func(p *PhaseResult) string { return fmt.Sprintf("%.2f tx/s", p.Throughput) })
+	row("Avg latency:", func(p *PhaseResult) string { return p.AvgLatency.Round(time.Millisecond).String() })
+	row("P95 latency:", func(p *PhaseResult) string { return p.P95Latency.Round(time.Millisecond).String() })
+
+	if result.Baseline == nil || result.Contended == nil {
+		return
+	}
+	console.Summaryf("\n  Throughput change:  %+.1f%%\n", result.ThroughputChange())
+	console.Summaryf("  Latency change:     %+.1f%%\n", result.LatencyChange())
+	if result.Contended.Reverted > 0 || result.Baseline.Reverted > 0 {
+		console.Warnf("\n%d baseline and %d contended increments reverted\n", result.Baseline.Reverted, result.Contended.Reverted)
+	}
+}
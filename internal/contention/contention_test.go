@@ -0,0 +1,145 @@
+package contention
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// mockClient mines every transaction immediately and records the calldata sent
+type mockClient struct {
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+	mined  map[common.Hash]bool
+	calls  map[string]int
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{
+		nonces: make(map[common.Address]uint64),
+		mined:  make(map[common.Hash]bool),
+		calls:  make(map[string]int),
+	}
+}
+
+func (m *mockClient) SendRawTransaction(_ context.Context, rawTx []byte) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return common.Hash{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mined[tx.Hash()] = true
+	m.calls[string(tx.Data())]++
+	return tx.Hash(), nil
+}
+
+func (m *mockClient) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nonces[account], nil
+}
+
+func (m *mockClient) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.mined[hash] {
+		return nil, nil
+	}
+	return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful}, nil
+}
+
+func newKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func TestCounter_Execution(t *testing.T) {
+	cfg := &runtime.Config{GasLimit: 10_000_000}
+	_, counter, _, err := runtime.Create(common.FromHex(CounterBytecode), cfg)
+	if err != nil {
+		t.Fatalf("deploy counter: %v", err)
+	}
+
+	senders := []common.Address{common.HexToAddress("0xa1"), common.HexToAddress("0xa2")}
+	for _, sender := range senders {
+		cfg.Origin = sender
+		for _, variant := range []Variant{VariantBaseline, VariantContended} {
+			if _, _, err := runtime.Call(counter, CallData(variant), cfg); err != nil {
+				t.Fatalf("%s increment: %v", variant, err)
+			}
+		}
+	}
+
+	if got := cfg.State.GetState(counter, common.Hash{}); got != common.BigToHash(big.NewInt(2)) {
+		t.Errorf("shared slot = %s, want 2", got.Hex())
+	}
+	for _, sender := range senders {
+		if got := cfg.State.GetState(counter, common.BytesToHash(sender.Bytes())); got != common.BigToHash(big.NewInt(1)) {
+			t.Errorf("slot of %s = %s, want 1", sender.Hex(), got.Hex())
+		}
+	}
+
+	// Unknown selectors revert
+	if _, _, err := runtime.Call(counter, []byte{1, 2, 3, 4}, cfg); err == nil {
+		t.Error("expected revert for unknown selector")
+	}
+}
+
+func TestTester_Run(t *testing.T) {
+	client := newMockClient()
+	cfg := DefaultConfig()
+	cfg.ChainID = big.NewInt(1)
+	cfg.GasPrice = big.NewInt(1)
+	cfg.Contract = common.HexToAddress("0xc0")
+	cfg.Transactions = 7
+	cfg.PollInterval = time.Millisecond
+
+	if _, err := New(client, &Config{}).Run(context.Background(), newKeys(t, 1)); err == nil {
+		t.Error("expected error without a counter contract")
+	}
+
+	result, err := New(client, cfg).Run(context.Background(), newKeys(t, 3))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, phase := range []*PhaseResult{result.Baseline, result.Contended} {
+		if phase.Sent != 7 || phase.Confirmed != 7 || phase.Pending != 0 {
+			t.Errorf("%s: sent %d, confirmed %d, pending %d", phase.Variant, phase.Sent, phase.Confirmed, phase.Pending)
+		}
+	}
+	if client.calls[string(incrementSenderSelector)] != 7 || client.calls[string(incrementSelector)] != 7 {
+		t.Errorf("calls = %v, want 7 of each variant", client.calls)
+	}
+}
+
+func TestResult_Changes(t *testing.T) {
+	result := &Result{
+		Baseline:  &PhaseResult{Throughput: 100, AvgLatency: 2 * time.Second},
+		Contended: &PhaseResult{Throughput: 75, AvgLatency: 3 * time.Second},
+	}
+	if got := result.ThroughputChange(); got != -25 {
+		t.Errorf("ThroughputChange() = %v, want -25", got)
+	}
+	if got := result.LatencyChange(); got != 50 {
+		t.Errorf("LatencyChange() = %v, want 50", got)
+	}
+	if got := (&Result{Contended: result.Contended}).ThroughputChange(); got != 0 {
+		t.Errorf("ThroughputChange() without baseline = %v, want 0", got)
+	}
+}
@@ -0,0 +1,89 @@
+package contention
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Client defines the interface for sending counter increments and waiting for their receipts
+type Client interface {
+	// SendRawTransaction sends a signed, encoded transaction
+	SendRawTransaction(ctx context.Context, rawTx []byte) (common.Hash, error)
+	// PendingNonceAt returns the next nonce of an account including pending txs
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	// TransactionReceipt returns the receipt of a mined transaction
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Config holds configuration for contention testing
+type Config struct {
+	ChainID        *big.Int
+	GasPrice       *big.Int
+	GasLimit       uint64
+	Contract       common.Address // Deployed counter contract
+	Transactions   int            // Increments sent in each variant
+	ReceiptTimeout time.Duration  // How long to wait for a variant's receipts
+	PollInterval   time.Duration  // How often to poll for receipts
+}
+
+// DefaultConfig returns default contention configuration
+func DefaultConfig() *Config {
+	return &Config{
+		GasLimit:       50000,
+		Transactions:   100,
+		ReceiptTimeout: 60 * time.Second,
+		PollInterval:   500 * time.Millisecond,
+	}
+}
+
+// Variant selects which storage slots the increments write
+type Variant string
+
+const (
+	// VariantBaseline increments one slot per sender, so no two senders touch the same slot
+	VariantBaseline Variant = "slot-per-sender"
+	// VariantContended increments a single slot shared by every sender
+	VariantContended Variant = "shared-slot"
+)
+
+// PhaseResult is the outcome of sending one variant's increments
+type PhaseResult struct {
+	Variant    Variant
+	Sent       int           // Increments accepted by the node
+	Rejected   int           // Increments the node refused
+	Confirmed  int           // Increments mined successfully
+	Reverted   int           // Increments mined with a failed status
+	Pending    int           // Accepted increments not mined within the receipt timeout
+	Duration   time.Duration // From the first send to the last receipt
+	Throughput float64       // Confirmed increments per second over Duration
+	AvgLatency time.Duration // Mean time from send to receipt
+	P95Latency time.Duration
+}
+
+// Result holds the outcome of a contention test
+type Result struct {
+	Baseline  *PhaseResult
+	Contended *PhaseResult
+}
+
+// ThroughputChange returns the percent change of the contended throughput
+// against the baseline; negative values are a slowdown
+func (r *Result) ThroughputChange() float64 {
+	if r.Baseline == nil || r.Contended == nil || r.Baseline.Throughput == 0 {
+		return 0
+	}
+	return (r.Contended.Throughput - r.Baseline.Throughput) / r.Baseline.Throughput * 100
+}
+
+// LatencyChange returns the percent change of the contended mean latency
+// against the baseline; positive values are a slowdown
+func (r *Result) LatencyChange() float64 {
+	if r.Baseline == nil || r.Contended == nil || r.Baseline.AvgLatency == 0 {
+		return 0
+	}
+	return float64(r.Contended.AvgLatency-r.Baseline.AvgLatency) / float64(r.Baseline.AvgLatency) * 100
+}
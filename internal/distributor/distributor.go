@@ -2,7 +2,6 @@ package distributor
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math/big"
@@ -11,9 +10,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/schollz/progressbar/v3"
 
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/progress"
 )
 
@@ -29,6 +28,7 @@ type Client interface {
 	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
 	ChainID(ctx context.Context) (*big.Int, error)
 }
 
@@ -53,10 +53,10 @@ func New(client Client, config *Config) *Distributor {
 // Distribute distributes funds from the master account to sub-accounts
 func (d *Distributor) Distribute(
 	ctx context.Context,
-	masterKey *ecdsa.PrivateKey,
+	master txbuilder.Signer,
 	subAccounts []common.Address,
 ) (*DistributionResult, error) {
-	fmt.Printf("\nStarting Fund Distribution\n\n")
+	console.Printf("\nStarting Fund Distribution\n\n")
 
 	// Get chain ID if not set
 	if d.chainID == nil {
@@ -69,10 +69,31 @@ func (d *Distributor) Distribute(
 
 	// Calculate required fund per account
 	requiredFund := d.config.CalculateRequiredFund()
-	fmt.Printf("Required fund per account: %s wei\n", requiredFund.String())
-	fmt.Printf("  Gas per tx: %d\n", d.config.GasPerTx)
-	fmt.Printf("  Txs per account: %d\n", d.config.TxsPerAccount)
-	fmt.Printf("  Buffer: %d%%\n\n", d.config.BufferPercent)
+	console.Printf("Required fund per account: %s\n", d.config.Units.Format(requiredFund))
+	console.Printf("  Gas per tx: %d\n", d.config.GasPerTx)
+	console.Printf("  Txs per account: %d\n", d.config.TxsPerAccount)
+	if d.config.ValuePerTx != nil && d.config.ValuePerTx.Sign() > 0 {
+		console.Printf("  Value per tx: %s\n", d.config.Units.Format(d.config.ValuePerTx))
+	}
+	console.Printf("  Buffer: %d%%\n", d.config.BufferPercent)
+	if d.config.FundAmount != nil {
+		console.Printf("  Fund amount: %s (replaces the computed %s)\n", d.config.Units.Format(d.config.FundAmount),
+			d.config.Units.Format(d.config.computedFund()))
+	}
+	if d.config.FundExtra != nil {
+		console.Printf("  Fund extra: %s\n", d.config.Units.Format(d.config.FundExtra))
+	}
+	console.Println()
+
+	// On a zero-fee network, transactions without value cost nothing
+	if requiredFund.Sign() == 0 {
+		console.OKf("Sub-accounts need no funds, skipping distribution\n")
+		ready := make([]*AccountStatus, len(subAccounts))
+		for i, addr := range subAccounts {
+			ready[i] = &AccountStatus{Address: addr, RequiredFund: requiredFund, IsFunded: true}
+		}
+		return &DistributionResult{ReadyAccounts: ready, TotalDistributed: big.NewInt(0)}, nil
+	}
 
 	// Check account balances and identify which need funding
 	accountStatuses, err := d.checkBalances(ctx, subAccounts, requiredFund)
@@ -92,7 +113,7 @@ func (d *Distributor) Distribute(
 
 	// If all accounts are already funded
 	if len(unfundedAccounts) == 0 {
-		fmt.Printf("[OK] All %d accounts are already funded\n", len(fundedAccounts))
+		console.OKf("All %d accounts are already funded\n", len(fundedAccounts))
 		return &DistributionResult{
 			ReadyAccounts:    fundedAccounts,
 			UnfundedAccounts: nil,
@@ -107,7 +128,15 @@ func (d *Distributor) Distribute(
 	})
 
 	// Fund the accounts
-	result, err := d.fundAccounts(ctx, masterKey, unfundedAccounts)
+	gasPrice, err := d.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := d.client.PendingNonceAt(ctx, master.Address())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master nonce: %w", err)
+	}
+	result, err := d.fundAccounts(ctx, master, unfundedAccounts, gasPrice, nonce)
 	if err != nil {
 		return nil, err
 	}
@@ -124,8 +153,8 @@ func (d *Distributor) checkBalances(
 	accounts []common.Address,
 	requiredFund *big.Int,
 ) ([]*AccountStatus, error) {
-	fmt.Printf("Checking balances of %d accounts...\n", len(accounts))
-	bar := progressbar.Default(int64(len(accounts)), "checking balances")
+	console.Printf("Checking balances of %d accounts...\n", len(accounts))
+	bar := progress.New(int64(len(accounts)), "checking balances")
 
 	statuses := make([]*AccountStatus, 0, len(accounts))
 
@@ -160,17 +189,21 @@ func (d *Distributor) checkBalances(
 		progress.Add(bar, 1)
 	}
 
-	fmt.Println()
+	console.Println()
 	return statuses, nil
 }
 
-// fundAccounts sends funds to accounts that need it
+// fundAccounts sends funds to accounts that need it at gasPrice, with master
+// nonces from nonce on. Accounts whose transfer cannot be sent are returned
+// as failed rather than aborting the distribution.
 func (d *Distributor) fundAccounts(
 	ctx context.Context,
-	masterKey *ecdsa.PrivateKey,
+	master txbuilder.Signer,
 	unfundedAccounts []*AccountStatus,
+	gasPrice *big.Int,
+	nonce uint64,
 ) (*DistributionResult, error) {
-	masterAddr := crypto.PubkeyToAddress(masterKey.PublicKey)
+	masterAddr := master.Address()
 
 	// Check master balance
 	masterBalance, err := d.client.BalanceAt(ctx, masterAddr, nil)
@@ -178,20 +211,8 @@ func (d *Distributor) fundAccounts(
 		return nil, fmt.Errorf("failed to get master balance: %w", err)
 	}
 
-	fmt.Printf("Master account: %s\n", masterAddr.Hex())
-	fmt.Printf("Master balance: %s wei\n\n", masterBalance.String())
-
-	// Get gas price - use config GasPrice if available, otherwise suggest
-	var gasPrice *big.Int
-	if d.config.GasPrice != nil && d.config.GasPrice.Sign() > 0 {
-		// Use configured gas price
-		gasPrice = new(big.Int).Set(d.config.GasPrice)
-	} else {
-		gasPrice, err = d.client.SuggestGasPrice(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to suggest gas price: %w", err)
-		}
-	}
+	console.Printf("Master account: %s\n", masterAddr.Hex())
+	console.Printf("Master balance: %s\n\n", d.config.Units.Format(masterBalance))
 
 	// Transfer gas cost (21000 gas for simple transfer)
 	transferGas := uint64(21000)
@@ -217,23 +238,19 @@ func (d *Distributor) fundAccounts(
 	}
 
 	if len(fundableAccounts) == 0 {
-		fmt.Printf("[FAIL] Master account cannot fund any sub-accounts\n")
-		fmt.Printf("   Master balance: %s wei\n", masterBalance.String())
-		fmt.Printf("   Minimum needed: %s wei\n", unfundedAccounts[0].MissingFund.String())
+		console.Failf("Master account cannot fund any sub-accounts\n")
+		console.Printf("   Master balance: %s\n", d.config.Units.Format(masterBalance))
+		console.Printf("   Minimum needed: %s\n", d.config.Units.Format(unfundedAccounts[0].MissingFund))
 		return nil, ErrInsufficientFunds
 	}
 
-	fmt.Printf("Funding %d accounts...\n", len(fundableAccounts))
-	bar := progressbar.Default(int64(len(fundableAccounts)), "funding accounts")
-
-	// Get master nonce
-	nonce, err := d.client.PendingNonceAt(ctx, masterAddr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get master nonce: %w", err)
-	}
+	console.Printf("Funding %d accounts...\n", len(fundableAccounts))
+	bar := progress.New(int64(len(fundableAccounts)), "funding accounts")
 
 	readyAccounts := make([]*AccountStatus, 0, len(fundableAccounts))
-	txCount := 0
+	var failedAccounts []*AccountStatus
+	var sendErr error
+	txCount, underpriced := 0, 0
 
 	for _, account := range fundableAccounts {
 		// Create legacy transaction (type 0) for better compatibility
@@ -246,16 +263,25 @@ func (d *Distributor) fundAccounts(
 			Data:     nil,
 		})
 
-		// Sign transaction with EIP-155 signer
-		signer := types.NewEIP155Signer(d.chainID)
-		signedTx, err := types.SignTx(tx, signer, masterKey)
+		// Sign transaction (EIP-155 protected)
+		signedTx, err := txbuilder.SignTransaction(tx, d.chainID, master)
 		if err != nil {
 			return nil, fmt.Errorf("failed to sign transfer tx: %w", err)
 		}
 
-		// Send transaction
+		// Send transaction; a refused transfer does not use up the nonce
 		if err := d.client.SendTransaction(ctx, signedTx); err != nil {
-			return nil, fmt.Errorf("failed to send transfer tx to %s: %w", account.Address.Hex(), err)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if isUnderpriced(err) {
+				underpriced++
+			}
+			sendErr = fmt.Errorf("failed to send transfer tx to %s: %w", account.Address.Hex(), err)
+			totalToDistribute.Sub(totalToDistribute, account.MissingFund)
+			failedAccounts = append(failedAccounts, account)
+			progress.Add(bar, 1)
+			continue
 		}
 
 		nonce++
@@ -272,8 +298,11 @@ func (d *Distributor) fundAccounts(
 		time.Sleep(10 * time.Millisecond)
 	}
 
-	fmt.Printf("\n[OK] Successfully funded %d accounts\n", len(readyAccounts))
-	fmt.Printf("   Total distributed: %s wei\n", totalToDistribute.String())
+	console.OKf("\nSuccessfully funded %d accounts\n", len(readyAccounts))
+	console.Printf("   Total distributed: %s\n", d.config.Units.Format(totalToDistribute))
+	if len(failedAccounts) > 0 {
+		console.Warnf("   %d transfers could not be sent: %v\n", len(failedAccounts), sendErr)
+	}
 
 	// Calculate unfunded accounts
 	unfunded := make([]*AccountStatus, 0)
@@ -282,53 +311,20 @@ func (d *Distributor) fundAccounts(
 	}
 
 	if len(unfunded) > 0 {
-		fmt.Printf("   [WARN] %d accounts could not be funded (insufficient master balance)\n", len(unfunded))
+		console.Warnf("   %d accounts could not be funded (insufficient master balance)\n", len(unfunded))
 	}
 
 	return &DistributionResult{
 		ReadyAccounts:    readyAccounts,
 		UnfundedAccounts: unfunded,
+		FailedAccounts:   failedAccounts,
 		TotalDistributed: totalToDistribute,
 		TxCount:          txCount,
+		Underpriced:      underpriced,
+		GasPrice:         gasPrice,
 	}, nil
 }
 
-// WaitForFunding waits for all distribution transactions to be confirmed
-func (d *Distributor) WaitForFunding(
-	ctx context.Context,
-	accounts []*AccountStatus,
-	timeout time.Duration,
-) error {
-	fmt.Printf("\nWaiting for funding confirmations...\n")
-
-	deadline := time.Now().Add(timeout)
-	bar := progressbar.Default(int64(len(accounts)), "confirming")
-
-	for _, account := range accounts {
-		for {
-			if time.Now().After(deadline) {
-				return fmt.Errorf("timeout waiting for funding confirmation")
-			}
-
-			balance, err := d.client.BalanceAt(ctx, account.Address, nil)
-			if err != nil {
-				return fmt.Errorf("failed to check balance: %w", err)
-			}
-
-			if balance.Cmp(account.RequiredFund) >= 0 {
-				account.Balance = balance
-				progress.Add(bar, 1)
-				break
-			}
-
-			time.Sleep(500 * time.Millisecond)
-		}
-	}
-
-	fmt.Printf("[OK] All funding transactions confirmed\n")
-	return nil
-}
-
 // GetAccountNonces fetches the current nonce for each account
 func (d *Distributor) GetAccountNonces(
 	ctx context.Context,
@@ -347,3 +343,15 @@ func (d *Distributor) GetAccountNonces(
 
 	return nonces, nil
 }
+
+// gasPrice returns the configured gas price, or the node's suggestion when none is set
+func (d *Distributor) gasPrice(ctx context.Context) (*big.Int, error) {
+	if d.config.GasPrice != nil && d.config.GasPrice.Sign() > 0 {
+		return new(big.Int).Set(d.config.GasPrice), nil
+	}
+	gasPrice, err := d.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+	}
+	return gasPrice, nil
+}
@@ -6,10 +6,13 @@ import (
 	"errors"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
 )
 
 const (
@@ -29,10 +32,14 @@ func mustParseBigInt(s string) *big.Int {
 type mockClient struct {
 	balances     map[common.Address]*big.Int
 	nonces       map[common.Address]uint64
+	inFlight     map[common.Address]uint64 // Sent but unmined transactions, on top of nonces
 	gasPrice     *big.Int
 	gasTipCap    *big.Int
 	chainID      *big.Int
 	sentTxs      []*types.Transaction
+	refuse       int      // Sends refused before the next is accepted
+	stuck        int      // Accepted sends that never confirm
+	minGasPrice  *big.Int // Sends priced below are refused as underpriced replacements (nil = none)
 	balanceErr   error
 	nonceErr     error
 	sendTxErr    error
@@ -45,6 +52,7 @@ func newMockClient() *mockClient {
 	return &mockClient{
 		balances:  make(map[common.Address]*big.Int),
 		nonces:    make(map[common.Address]uint64),
+		inFlight:  make(map[common.Address]uint64),
 		gasPrice:  big.NewInt(1000000000), // 1 Gwei
 		gasTipCap: big.NewInt(100000000),  // 0.1 Gwei
 		chainID:   big.NewInt(1001),
@@ -66,7 +74,7 @@ func (m *mockClient) PendingNonceAt(ctx context.Context, account common.Address)
 	if m.nonceErr != nil {
 		return 0, m.nonceErr
 	}
-	return m.nonces[account], nil
+	return m.nonces[account] + m.inFlight[account], nil
 }
 
 func (m *mockClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
@@ -87,7 +95,18 @@ func (m *mockClient) SendTransaction(ctx context.Context, tx *types.Transaction)
 	if m.sendTxErr != nil {
 		return m.sendTxErr
 	}
+	if m.refuse > 0 {
+		m.refuse--
+		return errors.New("replacement transaction underpriced")
+	}
+	if m.minGasPrice != nil && tx.GasPrice().Cmp(m.minGasPrice) < 0 {
+		return errors.New("replacement transaction underpriced")
+	}
 	m.sentTxs = append(m.sentTxs, tx)
+	if m.stuck > 0 {
+		m.stuck--
+		return nil
+	}
 	// Update balance of recipient (simulate tx)
 	if tx.To() != nil {
 		if _, ok := m.balances[*tx.To()]; !ok {
@@ -98,6 +117,13 @@ func (m *mockClient) SendTransaction(ctx context.Context, tx *types.Transaction)
 	return nil
 }
 
+func (m *mockClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	if m.nonceErr != nil {
+		return 0, m.nonceErr
+	}
+	return m.nonces[account], nil
+}
+
 func (m *mockClient) ChainID(ctx context.Context) (*big.Int, error) {
 	if m.chainIDErr != nil {
 		return nil, m.chainIDErr
@@ -162,6 +188,62 @@ func TestConfig_CalculateRequiredFund(t *testing.T) {
 				return result.Cmp(expected) == 0
 			},
 		},
+		{
+			name: "with value",
+			config: &Config{
+				GasPerTx:      21000,
+				TxsPerAccount: 10,
+				GasPrice:      big.NewInt(1000000000),
+				ValuePerTx:    big.NewInt(1000000000000000),
+				BufferPercent: 20,
+			},
+			wantFunc: func(result *big.Int) bool {
+				// (21000 * 1000000000 + 1000000000000000) * 10 * 1.2 = 12252000000000000
+				expected := big.NewInt(12252000000000000)
+				return result.Cmp(expected) == 0
+			},
+		},
+		{
+			name: "zero gas price with value",
+			config: &Config{
+				GasPerTx:      21000,
+				TxsPerAccount: 10,
+				GasPrice:      big.NewInt(0),
+				ValuePerTx:    big.NewInt(1000),
+				BufferPercent: 20,
+			},
+			wantFunc: func(result *big.Int) bool {
+				// 1000 * 10 * 1.2 = 12000
+				return result.Cmp(big.NewInt(12000)) == 0
+			},
+		},
+		{
+			name: "fund amount with extra",
+			config: &Config{
+				GasPerTx:      21000,
+				TxsPerAccount: 10,
+				GasPrice:      big.NewInt(1000000000),
+				BufferPercent: 20,
+				FundAmount:    big.NewInt(5000),
+				FundExtra:     big.NewInt(500),
+			},
+			wantFunc: func(result *big.Int) bool {
+				return result.Cmp(big.NewInt(5500)) == 0
+			},
+		},
+		{
+			name: "extra on the computed fund",
+			config: &Config{
+				GasPerTx:      21000,
+				TxsPerAccount: 10,
+				GasPrice:      big.NewInt(1000000000),
+				BufferPercent: 0,
+				FundExtra:     big.NewInt(1),
+			},
+			wantFunc: func(result *big.Int) bool {
+				return result.Cmp(big.NewInt(210000000000001)) == 0
+			},
+		},
 		{
 			name: "higher gas limit",
 			config: &Config{
@@ -236,7 +318,7 @@ func TestDistributor_Distribute_AllAccountsFunded(t *testing.T) {
 	distributor := New(client, cfg)
 	masterKey, _ := newTestKey()
 
-	result, err := distributor.Distribute(context.Background(), masterKey, subAccounts)
+	result, err := distributor.Distribute(context.Background(), txbuilder.NewKeySigner(masterKey), subAccounts)
 	if err != nil {
 		t.Fatalf("Distribute() error: %v", err)
 	}
@@ -262,6 +344,25 @@ func TestDistributor_Distribute_AllAccountsFunded(t *testing.T) {
 	}
 }
 
+func TestDistributor_Distribute_NothingToFund(t *testing.T) {
+	client := newMockClient()
+	client.balanceErr = errors.New("balances should not be queried")
+	subAccounts := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+	}
+
+	cfg := &Config{GasPerTx: 21000, TxsPerAccount: 10, GasPrice: big.NewInt(0), BufferPercent: 20}
+	masterKey, _ := newTestKey()
+	result, err := New(client, cfg).Distribute(context.Background(), txbuilder.NewKeySigner(masterKey), subAccounts)
+	if err != nil {
+		t.Fatalf("Distribute() error: %v", err)
+	}
+	if len(result.ReadyAccounts) != 2 || result.TxCount != 0 || len(client.sentTxs) != 0 {
+		t.Errorf("result = %d ready, %d txs; want 2 ready and no funding", len(result.ReadyAccounts), result.TxCount)
+	}
+}
+
 func TestDistributor_Distribute_FundAccounts(t *testing.T) {
 	client := newMockClient()
 	masterKey, masterAddr := newTestKey()
@@ -284,7 +385,7 @@ func TestDistributor_Distribute_FundAccounts(t *testing.T) {
 
 	distributor := New(client, cfg)
 
-	result, err := distributor.Distribute(context.Background(), masterKey, subAccounts)
+	result, err := distributor.Distribute(context.Background(), txbuilder.NewKeySigner(masterKey), subAccounts)
 	if err != nil {
 		t.Fatalf("Distribute() error: %v", err)
 	}
@@ -330,7 +431,7 @@ func TestDistributor_Distribute_InsufficientFunds(t *testing.T) {
 
 	distributor := New(client, cfg)
 
-	_, err := distributor.Distribute(context.Background(), masterKey, subAccounts)
+	_, err := distributor.Distribute(context.Background(), txbuilder.NewKeySigner(masterKey), subAccounts)
 	if err == nil {
 		t.Error("Distribute() expected error for insufficient funds")
 	}
@@ -369,7 +470,7 @@ func TestDistributor_Distribute_PartialFunding(t *testing.T) {
 
 	distributor := New(client, cfg)
 
-	result, err := distributor.Distribute(context.Background(), masterKey, subAccounts)
+	result, err := distributor.Distribute(context.Background(), txbuilder.NewKeySigner(masterKey), subAccounts)
 	if err != nil {
 		t.Fatalf("Distribute() error: %v", err)
 	}
@@ -510,7 +611,7 @@ func TestDistributor_Distribute_MixedAccounts(t *testing.T) {
 
 	distributor := New(client, cfg)
 
-	result, err := distributor.Distribute(context.Background(), masterKey, subAccounts)
+	result, err := distributor.Distribute(context.Background(), txbuilder.NewKeySigner(masterKey), subAccounts)
 	if err != nil {
 		t.Fatalf("Distribute() error: %v", err)
 	}
@@ -534,3 +635,179 @@ func TestErrors(t *testing.T) {
 		t.Errorf("ErrNoAccountsToFund message incorrect")
 	}
 }
+
+func TestDistributor_Reclaim(t *testing.T) {
+	client := newMockClient()
+	_, masterAddr := newTestKey()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	addr := func(i int) common.Address { return crypto.PubkeyToAddress(keys[i].PublicKey) }
+
+	// 21000 gas at 1 Gwei costs 21000 Gwei
+	client.balances[addr(0)] = mustParseBigInt("1000000000000000000")
+	client.balances[addr(1)] = mustParseBigInt("21000000000000") // Exactly the sweep's gas
+	client.nonces[addr(0)] = 5
+
+	distributor := New(client, &Config{GasPrice: big.NewInt(1000000000)})
+	result, err := distributor.Reclaim(context.Background(), keys, masterAddr, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Reclaim() error: %v", err)
+	}
+	if result.Swept != 1 || result.Skipped != 2 || result.Failed != 0 {
+		t.Errorf("swept %d, skipped %d, failed %d, want 1, 2, 0", result.Swept, result.Skipped, result.Failed)
+	}
+
+	want := mustParseBigInt("999979000000000000")
+	if result.Reclaimed.Cmp(want) != 0 || client.balances[masterAddr].Cmp(want) != 0 {
+		t.Errorf("reclaimed %s, master balance %s, want %s", result.Reclaimed, client.balances[masterAddr], want)
+	}
+	if len(client.sentTxs) != 1 {
+		t.Fatalf("sent %d txs, want 1", len(client.sentTxs))
+	}
+	tx := client.sentTxs[0]
+	from, err := types.Sender(types.NewLondonSigner(client.chainID), tx)
+	if err != nil || from != addr(0) || tx.Nonce() != 5 || *tx.To() != masterAddr {
+		t.Errorf("sweep tx from %s nonce %d to %s (%v)", from.Hex(), tx.Nonce(), tx.To().Hex(), err)
+	}
+
+	// Send failures are counted, not returned
+	client.sendTxErr = errors.New("nonce too low")
+	result, err = distributor.Reclaim(context.Background(), keys[:1], masterAddr, time.Millisecond)
+	if err != nil || result.Failed != 1 {
+		t.Errorf("Reclaim() = %+v, %v, want one failure", result, err)
+	}
+
+	// An account with transactions in flight is left alone, since they spend
+	// from the balance a sweep would send
+	client.sendTxErr = nil
+	client.sentTxs = nil
+	client.inFlight[addr(0)] = 2
+	result, err = distributor.Reclaim(context.Background(), keys[:1], masterAddr, time.Millisecond)
+	if err != nil || result.Failed != 1 || len(client.sentTxs) != 0 {
+		t.Errorf("Reclaim() = %+v, %v, sent %d txs, want one failure and none sent", result, err, len(client.sentTxs))
+	}
+}
+
+func TestDistributor_Confirm_RetriesShortAccounts(t *testing.T) {
+	client := newMockClient()
+	masterKey, masterAddr := newTestKey()
+	master := txbuilder.NewKeySigner(masterKey)
+	client.balances[masterAddr] = mustParseBigInt("10000000000000000000")
+	client.nonces[masterAddr] = 7
+	client.refuse = 1 // First transfer refused
+	client.stuck = 1  // Second accepted but never mined
+	subAccounts := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		common.HexToAddress("0x3333333333333333333333333333333333333333"),
+	}
+
+	cfg := &Config{GasPerTx: 21000, TxsPerAccount: 10, GasPrice: big.NewInt(1000000000), BufferPercent: 20, FundRetries: 2, PriceBump: 20}
+	distributor := New(client, cfg)
+	result, err := distributor.Distribute(context.Background(), master, subAccounts)
+	if err != nil {
+		t.Fatalf("Distribute() error: %v", err)
+	}
+	if len(result.ReadyAccounts) != 2 || len(result.FailedAccounts) != 1 || result.TxCount != 2 {
+		t.Fatalf("result = %d ready, %d failed, %d txs; want 2, 1, 2", len(result.ReadyAccounts), len(result.FailedAccounts), result.TxCount)
+	}
+
+	if err := distributor.Confirm(context.Background(), master, result, time.Millisecond); err != nil {
+		t.Fatalf("Confirm() error: %v", err)
+	}
+	if len(result.Retries) != 1 {
+		t.Fatalf("Retries = %+v, want one", result.Retries)
+	}
+	retry := result.Retries[0]
+	if retry.Accounts != 2 || retry.Sent != 2 || retry.Funded != 2 || retry.GasPrice != "1200000000" || retry.Error != "" {
+		t.Errorf("retry = %+v", retry)
+	}
+	if len(result.ReadyAccounts) != 3 || len(result.FailedAccounts) != 0 || result.TxCount != 4 {
+		t.Errorf("result = %d ready, %d failed, %d txs; want 3, 0, 4", len(result.ReadyAccounts), len(result.FailedAccounts), result.TxCount)
+	}
+
+	// The retry starts at the confirmed nonce with the bumped price
+	tx := client.sentTxs[2]
+	if tx.Nonce() != 7 || tx.GasPrice().Cmp(big.NewInt(1200000000)) != 0 {
+		t.Errorf("retry tx nonce %d, gas price %s; want 7 at 1.2 Gwei", tx.Nonce(), tx.GasPrice())
+	}
+}
+
+func TestDistributor_Confirm_GivesUp(t *testing.T) {
+	client := newMockClient()
+	masterKey, masterAddr := newTestKey()
+	master := txbuilder.NewKeySigner(masterKey)
+	client.balances[masterAddr] = mustParseBigInt("10000000000000000000")
+	client.stuck = 10
+	subAccounts := []common.Address{common.HexToAddress("0x1111111111111111111111111111111111111111")}
+
+	cfg := &Config{GasPerTx: 21000, TxsPerAccount: 10, GasPrice: big.NewInt(1000000000), BufferPercent: 20, FundRetries: 1}
+	distributor := New(client, cfg)
+	result, err := distributor.Distribute(context.Background(), master, subAccounts)
+	if err != nil {
+		t.Fatalf("Distribute() error: %v", err)
+	}
+	if err := distributor.Confirm(context.Background(), master, result, time.Millisecond); err == nil {
+		t.Fatal("Confirm() expected an error for accounts that never confirm")
+	}
+	if len(result.Retries) != 1 || result.Retries[0].Funded != 0 {
+		t.Errorf("Retries = %+v, want one that funded nothing", result.Retries)
+	}
+}
+
+func TestDistributor_Confirm_RaisesBumpOnUnderpriced(t *testing.T) {
+	client := newMockClient()
+	masterKey, masterAddr := newTestKey()
+	master := txbuilder.NewKeySigner(masterKey)
+	client.balances[masterAddr] = mustParseBigInt("10000000000000000000")
+	client.stuck = 1
+	subAccounts := []common.Address{common.HexToAddress("0x1111111111111111111111111111111111111111")}
+
+	cfg := &Config{GasPerTx: 21000, TxsPerAccount: 10, GasPrice: big.NewInt(1000000000), BufferPercent: 20, FundRetries: 2}
+	distributor := New(client, cfg)
+	result, err := distributor.Distribute(context.Background(), master, subAccounts)
+	if err != nil {
+		t.Fatalf("Distribute() error: %v", err)
+	}
+
+	// The node replaces the stuck transfer only at a 25% higher price
+	client.minGasPrice = big.NewInt(1250000000)
+	if err := distributor.Confirm(context.Background(), master, result, time.Millisecond); err != nil {
+		t.Fatalf("Confirm() error: %v", err)
+	}
+	if len(result.Retries) != 2 {
+		t.Fatalf("Retries = %+v, want two", result.Retries)
+	}
+	first, second := result.Retries[0], result.Retries[1]
+	if first.PriceBump != DefaultPriceBump || first.GasPrice != "1100000000" || first.Underpriced != 1 || first.Sent != 0 {
+		t.Errorf("first retry = %+v, want one underpriced at 1.1 Gwei", first)
+	}
+	if second.PriceBump != 2*DefaultPriceBump || second.GasPrice != "1320000000" || second.Underpriced != 0 || second.Funded != 1 {
+		t.Errorf("second retry = %+v, want funded at 1.32 Gwei", second)
+	}
+	if result.Underpriced != 1 {
+		t.Errorf("Underpriced = %d, want 1", result.Underpriced)
+	}
+}
+
+func TestReplacementPrice(t *testing.T) {
+	tests := []struct {
+		previous int64
+		bump     int
+		want     int64
+	}{
+		{1000000000, 10, 1100000000},
+		{1000000001, 10, 1100000002}, // Rounded up
+		{5, 10, 6},
+		{1, 0, 2}, // Always above the previous price
+		{0, 10, 0},
+	}
+	for _, tt := range tests {
+		if got := ReplacementPrice(big.NewInt(tt.previous), tt.bump); got.Int64() != tt.want {
+			t.Errorf("ReplacementPrice(%d, %d) = %s, want %d", tt.previous, tt.bump, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package distributor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/progress"
+)
+
+// ReclaimResult holds the result of sweeping sub-accounts back to the master
+type ReclaimResult struct {
+	Swept     int      // Accounts whose balance was sent back
+	Skipped   int      // Accounts holding no more than the sweep's gas cost
+	Failed    int      // Accounts whose sweep could not be sent
+	Reclaimed *big.Int // Total value sent back
+}
+
+// errPendingTxs marks an account whose transactions were still in flight
+var errPendingTxs = errors.New("transactions still pending")
+
+// Reclaim sends the balance of each sub-account, less the transfer's gas, back
+// to the master account. It first waits up to timeout for the transactions
+// the accounts still have in flight to be mined, since those spend from the
+// balance a sweep would send; accounts still pending by then are not swept.
+// Failures are counted, not returned, so one stuck account does not strand
+// the rest.
+func (d *Distributor) Reclaim(
+	ctx context.Context,
+	keys []*ecdsa.PrivateKey,
+	master common.Address,
+	timeout time.Duration,
+) (*ReclaimResult, error) {
+	console.Printf("\nReclaiming funds from %d sub-accounts to %s\n", len(keys), master.Hex())
+
+	if d.chainID == nil {
+		chainID, err := d.client.ChainID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		d.chainID = chainID
+	}
+	gasPrice, err := d.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transferGas := uint64(21000)
+	transferCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(transferGas))
+
+	signers := make([]txbuilder.Signer, len(keys))
+	for i, key := range keys {
+		signers[i] = txbuilder.NewKeySigner(key)
+	}
+	pending, err := d.awaitSettled(ctx, signers, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReclaimResult{Reclaimed: big.NewInt(0)}
+	var firstErr error
+	bar := progress.New(int64(len(keys)), "reclaiming funds")
+	for _, signer := range signers {
+		var swept *big.Int
+		err := pending[signer.Address()]
+		if err == nil {
+			swept, err = d.sweep(ctx, signer, master, gasPrice, transferGas, transferCost)
+		}
+		switch {
+		case err != nil:
+			result.Failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", signer.Address().Hex(), err)
+			}
+		case swept == nil:
+			result.Skipped++
+		default:
+			result.Swept++
+			result.Reclaimed.Add(result.Reclaimed, swept)
+		}
+		progress.Add(bar, 1)
+	}
+
+	console.OKf("\nReclaimed %s from %d accounts\n", d.config.Units.Format(result.Reclaimed), result.Swept)
+	if result.Skipped > 0 {
+		console.Printf("   %d accounts held no more than the sweep's gas cost\n", result.Skipped)
+	}
+	if result.Failed > 0 {
+		console.Warnf("%d accounts could not be swept, first failure: %v\n", result.Failed, firstErr)
+	}
+	return result, nil
+}
+
+// awaitSettled waits up to timeout until no account has transactions in
+// flight, that is until its pending nonce equals its latest nonce. It returns
+// why each account that has not settled by the deadline cannot be swept.
+func (d *Distributor) awaitSettled(
+	ctx context.Context,
+	signers []txbuilder.Signer,
+	timeout time.Duration,
+) (map[common.Address]error, error) {
+	deadline := time.Now().Add(timeout)
+	remaining := signers
+	for {
+		unsettled := make(map[common.Address]error)
+		var short []txbuilder.Signer
+		for _, signer := range remaining {
+			if err := d.settled(ctx, signer.Address()); err != nil {
+				unsettled[signer.Address()] = err
+				short = append(short, signer)
+			}
+		}
+		remaining = short
+		if len(remaining) == 0 || time.Now().After(deadline) {
+			return unsettled, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(fundingPollInterval):
+		}
+	}
+}
+
+// settled returns errPendingTxs if account has transactions in flight
+func (d *Distributor) settled(ctx context.Context, account common.Address) error {
+	latest, err := d.client.NonceAt(ctx, account, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	pending, err := d.client.PendingNonceAt(ctx, account)
+	if err != nil {
+		return fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+	if pending > latest {
+		return fmt.Errorf("%w: %d after nonce %d", errPendingTxs, pending-latest, latest)
+	}
+	return nil
+}
+
+// sweep sends one account's balance less gas to the master, returning the
+// value sent or nil when the balance does not cover the gas
+func (d *Distributor) sweep(
+	ctx context.Context,
+	signer txbuilder.Signer,
+	master common.Address,
+	gasPrice *big.Int,
+	transferGas uint64,
+	transferCost *big.Int,
+) (*big.Int, error) {
+	from := signer.Address()
+	balance, err := d.client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	if balance.Cmp(transferCost) <= 0 {
+		return nil, nil
+	}
+	nonce, err := d.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	value := new(big.Int).Sub(balance, transferCost)
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      transferGas,
+		To:       &master,
+		Value:    value,
+	})
+	signedTx, err := txbuilder.SignTransaction(tx, d.chainID, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign sweep tx: %w", err)
+	}
+	if err := d.client.SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("failed to send sweep tx: %w", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,230 @@
+package distributor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/progress"
+)
+
+const (
+	// DefaultFundRetries is the default number of funding retries
+	DefaultFundRetries = 2
+
+	// DefaultPriceBump is the least percentage by which nodes require a
+	// replacement to raise the gas price of the transaction it replaces
+	// (geth's txpool.pricebump)
+	DefaultPriceBump = 10
+
+	// fundingPollInterval is the interval at which funded balances are checked
+	fundingPollInterval = 500 * time.Millisecond
+)
+
+// RetryAttempt is one retry of the funding of accounts still short of their target
+type RetryAttempt struct {
+	Attempt     int    `json:"attempt"`
+	Accounts    int    `json:"accounts"`              // Accounts still short before the retry
+	Sent        int    `json:"sent"`                  // Transfers the node accepted
+	Funded      int    `json:"funded"`                // Accounts that reached their target after the retry
+	GasPrice    string `json:"gas_price"`             // Wei
+	PriceBump   int    `json:"price_bump"`            // Percent the gas price was raised at least over the previous attempt
+	Underpriced int    `json:"underpriced,omitempty"` // Replacements the node refused as underpriced
+	Error       string `json:"error,omitempty"`       // Why the retry could not send every transfer
+}
+
+// Confirm waits up to timeout for the funding of result's ready accounts to
+// confirm. Accounts whose transfer was refused or has not confirmed by then
+// are funded again, up to Config.FundRetries times. Each retry uses the
+// node's current gas price, raised at least Config.PriceBump percent over the
+// previous attempt, and starts at the master's confirmed nonce so that stuck
+// transfers are replaced. If the node still refuses replacements as
+// underpriced, the bump of the next retry is doubled. The retries are
+// recorded in result.Retries. It returns an error if accounts are still
+// short after the last retry.
+func (d *Distributor) Confirm(
+	ctx context.Context,
+	master txbuilder.Signer,
+	result *DistributionResult,
+	timeout time.Duration,
+) error {
+	console.Printf("\nWaiting for funding confirmations...\n")
+	short, err := d.awaitFunding(ctx, result.ReadyAccounts, timeout)
+	if err != nil {
+		return err
+	}
+	short = append(short, result.FailedAccounts...)
+
+	bump := d.config.priceBump()
+	for attempt := 1; len(short) > 0; attempt++ {
+		if attempt > d.config.FundRetries {
+			return fmt.Errorf("%d accounts still unfunded after %d retries", len(short), d.config.FundRetries)
+		}
+		retry, res, err := d.retry(ctx, master, result, short, attempt, bump)
+		if err != nil {
+			return err
+		}
+		if retry.Underpriced > 0 {
+			// The node's bump rule is stricter than configured
+			bump *= 2
+			console.Warnf("Retry %d: %d replacements refused as underpriced, raising the price bump to %d%%\n",
+				attempt, retry.Underpriced, bump)
+		}
+		short, err = d.awaitFunding(ctx, res.ReadyAccounts, timeout)
+		if err != nil {
+			return err
+		}
+		short = append(short, res.FailedAccounts...)
+		retry.Funded = retry.Accounts - len(short) - len(res.UnfundedAccounts)
+		console.Printf("Retry %d: %d of %d accounts funded\n", attempt, retry.Funded, retry.Accounts)
+	}
+
+	console.OKf("All funding transactions confirmed\n")
+	return nil
+}
+
+// retry funds the short accounts again and merges the outcome into result.
+// It returns the attempt and the outcome of the retry alone.
+func (d *Distributor) retry(
+	ctx context.Context,
+	master txbuilder.Signer,
+	result *DistributionResult,
+	short []*AccountStatus,
+	attempt int,
+	bump int,
+) (*RetryAttempt, *DistributionResult, error) {
+	gasPrice, err := d.retryGasPrice(ctx, result.GasPrice, bump)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := d.client.NonceAt(ctx, master.Address(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get master nonce: %w", err)
+	}
+	for _, account := range short {
+		account.IsFunded = false
+		account.MissingFund = new(big.Int).Sub(account.RequiredFund, account.Balance)
+	}
+
+	console.Warnf("\nRetry %d: funding %d accounts again at %s gas price\n", attempt, len(short), d.config.Units.Format(gasPrice))
+	retry := &RetryAttempt{Attempt: attempt, Accounts: len(short), GasPrice: gasPrice.String(), PriceBump: bump}
+	result.Retries = append(result.Retries, retry)
+
+	res, err := d.fundAccounts(ctx, master, short, gasPrice, nonce)
+	if err != nil {
+		retry.Error = err.Error()
+		return nil, nil, fmt.Errorf("funding retry %d failed: %w", attempt, err)
+	}
+	retry.Sent = res.TxCount
+	retry.Underpriced = res.Underpriced
+	if len(res.FailedAccounts) > 0 {
+		retry.Error = fmt.Sprintf("%d transfers refused", len(res.FailedAccounts))
+	}
+	if len(res.UnfundedAccounts) > 0 {
+		retry.Error = fmt.Sprintf("%d accounts beyond the master balance", len(res.UnfundedAccounts))
+	}
+
+	// The short accounts are ready again only if the retry sent their transfer
+	retried := make(map[*AccountStatus]bool, len(short))
+	for _, account := range short {
+		retried[account] = true
+	}
+	ready := make([]*AccountStatus, 0, len(result.ReadyAccounts)+len(res.ReadyAccounts))
+	for _, account := range result.ReadyAccounts {
+		if !retried[account] {
+			ready = append(ready, account)
+		}
+	}
+	result.ReadyAccounts = append(ready, res.ReadyAccounts...)
+	result.FailedAccounts = res.FailedAccounts
+	result.UnfundedAccounts = append(result.UnfundedAccounts, res.UnfundedAccounts...)
+	result.TotalDistributed.Add(result.TotalDistributed, res.TotalDistributed)
+	result.TxCount += res.TxCount
+	result.Underpriced += res.Underpriced
+	result.GasPrice = gasPrice
+	return retry, res, nil
+}
+
+// retryGasPrice returns the gas price of a retry: the configured or suggested
+// price, but at least the replacement price of the previous one under bump
+func (d *Distributor) retryGasPrice(ctx context.Context, previous *big.Int, bump int) (*big.Int, error) {
+	gasPrice, err := d.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return gasPrice, nil
+	}
+	if replacement := ReplacementPrice(previous, bump); replacement.Cmp(gasPrice) > 0 {
+		return replacement, nil
+	}
+	return gasPrice, nil
+}
+
+// ReplacementPrice returns the least gas price a node with the given price
+// bump rule accepts for a transaction replacing one priced at previous:
+// previous raised by bump percent, rounded up, and above previous. Zero stays
+// zero on zero-fee networks.
+func ReplacementPrice(previous *big.Int, bump int) *big.Int {
+	if previous.Sign() <= 0 {
+		return new(big.Int)
+	}
+	price := new(big.Int).Mul(previous, big.NewInt(int64(100+bump)))
+	price.Add(price, big.NewInt(99))
+	price.Div(price, big.NewInt(100))
+	if price.Cmp(previous) <= 0 {
+		price.Add(previous, big.NewInt(1))
+	}
+	return price
+}
+
+// isUnderpriced reports whether a node refused a transaction because it
+// replaces a pending one without raising the price enough
+func isUnderpriced(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "replacement transaction underpriced") ||
+		strings.Contains(msg, "replacement fee too low") || strings.Contains(msg, "underpriced replacement")
+}
+
+// awaitFunding waits up to timeout for the balance of each account to reach
+// its target and returns the accounts still short at the deadline
+func (d *Distributor) awaitFunding(
+	ctx context.Context,
+	accounts []*AccountStatus,
+	timeout time.Duration,
+) ([]*AccountStatus, error) {
+	deadline := time.Now().Add(timeout)
+	bar := progress.New(int64(len(accounts)), "confirming")
+
+	remaining := accounts
+	for {
+		var short []*AccountStatus
+		for _, account := range remaining {
+			balance, err := d.client.BalanceAt(ctx, account.Address, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check balance: %w", err)
+			}
+			account.Balance = balance
+			if balance.Cmp(account.RequiredFund) >= 0 {
+				account.IsFunded = true
+				progress.Add(bar, 1)
+				continue
+			}
+			short = append(short, account)
+		}
+		remaining = short
+		if len(remaining) == 0 || time.Now().After(deadline) {
+			return remaining, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(fundingPollInterval):
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xmhha/txhammer/internal/util/units"
 )
 
 // AccountStatus represents the funding status of an account
@@ -24,11 +26,23 @@ type DistributionResult struct {
 	// Accounts that could not be funded
 	UnfundedAccounts []*AccountStatus
 
+	// Accounts whose funding transaction the node refused
+	FailedAccounts []*AccountStatus
+
 	// Total amount distributed
 	TotalDistributed *big.Int
 
 	// Number of distribution transactions sent
 	TxCount int
+
+	// Transfers the node refused as underpriced replacements of pending ones
+	Underpriced int
+
+	// Gas price of the last funding transactions sent
+	GasPrice *big.Int
+
+	// Funding retries of accounts still short after the distribution, in order
+	Retries []*RetryAttempt
 }
 
 // Config holds distribution configuration
@@ -39,11 +53,30 @@ type Config struct {
 	// Number of transactions each sub-account will send
 	TxsPerAccount int
 
-	// Gas price for calculations
+	// Gas price for calculations (0 on zero-fee networks)
 	GasPrice *big.Int
 
+	// Value each transaction sends (nil = none)
+	ValuePerTx *big.Int
+
+	// Per-account funding target that replaces the computed one (nil = computed)
+	FundAmount *big.Int
+
+	// Headroom added to the funding target (nil = none)
+	FundExtra *big.Int
+
 	// Extra buffer percentage (e.g., 10 for 10% extra)
 	BufferPercent int
+
+	// Times accounts still short after the distribution are funded again (0 = none)
+	FundRetries int
+
+	// Percent a retry raises the gas price at least over the previous
+	// attempt, to meet the node's replacement rule (0 = DefaultPriceBump)
+	PriceBump int
+
+	// Units formats wei amounts in console output
+	Units units.Formatter
 }
 
 // DefaultConfig returns default distribution configuration
@@ -56,14 +89,38 @@ func DefaultConfig() *Config {
 	}
 }
 
-// CalculateRequiredFund calculates the required fund for an account
+// priceBump returns the configured price bump or DefaultPriceBump
+func (c *Config) priceBump() int {
+	if c.PriceBump > 0 {
+		return c.PriceBump
+	}
+	return DefaultPriceBump
+}
+
+// CalculateRequiredFund calculates the required fund for an account: the
+// computed cost, or FundAmount if set, plus FundExtra
 func (c *Config) CalculateRequiredFund() *big.Int {
-	// Required fund formula: gasPerTx × txsPerAccount × gasPrice × (1 + buffer/100)
-	baseCost := new(big.Int).Mul(
-		new(big.Int).SetUint64(c.GasPerTx),
-		big.NewInt(int64(c.TxsPerAccount)),
-	)
-	baseCost.Mul(baseCost, c.GasPrice)
+	required := c.computedFund()
+	if c.FundAmount != nil {
+		required = new(big.Int).Set(c.FundAmount)
+	}
+	if c.FundExtra != nil {
+		required.Add(required, c.FundExtra)
+	}
+	return required
+}
+
+// computedFund returns the fund an account needs for the gas and value of its transactions
+func (c *Config) computedFund() *big.Int {
+	// Required fund formula: (gasPerTx × gasPrice + valuePerTx) × txsPerAccount × (1 + buffer/100)
+	baseCost := new(big.Int)
+	if c.GasPrice != nil {
+		baseCost.Mul(new(big.Int).SetUint64(c.GasPerTx), c.GasPrice)
+	}
+	if c.ValuePerTx != nil {
+		baseCost.Add(baseCost, c.ValuePerTx)
+	}
+	baseCost.Mul(baseCost, big.NewInt(int64(c.TxsPerAccount)))
 
 	// Add buffer
 	if c.BufferPercent > 0 {
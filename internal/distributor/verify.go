@@ -0,0 +1,95 @@
+package distributor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// balanceBatchSize is the number of eth_getBalance calls sent per batch request
+const balanceBatchSize = 100
+
+// BatchClient is implemented by clients that can batch RPC calls
+type BatchClient interface {
+	BatchCall(batch []rpc.BatchElem) error
+}
+
+// VerifyFunding checks that every account holds the required fund without
+// sending anything, and returns the underfunded accounts. Balances are
+// fetched in batched eth_getBalance requests when the client supports it.
+func (d *Distributor) VerifyFunding(ctx context.Context, accounts []common.Address) ([]*AccountStatus, error) {
+	requiredFund := d.config.CalculateRequiredFund()
+	console.Printf("Verifying balances of %d accounts (required: %s each)...\n",
+		len(accounts), d.config.Units.Format(requiredFund))
+
+	balances, err := d.fetchBalances(ctx, accounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check balances: %w", err)
+	}
+
+	var underfunded []*AccountStatus
+	for i, addr := range accounts {
+		if balances[i].Cmp(requiredFund) >= 0 {
+			continue
+		}
+		underfunded = append(underfunded, &AccountStatus{
+			Address:      addr,
+			Balance:      balances[i],
+			RequiredFund: requiredFund,
+			MissingFund:  new(big.Int).Sub(requiredFund, balances[i]),
+		})
+	}
+	return underfunded, nil
+}
+
+// fetchBalances returns the balance of each account, batching the requests
+// when the client supports it
+func (d *Distributor) fetchBalances(ctx context.Context, accounts []common.Address) ([]*big.Int, error) {
+	balances := make([]*big.Int, len(accounts))
+
+	batcher, ok := d.client.(BatchClient)
+	if !ok {
+		for i, addr := range accounts {
+			balance, err := d.client.BalanceAt(ctx, addr, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get balance for %s: %w", addr.Hex(), err)
+			}
+			balances[i] = balance
+		}
+		return balances, nil
+	}
+
+	for start := 0; start < len(accounts); start += balanceBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := min(start+balanceBatchSize, len(accounts))
+
+		results := make([]hexutil.Big, end-start)
+		batch := make([]rpc.BatchElem, end-start)
+		for i, addr := range accounts[start:end] {
+			batch[i] = rpc.BatchElem{
+				Method: "eth_getBalance",
+				Args:   []interface{}{addr, "latest"},
+				Result: &results[i],
+			}
+		}
+
+		if err := batcher.BatchCall(batch); err != nil {
+			return nil, fmt.Errorf("batch balance request failed: %w", err)
+		}
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, fmt.Errorf("failed to get balance for %s: %w", accounts[start+i].Hex(), elem.Error)
+			}
+			balances[start+i] = results[i].ToInt()
+		}
+	}
+	return balances, nil
+}
@@ -0,0 +1,78 @@
+package distributor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// batchMockClient answers eth_getBalance batches from the mock balances
+type batchMockClient struct {
+	*mockClient
+	batches int
+}
+
+func (m *batchMockClient) BatchCall(batch []rpc.BatchElem) error {
+	m.batches++
+	for i := range batch {
+		addr := batch[i].Args[0].(common.Address)
+		balance, _ := m.BalanceAt(context.Background(), addr, nil)
+		*batch[i].Result.(*hexutil.Big) = hexutil.Big(*balance)
+	}
+	return nil
+}
+
+func verifyTestAccounts(client *mockClient, n int, underfunded map[int]bool) []common.Address {
+	accounts := make([]common.Address, n)
+	for i := range accounts {
+		accounts[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+		if !underfunded[i] {
+			client.balances[accounts[i]] = mustParseBigInt("1000000000000000000") // 1 ETH
+		}
+	}
+	return accounts
+}
+
+func TestDistributor_VerifyFunding_Batched(t *testing.T) {
+	client := &batchMockClient{mockClient: newMockClient()}
+	accounts := verifyTestAccounts(client.mockClient, 150, map[int]bool{3: true, 120: true})
+
+	underfunded, err := New(client, DefaultConfig()).VerifyFunding(context.Background(), accounts)
+	if err != nil {
+		t.Fatalf("VerifyFunding() error: %v", err)
+	}
+
+	if client.batches != 2 {
+		t.Errorf("batch requests = %d, want 2", client.batches)
+	}
+	if len(underfunded) != 2 {
+		t.Fatalf("underfunded = %d, want 2", len(underfunded))
+	}
+	if underfunded[0].Address != accounts[3] || underfunded[1].Address != accounts[120] {
+		t.Errorf("underfunded = %s, %s; want %s, %s",
+			underfunded[0].Address.Hex(), underfunded[1].Address.Hex(), accounts[3].Hex(), accounts[120].Hex())
+	}
+	if underfunded[0].MissingFund.Cmp(underfunded[0].RequiredFund) != 0 {
+		t.Errorf("MissingFund = %s, want %s", underfunded[0].MissingFund, underfunded[0].RequiredFund)
+	}
+	if len(client.sentTxs) != 0 {
+		t.Errorf("VerifyFunding sent %d transactions", len(client.sentTxs))
+	}
+}
+
+func TestDistributor_VerifyFunding_Unbatched(t *testing.T) {
+	client := newMockClient()
+	accounts := verifyTestAccounts(client, 5, nil)
+
+	underfunded, err := New(client, DefaultConfig()).VerifyFunding(context.Background(), accounts)
+	if err != nil {
+		t.Fatalf("VerifyFunding() error: %v", err)
+	}
+	if len(underfunded) != 0 {
+		t.Errorf("underfunded = %d, want 0", len(underfunded))
+	}
+}
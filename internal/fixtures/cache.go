@@ -0,0 +1,98 @@
+// Package fixtures records the helper contracts txhammer deploys, per chain
+// ID, so later runs can reuse them instead of redeploying every time.
+package fixtures
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Deployment is a helper contract deployed on one chain
+type Deployment struct {
+	Address      common.Address `json:"address"`
+	InitCodeHash common.Hash    `json:"init_code_hash"` // Bytecode and constructor arguments
+	TxHash       common.Hash    `json:"tx_hash"`
+	DeployedAt   time.Time      `json:"deployed_at"`
+}
+
+// Cache holds deployments by chain ID and fixture name
+type Cache struct {
+	Chains map[string]map[string]*Deployment `json:"chains"`
+}
+
+// NewCache creates an empty cache
+func NewCache() *Cache {
+	return &Cache{Chains: make(map[string]map[string]*Deployment)}
+}
+
+// Load reads a cache from a JSON file; a missing file is an empty cache
+func Load(path string) (*Cache, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewCache(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture cache: %w", err)
+	}
+
+	cache := NewCache()
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture cache %s: %w", path, err)
+	}
+	if cache.Chains == nil {
+		cache.Chains = make(map[string]map[string]*Deployment)
+	}
+	return cache, nil
+}
+
+// Save writes the cache to a JSON file, creating parent directories. The
+// file is replaced atomically so an interrupted run cannot truncate it.
+func (c *Cache) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write fixture cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write fixture cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write fixture cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write fixture cache: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the deployment of a fixture on a chain
+func (c *Cache) Lookup(chainID *big.Int, name string) (*Deployment, bool) {
+	d, ok := c.Chains[chainID.String()][name]
+	return d, ok
+}
+
+// Record stores the deployment of a fixture on a chain, replacing any earlier one
+func (c *Cache) Record(chainID *big.Int, name string, d *Deployment) {
+	key := chainID.String()
+	if c.Chains[key] == nil {
+		c.Chains[key] = make(map[string]*Deployment)
+	}
+	c.Chains[key][name] = d
+}
@@ -0,0 +1,147 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Fixture names
+const (
+	ChurnFactory = "create2-churn-factory"
+	ERC721       = "erc721"
+	Counter      = "contention-counter"
+	SwapPair     = "swap-pair"
+)
+
+// Client defines the interface for deploying fixtures and checking cached ones
+type Client interface {
+	// CodeAt returns the contract code of an account
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	// SendTransaction sends a signed transaction
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	// TransactionReceipt returns the receipt of a mined transaction
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Manager reuses cached fixture deployments and deploys missing ones
+type Manager struct {
+	client   Client
+	chainID  *big.Int
+	cache    *Cache
+	path     string // Cache file ("" = no cache, always deploy)
+	redeploy bool
+
+	// Deployment receipt polling
+	timeout      time.Duration
+	pollInterval time.Duration
+}
+
+// NewManager creates a manager backed by the cache file at path; an empty
+// path disables caching
+func NewManager(client Client, chainID *big.Int, path string) (*Manager, error) {
+	cache := NewCache()
+	if path != "" {
+		var err error
+		if cache, err = Load(path); err != nil {
+			return nil, err
+		}
+	}
+	return &Manager{
+		client:       client,
+		chainID:      chainID,
+		cache:        cache,
+		path:         path,
+		timeout:      60 * time.Second,
+		pollInterval: 500 * time.Millisecond,
+	}, nil
+}
+
+// WithRedeploy ignores cached deployments; new ones still replace them in the cache
+func (m *Manager) WithRedeploy(redeploy bool) *Manager {
+	m.redeploy = redeploy
+	return m
+}
+
+// Ensure returns the address of the named fixture. A cached deployment is
+// reused if it was built from the same init code and still has code on
+// chain (devnets are often reset under the same chain ID); otherwise deployTx
+// is sent, awaited and recorded.
+func (m *Manager) Ensure(ctx context.Context, name string, deployTx *types.Transaction) (common.Address, error) {
+	initCodeHash := crypto.Keccak256Hash(deployTx.Data())
+
+	if d, ok := m.cache.Lookup(m.chainID, name); ok && !m.redeploy {
+		switch reusable, err := m.reusable(ctx, d, initCodeHash); {
+		case err != nil:
+			return common.Address{}, err
+		case reusable:
+			console.OKf("Reusing %s at %s (deployed %s)\n", name, d.Address.Hex(), d.DeployedAt.Format(time.RFC3339))
+			return d.Address, nil
+		}
+	}
+
+	d, err := m.deploy(ctx, name, deployTx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	d.InitCodeHash = initCodeHash
+	if m.path != "" {
+		m.cache.Record(m.chainID, name, d)
+		if err := m.cache.Save(m.path); err != nil {
+			console.Warnf("Failed to save fixture cache: %v\n", err)
+		}
+	}
+	return d.Address, nil
+}
+
+// reusable reports whether a cached deployment matches the init code and still exists
+func (m *Manager) reusable(ctx context.Context, d *Deployment, initCodeHash common.Hash) (bool, error) {
+	if d.InitCodeHash != initCodeHash {
+		return false, nil
+	}
+	code, err := m.client.CodeAt(ctx, d.Address, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to check cached fixture at %s: %w", d.Address.Hex(), err)
+	}
+	return len(code) > 0, nil
+}
+
+// deploy sends deployTx and waits for its receipt
+func (m *Manager) deploy(ctx context.Context, name string, deployTx *types.Transaction) (*Deployment, error) {
+	if err := m.client.SendTransaction(ctx, deployTx); err != nil {
+		return nil, fmt.Errorf("failed to deploy %s: %w", name, err)
+	}
+	console.Printf("Deploying %s (tx %s)...\n", name, deployTx.Hash().Hex())
+
+	deadline := time.Now().Add(m.timeout)
+	for {
+		receipt, err := m.client.TransactionReceipt(ctx, deployTx.Hash())
+		if err == nil && receipt != nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return nil, fmt.Errorf("%s deployment reverted", name)
+			}
+			console.OKf("%s deployed at %s\n", name, receipt.ContractAddress.Hex())
+			return &Deployment{
+				Address:    receipt.ContractAddress,
+				TxHash:     deployTx.Hash(),
+				DeployedAt: time.Now().UTC(),
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timeout waiting for %s deployment", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(m.pollInterval):
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package fixtures
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// mockClient deploys every contract instantly at a fresh address
+type mockClient struct {
+	code     map[common.Address][]byte
+	receipts map[common.Hash]*types.Receipt
+	deploys  int
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{
+		code:     make(map[common.Address][]byte),
+		receipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (m *mockClient) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	return m.code[account], nil
+}
+
+func (m *mockClient) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	m.deploys++
+	addr := common.BigToAddress(big.NewInt(int64(m.deploys)))
+	m.code[addr] = []byte{0x60, 0x00}
+	m.receipts[tx.Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful, ContractAddress: addr}
+	return nil
+}
+
+func (m *mockClient) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	return m.receipts[hash], nil
+}
+
+func deployTx(nonce uint64, initCode []byte) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, Gas: 1_000_000, GasPrice: big.NewInt(1), Data: initCode})
+}
+
+func newTestManager(t *testing.T, client Client, path string) *Manager {
+	t.Helper()
+	m, err := NewManager(client, big.NewInt(1337), path)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	m.pollInterval = time.Millisecond
+	return m
+}
+
+func TestManager_Ensure(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	client := newMockClient()
+	initCode := []byte{0x60, 0x80}
+
+	first, err := newTestManager(t, client, path).Ensure(ctx, ERC721, deployTx(0, initCode))
+	if err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+
+	// A new run reuses the cached deployment
+	second, err := newTestManager(t, client, path).Ensure(ctx, ERC721, deployTx(1, initCode))
+	if err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	if second != first || client.deploys != 1 {
+		t.Errorf("got %s after %d deploys, want reuse of %s", second.Hex(), client.deploys, first.Hex())
+	}
+
+	// Fixtures are cached by name
+	if _, err := newTestManager(t, client, path).Ensure(ctx, ChurnFactory, deployTx(2, initCode)); err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	if client.deploys != 2 {
+		t.Errorf("deploys = %d, want 2", client.deploys)
+	}
+}
+
+func TestManager_EnsureRedeploys(t *testing.T) {
+	ctx := context.Background()
+	initCode := []byte{0x60, 0x80}
+
+	tests := []struct {
+		name     string
+		initCode []byte
+		reset    bool // Chain reset under the same chain ID
+		redeploy bool
+	}{
+		{name: "init code changed", initCode: []byte{0x60, 0x81}},
+		{name: "code missing on chain", initCode: initCode, reset: true},
+		{name: "redeploy", initCode: initCode, redeploy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fixtures.json")
+			client := newMockClient()
+			first, err := newTestManager(t, client, path).Ensure(ctx, ERC721, deployTx(0, initCode))
+			if err != nil {
+				t.Fatalf("Ensure() error = %v", err)
+			}
+			if tt.reset {
+				delete(client.code, first)
+			}
+
+			second, err := newTestManager(t, client, path).WithRedeploy(tt.redeploy).Ensure(ctx, ERC721, deployTx(1, tt.initCode))
+			if err != nil {
+				t.Fatalf("Ensure() error = %v", err)
+			}
+			if second == first || client.deploys != 2 {
+				t.Fatalf("got %s after %d deploys, want a new deployment", second.Hex(), client.deploys)
+			}
+
+			// The new deployment replaces the cached one
+			cache, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			d, ok := cache.Lookup(big.NewInt(1337), ERC721)
+			if !ok || d.Address != second || d.InitCodeHash != crypto.Keccak256Hash(tt.initCode) {
+				t.Errorf("cached deployment = %+v, want %s", d, second.Hex())
+			}
+		})
+	}
+}
+
+func TestManager_NoCache(t *testing.T) {
+	ctx := context.Background()
+	client := newMockClient()
+	for i := range 2 {
+		if _, err := newTestManager(t, client, "").Ensure(ctx, ERC721, deployTx(uint64(i), []byte{0x60})); err != nil {
+			t.Fatalf("Ensure() error = %v", err)
+		}
+	}
+	if client.deploys != 2 {
+		t.Errorf("deploys = %d, want 2 without a cache", client.deploys)
+	}
+}
+
+func TestCache_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "fixtures.json")
+
+	cache, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file error = %v", err)
+	}
+	if len(cache.Chains) != 0 {
+		t.Fatalf("missing file should load as an empty cache")
+	}
+
+	d := &Deployment{
+		Address:      common.HexToAddress("0x1234"),
+		InitCodeHash: common.HexToHash("0xabcd"),
+		TxHash:       common.HexToHash("0x5678"),
+		DeployedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	cache.Record(big.NewInt(1), ChurnFactory, d)
+	if err := cache.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, ok := loaded.Lookup(big.NewInt(1), ChurnFactory)
+	if !ok || *got != *d {
+		t.Errorf("Lookup() = %+v, want %+v", got, d)
+	}
+	if _, ok := loaded.Lookup(big.NewInt(2), ChurnFactory); ok {
+		t.Error("deployments must be keyed by chain ID")
+	}
+}
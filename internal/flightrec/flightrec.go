@@ -0,0 +1,226 @@
+// Package flightrec appends the live counters of a run to a CSV file once per
+// interval, so every run leaves a time series on disk without a metrics stack
+package flightrec
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DefaultInterval is the time between two rows
+const DefaultInterval = time.Second
+
+// header is the first row of the CSV
+var header = []string{"Time", "Seconds", "Sent", "SendFailed", "Confirmed", "Reverted", "Pending", "SendTPS", "ConfirmTPS", "PoolDepth"}
+
+// Client defines the interface for reading the node's transaction pool
+type Client interface {
+	CallContext(ctx context.Context, result any, method string, args ...any) error
+}
+
+// Counts are the receipt counters of the run at one point in time
+type Counts struct {
+	Confirmed int64 // Mined successfully
+	Reverted  int64 // Mined with a failed status
+	Pending   int64 // Tracked and not yet mined
+}
+
+// poolStatus is the txpool_status result
+type poolStatus struct {
+	Pending hexutil.Uint64 `json:"pending"`
+	Queued  hexutil.Uint64 `json:"queued"`
+}
+
+// Recorder writes one row of counters per interval until Stop
+type Recorder struct {
+	client   Client
+	interval time.Duration
+	counts   func() (Counts, bool)
+
+	sent       atomic.Int64
+	sendFailed atomic.Int64
+
+	mu     sync.Mutex
+	file   *os.File
+	writer *csv.Writer
+	start  time.Time
+	last   time.Time
+	prev   [2]int64 // Sent and confirmed at the last row
+	pool   bool     // txpool_status is served
+	rows   int
+	err    error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Create creates the CSV at path and writes its header. A nil client leaves
+// the pool depth empty.
+func Create(path string, client Client, interval time.Duration) (*Recorder, error) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.Write(header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+	writer.Flush()
+	return &Recorder{
+		client:   client,
+		interval: interval,
+		file:     file,
+		writer:   writer,
+		pool:     client != nil,
+	}, nil
+}
+
+// WithCounts sets the source of the receipt counters. It reports false while
+// no receipts are tracked, which leaves their cells empty.
+func (r *Recorder) WithCounts(counts func() (Counts, bool)) *Recorder {
+	r.counts = counts
+	return r
+}
+
+// Sent counts a transaction the node accepted
+func (r *Recorder) Sent() {
+	r.sent.Add(1)
+}
+
+// Failed counts a transaction the node rejected
+func (r *Recorder) Failed() {
+	r.sendFailed.Add(1)
+}
+
+// Start writes the first row and keeps writing in the background until Stop
+func (r *Recorder) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+	r.start = time.Now()
+	r.last = r.start
+	r.record(ctx)
+
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.record(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends background recording, writes a last row, closes the file and
+// returns the number of rows written
+func (r *Recorder) Stop(ctx context.Context) (int, error) {
+	if r.cancel != nil {
+		r.cancel()
+		<-r.done
+		r.cancel = nil
+	}
+	r.record(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = fmt.Errorf("failed to close file: %w", err)
+	}
+	return r.rows, r.err
+}
+
+// record appends a row and flushes it, so the file holds every row written
+// so far even if the run is killed. After a write error it does nothing.
+func (r *Recorder) record(ctx context.Context) {
+	now := time.Now()
+	sent, sendFailed := r.sent.Load(), r.sendFailed.Load()
+	var counts Counts
+	tracked := false
+	if r.counts != nil {
+		counts, tracked = r.counts()
+	}
+	depth, pool := r.poolDepth(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	elapsed := now.Sub(r.last).Seconds()
+	row := []string{
+		now.Format(time.RFC3339Nano),
+		strconv.FormatFloat(now.Sub(r.start).Seconds(), 'f', 3, 64),
+		strconv.FormatInt(sent, 10),
+		strconv.FormatInt(sendFailed, 10),
+		"", "", "",
+		rate(sent-r.prev[0], elapsed),
+		"",
+		"",
+	}
+	if tracked {
+		row[4] = strconv.FormatInt(counts.Confirmed, 10)
+		row[5] = strconv.FormatInt(counts.Reverted, 10)
+		row[6] = strconv.FormatInt(counts.Pending, 10)
+		row[8] = rate(counts.Confirmed-r.prev[1], elapsed)
+	}
+	if pool {
+		row[9] = strconv.FormatUint(depth, 10)
+	}
+	if err := r.writer.Write(row); err != nil {
+		r.err = fmt.Errorf("failed to write row: %w", err)
+		return
+	}
+	r.writer.Flush()
+	if err := r.writer.Error(); err != nil {
+		r.err = fmt.Errorf("failed to write row: %w", err)
+		return
+	}
+	r.rows++
+	r.last = now
+	r.prev = [2]int64{sent, counts.Confirmed}
+}
+
+// poolDepth reads the number of pending and queued txs in the node's pool.
+// A node that does not serve txpool_status is not asked again.
+func (r *Recorder) poolDepth(ctx context.Context) (uint64, bool) {
+	r.mu.Lock()
+	pool := r.pool
+	r.mu.Unlock()
+	if !pool {
+		return 0, false
+	}
+	var status poolStatus
+	if err := r.client.CallContext(ctx, &status, "txpool_status"); err != nil {
+		if ctx.Err() == nil {
+			r.mu.Lock()
+			r.pool = false
+			r.mu.Unlock()
+		}
+		return 0, false
+	}
+	return uint64(status.Pending) + uint64(status.Queued), true
+}
+
+// rate formats n events over elapsed seconds as a per-second rate
+func rate(n int64, elapsed float64) string {
+	if elapsed <= 0 {
+		return "0.00"
+	}
+	return strconv.FormatFloat(float64(n)/elapsed, 'f', 2, 64)
+}
@@ -0,0 +1,109 @@
+package flightrec
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// poolClient answers txpool_status, or fails it when unsupported
+type poolClient struct {
+	unsupported bool
+	calls       int
+}
+
+func (c *poolClient) CallContext(_ context.Context, result any, method string, _ ...any) error {
+	c.calls++
+	if c.unsupported || method != "txpool_status" {
+		return errors.New("the method txpool_status does not exist/is not available")
+	}
+	*result.(*poolStatus) = poolStatus{Pending: hexutil.Uint64(7), Queued: hexutil.Uint64(3)}
+	return nil
+}
+
+// readRows reads the CSV at path without its header
+func readRows(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) == 0 || rows[0][0] != "Time" {
+		t.Fatalf("missing header: %v", rows)
+	}
+	return rows[1:]
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flight.csv")
+	rec, err := Create(path, &poolClient{}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	rec.WithCounts(func() (Counts, bool) {
+		return Counts{Confirmed: 4, Reverted: 1, Pending: 2}, true
+	})
+
+	rec.Start(context.Background())
+	for i := 0; i < 5; i++ {
+		rec.Sent()
+	}
+	rec.Failed()
+	time.Sleep(35 * time.Millisecond)
+	rows, err := rec.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	got := readRows(t, path)
+	if rows < 3 || len(got) != rows {
+		t.Fatalf("Stop() = %d rows, file has %d, want at least 3", rows, len(got))
+	}
+	last := got[len(got)-1]
+	want := map[int]string{2: "5", 3: "1", 4: "4", 5: "1", 6: "2", 9: "10"}
+	for col, v := range want {
+		if last[col] != v {
+			t.Errorf("last row %s = %q, want %q", header[col], last[col], v)
+		}
+	}
+	// Counts did not move after the first row
+	if last[8] != "0.00" {
+		t.Errorf("last row ConfirmTPS = %q, want 0.00", last[8])
+	}
+}
+
+func TestRecorder_NoCountsOrPool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flight.csv")
+	client := &poolClient{unsupported: true}
+	rec, err := Create(path, client, time.Hour)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	rec.Start(context.Background())
+	rec.Sent()
+	if _, err := rec.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	got := readRows(t, path)
+	if len(got) != 2 {
+		t.Fatalf("got %d rows, want 2", len(got))
+	}
+	if row := got[1]; row[2] != "1" || row[4] != "" || row[8] != "" || row[9] != "" {
+		t.Errorf("row = %v, want sends only", row)
+	}
+	if client.calls != 1 {
+		t.Errorf("txpool_status asked %d times, want once", client.calls)
+	}
+}
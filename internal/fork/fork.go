@@ -0,0 +1,210 @@
+package fork
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Config holds configuration for a fork comparison
+type Config struct {
+	ActivationBlock uint64 // First block of the new fork
+	LeadBlocks      uint64 // The before phase starts once the chain is this many blocks short of the activation
+}
+
+// DefaultConfig returns default fork comparison configuration
+func DefaultConfig() *Config {
+	return &Config{LeadBlocks: 30}
+}
+
+// Validate validates the configuration
+func (c *Config) Validate() error {
+	if c.ActivationBlock == 0 {
+		return fmt.Errorf("activation-block is required")
+	}
+	if c.LeadBlocks == 0 {
+		return fmt.Errorf("lead-blocks must be greater than 0")
+	}
+	if c.LeadBlocks >= c.ActivationBlock {
+		return fmt.Errorf("lead-blocks must be smaller than activation-block")
+	}
+	return nil
+}
+
+// StartBlock returns the chain head at which the before phase starts sending
+func (c *Config) StartBlock() uint64 {
+	return c.ActivationBlock - c.LeadBlocks
+}
+
+// Phase holds the measured outcome of the workload on one side of the activation
+type Phase struct {
+	Name         string
+	StartBlock   uint64 // Chain head when sending started
+	Sent         int
+	Unsent       int // Left unsent because the chain reached the activation block
+	Confirmed    int
+	Failed       int
+	Outside      int           // Before-phase txs included at or after the activation block
+	SendTPS      float64       // Transactions accepted by the node per second
+	ChainTPS     float64       // Confirmed transactions per second of block time
+	AvgLatency   time.Duration // Average confirmation latency
+	P95Latency   time.Duration
+	AvgGasUsed   uint64
+	TotalGasUsed uint64
+	Err          error // Set if the phase could not run
+}
+
+// Result holds the outcome of a fork comparison
+type Result struct {
+	ActivationBlock uint64
+	Before          *Phase
+	After           *Phase
+}
+
+// Comparable reports whether both phases ran and confirmed transactions
+func (r *Result) Comparable() bool {
+	for _, phase := range []*Phase{r.Before, r.After} {
+		if phase == nil || phase.Err != nil || phase.Confirmed == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Change returns the percent change from before to after (0 if before is 0)
+func Change(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return (after - before) / before * 100
+}
+
+// Summary is the machine-readable result of a fork comparison
+type Summary struct {
+	ActivationBlock uint64        `json:"activation_block"`
+	Before          *PhaseSummary `json:"before,omitempty"`
+	After           *PhaseSummary `json:"after,omitempty"`
+	Deltas          *Deltas       `json:"deltas,omitempty"` // Omitted unless both phases confirmed txs
+}
+
+// PhaseSummary is one phase of a fork comparison
+type PhaseSummary struct {
+	StartBlock   uint64  `json:"start_block"`
+	Sent         int     `json:"sent"`
+	Unsent       int     `json:"unsent,omitempty"`
+	Confirmed    int     `json:"confirmed"`
+	Failed       int     `json:"failed"`
+	Outside      int     `json:"outside,omitempty"`
+	SendTPS      float64 `json:"send_tps"`
+	ChainTPS     float64 `json:"chain_tps"`
+	AvgLatency   string  `json:"avg_latency"` // Go duration string
+	P95Latency   string  `json:"p95_latency"`
+	AvgGasUsed   uint64  `json:"avg_gas_used"`
+	TotalGasUsed uint64  `json:"total_gas_used"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// Deltas holds the percent change of each metric from before to after the activation
+type Deltas struct {
+	SendTPS      float64 `json:"send_tps"`
+	ChainTPS     float64 `json:"chain_tps"`
+	AvgLatency   float64 `json:"avg_latency"`
+	P95Latency   float64 `json:"p95_latency"`
+	AvgGasUsed   float64 `json:"avg_gas_used"`
+	TotalGasUsed float64 `json:"total_gas_used"`
+}
+
+// Summary returns the machine-readable summary of the comparison
+func (r *Result) Summary() *Summary {
+	s := &Summary{
+		ActivationBlock: r.ActivationBlock,
+		Before:          r.Before.summary(),
+		After:           r.After.summary(),
+	}
+	if r.Comparable() {
+		s.Deltas = r.deltas()
+	}
+	return s
+}
+
+// summary returns the machine-readable form of the phase (nil if it never started)
+func (p *Phase) summary() *PhaseSummary {
+	if p == nil {
+		return nil
+	}
+	s := &PhaseSummary{
+		StartBlock:   p.StartBlock,
+		Sent:         p.Sent,
+		Unsent:       p.Unsent,
+		Confirmed:    p.Confirmed,
+		Failed:       p.Failed,
+		Outside:      p.Outside,
+		SendTPS:      p.SendTPS,
+		ChainTPS:     p.ChainTPS,
+		AvgLatency:   p.AvgLatency.String(),
+		P95Latency:   p.P95Latency.String(),
+		AvgGasUsed:   p.AvgGasUsed,
+		TotalGasUsed: p.TotalGasUsed,
+	}
+	if p.Err != nil {
+		s.Error = p.Err.Error()
+	}
+	return s
+}
+
+// deltas returns the percent change of each metric
+func (r *Result) deltas() *Deltas {
+	b, a := r.Before, r.After
+	return &Deltas{
+		SendTPS:      Change(b.SendTPS, a.SendTPS),
+		ChainTPS:     Change(b.ChainTPS, a.ChainTPS),
+		AvgLatency:   Change(float64(b.AvgLatency), float64(a.AvgLatency)),
+		P95Latency:   Change(float64(b.P95Latency), float64(a.P95Latency)),
+		AvgGasUsed:   Change(float64(b.AvgGasUsed), float64(a.AvgGasUsed)),
+		TotalGasUsed: Change(float64(b.TotalGasUsed), float64(a.TotalGasUsed)),
+	}
+}
+
+// PrintTable prints the two phases side by side with the change of each metric
+func PrintTable(result *Result) {
+	console.Summaryf("\nFork Comparison at Block #%d\n\n", result.ActivationBlock)
+	for _, phase := range []*Phase{result.Before, result.After} {
+		if phase != nil && phase.Err != nil {
+			console.Failf("  %s phase failed: %v\n", phase.Name, phase.Err)
+		}
+	}
+	if !result.Comparable() {
+		console.Warnf("  No comparison: both phases must confirm transactions\n")
+		return
+	}
+
+	b, a := result.Before, result.After
+	d := result.deltas()
+	console.Summaryf("  %-15s %14s %14s %10s\n", "Metric", "Before", "After", "Change")
+	row := func(name, before, after, change string) {
+		console.Summaryf("  %-15s %14s %14s %10s\n", name, before, after, change)
+	}
+	count := func(v int) string { return fmt.Sprintf("%d", v) }
+	rate := func(v float64) string { return fmt.Sprintf("%.2f", v) }
+	latency := func(v time.Duration) string { return v.Round(time.Millisecond).String() }
+	pct := func(v float64) string { return fmt.Sprintf("%+.1f%%", v) }
+
+	row("Start block", fmt.Sprintf("#%d", b.StartBlock), fmt.Sprintf("#%d", a.StartBlock), "")
+	row("Sent", count(b.Sent), count(a.Sent), "")
+	row("Confirmed", count(b.Confirmed), count(a.Confirmed), "")
+	row("Failed", count(b.Failed), count(a.Failed), "")
+	row("Send Tx/s", rate(b.SendTPS), rate(a.SendTPS), pct(d.SendTPS))
+	row("Chain TPS", rate(b.ChainTPS), rate(a.ChainTPS), pct(d.ChainTPS))
+	row("Avg Latency", latency(b.AvgLatency), latency(a.AvgLatency), pct(d.AvgLatency))
+	row("P95 Latency", latency(b.P95Latency), latency(a.P95Latency), pct(d.P95Latency))
+	row("Avg Gas Used", fmt.Sprintf("%d", b.AvgGasUsed), fmt.Sprintf("%d", a.AvgGasUsed), pct(d.AvgGasUsed))
+	row("Total Gas Used", fmt.Sprintf("%d", b.TotalGasUsed), fmt.Sprintf("%d", a.TotalGasUsed), pct(d.TotalGasUsed))
+
+	if b.Unsent > 0 {
+		console.Warnf("\n  %d before-phase txs were left unsent at the activation block; the phases sent different loads\n", b.Unsent)
+	}
+	if b.Outside > 0 {
+		console.Warnf("\n  %d before-phase txs were included at or after the activation block; lower the load or raise --lead-blocks\n", b.Outside)
+	}
+}
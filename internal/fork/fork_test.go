@@ -0,0 +1,70 @@
+package fork
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error without an activation block")
+	}
+
+	cfg.ActivationBlock = 1000
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+	if got := cfg.StartBlock(); got != 970 {
+		t.Errorf("StartBlock() = %d, want 970", got)
+	}
+
+	cfg.LeadBlocks = 1000
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for lead-blocks reaching back to genesis")
+	}
+}
+
+func TestResult_Summary(t *testing.T) {
+	result := &Result{
+		ActivationBlock: 1000,
+		Before: &Phase{
+			Name: "before", StartBlock: 970, Sent: 100, Confirmed: 100, SendTPS: 200, ChainTPS: 100,
+			AvgLatency: 2 * time.Second, P95Latency: 4 * time.Second, AvgGasUsed: 21000, TotalGasUsed: 2100000,
+		},
+		After: &Phase{
+			Name: "after", StartBlock: 1002, Sent: 100, Confirmed: 100, SendTPS: 200, ChainTPS: 125,
+			AvgLatency: time.Second, P95Latency: 3 * time.Second, AvgGasUsed: 23100, TotalGasUsed: 2310000,
+		},
+	}
+
+	s := result.Summary()
+	if s.Deltas == nil {
+		t.Fatal("Summary() should include deltas when both phases confirmed txs")
+	}
+	if s.Deltas.ChainTPS != 25 || s.Deltas.AvgLatency != -50 || s.Deltas.SendTPS != 0 {
+		t.Errorf("deltas = %+v", s.Deltas)
+	}
+	if d := s.Deltas.AvgGasUsed; d < 9.99 || d > 10.01 {
+		t.Errorf("AvgGasUsed delta = %v, want 10", d)
+	}
+	if s.Before.AvgLatency != "2s" || s.After.StartBlock != 1002 {
+		t.Errorf("phases = %+v, %+v", s.Before, s.After)
+	}
+
+	result.After.Err = errors.New("build failed")
+	s = result.Summary()
+	if s.Deltas != nil || s.After.Error != "build failed" {
+		t.Errorf("Summary() of a failed phase = %+v", s)
+	}
+}
+
+func TestChange(t *testing.T) {
+	if got := Change(0, 10); got != 0 {
+		t.Errorf("Change(0, 10) = %v, want 0", got)
+	}
+	if got := Change(200, 150); got != -25 {
+		t.Errorf("Change(200, 150) = %v, want -25", got)
+	}
+}
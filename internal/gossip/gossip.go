@@ -0,0 +1,406 @@
+// Package gossip sends transactions to a node over devp2p instead of JSON-RPC.
+//
+// The sender connects as an eth/68 peer and pushes signed transactions in
+// Transactions messages, the way nodes gossip them to each other. It is
+// experimental: the node must accept inbound peers, and since the protocol
+// has no per-transaction reply, inclusion is only known from receipts.
+package gossip
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/rlpx"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/progress"
+	"github.com/0xmhha/txhammer/internal/util/units"
+)
+
+// Gate pauses sending, e.g. while the chain is halted
+type Gate interface {
+	// Wait blocks until sending may proceed
+	Wait(ctx context.Context) error
+}
+
+// Callbacks are invoked as transactions are sent
+type Callbacks struct {
+	// OnSent is called for each transaction once its message is written
+	OnSent func(tx *txbuilder.SignedTx, sentAt time.Time)
+}
+
+// Sender is a devp2p connection to one node
+type Sender struct {
+	config    *Config
+	conn      *rlpx.Conn
+	peerName  string
+	gate      Gate
+	callbacks *Callbacks
+
+	writeMu sync.Mutex
+
+	// Set by the read loop when the connection fails or the node disconnects
+	errMu   sync.Mutex
+	readErr error
+	done    chan struct{}
+}
+
+// Dial connects to the node at config.Enode and completes the devp2p and
+// eth/68 handshakes. The eth status is mirrored from the node, so no chain
+// configuration is needed to pass its fork ID check.
+func Dial(ctx context.Context, config *Config) (*Sender, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	node, err := enode.Parse(enode.ValidSchemes, config.Enode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enode URL: %w", err)
+	}
+	if node.Pubkey() == nil || node.TCP() == 0 {
+		return nil, fmt.Errorf("enode URL must include a public key and TCP port")
+	}
+
+	dialer := net.Dialer{Timeout: config.DialTimeout}
+	fd, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(node.IP().String(), strconv.Itoa(node.TCP())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", node.URLv4(), err)
+	}
+
+	s := &Sender{
+		config: config,
+		conn:   rlpx.NewConn(fd, node.Pubkey()),
+		done:   make(chan struct{}),
+	}
+	if err := s.handshake(); err != nil {
+		s.conn.Close()
+		return nil, err
+	}
+
+	go s.readLoop()
+	return s, nil
+}
+
+// WithGate sets a gate that pauses sending while closed
+func (s *Sender) WithGate(gate Gate) *Sender {
+	s.gate = gate
+	return s
+}
+
+// WithCallbacks sets callbacks invoked as transactions are sent
+func (s *Sender) WithCallbacks(callbacks *Callbacks) *Sender {
+	s.callbacks = callbacks
+	return s
+}
+
+// PeerName returns the client name the node announced
+func (s *Sender) PeerName() string {
+	return s.peerName
+}
+
+// Close disconnects from the node and waits for the read loop to exit
+func (s *Sender) Close() error {
+	err := s.conn.Close()
+	<-s.done
+	return err
+}
+
+// handshake runs the encryption, hello and status handshakes under one deadline
+func (s *Sender) handshake() error {
+	if err := s.conn.SetDeadline(time.Now().Add(s.config.DialTimeout)); err != nil {
+		return err
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return fmt.Errorf("failed to generate node key: %w", err)
+	}
+	if _, err := s.conn.Handshake(key); err != nil {
+		return fmt.Errorf("encryption handshake failed: %w", err)
+	}
+
+	if err := s.exchangeHello(key); err != nil {
+		return err
+	}
+	if err := s.exchangeStatus(); err != nil {
+		return err
+	}
+	return s.conn.SetDeadline(time.Time{})
+}
+
+// exchangeHello announces eth/68 and checks the node supports it
+func (s *Sender) exchangeHello(key *ecdsa.PrivateKey) error {
+	ours := &hello{
+		Version: baseProtocolVersion,
+		Name:    s.config.ClientName,
+		Caps:    []capability{{Name: "eth", Version: ethVersion}},
+		ID:      crypto.FromECDSAPub(&key.PublicKey)[1:],
+	}
+	if err := s.write(helloMsg, ours); err != nil {
+		return fmt.Errorf("failed to send hello: %w", err)
+	}
+
+	code, data, _, err := s.conn.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read hello: %w", err)
+	}
+	if code == disconnectMsg {
+		return fmt.Errorf("node disconnected during hello: %s", disconnectReason(data))
+	}
+	if code != helloMsg {
+		return fmt.Errorf("expected hello, got message 0x%x", code)
+	}
+	var theirs hello
+	if err := rlp.DecodeBytes(data, &theirs); err != nil {
+		return fmt.Errorf("invalid hello: %w", err)
+	}
+
+	supported := false
+	for _, c := range theirs.Caps {
+		if c.Name == "eth" && c.Version == ethVersion {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("node %q does not support eth/%d", theirs.Name, ethVersion)
+	}
+	s.peerName = theirs.Name
+
+	// Both sides compress everything after the hello from version 5 on
+	s.conn.SetSnappy(theirs.Version >= baseProtocolVersion)
+	return nil
+}
+
+// exchangeStatus reads the node's status and answers with the same chain
+func (s *Sender) exchangeStatus() error {
+	for {
+		code, data, _, err := s.conn.Read()
+		if err != nil {
+			return fmt.Errorf("failed to read status: %w", err)
+		}
+		switch code {
+		case pingMsg:
+			if err := s.write(pongMsg, []any{}); err != nil {
+				return err
+			}
+		case disconnectMsg:
+			return fmt.Errorf("node disconnected during status: %s", disconnectReason(data))
+		case statusMsg:
+			var theirs status
+			if err := rlp.DecodeBytes(data, &theirs); err != nil {
+				return fmt.Errorf("invalid status: %w", err)
+			}
+			if theirs.ProtocolVersion != ethVersion {
+				return fmt.Errorf("node negotiated eth/%d, want eth/%d", theirs.ProtocolVersion, ethVersion)
+			}
+			if err := s.write(statusMsg, &theirs); err != nil {
+				return fmt.Errorf("failed to send status: %w", err)
+			}
+			return nil
+		}
+	}
+}
+
+// readLoop keeps the connection alive: it answers pings and header requests
+// and records why the connection ended
+func (s *Sender) readLoop() {
+	defer close(s.done)
+	for {
+		code, data, _, err := s.conn.Read()
+		if err != nil {
+			s.fail(fmt.Errorf("connection lost: %w", err))
+			return
+		}
+
+		switch code {
+		case pingMsg:
+			err = s.write(pongMsg, []any{})
+		case disconnectMsg:
+			s.fail(fmt.Errorf("node disconnected: %s", disconnectReason(data)))
+			return
+		case getBlockHeadersMsg:
+			// The sender has no chain; an empty response is valid
+			var req getBlockHeaders
+			if rlp.DecodeBytes(data, &req) == nil {
+				err = s.write(blockHeadersMsg, &blockHeaders{RequestID: req.RequestID})
+			}
+		}
+		if err != nil {
+			s.fail(err)
+			return
+		}
+	}
+}
+
+// fail records the first connection error
+func (s *Sender) fail(err error) {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.readErr == nil {
+		s.readErr = err
+	}
+}
+
+// err returns the connection error, if any
+func (s *Sender) err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	return s.readErr
+}
+
+// write encodes and writes one message
+func (s *Sender) write(code uint64, val any) error {
+	payload, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.conn.SetWriteDeadline(time.Now().Add(s.config.DialTimeout)); err != nil {
+		return err
+	}
+	_, err = s.conn.Write(code, payload)
+	return err
+}
+
+// Send pushes txs to the node in Transactions messages as fast as the
+// connection accepts them. When the connection fails, the remaining txs
+// count as failed and the result is returned together with the error.
+func (s *Sender) Send(ctx context.Context, txs []*txbuilder.SignedTx) (*Result, error) {
+	result := &Result{TotalTxs: len(txs)}
+	if len(txs) == 0 {
+		return result, nil
+	}
+
+	console.Printf("\nStarting devp2p Transaction Sending\n\n")
+	console.Printf("Total transactions: %d\n", len(txs))
+	console.Printf("Peer: %s\n", s.peerName)
+	console.Printf("Txs per message: %d\n\n", s.config.BatchSize)
+
+	bar := progress.New(int64(len(txs)), "gossiping txs")
+	startTime := time.Now()
+
+	for start := 0; start < len(txs); {
+		if err := s.wait(ctx); err != nil {
+			result.Error = err
+			break
+		}
+
+		end, entries, size := s.nextMessage(txs, start)
+		if err := s.write(transactionsMsg, entries); err != nil {
+			result.Error = fmt.Errorf("failed to send transactions: %w", err)
+			break
+		}
+
+		sentAt := time.Now()
+		result.Messages++
+		result.TotalBytes += size
+		for _, tx := range txs[start:end] {
+			if s.callbacks != nil && s.callbacks.OnSent != nil {
+				s.callbacks.OnSent(tx, sentAt)
+			}
+		}
+		result.SentCount += end - start
+		progress.Add(bar, end-start)
+		start = end
+	}
+	console.Println()
+
+	result.FailedCount = result.TotalTxs - result.SentCount
+	result.Duration = time.Since(startTime)
+	if secs := result.Duration.Seconds(); secs > 0 {
+		result.TxPerSecond = float64(result.SentCount) / secs
+		result.BytesPerSec = float64(result.TotalBytes) / secs
+	}
+
+	printSummary(result)
+	return result, result.Error
+}
+
+// wait blocks on the gate and reports a dead connection or canceled context
+func (s *Sender) wait(ctx context.Context) error {
+	if s.gate != nil {
+		if err := s.gate.Wait(ctx); err != nil {
+			return fmt.Errorf("send gate error: %w", err)
+		}
+	}
+	if err := s.err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// nextMessage collects the txs from start that fit into one message. Typed
+// txs go on the wire as byte strings, legacy txs as RLP lists.
+func (s *Sender) nextMessage(txs []*txbuilder.SignedTx, start int) (int, []rlp.RawValue, int64) {
+	var entries []rlp.RawValue
+	var size, raw int64
+	end := start
+	for ; end < len(txs) && len(entries) < max(s.config.BatchSize, 1); end++ {
+		entry := rlp.RawValue(txs[end].RawTx)
+		if len(entry) > 0 && entry[0] < 0xc0 {
+			entry, _ = rlp.EncodeToBytes(txs[end].RawTx)
+		}
+		if len(entries) > 0 && size+int64(len(entry)) > int64(s.config.MaxMessageBytes) {
+			break
+		}
+		entries = append(entries, entry)
+		size += int64(len(entry))
+		raw += int64(len(txs[end].RawTx))
+	}
+	return end, entries, raw
+}
+
+// disconnectReason decodes the reason of a disconnect message
+func disconnectReason(data []byte) string {
+	var reasons []uint
+	if err := rlp.DecodeBytes(data, &reasons); err != nil || len(reasons) == 0 {
+		// Some clients send the reason as a bare integer
+		var reason uint
+		if rlp.DecodeBytes(data, &reason) != nil {
+			return "unknown reason"
+		}
+		reasons = []uint{reason}
+	}
+	if text, ok := disconnectReasons[reasons[0]]; ok {
+		return text
+	}
+	return "reason " + strconv.FormatUint(uint64(reasons[0]), 10)
+}
+
+// disconnectReasons follows the devp2p specification
+var disconnectReasons = map[uint]string{
+	0x00: "disconnect requested",
+	0x01: "network error",
+	0x02: "breach of protocol",
+	0x03: "useless peer",
+	0x04: "too many peers",
+	0x05: "already connected",
+	0x06: "incompatible p2p protocol version",
+	0x07: "invalid node identity",
+	0x08: "client quitting",
+	0x09: "unexpected identity",
+	0x0a: "connected to self",
+	0x0b: "read timeout",
+	0x10: "subprotocol error",
+}
+
+// printSummary prints the devp2p send summary
+func printSummary(result *Result) {
+	console.Printf("\ndevp2p Summary\n\n")
+	console.Printf("Total transactions: %d\n", result.TotalTxs)
+	console.Printf("Sent: %d in %d messages\n", result.SentCount, result.Messages)
+	console.Printf("Total duration: %s\n", result.Duration)
+	console.Printf("Actual throughput: %.2f tx/s\n", result.TxPerSecond)
+	console.Printf("Bandwidth: %s sent (%s/s)\n", units.FormatBytes(float64(result.TotalBytes)), units.FormatBytes(result.BytesPerSec))
+	if result.FailedCount > 0 {
+		console.Warnf("\n%d transaction(s) not sent: %v\n", result.FailedCount, result.Error)
+	}
+}
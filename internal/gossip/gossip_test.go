@@ -0,0 +1,245 @@
+package gossip
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/rlpx"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+)
+
+// fakeNode accepts one devp2p connection and plays the node side of the handshakes
+type fakeNode struct {
+	key      *ecdsa.PrivateKey
+	listener net.Listener
+	status   *status
+}
+
+func newFakeNode(t *testing.T) *fakeNode {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return &fakeNode{
+		key:      key,
+		listener: listener,
+		status: &status{
+			ProtocolVersion: ethVersion,
+			NetworkID:       1337,
+			TD:              big.NewInt(1),
+			Head:            common.HexToHash("0x01"),
+			Genesis:         common.HexToHash("0x02"),
+			ForkID:          forkid.ID{Hash: [4]byte{1, 2, 3, 4}},
+		},
+	}
+}
+
+func (n *fakeNode) config() *Config {
+	addr := n.listener.Addr().(*net.TCPAddr)
+	cfg := DefaultConfig()
+	cfg.Enode = enode.NewV4(&n.key.PublicKey, addr.IP, addr.Port, 0).URLv4()
+	cfg.DialTimeout = 5 * time.Second
+	return cfg
+}
+
+// accept runs the node side of the handshakes, failing the test on any mismatch
+func (n *fakeNode) accept(t *testing.T, caps []capability) *rlpx.Conn {
+	t.Helper()
+	fd, err := n.listener.Accept()
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	conn := rlpx.NewConn(fd, nil)
+	if _, err := conn.Handshake(n.key); err != nil {
+		t.Error(err)
+		return nil
+	}
+
+	code, _, _, err := conn.Read()
+	if err != nil || code != helloMsg {
+		t.Errorf("read hello: code %d, err %v", code, err)
+		return nil
+	}
+	writeMsg(t, conn, helloMsg, &hello{
+		Version: baseProtocolVersion,
+		Name:    "fake/v1",
+		Caps:    caps,
+		ID:      crypto.FromECDSAPub(&n.key.PublicKey)[1:],
+	})
+	conn.SetSnappy(true)
+	return conn
+}
+
+func writeMsg(t *testing.T, conn *rlpx.Conn, code uint64, val any) {
+	t.Helper()
+	payload, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write(code, payload); err != nil {
+		t.Error(err)
+	}
+}
+
+func signedTxs(t *testing.T, n int) []*txbuilder.SignedTx {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := types.NewLondonSigner(big.NewInt(1337))
+	to := common.HexToAddress("0x1234")
+
+	txs := make([]*txbuilder.SignedTx, n)
+	for i := range txs {
+		// Alternate legacy and typed txs, which are encoded differently on the wire
+		var inner types.TxData = &types.LegacyTx{Nonce: uint64(i), GasPrice: big.NewInt(1), Gas: 21000, To: &to}
+		if i%2 == 1 {
+			inner = &types.DynamicFeeTx{ChainID: big.NewInt(1337), Nonce: uint64(i), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &to}
+		}
+		tx, err := types.SignTx(types.NewTx(inner), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs[i] = &txbuilder.SignedTx{Tx: tx, RawTx: raw, Hash: tx.Hash(), Nonce: uint64(i)}
+	}
+	return txs
+}
+
+func TestSender_Send(t *testing.T) {
+	node := newFakeNode(t)
+	txs := signedTxs(t, 7)
+
+	received := make(chan []common.Hash, 1)
+	go func() {
+		conn := node.accept(t, []capability{{Name: "eth", Version: 67}, {Name: "eth", Version: ethVersion}})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		writeMsg(t, conn, statusMsg, node.status)
+
+		var hashes []common.Hash
+		for len(hashes) < len(txs) {
+			code, data, _, err := conn.Read()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			switch code {
+			case statusMsg:
+				var got status
+				if err := rlp.DecodeBytes(data, &got); err != nil || got.ForkID != node.status.ForkID || got.Genesis != node.status.Genesis {
+					t.Errorf("status = %+v (err %v), want the node's own", got, err)
+				}
+				// The sender must keep answering pings while it sends
+				writeMsg(t, conn, pingMsg, []any{})
+			case transactionsMsg:
+				var batch types.Transactions
+				if err := rlp.DecodeBytes(data, &batch); err != nil {
+					t.Errorf("decode transactions: %v", err)
+					return
+				}
+				for _, tx := range batch {
+					hashes = append(hashes, tx.Hash())
+				}
+			}
+		}
+		received <- hashes
+	}()
+
+	cfg := node.config()
+	cfg.BatchSize = 3
+	sender, err := Dial(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer sender.Close()
+	if sender.PeerName() != "fake/v1" {
+		t.Errorf("PeerName() = %q, want fake/v1", sender.PeerName())
+	}
+
+	var sent int
+	result, err := sender.WithCallbacks(&Callbacks{OnSent: func(*txbuilder.SignedTx, time.Time) { sent++ }}).
+		Send(context.Background(), txs)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if result.SentCount != 7 || result.FailedCount != 0 || result.Messages != 3 || sent != 7 {
+		t.Errorf("sent %d, failed %d in %d messages, %d callbacks; want 7, 0, 3, 7",
+			result.SentCount, result.FailedCount, result.Messages, sent)
+	}
+
+	select {
+	case hashes := <-received:
+		for i, tx := range txs {
+			if hashes[i] != tx.Hash {
+				t.Errorf("tx %d hash = %s, want %s", i, hashes[i].Hex(), tx.Hash.Hex())
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("node did not receive all transactions")
+	}
+}
+
+func TestDial_NoEth68(t *testing.T) {
+	node := newFakeNode(t)
+	go func() {
+		if conn := node.accept(t, []capability{{Name: "eth", Version: 69}}); conn != nil {
+			conn.Close()
+		}
+	}()
+
+	_, err := Dial(context.Background(), node.config())
+	if err == nil || !strings.Contains(err.Error(), "does not support eth/68") {
+		t.Errorf("Dial() error = %v, want eth/68 unsupported", err)
+	}
+}
+
+func TestDial_Disconnected(t *testing.T) {
+	node := newFakeNode(t)
+	go func() {
+		conn := node.accept(t, []capability{{Name: "eth", Version: ethVersion}})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		writeMsg(t, conn, disconnectMsg, []uint{0x04})
+	}()
+
+	_, err := Dial(context.Background(), node.config())
+	if err == nil || !strings.Contains(err.Error(), "too many peers") {
+		t.Errorf("Dial() error = %v, want too many peers", err)
+	}
+}
+
+func TestDial_InvalidEnode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Enode = "http://localhost:8545"
+	if _, err := Dial(context.Background(), cfg); err == nil {
+		t.Error("Dial() with an RPC URL should fail")
+	}
+}
@@ -0,0 +1,112 @@
+package gossip
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Base devp2p protocol messages
+const (
+	baseProtocolVersion = 5
+	baseProtocolLength  = 16
+
+	helloMsg      = 0x00
+	disconnectMsg = 0x01
+	pingMsg       = 0x02
+	pongMsg       = 0x03
+)
+
+// eth/68 messages, offset by the base protocol length on the wire
+const (
+	ethVersion = 68
+
+	statusMsg          = baseProtocolLength + 0x00
+	transactionsMsg    = baseProtocolLength + 0x02
+	getBlockHeadersMsg = baseProtocolLength + 0x03
+	blockHeadersMsg    = baseProtocolLength + 0x04
+)
+
+// Config holds configuration for the devp2p sender
+type Config struct {
+	// Enode URL of the node to connect to (enode://pubkey@host:port)
+	Enode string
+
+	// Transactions per Transactions message
+	BatchSize int
+
+	// Upper bound on the encoded size of one Transactions message
+	MaxMessageBytes int
+
+	// Timeout for the TCP dial and the protocol handshakes
+	DialTimeout time.Duration
+
+	// Client name announced in the devp2p hello
+	ClientName string
+}
+
+// DefaultConfig returns default devp2p sender configuration
+func DefaultConfig() *Config {
+	return &Config{
+		BatchSize:       100,
+		MaxMessageBytes: 1024 * 1024, // Well below the 10 MB devp2p message limit
+		DialTimeout:     10 * time.Second,
+		ClientName:      "txhammer",
+	}
+}
+
+// Result holds the outcome of sending over devp2p. The Transactions message
+// has no reply, so a tx counts as sent once its message is written.
+type Result struct {
+	TotalTxs    int
+	SentCount   int
+	FailedCount int
+	Messages    int
+	TotalBytes  int64
+	Duration    time.Duration
+	TxPerSecond float64
+	BytesPerSec float64
+	Error       error // Connection error that stopped sending, if any
+}
+
+// capability is a devp2p capability announced in the hello
+type capability struct {
+	Name    string
+	Version uint
+}
+
+// hello is the devp2p protocol handshake
+type hello struct {
+	Version    uint64
+	Name       string
+	Caps       []capability
+	ListenPort uint64
+	ID         []byte // secp256k1 public key without the 0x04 prefix
+
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
+// status is the eth/68 handshake
+type status struct {
+	ProtocolVersion uint32
+	NetworkID       uint64
+	TD              *big.Int
+	Head            common.Hash
+	Genesis         common.Hash
+	ForkID          forkid.ID
+}
+
+// getBlockHeaders is the eth/68 header request, answered with no headers
+type getBlockHeaders struct {
+	RequestID uint64
+	Request   rlp.RawValue
+}
+
+// blockHeaders is the eth/68 header response
+type blockHeaders struct {
+	RequestID uint64
+	Headers   []rlp.RawValue
+}
@@ -0,0 +1,258 @@
+package growth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/time/rate"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Runner creates accounts at a growing rate, funds each from the master
+// account and has it send a few transactions before retiring it
+type Runner struct {
+	client Client
+	config *Config
+
+	// Master account funding state; funding txs are sent in nonce order
+	master      txbuilder.Signer
+	masterNonce uint64
+	masterMu    sync.Mutex
+
+	// Counters
+	created     atomic.Int64
+	funded      atomic.Int64
+	fundFailed  atomic.Int64
+	retired     atomic.Int64
+	txsSent     atomic.Int64
+	txsFailed   atomic.Int64
+	fundLatency atomic.Int64 // Total funding latency (ns)
+}
+
+// New creates a new Runner instance
+func New(client Client, config *Config) *Runner {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Runner{
+		client: client,
+		config: config,
+	}
+}
+
+// FundingAmount returns the wei given to each new account: exactly enough
+// gas for its transactions at the configured gas price
+func (r *Runner) FundingAmount() *big.Int {
+	gas := new(big.Int).SetUint64(r.config.GasLimit * uint64(r.config.TxsPerAccount))
+	return gas.Mul(gas, r.config.GasPrice)
+}
+
+// Run creates accounts until Duration elapses or ctx is canceled, then waits
+// for the accounts in flight to retire. Either way of stopping is normal.
+func (r *Runner) Run(ctx context.Context, master txbuilder.Signer) (*Result, error) {
+	if r.config.Rate <= 0 {
+		return nil, fmt.Errorf("account rate must be positive")
+	}
+	if r.config.TxsPerAccount <= 0 {
+		return nil, fmt.Errorf("transactions per account must be positive")
+	}
+
+	nonce, err := r.client.PendingNonceAt(ctx, master.Address())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master nonce: %w", err)
+	}
+	r.master = master
+	r.masterNonce = nonce
+
+	runCtx := ctx
+	if r.config.Duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, r.config.Duration)
+		defer cancel()
+	}
+
+	start := time.Now()
+	limiter := rate.NewLimiter(rate.Limit(r.config.Rate), 1)
+	currentRate := r.config.Rate
+
+	// Accounts in flight finish on ctx, not runCtx, so they can retire after creation stops
+	var wg sync.WaitGroup
+	for {
+		if err := limiter.Wait(runCtx); err != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.grow(ctx)
+		}()
+
+		currentRate = r.rateAt(time.Since(start))
+		limiter.SetLimit(rate.Limit(currentRate))
+	}
+	creation := time.Since(start)
+	wg.Wait()
+
+	result := &Result{
+		Created:    int(r.created.Load()),
+		Funded:     int(r.funded.Load()),
+		FundFailed: int(r.fundFailed.Load()),
+		Retired:    int(r.retired.Load()),
+		TxsSent:    int(r.txsSent.Load()),
+		TxsFailed:  int(r.txsFailed.Load()),
+		FinalRate:  currentRate,
+		Duration:   time.Since(start),
+	}
+	if creation > 0 {
+		result.AvgRate = float64(result.Created) / creation.Seconds()
+	}
+	if result.Funded > 0 {
+		result.AvgFundLatency = time.Duration(r.fundLatency.Load() / int64(result.Funded))
+	}
+	return result, nil
+}
+
+// rateAt returns the account rate after elapsed time, compounding Acceleration per minute
+func (r *Runner) rateAt(elapsed time.Duration) float64 {
+	return r.config.Rate * math.Pow(1+r.config.Acceleration/100, elapsed.Minutes())
+}
+
+// grow runs the life of one account: create, fund, send, retire
+func (r *Runner) grow(ctx context.Context) {
+	r.created.Add(1)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		r.fundFailed.Add(1)
+		return
+	}
+
+	if err := r.fund(ctx, crypto.PubkeyToAddress(key.PublicKey)); err != nil {
+		r.fundFailed.Add(1)
+		return
+	}
+	r.funded.Add(1)
+
+	failed := false
+	for nonce := uint64(0); nonce < uint64(r.config.TxsPerAccount); nonce++ {
+		if err := r.send(ctx, key, nonce); err != nil {
+			// Later nonces cannot be mined after a gap
+			r.txsFailed.Add(int64(uint64(r.config.TxsPerAccount) - nonce))
+			failed = true
+			break
+		}
+		r.txsSent.Add(1)
+	}
+	if !failed {
+		r.retired.Add(1)
+	}
+}
+
+// fund sends FundingAmount from the master account and waits for it to be mined
+func (r *Runner) fund(ctx context.Context, to common.Address) error {
+	r.masterMu.Lock()
+	raw, hash, err := r.sign(r.master, r.masterNonce, to, r.FundingAmount())
+	if err == nil {
+		_, err = r.client.SendRawTransaction(ctx, raw)
+	}
+	if err != nil {
+		r.masterMu.Unlock()
+		return fmt.Errorf("failed to send funding: %w", err)
+	}
+	r.masterNonce++
+	r.masterMu.Unlock()
+
+	sentAt := time.Now()
+	if err := r.waitMined(ctx, hash); err != nil {
+		return err
+	}
+	r.fundLatency.Add(int64(time.Since(sentAt)))
+	return nil
+}
+
+// send sends a zero-value self-transfer from a funded account
+func (r *Runner) send(ctx context.Context, key *ecdsa.PrivateKey, nonce uint64) error {
+	signer := txbuilder.NewKeySigner(key)
+	raw, _, err := r.sign(signer, nonce, signer.Address(), big.NewInt(0))
+	if err != nil {
+		return err
+	}
+	_, err = r.client.SendRawTransaction(ctx, raw)
+	return err
+}
+
+// sign builds and signs a legacy transfer
+func (r *Runner) sign(signer txbuilder.Signer, nonce uint64, to common.Address, value *big.Int) ([]byte, common.Hash, error) {
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: r.config.GasPrice,
+		Gas:      r.config.GasLimit,
+		To:       &to,
+		Value:    value,
+	})
+	signedTx, err := txbuilder.SignTransaction(tx, r.config.ChainID, signer)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	return raw, signedTx.Hash(), nil
+}
+
+// waitMined polls for a successful receipt until FundTimeout elapses
+func (r *Runner) waitMined(ctx context.Context, hash common.Hash) error {
+	waitCtx, cancel := context.WithTimeout(ctx, r.config.FundTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := r.client.TransactionReceipt(waitCtx, hash)
+		if err == nil && receipt != nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("funding tx %s reverted", hash.Hex())
+			}
+			return nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("funding tx %s not mined: %w", hash.Hex(), waitCtx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// PrintResult prints the account growth summary
+func PrintResult(result *Result) {
+	console.Summaryf("\nAccount Growth Results\n\n")
+	console.Summaryf("  Duration:          %s\n", result.Duration)
+	console.Summaryf("  Accounts created:  %d\n", result.Created)
+	console.Summaryf("  Accounts funded:   %d\n", result.Funded)
+	console.Summaryf("  Accounts retired:  %d\n", result.Retired)
+	console.Summaryf("  Transactions sent: %d\n", result.TxsSent)
+	if result.TxsFailed > 0 {
+		console.Summaryf("  Transactions failed: %d\n", result.TxsFailed)
+	}
+	console.Summaryf("  Account rate:      %.2f/s average, %.2f/s at the end\n", result.AvgRate, result.FinalRate)
+	if result.Funded > 0 {
+		console.Summaryf("  Avg funding time:  %s\n", result.AvgFundLatency)
+	}
+
+	if result.FundFailed > 0 {
+		console.Warnf("\n%d account(s) could not be funded\n", result.FundFailed)
+	}
+}
@@ -0,0 +1,162 @@
+package growth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+)
+
+// mockClient mines every transaction immediately and records the funding sent to each account
+type mockClient struct {
+	mu         sync.Mutex
+	nonces     map[common.Address]uint64
+	mined      map[common.Hash]bool
+	funding    map[common.Address]*big.Int
+	sent       map[common.Address]int
+	rejectFrom common.Address
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{
+		nonces:  make(map[common.Address]uint64),
+		mined:   make(map[common.Hash]bool),
+		funding: make(map[common.Address]*big.Int),
+		sent:    make(map[common.Address]int),
+	}
+}
+
+func (m *mockClient) SendRawTransaction(_ context.Context, rawTx []byte) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return common.Hash{}, err
+	}
+	from, err := types.Sender(types.NewLondonSigner(tx.ChainId()), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if from == m.rejectFrom {
+		return common.Hash{}, errors.New("insufficient funds")
+	}
+	if tx.Nonce() != m.nonces[from] {
+		return common.Hash{}, errors.New("nonce gap")
+	}
+	m.nonces[from]++
+	m.mined[tx.Hash()] = true
+	m.sent[from]++
+	if *tx.To() != from {
+		m.funding[*tx.To()] = tx.Value()
+	}
+	return tx.Hash(), nil
+}
+
+func (m *mockClient) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nonces[account], nil
+}
+
+func (m *mockClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.mined[txHash] {
+		return nil, errors.New("not found")
+	}
+	return &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusSuccessful}, nil
+}
+
+func testConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.ChainID = big.NewInt(1337)
+	cfg.GasPrice = big.NewInt(10)
+	cfg.Rate = 100
+	cfg.TxsPerAccount = 2
+	cfg.Duration = 100 * time.Millisecond
+	cfg.PollInterval = 5 * time.Millisecond
+	return cfg
+}
+
+func TestRunner_Run(t *testing.T) {
+	master, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	masterAddr := crypto.PubkeyToAddress(master.PublicKey)
+	client := newMockClient()
+	client.nonces[masterAddr] = 7
+
+	runner := New(client, testConfig())
+	result, err := runner.Run(context.Background(), txbuilder.NewKeySigner(master))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if result.Created == 0 {
+		t.Fatal("no accounts were created")
+	}
+	if result.Funded != result.Created || result.Retired != result.Created {
+		t.Errorf("created/funded/retired = %d/%d/%d, want all equal", result.Created, result.Funded, result.Retired)
+	}
+	if result.TxsSent != 2*result.Retired || result.TxsFailed != 0 {
+		t.Errorf("TxsSent = %d, TxsFailed = %d, want %d and 0", result.TxsSent, result.TxsFailed, 2*result.Retired)
+	}
+
+	// Funding comes from consecutive master nonces and covers exactly the account's gas
+	if got := client.nonces[masterAddr]; got != 7+uint64(result.Created) {
+		t.Errorf("master nonce = %d, want %d", got, 7+result.Created)
+	}
+	want := big.NewInt(2 * 21000 * 10)
+	for addr, amount := range client.funding {
+		if amount.Cmp(want) != 0 {
+			t.Errorf("funding of %s = %s, want %s", addr.Hex(), amount, want)
+		}
+		if client.sent[addr] != 2 {
+			t.Errorf("%s sent %d txs, want 2", addr.Hex(), client.sent[addr])
+		}
+	}
+}
+
+func TestRunner_Run_FundingRejected(t *testing.T) {
+	master, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newMockClient()
+	client.rejectFrom = crypto.PubkeyToAddress(master.PublicKey)
+
+	result, err := New(client, testConfig()).Run(context.Background(), txbuilder.NewKeySigner(master))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Created == 0 || result.FundFailed != result.Created {
+		t.Errorf("created = %d, fund failed = %d, want all failed", result.Created, result.FundFailed)
+	}
+	if result.TxsSent != 0 {
+		t.Errorf("TxsSent = %d, want 0", result.TxsSent)
+	}
+}
+
+func TestRunner_RateAt(t *testing.T) {
+	cfg := testConfig()
+	cfg.Rate = 2
+	cfg.Acceleration = 50
+
+	runner := New(newMockClient(), cfg)
+	if got := runner.rateAt(0); got != 2 {
+		t.Errorf("rateAt(0) = %v, want 2", got)
+	}
+	if got := runner.rateAt(2 * time.Minute); got != 4.5 {
+		t.Errorf("rateAt(2m) = %v, want 4.5", got)
+	}
+}
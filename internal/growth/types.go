@@ -0,0 +1,58 @@
+package growth
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Client defines the interface for funding fresh accounts and sending from them
+type Client interface {
+	// SendRawTransaction sends a signed, encoded transaction
+	SendRawTransaction(ctx context.Context, rawTx []byte) (common.Hash, error)
+	// PendingNonceAt returns the next nonce of an account including pending txs
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	// TransactionReceipt returns the receipt of a mined transaction
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Config holds configuration for account growth testing
+type Config struct {
+	ChainID       *big.Int
+	GasPrice      *big.Int
+	GasLimit      uint64
+	Rate          float64       // New accounts per second at the start
+	Acceleration  float64       // Percent the account rate grows per minute (0 = constant)
+	TxsPerAccount int           // Transactions each account sends before retiring
+	Duration      time.Duration // How long new accounts are created (0 = until canceled)
+	FundTimeout   time.Duration // How long to wait for an account's funding tx to be mined
+	PollInterval  time.Duration // How often to poll for the funding receipt
+}
+
+// DefaultConfig returns default account growth configuration
+func DefaultConfig() *Config {
+	return &Config{
+		GasLimit:      21000,
+		Rate:          1,
+		TxsPerAccount: 3,
+		FundTimeout:   60 * time.Second,
+		PollInterval:  500 * time.Millisecond,
+	}
+}
+
+// Result holds the outcome of an account growth run
+type Result struct {
+	Created        int           // Accounts generated
+	Funded         int           // Accounts whose funding tx was mined
+	FundFailed     int           // Accounts whose funding tx was rejected, reverted or timed out
+	Retired        int           // Funded accounts that sent all their transactions
+	TxsSent        int           // Transactions accepted from funded accounts
+	TxsFailed      int           // Transactions rejected from funded accounts
+	AvgFundLatency time.Duration // Mean time from funding tx sent to its receipt
+	AvgRate        float64       // Accounts created per second while creating
+	FinalRate      float64       // Account rate when creation stopped (accounts/s)
+	Duration       time.Duration // Including the wait for accounts in flight to retire
+}
@@ -284,5 +284,5 @@ func TestFullPipeline(t *testing.T) {
 
 	t.Logf("Full pipeline completed in %s", result.Duration)
 	t.Logf("Total Transactions: %d", result.TotalTransactions)
-	t.Logf("TPS: %.2f", result.TPS)
+	t.Logf("Chain TPS: %.2f", result.ChainTPS)
 }
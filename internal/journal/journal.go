@@ -0,0 +1,182 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xmhha/txhammer/internal/util/compress"
+)
+
+// Version is the journal format written by this build
+const Version = 1
+
+// Header starts the records of one run. A journal file may hold several
+// runs, each appended after the previous one.
+type Header struct {
+	Journal   int       `json:"journal"` // Format version; always set on header lines
+	ChainID   uint64    `json:"chain_id"`
+	Mode      string    `json:"mode,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Entry is one transaction the node acknowledged
+type Entry struct {
+	Hash     common.Hash    `json:"hash"`
+	From     common.Address `json:"from"`
+	Nonce    uint64         `json:"nonce"`
+	GasLimit uint64         `json:"gas_limit,omitempty"`
+	SentAt   time.Time      `json:"sent_at"`
+}
+
+// Journal is the content of a journal file
+type Journal struct {
+	Headers   []Header
+	Entries   []Entry
+	Truncated bool // The last line was cut off, e.g. by a crash mid-write
+}
+
+// Writer appends acknowledged transactions to a journal file. Each record is
+// written with its own write call as soon as it is appended, so records
+// survive a crash of the process; Close syncs the file to disk.
+type Writer struct {
+	mu   sync.Mutex
+	file *os.File
+	path string
+}
+
+// Create opens the journal at path for appending, creating it and its parent
+// directories if needed, and writes the header of a new run
+func Create(path string, header Header) (*Writer, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create journal directory: %w", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	w := &Writer{file: file, path: path}
+	header.Journal = Version
+	if err := w.write(header); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Path returns the journal file path
+func (w *Writer) Path() string {
+	return w.path
+}
+
+// Append records one acknowledged transaction; it is safe for concurrent use
+func (w *Writer) Append(entry Entry) error {
+	return w.write(entry)
+}
+
+func (w *Writer) write(record any) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal record: %w", err)
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// Close syncs and closes the journal file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	syncErr := w.file.Sync()
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close journal: %w", err)
+	}
+	if syncErr != nil {
+		return fmt.Errorf("failed to sync journal: %w", syncErr)
+	}
+	return nil
+}
+
+// record is a journal line, either a header or an entry
+type record struct {
+	Header
+	Entry
+}
+
+// Load reads a journal file, which may be zstd or gzip compressed. A cut-off
+// last line is skipped and reported in Truncated; any other malformed line is
+// an error.
+func Load(path string) (*Journal, error) {
+	file, err := compress.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	j := &Journal{}
+	reader := bufio.NewReader(file)
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			complete := line[len(line)-1] == '\n'
+			var rec record
+			if jsonErr := json.Unmarshal(line, &rec); jsonErr != nil {
+				if !complete {
+					j.Truncated = true
+					break
+				}
+				return nil, fmt.Errorf("journal line %d: %w", lineNo, jsonErr)
+			}
+			if rec.Journal > 0 {
+				if rec.Journal > Version {
+					return nil, fmt.Errorf("journal line %d: unsupported format version %d", lineNo, rec.Journal)
+				}
+				j.Headers = append(j.Headers, rec.Header)
+			} else {
+				j.Entries = append(j.Entries, rec.Entry)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read journal: %w", err)
+		}
+	}
+	if len(j.Headers) == 0 && len(j.Entries) > 0 {
+		return nil, errors.New("journal has entries but no header")
+	}
+	return j, nil
+}
+
+// ChainID returns the chain the journal was written on, or an error if its
+// runs span several chains
+func (j *Journal) ChainID() (uint64, error) {
+	if len(j.Headers) == 0 {
+		return 0, errors.New("journal is empty")
+	}
+	chainID := j.Headers[0].ChainID
+	for _, h := range j.Headers[1:] {
+		if h.ChainID != chainID {
+			return 0, fmt.Errorf("journal mixes chain IDs %d and %d", chainID, h.ChainID)
+		}
+	}
+	return chainID, nil
+}
@@ -0,0 +1,229 @@
+package journal
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/util/compress"
+)
+
+func testEntry(i int) Entry {
+	return Entry{
+		Hash:     common.BigToHash(big.NewInt(int64(i + 1))),
+		From:     common.BigToAddress(big.NewInt(int64(i%2 + 1))),
+		Nonce:    uint64(i / 2),
+		GasLimit: 21000,
+		SentAt:   time.Unix(1700000000, 0).UTC(),
+	}
+}
+
+func TestWriterLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs", "journal.jsonl")
+
+	w, err := Create(path, Header{ChainID: 1337, Mode: "TRANSFER", StartedAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.Append(testEntry(i)); err != nil {
+				t.Errorf("Append() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// A second run appends to the same file
+	w, err = Create(path, Header{ChainID: 1337, Mode: "SWAP", StartedAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := w.Append(testEntry(20)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(j.Headers) != 2 || j.Headers[0].Journal != Version || j.Headers[1].Mode != "SWAP" {
+		t.Errorf("headers = %+v, want two runs", j.Headers)
+	}
+	if len(j.Entries) != 21 || j.Truncated {
+		t.Fatalf("loaded %d entries (truncated %v), want 21", len(j.Entries), j.Truncated)
+	}
+	seen := make(map[common.Hash]bool)
+	for _, e := range j.Entries {
+		seen[e.Hash] = true
+	}
+	if last := j.Entries[20]; last != testEntry(20) {
+		t.Errorf("last entry = %+v, want %+v", last, testEntry(20))
+	}
+	if len(seen) != 21 {
+		t.Errorf("loaded %d distinct hashes, want 21", len(seen))
+	}
+	if chainID, err := j.ChainID(); err != nil || chainID != 1337 {
+		t.Errorf("ChainID() = %d, %v", chainID, err)
+	}
+}
+
+func TestLoad_Truncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	w, err := Create(path, Header{ChainID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Append(testEntry(0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash mid-write leaves half a line
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"hash":"0xab`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	j, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !j.Truncated || len(j.Entries) != 1 {
+		t.Errorf("Truncated = %v with %d entries, want true with 1", j.Truncated, len(j.Entries))
+	}
+
+	// A malformed line in the middle is an error
+	if err := os.WriteFile(path, []byte("{\"journal\":1}\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for a malformed line")
+	}
+}
+
+func TestJournal_ChainIDMismatch(t *testing.T) {
+	j := &Journal{Headers: []Header{{ChainID: 1}, {ChainID: 2}}}
+	if _, err := j.ChainID(); err == nil {
+		t.Error("expected error for mixed chain IDs")
+	}
+}
+
+type mockClient struct {
+	receipts map[common.Hash]*types.Receipt
+	pending  map[common.Hash]bool
+	nonces   map[common.Address]uint64
+}
+
+func (m *mockClient) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	return m.receipts[hash], nil
+}
+
+func (m *mockClient) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if m.pending[hash] {
+		return types.NewTx(&types.LegacyTx{}), true, nil
+	}
+	return nil, false, nil
+}
+
+func (m *mockClient) NonceAt(_ context.Context, account common.Address, _ *big.Int) (uint64, error) {
+	return m.nonces[account], nil
+}
+
+func TestReconcile(t *testing.T) {
+	entries := []Entry{testEntry(0), testEntry(1), testEntry(2), testEntry(3), testEntry(4)}
+	// Sender 1 sent nonces 0, 1 and 2; sender 2 sent nonces 0 and 1
+	client := &mockClient{
+		receipts: map[common.Hash]*types.Receipt{
+			entries[0].Hash: {Status: types.ReceiptStatusSuccessful, GasUsed: 21000, EffectiveGasPrice: big.NewInt(2), BlockNumber: big.NewInt(5)},
+			entries[1].Hash: {Status: types.ReceiptStatusFailed, GasUsed: 30000, EffectiveGasPrice: big.NewInt(2), BlockNumber: big.NewInt(5)},
+		},
+		pending: map[common.Hash]bool{entries[3].Hash: true},
+		nonces: map[common.Address]uint64{
+			entries[0].From: 2, // Nonce 1 of sender 1 went to another tx, nonce 2 is unused
+			entries[1].From: 1,
+		},
+	}
+
+	result, err := Reconcile(context.Background(), client, entries)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	want := map[Status]int{StatusConfirmed: 1, StatusReverted: 1, StatusReplaced: 1, StatusPending: 1, StatusMissing: 1}
+	for status, n := range want {
+		if result.Counts[status] != n {
+			t.Errorf("%s = %d, want %d", status, result.Counts[status], n)
+		}
+	}
+	if result.GasUsed != 51000 || result.Fees.Int64() != 102000 {
+		t.Errorf("GasUsed = %d, Fees = %s, want 51000 and 102000", result.GasUsed, result.Fees)
+	}
+	if len(result.Unresolved()) != 3 || len(result.Accounts) != 2 {
+		t.Errorf("%d unresolved across %d accounts, want 3 across 2", len(result.Unresolved()), len(result.Accounts))
+	}
+	if result.Outcomes[4].Status != StatusMissing {
+		t.Errorf("nonce 2 of sender 1 = %s, want missing", result.Outcomes[4].Status)
+	}
+}
+
+func TestLoad_Compressed(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "journal.jsonl")
+	w, err := Create(path, Header{ChainID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Append(testEntry(0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A journal compressed after the run, e.g. with `zstd journal.jsonl`
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed := path + ".zst"
+	zw, err := compress.Create(compressed, compress.Zstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := Load(compressed)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(j.Headers) != 1 || len(j.Entries) != 1 {
+		t.Errorf("Load() = %d headers, %d entries, want 1 and 1", len(j.Headers), len(j.Entries))
+	}
+}
@@ -0,0 +1,178 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Client defines the interface for looking up journaled transactions
+type Client interface {
+	// TransactionReceipt returns the receipt of a mined transaction
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	// TransactionByHash returns a transaction and whether it is still pending
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
+	// NonceAt returns the nonce of an account at a block (nil = latest)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// Status is what became of a journaled transaction
+type Status string
+
+const (
+	// StatusConfirmed was mined and succeeded
+	StatusConfirmed Status = "confirmed"
+	// StatusReverted was mined and reverted
+	StatusReverted Status = "reverted"
+	// StatusPending is still in the node's pool
+	StatusPending Status = "pending"
+	// StatusReplaced is unknown while its nonce was used by another transaction
+	StatusReplaced Status = "replaced"
+	// StatusMissing is unknown and its nonce is still unused
+	StatusMissing Status = "missing"
+)
+
+// Outcome is the reconciled state of one journaled transaction
+type Outcome struct {
+	Entry
+	Status  Status
+	Block   uint64   // Inclusion block of mined transactions
+	GasUsed uint64   // Gas used by mined transactions
+	Fee     *big.Int // Fee paid by mined transactions, in wei (nil = unknown)
+}
+
+// Result summarizes a reconciled journal
+type Result struct {
+	Total    int
+	Counts   map[Status]int
+	GasUsed  uint64                 // Gas used by mined transactions
+	Fees     *big.Int               // Fees paid by mined transactions, in wei
+	Accounts map[common.Address]int // Unresolved (pending, replaced or missing) transactions per sender
+	Outcomes []*Outcome
+}
+
+// Reconcile looks up every journal entry on chain. Transactions without a
+// receipt are classified by whether the node still holds them and whether
+// their sender's nonce has moved past them.
+func Reconcile(ctx context.Context, client Client, entries []Entry) (*Result, error) {
+	result := &Result{
+		Total:    len(entries),
+		Counts:   make(map[Status]int),
+		Fees:     new(big.Int),
+		Accounts: make(map[common.Address]int),
+		Outcomes: make([]*Outcome, 0, len(entries)),
+	}
+	nonces := make(map[common.Address]uint64)
+
+	for _, entry := range entries {
+		outcome, err := reconcileEntry(ctx, client, entry, nonces)
+		if err != nil {
+			return result, err
+		}
+		result.Counts[outcome.Status]++
+		result.GasUsed += outcome.GasUsed
+		if outcome.Fee != nil {
+			result.Fees.Add(result.Fees, outcome.Fee)
+		}
+		switch outcome.Status {
+		case StatusConfirmed, StatusReverted:
+		default:
+			result.Accounts[entry.From]++
+		}
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+	return result, nil
+}
+
+// reconcileEntry looks up one entry; nonces caches the latest nonce per sender
+func reconcileEntry(ctx context.Context, client Client, entry Entry, nonces map[common.Address]uint64) (*Outcome, error) {
+	outcome := &Outcome{Entry: entry}
+
+	receipt, err := client.TransactionReceipt(ctx, entry.Hash)
+	if err == nil && receipt != nil {
+		outcome.Status = StatusConfirmed
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			outcome.Status = StatusReverted
+		}
+		if receipt.BlockNumber != nil {
+			outcome.Block = receipt.BlockNumber.Uint64()
+		}
+		outcome.GasUsed = receipt.GasUsed
+		if receipt.EffectiveGasPrice != nil {
+			outcome.Fee = new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+		}
+		return outcome, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if tx, pending, err := client.TransactionByHash(ctx, entry.Hash); err == nil && tx != nil && pending {
+		outcome.Status = StatusPending
+		return outcome, nil
+	}
+
+	nonce, ok := nonces[entry.From]
+	if !ok {
+		nonce, err = client.NonceAt(ctx, entry.From, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nonce of %s: %w", entry.From.Hex(), err)
+		}
+		nonces[entry.From] = nonce
+	}
+	outcome.Status = StatusMissing
+	if nonce > entry.Nonce {
+		outcome.Status = StatusReplaced
+	}
+	return outcome, nil
+}
+
+// Unresolved returns the transactions that were not mined
+func (r *Result) Unresolved() []*Outcome {
+	var unresolved []*Outcome
+	for _, outcome := range r.Outcomes {
+		if outcome.Status != StatusConfirmed && outcome.Status != StatusReverted {
+			unresolved = append(unresolved, outcome)
+		}
+	}
+	return unresolved
+}
+
+// PrintResult prints the reconciliation summary and up to limit unresolved transactions
+func PrintResult(result *Result, j *Journal, limit int) {
+	console.Printf("\nJournal Reconciliation\n\n")
+	console.Printf("  Runs:          %d\n", len(j.Headers))
+	if len(j.Headers) > 0 {
+		console.Printf("  First Run:     %s (%s)\n", j.Headers[0].StartedAt.Format(time.RFC3339), j.Headers[0].Mode)
+	}
+	console.Printf("  Transactions:  %d\n", result.Total)
+	for _, status := range []Status{StatusConfirmed, StatusReverted, StatusPending, StatusReplaced, StatusMissing} {
+		console.Printf("  %-14s %d\n", titleStatus(status)+":", result.Counts[status])
+	}
+	console.Printf("  Gas Used:      %d\n", result.GasUsed)
+	console.Printf("  Fees Paid:     %s wei\n", result.Fees)
+
+	unresolved := result.Unresolved()
+	if len(unresolved) == 0 {
+		return
+	}
+	console.Printf("\nUnresolved transactions (%d across %d accounts):\n", len(unresolved), len(result.Accounts))
+	for i, outcome := range unresolved {
+		if limit > 0 && i == limit {
+			console.Printf("  ... %d more\n", len(unresolved)-limit)
+			break
+		}
+		console.Printf("  %s  %s nonce %d  %s\n", outcome.Hash.Hex(), outcome.From.Hex(), outcome.Nonce, outcome.Status)
+	}
+}
+
+func titleStatus(status Status) string {
+	s := string(status)
+	return string(s[0]-'a'+'A') + s[1:]
+}
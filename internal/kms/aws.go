@@ -0,0 +1,245 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AWS signs with an AWS KMS key through the KMS JSON API. Credentials come
+// from the first source that has them, in the order of the AWS SDKs:
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN; the
+// AWS_PROFILE profile of the shared credentials and config files; the ECS
+// task role or EKS Pod Identity; the EC2 instance role. Role credentials are
+// refreshed before they expire. Profiles that assume a role, use SSO or run
+// a credential_process are not supported. The region comes from the key ARN,
+// AWS_REGION, AWS_DEFAULT_REGION or the profile.
+type AWS struct {
+	KeyID        string
+	Region       string
+	Endpoint     string // Defaults to https://kms.<region>.amazonaws.com
+	AccessKey    string // Long-term or session keys; empty to use a role
+	SecretKey    string
+	SessionToken string
+
+	client *http.Client
+	now    func() time.Time
+
+	// Role credentials, cached until shortly before they expire
+	roleCreds func(ctx context.Context) (*awsCredentials, error)
+	mu        sync.Mutex
+	cached    *awsCredentials
+}
+
+// NewAWS creates an AWS KMS backend for a key ID, alias or ARN
+func NewAWS(keyID string, client *http.Client) (*AWS, error) {
+	a := &AWS{
+		KeyID:        keyID,
+		Region:       firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		Endpoint:     firstEnv("AWS_ENDPOINT_URL_KMS", "AWS_ENDPOINT_URL"),
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		client:       client,
+		now:          time.Now,
+	}
+	profile, err := loadAWSProfile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AWS profile: %w", err)
+	}
+	if a.Region == "" {
+		a.Region = profile.region
+	}
+	// arn:aws:kms:<region>:<account>:key/<id>
+	if parts := strings.Split(keyID, ":"); len(parts) >= 6 && parts[0] == "arn" {
+		a.Region = parts[3]
+	}
+	if a.Region == "" {
+		return nil, fmt.Errorf("AWS KMS region is required: use a key ARN or set AWS_REGION")
+	}
+	if a.AccessKey == "" || a.SecretKey == "" {
+		a.AccessKey, a.SecretKey, a.SessionToken = profile.creds.AccessKey, profile.creds.SecretKey, profile.creds.SessionToken
+	}
+	if a.AccessKey == "" || a.SecretKey == "" {
+		if profile.explicit {
+			return nil, fmt.Errorf("AWS profile %q has no access keys; for role, SSO or credential_process profiles "+
+				"export the keys with `aws configure export-credentials --format env`", profile.name)
+		}
+		a.AccessKey, a.SecretKey, a.SessionToken = "", "", ""
+		a.roleCreds = awsRoleCredentials(client)
+	}
+	if a.Endpoint == "" {
+		a.Endpoint = "https://kms." + a.Region + ".amazonaws.com"
+	}
+	return a, nil
+}
+
+// PublicKey returns the DER public key of the KMS key
+func (a *AWS) PublicKey(ctx context.Context) ([]byte, error) {
+	var resp struct {
+		PublicKey []byte
+		KeySpec   string
+	}
+	if err := a.call(ctx, "GetPublicKey", map[string]any{"KeyId": a.KeyID}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.KeySpec != "" && resp.KeySpec != "ECC_SECG_P256K1" {
+		return nil, fmt.Errorf("AWS KMS key spec is %s, want ECC_SECG_P256K1", resp.KeySpec)
+	}
+	return resp.PublicKey, nil
+}
+
+// Sign signs a digest with the KMS key
+func (a *AWS) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	req := map[string]any{
+		"KeyId":            a.KeyID,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}
+	var resp struct{ Signature []byte }
+	if err := a.call(ctx, "Sign", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// call sends one KMS action; []byte fields travel as base64 in both directions
+func (a *AWS) call(ctx context.Context, action string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	creds, err := a.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	a.sign(req, body, creds)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &e)
+		return fmt.Errorf("AWS KMS %s failed: %s %s: %s", action, resp.Status, e.Type, e.Message)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// credentials returns the configured keys, or the role credentials,
+// fetched again when they are about to expire
+func (a *AWS) credentials(ctx context.Context) (*awsCredentials, error) {
+	if a.roleCreds == nil {
+		return &awsCredentials{AccessKey: a.AccessKey, SecretKey: a.SecretKey, SessionToken: a.SessionToken}, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cached != nil && (a.cached.Expires.IsZero() || a.now().Before(a.cached.Expires.Add(-credentialRefresh))) {
+		return a.cached, nil
+	}
+	creds, err := a.roleCreds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	a.cached = creds
+	return creds, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header
+func (a *AWS) sign(req *http.Request, body []byte, creds *awsCredentials) {
+	now := a.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	if creds.SessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+	}
+	sort.Strings(headers)
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		value := req.Header.Get(h)
+		if h == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		"", // KMS requests carry no query string
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + a.Region + "/kms/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hexSHA256([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretKey), date)
+	key = hmacSHA256(key, a.Region)
+	key = hmacSHA256(key, "kms")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKey, scope, signedHeaders, signature))
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// firstEnv returns the first non-empty environment variable of names
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,240 @@
+package kms
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// awsCredentials are the keys a request is signed with
+type awsCredentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Expires      time.Time // Zero for long-term keys
+}
+
+// awsProfile is the profile of the shared config and credentials files
+type awsProfile struct {
+	name     string
+	explicit bool // Named by AWS_PROFILE rather than the default
+	found    bool
+	creds    awsCredentials
+	region   string
+}
+
+// loadAWSProfile reads the AWS_PROFILE (default "default") profile from
+// ~/.aws/credentials and ~/.aws/config, or the files named by
+// AWS_SHARED_CREDENTIALS_FILE and AWS_CONFIG_FILE. Missing files are no error.
+func loadAWSProfile() (*awsProfile, error) {
+	p := &awsProfile{name: firstEnv("AWS_PROFILE", "AWS_DEFAULT_PROFILE")}
+	p.explicit = p.name != ""
+	if !p.explicit {
+		p.name = "default"
+	}
+	home, _ := os.UserHomeDir()
+	credsFile := firstEnv("AWS_SHARED_CREDENTIALS_FILE")
+	if credsFile == "" && home != "" {
+		credsFile = filepath.Join(home, ".aws", "credentials")
+	}
+	configFile := firstEnv("AWS_CONFIG_FILE")
+	if configFile == "" && home != "" {
+		configFile = filepath.Join(home, ".aws", "config")
+	}
+
+	// The config file prefixes every profile but the default with "profile "
+	configSection := p.name
+	if p.name != "default" {
+		configSection = "profile " + p.name
+	}
+	for _, src := range []struct{ file, section string }{{credsFile, p.name}, {configFile, configSection}} {
+		if src.file == "" {
+			continue
+		}
+		values, err := readINISection(src.file, src.section)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			continue
+		}
+		p.found = true
+		if p.creds.AccessKey == "" {
+			p.creds = awsCredentials{
+				AccessKey:    values["aws_access_key_id"],
+				SecretKey:    values["aws_secret_access_key"],
+				SessionToken: values["aws_session_token"],
+			}
+		}
+		if p.region == "" {
+			p.region = values["region"]
+		}
+	}
+	return p, nil
+}
+
+// readINISection returns the keys of one section of an INI file, or nil if
+// the file or the section does not exist
+func readINISection(path, section string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]string
+	inSection := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case line[0] == '[' && line[len(line)-1] == ']':
+			inSection = strings.TrimSpace(line[1:len(line)-1]) == section
+			if inSection && values == nil {
+				values = map[string]string{}
+			}
+		case inSection:
+			if key, value, ok := strings.Cut(line, "="); ok {
+				values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return values, scanner.Err()
+}
+
+// awsRoleCredentials returns the source of role credentials: the ECS task
+// role or EKS Pod Identity if the container provides one, else the EC2
+// instance role through IMDSv2
+func awsRoleCredentials(client *http.Client) func(context.Context) (*awsCredentials, error) {
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+		return func(ctx context.Context) (*awsCredentials, error) {
+			return containerCredentials(ctx, client, "http://169.254.170.2"+uri)
+		}
+	}
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); uri != "" {
+		return func(ctx context.Context) (*awsCredentials, error) {
+			return containerCredentials(ctx, client, uri)
+		}
+	}
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		return func(context.Context) (*awsCredentials, error) {
+			return nil, errors.New("no AWS credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or AWS_PROFILE")
+		}
+	}
+	endpoint := firstEnv("AWS_EC2_METADATA_SERVICE_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "http://169.254.169.254"
+	}
+	return func(ctx context.Context) (*awsCredentials, error) {
+		return instanceCredentials(ctx, client, strings.TrimSuffix(endpoint, "/"))
+	}
+}
+
+// containerCredentials fetches task role credentials from the container
+// credentials endpoint
+func containerCredentials(ctx context.Context, client *http.Client, url string) (*awsCredentials, error) {
+	headers := map[string]string{}
+	token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		headers["Authorization"] = token
+	}
+	data, err := metadataRequest(ctx, client, http.MethodGet, url, headers)
+	if err != nil {
+		return nil, fmt.Errorf("container credentials: %w", err)
+	}
+	return parseRoleCredentials(data)
+}
+
+// instanceCredentials fetches instance role credentials from IMDSv2
+func instanceCredentials(ctx context.Context, client *http.Client, endpoint string) (*awsCredentials, error) {
+	token, err := metadataRequest(ctx, client, http.MethodPut, endpoint+"/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "300"})
+	if err != nil {
+		return nil, fmt.Errorf("no AWS credentials in the environment, profile or instance metadata: %w", err)
+	}
+	headers := map[string]string{"X-aws-ec2-metadata-token": string(token)}
+
+	base := endpoint + "/latest/meta-data/iam/security-credentials/"
+	roles, err := metadataRequest(ctx, client, http.MethodGet, base, headers)
+	if err != nil {
+		return nil, fmt.Errorf("instance role: %w", err)
+	}
+	role, _, _ := strings.Cut(strings.TrimSpace(string(roles)), "\n")
+	if role == "" {
+		return nil, errors.New("the instance has no IAM role")
+	}
+	data, err := metadataRequest(ctx, client, http.MethodGet, base+role, headers)
+	if err != nil {
+		return nil, fmt.Errorf("instance role %s: %w", role, err)
+	}
+	return parseRoleCredentials(data)
+}
+
+// parseRoleCredentials decodes the credentials document of the container
+// and instance metadata endpoints
+func parseRoleCredentials(data []byte) (*awsCredentials, error) {
+	var resp struct {
+		AccessKeyId     string
+		SecretAccessKey string
+		Token           string
+		Expiration      time.Time
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("invalid role credentials: %w", err)
+	}
+	if resp.AccessKeyId == "" || resp.SecretAccessKey == "" {
+		return nil, errors.New("role credentials carry no access key")
+	}
+	return &awsCredentials{
+		AccessKey:    resp.AccessKeyId,
+		SecretKey:    resp.SecretAccessKey,
+		SessionToken: resp.Token,
+		Expires:      resp.Expiration,
+	}, nil
+}
+
+// metadataRequest sends one request to a local metadata endpoint, which
+// answers quickly or not at all
+func metadataRequest(ctx context.Context, client *http.Client, method, url string, headers map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	return data, nil
+}
@@ -0,0 +1,157 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GCP signs with a Cloud KMS key version through the Cloud KMS REST API.
+// The OAuth access token is GOOGLE_OAUTH_ACCESS_TOKEN if set, e.g. the
+// output of `gcloud auth print-access-token`, which is not refreshed.
+// Otherwise tokens come from Application Default Credentials: the user or
+// service account key file of GOOGLE_APPLICATION_CREDENTIALS or of
+// `gcloud auth application-default login`, else the metadata server of GCE,
+// GKE and Cloud Run. Those tokens are refreshed before they expire.
+type GCP struct {
+	Name     string // projects/<p>/locations/<l>/keyRings/<r>/cryptoKeys/<k>/cryptoKeyVersions/<v>
+	Endpoint string // Defaults to https://cloudkms.googleapis.com
+	Token    string // Fixed access token; empty to use Application Default Credentials
+
+	client *http.Client
+	now    func() time.Time
+
+	// Application Default Credentials tokens, cached until shortly before they expire
+	tokens func(ctx context.Context) (*gcpToken, error)
+	mu     sync.Mutex
+	cached *gcpToken
+}
+
+// NewGCP creates a Cloud KMS backend for a crypto key version
+func NewGCP(name string, client *http.Client) (*GCP, error) {
+	if !strings.HasPrefix(name, "projects/") || !strings.Contains(name, "/cryptoKeyVersions/") {
+		return nil, fmt.Errorf("invalid Cloud KMS key %q: want projects/.../cryptoKeys/<key>/cryptoKeyVersions/<version>", name)
+	}
+	g := &GCP{
+		Name:     name,
+		Endpoint: "https://cloudkms.googleapis.com",
+		Token:    os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+		client:   client,
+		now:      time.Now,
+	}
+	if g.Token == "" {
+		tokens, err := gcpDefaultTokens(client, g.now)
+		if err != nil {
+			return nil, fmt.Errorf("GCP credentials: %w", err)
+		}
+		g.tokens = tokens
+	}
+	return g, nil
+}
+
+// PublicKey returns the DER public key of the key version
+func (g *GCP) PublicKey(ctx context.Context) ([]byte, error) {
+	var resp struct {
+		Pem       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := g.call(ctx, http.MethodGet, "/publicKey", nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Algorithm != "" && resp.Algorithm != "EC_SIGN_SECP256K1_SHA256" {
+		return nil, fmt.Errorf("GCP KMS key algorithm is %s, want EC_SIGN_SECP256K1_SHA256", resp.Algorithm)
+	}
+	block, _ := pem.Decode([]byte(resp.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("GCP KMS returned no PEM public key")
+	}
+	return block.Bytes, nil
+}
+
+// Sign signs a digest with the key version
+func (g *GCP) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	if len(digest) != sha256.Size {
+		return nil, fmt.Errorf("digest is %d bytes, want %d", len(digest), sha256.Size)
+	}
+	req := map[string]any{"digest": map[string][]byte{"sha256": digest}}
+	var resp struct {
+		Signature []byte `json:"signature"`
+	}
+	if err := g.call(ctx, http.MethodPost, ":asymmetricSign", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// call sends one request for the key version; []byte fields travel as base64
+func (g *GCP) call(ctx context.Context, method, suffix string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.Endpoint+"/v1/"+g.Name+suffix, body)
+	if err != nil {
+		return err
+	}
+	token, err := g.token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(data, &e)
+		return fmt.Errorf("GCP KMS request failed: %s %s: %s", resp.Status, e.Error.Status, e.Error.Message)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// token returns the fixed access token, or an Application Default
+// Credentials token, fetched again when it is about to expire
+func (g *GCP) token(ctx context.Context) (string, error) {
+	if g.tokens == nil {
+		return g.Token, nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cached != nil && g.now().Before(g.cached.Expires.Add(-credentialRefresh)) {
+		return g.cached.Value, nil
+	}
+	token, err := g.tokens(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get GCP access token: %w", err)
+	}
+	g.cached = token
+	return token.Value, nil
+}
@@ -0,0 +1,220 @@
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// gcpTokenURL is the OAuth token endpoint of user credentials
+	gcpTokenURL = "https://oauth2.googleapis.com/token"
+
+	// gcpKMSScope is the OAuth scope service account tokens are requested for
+	gcpKMSScope = "https://www.googleapis.com/auth/cloudkms"
+)
+
+// gcpToken is an OAuth access token
+type gcpToken struct {
+	Value   string
+	Expires time.Time
+}
+
+// gcpCredentialsFile is an Application Default Credentials file
+type gcpCredentialsFile struct {
+	Type string `json:"type"`
+
+	// authorized_user, written by `gcloud auth application-default login`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+
+	// service_account key files
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// gcpDefaultTokens returns the token source of Application Default
+// Credentials: the GOOGLE_APPLICATION_CREDENTIALS file, else the file gcloud
+// writes, else the metadata server of GCE, GKE and Cloud Run
+func gcpDefaultTokens(client *http.Client, now func() time.Time) (func(context.Context) (*gcpToken, error), error) {
+	path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if path == "" {
+		path = gcloudCredentialsPath()
+		if _, err := os.Stat(path); err != nil {
+			return func(ctx context.Context) (*gcpToken, error) {
+				return metadataToken(ctx, client, now)
+			}, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f gcpCredentialsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid credentials file %s: %w", path, err)
+	}
+	switch f.Type {
+	case "authorized_user":
+		form := url.Values{
+			"grant_type":    {"refresh_token"},
+			"client_id":     {f.ClientID},
+			"client_secret": {f.ClientSecret},
+			"refresh_token": {f.RefreshToken},
+		}
+		return func(ctx context.Context) (*gcpToken, error) {
+			return exchangeToken(ctx, client, gcpTokenURL, form, now)
+		}, nil
+	case "service_account":
+		key, err := parseRSAKey(f.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service account key in %s: %w", path, err)
+		}
+		if f.TokenURI == "" {
+			f.TokenURI = gcpTokenURL
+		}
+		return func(ctx context.Context) (*gcpToken, error) {
+			assertion, err := serviceAccountJWT(f.ClientEmail, f.TokenURI, key, now())
+			if err != nil {
+				return nil, err
+			}
+			form := url.Values{
+				"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+				"assertion":  {assertion},
+			}
+			return exchangeToken(ctx, client, f.TokenURI, form, now)
+		}, nil
+	default:
+		return nil, fmt.Errorf("credentials file %s has unsupported type %q: set GOOGLE_OAUTH_ACCESS_TOKEN instead", path, f.Type)
+	}
+}
+
+// gcloudCredentialsPath returns where `gcloud auth application-default login`
+// writes its credentials
+func gcloudCredentialsPath() string {
+	dir := os.Getenv("CLOUDSDK_CONFIG")
+	if dir == "" {
+		if runtime.GOOS == "windows" {
+			dir = filepath.Join(os.Getenv("APPDATA"), "gcloud")
+		} else {
+			home, _ := os.UserHomeDir()
+			dir = filepath.Join(home, ".config", "gcloud")
+		}
+	}
+	return filepath.Join(dir, "application_default_credentials.json")
+}
+
+// parseRSAKey parses the PKCS #8 PEM private key of a service account
+func parseRSAKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("no PEM private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// serviceAccountJWT signs the RS256 assertion a service account trades for
+// an access token
+func serviceAccountJWT(email, audience string, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   email,
+		"scope": gcpKMSScope,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// exchangeToken posts a grant to an OAuth token endpoint
+func exchangeToken(ctx context.Context, client *http.Client, tokenURL string, form url.Values, now func() time.Time) (*gcpToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(data, &e)
+		return nil, fmt.Errorf("OAuth token request failed: %s %s: %s", resp.Status, e.Error, e.Description)
+	}
+	return parseToken(data, now)
+}
+
+// metadataToken fetches the token of the default service account from the
+// metadata server, or the GCE_METADATA_HOST one
+func metadataToken(ctx context.Context, client *http.Client, now func() time.Time) (*gcpToken, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = "metadata.google.internal"
+	}
+	data, err := metadataRequest(ctx, client, http.MethodGet,
+		"http://"+host+"/computeMetadata/v1/instance/service-accounts/default/token",
+		map[string]string{"Metadata-Flavor": "Google"})
+	if err != nil {
+		return nil, fmt.Errorf("no GCP credentials: set GOOGLE_OAUTH_ACCESS_TOKEN or GOOGLE_APPLICATION_CREDENTIALS, "+
+			"run `gcloud auth application-default login`, or run on GCP: %w", err)
+	}
+	return parseToken(data, now)
+}
+
+// parseToken decodes the token response of the token endpoint and the metadata server
+func parseToken(data []byte, now func() time.Time) (*gcpToken, error) {
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return nil, errors.New("token response carries no access token")
+	}
+	return &gcpToken{Value: resp.AccessToken, Expires: now().Add(time.Duration(resp.ExpiresIn) * time.Second)}, nil
+}
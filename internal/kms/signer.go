@@ -0,0 +1,171 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Backend is a key held by a key management service. The key must be a
+// secp256k1 key (AWS KMS ECC_SECG_P256K1, GCP KMS EC_SIGN_SECP256K1_SHA256).
+type Backend interface {
+	// PublicKey returns the DER-encoded SubjectPublicKeyInfo of the key
+	PublicKey(ctx context.Context) ([]byte, error)
+	// Sign signs a 32-byte digest and returns the DER-encoded ECDSA signature
+	Sign(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+const (
+	// requestTimeout bounds every call to a key management service
+	requestTimeout = 30 * time.Second
+
+	// metadataTimeout bounds a call to a local credentials endpoint, which
+	// does not answer at all off the cloud
+	metadataTimeout = 2 * time.Second
+
+	// credentialRefresh is how long before they expire temporary
+	// credentials are fetched again
+	credentialRefresh = 5 * time.Minute
+)
+
+// oidSecp256k1 identifies the secp256k1 curve in a SubjectPublicKeyInfo
+var oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+var (
+	// secp256k1N is the order of the secp256k1 curve
+	secp256k1N = crypto.S256().Params().N
+	// secp256k1HalfN is half the curve order; Ethereum only accepts S at or below it
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// Signer signs for an account whose key never leaves a key management
+// service. It satisfies txbuilder.Signer.
+type Signer struct {
+	backend Backend
+	pubkey  []byte // Uncompressed public key, 65 bytes
+	address common.Address
+}
+
+// Open connects to the key named by uri: awskms://<key-id or ARN> or
+// gcpkms://<crypto key version resource name>
+func Open(ctx context.Context, uri string) (*Signer, error) {
+	backend, err := NewBackend(uri)
+	if err != nil {
+		return nil, err
+	}
+	return New(ctx, backend)
+}
+
+// NewBackend creates the backend for a key URI
+func NewBackend(uri string) (Backend, error) {
+	scheme, key, ok := strings.Cut(uri, "://")
+	if !ok || key == "" {
+		return nil, fmt.Errorf("invalid KMS key URI %q: want awskms://<key-id> or gcpkms://<key-version>", uri)
+	}
+	httpClient := &http.Client{Timeout: requestTimeout}
+	switch scheme {
+	case "awskms":
+		return NewAWS(key, httpClient)
+	case "gcpkms":
+		return NewGCP(key, httpClient)
+	default:
+		return nil, fmt.Errorf("unsupported KMS scheme %q: must be awskms or gcpkms", scheme)
+	}
+}
+
+// New fetches the public key of the backend and creates its signer
+func New(ctx context.Context, backend Backend) (*Signer, error) {
+	der, err := backend.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KMS public key: %w", err)
+	}
+	pub, err := parsePublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{
+		backend: backend,
+		pubkey:  crypto.FromECDSAPub(pub),
+		address: crypto.PubkeyToAddress(*pub),
+	}, nil
+}
+
+// Address returns the address of the KMS key
+func (s *Signer) Address() common.Address {
+	return s.address
+}
+
+// SignHash signs the digest in the key management service and converts the
+// DER signature to the 65-byte [R || S || V] form with a low S
+func (s *Signer) SignHash(hash []byte) ([]byte, error) {
+	if len(hash) != common.HashLength {
+		return nil, fmt.Errorf("hash is %d bytes, want %d", len(hash), common.HashLength)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	der, err := s.backend.Sign(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("KMS signing failed for %s: %w", s.address.Hex(), err)
+	}
+	return s.recoverable(hash, der)
+}
+
+// recoverable converts a DER signature to [R || S || V], finding V by
+// recovering the public key
+func (s *Signer) recoverable(hash, der []byte) ([]byte, error) {
+	var parsed struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(der, &parsed); err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("invalid DER signature from KMS")
+	}
+	if parsed.R.Sign() <= 0 || parsed.S.Sign() <= 0 || parsed.R.Cmp(secp256k1N) >= 0 || parsed.S.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("KMS signature out of range")
+	}
+	// Both S and N-S are valid; EIP-2 only accepts the lower one
+	if parsed.S.Cmp(secp256k1HalfN) > 0 {
+		parsed.S = new(big.Int).Sub(secp256k1N, parsed.S)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	parsed.R.FillBytes(sig[0:32])
+	parsed.S.FillBytes(sig[32:64])
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.Ecrecover(hash, sig)
+		if err == nil && bytes.Equal(pub, s.pubkey) {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("KMS signature does not match the key of %s", s.address.Hex())
+}
+
+// parsePublicKey parses a DER SubjectPublicKeyInfo holding a secp256k1 key.
+// crypto/x509 does not know the curve, so the structure is decoded directly.
+func parsePublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("invalid KMS public key")
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("KMS key is not a secp256k1 key")
+	}
+	pub, err := crypto.UnmarshalPubkey(spki.PublicKey.RightAlign())
+	if err != nil {
+		return nil, fmt.Errorf("invalid KMS public key: %w", err)
+	}
+	return pub, nil
+}
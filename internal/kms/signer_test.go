@@ -0,0 +1,496 @@
+package kms
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+)
+
+// oidECPublicKey identifies an elliptic curve key in a SubjectPublicKeyInfo
+var oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+// fakeBackend signs with a local key the way a KMS does: DER output, no recovery id
+type fakeBackend struct {
+	key   *ecdsa.PrivateKey
+	highS bool // Return the high-S form of every signature
+	err   error
+}
+
+func (f *fakeBackend) PublicKey(context.Context) ([]byte, error) {
+	return marshalPublicKey(&f.key.PublicKey), nil
+}
+
+func (f *fakeBackend) Sign(_ context.Context, digest []byte) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	sig, err := crypto.Sign(digest, f.key)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if f.highS {
+		s.Sub(secp256k1N, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func marshalPublicKey(pub *ecdsa.PublicKey) []byte {
+	curve, _ := asn1.Marshal(oidSecp256k1)
+	der, _ := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidECPublicKey, Parameters: asn1.RawValue{FullBytes: curve}},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(pub), BitLength: 8 * 65},
+	})
+	return der
+}
+
+func TestSigner_SignTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(1337)
+	to := common.HexToAddress("0xbeef")
+
+	for _, highS := range []bool{false, true} {
+		signer, err := New(context.Background(), &fakeBackend{key: key, highS: highS})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if signer.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+			t.Fatalf("Address() = %s, want the key's address", signer.Address().Hex())
+		}
+
+		// Several transactions so both recovery ids come up
+		for nonce := uint64(0); nonce < 8; nonce++ {
+			tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: nonce, To: &to, Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), Value: big.NewInt(1)})
+			signed, err := txbuilder.SignTransaction(tx, chainID, signer)
+			if err != nil {
+				t.Fatalf("highS=%v: SignTransaction() error = %v", highS, err)
+			}
+			from, err := types.Sender(types.NewLondonSigner(chainID), signed)
+			if err != nil || from != signer.Address() {
+				t.Fatalf("highS=%v: sender = %s, %v, want %s", highS, from.Hex(), err, signer.Address().Hex())
+			}
+			if _, _, s := signed.RawSignatureValues(); s.Cmp(secp256k1HalfN) > 0 {
+				t.Errorf("highS=%v: S not normalized", highS)
+			}
+		}
+	}
+}
+
+func TestSigner_Errors(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+
+	backend := &fakeBackend{key: key, err: errors.New("throttled")}
+	signer, err := New(context.Background(), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := crypto.Keccak256([]byte("payload"))
+	if _, err := signer.SignHash(hash); err == nil || !strings.Contains(err.Error(), "throttled") {
+		t.Errorf("SignHash() error = %v, want backend error", err)
+	}
+	if _, err := signer.SignHash(hash[:31]); err == nil {
+		t.Error("SignHash() should reject a short hash")
+	}
+
+	// A signature from another key must not pass as this account's
+	backend.err = nil
+	backend.key = other
+	if _, err := signer.SignHash(hash); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("SignHash() error = %v, want key mismatch", err)
+	}
+
+	// Only secp256k1 keys can sign for Ethereum accounts
+	p256, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	der := marshalPublicKey(&key.PublicKey)
+	der = []byte(strings.Replace(string(der), mustMarshal(oidSecp256k1), string(p256), 1))
+	if _, err := parsePublicKey(der); err == nil {
+		t.Error("parsePublicKey() should reject a P-256 key")
+	}
+
+	if _, err := NewBackend("vault://treasury"); err == nil {
+		t.Error("NewBackend() should reject an unknown scheme")
+	}
+}
+
+func mustMarshal(v any) string {
+	der, err := asn1.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(der)
+}
+
+func TestAWS(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	backend := &fakeBackend{key: key}
+
+	var targets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240102/eu-west-1/kms/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-target, Signature=") {
+			t.Errorf("Authorization = %q", auth)
+		}
+		target := r.Header.Get("X-Amz-Target")
+		targets = append(targets, target)
+
+		var req struct {
+			KeyId       string
+			Message     []byte
+			MessageType string
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &req); err != nil || req.KeyId != "alias/treasury" {
+			t.Errorf("request = %s, %v", body, err)
+		}
+		switch target {
+		case "TrentService.GetPublicKey":
+			pub, _ := backend.PublicKey(r.Context())
+			_ = json.NewEncoder(w).Encode(map[string]any{"PublicKey": pub, "KeySpec": "ECC_SECG_P256K1"})
+		case "TrentService.Sign":
+			if req.MessageType != "DIGEST" {
+				t.Errorf("MessageType = %s, want DIGEST", req.MessageType)
+			}
+			sig, _ := backend.Sign(r.Context(), req.Message)
+			_ = json.NewEncoder(w).Encode(map[string]any{"Signature": sig})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"UnknownOperationException","message":"unknown"}`))
+		}
+	}))
+	defer server.Close()
+
+	isolateAWSProfile(t)
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_ENDPOINT_URL_KMS", server.URL)
+	aws, err := NewAWS("alias/treasury", server.Client())
+	if err != nil {
+		t.Fatalf("NewAWS() error = %v", err)
+	}
+	aws.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	signer, err := New(context.Background(), aws)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if signer.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("Address() = %s", signer.Address().Hex())
+	}
+	if _, err := signer.SignHash(crypto.Keccak256([]byte("payload"))); err != nil {
+		t.Errorf("SignHash() error = %v", err)
+	}
+	if strings.Join(targets, ",") != "TrentService.GetPublicKey,TrentService.Sign" {
+		t.Errorf("targets = %v", targets)
+	}
+
+	if err := aws.call(context.Background(), "Decrypt", map[string]any{"KeyId": "alias/treasury"}, &struct{}{}); err == nil || !strings.Contains(err.Error(), "UnknownOperationException") {
+		t.Errorf("call() error = %v, want the KMS error type", err)
+	}
+
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	if _, err := NewAWS("alias/treasury", nil); err == nil {
+		t.Error("NewAWS() should require a region")
+	}
+	arn, err := NewAWS("arn:aws:kms:ap-northeast-2:111122223333:key/abcd", nil)
+	if err != nil || arn.Region != "ap-northeast-2" {
+		t.Errorf("NewAWS(arn) region = %v, %v", arn, err)
+	}
+}
+
+// isolateAWSProfile points the shared AWS files at an empty directory
+func isolateAWSProfile(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_DEFAULT_PROFILE", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	return dir
+}
+
+func TestAWS_Profile(t *testing.T) {
+	dir := isolateAWSProfile(t)
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	credentials := "[default]\naws_access_key_id = AKIDDEFAULT\naws_secret_access_key = default\n\n" +
+		"[ci]\n# deploy keys\naws_access_key_id = AKIDCI\naws_secret_access_key = ci-secret\n\n[sso]\n"
+	config := "[profile ci]\nregion = us-east-2\n\n[profile sso]\nsso_session = corp\nregion = us-west-1\n"
+	if err := os.WriteFile(filepath.Join(dir, "credentials"), []byte(credentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("AWS_PROFILE", "ci")
+	a, err := NewAWS("alias/treasury", nil)
+	if err != nil {
+		t.Fatalf("NewAWS() error = %v", err)
+	}
+	if a.AccessKey != "AKIDCI" || a.SecretKey != "ci-secret" || a.Region != "us-east-2" || a.roleCreds != nil {
+		t.Errorf("profile ci: key=%s region=%s role=%v", a.AccessKey, a.Region, a.roleCreds != nil)
+	}
+
+	t.Setenv("AWS_PROFILE", "sso")
+	if _, err := NewAWS("alias/treasury", nil); err == nil || !strings.Contains(err.Error(), "export-credentials") {
+		t.Errorf("NewAWS() error = %v, want a profile without keys rejected", err)
+	}
+
+	// Keys in the environment win over the profile
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env")
+	if a, err := NewAWS("alias/treasury", nil); err != nil || a.AccessKey != "AKIDENV" {
+		t.Errorf("NewAWS() = %v, %v, want the environment keys", a, err)
+	}
+}
+
+func TestAWS_InstanceRole(t *testing.T) {
+	isolateAWSProfile(t)
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("imds-token"))
+		case r.Header.Get("X-aws-ec2-metadata-token") != "imds-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+			_, _ = w.Write([]byte("loadtest-role"))
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/loadtest-role":
+			fetches++
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"AccessKeyId":     fmt.Sprintf("ASIAROLE%d", fetches),
+				"SecretAccessKey": "role-secret",
+				"Token":           "role-session",
+				"Expiration":      now.Add(time.Hour).Format(time.RFC3339),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL)
+
+	a, err := NewAWS("alias/treasury", server.Client())
+	if err != nil {
+		t.Fatalf("NewAWS() error = %v", err)
+	}
+	a.now = func() time.Time { return now }
+
+	creds, err := a.credentials(context.Background())
+	if err != nil || creds.AccessKey != "ASIAROLE1" || creds.SessionToken != "role-session" {
+		t.Fatalf("credentials() = %+v, %v", creds, err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, "https://kms.eu-west-1.amazonaws.com/", nil)
+	a.sign(req, nil, creds)
+	if req.Header.Get("X-Amz-Security-Token") != "role-session" || !strings.Contains(req.Header.Get("Authorization"), "Credential=ASIAROLE1/") {
+		t.Errorf("request not signed with the role credentials: %v", req.Header)
+	}
+
+	// Cached until shortly before they expire
+	now = now.Add(50 * time.Minute)
+	if creds, _ := a.credentials(context.Background()); creds.AccessKey != "ASIAROLE1" {
+		t.Errorf("credentials refetched early: %s", creds.AccessKey)
+	}
+	now = now.Add(6 * time.Minute)
+	if creds, _ := a.credentials(context.Background()); creds == nil || creds.AccessKey != "ASIAROLE2" {
+		t.Errorf("credentials not refreshed before expiry: %+v", creds)
+	}
+}
+
+func TestGCP_ServiceAccount(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"bad grant"}`))
+			return
+		}
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&rsaKey.PublicKey, stdcrypto.SHA256, digest[:], sig); err != nil {
+			t.Errorf("assertion signature: %v", err)
+		}
+		if !strings.Contains(string(claims), `"iss":"loadtest@p.iam.gserviceaccount.com"`) || !strings.Contains(string(claims), gcpKMSScope) {
+			t.Errorf("claims = %s", claims)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": fmt.Sprintf("sa-token-%d", requests), "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "key.json")
+	data, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "loadtest@p.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL,
+	})
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", file)
+
+	g, err := NewGCP("projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1", server.Client())
+	if err != nil {
+		t.Fatalf("NewGCP() error = %v", err)
+	}
+	now := time.Now()
+	g.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if token, err := g.token(context.Background()); err != nil || token != "sa-token-1" {
+			t.Fatalf("token() = %q, %v, want the cached first token", token, err)
+		}
+	}
+	now = now.Add(56 * time.Minute)
+	if token, err := g.token(context.Background()); err != nil || token != "sa-token-2" {
+		t.Errorf("token() = %q, %v, want a refreshed token", token, err)
+	}
+}
+
+func TestGCP_Metadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" || r.URL.Path != "/computeMetadata/v1/instance/service-accounts/default/token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "vm-token", "expires_in": 3599, "token_type": "Bearer"})
+	}))
+	defer server.Close()
+
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("CLOUDSDK_CONFIG", t.TempDir())
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	g, err := NewGCP("projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1", server.Client())
+	if err != nil {
+		t.Fatalf("NewGCP() error = %v", err)
+	}
+	if token, err := g.token(context.Background()); err != nil || token != "vm-token" {
+		t.Errorf("token() = %q, %v, want the metadata server token", token, err)
+	}
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := NewGCP("projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1", nil); err == nil {
+		t.Error("NewGCP() should fail for a missing GOOGLE_APPLICATION_CREDENTIALS file")
+	}
+}
+
+func TestGCP(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	backend := &fakeBackend{key: key}
+	name := "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"status":"UNAUTHENTICATED","message":"bad token"}}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/" + name + "/publicKey":
+			pub, _ := backend.PublicKey(r.Context())
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"pem":       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})),
+				"algorithm": "EC_SIGN_SECP256K1_SHA256",
+			})
+		case "/v1/" + name + ":asymmetricSign":
+			var req struct {
+				Digest struct {
+					Sha256 string `json:"sha256"`
+				} `json:"digest"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			digest, _ := base64.StdEncoding.DecodeString(req.Digest.Sha256)
+			sig, _ := backend.Sign(r.Context(), digest)
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"signature": sig})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if _, err := NewGCP("keyRings/r", nil); err == nil {
+		t.Error("NewGCP() should reject a name without a key version")
+	}
+
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "token")
+	gcp, err := NewGCP(name, server.Client())
+	if err != nil {
+		t.Fatalf("NewGCP() error = %v", err)
+	}
+	gcp.Endpoint = server.URL
+
+	signer, err := New(context.Background(), gcp)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if signer.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("Address() = %s", signer.Address().Hex())
+	}
+	if _, err := signer.SignHash(crypto.Keccak256([]byte("payload"))); err != nil {
+		t.Errorf("SignHash() error = %v", err)
+	}
+
+	gcp.Token = "expired"
+	if _, err := signer.SignHash(crypto.Keccak256([]byte("payload"))); err == nil || !strings.Contains(err.Error(), "UNAUTHENTICATED") {
+		t.Errorf("SignHash() error = %v, want UNAUTHENTICATED", err)
+	}
+}
@@ -3,6 +3,7 @@ package longsender
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
@@ -22,24 +23,43 @@ type LongSender struct {
 	limiter *rate.Limiter
 
 	// Keys and addresses
-	keys      []*ecdsa.PrivateKey
-	addresses []common.Address
+	keys       []*ecdsa.PrivateKey
+	addresses  []common.Address
+	recipients []common.Address // Recipient of each account (nil = self-transfers)
 
 	// Atomic nonce management per account
 	nonces []atomic.Uint64
 
+	// Lowest nonce of each account that was not sent, plus one (0 = none)
+	gaps []atomic.Uint64
+
+	// Failure tracking per account, the round-robin cursor and the
+	// accounts taken out of the rotation
+	health       []accountHealth
+	cursor       atomic.Uint64
+	quarantined  []Quarantine
+	quarantineMu sync.Mutex
+
 	// Atomic counters
 	sentCount   atomic.Int64
 	failedCount atomic.Int64
+	sentBytes   atomic.Int64
 
 	// Chain info
 	chainID  *big.Int
 	gasPrice *big.Int
 	gasLimit uint64
+	value    *big.Int
 
 	// Callbacks
 	callbacks *Callbacks
 
+	// Optional gate checked before each send
+	gate Gate
+
+	// Optional budget that must admit each transaction
+	budget Budget
+
 	// Start time for TPS calculation
 	startTime time.Time
 
@@ -62,6 +82,7 @@ func New(client SendClient, config *Config) *LongSender {
 		config:   config,
 		limiter:  limiter,
 		gasLimit: 21000, // Standard transfer gas limit
+		value:    big.NewInt(0),
 		errors:   make([]error, 0),
 	}
 }
@@ -72,12 +93,42 @@ func (l *LongSender) WithGasLimit(gasLimit uint64) *LongSender {
 	return l
 }
 
+// WithValue sets the value each self-transfer sends
+func (l *LongSender) WithValue(value *big.Int) *LongSender {
+	l.value = value
+	return l
+}
+
+// WithRecipients sets the recipient of each account, in key order; without
+// recipients every account transfers to itself
+func (l *LongSender) WithRecipients(recipients []common.Address) *LongSender {
+	l.recipients = recipients
+	return l
+}
+
 // WithGasPrice sets the gas price for transactions
 func (l *LongSender) WithGasPrice(gasPrice *big.Int) *LongSender {
 	l.gasPrice = gasPrice
 	return l
 }
 
+// SetTPS updates the target send rate, taking effect while the sender is running
+func (l *LongSender) SetTPS(tps float64) {
+	l.limiter.SetLimit(rate.Limit(tps))
+}
+
+// WithGate sets a gate that is waited on before each transaction is sent
+func (l *LongSender) WithGate(gate Gate) *LongSender {
+	l.gate = gate
+	return l
+}
+
+// WithBudget sets a budget that must admit each transaction; workers stop once it refuses
+func (l *LongSender) WithBudget(budget Budget) *LongSender {
+	l.budget = budget
+	return l
+}
+
 // WithCallbacks sets the callbacks for metrics integration
 func (l *LongSender) WithCallbacks(callbacks *Callbacks) *LongSender {
 	l.callbacks = callbacks
@@ -97,6 +148,8 @@ func (l *LongSender) Run(ctx context.Context, keys []*ecdsa.PrivateKey, initialN
 	l.keys = keys
 	l.addresses = make([]common.Address, len(keys))
 	l.nonces = make([]atomic.Uint64, len(keys))
+	l.gaps = make([]atomic.Uint64, len(keys))
+	l.health = make([]accountHealth, len(keys))
 
 	for i, key := range keys {
 		l.addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
@@ -143,21 +196,35 @@ func (l *LongSender) Run(ctx context.Context, keys []*ecdsa.PrivateKey, initialN
 	sent := l.sentCount.Load()
 	failed := l.failedCount.Load()
 
-	avgTPS := float64(0)
+	bytes := l.sentBytes.Load()
+
+	avgTPS, bytesPerSec := float64(0), float64(0)
 	if duration.Seconds() > 0 {
 		avgTPS = float64(sent) / duration.Seconds()
+		bytesPerSec = float64(bytes) / duration.Seconds()
 	}
 
-	return &Result{
+	result := &Result{
 		TotalSent:     sent,
 		TotalFailed:   failed,
 		TotalDuration: duration,
 		AverageTPS:    avgTPS,
 		ActualTPS:     avgTPS,
+		TotalBytes:    bytes,
+		BytesPerSec:   bytesPerSec,
 		Errors:        l.errors,
-	}, nil
+		Quarantined:   l.Quarantined(),
+		NextNonces:    l.nextNonces(),
+	}
+	if len(result.Quarantined) == len(keys) {
+		return result, ErrAllQuarantined
+	}
+	return result, nil
 }
 
+// errBudgetRefused marks a transaction that was not sent because the budget refused it
+var errBudgetRefused = errors.New("budget refused transaction")
+
 // worker is a goroutine that continuously sends transactions
 func (l *LongSender) worker(ctx context.Context, wg *sync.WaitGroup, _ int) {
 	defer wg.Done()
@@ -167,6 +234,13 @@ func (l *LongSender) worker(ctx context.Context, wg *sync.WaitGroup, _ int) {
 		case <-ctx.Done():
 			return
 		default:
+			// Pause while the gate is closed
+			if l.gate != nil {
+				if err := l.gate.Wait(ctx); err != nil {
+					return
+				}
+			}
+
 			// Wait for rate limiter
 			if err := l.limiter.Wait(ctx); err != nil {
 				if ctx.Err() != nil {
@@ -175,11 +249,19 @@ func (l *LongSender) worker(ctx context.Context, wg *sync.WaitGroup, _ int) {
 				continue
 			}
 
-			// Round-robin account selection
-			accountIdx := int(l.sentCount.Load()+l.failedCount.Load()) % len(l.keys)
+			// Round-robin selection over the accounts not quarantined
+			accountIdx := l.nextAccount()
+			if accountIdx < 0 {
+				return
+			}
 
 			// Send transaction
-			if err := l.sendTransaction(ctx, accountIdx); err != nil {
+			err := l.sendTransaction(ctx, accountIdx)
+			if errors.Is(err, errBudgetRefused) {
+				return
+			}
+			l.recordOutcome(accountIdx, err)
+			if err != nil {
 				l.failedCount.Add(1)
 				l.recordError(err)
 				if l.callbacks != nil && l.callbacks.OnFailed != nil {
@@ -191,20 +273,30 @@ func (l *LongSender) worker(ctx context.Context, wg *sync.WaitGroup, _ int) {
 }
 
 // sendTransaction creates and sends a single transaction
-func (l *LongSender) sendTransaction(ctx context.Context, accountIdx int) error {
+func (l *LongSender) sendTransaction(ctx context.Context, accountIdx int) (err error) {
 	key := l.keys[accountIdx]
 	from := l.addresses[accountIdx]
 	nonce := l.getNonceAndIncrement(accountIdx)
+	defer func() {
+		if err != nil {
+			l.recordGap(accountIdx, nonce)
+		}
+	}()
+
+	to := from
+	if accountIdx < len(l.recipients) {
+		to = l.recipients[accountIdx]
+	}
 
-	// Create transaction (self-transfer)
+	// Create transaction (self-transfer unless recipients are set)
 	tx := types.NewTx(&types.DynamicFeeTx{
 		ChainID:   l.chainID,
 		Nonce:     nonce,
 		GasTipCap: l.gasPrice,
 		GasFeeCap: new(big.Int).Mul(l.gasPrice, big.NewInt(2)),
 		Gas:       l.gasLimit,
-		To:        &from, // Self-transfer
-		Value:     big.NewInt(0),
+		To:        &to,
+		Value:     l.value,
 		Data:      nil,
 	})
 
@@ -215,15 +307,25 @@ func (l *LongSender) sendTransaction(ctx context.Context, accountIdx int) error
 		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
+	if l.budget != nil {
+		if err := l.budget.Reserve(signedTx); err != nil {
+			return fmt.Errorf("%w: %w", errBudgetRefused, err)
+		}
+	}
+
 	// Send transaction
 	err = l.client.SendTransaction(ctx, signedTx)
 	if err != nil {
+		if l.budget != nil {
+			l.budget.Release(signedTx)
+		}
 		// On error, we might need to refresh nonce
 		// For simplicity, we just return the error
 		return fmt.Errorf("failed to send transaction: %w", err)
 	}
 
 	l.sentCount.Add(1)
+	l.sentBytes.Add(int64(signedTx.Size()))
 
 	if l.callbacks != nil {
 		if l.callbacks.OnSent != nil {
@@ -242,6 +344,34 @@ func (l *LongSender) getNonceAndIncrement(accountIdx int) uint64 {
 	return l.nonces[accountIdx].Add(1) - 1
 }
 
+// recordGap remembers a nonce of an account that was not sent. Later nonces
+// of the account cannot be mined until it is filled.
+func (l *LongSender) recordGap(accountIdx int, nonce uint64) {
+	gap := &l.gaps[accountIdx]
+	for {
+		current := gap.Load()
+		if current != 0 && current <= nonce+1 {
+			return
+		}
+		if gap.CompareAndSwap(current, nonce+1) {
+			return
+		}
+	}
+}
+
+// nextNonces returns the next usable nonce of each account: its lowest
+// unsent nonce, or one past the last nonce it sent
+func (l *LongSender) nextNonces() []uint64 {
+	nonces := make([]uint64, len(l.keys))
+	for i := range nonces {
+		nonces[i] = l.nonces[i].Load()
+		if gap := l.gaps[i].Load(); gap != 0 && gap-1 < nonces[i] {
+			nonces[i] = gap - 1
+		}
+	}
+	return nonces
+}
+
 // getCurrentTPS calculates the current TPS
 func (l *LongSender) getCurrentTPS() float64 {
 	elapsed := time.Since(l.startTime).Seconds()
@@ -0,0 +1,63 @@
+package longsender
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/budget"
+)
+
+// acceptingClient accepts every transaction
+type acceptingClient struct{}
+
+func (acceptingClient) SendTransaction(context.Context, *types.Transaction) error {
+	return nil
+}
+
+func (acceptingClient) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (acceptingClient) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (acceptingClient) ChainID(context.Context) (*big.Int, error) {
+	return big.NewInt(1337), nil
+}
+
+func TestLongSender_Budget(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+
+	// Gas price 1 and the default gas limit: 21000 × 2 wei max fee per tx
+	b := budget.New(big.NewInt(5 * 42000))
+	sender := New(acceptingClient{}, &Config{Duration: 2 * time.Second, TPS: 1000, Burst: 10, Workers: 2}).WithBudget(b)
+
+	result, err := sender.Run(context.Background(), keys, make([]uint64, len(keys)))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.TotalSent != 5 || result.TotalFailed != 0 {
+		t.Errorf("sent=%d failed=%d, want 5/0 within the budget", result.TotalSent, result.TotalFailed)
+	}
+	if result.TotalDuration >= 2*time.Second {
+		t.Errorf("TotalDuration = %s, want the workers to stop at the budget", result.TotalDuration)
+	}
+	if b.Stopped() == nil {
+		t.Error("Stopped() = nil, want the budget stop recorded")
+	}
+}
@@ -0,0 +1,93 @@
+package longsender
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrAllQuarantined stops a run once every account is quarantined
+var ErrAllQuarantined = errors.New("all accounts quarantined")
+
+// Quarantine is an account taken out of the rotation after repeated failures
+type Quarantine struct {
+	Account   common.Address
+	Failures  int    // Consecutive failures that triggered the quarantine
+	LastError string // Error of the last failure
+	Sent      int64  // Transactions the account sent before
+	At        time.Time
+}
+
+// nextAccount returns the next account in round-robin order that is not
+// quarantined, or -1 once all are
+func (l *LongSender) nextAccount() int {
+	start := int(l.cursor.Add(1)-1) % len(l.keys)
+	for i := 0; i < len(l.keys); i++ {
+		idx := (start + i) % len(l.keys)
+		if !l.health[idx].quarantined.Load() {
+			return idx
+		}
+	}
+	return -1
+}
+
+// recordOutcome tracks the consecutive account failures of an account and
+// quarantines it once they reach QuarantineAfter. Its share of the load then
+// goes to the remaining accounts. Errors that say nothing about the account,
+// such as a timeout or an overloaded node, are not counted.
+func (l *LongSender) recordOutcome(accountIdx int, err error) {
+	h := &l.health[accountIdx]
+	if err == nil {
+		h.streak.Store(0)
+		h.sent.Add(1)
+		return
+	}
+	if l.config.QuarantineAfter <= 0 || !isAccountError(err) {
+		return
+	}
+	streak := h.streak.Add(1)
+	if int(streak) < l.config.QuarantineAfter || !h.quarantined.CompareAndSwap(false, true) {
+		return
+	}
+
+	q := Quarantine{
+		Account:   l.addresses[accountIdx],
+		Failures:  int(streak),
+		LastError: err.Error(),
+		Sent:      h.sent.Load(),
+		At:        time.Now(),
+	}
+	l.quarantineMu.Lock()
+	l.quarantined = append(l.quarantined, q)
+	l.quarantineMu.Unlock()
+
+	if l.callbacks != nil && l.callbacks.OnQuarantine != nil {
+		l.callbacks.OnQuarantine(q)
+	}
+}
+
+// isAccountError reports whether a node refused a transaction because of the
+// state of its sender: a nonce already used, a balance too low to pay for it,
+// or a pending transaction at its nonce it does not outbid
+func isAccountError(err error) bool {
+	// Sends cut off by the end of the run say nothing about the account
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "insufficient funds") ||
+		strings.Contains(msg, "replacement transaction underpriced") ||
+		strings.Contains(msg, "replacement fee too low") ||
+		strings.Contains(msg, "underpriced replacement")
+}
+
+// Quarantined returns the accounts quarantined so far, in quarantine order
+func (l *LongSender) Quarantined() []Quarantine {
+	l.quarantineMu.Lock()
+	defer l.quarantineMu.Unlock()
+	return append([]Quarantine(nil), l.quarantined...)
+}
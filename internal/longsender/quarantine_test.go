@@ -0,0 +1,161 @@
+package longsender
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// failingClient refuses every transaction from the accounts in failing
+type failingClient struct {
+	mu      sync.Mutex
+	failing map[common.Address]bool
+	sent    map[common.Address]int
+	err     error // Refusal error (default insufficient funds)
+}
+
+func (c *failingClient) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failing[from] {
+		if c.err != nil {
+			return c.err
+		}
+		return errors.New("insufficient funds for gas * price + value")
+	}
+	c.sent[from]++
+	return nil
+}
+
+func (c *failingClient) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (c *failingClient) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (c *failingClient) ChainID(context.Context) (*big.Int, error) {
+	return big.NewInt(1337), nil
+}
+
+func newKeys(t *testing.T, n int) ([]*ecdsa.PrivateKey, []uint64) {
+	t.Helper()
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	return keys, make([]uint64, n)
+}
+
+func TestLongSender_Quarantine(t *testing.T) {
+	keys, nonces := newKeys(t, 3)
+	bad := crypto.PubkeyToAddress(keys[1].PublicKey)
+	client := &failingClient{failing: map[common.Address]bool{bad: true}, sent: map[common.Address]int{}}
+
+	var quarantined []Quarantine
+	var mu sync.Mutex
+	sender := New(client, &Config{Duration: 200 * time.Millisecond, TPS: 1000, Burst: 10, Workers: 1, QuarantineAfter: 3}).
+		WithCallbacks(&Callbacks{OnQuarantine: func(q Quarantine) {
+			mu.Lock()
+			quarantined = append(quarantined, q)
+			mu.Unlock()
+		}})
+
+	result, err := sender.Run(context.Background(), keys, nonces)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.TotalFailed != 3 {
+		t.Errorf("TotalFailed = %d, want 3 before the quarantine", result.TotalFailed)
+	}
+	if len(result.Quarantined) != 1 || result.Quarantined[0].Account != bad || result.Quarantined[0].Failures != 3 {
+		t.Fatalf("Quarantined = %+v, want the failing account after 3 failures", result.Quarantined)
+	}
+	if len(quarantined) != 1 {
+		t.Errorf("OnQuarantine calls = %d, want 1", len(quarantined))
+	}
+
+	// The failing account's first nonce was never sent
+	if result.NextNonces[1] != 0 {
+		t.Errorf("NextNonces[1] = %d, want 0 at the first unsent nonce", result.NextNonces[1])
+	}
+
+	// The healthy accounts took over its share
+	good := client.sent[crypto.PubkeyToAddress(keys[0].PublicKey)] + client.sent[crypto.PubkeyToAddress(keys[2].PublicKey)]
+	if int64(good) != result.TotalSent {
+		t.Errorf("healthy accounts sent %d, want all %d", good, result.TotalSent)
+	}
+}
+
+func TestLongSender_AllQuarantined(t *testing.T) {
+	keys, nonces := newKeys(t, 2)
+	failing := map[common.Address]bool{}
+	for _, key := range keys {
+		failing[crypto.PubkeyToAddress(key.PublicKey)] = true
+	}
+	client := &failingClient{failing: failing, sent: map[common.Address]int{}}
+
+	sender := New(client, &Config{Duration: 5 * time.Second, TPS: 1000, Burst: 10, Workers: 2, QuarantineAfter: 2})
+	result, err := sender.Run(context.Background(), keys, nonces)
+	if !errors.Is(err, ErrAllQuarantined) {
+		t.Fatalf("Run() error = %v, want ErrAllQuarantined", err)
+	}
+	if len(result.Quarantined) != 2 || result.TotalDuration >= 5*time.Second {
+		t.Errorf("result = %+v, want both accounts quarantined well before the duration", result)
+	}
+}
+
+func TestLongSender_Quarantine_OnlyAccountErrors(t *testing.T) {
+	keys, nonces := newKeys(t, 2)
+	bad := crypto.PubkeyToAddress(keys[1].PublicKey)
+	client := &failingClient{
+		failing: map[common.Address]bool{bad: true},
+		sent:    map[common.Address]int{},
+		err:     errors.New("request timed out"),
+	}
+
+	sender := New(client, &Config{Duration: 100 * time.Millisecond, TPS: 1000, Burst: 10, Workers: 1, QuarantineAfter: 2})
+	result, err := sender.Run(context.Background(), keys, nonces)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Quarantined) != 0 || result.TotalFailed < 2 {
+		t.Errorf("Quarantined = %+v after %d failures, want none for timeouts", result.Quarantined, result.TotalFailed)
+	}
+}
+
+func TestIsAccountError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("nonce too low"), true},
+		{errors.New("insufficient funds for gas * price + value"), true},
+		{errors.New("replacement transaction underpriced"), true},
+		{errors.New("connection refused"), false},
+		{errors.New("txpool is full"), false},
+		{context.DeadlineExceeded, false},
+	}
+	for _, tt := range tests {
+		if got := isAccountError(tt.err); got != tt.want {
+			t.Errorf("isAccountError(%q) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package longsender
 import (
 	"context"
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -21,12 +22,31 @@ type SendClient interface {
 	ChainID(ctx context.Context) (*big.Int, error)
 }
 
+// Gate pauses sending while closed (e.g. while the chain is halted)
+type Gate interface {
+	// Wait blocks until sending may proceed
+	Wait(ctx context.Context) error
+}
+
+// Budget admits transactions while spending stays within a limit
+type Budget interface {
+	// Reserve sets aside the max fee of the transactions, or fails if that would exceed the limit
+	Reserve(txs ...*types.Transaction) error
+	// Release returns the reservation of transactions that were not sent
+	Release(txs ...*types.Transaction)
+}
+
 // Config holds configuration for the LongSender
 type Config struct {
 	Duration time.Duration // Total test duration (0 = run until canceled)
 	TPS      float64       // Target transactions per second
 	Burst    int           // Rate limiter burst size
 	Workers  int           // Number of concurrent workers
+
+	// QuarantineAfter takes an account out of the rotation after this many
+	// consecutive account failures: nonce too low, insufficient funds or an
+	// underpriced replacement (0 = never)
+	QuarantineAfter int
 }
 
 // DefaultConfig returns default LongSender configuration
@@ -46,7 +66,18 @@ type Result struct {
 	TotalDuration time.Duration
 	AverageTPS    float64
 	ActualTPS     float64
+	TotalBytes    int64   // Raw bytes of the successfully sent transactions
+	BytesPerSec   float64 // TotalBytes over TotalDuration
 	Errors        []error
+	Quarantined   []Quarantine // Accounts taken out of the rotation, in order
+	NextNonces    []uint64     // Next nonce of each key after the run, in key order
+}
+
+// accountHealth tracks the send failures of one account
+type accountHealth struct {
+	streak      atomic.Int32 // Consecutive failures
+	sent        atomic.Int64
+	quarantined atomic.Bool
 }
 
 // Callbacks for metrics integration
@@ -55,4 +86,7 @@ type Callbacks struct {
 	OnFailed  func(err error)
 	OnTPS     func(currentTPS float64)
 	OnMetrics func(sent, failed int64, tps float64)
+
+	// OnQuarantine is called when an account is taken out of the rotation
+	OnQuarantine func(q Quarantine)
 }
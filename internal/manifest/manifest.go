@@ -0,0 +1,129 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Manifest lists the artifacts of one run with their SHA-256 hashes, so an
+// archived result set can later be checked for tampering or corruption
+type Manifest struct {
+	Tool      string     `json:"tool"`
+	Version   string     `json:"version"`
+	Commit    string     `json:"commit"`
+	Mode      string     `json:"mode,omitempty"`
+	ChainID   uint64     `json:"chain_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// Artifact is one file of a run
+type Artifact struct {
+	Path   string `json:"path"` // Relative to the manifest's directory
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Problem is an artifact that no longer matches its manifest entry
+type Problem struct {
+	Path   string
+	Reason string
+}
+
+// New creates an empty manifest for a build of txhammer
+func New(version, commit string) *Manifest {
+	return &Manifest{
+		Tool:      "txhammer",
+		Version:   version,
+		Commit:    commit,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Write hashes each file and writes the manifest to path. Artifact paths are
+// stored relative to the manifest so the directory can be moved as a whole.
+func (m *Manifest) Write(path string, files []string) error {
+	dir := filepath.Dir(path)
+	m.Artifacts = make([]Artifact, 0, len(files))
+	for _, file := range files {
+		size, sum, err := hashFile(file)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return fmt.Errorf("artifact %s is not reachable from %s: %w", file, dir, err)
+		}
+		m.Artifacts = append(m.Artifacts, Artifact{Path: filepath.ToSlash(rel), Size: size, SHA256: sum})
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// Load reads a manifest file
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Verify re-hashes every artifact of the manifest at path and returns those
+// that are missing or changed. An empty result means the run is intact.
+func Verify(path string) (*Manifest, []Problem, error) {
+	m, err := Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dir := filepath.Dir(path)
+	var problems []Problem
+	for _, a := range m.Artifacts {
+		size, sum, err := hashFile(filepath.Join(dir, filepath.FromSlash(a.Path)))
+		switch {
+		case os.IsNotExist(err):
+			problems = append(problems, Problem{Path: a.Path, Reason: "missing"})
+		case err != nil:
+			problems = append(problems, Problem{Path: a.Path, Reason: err.Error()})
+		case size != a.Size:
+			problems = append(problems, Problem{Path: a.Path, Reason: fmt.Sprintf("size %d, manifest says %d", size, a.Size)})
+		case sum != a.SHA256:
+			problems = append(problems, Problem{Path: a.Path, Reason: "SHA-256 mismatch"})
+		}
+	}
+	return m, problems, nil
+}
+
+// hashFile returns the size and hex SHA-256 of a file
+func hashFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,81 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifest_WriteVerify(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	report := write("report.json", `{"tps": 100}`)
+	txs := write("transactions.csv", "hash,status\n0x01,CONFIRMED\n")
+	blocks := write("blocks.csv", "number\n10\n")
+
+	path := filepath.Join(dir, "manifest.json")
+	m := New("v1.2.3", "abc1234")
+	m.Mode = "TRANSFER"
+	if err := m.Write(path, []string{report, txs, blocks}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	loaded, problems, err := Verify(path)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("Verify() problems on an untouched run: %v", problems)
+	}
+	if loaded.Version != "v1.2.3" || loaded.Commit != "abc1234" || len(loaded.Artifacts) != 3 {
+		t.Fatalf("loaded manifest = %+v", loaded)
+	}
+	// sha256("number\n10\n")
+	if got := loaded.Artifacts[2]; got.Path != "blocks.csv" || got.Size != 10 ||
+		got.SHA256 != "a2c754c5fe647329d8f76a40808049999ecdc6e1b318b4ba385bf20c6f426608" {
+		t.Errorf("blocks artifact = %+v", got)
+	}
+
+	// Same size, different content; a changed size; a removed file
+	write("report.json", `{"tps": 999}`)
+	write("transactions.csv", "hash,status\n")
+	if err := os.Remove(blocks); err != nil {
+		t.Fatal(err)
+	}
+	_, problems, err = Verify(path)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := map[string]string{
+		"report.json":      "SHA-256 mismatch",
+		"transactions.csv": "size 12, manifest says 27",
+		"blocks.csv":       "missing",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("problems = %v, want %d", problems, len(want))
+	}
+	for _, p := range problems {
+		if want[p.Path] != p.Reason {
+			t.Errorf("%s: %q, want %q", p.Path, p.Reason, want[p.Path])
+		}
+	}
+}
+
+func TestVerify_InvalidManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if _, _, err := Verify(path); err == nil {
+		t.Error("Verify() should fail without a manifest")
+	}
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Verify(path); err == nil {
+		t.Error("Verify() should fail on an invalid manifest")
+	}
+}
@@ -0,0 +1,93 @@
+// Package mempool snapshots the node's transaction pool through txpool_content
+// and measures the impact of a run on transactions from other senders.
+package mempool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// poolEntry is the part of a txpool_content transaction the snapshot keeps
+type poolEntry struct {
+	Hash  common.Hash    `json:"hash"`
+	From  common.Address `json:"from"`
+	Nonce hexutil.Uint64 `json:"nonce"`
+}
+
+// poolContent is the txpool_content result: sender -> nonce -> transaction
+type poolContent struct {
+	Pending map[common.Address]map[string]*poolEntry `json:"pending"`
+	Queued  map[common.Address]map[string]*poolEntry `json:"queued"`
+}
+
+// Take snapshots the pool content
+func Take(ctx context.Context, client Client) (*Snapshot, error) {
+	var content poolContent
+	if err := client.CallContext(ctx, &content, "txpool_content"); err != nil {
+		return nil, fmt.Errorf("txpool_content failed: %w", err)
+	}
+
+	snap := &Snapshot{Taken: time.Now(), Txs: make(map[common.Hash]*PoolTx)}
+	add := func(pool map[common.Address]map[string]*poolEntry, pending bool) {
+		for from, txs := range pool {
+			for _, tx := range txs {
+				if tx == nil {
+					continue
+				}
+				snap.Txs[tx.Hash] = &PoolTx{Hash: tx.Hash, From: from, Nonce: uint64(tx.Nonce), Pending: pending}
+			}
+		}
+	}
+	add(content.Pending, true)
+	add(content.Queued, false)
+	return snap, nil
+}
+
+// Compare relates the pool before and after a run. ours reports whether a
+// sender belongs to the run. Foreign txs that left the pool are looked up to
+// tell mined ones from displaced ones.
+func Compare(ctx context.Context, client Client, before, after *Snapshot, ours func(common.Address) bool) (*Impact, error) {
+	impact := &Impact{}
+	for hash, tx := range before.Txs {
+		if ours(tx.From) {
+			impact.OursBefore++
+			continue
+		}
+		impact.ForeignBefore++
+		if _, ok := after.Txs[hash]; ok {
+			impact.Delayed++
+			continue
+		}
+
+		_, err := client.TransactionReceipt(ctx, hash)
+		switch {
+		case err == nil:
+			impact.Included++
+		case errors.Is(err, ethereum.NotFound):
+			impact.Displaced++
+			if len(impact.DisplacedTxs) < DisplacedExamples {
+				impact.DisplacedTxs = append(impact.DisplacedTxs, hash)
+			}
+		default:
+			return nil, fmt.Errorf("failed to get receipt of %s: %w", hash.Hex(), err)
+		}
+	}
+
+	for hash, tx := range after.Txs {
+		if ours(tx.From) {
+			impact.OursAfter++
+			continue
+		}
+		impact.ForeignAfter++
+		if _, ok := before.Txs[hash]; !ok {
+			impact.Arrived++
+		}
+	}
+	return impact, nil
+}
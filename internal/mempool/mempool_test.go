@@ -0,0 +1,121 @@
+package mempool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// mockClient serves a fixed txpool_content document and knows which txs were mined
+type mockClient struct {
+	content string
+	mined   map[common.Hash]bool
+	err     error
+}
+
+func (m *mockClient) CallContext(_ context.Context, result any, method string, _ ...any) error {
+	if m.err != nil {
+		return m.err
+	}
+	if method != "txpool_content" {
+		return fmt.Errorf("unexpected method %s", method)
+	}
+	return json.Unmarshal([]byte(m.content), result)
+}
+
+func (m *mockClient) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	if m.mined[hash] {
+		return &types.Receipt{TxHash: hash}, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+var (
+	foreign = common.HexToAddress("0xf0")
+	ours    = common.HexToAddress("0x01")
+)
+
+func hash(n int) common.Hash { return common.BigToHash(big.NewInt(int64(n))) }
+
+// content renders a txpool_content document with pending txs only
+func content(txs ...*PoolTx) string {
+	pending := make(map[string]map[string]map[string]string)
+	for _, tx := range txs {
+		from := tx.From.Hex()
+		if pending[from] == nil {
+			pending[from] = make(map[string]map[string]string)
+		}
+		pending[from][fmt.Sprint(tx.Nonce)] = map[string]string{
+			"hash":  tx.Hash.Hex(),
+			"from":  from,
+			"nonce": fmt.Sprintf("0x%x", tx.Nonce),
+		}
+	}
+	data, _ := json.Marshal(map[string]any{"pending": pending, "queued": map[string]any{}})
+	return string(data)
+}
+
+func TestTake(t *testing.T) {
+	client := &mockClient{content: `{
+		"pending": {"0x00000000000000000000000000000000000000f0": {"7": {"hash": "0x0000000000000000000000000000000000000000000000000000000000000001", "from": "0x00000000000000000000000000000000000000f0", "nonce": "0x7"}}},
+		"queued": {"0x0000000000000000000000000000000000000001": {"9": {"hash": "0x0000000000000000000000000000000000000000000000000000000000000002", "from": "0x0000000000000000000000000000000000000001", "nonce": "0x9"}}}
+	}`}
+
+	snap, err := Take(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if len(snap.Txs) != 2 {
+		t.Fatalf("got %d txs, want 2", len(snap.Txs))
+	}
+	pending := snap.Txs[common.HexToHash("0x01")]
+	if pending == nil || !pending.Pending || pending.From != foreign || pending.Nonce != 7 {
+		t.Errorf("pending tx = %+v", pending)
+	}
+	if queued := snap.Txs[common.HexToHash("0x02")]; queued == nil || queued.Pending || queued.Nonce != 9 {
+		t.Errorf("queued tx = %+v", queued)
+	}
+
+	client.err = errors.New("the method txpool_content does not exist")
+	if _, err := Take(context.Background(), client); err == nil || !strings.Contains(err.Error(), "txpool_content") {
+		t.Errorf("Take() error = %v, want txpool_content failure", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ctx := context.Background()
+	tx := func(n int, from common.Address) *PoolTx { return &PoolTx{Hash: hash(n), From: from, Nonce: uint64(n)} }
+
+	client := &mockClient{content: content(tx(1, foreign), tx(2, foreign), tx(3, foreign), tx(4, ours))}
+	before, err := Take(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1 was mined, 2 displaced, 3 still waiting; 5 arrived; our 4 was mined and 6 is pending
+	client.content = content(tx(3, foreign), tx(5, foreign), tx(6, ours))
+	client.mined = map[common.Hash]bool{hash(1): true, hash(4): true}
+	after, err := Take(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	impact, err := Compare(ctx, client, before, after, func(addr common.Address) bool { return addr == ours })
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	want := Impact{
+		ForeignBefore: 3, ForeignAfter: 2, Included: 1, Displaced: 1, Delayed: 1, Arrived: 1,
+		OursBefore: 1, OursAfter: 1, DisplacedTxs: []common.Hash{hash(2)},
+	}
+	if fmt.Sprint(*impact) != fmt.Sprint(want) {
+		t.Errorf("Compare() = %+v, want %+v", *impact, want)
+	}
+}
@@ -0,0 +1,50 @@
+package mempool
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Client defines the interface for reading the node's transaction pool
+type Client interface {
+	// CallContext performs a raw JSON-RPC call
+	CallContext(ctx context.Context, result any, method string, args ...any) error
+	// TransactionReceipt returns the receipt of a mined transaction
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// PoolTx is a transaction seen in the pool
+type PoolTx struct {
+	Hash    common.Hash
+	From    common.Address
+	Nonce   uint64
+	Pending bool // Executable; false if queued behind a nonce gap
+}
+
+// Snapshot is the content of the pool at one point in time
+type Snapshot struct {
+	Taken time.Time
+	Txs   map[common.Hash]*PoolTx
+}
+
+// Impact describes how foreign transactions, those not sent from our
+// accounts, fared in the pool over the run
+type Impact struct {
+	ForeignBefore int `json:"foreign_before"` // Foreign txs in the pool before the run
+	ForeignAfter  int `json:"foreign_after"`  // Foreign txs in the pool after the run
+	Included      int `json:"included"`       // Foreign txs from before that were mined during the run
+	Displaced     int `json:"displaced"`      // Foreign txs from before that left the pool unmined
+	Delayed       int `json:"delayed"`        // Foreign txs from before still in the pool after the run
+	Arrived       int `json:"arrived"`        // Foreign txs that entered the pool during the run and are still in it
+	OursBefore    int `json:"ours_before"`    // Our txs in the pool before the run, e.g. left over from an earlier one
+	OursAfter     int `json:"ours_after"`     // Our txs still in the pool after the run
+
+	// Up to DisplacedExamples displaced foreign txs
+	DisplacedTxs []common.Hash `json:"displaced_txs,omitempty"`
+}
+
+// DisplacedExamples is the number of displaced txs listed in an Impact
+const DisplacedExamples = 5
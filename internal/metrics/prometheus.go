@@ -10,6 +10,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
 )
 
 // Metrics holds all Prometheus metrics for txhammer
@@ -28,6 +31,7 @@ type Metrics struct {
 	ConfirmedTPS   prometheus.Gauge
 	PendingTxCount prometheus.Gauge
 	SendRate       prometheus.Gauge
+	ChainHalted    prometheus.Gauge
 
 	// Gas metrics
 	GasUsedTotal prometheus.Counter
@@ -35,71 +39,144 @@ type Metrics struct {
 	// Pipeline stage duration histogram
 	StageDuration *prometheus.HistogramVec
 
+	// End-of-run summary gauges, set once when the run finishes
+	RunDuration   prometheus.Gauge
+	RunFinalTPS   prometheus.Gauge
+	RunSuccess    prometheus.Gauge
+	RunP95Latency prometheus.Gauge
+	RunGasUsed    prometheus.Gauge
+
+	// Constant 1, labeled with the txhammer build
+	BuildInfo *prometheus.GaugeVec
+
+	// Constant labels on the live metrics. The pushed summary gauges get
+	// them as Pushgateway grouping instead, which rejects duplicates.
+	labels prometheus.Labels
+
 	// HTTP server
 	server *http.Server
 	mu     sync.Mutex
 }
 
-// NewMetrics creates a new Metrics instance with the given namespace
-func NewMetrics(namespace string) *Metrics {
+// RegionLabels returns the constant labels tagging metrics with the region
+// or worker that produced them (nil when region is empty)
+func RegionLabels(region string) prometheus.Labels {
+	if region == "" {
+		return nil
+	}
+	return prometheus.Labels{"region": region}
+}
+
+// NewMetrics creates a new Metrics instance with the given namespace.
+// labels (may be nil) are attached to the live metrics and used as the
+// grouping key of PushRunSummary.
+func NewMetrics(namespace string, labels prometheus.Labels) *Metrics {
 	m := &Metrics{
+		labels: labels,
 		TxSent: promauto.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "tx_sent_total",
-			Help:      "Total number of transactions sent",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "tx_sent_total",
+			Help:        "Total number of transactions sent",
 		}),
 		TxConfirmed: promauto.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "tx_confirmed_total",
-			Help:      "Total number of transactions confirmed",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "tx_confirmed_total",
+			Help:        "Total number of transactions confirmed",
 		}),
 		TxFailed: promauto.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "tx_failed_total",
-			Help:      "Total number of transactions failed",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "tx_failed_total",
+			Help:        "Total number of transactions failed",
 		}),
 		TxTimeout: promauto.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "tx_timeout_total",
-			Help:      "Total number of transactions timed out",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "tx_timeout_total",
+			Help:        "Total number of transactions timed out",
 		}),
 		TxLatency: promauto.NewHistogram(prometheus.HistogramOpts{
-			Namespace: namespace,
-			Name:      "tx_latency_seconds",
-			Help:      "Transaction confirmation latency in seconds",
-			Buckets:   []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60},
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "tx_latency_seconds",
+			Help:        "Transaction confirmation latency in seconds",
+			Buckets:     []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60},
 		}),
 		CurrentTPS: promauto.NewGauge(prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "current_tps",
-			Help:      "Current transactions per second (send rate)",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "current_tps",
+			Help:        "Current transactions per second (send rate)",
 		}),
 		ConfirmedTPS: promauto.NewGauge(prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "confirmed_tps",
-			Help:      "Confirmed transactions per second",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "confirmed_tps",
+			Help:        "Confirmed transactions per second",
 		}),
 		PendingTxCount: promauto.NewGauge(prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "pending_tx_count",
-			Help:      "Number of pending (unconfirmed) transactions",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "pending_tx_count",
+			Help:        "Number of pending (unconfirmed) transactions",
 		}),
 		SendRate: promauto.NewGauge(prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "send_rate",
-			Help:      "Current send rate in transactions per second",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "send_rate",
+			Help:        "Current send rate in transactions per second",
+		}),
+		ChainHalted: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "chain_halted",
+			Help:        "1 while no new blocks are observed within the halt window, 0 otherwise",
 		}),
 		GasUsedTotal: promauto.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
-			Name:      "gas_used_total",
-			Help:      "Total gas used by confirmed transactions",
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "gas_used_total",
+			Help:        "Total gas used by confirmed transactions",
 		}),
 		StageDuration: promauto.NewHistogramVec(prometheus.HistogramOpts{
-			Namespace: namespace,
-			Name:      "stage_duration_seconds",
-			Help:      "Duration of each pipeline stage in seconds",
-			Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300},
+			Namespace:   namespace,
+			ConstLabels: labels,
+			Name:        "stage_duration_seconds",
+			Help:        "Duration of each pipeline stage in seconds",
+			Buckets:     []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300},
 		}, []string{"stage"}),
+		RunDuration: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "run_duration_seconds",
+			Help:      "Wall-clock duration of the finished run in seconds",
+		}),
+		RunFinalTPS: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "run_final_tps",
+			Help:      "Chain TPS of the finished run",
+		}),
+		RunSuccess: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "run_success_rate",
+			Help:      "Fraction of the run's transactions confirmed successfully (0-1)",
+		}),
+		RunP95Latency: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "run_p95_latency_seconds",
+			Help:      "95th percentile confirmation latency of the finished run in seconds",
+		}),
+		RunGasUsed: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "run_gas_used",
+			Help:      "Total gas used by the finished run",
+		}),
+		BuildInfo: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "build_info",
+			Help:      "Always 1, labeled with the version, commit, build date and Go version of txhammer",
+		}, []string{"version", "commit", "date", "goversion"}),
 	}
 
 	return m
@@ -125,7 +202,7 @@ func (m *Metrics) Start(_ context.Context, port int) error {
 
 	go func() {
 		if err := m.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Printf("Metrics server error: %v\n", err)
+			console.Warnf("Metrics server error: %v\n", err)
 		}
 	}()
 
@@ -192,6 +269,15 @@ func (m *Metrics) SetSendRate(rate float64) {
 	m.SendRate.Set(rate)
 }
 
+// SetChainHalted sets the chain halted gauge
+func (m *Metrics) SetChainHalted(halted bool) {
+	if halted {
+		m.ChainHalted.Set(1)
+		return
+	}
+	m.ChainHalted.Set(0)
+}
+
 // RecordGasUsed adds to the total gas used counter
 func (m *Metrics) RecordGasUsed(gasUsed uint64) {
 	m.GasUsedTotal.Add(float64(gasUsed))
@@ -202,6 +288,51 @@ func (m *Metrics) RecordStageDuration(stage string, duration time.Duration) {
 	m.StageDuration.WithLabelValues(stage).Observe(duration.Seconds())
 }
 
+// RecordBuildInfo sets the build_info gauge for the running binary
+func (m *Metrics) RecordBuildInfo(version, commit, date, goVersion string) {
+	m.BuildInfo.Reset()
+	m.BuildInfo.WithLabelValues(version, commit, date, goVersion).Set(1)
+}
+
+// RunSummary is the outcome of a finished run
+type RunSummary struct {
+	Duration    time.Duration
+	FinalTPS    float64
+	SuccessRate float64 // Confirmed share of the transactions, 0-1
+	P95Latency  time.Duration
+	GasUsed     uint64
+}
+
+// RecordRunSummary sets the end-of-run summary gauges
+func (m *Metrics) RecordRunSummary(s RunSummary) {
+	m.RunDuration.Set(s.Duration.Seconds())
+	m.RunFinalTPS.Set(s.FinalTPS)
+	m.RunSuccess.Set(s.SuccessRate)
+	m.RunP95Latency.Set(s.P95Latency.Seconds())
+	m.RunGasUsed.Set(float64(s.GasUsed))
+}
+
+// PushRunSummary pushes the end-of-run summary gauges and build info to a Pushgateway,
+// replacing the previous push of the job
+func (m *Metrics) PushRunSummary(ctx context.Context, url, job string) error {
+	pusher := push.New(url, job)
+	for name, value := range m.labels {
+		pusher = pusher.Grouping(name, value)
+	}
+	err := pusher.
+		Collector(m.RunDuration).
+		Collector(m.RunFinalTPS).
+		Collector(m.RunSuccess).
+		Collector(m.RunP95Latency).
+		Collector(m.RunGasUsed).
+		Collector(m.BuildInfo).
+		PushContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to push to %s: %w", url, err)
+	}
+	return nil
+}
+
 // IsRunning returns true if the metrics server is running
 func (m *Metrics) IsRunning() bool {
 	m.mu.Lock()
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_PushRunSummary(t *testing.T) {
+	var path, body string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		path, body = r.URL.Path, string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	m := NewMetrics("txhammer_test", nil)
+	m.RecordRunSummary(RunSummary{
+		Duration:    90 * time.Second,
+		FinalTPS:    120,
+		SuccessRate: 0.99,
+		P95Latency:  2 * time.Second,
+		GasUsed:     21000,
+	})
+	m.RecordBuildInfo("v1.2.0", "1a2b3c4", "2025-01-01T00:00:00Z", "go1.24.0")
+	if err := m.PushRunSummary(context.Background(), gateway.URL, "txhammer"); err != nil {
+		t.Fatalf("PushRunSummary() error = %v", err)
+	}
+
+	if path != "/metrics/job/txhammer" {
+		t.Errorf("pushed to %s, want /metrics/job/txhammer", path)
+	}
+	for _, name := range []string{"run_duration_seconds", "run_final_tps", "run_success_rate", "run_p95_latency_seconds", "run_gas_used", "build_info"} {
+		if !strings.Contains(body, "txhammer_test_"+name) {
+			t.Errorf("push is missing %s", name)
+		}
+	}
+	if !strings.Contains(body, "v1.2.0") || !strings.Contains(body, "1a2b3c4") {
+		t.Error("build_info is missing its version labels")
+	}
+	if strings.Contains(body, "tx_sent_total") {
+		t.Error("push should only carry the run summary gauges")
+	}
+}
+
+func TestMetrics_PushRunSummary_Region(t *testing.T) {
+	var path, body string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		path, body = r.URL.Path, string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	m := NewMetrics("txhammer_region_test", RegionLabels("eu-west"))
+	m.RecordRunSummary(RunSummary{Duration: time.Minute, FinalTPS: 50})
+	if err := m.PushRunSummary(context.Background(), gateway.URL, "txhammer"); err != nil {
+		t.Fatalf("PushRunSummary() error = %v", err)
+	}
+
+	if path != "/metrics/job/txhammer/region/eu-west" {
+		t.Errorf("pushed to %s, want the region in the grouping key", path)
+	}
+	if !strings.Contains(body, "run_duration_seconds") {
+		t.Error("push is missing run_duration_seconds")
+	}
+	if !strings.Contains(m.TxSent.Desc().String(), "eu-west") {
+		t.Error("live metrics are missing the region label")
+	}
+	if RegionLabels("") != nil {
+		t.Error("RegionLabels(\"\") should be nil")
+	}
+}
@@ -0,0 +1,143 @@
+package mix
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Workload is one traffic class of a mixed run, sent at its own rate from its
+// own sub-accounts
+type Workload struct {
+	Mode string  // Test mode, upper case
+	TPS  float64 // Send rate (tx/s)
+}
+
+// Parse parses a MODE:TPS workload spec such as TRANSFER:500
+func Parse(spec string) (Workload, error) {
+	mode, rate, ok := strings.Cut(spec, ":")
+	if !ok || strings.TrimSpace(mode) == "" {
+		return Workload{}, fmt.Errorf("invalid workload %q: expected MODE:TPS", spec)
+	}
+	tps, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+	if err != nil || tps <= 0 {
+		return Workload{}, fmt.Errorf("invalid workload %q: TPS must be a number greater than 0", spec)
+	}
+	return Workload{Mode: strings.ToUpper(strings.TrimSpace(mode)), TPS: tps}, nil
+}
+
+// ParseAll parses every workload spec
+func ParseAll(specs []string) ([]Workload, error) {
+	workloads := make([]Workload, 0, len(specs))
+	for _, spec := range specs {
+		w, err := Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, w)
+	}
+	return workloads, nil
+}
+
+// Name returns the label of the i-th workload, also used as its output subdirectory
+func (w Workload) Name(i int) string {
+	return fmt.Sprintf("%d-%s", i+1, strings.ToLower(w.Mode))
+}
+
+// Split divides total between the workloads in proportion to their rates, so
+// that every workload runs for about the same time. Each gets at least one.
+func Split(total uint64, workloads []Workload) ([]uint64, error) {
+	if total < uint64(len(workloads)) {
+		return nil, fmt.Errorf("%d cannot be split between %d workloads", total, len(workloads))
+	}
+	var sum float64
+	for _, w := range workloads {
+		sum += w.TPS
+	}
+
+	// Largest remainder: floor each share, then hand out what is left
+	// to the workloads with the largest fractional parts
+	shares := make([]uint64, len(workloads))
+	fractions := make([]float64, len(workloads))
+	left := total
+	for i, w := range workloads {
+		exact := float64(total) * w.TPS / sum
+		shares[i] = uint64(exact)
+		fractions[i] = exact - float64(shares[i])
+		left -= shares[i]
+	}
+	order := make([]int, len(workloads))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return fractions[order[a]] > fractions[order[b]] })
+	for i := 0; left > 0; i++ {
+		shares[order[i%len(order)]]++
+		left--
+	}
+
+	// A workload too slow for a share of its own borrows one from the largest
+	for i := range shares {
+		if shares[i] > 0 {
+			continue
+		}
+		largest := 0
+		for j := range shares {
+			if shares[j] > shares[largest] {
+				largest = j
+			}
+		}
+		shares[largest]--
+		shares[i]++
+	}
+	return shares, nil
+}
+
+// Row is the outcome of one workload in the merged table
+type Row struct {
+	Name         string
+	TargetTPS    float64
+	Sent         int
+	Confirmed    int
+	Failed       int
+	ConfirmedTPS float64 // Confirmed transactions per wall-clock second
+	AvgLatency   time.Duration
+	P95Latency   time.Duration
+	Err          error // Set if the workload did not complete
+}
+
+// PrintTable prints one row per workload and the totals of the whole mix
+func PrintTable(rows []Row) {
+	console.Summaryf("\nWorkload Mix\n\n")
+	console.Summaryf("  %-22s %10s %9s %10s %8s %14s %12s %12s\n",
+		"Workload", "Target TPS", "Sent", "Confirmed", "Failed", "Confirmed TPS", "Avg Latency", "P95 Latency")
+
+	var total Row
+	var latencySum time.Duration
+	for _, r := range rows {
+		console.Summaryf("  %-22s %10.1f %9d %10d %8d %14.2f %12s %12s\n",
+			r.Name, r.TargetTPS, r.Sent, r.Confirmed, r.Failed, r.ConfirmedTPS,
+			r.AvgLatency.Round(time.Millisecond), r.P95Latency.Round(time.Millisecond))
+		if r.Err != nil {
+			console.Failf("  %-22s %s\n", "", r.Err)
+		}
+		total.TargetTPS += r.TargetTPS
+		total.Sent += r.Sent
+		total.Confirmed += r.Confirmed
+		total.Failed += r.Failed
+		total.ConfirmedTPS += r.ConfirmedTPS
+		latencySum += r.AvgLatency * time.Duration(r.Confirmed)
+	}
+	if total.Confirmed > 0 {
+		total.AvgLatency = latencySum / time.Duration(total.Confirmed)
+	}
+
+	// Percentiles of the parts do not combine into one of the whole
+	console.Summaryf("  %-22s %10.1f %9d %10d %8d %14.2f %12s %12s\n",
+		"Total", total.TargetTPS, total.Sent, total.Confirmed, total.Failed, total.ConfirmedTPS,
+		total.AvgLatency.Round(time.Millisecond), "-")
+}
@@ -0,0 +1,52 @@
+package mix
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	w, err := Parse("contract_call:50")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if w.Mode != "CONTRACT_CALL" || w.TPS != 50 {
+		t.Errorf("Parse() = %+v, want CONTRACT_CALL at 50", w)
+	}
+	if got := w.Name(1); got != "2-contract_call" {
+		t.Errorf("Name(1) = %q, want 2-contract_call", got)
+	}
+
+	for _, spec := range []string{"TRANSFER", ":500", "TRANSFER:", "TRANSFER:0", "TRANSFER:-5", "TRANSFER:fast"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) should fail", spec)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	workloads := []Workload{{Mode: "TRANSFER", TPS: 500}, {Mode: "CONTRACT_CALL", TPS: 50}}
+
+	tests := []struct {
+		total uint64
+		want  []uint64
+	}{
+		{11000, []uint64{10000, 1000}},
+		{10, []uint64{9, 1}},
+		{2, []uint64{1, 1}},
+		{7, []uint64{6, 1}},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.total, workloads)
+		if err != nil {
+			t.Fatalf("Split(%d) failed: %v", tt.total, err)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Split(%d) = %v, want %v", tt.total, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if _, err := Split(1, workloads); err == nil {
+		t.Error("Split() should fail with fewer units than workloads")
+	}
+}
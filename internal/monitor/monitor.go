@@ -6,6 +6,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
 )
 
 // Config holds configuration for the monitor
@@ -177,7 +179,7 @@ func (m *Monitor) Display(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			fmt.Printf("\r%s", m.DisplayLine())
+			console.Printf("\r%s", m.DisplayLine())
 		}
 	}
 }
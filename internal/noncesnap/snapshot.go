@@ -0,0 +1,94 @@
+package noncesnap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrChainMismatch is returned when a snapshot was taken on a different chain
+var ErrChainMismatch = errors.New("nonce snapshot chain ID mismatch")
+
+// Snapshot holds the next nonce for a set of accounts on a given chain
+type Snapshot struct {
+	ChainID   uint64                    `json:"chain_id"`
+	CreatedAt time.Time                 `json:"created_at"`
+	Nonces    map[common.Address]uint64 `json:"nonces"`
+}
+
+// New creates an empty snapshot for the given chain
+func New(chainID uint64) *Snapshot {
+	return &Snapshot{
+		ChainID:   chainID,
+		CreatedAt: time.Now(),
+		Nonces:    make(map[common.Address]uint64),
+	}
+}
+
+// Load reads a snapshot from a JSON file
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nonce snapshot: %w", err)
+	}
+
+	snap := &Snapshot{}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("failed to parse nonce snapshot: %w", err)
+	}
+	if snap.Nonces == nil {
+		snap.Nonces = make(map[common.Address]uint64)
+	}
+	return snap, nil
+}
+
+// Save writes the snapshot to a JSON file, creating parent directories
+func (s *Snapshot) Save(path string) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal nonce snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write nonce snapshot: %w", err)
+	}
+	return nil
+}
+
+// Set records the next nonce for an account
+func (s *Snapshot) Set(addr common.Address, nonce uint64) {
+	s.Nonces[addr] = nonce
+}
+
+// Lookup returns the nonces for the given addresses in order, together with
+// the indexes of addresses that are missing from the snapshot
+func (s *Snapshot) Lookup(addrs []common.Address) (nonces []uint64, missing []int) {
+	nonces = make([]uint64, len(addrs))
+	for i, addr := range addrs {
+		nonce, ok := s.Nonces[addr]
+		if !ok {
+			missing = append(missing, i)
+			continue
+		}
+		nonces[i] = nonce
+	}
+	return nonces, missing
+}
+
+// CheckChain returns ErrChainMismatch if the snapshot belongs to another chain
+func (s *Snapshot) CheckChain(chainID uint64) error {
+	if s.ChainID != 0 && s.ChainID != chainID {
+		return fmt.Errorf("%w: snapshot %d, node %d", ErrChainMismatch, s.ChainID, chainID)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package noncesnap
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSnapshot_SaveLoad(t *testing.T) {
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	snap := New(1337)
+	snap.Set(addr1, 5)
+	snap.Set(addr2, 42)
+
+	path := filepath.Join(t.TempDir(), "nested", "nonces.json")
+	if err := snap.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.ChainID != 1337 {
+		t.Errorf("ChainID = %d, want 1337", loaded.ChainID)
+	}
+	if loaded.Nonces[addr1] != 5 || loaded.Nonces[addr2] != 42 {
+		t.Errorf("Nonces = %v", loaded.Nonces)
+	}
+}
+
+func TestSnapshot_Lookup(t *testing.T) {
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	addr3 := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	snap := New(1)
+	snap.Set(addr1, 7)
+	snap.Set(addr3, 9)
+
+	nonces, missing := snap.Lookup([]common.Address{addr1, addr2, addr3})
+	if nonces[0] != 7 || nonces[2] != 9 {
+		t.Errorf("nonces = %v, want [7 0 9]", nonces)
+	}
+	if len(missing) != 1 || missing[0] != 1 {
+		t.Errorf("missing = %v, want [1]", missing)
+	}
+}
+
+func TestSnapshot_CheckChain(t *testing.T) {
+	snap := New(1)
+	if err := snap.CheckChain(1); err != nil {
+		t.Errorf("CheckChain(1) error = %v", err)
+	}
+	if err := snap.CheckChain(2); !errors.Is(err, ErrChainMismatch) {
+		t.Errorf("CheckChain(2) error = %v, want ErrChainMismatch", err)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() should fail for a missing file")
+	}
+}
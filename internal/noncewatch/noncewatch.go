@@ -0,0 +1,210 @@
+package noncewatch
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xmhha/txhammer/internal/util/compress"
+)
+
+// batchSize is the number of accounts queried per batch call
+const batchSize = 200
+
+// Client defines the interface for reading account nonces
+type Client interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// Sample holds the confirmed nonce of every account at one point in time
+type Sample struct {
+	Offset time.Duration // Time since the first sample
+	Nonces []uint64      // Parallel to Series.Accounts
+}
+
+// Series is the nonce progression of a set of accounts over a run
+type Series struct {
+	Start    time.Time
+	Interval time.Duration
+	Accounts []common.Address
+	Samples  []Sample
+}
+
+// Stall is the longest stretch over which an account's nonce did not move
+// while it still had transactions to confirm
+type Stall struct {
+	Account  common.Address `json:"account"`
+	Nonce    uint64         `json:"nonce"` // Nonce the account was stuck at
+	From     time.Duration  `json:"from"`  // Offset of the first sample at that nonce
+	Duration time.Duration  `json:"duration"`
+}
+
+// Watcher samples the confirmed nonce of accounts at a fixed interval
+type Watcher struct {
+	client   Client
+	accounts []common.Address
+	interval time.Duration
+
+	mu     sync.Mutex
+	series *Series
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a watcher for the accounts
+func New(client Client, accounts []common.Address, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Watcher{
+		client:   client,
+		accounts: accounts,
+		interval: interval,
+		series:   &Series{Interval: interval, Accounts: accounts},
+	}
+}
+
+// Start takes the first sample and keeps sampling in the background until Stop
+func (w *Watcher) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+	w.series.Start = time.Now()
+	w.sample(ctx)
+
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.sample(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends background sampling, takes a last sample and returns the series
+func (w *Watcher) Stop(ctx context.Context) *Series {
+	if w.cancel != nil {
+		w.cancel()
+		<-w.done
+		w.cancel = nil
+	}
+	w.sample(ctx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.series
+}
+
+// sample reads the nonce of every account; a failed read skips the sample
+func (w *Watcher) sample(ctx context.Context) {
+	at := time.Now()
+	nonces, err := readNonces(ctx, w.client, w.accounts)
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	w.series.Samples = append(w.series.Samples, Sample{Offset: at.Sub(w.series.Start), Nonces: nonces})
+	w.mu.Unlock()
+}
+
+// readNonces reads the nonce of each account at the latest block in batches
+func readNonces(ctx context.Context, client Client, accounts []common.Address) ([]uint64, error) {
+	results := make([]hexutil.Uint64, len(accounts))
+	for start := 0; start < len(accounts); start += batchSize {
+		end := min(start+batchSize, len(accounts))
+		batch := make([]rpc.BatchElem, 0, end-start)
+		for i := start; i < end; i++ {
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_getTransactionCount",
+				Args:   []any{accounts[i], "latest"},
+				Result: &results[i],
+			})
+		}
+		if err := client.BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+		for _, elem := range batch {
+			if elem.Error != nil {
+				return nil, elem.Error
+			}
+		}
+	}
+
+	nonces := make([]uint64, len(accounts))
+	for i, n := range results {
+		nonces[i] = uint64(n)
+	}
+	return nonces, nil
+}
+
+// Stalls returns, for each account whose nonce stood still for at least
+// minDuration before reaching its final value, its longest such stretch,
+// longest first. Time spent at the final value is not a stall, since the
+// account may simply have had nothing left to send.
+func Stalls(s *Series, minDuration time.Duration) []Stall {
+	if s == nil || len(s.Samples) < 2 {
+		return nil
+	}
+	var stalls []Stall
+	for i, account := range s.Accounts {
+		var longest Stall
+		runStart := 0
+		for j := 1; j < len(s.Samples); j++ {
+			if s.Samples[j].Nonces[i] == s.Samples[runStart].Nonces[i] {
+				continue
+			}
+			if d := s.Samples[j-1].Offset - s.Samples[runStart].Offset; d > longest.Duration {
+				longest = Stall{Account: account, Nonce: s.Samples[runStart].Nonces[i], From: s.Samples[runStart].Offset, Duration: d}
+			}
+			runStart = j
+		}
+		if longest.Duration >= minDuration && longest.Duration > 0 {
+			stalls = append(stalls, longest)
+		}
+	}
+	sort.Slice(stalls, func(a, b int) bool { return stalls[a].Duration > stalls[b].Duration })
+	return stalls
+}
+
+// WriteCSV writes the series in long form, one row per account and sample,
+// compressed with codec
+func WriteCSV(path string, s *Series, codec compress.Codec) error {
+	file, err := compress.Create(path, codec)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"Time", "Offset", "Account", "Nonce"}); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, sample := range s.Samples {
+		at := s.Start.Add(sample.Offset).Format(time.RFC3339Nano)
+		offset := sample.Offset.String()
+		for i, account := range s.Accounts {
+			record := []string{at, offset, account.Hex(), strconv.FormatUint(sample.Nonces[i], 10)}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write record: %w", err)
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,120 @@
+package noncewatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xmhha/txhammer/internal/util/compress"
+)
+
+// countingClient answers eth_getTransactionCount with a nonce that grows by
+// one on every call, except for stuck accounts
+type countingClient struct {
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+	stuck  map[common.Address]bool
+	calls  int
+}
+
+func (c *countingClient) BatchCallContext(_ context.Context, b []rpc.BatchElem) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	for i := range b {
+		addr := b[i].Args[0].(common.Address)
+		if !c.stuck[addr] {
+			c.nonces[addr]++
+		}
+		*b[i].Result.(*hexutil.Uint64) = hexutil.Uint64(c.nonces[addr])
+	}
+	return nil
+}
+
+func TestWatcher(t *testing.T) {
+	a, b := common.HexToAddress("0xa"), common.HexToAddress("0xb")
+	client := &countingClient{nonces: map[common.Address]uint64{}, stuck: map[common.Address]bool{b: true}}
+
+	w := New(client, []common.Address{a, b}, 10*time.Millisecond)
+	w.Start(context.Background())
+	time.Sleep(55 * time.Millisecond)
+	series := w.Stop(context.Background())
+
+	if len(series.Samples) < 3 {
+		t.Fatalf("got %d samples, want at least 3", len(series.Samples))
+	}
+	first, last := series.Samples[0], series.Samples[len(series.Samples)-1]
+	if last.Nonces[0] <= first.Nonces[0] {
+		t.Errorf("account a did not progress: %d -> %d", first.Nonces[0], last.Nonces[0])
+	}
+	if last.Nonces[1] != 0 {
+		t.Errorf("stuck account b nonce = %d, want 0", last.Nonces[1])
+	}
+	for i := 1; i < len(series.Samples); i++ {
+		if series.Samples[i].Offset < series.Samples[i-1].Offset {
+			t.Fatal("sample offsets are not increasing")
+		}
+	}
+}
+
+func TestStalls(t *testing.T) {
+	a, b, c := common.HexToAddress("0xa"), common.HexToAddress("0xb"), common.HexToAddress("0xc")
+	series := &Series{
+		Accounts: []common.Address{a, b, c},
+		Samples: []Sample{
+			{Offset: 0, Nonces: []uint64{0, 0, 0}},
+			{Offset: time.Second, Nonces: []uint64{1, 1, 1}},
+			{Offset: 2 * time.Second, Nonces: []uint64{2, 1, 2}},
+			{Offset: 3 * time.Second, Nonces: []uint64{3, 1, 2}},
+			{Offset: 4 * time.Second, Nonces: []uint64{4, 1, 2}},
+			{Offset: 5 * time.Second, Nonces: []uint64{5, 6, 2}},
+			{Offset: 6 * time.Second, Nonces: []uint64{5, 7, 2}},
+		},
+	}
+
+	// b sat at nonce 1 for 3s before recovering; c stopped at its final nonce
+	// and a never stood still, so neither counts
+	stalls := Stalls(series, 2*time.Second)
+	if len(stalls) != 1 {
+		t.Fatalf("got %d stalls, want 1: %+v", len(stalls), stalls)
+	}
+	if s := stalls[0]; s.Account != b || s.Nonce != 1 || s.From != time.Second || s.Duration != 3*time.Second {
+		t.Errorf("stall = %+v", s)
+	}
+	if got := Stalls(series, 5*time.Second); len(got) != 0 {
+		t.Errorf("stalls above 5s = %+v, want none", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	a := common.HexToAddress("0xa")
+	start := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	series := &Series{
+		Start:    start,
+		Accounts: []common.Address{a},
+		Samples:  []Sample{{Offset: 0, Nonces: []uint64{3}}, {Offset: time.Second, Nonces: []uint64{5}}},
+	}
+	path := filepath.Join(t.TempDir(), "nonces.csv")
+	if err := WriteCSV(path, series, compress.None); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || lines[0] != "Time,Offset,Account,Nonce" {
+		t.Fatalf("unexpected CSV:\n%s", data)
+	}
+	if want := "2024-01-15T14:30:01Z,1s," + a.Hex() + ",5"; lines[2] != want {
+		t.Errorf("row = %s, want %s", lines[2], want)
+	}
+}
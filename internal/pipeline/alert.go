@@ -0,0 +1,76 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/alert"
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// alertMinTxs is the fewest sends in the alert window for the failure rate to count
+const alertMinTxs = 10
+
+// startAlert starts watching the send failure rate if --alert-failure-rate
+// is set, and returns a function that stops it
+func (p *Pipeline) startAlert(ctx context.Context) func() {
+	if p.runCfg.AlertFailureRate <= 0 {
+		return func() {}
+	}
+	p.alert = alert.New(alert.Config{
+		Window:    p.runCfg.AlertWindow,
+		Threshold: p.runCfg.AlertFailureRate,
+		MinTxs:    alertMinTxs,
+	})
+	alertCtx, cancel := context.WithCancel(ctx)
+	go p.alert.Run(alertCtx)
+	return cancel
+}
+
+// alertCallbacks counts every send outcome toward the failure rate before
+// passing it on to next
+func (p *Pipeline) alertCallbacks(next *batcher.Callbacks) *batcher.Callbacks {
+	if p.alert == nil {
+		return next
+	}
+	return &batcher.Callbacks{
+		OnSent: func(r *batcher.TxResult) {
+			p.alert.Sent()
+			if next != nil && next.OnSent != nil {
+				next.OnSent(r)
+			}
+		},
+		OnFailed: func(r *batcher.TxResult) {
+			p.alert.Failed(r.Error)
+			if next != nil && next.OnFailed != nil {
+				next.OnFailed(r)
+			}
+		},
+	}
+}
+
+// recordAlerts stores the failure-rate alerts raised so far in the result
+func (p *Pipeline) recordAlerts(result *Result) {
+	if p.alert != nil {
+		result.Alerts = p.alert.Alerts()
+	}
+}
+
+// printAlerts prints the periods in which the send failure rate exceeded the threshold
+func printAlerts(alerts []alert.Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+	console.Warnf("\nFailure Rate Alerts: %d\n", len(alerts))
+	for _, a := range alerts {
+		ended := "ongoing at end of send"
+		if !a.End.IsZero() {
+			ended = "for " + a.Duration.Round(time.Second).String()
+		}
+		console.Summaryf("  - from %s %s, peak %.1f%% (%d of %d)\n", a.Start.Format(time.RFC3339), ended, a.PeakRate, a.Failed, a.Total)
+		if a.LastError != "" {
+			console.Summaryf("    last error: %s\n", a.LastError)
+		}
+	}
+}
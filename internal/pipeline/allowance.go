@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// prepareApprovals runs the approve() phase of APPROVE_TRANSFERFROM: each
+// sub-account approves the next one as its spender, and the phase waits up
+// to --timeout for the receipts before the transferFrom() calls are built.
+// The nonces of accepted approvals are consumed.
+func (p *Pipeline) prepareApprovals(ctx context.Context, keys []*ecdsa.PrivateKey, count int) error {
+	builder, ok := p.builder.(*txbuilder.ApproveTransferFromBuilder)
+	if !ok {
+		return nil
+	}
+
+	approveTxs, err := builder.GetApproveTransactions(ctx, txbuilder.KeySigners(keys), p.nonces, count)
+	if err != nil {
+		return fmt.Errorf("failed to build approvals: %w", err)
+	}
+	index := make(map[common.Address]int, len(keys))
+	for i, signer := range txbuilder.KeySigners(keys) {
+		index[signer.Address()] = i
+	}
+
+	console.Printf("\nSending %d approvals...\n", len(approveTxs))
+	metrics := &ApproveMetrics{}
+	start := time.Now()
+	sent := make([]*txbuilder.SignedTx, 0, len(approveTxs))
+	sentAt := make([]time.Time, 0, len(approveTxs))
+	for _, tx := range approveTxs {
+		at := time.Now()
+		if err := p.client.SendTransaction(ctx, tx.Tx); err != nil {
+			metrics.TxsFailed++
+			continue
+		}
+		sent = append(sent, tx)
+		sentAt = append(sentAt, at)
+		p.nonces[index[tx.From]]++
+	}
+	metrics.TxsSent = len(sent)
+
+	last := p.awaitApprovals(ctx, sent, sentAt, metrics)
+	if !last.IsZero() {
+		duration := last.Sub(start)
+		metrics.Duration = duration.String()
+		if duration > 0 {
+			metrics.Throughput = float64(metrics.Confirmed) / duration.Seconds()
+		}
+	}
+	p.stages.Approve = metrics
+
+	console.Printf("\nApprove Summary:\n")
+	console.Printf("  Sent:              %d\n", metrics.TxsSent)
+	console.Printf("  Confirmed:         %d\n", metrics.Confirmed)
+	if metrics.AvgLatency != "" {
+		console.Printf("  Avg Latency:       %s\n", metrics.AvgLatency)
+	}
+	if metrics.TxsFailed > 0 || metrics.Reverted > 0 || metrics.Pending > 0 {
+		console.Warnf("%d approvals refused, %d reverted and %d not mined; transferFrom() calls spending them will revert\n",
+			metrics.TxsFailed, metrics.Reverted, metrics.Pending)
+	}
+	if len(approveTxs) > 0 && metrics.Confirmed == 0 {
+		return fmt.Errorf("none of %d approvals succeeded", len(approveTxs))
+	}
+	return nil
+}
+
+// awaitApprovals polls the approval receipts until all are mined or
+// --timeout elapses, counting outcomes into metrics. It returns when the
+// last receipt was seen.
+func (p *Pipeline) awaitApprovals(ctx context.Context, sent []*txbuilder.SignedTx, sentAt []time.Time, metrics *ApproveMetrics) time.Time {
+	mined := make([]bool, len(sent))
+	pending := len(sent)
+	deadline := time.Now().Add(p.cfg.Timeout)
+
+	var last time.Time
+	var totalLatency time.Duration
+	for pending > 0 {
+		for i, tx := range sent {
+			if mined[i] {
+				continue
+			}
+			receipt, err := p.client.TransactionReceipt(ctx, tx.Hash)
+			if err != nil || receipt == nil {
+				continue
+			}
+			mined[i] = true
+			pending--
+			last = time.Now()
+			totalLatency += last.Sub(sentAt[i])
+			if receipt.Status == types.ReceiptStatusSuccessful {
+				metrics.Confirmed++
+			} else {
+				metrics.Reverted++
+			}
+		}
+		if pending == 0 || time.Now().After(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(deployPollInterval):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	metrics.Pending = pending
+	if minedCount := len(sent) - pending; minedCount > 0 {
+		metrics.AvgLatency = (totalLatency / time.Duration(minedCount)).String()
+	}
+	return last
+}
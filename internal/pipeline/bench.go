@@ -0,0 +1,167 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/bench"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// errEphemeralBench rejects bench runs on ephemeral sub-accounts, whose funds
+// only the main command reclaims
+var errEphemeralBench = errors.New("bench needs sub-accounts that outlive the run: drop --ephemeral-accounts or load them with --key-file")
+
+// ExecuteBench sweeps batch sizes and concurrency levels with short sends and
+// recommends the setting with the best throughput within the error budget
+func (p *Pipeline) ExecuteBench(ctx context.Context, benchCfg *bench.Config) (*bench.Result, error) {
+	if err := benchCfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bench config: %w", err)
+	}
+	if p.wallet.Ephemeral() {
+		return nil, errEphemeralBench
+	}
+	settings := benchCfg.Settings()
+
+	console.Printf("\nStarting Batch Sweep\n\n")
+	console.Printf("  Settings:        %d\n", len(settings))
+	console.Printf("  Txs per trial:   %d\n", benchCfg.TxsPerTrial)
+	console.Printf("  Max error rate:  %.2f%%\n", benchCfg.MaxErrorRate)
+
+	// Fund sub-accounts for every trial up front
+	p.cfg.Transactions = uint64(benchCfg.TxsPerTrial) * uint64(len(settings))
+	p.stages = &StageMetrics{}
+	if err := p.initialize(ctx); err != nil {
+		return nil, fmt.Errorf("initialization failed: %w", err)
+	}
+	if !p.runCfg.SkipDistribution {
+		if err := p.distribute(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	p.cfg.Transactions = uint64(benchCfg.TxsPerTrial)
+	result := &bench.Result{Trials: make([]*bench.Trial, 0, len(settings))}
+	for i, setting := range settings {
+		if ctx.Err() != nil {
+			break
+		}
+		console.Printf("\nTrial %d/%d: batch=%d concurrent=%d\n", i+1, len(settings), setting.BatchSize, setting.MaxConcurrent)
+		result.Trials = append(result.Trials, p.runBenchTrial(ctx, setting))
+
+		// Later trials start from the node's view of each account
+		p.nonces = nil
+		p.nonceSnap = nil
+	}
+
+	result.Recommended = bench.Recommend(result.Trials, benchCfg.MaxErrorRate)
+	bench.PrintTable(result)
+	return result, ctx.Err()
+}
+
+// runBenchTrial builds and sends one trial's transactions with the given setting
+func (p *Pipeline) runBenchTrial(ctx context.Context, setting bench.Setting) *bench.Trial {
+	if err := p.build(ctx); err != nil {
+		return &bench.Trial{Setting: setting, Err: err}
+	}
+
+	batchCfg := &batcher.Config{
+		BatchSize:     setting.BatchSize,
+		MaxConcurrent: setting.MaxConcurrent,
+		BatchInterval: 0,
+		RetryCount:    0, // Retries would hide the errors being measured
+		Timeout:       30 * time.Second,
+	}
+	b, err := batcher.New(p.client, batchCfg)
+	if err != nil {
+		return &bench.Trial{Setting: setting, Err: err}
+	}
+
+	summary, err := b.SendAll(ctx, p.signedTxs)
+	if err != nil {
+		return &bench.Trial{Setting: setting, Err: err}
+	}
+	return bench.NewTrial(setting, summary.SuccessCount, summary.FailedCount, summary.TotalDuration)
+}
+
+// ExecuteAccountBench sends a fixed number of transactions from increasing numbers
+// of sub-accounts and reports how chain throughput scales with sender parallelism
+func (p *Pipeline) ExecuteAccountBench(ctx context.Context, benchCfg *bench.AccountConfig) (*bench.AccountResult, error) {
+	if err := benchCfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bench config: %w", err)
+	}
+	if p.wallet.Ephemeral() {
+		return nil, errEphemeralBench
+	}
+	maxCount := benchCfg.MaxCount()
+	if len(p.wallet.SubKeys()) < maxCount {
+		return nil, fmt.Errorf("wallet has %d sub-accounts, sweep needs %d", len(p.wallet.SubKeys()), maxCount)
+	}
+
+	console.Printf("\nStarting Sub-Account Sweep\n\n")
+	console.Printf("  Counts:          %v\n", benchCfg.Counts)
+	console.Printf("  Txs per trial:   %d\n", benchCfg.TotalTxs)
+
+	// Per-trial reports are summarized in the sweep table instead of exported
+	runCfg := *p.runCfg
+	runCfg.ExportReport = false
+	p.runCfg = &runCfg
+
+	// Fund every sub-account for all trials it takes part in
+	p.cfg.Transactions = uint64(benchCfg.TxsPerAccount()) * uint64(maxCount)
+	p.stages = &StageMetrics{}
+	if err := p.initialize(ctx); err != nil {
+		return nil, fmt.Errorf("initialization failed: %w", err)
+	}
+	if !p.runCfg.SkipDistribution {
+		if err := p.distribute(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	p.cfg.Transactions = uint64(benchCfg.TotalTxs)
+	result := &bench.AccountResult{Trials: make([]*bench.AccountTrial, 0, len(benchCfg.Counts))}
+	for i, count := range benchCfg.Counts {
+		if ctx.Err() != nil {
+			break
+		}
+		console.Printf("\nTrial %d/%d: %d sub-accounts\n", i+1, len(benchCfg.Counts), count)
+		result.Trials = append(result.Trials, p.runAccountTrial(ctx, count))
+	}
+	p.accountLimit = 0
+
+	result.Saturation = bench.Analyze(result.Trials, benchCfg.MinGain)
+	bench.PrintAccountTable(result)
+	return result, ctx.Err()
+}
+
+// runAccountTrial builds, sends and collects one trial's transactions from the first count sub-accounts
+func (p *Pipeline) runAccountTrial(ctx context.Context, count int) *bench.AccountTrial {
+	trial := &bench.AccountTrial{SubAccounts: count}
+
+	p.accountLimit = count
+	p.nonces = nil
+	p.nonceSnap = nil
+	p.stages = &StageMetrics{}
+
+	for _, stage := range []func(context.Context) error{p.build, p.send, p.collect} {
+		if err := stage(ctx); err != nil {
+			trial.Err = err
+			return trial
+		}
+	}
+
+	if send := p.stages.Send; send != nil {
+		trial.Sent = send.TxsSent
+		trial.SendTPS = send.RPCThroughput
+	}
+	if m := p.report.Metrics; m != nil {
+		trial.Confirmed = m.TotalConfirmed
+		trial.ChainTPS = m.ChainTPS
+		trial.AvgLatency = m.AvgLatency
+	}
+	return trial
+}
@@ -0,0 +1,91 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xmhha/txhammer/internal/blockstop"
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// checkStopBlock fails the run before funding if the chain has already
+// reached --stop-at-block
+func (p *Pipeline) checkStopBlock(ctx context.Context) error {
+	if p.runCfg.StopAtBlock == 0 {
+		return nil
+	}
+	head, err := p.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	if head >= p.runCfg.StopAtBlock {
+		return fmt.Errorf("chain head #%d has already reached --stop-at-block %d", head, p.runCfg.StopAtBlock)
+	}
+	console.Printf("  Stop Block:     #%d (%d blocks ahead)\n", p.runCfg.StopAtBlock, p.runCfg.StopAtBlock-head)
+	return nil
+}
+
+// stopsAtBlock reports whether mode honors --stop-at-block: the standard
+// pipeline modes and the long senders, which run until canceled
+func stopsAtBlock(mode config.Mode) bool {
+	return mixable(mode) || mode == config.ModeLongSender || mode == config.ModeTargetUtilization
+}
+
+// startBlockStop watches the chain head for --stop-at-block and returns a
+// function that stops watching
+func (p *Pipeline) startBlockStop(ctx context.Context) func() {
+	if p.runCfg.StopAtBlock == 0 {
+		return func() {}
+	}
+	p.blockStop = blockstop.New(p.client, &blockstop.Config{Height: p.runCfg.StopAtBlock})
+	stopCtx, cancel := context.WithCancel(ctx)
+	go p.blockStop.Run(stopCtx)
+	return cancel
+}
+
+// untilBlockStop returns a context that is canceled once the chain reaches
+// --stop-at-block, for senders that run until canceled
+func (p *Pipeline) untilBlockStop(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if p.blockStop == nil {
+		return ctx, cancel
+	}
+	go func() {
+		select {
+		case <-p.blockStop.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// recordBlockStop stores where the chain reached --stop-at-block in the result
+// and the send metrics. Transactions the cutoff refused count as unsent, not failed.
+func (p *Pipeline) recordBlockStop(result *Result) {
+	if p.blockStop == nil {
+		return
+	}
+	result.BlockStop = p.blockStop.Stopped()
+	if result.BlockStop == nil || p.stages == nil || p.stages.Send == nil {
+		return
+	}
+	p.failuresMu.Lock()
+	unsent := p.unsentAtStop
+	p.failuresMu.Unlock()
+	p.stages.Send.BlockStop = result.BlockStop
+	p.stages.Send.TxsUnsent = unsent
+	p.stages.Send.TxsFailed -= unsent
+}
+
+// printBlockStop prints where sending stopped at --stop-at-block
+func printBlockStop(stop *blockstop.Stop, unsent int) {
+	if stop == nil {
+		return
+	}
+	console.Warnf("\nSending stopped at stop block #%d (head #%d) at %s\n", stop.Height, stop.Head, stop.At.Format("15:04:05"))
+	if unsent > 0 {
+		console.Summaryf("  Unsent:   %d txs\n", unsent)
+	}
+}
@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/blockstop"
+	"github.com/0xmhha/txhammer/internal/budget"
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/units"
+)
+
+// initBudget creates the spend budget if --max-spend is set
+func (p *Pipeline) initBudget() error {
+	p.budget = nil
+	if p.runCfg.MaxSpend == "" {
+		return nil
+	}
+	limit, err := units.ParseAmount(p.runCfg.MaxSpend)
+	if err != nil {
+		return fmt.Errorf("invalid max-spend: %w", err)
+	}
+	p.budget = budget.New(limit)
+	console.Printf("  Spend Budget:   %s\n", p.units().Format(limit))
+	return nil
+}
+
+// budgeted reports whether mode sends through the batcher, the streamer or
+// the long sender, which hold each transaction to --max-spend
+func budgeted(mode config.Mode) bool {
+	return mixable(mode) || mode == config.ModeLongSender || mode == config.ModeTargetUtilization
+}
+
+// checkPlanBudget compares the max fee of the built transactions with the budget.
+// A dry run fails if the plan does not fit; a real run is cut short at runtime.
+func (p *Pipeline) checkPlanBudget() error {
+	if p.budget == nil {
+		return nil
+	}
+
+	txs := make([]*types.Transaction, len(p.signedTxs))
+	for i, tx := range p.signedTxs {
+		txs[i] = tx.Tx
+	}
+	plan := budget.PlanCost(txs)
+	console.Printf("  Max Fee (plan):    %s of %s budget\n", p.units().Format(plan), p.units().Format(p.budget.Limit()))
+
+	if plan.Cmp(p.budget.Limit()) <= 0 {
+		return nil
+	}
+	if p.runCfg.DryRun {
+		return fmt.Errorf("planned max fee %s exceeds --max-spend %s", plan, p.budget.Limit())
+	}
+	console.Warnf("Planned max fee exceeds the budget; sending stops when it is reached\n")
+	return nil
+}
+
+// recordSendFailures keeps failed sends for the nonce snapshot and stops
+// tracking transactions the budget refused, the send deadline expired or
+// --stop-at-block left behind, since they were never sent. Sends refused
+// by provider throttling are counted apart from node rejections.
+func (p *Pipeline) recordSendFailures(failed []*batcher.TxResult) {
+	p.failuresMu.Lock()
+	defer p.failuresMu.Unlock()
+	for _, ft := range failed {
+		p.sendFailures = append(p.sendFailures, ft.Tx)
+		if errors.Is(ft.Error, budget.ErrExceeded) || errors.Is(ft.Error, batcher.ErrExpired) {
+			p.collector.Untrack(ft.Tx.Hash)
+		}
+		if client.IsThrottled(ft.Error) {
+			p.throttledSends++
+		}
+		if errors.Is(ft.Error, blockstop.ErrReached) {
+			p.collector.Untrack(ft.Tx.Hash)
+			p.unsentAtStop++
+		}
+	}
+}
+
+// printBudget prints where sending stopped because of the spend budget
+func (p *Pipeline) printBudget(stop *budget.Stop) {
+	if stop == nil {
+		return
+	}
+	console.Warnf("\nSpend budget of %s reached at %s after %d txs\n",
+		p.units().Format(p.budget.Limit()), stop.At.Format("15:04:05"), stop.Admitted)
+	console.Summaryf("  Spent:    %s\n", p.units().Format(stop.Spent))
+	console.Summaryf("  Reserved: %s (max fee of unconfirmed txs)\n", p.units().Format(stop.Reserved))
+}
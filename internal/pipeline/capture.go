@@ -0,0 +1,64 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// startCapture starts recording failed sends if --capture-failures is set
+func (p *Pipeline) startCapture() {
+	if p.runCfg.CaptureFailures <= 0 {
+		return
+	}
+	p.capture = client.NewCapture(p.runCfg.CaptureFailures)
+	client.CaptureFailures(p.capture)
+}
+
+// writeCapture stops recording failed sends and writes the captured
+// exchanges to the output directory
+func (p *Pipeline) writeCapture() {
+	if p.capture == nil {
+		return
+	}
+	client.CaptureFailures(nil)
+	failures := p.capture.Failures()
+	if len(failures) == 0 || p.runCfg.OutputDir == "" {
+		return
+	}
+	file, err := writeFailures(p.runCfg.OutputDir, failures)
+	if err != nil {
+		console.Warnf("Failed to export captured send failures: %v\n", err)
+		return
+	}
+	p.artifacts = append(p.artifacts, file)
+	console.Printf("%d failed send requests captured to: %s\n", len(failures), file)
+}
+
+// writeFailures writes the captured exchanges as JSON lines to the output directory
+func writeFailures(outputDir string, failures []client.Failure) (string, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	filename := filepath.Join(outputDir, fmt.Sprintf("failures_%s.jsonl", time.Now().Format("20060102_150405")))
+	file, err := os.Create(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	enc := json.NewEncoder(file)
+	for i := range failures {
+		if err := enc.Encode(&failures[i]); err != nil {
+			file.Close()
+			return "", fmt.Errorf("failed to write failure: %w", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to close file: %w", err)
+	}
+	return filename, nil
+}
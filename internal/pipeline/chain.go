@@ -0,0 +1,66 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xmhha/txhammer/internal/txchain"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/mathutil"
+)
+
+// executeChain sends independent transfers from the sub-accounts and then a
+// chain of transfers in which each sender is the previous recipient, and
+// compares how long each takes to be mined
+func (p *Pipeline) executeChain(ctx context.Context, result *Result) (*Result, error) {
+	console.Println("Running Chain mode...")
+
+	p.stages = result.Stages
+	if err := p.initialize(ctx); err != nil {
+		result.Finalize()
+		return result, fmt.Errorf("initialization failed: %w", err)
+	}
+	if !p.runCfg.SkipDistribution {
+		if err := p.distribute(ctx); err != nil {
+			result.Finalize()
+			return result, err
+		}
+	}
+
+	gasPrice, err := p.legacyGasPrice(ctx)
+	if err != nil {
+		result.Finalize()
+		return result, err
+	}
+	length, err := mathutil.Uint64ToInt(p.cfg.Transactions)
+	if err != nil {
+		result.Finalize()
+		return result, fmt.Errorf("transaction count overflow: %w", err)
+	}
+
+	chainCfg := txchain.DefaultConfig()
+	chainCfg.ChainID = p.chainID
+	chainCfg.GasPrice = gasPrice
+	chainCfg.GasLimit = p.cfg.GasLimit
+	chainCfg.Length = length
+	if p.cfg.Timeout > 0 {
+		chainCfg.StallTimeout = p.cfg.Timeout
+	}
+	tester := txchain.New(p.client, chainCfg)
+
+	console.Printf("\nStarting Chain Test\n\n")
+	console.Printf("  Chain Length:     %d\n", chainCfg.Length)
+	console.Printf("  Senders:          %d (independent transfers)\n", len(p.subKeys()))
+	console.Printf("  Chain Gas Cost:   %s (paid by the master)\n", p.units().Format(tester.Cost()))
+
+	chainResult, err := tester.Run(ctx, p.masterSigner(), p.subKeys())
+	if chainResult != nil {
+		txchain.PrintResult(chainResult)
+	}
+
+	result.Finalize()
+	if err != nil {
+		return result, fmt.Errorf("chain test failed: %w", err)
+	}
+	return result, nil
+}
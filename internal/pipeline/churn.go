@@ -0,0 +1,47 @@
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/fixtures"
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+)
+
+// churnStats counts contracts created and destroyed in confirmed churn transactions
+type churnStats struct {
+	created   atomic.Int64
+	destroyed atomic.Int64
+}
+
+// record adds the churn counts found in a receipt
+func (s *churnStats) record(receipt *types.Receipt) {
+	created, destroyed := txbuilder.ChurnCounts(receipt)
+	s.created.Add(int64(created))
+	s.destroyed.Add(int64(destroyed))
+}
+
+// prepareChurn deploys or reuses the churn factory when no --contract was
+// given, and counts churned contracts as receipts arrive
+func (p *Pipeline) prepareChurn(ctx context.Context, fm *fixtures.Manager) error {
+	builder, ok := p.builder.(*txbuilder.Create2ChurnBuilder)
+	if !ok {
+		return nil
+	}
+
+	p.churn = &churnStats{}
+	p.collector.WithCallbacks(p.collectorCallbacks(p.churn.record))
+
+	if builder.Factory() != (common.Address{}) {
+		return nil
+	}
+	addr, err := p.ensureFixture(ctx, fm, fixtures.ChurnFactory, builder.GetDeployTransaction)
+	if err != nil {
+		return err
+	}
+	builder.WithFactory(addr)
+	return nil
+}
@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/conflict"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/mathutil"
+)
+
+// executeConflict sends differing transactions with the same nonce, optionally
+// through several endpoints, and reports which variant the chain keeps
+func (p *Pipeline) executeConflict(ctx context.Context, result *Result) (*Result, error) {
+	console.Println("Running Conflict mode...")
+
+	p.stages = result.Stages
+	if err := p.initialize(ctx); err != nil {
+		result.Finalize()
+		return result, fmt.Errorf("initialization failed: %w", err)
+	}
+	if !p.runCfg.SkipDistribution {
+		if err := p.distribute(ctx); err != nil {
+			result.Finalize()
+			return result, err
+		}
+	}
+
+	endpoints := []conflict.Endpoint{{URL: p.cfg.URL, Sender: p.client}}
+	for _, url := range p.cfg.Endpoints {
+		cli, err := client.New(url)
+		if err != nil {
+			result.Finalize()
+			return result, fmt.Errorf("failed to create client for %s: %w", url, err)
+		}
+		defer cli.Close()
+		endpoints = append(endpoints, conflict.Endpoint{URL: url, Sender: cli})
+	}
+
+	gasPrice, err := p.legacyGasPrice(ctx)
+	if err != nil {
+		result.Finalize()
+		return result, err
+	}
+	rounds, err := mathutil.Uint64ToInt(p.cfg.Transactions)
+	if err != nil {
+		result.Finalize()
+		return result, fmt.Errorf("transaction count overflow: %w", err)
+	}
+
+	conflictCfg := conflict.DefaultConfig()
+	conflictCfg.ChainID = p.chainID
+	conflictCfg.GasPrice = gasPrice
+	conflictCfg.GasLimit = p.cfg.GasLimit
+	conflictCfg.Rounds = rounds
+	conflictCfg.Variants = p.cfg.ConflictVariants
+	if p.cfg.Timeout > 0 {
+		conflictCfg.ReceiptTimeout = p.cfg.Timeout
+	}
+
+	console.Printf("\nStarting Conflict Test\n\n")
+	console.Printf("  Rounds:          %d\n", conflictCfg.Rounds)
+	console.Printf("  Variants:        %d\n", conflictCfg.Variants)
+	console.Printf("  Endpoints:       %d\n", len(endpoints))
+
+	tester := conflict.New(p.client, endpoints, conflictCfg)
+	conflictResult, err := tester.Run(ctx, p.subKeys())
+	if conflictResult != nil {
+		conflict.PrintResult(conflictResult)
+	}
+
+	result.Finalize()
+	if err != nil {
+		return result, fmt.Errorf("conflict test failed: %w", err)
+	}
+	return result, nil
+}
+
+// legacyGasPrice returns zero on a zero-fee run, otherwise the configured gas
+// price or the node's suggestion
+func (p *Pipeline) legacyGasPrice(ctx context.Context) (*big.Int, error) {
+	if p.zeroFee {
+		return new(big.Int), nil
+	}
+	if p.cfg.GasPrice != "" {
+		if gasPrice, ok := new(big.Int).SetString(p.cfg.GasPrice, 10); ok && gasPrice.Sign() > 0 {
+			return gasPrice, nil
+		}
+	}
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	return gasPrice, nil
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"context"
+	"sort"
+
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/consistency"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// maxPrintedDivergences is the number of divergent blocks listed in the console
+const maxPrintedDivergences = 5
+
+// checkConsistency compares the blocks that include our transactions across
+// --url and --endpoints for --consistency-check
+func (p *Pipeline) checkConsistency(ctx context.Context, report *collector.Report) {
+	if !p.runCfg.ConsistencyCheck {
+		return
+	}
+	blocks := inclusionBlocks(report)
+	if len(blocks) == 0 {
+		return
+	}
+	conns, err := p.dialEndpoints()
+	if err != nil {
+		console.Warnf("Consistency check skipped: %v\n", err)
+		return
+	}
+
+	endpoints := []consistency.Endpoint{{Name: endpointName(p.cfg.URL), Client: p.client}}
+	for i, u := range p.cfg.Endpoints {
+		endpoints = append(endpoints, consistency.Endpoint{Name: endpointName(u), Client: conns[i]})
+	}
+	result := consistency.Check(ctx, endpoints, blocks)
+	p.stages.Consistency = result
+	printConsistency(result)
+}
+
+// inclusionBlocks returns the numbers of the blocks holding confirmed txs, in order
+func inclusionBlocks(report *collector.Report) []uint64 {
+	seen := make(map[uint64]bool)
+	_ = report.EachTransaction(func(tx *collector.TxInfo) error {
+		if tx.Receipt != nil && tx.Receipt.BlockNumber != nil {
+			seen[tx.Receipt.BlockNumber.Uint64()] = true
+		}
+		return nil
+	})
+	blocks := make([]uint64, 0, len(seen))
+	for n := range seen {
+		blocks = append(blocks, n)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+	return blocks
+}
+
+// printConsistency prints the outcome of the consistency check
+func printConsistency(result *consistency.Result) {
+	console.Printf("\nConsistency Check: %d blocks across %d endpoints, %d compared\n", result.Blocks, len(result.Endpoints), result.Compared)
+	for _, e := range result.Endpoints {
+		if e.Missing > 0 || e.Errors > 0 {
+			console.Printf("  %s: %d blocks missing, %d errors\n", e.Name, e.Missing, e.Errors)
+		}
+	}
+	if result.Consistent {
+		console.OKf("  All endpoints agree on block hashes and state roots\n")
+		return
+	}
+
+	console.Failf("  %d blocks diverge\n", len(result.Divergent))
+	for i, d := range result.Divergent {
+		if i == maxPrintedDivergences {
+			console.Printf("    ... and %d more\n", len(result.Divergent)-maxPrintedDivergences)
+			break
+		}
+		what := "hash"
+		if d.RootDiffers {
+			what = "state root"
+		}
+		console.Printf("    block #%d: %s differs\n", d.Block, what)
+		for _, v := range d.Views {
+			switch {
+			case v.Error != "":
+				console.Printf("      %-30s error: %s\n", v.Endpoint, v.Error)
+			case v.Missing:
+				console.Printf("      %-30s missing\n", v.Endpoint)
+			default:
+				console.Printf("      %-30s hash %s root %s\n", v.Endpoint, v.Hash.Hex()[:18], v.StateRoot.Hex()[:18])
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xmhha/txhammer/internal/contention"
+	"github.com/0xmhha/txhammer/internal/fixtures"
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/mathutil"
+)
+
+// executeContention has every sub-account increment a counter, first in one
+// slot per sender and then in a single shared slot, and compares the two
+func (p *Pipeline) executeContention(ctx context.Context, result *Result) (*Result, error) {
+	console.Println("Running Contention mode...")
+
+	p.stages = result.Stages
+	if err := p.initialize(ctx); err != nil {
+		result.Finalize()
+		return result, fmt.Errorf("initialization failed: %w", err)
+	}
+	if !p.runCfg.SkipDistribution {
+		if err := p.distribute(ctx); err != nil {
+			result.Finalize()
+			return result, err
+		}
+	}
+
+	gasPrice, err := p.legacyGasPrice(ctx)
+	if err != nil {
+		result.Finalize()
+		return result, err
+	}
+	txs, err := mathutil.Uint64ToInt(p.cfg.Transactions)
+	if err != nil {
+		result.Finalize()
+		return result, fmt.Errorf("transaction count overflow: %w", err)
+	}
+
+	contentionCfg := contention.DefaultConfig()
+	contentionCfg.ChainID = p.chainID
+	contentionCfg.GasPrice = gasPrice
+	contentionCfg.GasLimit = p.cfg.GasLimit
+	contentionCfg.Transactions = txs
+	if p.cfg.Timeout > 0 {
+		contentionCfg.ReceiptTimeout = p.cfg.Timeout
+	}
+	tester := contention.New(p.client, contentionCfg)
+
+	if p.cfg.Contract != "" {
+		contentionCfg.Contract = common.HexToAddress(p.cfg.Contract)
+	} else if contentionCfg.Contract, err = p.deployCounter(ctx, tester); err != nil {
+		result.Finalize()
+		return result, err
+	}
+
+	console.Printf("\nStarting Contention Test\n\n")
+	console.Printf("  Counter:          %s\n", contentionCfg.Contract.Hex())
+	console.Printf("  Txs per Variant:  %d\n", contentionCfg.Transactions)
+	console.Printf("  Senders:          %d\n", len(p.subKeys()))
+
+	contentionResult, err := tester.Run(ctx, p.subKeys())
+	if contentionResult != nil {
+		contention.PrintResult(contentionResult)
+	}
+
+	result.Finalize()
+	if err != nil {
+		return result, fmt.Errorf("contention test failed: %w", err)
+	}
+	return result, nil
+}
+
+// deployCounter deploys or reuses the counter contract from the master account
+func (p *Pipeline) deployCounter(ctx context.Context, tester *contention.Tester) (common.Address, error) {
+	fm, err := fixtures.NewManager(p.client, p.chainID, p.runCfg.FixtureCache)
+	if err != nil {
+		return common.Address{}, err
+	}
+	fm.WithRedeploy(p.runCfg.Redeploy)
+
+	build := func(_ context.Context, signer txbuilder.Signer, nonce uint64) (*txbuilder.SignedTx, error) {
+		tx, err := txbuilder.SignTransaction(tester.DeployTx(nonce), p.chainID, signer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign counter deployment: %w", err)
+		}
+		return &txbuilder.SignedTx{Tx: tx, Hash: tx.Hash(), From: signer.Address(), Nonce: nonce}, nil
+	}
+	return p.ensureFixture(ctx, fm, fixtures.Counter, build)
+}
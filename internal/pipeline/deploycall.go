@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// deployPollInterval is how often deployment receipts are polled
+const deployPollInterval = 500 * time.Millisecond
+
+// prepareDeployThenCall deploys --deploy-count fresh contracts from the
+// master account, waits for their receipts and hands the addresses to the
+// DEPLOY_THEN_CALL builder
+func (p *Pipeline) prepareDeployThenCall(ctx context.Context) error {
+	builder, ok := p.builder.(*txbuilder.DeployThenCallBuilder)
+	if !ok {
+		return nil
+	}
+
+	master := p.masterSigner()
+	nonce, err := p.client.PendingNonceAt(ctx, master.Address())
+	if err != nil {
+		return fmt.Errorf("failed to get master nonce: %w", err)
+	}
+
+	deployTxs, err := builder.GetDeployTransactions(ctx, master, nonce, p.cfg.DeployCount)
+	if err != nil {
+		return fmt.Errorf("failed to build deployments: %w", err)
+	}
+	console.Printf("\nDeploying %d contracts from the master account...\n", len(deployTxs))
+	for _, tx := range deployTxs {
+		if err := p.client.SendTransaction(ctx, tx.Tx); err != nil {
+			return fmt.Errorf("failed to send deployment %s: %w", tx.Hash.Hex(), err)
+		}
+	}
+
+	addrs, err := p.awaitDeployments(ctx, deployTxs)
+	if err != nil {
+		return err
+	}
+	for i, addr := range addrs {
+		console.Printf("  #%d %s\n", i, addr.Hex())
+	}
+	console.OKf("Deployed %d contracts\n", len(addrs))
+
+	builder.WithContracts(addrs)
+	return nil
+}
+
+// awaitDeployments polls the deployment receipts until every contract is
+// deployed, in the order of deployTxs
+func (p *Pipeline) awaitDeployments(ctx context.Context, deployTxs []*txbuilder.SignedTx) ([]common.Address, error) {
+	addrs := make([]common.Address, len(deployTxs))
+	pending := len(deployTxs)
+	deadline := time.Now().Add(p.cfg.Timeout)
+
+	for {
+		for i, tx := range deployTxs {
+			if addrs[i] != (common.Address{}) {
+				continue
+			}
+			receipt, err := p.client.TransactionReceipt(ctx, tx.Hash)
+			if err != nil || receipt == nil {
+				continue
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return nil, fmt.Errorf("deployment %s reverted", tx.Hash.Hex())
+			}
+			addrs[i] = receipt.ContractAddress
+			pending--
+		}
+		if pending == 0 {
+			return addrs, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timeout waiting for %d of %d deployments", pending, len(deployTxs))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(deployPollInterval):
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/config"
+)
+
+// noEvents turns off the expected event check of a mode that has one
+const noEvents = "none"
+
+// defaultEvents are the events the transactions of each mode emit when they
+// do their work; modes without an entry are only checked with --expect-event
+var defaultEvents = map[config.Mode][]string{
+	config.ModeERC20Transfer:       {"Transfer(address,address,uint256)"},
+	config.ModeApproveTransferFrom: {"Approval(address,address,uint256)", "Transfer(address,address,uint256)"},
+	config.ModeERC721Mint:          {"Transfer(address,address,uint256)"},
+	config.ModeSwap:                {"Swap(address,uint256,uint256,uint256,uint256,address)"},
+}
+
+// expectedEvents returns the events a successful transaction of the run
+// should emit: those of --expect-event, or the mode's default
+func (p *Pipeline) expectedEvents() []collector.ExpectedEvent {
+	signatures := p.runCfg.ExpectEvents
+	if len(signatures) == 0 {
+		signatures = defaultEvents[p.cfg.GetMode()]
+	}
+	var events []collector.ExpectedEvent
+	for _, signature := range signatures {
+		if signature == noEvents {
+			return nil
+		}
+		events = append(events, collector.NewExpectedEvent(signature))
+	}
+	return events
+}
@@ -0,0 +1,79 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xmhha/txhammer/internal/fixtures"
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+)
+
+// deployTxFunc builds a signed deployment transaction from the given account
+type deployTxFunc func(ctx context.Context, signer txbuilder.Signer, nonce uint64) (*txbuilder.SignedTx, error)
+
+// prepareFixtures deploys or reuses the helper contracts the builder needs
+func (p *Pipeline) prepareFixtures(ctx context.Context) error {
+	fm, err := fixtures.NewManager(p.client, p.chainID, p.runCfg.FixtureCache)
+	if err != nil {
+		return err
+	}
+	fm.WithRedeploy(p.runCfg.Redeploy)
+
+	if err := p.prepareChurn(ctx, fm); err != nil {
+		return err
+	}
+	if err := p.prepareNFT(ctx, fm); err != nil {
+		return err
+	}
+	if err := p.prepareSwap(ctx, fm); err != nil {
+		return err
+	}
+	return p.prepareDeployThenCall(ctx)
+}
+
+// prepareNFT deploys or reuses the NFT collection when no --contract was given
+func (p *Pipeline) prepareNFT(ctx context.Context, fm *fixtures.Manager) error {
+	builder, ok := p.builder.(*txbuilder.ERC721MintBuilder)
+	if !ok || builder.GetContractAddress() != (common.Address{}) {
+		return nil
+	}
+	addr, err := p.ensureFixture(ctx, fm, fixtures.ERC721, builder.GetDeployTransaction)
+	if err != nil {
+		return err
+	}
+	builder.WithContract(addr)
+	return nil
+}
+
+// prepareSwap deploys or reuses the embedded swap pair when neither a pair
+// nor a router was given
+func (p *Pipeline) prepareSwap(ctx context.Context, fm *fixtures.Manager) error {
+	builder, ok := p.builder.(*txbuilder.SwapBuilder)
+	if !ok || builder.Pair() != (common.Address{}) || builder.Router() != (common.Address{}) {
+		return nil
+	}
+	addr, err := p.ensureFixture(ctx, fm, fixtures.SwapPair, builder.GetDeployTransaction)
+	if err != nil {
+		return err
+	}
+	builder.WithPair(addr)
+	return nil
+}
+
+// ensureFixture returns the address of a fixture, deploying it from the
+// master account unless the fixture cache holds a usable deployment
+func (p *Pipeline) ensureFixture(ctx context.Context, fm *fixtures.Manager, name string, build deployTxFunc) (common.Address, error) {
+	master := p.masterSigner()
+	nonce, err := p.client.PendingNonceAt(ctx, master.Address())
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get master nonce: %w", err)
+	}
+
+	deployTx, err := build(ctx, master, nonce)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to build %s deployment: %w", name, err)
+	}
+	return fm.Ensure(ctx, name, deployTx.Tx)
+}
@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/flightrec"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// startFlightRecorder starts appending the live counters of the run to
+// flight_<timestamp>.csv in the output directory for --flight-recorder
+func (p *Pipeline) startFlightRecorder(ctx context.Context) {
+	if !p.runCfg.FlightRecorder || p.runCfg.OutputDir == "" || p.flight != nil {
+		return
+	}
+	if err := os.MkdirAll(p.runCfg.OutputDir, 0o755); err != nil {
+		console.Warnf("Flight recorder disabled: failed to create output directory: %v\n", err)
+		return
+	}
+	path := filepath.Join(p.runCfg.OutputDir, fmt.Sprintf("flight_%s.csv", time.Now().Format("20060102_150405")))
+	rec, err := flightrec.Create(path, p.client, flightrec.DefaultInterval)
+	if err != nil {
+		console.Warnf("Flight recorder disabled: %v\n", err)
+		return
+	}
+	rec.WithCounts(func() (flightrec.Counts, bool) {
+		if p.collector == nil {
+			return flightrec.Counts{}, false
+		}
+		counts := flightrec.Counts{
+			Confirmed: p.collector.GetConfirmedCount(),
+			Reverted:  p.collector.GetFailedCount(),
+			Pending:   p.collector.GetPendingCount(),
+		}
+		return counts, counts.Confirmed+counts.Reverted+counts.Pending > 0
+	})
+	rec.Start(ctx)
+	p.flight = rec
+	p.flightPath = path
+}
+
+// stopFlightRecorder writes the last row and closes the flight recorder. It
+// does nothing when the recorder is not running.
+func (p *Pipeline) stopFlightRecorder(ctx context.Context) {
+	if p.flight == nil {
+		return
+	}
+	rows, err := p.flight.Stop(ctx)
+	p.flight = nil
+	if err != nil {
+		console.Warnf("Flight recorder stopped early: %v\n", err)
+	}
+	p.artifacts = append(p.artifacts, p.flightPath)
+	console.Printf("Flight recorder: %d rows written to %s\n", rows, p.flightPath)
+}
+
+// flightCallbacks counts every send outcome in the flight recorder before
+// passing it on to next
+func (p *Pipeline) flightCallbacks(next *batcher.Callbacks) *batcher.Callbacks {
+	if p.flight == nil {
+		return next
+	}
+	rec := p.flight
+	return &batcher.Callbacks{
+		OnSent: func(r *batcher.TxResult) {
+			rec.Sent()
+			if next != nil && next.OnSent != nil {
+				next.OnSent(r)
+			}
+		},
+		OnFailed: func(r *batcher.TxResult) {
+			rec.Failed()
+			if next != nil && next.OnFailed != nil {
+				next.OnFailed(r)
+			}
+		},
+	}
+}
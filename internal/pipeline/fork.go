@@ -0,0 +1,210 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/fork"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// errEphemeralFork rejects fork comparisons on ephemeral sub-accounts, whose
+// funds only the main command reclaims
+var errEphemeralFork = errors.New("fork comparison needs sub-accounts that outlive the run: drop --ephemeral-accounts or load them with --key-file")
+
+// ExecuteFork runs the configured workload twice, once in the blocks right
+// before a fork activation and once right after it, and compares the two.
+// The before phase stops sending at the activation block, so that its load
+// stays on the old rules.
+func (p *Pipeline) ExecuteFork(ctx context.Context, forkCfg *fork.Config) (*fork.Result, error) {
+	if err := forkCfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fork config: %w", err)
+	}
+	if p.wallet.Ephemeral() {
+		return nil, errEphemeralFork
+	}
+	if mode := p.cfg.GetMode(); !mixable(mode) {
+		return nil, fmt.Errorf("%s mode runs its own send loop and cannot be compared across a fork", mode)
+	}
+
+	console.Printf("\nStarting Fork Comparison\n\n")
+	console.Printf("  Activation:      #%d\n", forkCfg.ActivationBlock)
+	console.Printf("  Before phase:    from #%d\n", forkCfg.StartBlock())
+	console.Printf("  Txs per phase:   %d\n", p.cfg.Transactions)
+
+	// Per-phase reports are summarized in the comparison instead of exported.
+	// The stop block holds the before phase back from the activation and
+	// makes initialization fail if the chain is already past it.
+	exportReport := p.runCfg.ExportReport
+	runCfg := *p.runCfg
+	runCfg.ExportReport = false
+	runCfg.StopAtBlock = forkCfg.ActivationBlock
+	p.runCfg = &runCfg
+
+	// Fund sub-accounts for both phases up front
+	txs := p.cfg.Transactions
+	p.cfg.Transactions = 2 * txs
+	p.stages = &StageMetrics{}
+	if err := p.initialize(ctx); err != nil {
+		return nil, fmt.Errorf("initialization failed: %w", err)
+	}
+	fund := p.distribute
+	if p.runCfg.SkipDistribution {
+		fund = p.verifyFunding
+	}
+	if err := fund(ctx); err != nil {
+		return nil, err
+	}
+	p.cfg.Transactions = txs
+
+	result := &fork.Result{ActivationBlock: forkCfg.ActivationBlock}
+	console.Printf("\nWaiting for block #%d to start the before phase...\n", forkCfg.StartBlock())
+	if err := p.waitForBlock(ctx, forkCfg.StartBlock()); err != nil {
+		return result, err
+	}
+	result.Before = p.runForkPhase(ctx, "before", true)
+	if result.Before.Err == nil {
+		result.Before.Outside = includedFrom(p.report, forkCfg.ActivationBlock)
+	}
+
+	console.Printf("\nWaiting for activation block #%d to start the after phase...\n", forkCfg.ActivationBlock)
+	if err := p.waitForBlock(ctx, forkCfg.ActivationBlock); err != nil {
+		return result, err
+	}
+	result.After = p.runForkPhase(ctx, "after", false)
+
+	fork.PrintTable(result)
+	if exportReport && p.runCfg.OutputDir != "" {
+		file, err := writeFork(p.runCfg.OutputDir, result.Summary())
+		if err != nil {
+			console.Warnf("Failed to export fork comparison: %v\n", err)
+		} else {
+			console.Printf("Fork comparison exported to: %s\n", file)
+		}
+	}
+	return result, ctx.Err()
+}
+
+// runForkPhase builds, sends and collects one phase of a fork comparison.
+// With cutoff, sending stops once the chain reaches the activation block.
+func (p *Pipeline) runForkPhase(ctx context.Context, name string, cutoff bool) *fork.Phase {
+	phase := &fork.Phase{Name: name}
+	console.Printf("\nPhase: %s activation\n", name)
+
+	// Each phase starts from the node's view of each account
+	p.nonces = nil
+	p.nonceSnap = nil
+	p.unsentAtStop = 0
+	p.throttledSends = 0
+	p.stages = &StageMetrics{}
+
+	head, err := p.client.BlockNumber(ctx)
+	if err != nil {
+		phase.Err = fmt.Errorf("failed to get block number: %w", err)
+		return phase
+	}
+	phase.StartBlock = head
+
+	if err := p.build(ctx); err != nil {
+		phase.Err = err
+		return phase
+	}
+
+	p.blockStop = nil
+	stopBlockStop := func() {}
+	if cutoff {
+		stopBlockStop = p.startBlockStop(ctx)
+	}
+	defer stopBlockStop()
+	var stop batcher.Cutoff
+	if p.blockStop != nil {
+		stop = p.blockStop
+	}
+	p.batcher.WithCutoff(stop)
+	if p.streamer != nil {
+		p.streamer.WithCutoff(stop)
+	}
+	err = p.send(ctx)
+	stopBlockStop()
+	p.recordBlockStop(&Result{})
+	if err != nil {
+		phase.Err = err
+		return phase
+	}
+
+	if err := p.collect(ctx); err != nil {
+		phase.Err = err
+		return phase
+	}
+
+	if send := p.stages.Send; send != nil {
+		phase.Sent = send.TxsSent
+		phase.Unsent = send.TxsUnsent
+		phase.SendTPS = send.RPCThroughput
+	}
+	if m := p.report.Metrics; m != nil {
+		phase.Confirmed = m.TotalConfirmed
+		phase.Failed = m.TotalFailed
+		phase.ChainTPS = m.ChainTPS
+		phase.AvgLatency = m.AvgLatency
+		phase.P95Latency = m.P95Latency
+		phase.AvgGasUsed = m.AvgGasUsed
+		phase.TotalGasUsed = m.TotalGasUsed
+	}
+	return phase
+}
+
+// waitForBlock polls the chain head until it reaches height
+func (p *Pipeline) waitForBlock(ctx context.Context, height uint64) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if head, err := p.client.BlockNumber(ctx); err == nil && head >= height {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// includedFrom counts the test transactions included at or after height
+func includedFrom(report *collector.Report, height uint64) int {
+	if report == nil {
+		return 0
+	}
+	n := 0
+	for _, b := range report.Blocks {
+		if b.Number >= height {
+			n += b.OurTxCount
+		}
+	}
+	return n
+}
+
+// writeFork writes the summary of a fork comparison to the output directory
+func writeFork(outputDir string, summary *fork.Summary) (string, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal fork comparison: %w", err)
+	}
+
+	filename := filepath.Join(outputDir, fmt.Sprintf("fork_%s.json", time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write fork comparison: %w", err)
+	}
+	return filename, nil
+}
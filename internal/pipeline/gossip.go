@@ -0,0 +1,192 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/gossip"
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/units"
+)
+
+// sendGossip sends the signed txs over devp2p. With --p2p-compare the second
+// half of the accounts goes over devp2p while the first half goes over RPC
+// at the same time.
+func (p *Pipeline) sendGossip(ctx context.Context, onSent *batcher.Callbacks) error {
+	gossipCfg := gossip.DefaultConfig()
+	gossipCfg.Enode = p.runCfg.P2PEnode
+	sender, err := gossip.Dial(ctx, gossipCfg)
+	if err != nil {
+		return fmt.Errorf("devp2p connection failed: %w", err)
+	}
+	defer sender.Close()
+	console.Printf("Connected to %s over devp2p (eth/68)\n", sender.PeerName())
+
+	if p.watchdog != nil {
+		sender.WithGate(p.watchdog)
+	}
+	if onSent != nil {
+		sender.WithCallbacks(&gossip.Callbacks{OnSent: func(tx *txbuilder.SignedTx, sentAt time.Time) {
+			onSent.OnSent(&batcher.TxResult{Tx: tx, Hash: tx.Hash, Status: batcher.TxStatusSent, SentAt: sentAt})
+		}})
+	}
+
+	if !p.runCfg.P2PCompare {
+		metrics, err := p.gossipAll(ctx, sender, p.signedTxs)
+		p.stages.Send = metrics
+		return err
+	}
+
+	// Split by account so each account's nonces stay on one path
+	keys := p.subKeys()
+	if len(keys) < 2 {
+		return fmt.Errorf("p2p-compare needs at least 2 sub-accounts")
+	}
+	p.gossipAccounts = make(map[common.Address]bool, len(keys)-len(keys)/2)
+	for _, key := range keys[len(keys)/2:] {
+		p.gossipAccounts[crypto.PubkeyToAddress(key.PublicKey)] = true
+	}
+	var rpcTxs, gossipTxs []*txbuilder.SignedTx
+	for _, tx := range p.signedTxs {
+		if p.gossipAccounts[tx.From] {
+			gossipTxs = append(gossipTxs, tx)
+		} else {
+			rpcTxs = append(rpcTxs, tx)
+		}
+	}
+
+	var (
+		wg                        sync.WaitGroup
+		rpcMetrics, gossipMetrics *SendMetrics
+		rpcErr, gossipErr         error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		rpcMetrics, rpcErr = p.sendRPC(ctx, rpcTxs, onSent)
+	}()
+	go func() {
+		defer wg.Done()
+		gossipMetrics, gossipErr = p.gossipAll(ctx, sender, gossipTxs)
+	}()
+	wg.Wait()
+
+	p.stages.Send = combineTransports(
+		transportMetrics(rpcMetrics, len(keys)/2),
+		transportMetrics(gossipMetrics, len(keys)-len(keys)/2),
+	)
+	if rpcErr != nil {
+		return rpcErr
+	}
+	return gossipErr
+}
+
+// gossipAll sends txs over an open devp2p connection
+func (p *Pipeline) gossipAll(ctx context.Context, sender *gossip.Sender, txs []*txbuilder.SignedTx) (*SendMetrics, error) {
+	result, err := sender.Send(ctx, txs)
+
+	// Txs after a connection failure were never written
+	var failed []*batcher.TxResult
+	for _, tx := range txs[result.SentCount:] {
+		failed = append(failed, &batcher.TxResult{Tx: tx, Hash: tx.Hash, Status: batcher.TxStatusFailed, Error: result.Error})
+	}
+	p.recordSendFailures(failed)
+
+	return &SendMetrics{
+		Method:        "devp2p",
+		TxsSent:       result.SentCount,
+		TxsFailed:     result.FailedCount,
+		RPCThroughput: result.TxPerSecond,
+		BytesSent:     result.TotalBytes,
+		Bandwidth:     result.BytesPerSec,
+	}, err
+}
+
+// transportMetrics turns the metrics of one send path into its comparison row
+func transportMetrics(m *SendMetrics, accounts int) *TransportMetrics {
+	if m == nil {
+		return nil
+	}
+	return &TransportMetrics{
+		Method:     m.Method,
+		Accounts:   accounts,
+		TxsSent:    m.TxsSent,
+		TxsFailed:  m.TxsFailed,
+		Throughput: m.RPCThroughput,
+		Bandwidth:  m.Bandwidth,
+	}
+}
+
+// combineTransports sums the send paths of a --p2p-compare run, which ran side by side
+func combineTransports(transports ...*TransportMetrics) *SendMetrics {
+	combined := &SendMetrics{Method: "rpc+devp2p"}
+	for _, t := range transports {
+		if t == nil {
+			continue
+		}
+		combined.TxsSent += t.TxsSent
+		combined.TxsFailed += t.TxsFailed
+		combined.RPCThroughput += t.Throughput
+		combined.Bandwidth += t.Bandwidth
+		combined.Transports = append(combined.Transports, t)
+	}
+	return combined
+}
+
+// compareTransports attributes confirmations to the send path of their sender
+func (p *Pipeline) compareTransports(report *collector.Report) {
+	if p.gossipAccounts == nil || p.stages.Send == nil {
+		return
+	}
+	var rpcPath, gossipPath *TransportMetrics
+	for _, t := range p.stages.Send.Transports {
+		if t.Method == "devp2p" {
+			gossipPath = t
+		} else {
+			rpcPath = t
+		}
+	}
+
+	latency := make(map[*TransportMetrics]time.Duration)
+	err := report.EachTransaction(func(tx *collector.TxInfo) error {
+		if tx.Status != collector.TxConfirmSuccess {
+			return nil
+		}
+		t := rpcPath
+		if p.gossipAccounts[tx.From] {
+			t = gossipPath
+		}
+		if t != nil {
+			t.Confirmed++
+			latency[t] += tx.Latency
+		}
+		return nil
+	})
+	if err != nil {
+		console.Warnf("Failed to compare transports: %v\n", err)
+		return
+	}
+	for t, total := range latency {
+		t.AvgLatency = (total / time.Duration(t.Confirmed)).String()
+	}
+}
+
+// printTransports prints the side-by-side comparison of a --p2p-compare run
+func printTransports(transports []*TransportMetrics) {
+	for _, t := range transports {
+		line := fmt.Sprintf("              %-9s %d accounts, %d sent (%.2f tx/s, %s/s), %d confirmed",
+			t.Method+":", t.Accounts, t.TxsSent, t.Throughput, units.FormatBytes(t.Bandwidth), t.Confirmed)
+		if t.AvgLatency != "" {
+			line += ", avg latency " + t.AvgLatency
+		}
+		console.Summaryln(line)
+	}
+}
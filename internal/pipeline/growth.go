@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xmhha/txhammer/internal/growth"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// executeAccountGrowth creates, funds and retires fresh accounts at a growing
+// rate instead of sending from the fixed sub-account set
+func (p *Pipeline) executeAccountGrowth(ctx context.Context, result *Result) (*Result, error) {
+	console.Println("Running Account Growth mode...")
+
+	p.stages = result.Stages
+	if err := p.initialize(ctx); err != nil {
+		result.Finalize()
+		return result, fmt.Errorf("initialization failed: %w", err)
+	}
+
+	gasPrice, err := p.legacyGasPrice(ctx)
+	if err != nil {
+		result.Finalize()
+		return result, err
+	}
+
+	growthCfg := growth.DefaultConfig()
+	growthCfg.ChainID = p.chainID
+	growthCfg.GasPrice = gasPrice
+	growthCfg.GasLimit = p.cfg.GasLimit
+	growthCfg.Rate = p.cfg.GrowthRate
+	growthCfg.Acceleration = p.cfg.GrowthAcceleration
+	growthCfg.TxsPerAccount = p.cfg.GrowthTxs
+	growthCfg.Duration = p.cfg.Duration
+	if p.cfg.Timeout > 0 {
+		growthCfg.FundTimeout = p.cfg.Timeout
+	}
+	runner := growth.New(p.client, growthCfg)
+
+	console.Printf("\nStarting Account Growth Test\n\n")
+	console.Printf("  Account Rate:     %.2f/s (+%.0f%%/min)\n", growthCfg.Rate, growthCfg.Acceleration)
+	console.Printf("  Txs per Account:  %d\n", growthCfg.TxsPerAccount)
+	console.Printf("  Funding:          %s per account\n", p.units().Format(runner.FundingAmount()))
+	console.Printf("  Duration:         %s\n", growthCfg.Duration)
+	if growthCfg.Duration == 0 {
+		console.Println("Press Ctrl+C to stop")
+	}
+
+	growthResult, err := runner.Run(ctx, p.masterSigner())
+	if growthResult != nil {
+		growth.PrintResult(growthResult)
+	}
+
+	result.Finalize()
+	if err != nil {
+		return result, fmt.Errorf("account growth test failed: %w", err)
+	}
+	return result, nil
+}
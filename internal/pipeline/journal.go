@@ -0,0 +1,112 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/buildinfo"
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/journal"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// openJournal starts a new run in the --journal file, or returns nil when
+// no journal is configured
+func (p *Pipeline) openJournal() (*journal.Writer, error) {
+	if p.runCfg.Journal == "" {
+		return nil, nil
+	}
+	w, err := journal.Create(p.runCfg.Journal, journal.Header{
+		ChainID:   p.cfg.ChainID,
+		Mode:      string(p.cfg.GetMode()),
+		StartedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	console.Printf("Journaling sent transactions to: %s\n", w.Path())
+	return w, nil
+}
+
+// closeJournal syncs the journal to disk
+func closeJournal(w *journal.Writer) {
+	if err := w.Close(); err != nil {
+		console.Warnf("Failed to close journal: %v\n", err)
+	}
+}
+
+// journalCallbacks appends every acknowledged transaction to the journal
+// before passing it on to next. A failing write is reported once and does
+// not stop sending.
+func journalCallbacks(w *journal.Writer, next *batcher.Callbacks) *batcher.Callbacks {
+	var warnOnce sync.Once
+	var onFailed func(*batcher.TxResult)
+	if next != nil {
+		onFailed = next.OnFailed
+	}
+	return &batcher.Callbacks{OnFailed: onFailed, OnSent: func(r *batcher.TxResult) {
+		err := w.Append(journal.Entry{
+			Hash: r.Tx.Hash, From: r.Tx.From, Nonce: r.Tx.Nonce, GasLimit: r.Tx.GasLimit, SentAt: r.SentAt,
+		})
+		if err != nil {
+			warnOnce.Do(func() {
+				console.Warnf("Journal write failed, later sends may be missing from it: %v\n", err)
+			})
+		}
+		if next != nil && next.OnSent != nil {
+			next.OnSent(r)
+		}
+	}}
+}
+
+// CollectJournal collects the receipts of the transactions recorded in a
+// journal without sending anything, and exports the report and manifest like
+// the collect stage of a run. It needs only cfg.URL and cfg.Timeout, so it
+// works after a crash or a fire-and-forget run. Blocks are not tracked, since
+// the transactions were sent before collection starts.
+func CollectJournal(ctx context.Context, cfg *config.Config, runCfg *RunConfig, build buildinfo.Info, j *journal.Journal) (*collector.Report, error) {
+	chainID, err := j.ChainID()
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.New(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	defer cli.Close()
+
+	nodeChainID, err := cli.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if nodeChainID.Uint64() != chainID {
+		return nil, fmt.Errorf("journal was written on chain %d, but the node is chain %d", chainID, nodeChainID)
+	}
+	cfg.ChainID = chainID
+	if cfg.Mode == "" {
+		cfg.Mode = j.Headers[0].Mode
+	}
+
+	p := &Pipeline{cfg: cfg, runCfg: runCfg, client: cli, buildInfo: build, stages: &StageMetrics{}}
+	collCfg := p.collectorConfig()
+	collCfg.BlockTrackingEnabled = false
+	p.collector = collector.New(cli, collCfg)
+	for _, e := range j.Entries {
+		p.collector.TrackTransaction(e.Hash, e.From, e.Nonce, e.GasLimit, e.SentAt)
+	}
+
+	defer p.writeManifest()
+	err = p.collect(ctx)
+	if p.report == nil {
+		return nil, err
+	}
+	if reportErr := p.generateReport(ctx); reportErr != nil && err == nil {
+		err = reportErr
+	}
+	return p.report, err
+}
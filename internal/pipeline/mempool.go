@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/mempool"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// snapshotMempool snapshots the pool before the run for --mempool-diff
+func (p *Pipeline) snapshotMempool(ctx context.Context) {
+	if !p.runCfg.MempoolDiff {
+		return
+	}
+	snap, err := mempool.Take(ctx, p.client)
+	if err != nil {
+		console.Warnf("Mempool diff disabled: %v\n", err)
+		return
+	}
+	p.mempoolBefore = snap
+	console.Printf("Mempool snapshot: %d txs in the pool\n", len(snap.Txs))
+}
+
+// diffMempool snapshots the pool after the run and compares it with the
+// snapshot taken before
+func (p *Pipeline) diffMempool(ctx context.Context) {
+	if p.mempoolBefore == nil {
+		return
+	}
+	after, err := mempool.Take(ctx, p.client)
+	if err != nil {
+		console.Warnf("Failed to snapshot mempool after the run: %v\n", err)
+		return
+	}
+
+	ours := map[common.Address]bool{p.masterSigner().Address(): true}
+	for _, key := range p.wallet.SubKeys() {
+		ours[crypto.PubkeyToAddress(key.PublicKey)] = true
+	}
+	impact, err := mempool.Compare(ctx, p.client, p.mempoolBefore, after, func(addr common.Address) bool { return ours[addr] })
+	if err != nil {
+		console.Warnf("Failed to compare mempool snapshots: %v\n", err)
+		return
+	}
+	p.stages.Mempool = impact
+}
+
+// printMempool prints the impact of the run on foreign transactions in the pool
+func printMempool(impact *mempool.Impact) {
+	if impact == nil {
+		return
+	}
+	console.Summaryf("\nMempool Impact (foreign txs):\n")
+	console.Summaryf("  Before:    %d in the pool (%d of ours)\n", impact.ForeignBefore, impact.OursBefore)
+	console.Summaryf("  Included:  %d\n", impact.Included)
+	console.Summaryf("  Delayed:   %d still in the pool\n", impact.Delayed)
+	console.Summaryf("  Arrived:   %d during the run\n", impact.Arrived)
+	console.Summaryf("  After:     %d in the pool (%d of ours)\n", impact.ForeignAfter, impact.OursAfter)
+	if impact.Displaced == 0 {
+		console.Summaryf("  Displaced: 0\n")
+		return
+	}
+	console.Warnf("Displaced: %d foreign txs left the pool unmined\n", impact.Displaced)
+	for _, hash := range impact.DisplacedTxs {
+		console.Summaryf("  - %s\n", hash.Hex())
+	}
+}
@@ -0,0 +1,279 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/buildinfo"
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/mix"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// mixGate lines up the workloads of a mix run: one at a time sets up, since
+// initialization and distribution send from the shared master account, and
+// none starts sending before every workload has built its transactions
+type mixGate struct {
+	setup sync.Mutex
+	ready sync.WaitGroup
+}
+
+// enterGate waits until no other workload of a mix run is setting up
+func (p *Pipeline) enterGate() {
+	if p.gate == nil {
+		return
+	}
+	p.gate.setup.Lock()
+	p.gateHeld = true
+}
+
+// passGate lets the next workload of a mix run set up and marks this one as
+// ready. With wait it then blocks until every workload is ready to send.
+func (p *Pipeline) passGate(wait bool) {
+	if p.gate == nil {
+		return
+	}
+	p.gateOnce.Do(func() {
+		if p.gateHeld {
+			p.gate.setup.Unlock()
+		}
+		p.gate.ready.Done()
+	})
+	if wait {
+		p.gate.ready.Wait()
+	}
+}
+
+// WorkloadResult is the outcome of one workload of a mix run
+type WorkloadResult struct {
+	Name         string
+	Workload     mix.Workload
+	Transactions uint64
+	SubAccounts  uint64
+	Result       *Result
+	Err          error // Error Execute returned
+}
+
+// MixResult holds the outcome of every workload of a mix run
+type MixResult struct {
+	Workloads []*WorkloadResult
+}
+
+// Success reports whether every workload completed successfully
+func (r *MixResult) Success() bool {
+	for _, w := range r.Workloads {
+		if w.Err != nil || w.Result == nil || !w.Result.Success() {
+			return false
+		}
+	}
+	return true
+}
+
+// MixSummary is the machine-readable result of a mix run
+type MixSummary struct {
+	Success   bool              `json:"success"`
+	Workloads []WorkloadSummary `json:"workloads"`
+}
+
+// WorkloadSummary is one workload of a mix run
+type WorkloadSummary struct {
+	Name         string      `json:"name"`
+	Mode         string      `json:"mode"`
+	TargetTPS    float64     `json:"target_tps"`
+	Transactions uint64      `json:"transactions"`
+	SubAccounts  uint64      `json:"sub_accounts"`
+	Summary      *RunSummary `json:"summary"`
+}
+
+// Summary returns the machine-readable summary of the mix run
+func (r *MixResult) Summary() *MixSummary {
+	s := &MixSummary{Success: r.Success(), Workloads: make([]WorkloadSummary, 0, len(r.Workloads))}
+	for _, w := range r.Workloads {
+		ws := WorkloadSummary{
+			Name:         w.Name,
+			Mode:         w.Workload.Mode,
+			TargetTPS:    w.Workload.TPS,
+			Transactions: w.Transactions,
+			SubAccounts:  w.SubAccounts,
+		}
+		if w.Result != nil {
+			ws.Summary = w.Result.Summary(w.Err)
+		}
+		s.Workloads = append(s.Workloads, ws)
+	}
+	return s
+}
+
+// mixable reports whether mode runs through the standard pipeline and so can
+// be a workload of a mix run. The other modes drive their own send loops.
+func mixable(mode config.Mode) bool {
+	switch mode {
+	case config.ModeAnalyzeBlocks, config.ModeLongSender, config.ModeTargetUtilization, config.ModeConflict,
+		config.ModeAccountGrowth, config.ModeContention, config.ModeChain, config.ModeScenario:
+		return false
+	}
+	return true
+}
+
+// ExecuteMix runs the --workload workloads of runCfg concurrently, each as
+// its own streaming pipeline at its own rate. The --transactions and
+// --sub-accounts totals are split between the workloads in proportion to
+// their rates, and every workload derives a separate range of sub-accounts.
+// Reports of each workload go to a subdirectory of the output directory.
+func ExecuteMix(ctx context.Context, cfg *config.Config, runCfg *RunConfig, build buildinfo.Info) (*MixResult, error) {
+	workloads, err := mix.ParseAll(runCfg.Workloads)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.KeyFile != "" {
+		return nil, fmt.Errorf("workload cannot split the accounts of a key file: derive them from --private-key or --mnemonic")
+	}
+	if cfg.MetricsEnabled || cfg.PushGateway != "" {
+		return nil, fmt.Errorf("workload cannot be combined with metrics or pushgateway")
+	}
+	txs, err := mix.Split(cfg.Transactions, workloads)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transactions for workloads: %w", err)
+	}
+	accounts, err := mix.Split(cfg.SubAccounts, workloads)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sub-accounts for workloads: %w", err)
+	}
+
+	// Validate every workload before any of them starts
+	cfgs := make([]*config.Config, len(workloads))
+	runCfgs := make([]*RunConfig, len(workloads))
+	result := &MixResult{Workloads: make([]*WorkloadResult, len(workloads))}
+	var offset uint64
+	for i, w := range workloads {
+		name := w.Name(i)
+		if !mixable(config.Mode(w.Mode)) {
+			return nil, fmt.Errorf("workload %s: %s mode runs its own send loop and cannot be mixed", name, w.Mode)
+		}
+		c := *cfg
+		c.Mode = w.Mode
+		c.Transactions = txs[i]
+		c.SubAccounts = accounts[i]
+		c.SubAccountOffset = cfg.SubAccountOffset + offset
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("workload %s: %w", name, err)
+		}
+		offset += accounts[i]
+		cfgs[i] = &c
+
+		r := *runCfg
+		r.Workloads = nil
+		r.StreamingMode = true
+		r.StreamingRate = w.TPS
+		if r.OutputDir != "" {
+			r.OutputDir = filepath.Join(r.OutputDir, name)
+		}
+		r.Journal = workloadPath(r.Journal, name)
+		r.NonceSnapshot = workloadPath(r.NonceSnapshot, name)
+		r.TraceFile = workloadPath(r.TraceFile, name)
+		runCfgs[i] = &r
+
+		result.Workloads[i] = &WorkloadResult{Name: name, Workload: w, Transactions: txs[i], SubAccounts: accounts[i]}
+	}
+
+	gate := &mixGate{}
+	pipelines := make([]*Pipeline, 0, len(workloads))
+	defer func() {
+		for _, p := range pipelines {
+			p.Close()
+		}
+	}()
+	for i := range workloads {
+		p, err := New(cfgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("workload %s: %w", result.Workloads[i].Name, err)
+		}
+		p.WithRunConfig(runCfgs[i]).WithBuildInfo(build)
+		p.gate = gate
+		pipelines = append(pipelines, p)
+	}
+
+	console.Printf("\nStarting Workload Mix\n\n")
+	for _, w := range result.Workloads {
+		console.Printf("  %-22s %8.1f tx/s  %8d txs  %5d accounts\n", w.Name, w.Workload.TPS, w.Transactions, w.SubAccounts)
+	}
+
+	gate.ready.Add(len(pipelines))
+	var wg sync.WaitGroup
+	for i, p := range pipelines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A workload that ends before its send stage must not hold up the others
+			defer p.passGate(false)
+			w := result.Workloads[i]
+			w.Result, w.Err = p.Execute(ctx)
+		}()
+	}
+	wg.Wait()
+
+	if !runCfg.JSONSummary {
+		printMix(result)
+	}
+	if runCfg.ExportReport && runCfg.OutputDir != "" {
+		file, err := writeMix(runCfg.OutputDir, result.Summary())
+		if err != nil {
+			console.Warnf("Failed to export workload mix summary: %v\n", err)
+		} else {
+			console.Printf("Workload mix summary exported to: %s\n", file)
+		}
+	}
+	return result, nil
+}
+
+// workloadPath inserts the workload name before the extension of path, so
+// that concurrent workloads do not share the file ("" stays "")
+func workloadPath(path, name string) string {
+	if path == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + name + ext
+}
+
+// printMix prints the merged table of a mix run
+func printMix(result *MixResult) {
+	rows := make([]mix.Row, 0, len(result.Workloads))
+	for _, w := range result.Workloads {
+		row := mix.Row{Name: w.Name, TargetTPS: w.Workload.TPS, Err: w.Err}
+		if r := w.Result; r != nil {
+			row.Sent = r.TotalTransactions
+			row.Confirmed = r.SuccessfulTxs
+			row.Failed = r.FailedTxs
+			row.ConfirmedTPS = r.WallClockConfirmedTPS
+			row.AvgLatency = r.AvgLatency
+			row.P95Latency = r.P95Latency
+		}
+		rows = append(rows, row)
+	}
+	mix.PrintTable(rows)
+}
+
+// writeMix writes the summary of a mix run to the output directory
+func writeMix(outputDir string, summary *MixSummary) (string, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal workload mix summary: %w", err)
+	}
+
+	filename := filepath.Join(outputDir, fmt.Sprintf("mix_%s.json", time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write workload mix summary: %w", err)
+	}
+	return filename, nil
+}
@@ -0,0 +1,133 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/longsender"
+	"github.com/0xmhha/txhammer/internal/noncesnap"
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// loadNonceSnapshot loads the nonce snapshot if the run trusts it
+func (p *Pipeline) loadNonceSnapshot(chainID uint64) error {
+	if !p.runCfg.TrustNonceSnapshot || p.runCfg.NonceSnapshot == "" {
+		return nil
+	}
+
+	snap, err := noncesnap.Load(p.runCfg.NonceSnapshot)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			console.Warnf("Nonce snapshot %s not found, querying nonces via RPC\n", p.runCfg.NonceSnapshot)
+			return nil
+		}
+		return err
+	}
+	if err := snap.CheckChain(chainID); err != nil {
+		return err
+	}
+
+	p.nonceSnap = snap
+	console.Printf("Loaded nonce snapshot: %s (%d accounts)\n", p.runCfg.NonceSnapshot, len(snap.Nonces))
+	return nil
+}
+
+// fetchNonces returns the next nonce for each key, taking nonces from the
+// trusted snapshot where available and querying the node for the rest
+func (p *Pipeline) fetchNonces(ctx context.Context, keys []*ecdsa.PrivateKey) ([]uint64, error) {
+	addrs := make([]common.Address, len(keys))
+	for i, key := range keys {
+		addrs[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	nonces := make([]uint64, len(keys))
+	missing := make([]int, len(keys))
+	for i := range missing {
+		missing[i] = i
+	}
+	if p.nonceSnap != nil {
+		nonces, missing = p.nonceSnap.Lookup(addrs)
+		console.Printf("Using %d nonces from snapshot (%d queried via RPC)\n", len(keys)-len(missing), len(missing))
+	}
+
+	for _, i := range missing {
+		nonce, err := p.client.PendingNonceAt(ctx, addrs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nonce for %s: %w", addrs[i].Hex(), err)
+		}
+		nonces[i] = nonce
+	}
+
+	for i, addr := range addrs {
+		p.recordNonce(addr, nonces[i])
+	}
+	return nonces, nil
+}
+
+// recordNonce remembers the starting nonce of an account for the end-of-run snapshot
+func (p *Pipeline) recordNonce(addr common.Address, nonce uint64) {
+	if p.nextNonces == nil {
+		p.nextNonces = make(map[common.Address]uint64)
+	}
+	p.nextNonces[addr] = nonce
+}
+
+// saveNonceSnapshot writes the next nonce of each account after sending.
+// A nonce that failed to send leaves a gap, so the account's next nonce is
+// the lowest failed nonce rather than one past the highest sent nonce.
+func (p *Pipeline) saveNonceSnapshot(failed []*txbuilder.SignedTx) {
+	if p.runCfg.NonceSnapshot == "" {
+		return
+	}
+
+	snap := noncesnap.New(p.cfg.ChainID)
+	if p.nonceSnap != nil {
+		for addr, nonce := range p.nonceSnap.Nonces {
+			snap.Set(addr, nonce)
+		}
+	}
+	for addr, nonce := range p.nextNonces {
+		snap.Set(addr, nonce)
+	}
+
+	for _, tx := range p.signedTxs {
+		if next := tx.Nonce + 1; next > snap.Nonces[tx.From] {
+			snap.Set(tx.From, next)
+		}
+	}
+
+	gaps := make(map[common.Address]uint64)
+	for _, tx := range failed {
+		if lowest, ok := gaps[tx.From]; !ok || tx.Nonce < lowest {
+			gaps[tx.From] = tx.Nonce
+		}
+	}
+	for addr, nonce := range gaps {
+		snap.Set(addr, nonce)
+	}
+
+	if err := snap.Save(p.runCfg.NonceSnapshot); err != nil {
+		console.Warnf("Failed to save nonce snapshot: %v\n", err)
+		return
+	}
+	console.Printf("Nonce snapshot saved to: %s\n", p.runCfg.NonceSnapshot)
+}
+
+// saveLongSenderNonces writes the nonce snapshot after a LONG_SENDER or
+// TARGET_UTILIZATION run, from the nonce the sender left each account at
+func (p *Pipeline) saveLongSenderNonces(keys []*ecdsa.PrivateKey, result *longsender.Result) {
+	if result == nil {
+		return
+	}
+	for i, key := range keys {
+		p.recordNonce(crypto.PubkeyToAddress(key.PublicKey), result.NextNonces[i])
+	}
+	p.saveNonceSnapshot(nil)
+}
@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/noncewatch"
+	"github.com/0xmhha/txhammer/internal/util/compress"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// nonceStallSamples is how many sample intervals a nonce must stand still
+// for to be reported as a stall
+const nonceStallSamples = 3
+
+// maxPrintedStalls is the number of nonce stalls listed in the console
+const maxPrintedStalls = 5
+
+// startNonceWatch starts sampling the sub-account nonces for --nonce-sample-interval
+func (p *Pipeline) startNonceWatch(ctx context.Context) {
+	if p.runCfg.NonceSampleInterval <= 0 {
+		return
+	}
+	p.nonceWatch = noncewatch.New(p.client, p.wallet.SubAddresses(), p.runCfg.NonceSampleInterval)
+	p.nonceWatch.Start(ctx)
+}
+
+// stopNonceWatch stops sampling, writes the nonce dataset and reports the
+// longest stalls. It does nothing when sampling is not running.
+func (p *Pipeline) stopNonceWatch(ctx context.Context) {
+	if p.nonceWatch == nil {
+		return
+	}
+	series := p.nonceWatch.Stop(ctx)
+	p.nonceWatch = nil
+
+	stalls := noncewatch.Stalls(series, nonceStallSamples*series.Interval)
+	p.stages.NonceStalls = stalls
+	printNonceStalls(series, stalls)
+
+	if p.runCfg.OutputDir == "" {
+		return
+	}
+	file, err := writeNonceSeries(p.runCfg.OutputDir, series, compress.Codec(p.runCfg.Compression))
+	if err != nil {
+		console.Warnf("Failed to export nonce progression: %v\n", err)
+		return
+	}
+	p.artifacts = append(p.artifacts, file)
+	console.Printf("Nonce progression exported to: %s\n", file)
+}
+
+// writeNonceSeries writes the nonce samples as CSV to the output directory
+func writeNonceSeries(outputDir string, series *noncewatch.Series, codec compress.Codec) (string, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	filename := filepath.Join(outputDir, fmt.Sprintf("nonces_%s.csv%s", time.Now().Format("20060102_150405"), codec.Ext()))
+	if err := noncewatch.WriteCSV(filename, series, codec); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// printNonceStalls prints the sampling coverage and the longest nonce stalls
+func printNonceStalls(series *noncewatch.Series, stalls []noncewatch.Stall) {
+	console.Printf("\nNonce Progression: %d samples of %d accounts every %s\n", len(series.Samples), len(series.Accounts), series.Interval)
+	if len(stalls) == 0 {
+		return
+	}
+	console.Warnf("  %d accounts stalled mid-run for %s or longer\n", len(stalls), nonceStallSamples*series.Interval)
+	for i, s := range stalls {
+		if i == maxPrintedStalls {
+			console.Printf("    ... and %d more\n", len(stalls)-maxPrintedStalls)
+			break
+		}
+		console.Printf("    - %s at nonce %d for %s from +%s\n", s.Account.Hex(), s.Nonce, s.Duration.Round(time.Millisecond), s.From.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+)
+
+// sendPaced sends txs in --per-block tranches, each released right after a
+// new head
+func (p *Pipeline) sendPaced(ctx context.Context, txs []*txbuilder.SignedTx, onSent *batcher.Callbacks) (*SendMetrics, error) {
+	pacerCfg := &batcher.PacerConfig{TxsPerBlock: p.runCfg.TxsPerBlock}
+	paced, err := p.batcher.WithCallbacks(onSent).SendPaced(ctx, p.client, txs, pacerCfg)
+	if paced == nil || paced.Summary == nil {
+		return nil, err
+	}
+	p.traceBatches(paced.BatchResults)
+	p.recordSendFailures(paced.FailedTxs)
+	return &SendMetrics{
+		Method:        "paced",
+		TxsSent:       paced.SuccessCount,
+		TxsFailed:     paced.FailedCount,
+		TxsExpired:    paced.ExpiredCount,
+		RPCThroughput: paced.TxPerSecond,
+		BytesSent:     paced.TotalBytes,
+		Bandwidth:     paced.BytesPerSec,
+		Batches:       newBatchMetrics(paced.BatchResults),
+		Tranches:      newTrancheMetrics(paced.Tranches),
+		Redundancy:    p.redundancyStats(),
+		Balance:       p.balanceStats(),
+	}, err
+}
+
+// newTrancheMetrics returns the per-tranche outcomes of a paced send
+func newTrancheMetrics(tranches []*batcher.Tranche) []*TrancheMetrics {
+	metrics := make([]*TrancheMetrics, len(tranches))
+	for i, t := range tranches {
+		metrics[i] = &TrancheMetrics{
+			Index:       t.Index,
+			AfterBlock:  t.AfterBlock,
+			MissedHeads: t.MissedHeads,
+			Released:    t.ReleasedAt.Format(time.RFC3339Nano),
+			Duration:    t.Duration.String(),
+			Txs:         t.Txs,
+			Failed:      t.Failed,
+		}
+	}
+	return metrics
+}
+
+// missedHeads returns the heads passed while tranches were still sending
+func missedHeads(tranches []*TrancheMetrics) uint64 {
+	var missed uint64
+	for _, t := range tranches {
+		missed += t.MissedHeads
+	}
+	return missed
+}
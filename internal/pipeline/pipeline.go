@@ -3,25 +3,46 @@ package pipeline
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 
+	"github.com/0xmhha/txhammer/internal/alert"
 	"github.com/0xmhha/txhammer/internal/analyzer"
 	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/blockstop"
+	"github.com/0xmhha/txhammer/internal/budget"
+	"github.com/0xmhha/txhammer/internal/buildinfo"
 	"github.com/0xmhha/txhammer/internal/client"
 	"github.com/0xmhha/txhammer/internal/collector"
 	"github.com/0xmhha/txhammer/internal/config"
 	"github.com/0xmhha/txhammer/internal/distributor"
+	"github.com/0xmhha/txhammer/internal/flightrec"
+	"github.com/0xmhha/txhammer/internal/kms"
 	"github.com/0xmhha/txhammer/internal/longsender"
+	"github.com/0xmhha/txhammer/internal/manifest"
+	"github.com/0xmhha/txhammer/internal/mempool"
 	"github.com/0xmhha/txhammer/internal/metrics"
 	"github.com/0xmhha/txhammer/internal/monitor"
+	"github.com/0xmhha/txhammer/internal/noncesnap"
+	"github.com/0xmhha/txhammer/internal/noncewatch"
+	"github.com/0xmhha/txhammer/internal/trace"
 	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/compress"
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/mathutil"
+	"github.com/0xmhha/txhammer/internal/util/units"
+	"github.com/0xmhha/txhammer/internal/utiltarget"
 	"github.com/0xmhha/txhammer/internal/wallet"
+	"github.com/0xmhha/txhammer/internal/watchdog"
 )
 
 // Pipeline orchestrates the stress test execution
@@ -30,6 +51,7 @@ type Pipeline struct {
 	runCfg  *RunConfig
 	client  *client.Client
 	wallet  *wallet.Wallet
+	master  txbuilder.Signer // Local key or --master-kms key
 	chainID *big.Int
 
 	// Components
@@ -39,9 +61,88 @@ type Pipeline struct {
 	streamer    *batcher.Streamer
 	collector   *collector.Collector
 
+	// Dedicated streaming connections, closed with the pipeline
+	streamConns []*client.Client
+
+	// Redundant sender over --url and --endpoints with --redundancy (nil otherwise)
+	redundant     *batcher.Redundant
+	endpointConns []*client.Client
+
+	// Balanced client over --url and --endpoints with --balance (nil otherwise)
+	balanced *client.Balanced
+
 	// State
-	signedTxs []*txbuilder.SignedTx
-	nonces    []uint64
+	signedTxs  []*txbuilder.SignedTx
+	replayTxs  []*txbuilder.SignedTx // Loaded from --replay-txs (nil otherwise)
+	replayLoad time.Duration
+	poisonTxs  []*txbuilder.PoisonTx
+	nonces     []uint64
+	stages     *StageMetrics
+	report     *collector.Report
+
+	// Nonce snapshot state
+	nonceSnap    *noncesnap.Snapshot
+	nextNonces   map[common.Address]uint64
+	sendFailures []*txbuilder.SignedTx
+	failuresMu   sync.Mutex // Send paths of a --p2p-compare run record failures concurrently
+
+	// Chain halt watchdog (nil when disabled)
+	watchdog *watchdog.Watchdog
+
+	// Spend budget (nil when disabled)
+	budget *budget.Budget
+
+	// Churned contract counts (nil outside CREATE2_CHURN mode)
+	churn *churnStats
+
+	// Send from only the first N sub-accounts (0 = all)
+	accountLimit int
+
+	// Build recorded in the report, metrics and run manifest
+	buildInfo buildinfo.Info
+
+	// Metrics of the scenario the pipeline runs a phase of (nil otherwise)
+	sharedMetrics *metrics.Metrics
+
+	// Files written to the output directory, listed in the run manifest
+	artifacts []string
+
+	// Accounts whose txs go over devp2p in a --p2p-compare run (nil otherwise)
+	gossipAccounts map[common.Address]bool
+
+	// Pool snapshot taken before the run with --mempool-diff (nil otherwise)
+	mempoolBefore *mempool.Snapshot
+
+	// Sub-account nonce sampler for --nonce-sample-interval (nil when not sampling)
+	nonceWatch *noncewatch.Watcher
+
+	// Live counters recorder for --flight-recorder (nil when not recording)
+	flight     *flightrec.Recorder
+	flightPath string
+
+	// Timeline recorded with --trace (nil otherwise)
+	trace *trace.Recorder
+
+	// Failed send exchanges recorded with --capture-failures (nil otherwise)
+	capture *client.Capture
+
+	// Send failure rate watch for --alert-failure-rate (nil otherwise)
+	alert *alert.Monitor
+
+	// Chain head watch for --stop-at-block (nil otherwise) and the txs it left unsent
+	blockStop    *blockstop.Stopper
+	unsentAtStop int
+
+	// Sends refused by provider throttling, guarded by failuresMu
+	throttledSends int
+
+	// Transactions are sent with a zero gas price (--zero-fee or detected)
+	zeroFee bool
+
+	// Turn taking with the other workloads of a --workload run (nil otherwise)
+	gate     *mixGate
+	gateHeld bool
+	gateOnce sync.Once
 }
 
 // New creates a new pipeline instance
@@ -54,23 +155,74 @@ func New(cfg *config.Config) (*Pipeline, error) {
 
 	// Create wallet
 	var w *wallet.Wallet
-	if cfg.Mnemonic != "" {
-		w, err = wallet.NewFromMnemonic(cfg.Mnemonic, cfg.SubAccounts)
-	} else {
-		w, err = wallet.NewFromPrivateKey(cfg.PrivateKey, cfg.SubAccounts)
+	switch {
+	case cfg.KeyFile != "":
+		w, err = wallet.NewFromKeyFile(cfg.PrivateKey, cfg.KeyFile)
+		if err == nil {
+			// The key file decides the number of sub-accounts
+			cfg.SubAccounts = uint64(len(w.SubKeys()))
+		}
+	case cfg.Mnemonic != "":
+		w, err = wallet.NewFromMnemonicAt(cfg.Mnemonic, cfg.SubAccountOffset, cfg.SubAccounts)
+	case cfg.MasterKMS != "":
+		w, err = wallet.NewEphemeral(cfg.SubAccounts)
+	default:
+		w, err = wallet.NewFromPrivateKeyAt(cfg.PrivateKey, cfg.SubAccountOffset, cfg.SubAccounts)
+	}
+	if err == nil && cfg.EphemeralAccounts && !w.Ephemeral() {
+		err = w.GenerateSubKeys(cfg.SubAccounts)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create wallet: %w", err)
 	}
 
+	master, err := openMaster(cfg, w)
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+
 	return &Pipeline{
-		cfg:    cfg,
-		runCfg: DefaultRunConfig(),
-		client: cli,
-		wallet: w,
+		cfg:       cfg,
+		runCfg:    DefaultRunConfig(),
+		client:    cli,
+		wallet:    w,
+		master:    master,
+		buildInfo: buildinfo.New("dev", "unknown", "unknown"),
 	}, nil
 }
 
+// openMaster returns the signer of the master account: the --master-kms key
+// when set, else the wallet's master key
+func openMaster(cfg *config.Config, w *wallet.Wallet) (txbuilder.Signer, error) {
+	if cfg.MasterKMS == "" {
+		if w.MasterKey() == nil {
+			return nil, nil
+		}
+		return txbuilder.NewKeySigner(w.MasterKey()), nil
+	}
+
+	// The KMS HTTP client bounds the call
+	master, err := kms.Open(context.Background(), cfg.MasterKMS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open master KMS key: %w", err)
+	}
+	return master, nil
+}
+
+// WithBuildInfo sets the txhammer build recorded in the report, metrics and run manifest
+func (p *Pipeline) WithBuildInfo(build buildinfo.Info) *Pipeline {
+	p.buildInfo = build
+	return p
+}
+
+// WithMetrics makes the pipeline record to metrics the caller created and
+// stops, such as those shared by the phases of a scenario
+func (p *Pipeline) WithMetrics(m *metrics.Metrics) *Pipeline {
+	p.sharedMetrics = m
+	return p
+}
+
 // WithRunConfig sets the run configuration
 func (p *Pipeline) WithRunConfig(runCfg *RunConfig) *Pipeline {
 	p.runCfg = runCfg
@@ -80,96 +232,278 @@ func (p *Pipeline) WithRunConfig(runCfg *RunConfig) *Pipeline {
 // Execute runs the complete stress test pipeline
 func (p *Pipeline) Execute(ctx context.Context) (*Result, error) {
 	result := NewResult()
-
-	fmt.Println()
-	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                          TxHammer                             ║")
-	fmt.Println("║              StableNet Stress Testing Tool                     ║")
-	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
-	fmt.Println()
-
+	result.Region = p.runCfg.Region
+	result.Workload = p.workload()
+
+	console.Println()
+	console.Println("╔══════════════════════════════════════════════════════════════╗")
+	console.Println("║                          TxHammer                             ║")
+	console.Println("║              StableNet Stress Testing Tool                     ║")
+	console.Println("╚══════════════════════════════════════════════════════════════╝")
+	console.Println()
+
+	defer p.writeManifest()
+	p.startTrace()
+	defer p.writeTrace()
+	p.startCapture()
+	defer p.writeCapture()
+	client.SetThrottleBackoff(p.runCfg.ThrottleBackoff)
 	metricsServer, cleanup := p.setupMetrics(ctx)
-	defer cleanup()
+	defer func() { cleanup(result) }()
+	defer p.reclaim(ctx)
+
+	// The metrics server outlives the deadline so it can still be stopped
+	if p.runCfg.MaxRuntime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, result.StartTime.Add(p.runCfg.MaxRuntime))
+		defer cancel()
+	}
 
 	if res, handled, err := p.handleSpecialModes(ctx, result, metricsServer); handled {
 		return res, err
 	}
 
-	if err := p.runStandardPipeline(ctx, result); err != nil {
+	if err := p.runStandardPipeline(ctx, result, metricsServer); err != nil {
 		return result, err
 	}
 
 	return result, nil
 }
 
-func (p *Pipeline) setupMetrics(ctx context.Context) (server *metrics.Metrics, cleanup func()) {
-	cleanup = func() {}
-	if !p.cfg.MetricsEnabled {
-		return nil, cleanup
+// errEphemeralUnfunded rejects --skip-distribution on ephemeral sub-accounts,
+// those of --ephemeral-accounts and of --master-kms without --key-file, which
+// nothing but the distribution funds
+var errEphemeralUnfunded = errors.New("--skip-distribution needs funded sub-accounts: drop --ephemeral-accounts or load them with --key-file")
+
+const (
+	// reclaimTimeout bounds sweeping the ephemeral sub-accounts after a run
+	reclaimTimeout = 2 * time.Minute
+
+	// reclaimSettleTimeout bounds the wait for the transactions the
+	// sub-accounts still have in flight before they are swept
+	reclaimSettleTimeout = time.Minute
+)
+
+// reclaim sweeps the funds of ephemeral sub-accounts back to the master
+// account once the run is over. It runs even after an interrupt or the run
+// deadline, since the keys are gone when the process exits.
+func (p *Pipeline) reclaim(ctx context.Context) {
+	if !p.wallet.Ephemeral() || p.distributor == nil || p.stages == nil ||
+		p.stages.Distribute == nil || p.stages.Distribute.AccountsFunded == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reclaimTimeout)
+	defer cancel()
+	if _, err := p.distributor.Reclaim(ctx, p.wallet.SubKeys(), p.master.Address(), reclaimSettleTimeout); err != nil {
+		console.Warnf("Failed to reclaim funds from ephemeral sub-accounts: %v\n", err)
 	}
+}
 
-	server = metrics.NewMetrics("txhammer")
-	if err := server.Start(ctx, p.cfg.MetricsPort); err != nil {
-		fmt.Printf("[WARN] Failed to start metrics server: %v\n", err)
+// setupMetrics starts the metrics server if enabled. The returned cleanup
+// records the end-of-run summary gauges, pushes them to the Pushgateway if
+// one is set, and stops the server.
+func (p *Pipeline) setupMetrics(ctx context.Context) (server *metrics.Metrics, cleanup func(*Result)) {
+	cleanup = func(*Result) {}
+	if !p.cfg.MetricsEnabled && p.cfg.PushGateway == "" {
 		return nil, cleanup
 	}
 
-	fmt.Printf("Prometheus metrics available at http://localhost:%d/metrics\n", p.cfg.MetricsPort)
-	cleanup = func() {
+	// Metrics shared by the phases of a scenario outlive the pipeline
+	shared := p.sharedMetrics != nil
+	server = p.sharedMetrics
+	if !shared {
+		if server = startMetrics(ctx, p.cfg, p.runCfg, p.buildInfo); server == nil {
+			return nil, cleanup
+		}
+	}
+
+	cleanup = func(result *Result) {
+		server.RecordRunSummary(runSummary(result))
+		if p.cfg.PushGateway != "" {
+			if err := server.PushRunSummary(ctx, p.cfg.PushGateway, "txhammer"); err != nil {
+				console.Warnf("Failed to push run summary: %v\n", err)
+			}
+		}
+		if shared {
+			return
+		}
 		if err := server.Stop(ctx); err != nil {
-			fmt.Printf("[WARN] Failed to stop metrics server: %v\n", err)
+			console.Warnf("Failed to stop metrics server: %v\n", err)
 		}
 	}
 	return server, cleanup
 }
 
+// startMetrics registers the metrics of a run and serves them if --metrics
+// is set. It returns nil if the server cannot start. The metrics register
+// with the default Prometheus registry, so a process creates them once.
+func startMetrics(ctx context.Context, cfg *config.Config, runCfg *RunConfig, build buildinfo.Info) *metrics.Metrics {
+	server := metrics.NewMetrics("txhammer", metrics.RegionLabels(runCfg.Region))
+	server.RecordBuildInfo(build.Version, build.Commit, build.Date, build.GoVersion)
+	if cfg.MetricsEnabled {
+		if err := server.Start(ctx, cfg.MetricsPort); err != nil {
+			console.Warnf("Failed to start metrics server: %v\n", err)
+			return nil
+		}
+		console.Printf("Prometheus metrics available at http://localhost:%d/metrics\n", cfg.MetricsPort)
+	}
+	return server
+}
+
+// runSummary returns the end-of-run gauges of a result
+func runSummary(result *Result) metrics.RunSummary {
+	s := metrics.RunSummary{
+		Duration:   result.Duration,
+		FinalTPS:   result.ChainTPS,
+		P95Latency: result.P95Latency,
+		GasUsed:    result.TotalGasUsed,
+	}
+	if s.Duration == 0 {
+		s.Duration = time.Since(result.StartTime)
+	}
+	if result.TotalTransactions > 0 {
+		s.SuccessRate = float64(result.SuccessfulTxs) / float64(result.TotalTransactions)
+	}
+	return s
+}
+
 func (p *Pipeline) handleSpecialModes(ctx context.Context, result *Result, metricsServer *metrics.Metrics) (*Result, bool, error) {
-	switch mode := p.cfg.GetMode(); mode {
+	mode := p.cfg.GetMode()
+	if p.runCfg.StopAtBlock > 0 && !stopsAtBlock(mode) {
+		return result, true, fmt.Errorf("--stop-at-block is not supported in %s mode", mode)
+	}
+	if p.runCfg.MaxSpend != "" && !budgeted(mode) {
+		return result, true, fmt.Errorf("--max-spend is not supported in %s mode", mode)
+	}
+	if (p.runCfg.SaveTxs != "" || p.runCfg.ReplayTxs != "") && !mixable(mode) {
+		return result, true, fmt.Errorf("--save-txs and --replay-txs are not supported in %s mode", mode)
+	}
+	if p.runCfg.SkipDistribution && p.wallet.Ephemeral() {
+		return result, true, errEphemeralUnfunded
+	}
+	switch mode {
 	case config.ModeAnalyzeBlocks:
 		res, err := p.executeAnalyzeBlocks(ctx, result)
 		return res, true, err
 	case config.ModeLongSender:
 		res, err := p.executeLongSender(ctx, result, metricsServer)
 		return res, true, err
-	case config.ModeTransfer, config.ModeFeeDelegation, config.ModeContractDeploy, config.ModeContractCall, config.ModeERC20Transfer, config.ModeERC721Mint:
+	case config.ModeTargetUtilization:
+		res, err := p.executeTargetUtilization(ctx, result, metricsServer)
+		return res, true, err
+	case config.ModeConflict:
+		res, err := p.executeConflict(ctx, result)
+		return res, true, err
+	case config.ModeAccountGrowth:
+		res, err := p.executeAccountGrowth(ctx, result)
+		return res, true, err
+	case config.ModeContention:
+		res, err := p.executeContention(ctx, result)
+		return res, true, err
+	case config.ModeChain:
+		res, err := p.executeChain(ctx, result)
+		return res, true, err
+	case config.ModeScenario:
+		return result, true, fmt.Errorf("%s mode runs through ExecuteScenario", mode)
+	case config.ModeTransfer, config.ModeFeeDelegation, config.ModeContractDeploy, config.ModeContractCall, config.ModeERC20Transfer, config.ModeERC721Mint, config.ModeCreate2Churn, config.ModeDeployThenCall,
+		config.ModeApproveTransferFrom, config.ModeSwap:
 		return nil, false, nil
 	default:
+		if config.IsCustomMode(mode) {
+			return nil, false, nil
+		}
 		return result, true, fmt.Errorf("unsupported mode: %s", mode)
 	}
 }
 
-func (p *Pipeline) runStandardPipeline(ctx context.Context, result *Result) error {
+func (p *Pipeline) runStandardPipeline(ctx context.Context, result *Result, metricsServer *metrics.Metrics) error {
+	p.stages = result.Stages
+	if p.runCfg.StreamingMode {
+		result.RateLimit = p.streamLimit()
+	}
+	defer func() {
+		if p.watchdog != nil {
+			result.Halts = p.watchdog.Halts()
+		}
+		if p.budget != nil {
+			result.BudgetStop = p.budget.Stopped()
+		}
+		p.recordAlerts(result)
+	}()
+	p.enterGate()
+	defer p.passGate(false)
+
 	if err := p.runStage(ctx, result, StageInit, p.initialize); err != nil {
-		return err
+		return p.finishPartial(ctx, result, StageInit, err)
 	}
+	p.snapshotMempool(ctx)
 
-	if !p.runCfg.SkipDistribution {
-		if err := p.runStage(ctx, result, StageDistribute, p.distribute); err != nil {
-			return err
-		}
+	distribute := p.distribute
+	if p.runCfg.SkipDistribution {
+		distribute = p.verifyFunding
+	}
+	if err := p.runStage(ctx, result, StageDistribute, distribute); err != nil {
+		return p.finishPartial(ctx, result, StageDistribute, err)
 	}
 
 	if err := p.runStage(ctx, result, StageBuild, p.build); err != nil {
-		return err
+		return p.finishPartial(ctx, result, StageBuild, err)
 	}
+	p.passGate(true)
 
 	if p.runCfg.DryRun {
-		fmt.Println("\nDry run complete - transactions built but not sent")
+		console.Println("\nDry run complete - transactions built but not sent")
 		result.Finalize()
 		return nil
 	}
 
+	stopWatchdog := p.startWatchdog(ctx, metricsServer)
+	defer stopWatchdog()
+	if p.watchdog != nil {
+		p.batcher.WithGate(p.watchdog)
+		if p.streamer != nil {
+			p.streamer.WithGate(p.watchdog)
+		}
+	}
+
+	p.startNonceWatch(ctx)
+	defer p.stopNonceWatch(ctx)
+	stopAlert := p.startAlert(ctx)
+	defer stopAlert()
+	p.startFlightRecorder(ctx)
+	defer p.stopFlightRecorder(ctx)
+	stopBlockStop := p.startBlockStop(ctx)
+	defer stopBlockStop()
+	if p.blockStop != nil {
+		p.batcher.WithCutoff(p.blockStop)
+		if p.streamer != nil {
+			p.streamer.WithCutoff(p.blockStop)
+		}
+	}
 	if err := p.runStage(ctx, result, StageSend, p.send); err != nil {
-		return err
+		return p.finishPartial(ctx, result, StageSend, err)
+	}
+	stopAlert()
+	stopBlockStop()
+	p.recordAlerts(result)
+	p.recordBlockStop(result)
+	p.recordThrottling(result)
+	if p.budget != nil && p.stages.Send != nil {
+		p.stages.Send.BudgetStop = p.budget.Stopped()
 	}
+	p.saveNonceSnapshot(p.sendFailures)
 
 	if !p.runCfg.SkipCollection {
 		if err := p.runStage(ctx, result, StageCollect, p.collect); err != nil {
-			return err
+			return p.finishPartial(ctx, result, StageCollect, err)
 		}
+		result.ApplyReport(p.report)
 	}
+	p.stopNonceWatch(ctx)
+	p.stopFlightRecorder(ctx)
+	p.diffMempool(ctx)
 
-	if err := p.runStage(ctx, result, StageReport, p.report); err != nil {
+	if err := p.runStage(ctx, result, StageReport, p.generateReport); err != nil {
 		return err
 	}
 
@@ -178,15 +512,43 @@ func (p *Pipeline) runStandardPipeline(ctx context.Context, result *Result) erro
 	return nil
 }
 
+// finishPartial handles a stage failure. When the failure is due to the
+// --max-runtime deadline, it still collects and reports whatever was sent
+// before it, so a cut-short run leaves a usable partial report.
+func (p *Pipeline) finishPartial(ctx context.Context, result *Result, failed Stage, stageErr error) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return stageErr
+	}
+	console.Warnf("\nMax runtime of %s exceeded during %s; generating partial report\n", p.runCfg.MaxRuntime, failed)
+	result.Partial = true
+
+	// The expired context makes collection report the current state without waiting
+	if failed == StageSend && !p.runCfg.SkipCollection {
+		_ = p.runStage(ctx, result, StageCollect, p.collect)
+	}
+	if p.report != nil {
+		result.ApplyReport(p.report)
+	}
+	p.stopNonceWatch(ctx)
+	p.stopFlightRecorder(ctx)
+	p.recordThrottling(result)
+	_ = p.runStage(ctx, result, StageReport, p.generateReport)
+
+	result.Finalize()
+	p.printFinalSummary(result)
+	return fmt.Errorf("max runtime of %s exceeded: %w", p.runCfg.MaxRuntime, stageErr)
+}
+
 // runStage executes a pipeline stage with timing and error handling
 func (p *Pipeline) runStage(ctx context.Context, result *Result, stage Stage, fn func(context.Context) error) error {
-	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	fmt.Printf("  Stage %d: %s\n", stage+1, stage.String())
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	console.Printf("  Stage %d: %s\n", stage+1, stage.String())
+	console.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 
 	start := time.Now()
 	err := fn(ctx)
 	duration := time.Since(start)
+	p.traceStage(stage, start, duration, err)
 
 	sr := &StageResult{
 		Stage:    stage,
@@ -197,19 +559,55 @@ func (p *Pipeline) runStage(ctx context.Context, result *Result, stage Stage, fn
 	if err != nil {
 		sr.Error = err
 		sr.Message = fmt.Sprintf("Failed: %v", err)
-		fmt.Printf("\n[FAIL] Stage %s failed: %v\n", stage.String(), err)
+		console.Failf("\nStage %s failed: %v\n", stage.String(), err)
 	} else {
 		sr.Message = fmt.Sprintf("Completed in %s", duration)
-		fmt.Printf("\n[OK] Stage %s completed in %s\n", stage.String(), duration)
+		console.OKf("\nStage %s completed in %s\n", stage.String(), duration)
 	}
 
 	result.AddStageResult(sr)
 	return err
 }
 
+// startWatchdog starts the chain halt watchdog if enabled and returns its stop function
+func (p *Pipeline) startWatchdog(ctx context.Context, metricsServer *metrics.Metrics) func() {
+	if p.runCfg.HaltWindow <= 0 {
+		return func() {}
+	}
+
+	wdCfg := watchdog.DefaultConfig()
+	wdCfg.Window = p.runCfg.HaltWindow
+	p.watchdog = watchdog.New(p.client, wdCfg)
+	if metricsServer != nil {
+		p.watchdog.WithCallbacks(&watchdog.Callbacks{
+			OnHalt:   func(watchdog.Halt) { metricsServer.SetChainHalted(true) },
+			OnResume: func(watchdog.Halt) { metricsServer.SetChainHalted(false) },
+		})
+	}
+
+	wdCtx, cancel := context.WithCancel(ctx)
+	go p.watchdog.Run(wdCtx)
+	return cancel
+}
+
+// printHalts prints the chain halt windows observed during the run
+func printHalts(halts []watchdog.Halt) {
+	if len(halts) == 0 {
+		return
+	}
+	console.Warnf("\nChain Halts: %d\n", len(halts))
+	for _, h := range halts {
+		if h.End == nil {
+			console.Summaryf("  - after block #%d from %s (not resumed)\n", h.LastBlock, h.Start.Format(time.RFC3339))
+			continue
+		}
+		console.Summaryf("  - after block #%d from %s for %s\n", h.LastBlock, h.Start.Format(time.RFC3339), h.Duration)
+	}
+}
+
 // Stage 1: Initialize
 func (p *Pipeline) initialize(ctx context.Context) error {
-	fmt.Println("Initializing pipeline...")
+	console.Println("Initializing pipeline...")
 
 	// Get chain ID
 	chainID, err := p.client.ChainID(ctx)
@@ -222,23 +620,51 @@ func (p *Pipeline) initialize(ctx context.Context) error {
 		p.cfg.ChainID = chainID.Uint64()
 	}
 
+	if err := p.loadNonceSnapshot(p.cfg.ChainID); err != nil {
+		return err
+	}
+	if err := p.loadReplay(); err != nil {
+		return err
+	}
+
 	// Display configuration
-	fmt.Printf("\nConfiguration:\n")
-	fmt.Printf("  URL:            %s\n", p.cfg.URL)
-	fmt.Printf("  Chain ID:       %d\n", p.cfg.ChainID)
-	fmt.Printf("  Mode:           %s\n", p.cfg.Mode)
-	fmt.Printf("  Master Account: %s\n", p.wallet.MasterAddress().Hex())
-	fmt.Printf("  Sub Accounts:   %d\n", p.cfg.SubAccounts)
-	fmt.Printf("  Transactions:   %d\n", p.cfg.Transactions)
-	fmt.Printf("  Batch Size:     %d\n", p.cfg.BatchSize)
-	fmt.Printf("  Gas Limit:      %d\n", p.cfg.GasLimit)
+	console.Printf("\nConfiguration:\n")
+	console.Printf("  URL:            %s\n", p.cfg.URL)
+	console.Printf("  Chain ID:       %d\n", p.cfg.ChainID)
+	console.Printf("  Mode:           %s\n", p.cfg.Mode)
+	console.Printf("  Master Account: %s\n", p.master.Address().Hex())
+	if p.cfg.MasterKMS != "" {
+		console.Printf("  Master Signer:  %s\n", p.cfg.MasterKMS)
+	}
+	console.Printf("  Sub Accounts:   %d\n", p.cfg.SubAccounts)
+	console.Printf("  Transactions:   %d\n", p.cfg.Transactions)
+	console.Printf("  Batch Size:     %d\n", p.cfg.BatchSize)
+	console.Printf("  Gas Limit:      %d\n", p.cfg.GasLimit)
+
+	if err := p.preflightNode(ctx); err != nil {
+		return err
+	}
+	if err := p.detectZeroFee(ctx); err != nil {
+		return err
+	}
+	if err := p.preflightCapacity(ctx); err != nil {
+		return err
+	}
+	if err := p.checkStopBlock(ctx); err != nil {
+		return err
+	}
 
 	// Check master balance
-	masterBalance, err := p.client.BalanceAt(ctx, p.wallet.MasterAddress(), nil)
+	masterBalance, err := p.client.BalanceAt(ctx, p.master.Address(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to get master balance: %w", err)
 	}
-	fmt.Printf("\nMaster Balance: %s wei\n", masterBalance.String())
+	console.Printf("\nMaster Balance: %s\n", p.units().Format(masterBalance))
+
+	if err := p.preflightFeePayer(ctx); err != nil {
+		return err
+	}
+	p.tuneConcurrency(ctx)
 
 	// Initialize components
 	return p.initializeComponents()
@@ -248,7 +674,9 @@ func (p *Pipeline) initialize(ctx context.Context) error {
 func (p *Pipeline) initializeComponents() error {
 	// Determine gas price for distributor
 	distGasPrice := big.NewInt(1000000000) // 1 Gwei default
-	if p.cfg.GasPrice != "" {
+	if p.zeroFee {
+		distGasPrice = new(big.Int)
+	} else if p.cfg.GasPrice != "" {
 		if gasPrice, ok := new(big.Int).SetString(p.cfg.GasPrice, 10); ok && gasPrice.Sign() > 0 {
 			distGasPrice = gasPrice
 		}
@@ -263,11 +691,18 @@ func (p *Pipeline) initializeComponents() error {
 	if err != nil {
 		return fmt.Errorf("transactions per account overflow: %w", err)
 	}
+	fundAmount, fundExtra := p.runCfg.FundOverrides()
 	distCfg := &distributor.Config{
 		GasPerTx:      p.cfg.GasLimit,
 		TxsPerAccount: txsPerAccount,
 		GasPrice:      distGasPrice,
+		ValuePerTx:    p.cfg.ValueWei(),
+		FundAmount:    fundAmount,
+		FundExtra:     fundExtra,
 		BufferPercent: 20,
+		FundRetries:   p.runCfg.FundRetries,
+		PriceBump:     p.runCfg.PriceBump,
+		Units:         p.units(),
 	}
 	p.distributor = distributor.New(p.client, distCfg)
 
@@ -276,15 +711,26 @@ func (p *Pipeline) initializeComponents() error {
 	if err != nil {
 		return fmt.Errorf("batch size overflow: %w", err)
 	}
+	maxConcurrent := p.runCfg.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = 100
+	}
 	batchCfg := &batcher.Config{
 		BatchSize:     batchSize,
-		MaxConcurrent: 100, // Increased from 10 for parallel sending
-		BatchInterval: 0,   // Removed delay for maximum speed
+		MaxConcurrent: maxConcurrent,
+		BatchInterval: 0, // Removed delay for maximum speed
 		RetryCount:    3,
 		RetryDelay:    500 * time.Millisecond,
 		Timeout:       30 * time.Second,
+		SendDeadline:  p.runCfg.SendDeadline,
 	}
-	p.batcher, err = batcher.New(p.client, batchCfg)
+	if err := p.openSenders(); err != nil {
+		return err
+	}
+	if p.runCfg.ConsistencyCheck && len(p.cfg.Endpoints) == 0 {
+		return fmt.Errorf("consistency-check needs at least one endpoint in --endpoints to compare --url with")
+	}
+	p.batcher, err = batcher.New(p.sender(), batchCfg)
 	if err != nil {
 		return fmt.Errorf("failed to create batcher: %w", err)
 	}
@@ -292,72 +738,186 @@ func (p *Pipeline) initializeComponents() error {
 	// Streamer (if streaming mode)
 	if p.runCfg.StreamingMode {
 		streamCfg := &batcher.StreamerConfig{
-			Rate:    p.runCfg.StreamingRate,
-			Burst:   100,
-			Workers: 10,
-			Timeout: 5 * time.Second,
+			Rate:         p.runCfg.StreamingRate,
+			Burst:        p.streamLimit().Burst,
+			Workers:      streamWorkers,
+			Timeout:      5 * time.Second,
+			SendDeadline: p.runCfg.SendDeadline,
+		}
+		p.streamer = batcher.NewStreamer(p.sender(), streamCfg)
+		if err := p.dialStreamConnections(streamCfg.Workers); err != nil {
+			return err
 		}
-		p.streamer = batcher.NewStreamer(p.client, streamCfg)
 	}
 
 	// Collector
-	collCfg := &collector.Config{
+	p.collector = collector.New(p.client, p.collectorConfig()).WithCallbacks(p.collectorCallbacks(nil))
+
+	if err := p.initBudget(); err != nil {
+		return err
+	}
+	if p.budget != nil {
+		p.batcher.WithBudget(p.budget)
+		if p.streamer != nil {
+			p.streamer.WithBudget(p.budget)
+		}
+		p.collector.WithBudget(p.budget)
+	}
+	return nil
+}
+
+// confirmConcurrency returns the receipt polling concurrency of the run
+func (p *Pipeline) confirmConcurrency() int {
+	if p.runCfg.ConfirmConcurrency <= 0 {
+		return 20
+	}
+	return p.runCfg.ConfirmConcurrency
+}
+
+// collectorConfig returns the receipt collector settings of the run
+func (p *Pipeline) collectorConfig() *collector.Config {
+	return &collector.Config{
 		PollInterval:         500 * time.Millisecond,
 		ConfirmTimeout:       p.cfg.Timeout,
-		MaxConcurrent:        20,
+		MaxConcurrent:        p.confirmConcurrency(),
 		BatchSize:            100,
 		BlockTrackingEnabled: true,
 		BlockPollInterval:    1 * time.Second,
+		EvictionBlocks:       p.runCfg.EvictionBlocks,
+		TopTxs:               p.runCfg.TopTxs,
+		PeakWindow:           p.runCfg.PeakWindow,
+		HeatmapInterval:      p.runCfg.HeatmapInterval,
+		Units:                p.units(),
+		MemoryCap:            p.runCfg.MemoryCap,
+		SpillDir:             p.runCfg.SpillDir,
+		FeeDelegation:        p.cfg.GetMode() == config.ModeFeeDelegation,
+		L1Fees:               p.runCfg.L1Fees,
+		ActivationBlock:      p.runCfg.StopAtBlock,
+		ExpectedEvents:       p.expectedEvents(),
+		SubscribeHeads:       p.runCfg.SubscribeReceipts,
+		SummaryLevel:         collector.SummaryLevel(p.runCfg.SummaryLevel),
 	}
-	p.collector = collector.New(p.client, collCfg)
-	return nil
 }
 
 // Stage 2: Distribute funds
 func (p *Pipeline) distribute(ctx context.Context) error {
-	fmt.Println("Distributing funds to sub-accounts...")
+	console.Println("Distributing funds to sub-accounts...")
 
 	subAddrs := p.wallet.SubAddresses()
 
-	result, err := p.distributor.Distribute(ctx, p.wallet.MasterKey(), subAddrs)
+	result, err := p.distributor.Distribute(ctx, p.master, subAddrs)
 	if err != nil {
 		return fmt.Errorf("distribution failed: %w", err)
 	}
 
-	// Wait for funding to confirm if any transactions were sent
-	if result.TxCount > 0 {
-		if err = p.distributor.WaitForFunding(ctx, result.ReadyAccounts, 60*time.Second); err != nil {
+	// Wait for funding to confirm if any transactions were sent, funding
+	// accounts that are still short again
+	if result.TxCount > 0 || len(result.FailedAccounts) > 0 {
+		err = p.distributor.Confirm(ctx, p.master, result, 60*time.Second)
+		p.printFundRetries(result.Retries)
+		if err != nil {
+			p.stages.Distribute = &DistributeMetrics{Retries: result.Retries}
 			return fmt.Errorf("failed waiting for funding: %w", err)
 		}
 	}
 
-	// Get nonces for building transactions
-	p.nonces, err = p.distributor.GetAccountNonces(ctx, result.ReadyAccounts)
-	if err != nil {
-		return fmt.Errorf("failed to get nonces: %w", err)
+	// Get nonces for building transactions, unless a trusted snapshot supplies them
+	if p.nonceSnap == nil {
+		p.nonces, err = p.distributor.GetAccountNonces(ctx, result.ReadyAccounts)
+		if err != nil {
+			return fmt.Errorf("failed to get nonces: %w", err)
+		}
+		for _, account := range result.ReadyAccounts {
+			p.recordNonce(account.Address, account.Nonce)
+		}
+	}
+
+	p.stages.Distribute = &DistributeMetrics{
+		AccountsReady:    len(result.ReadyAccounts),
+		AccountsFunded:   result.TxCount,
+		AccountsUnfunded: len(result.UnfundedAccounts),
+		WeiMoved:         result.TotalDistributed.String(),
+		Retries:          result.Retries,
 	}
 
-	fmt.Printf("\nDistribution Summary:\n")
-	fmt.Printf("  Ready Accounts:    %d\n", len(result.ReadyAccounts))
-	fmt.Printf("  Unfunded Accounts: %d\n", len(result.UnfundedAccounts))
-	fmt.Printf("  Total Distributed: %s wei\n", result.TotalDistributed.String())
-	fmt.Printf("  Transactions Sent: %d\n", result.TxCount)
+	console.Printf("\nDistribution Summary:\n")
+	console.Printf("  Ready Accounts:    %d\n", len(result.ReadyAccounts))
+	console.Printf("  Unfunded Accounts: %d\n", len(result.UnfundedAccounts))
+	console.Printf("  Total Distributed: %s\n", p.units().Format(result.TotalDistributed))
+	console.Printf("  Transactions Sent: %d\n", result.TxCount)
 
 	return nil
 }
 
-// Stage 3: Build transactions
-func (p *Pipeline) build(ctx context.Context) error {
-	fmt.Println("Building transactions...")
+// printFundRetries prints the funding retries of the distribute stage
+func (p *Pipeline) printFundRetries(retries []*distributor.RetryAttempt) {
+	if len(retries) == 0 {
+		return
+	}
+	console.Printf("\nFunding Retries: %d\n", len(retries))
+	for _, r := range retries {
+		gasPrice, _ := new(big.Int).SetString(r.GasPrice, 10)
+		console.Printf("  #%d: %d accounts, %d sent, %d funded at %s gas price (+%d%% bump)\n",
+			r.Attempt, r.Accounts, r.Sent, r.Funded, p.units().Format(gasPrice), r.PriceBump)
+		if r.Underpriced > 0 {
+			console.Printf("      %d replacements refused as underpriced\n", r.Underpriced)
+		}
+		if r.Error != "" {
+			console.Printf("      %s\n", r.Error)
+		}
+	}
+}
+
+// verifyFunding replaces the distribute stage under --skip-distribution: it
+// checks the sub-account balances and aborts before building if any is short
+func (p *Pipeline) verifyFunding(ctx context.Context) error {
+	console.Println("Skipping distribution, verifying sub-account funding...")
+
+	keys := p.subKeys()
+	addrs := make([]common.Address, len(keys))
+	for i, key := range keys {
+		addrs[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	underfunded, err := p.distributor.VerifyFunding(ctx, addrs)
+	if err != nil {
+		return fmt.Errorf("funding verification failed: %w", err)
+	}
 
-	// Create builder config
+	p.stages.Distribute = &DistributeMetrics{
+		AccountsReady:    len(addrs) - len(underfunded),
+		AccountsUnfunded: len(underfunded),
+		WeiMoved:         "0",
+	}
+
+	if len(underfunded) > 0 {
+		console.Failf("%d of %d sub-accounts are underfunded:\n", len(underfunded), len(addrs))
+		for _, account := range underfunded {
+			console.Summaryf("  %s  balance %s, missing %s\n", account.Address.Hex(),
+				p.units().Format(account.Balance), p.units().Format(account.MissingFund))
+		}
+		return fmt.Errorf("%d sub-accounts are underfunded; run without --skip-distribution to fund them", len(underfunded))
+	}
+
+	console.OKf("All %d sub-accounts are funded\n", len(addrs))
+	return nil
+}
+
+// builderConfig returns the transaction builder settings of the run
+func (p *Pipeline) builderConfig() *txbuilder.BuilderConfig {
 	builderCfg := &txbuilder.BuilderConfig{
 		ChainID:  p.chainID,
 		GasLimit: p.cfg.GasLimit,
+		Fees:     p.runCfg.FeeBounds(),
+		TxType:   config.TxType(p.cfg.TxType),
 	}
 
 	// Apply gas price from config if specified
-	if p.cfg.GasPrice != "" {
+	if p.zeroFee {
+		builderCfg.GasPrice = new(big.Int)
+		builderCfg.GasTipCap = new(big.Int)
+		builderCfg.GasFeeCap = new(big.Int)
+	} else if p.cfg.GasPrice != "" {
 		gasPrice, ok := new(big.Int).SetString(p.cfg.GasPrice, 10)
 		if ok && gasPrice.Sign() > 0 {
 			builderCfg.GasPrice = gasPrice
@@ -366,19 +926,20 @@ func (p *Pipeline) build(ctx context.Context) error {
 		}
 	}
 
-	// Apply transfer value from config (default: 1 wei)
-	if p.cfg.Value != "" {
-		value, ok := new(big.Int).SetString(p.cfg.Value, 10)
-		if ok && value.Sign() >= 0 {
-			builderCfg.Value = value
-		}
-	}
-	if builderCfg.Value == nil {
-		builderCfg.Value = big.NewInt(1)
+	// Apply the transaction value from config
+	builderCfg.Value = p.cfg.ValueWei()
+	return builderCfg
+}
+
+// Stage 3: Build transactions
+func (p *Pipeline) build(ctx context.Context) error {
+	if p.replayTxs != nil {
+		return p.replay()
 	}
+	console.Println("Building transactions...")
 
 	// Create factory
-	factory := txbuilder.NewFactory(builderCfg, p.client)
+	factory := txbuilder.NewFactory(p.builderConfig(), p.client)
 
 	// Create builder based on mode
 	var err error
@@ -386,19 +947,16 @@ func (p *Pipeline) build(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create builder: %w", err)
 	}
+	if err := p.prepareFixtures(ctx); err != nil {
+		return err
+	}
 
 	// Get keys and ensure nonces are set
-	keys := p.wallet.SubKeys()
+	keys := p.subKeys()
 	if len(p.nonces) == 0 {
-		p.nonces = make([]uint64, len(keys))
-		var nonce uint64
-		for i, key := range keys {
-			addr := crypto.PubkeyToAddress(key.PublicKey)
-			nonce, err = p.client.PendingNonceAt(ctx, addr)
-			if err != nil {
-				return fmt.Errorf("failed to get nonce for %s: %w", addr.Hex(), err)
-			}
-			p.nonces[i] = nonce
+		p.nonces, err = p.fetchNonces(ctx, keys)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -407,16 +965,68 @@ func (p *Pipeline) build(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("transaction count overflow: %w", err)
 	}
-	p.signedTxs, err = p.builder.Build(ctx, keys, p.nonces, txCount)
+	if err := p.prepareApprovals(ctx, keys, txCount); err != nil {
+		return err
+	}
+	buildStart := time.Now()
+	p.signedTxs, err = p.builder.Build(ctx, txbuilder.KeySigners(keys), p.nonces, txCount)
 	if err != nil {
 		return fmt.Errorf("failed to build transactions: %w", err)
 	}
+	buildDuration := time.Since(buildStart)
+
+	p.stages.Build = &BuildMetrics{
+		Builder:  p.builder.Name(),
+		TxsBuilt: len(p.signedTxs),
+	}
+	if buildDuration.Seconds() > 0 {
+		p.stages.Build.TxsPerSecond = float64(len(p.signedTxs)) / buildDuration.Seconds()
+	}
 
-	fmt.Printf("\nBuild Summary:\n")
-	fmt.Printf("  Builder:           %s\n", p.builder.Name())
-	fmt.Printf("  Total Built:       %d\n", len(p.signedTxs))
+	console.Printf("\nBuild Summary:\n")
+	console.Printf("  Builder:           %s\n", p.builder.Name())
+	console.Printf("  Total Built:       %d\n", len(p.signedTxs))
 
-	return nil
+	p.validateTxs()
+	if err := p.saveTxs(); err != nil {
+		return err
+	}
+	if err := p.checkPlanBudget(); err != nil {
+		return err
+	}
+	return p.buildPoison()
+}
+
+// masterSigner returns the signer of the master account
+func (p *Pipeline) masterSigner() txbuilder.Signer {
+	return p.master
+}
+
+// subKeys returns the sub-account keys used for sending
+func (p *Pipeline) subKeys() []*ecdsa.PrivateKey {
+	keys := p.wallet.SubKeys()
+	if p.accountLimit > 0 && p.accountLimit < len(keys) {
+		return keys[:p.accountLimit]
+	}
+	return keys
+}
+
+// recipientOptions returns the builder options for --recipient and --recipient-strategy
+func (p *Pipeline) recipientOptions() []txbuilder.BuilderOption {
+	opts := []txbuilder.BuilderOption{txbuilder.WithRecipientStrategy(p.cfg.GetRecipientStrategy())}
+	if p.cfg.Recipient != "" {
+		opts = append(opts, txbuilder.WithRecipient(common.HexToAddress(p.cfg.Recipient)))
+	}
+	return opts
+}
+
+// recipients returns the recipient of each key for the long sender
+func (p *Pipeline) recipients(keys []*ecdsa.PrivateKey) []common.Address {
+	senders := make([]common.Address, len(keys))
+	for i, key := range keys {
+		senders[i] = txbuilder.AddressFromKey(key)
+	}
+	return txbuilder.Recipients(p.cfg.GetRecipientStrategy(), senders, common.HexToAddress(p.cfg.Recipient))
 }
 
 // createBuilder creates a builder based on the mode
@@ -427,7 +1037,7 @@ func (p *Pipeline) createBuilder(factory *txbuilder.Factory) (txbuilder.Builder,
 	switch mode {
 	case config.ModeTransfer:
 		// Self-transfer by default
-		return factory.CreateBuilder(mode, opts...)
+		return factory.CreateBuilder(mode, p.recipientOptions()...)
 
 	case config.ModeFeeDelegation:
 		// Parse fee payer key
@@ -435,7 +1045,7 @@ func (p *Pipeline) createBuilder(factory *txbuilder.Factory) (txbuilder.Builder,
 		if err != nil {
 			return nil, err
 		}
-		opts = append(opts, txbuilder.WithFeePayerKey(feePayerKey))
+		opts = append(p.recipientOptions(), txbuilder.WithFeePayer(txbuilder.NewKeySigner(feePayerKey)))
 		return factory.CreateBuilder(mode, opts...)
 
 	case config.ModeContractDeploy:
@@ -449,7 +1059,7 @@ func (p *Pipeline) createBuilder(factory *txbuilder.Factory) (txbuilder.Builder,
 		)
 		return factory.CreateBuilder(mode, opts...)
 
-	case config.ModeERC20Transfer:
+	case config.ModeERC20Transfer, config.ModeApproveTransferFrom:
 		tokenAddr := common.HexToAddress(p.cfg.Contract)
 		opts = append(opts, txbuilder.WithTokenAddress(tokenAddr))
 		return factory.CreateBuilder(mode, opts...)
@@ -466,15 +1076,63 @@ func (p *Pipeline) createBuilder(factory *txbuilder.Factory) (txbuilder.Builder,
 		}
 		return factory.CreateBuilder(mode, opts...)
 
-	case config.ModeLongSender, config.ModeAnalyzeBlocks:
+	case config.ModeCreate2Churn:
+		opts = append(opts, txbuilder.WithChurnCount(p.cfg.ChurnCount))
+		if p.cfg.Contract != "" {
+			opts = append(opts, txbuilder.WithContractAddress(common.HexToAddress(p.cfg.Contract)))
+		}
+		return factory.CreateBuilder(mode, opts...)
+
+	case config.ModeDeployThenCall:
+		if p.cfg.Method != "" {
+			opts = append(opts, txbuilder.WithMethod(p.cfg.Method))
+		}
+		return factory.CreateBuilder(mode, opts...)
+
+	case config.ModeSwap:
+		opts = append(opts, txbuilder.WithAmount(p.cfg.SwapAmountWei()))
+		if p.cfg.Contract != "" {
+			opts = append(opts, txbuilder.WithContractAddress(common.HexToAddress(p.cfg.Contract)))
+		}
+		if len(p.cfg.SwapPath) > 0 {
+			path := make([]common.Address, len(p.cfg.SwapPath))
+			for i, token := range p.cfg.SwapPath {
+				path[i] = common.HexToAddress(token)
+			}
+			opts = append(opts, txbuilder.WithSwapPath(path))
+		}
+		return factory.CreateBuilder(mode, opts...)
+
+	case config.ModeLongSender, config.ModeAnalyzeBlocks, config.ModeTargetUtilization, config.ModeConflict:
 		return nil, fmt.Errorf("mode %s does not support transaction builders", mode)
 	default:
+		if config.IsCustomMode(mode) {
+			opts = append(opts, txbuilder.WithMethod(p.cfg.Method), txbuilder.WithAmount(p.cfg.ValueWei()))
+			if p.cfg.Contract != "" {
+				opts = append(opts, txbuilder.WithContractAddress(common.HexToAddress(p.cfg.Contract)))
+			}
+			return factory.CreateBuilder(mode, opts...)
+		}
 		return nil, fmt.Errorf("unsupported mode: %s", mode)
 	}
 }
 
-// parseFeePayerKey parses the fee payer private key
+// units returns the formatter for wei amounts in console output
+func (p *Pipeline) units() units.Formatter {
+	return units.Formatter{Denomination: units.Denomination(p.runCfg.Denomination), Decimals: p.runCfg.Decimals}
+}
+
+// parseFeePayerKey parses the fee payer private key, or derives it from the mnemonic
 func (p *Pipeline) parseFeePayerKey() (*ecdsa.PrivateKey, error) {
+	if p.cfg.FeePayerIndex > 0 {
+		key, err := p.wallet.DeriveKey(p.cfg.FeePayerIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive fee payer: %w", err)
+		}
+		console.Printf("  Fee Payer:         %s (mnemonic index %d)\n", crypto.PubkeyToAddress(key.PublicKey).Hex(), p.cfg.FeePayerIndex)
+		return key, nil
+	}
+
 	keyHex := p.cfg.FeePayerKey
 	if len(keyHex) >= 2 && keyHex[:2] == "0x" {
 		keyHex = keyHex[2:]
@@ -484,103 +1142,445 @@ func (p *Pipeline) parseFeePayerKey() (*ecdsa.PrivateKey, error) {
 
 // Stage 4: Send transactions
 func (p *Pipeline) send(ctx context.Context) error {
-	fmt.Println("Sending transactions...")
+	console.Println("Sending transactions...")
 
 	if len(p.signedTxs) == 0 {
 		return fmt.Errorf("no transactions to send")
 	}
 
-	// Track transactions in collector
-	for _, tx := range p.signedTxs {
-		p.collector.TrackTransaction(tx.Hash, tx.From, tx.Nonce, tx.GasLimit, time.Now())
+	// Track transactions in collector, either up front or as the node
+	// acknowledges them while the collector already polls for receipts
+	var onSent *batcher.Callbacks
+	if p.runCfg.CollectDuringSend {
+		onSent = &batcher.Callbacks{OnSent: func(r *batcher.TxResult) {
+			p.collector.TrackTransaction(r.Tx.Hash, r.Tx.From, r.Tx.Nonce, r.Tx.GasLimit, r.SentAt)
+		}}
+		p.collector.Start(ctx)
+	} else {
+		for _, tx := range p.signedTxs {
+			p.collector.TrackTransaction(tx.Hash, tx.From, tx.Nonce, tx.GasLimit, time.Now())
+		}
 	}
 
-	// Send using appropriate method
-	if p.runCfg.StreamingMode && p.streamer != nil {
-		_, err := p.streamer.Stream(ctx, p.signedTxs)
+	// Record acknowledged hashes on disk as they are sent
+	jw, err := p.openJournal()
+	if err != nil {
 		return err
 	}
+	if jw != nil {
+		defer closeJournal(jw)
+		onSent = journalCallbacks(jw, onSent)
+	}
+	onSent = p.alertCallbacks(onSent)
+	onSent = p.flightCallbacks(onSent)
+
+	// Poison txs go out alongside the valid load
+	var poison chan []*PoisonStats
+	if len(p.poisonTxs) > 0 {
+		poison = make(chan []*PoisonStats, 1)
+		go func(txs []*txbuilder.PoisonTx) {
+			poison <- p.sendPoison(ctx, txs)
+		}(p.poisonTxs)
+		defer func() {
+			stats := <-poison
+			printPoison(stats)
+			if p.stages.Send != nil {
+				p.stages.Send.Poison = stats
+			}
+		}()
+	}
+
+	// Pay DNS and connection setup before the first batch goes out
+	if p.runCfg.Prewarm && (p.runCfg.P2PEnode == "" || p.runCfg.P2PCompare) {
+		p.prewarm(ctx)
+	}
 
-	_, err := p.batcher.SendAll(ctx, p.signedTxs)
+	// Send over devp2p, alone or next to RPC for comparison
+	if p.runCfg.P2PEnode != "" {
+		return p.sendGossip(ctx, onSent)
+	}
+
+	metrics, err := p.sendRPC(ctx, p.signedTxs, onSent)
+	if metrics != nil {
+		p.stages.Send = metrics
+	}
 	return err
 }
 
+// sendRPC sends txs over JSON-RPC in streaming or batch mode
+func (p *Pipeline) sendRPC(ctx context.Context, txs []*txbuilder.SignedTx, onSent *batcher.Callbacks) (*SendMetrics, error) {
+	if p.runCfg.StreamingMode && p.streamer != nil {
+		streamResult, err := p.streamer.WithCallbacks(onSent).Stream(ctx, txs)
+		if streamResult == nil {
+			return nil, err
+		}
+		p.recordSendFailures(streamResult.FailedTxs)
+		return &SendMetrics{
+			Method:        "streaming",
+			TxsSent:       streamResult.SuccessCount,
+			TxsFailed:     streamResult.FailedCount,
+			TxsExpired:    streamResult.ExpiredCount,
+			RPCThroughput: streamResult.TxPerSecond,
+			BytesSent:     streamResult.TotalBytes,
+			Bandwidth:     streamResult.BytesPerSec,
+			Connections:   streamResult.Connections,
+			Redundancy:    p.redundancyStats(),
+			Balance:       p.balanceStats(),
+		}, err
+	}
+
+	if p.runCfg.TxsPerBlock > 0 {
+		return p.sendPaced(ctx, txs, onSent)
+	}
+
+	summary, err := p.batcher.WithCallbacks(onSent).SendAll(ctx, txs)
+	if summary == nil {
+		return nil, err
+	}
+	p.traceBatches(summary.BatchResults)
+	p.recordSendFailures(summary.FailedTxs)
+	return &SendMetrics{
+		Method:        "batch",
+		TxsSent:       summary.SuccessCount,
+		TxsFailed:     summary.FailedCount,
+		TxsExpired:    summary.ExpiredCount,
+		RPCThroughput: summary.TxPerSecond,
+		BytesSent:     summary.TotalBytes,
+		Bandwidth:     summary.BytesPerSec,
+		Batches:       newBatchMetrics(summary.BatchResults),
+		Redundancy:    p.redundancyStats(),
+		Balance:       p.balanceStats(),
+	}, err
+}
+
+// newBatchMetrics returns the per-batch outcomes, offset from the earliest batch start
+func newBatchMetrics(results []*batcher.BatchResult) []*BatchMetrics {
+	var first time.Time
+	for _, r := range results {
+		if r != nil && (first.IsZero() || r.StartTime.Before(first)) {
+			first = r.StartTime
+		}
+	}
+
+	batches := make([]*BatchMetrics, 0, len(results))
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		b := &BatchMetrics{
+			Index:    r.BatchIndex,
+			Start:    r.StartTime.Format(time.RFC3339Nano),
+			Offset:   r.StartTime.Sub(first).String(),
+			Duration: r.Duration.String(),
+			Txs:      r.TxCount,
+			Failed:   r.FailedCount,
+		}
+		if r.Error != nil {
+			b.Error = r.Error.Error()
+		}
+		batches = append(batches, b)
+	}
+	return batches
+}
+
+// firstFailingBatch returns the earliest started batch with failures, or nil,
+// and the number of batches with failures
+func firstFailingBatch(batches []*BatchMetrics) (first *BatchMetrics, failing int) {
+	var firstOffset time.Duration
+	for _, b := range batches {
+		if b.Failed == 0 {
+			continue
+		}
+		failing++
+		offset, _ := time.ParseDuration(b.Offset)
+		if first == nil || offset < firstOffset {
+			first, firstOffset = b, offset
+		}
+	}
+	return first, failing
+}
+
 // Stage 5: Collect results
 func (p *Pipeline) collect(ctx context.Context) error {
-	fmt.Println("Collecting transaction receipts...")
+	console.Println("Collecting transaction receipts...")
 
-	report, err := p.collector.Collect(ctx)
-	if err != nil {
-		return fmt.Errorf("collection failed: %w", err)
+	// A partial report comes back together with the deadline error
+	report, collectErr := p.collector.Collect(ctx)
+	if report == nil {
+		return fmt.Errorf("collection failed: %w", collectErr)
 	}
 
 	// Store report for later use
+	if p.watchdog != nil {
+		report.Halts = p.watchdog.Halts()
+	}
+	build := p.buildInfo
+	report.Build = &build
+	report.Region = p.runCfg.Region
+	report.Workload = p.workload()
+	p.report = report
 	p.collector.Reset()
+	p.compareTransports(report)
+	p.checkConsistency(ctx, report)
+
+	p.stages.Collect = &CollectMetrics{
+		Confirmed:         report.Metrics.TotalConfirmed,
+		Failed:            report.Metrics.TotalFailed,
+		Timeout:           report.Metrics.TotalTimeout,
+		Evicted:           report.Metrics.TotalEvicted,
+		ConfirmThroughput: report.Metrics.WallClockConfirmedTPS,
+	}
+	if p.churn != nil {
+		p.stages.Collect.ContractsCreated = p.churn.created.Load()
+		p.stages.Collect.ContractsDestroyed = p.churn.destroyed.Load()
+	}
 
 	// Export if configured
 	if p.runCfg.ExportReport && p.runCfg.OutputDir != "" {
 		exporter := collector.NewExporter(p.runCfg.OutputDir)
+		if p.runCfg.DatasetFormat != "" {
+			exporter.WithDatasetFormat(collector.ExportFormat(p.runCfg.DatasetFormat))
+		}
+		if p.runCfg.Compression != "" {
+			exporter.WithCompression(compress.Codec(p.runCfg.Compression))
+		}
 		files, err := exporter.ExportAll(report)
+		p.artifacts = append(p.artifacts, exporter.Written()...)
 		if err != nil {
-			fmt.Printf("[WARN] Failed to export report: %v\n", err)
+			console.Warnf("Failed to export report: %v\n", err)
 		} else {
-			fmt.Printf("\nReports exported to:\n")
+			console.Printf("\nReports exported to:\n")
 			for _, f := range files {
-				fmt.Printf("  - %s\n", f)
+				console.Printf("  - %s\n", f)
 			}
 		}
 	}
 
+	// Spilled records are only needed for the export
+	if err := report.Close(); err != nil {
+		console.Warnf("Failed to remove spill store: %v\n", err)
+	}
+
+	if collectErr != nil {
+		return fmt.Errorf("collection incomplete: %w", collectErr)
+	}
 	return nil
 }
 
 // Stage 6: Generate report
-func (p *Pipeline) report(_ context.Context) error {
-	fmt.Println("Generating final report...")
-	// Report is already generated in collect stage
+func (p *Pipeline) generateReport(_ context.Context) error {
+	console.Println("Generating final report...")
+	// Collection report is already exported in collect stage; add stage metrics
+	if !p.runCfg.ExportReport || p.runCfg.OutputDir == "" {
+		return nil
+	}
+
+	file, err := exportStageMetrics(p.runCfg.OutputDir, p.stages)
+	if err != nil {
+		console.Warnf("Failed to export stage metrics: %v\n", err)
+		return nil
+	}
+	p.artifacts = append(p.artifacts, file)
+	console.Printf("Stage metrics exported to: %s\n", file)
 	return nil
 }
 
-// printFinalSummary prints the final execution summary
+// writeManifest writes the run manifest listing every exported file with its
+// SHA-256 hash, so an archived run can be checked with `txhammer verify`
+func (p *Pipeline) writeManifest() {
+	if p.runCfg.OutputDir == "" || len(p.artifacts) == 0 {
+		return
+	}
+	m := manifest.New(p.buildInfo.Version, p.buildInfo.Commit)
+	m.Mode = p.cfg.Mode
+	m.ChainID = p.cfg.ChainID
+
+	path := filepath.Join(p.runCfg.OutputDir, fmt.Sprintf("manifest_%s.json", time.Now().Format("20060102_150405")))
+	if err := m.Write(path, p.artifacts); err != nil {
+		console.Warnf("Failed to write run manifest: %v\n", err)
+		return
+	}
+	console.Printf("Run manifest written to: %s\n", path)
+}
+
+// exportStageMetrics writes the per-stage metrics as JSON to the output directory
+func exportStageMetrics(outputDir string, stages *StageMetrics) (string, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(stages, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal stage metrics: %w", err)
+	}
+
+	filename := filepath.Join(outputDir, fmt.Sprintf("stages_%s.json", time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write stage metrics: %w", err)
+	}
+	return filename, nil
+}
+
+// printFinalSummary prints the final execution summary, unless
+// --summary-level is minimal, and then the one-line verdict
 func (p *Pipeline) printFinalSummary(result *Result) {
-	fmt.Println()
-	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                      Execution Summary                        ║")
-	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
-	fmt.Println()
+	if p.runCfg.JSONSummary {
+		return
+	}
+	if collector.SummaryLevel(p.runCfg.SummaryLevel) != collector.SummaryMinimal {
+		p.printExecutionSummary(result)
+	}
+	console.Summaryf("\n%s\n", result.Verdict())
+}
+
+// printExecutionSummary prints the stage results and metrics of the run
+func (p *Pipeline) printExecutionSummary(result *Result) {
+	console.Summaryln()
+	console.Summaryln("╔══════════════════════════════════════════════════════════════╗")
+	console.Summaryln("║                      Execution Summary                        ║")
+	console.Summaryln("╚══════════════════════════════════════════════════════════════╝")
+	console.Summaryln()
 
 	// Stage summary
-	fmt.Printf("Stage Results:\n")
+	console.Summaryf("Stage Results:\n")
 	for _, sr := range result.StageResults {
-		status := "[OK]"
+		status := console.Marker("[OK]")
 		if !sr.Success {
-			status = "[FAIL]"
+			status = console.Marker("[FAIL]")
 		}
-		fmt.Printf("  %s Stage %d (%s): %s\n", status, sr.Stage+1, sr.Stage.String(), sr.Duration)
+		console.Summaryf("  %s Stage %d (%s): %s\n", status, sr.Stage+1, sr.Stage.String(), sr.Duration)
 	}
 
-	fmt.Printf("\nTotal Duration: %s\n", result.Duration)
+	p.printStageMetrics(result.Stages)
+
+	printHalts(result.Halts)
+	printAlerts(result.Alerts)
+	printThrottling(result.Throttling, result.Stages.Send, result.Duration)
+	printMempool(result.Stages.Mempool)
+	p.printBudget(result.BudgetStop)
+	if result.Stages.Send != nil {
+		printBlockStop(result.BlockStop, result.Stages.Send.TxsUnsent)
+	}
+
+	if result.ChainTPS > 0 {
+		console.Summaryf("\nChain TPS: %.2f tx/s\n", result.ChainTPS)
+	}
+	if result.PeakTPS > 0 {
+		console.Summaryf("Peak TPS:  %.2f tx/s (best %s)\n", result.PeakTPS, result.PeakWindow)
+	}
+	console.Summaryf("\nTotal Duration: %s\n", result.Duration)
+	if result.Workload != nil {
+		console.Summaryf("Workload Hash:  %s\n", result.Workload.Hash)
+	}
+	if result.Partial {
+		console.Warnf("Partial run: stopped at --max-runtime of %s\n", p.runCfg.MaxRuntime)
+	}
 
 	if result.Success() {
-		fmt.Println("\nStress test completed successfully!")
+		console.Summaryln("\nStress test completed successfully!")
 	} else {
-		fmt.Println("\n[WARN] Stress test completed with errors")
+		console.Warnf("\nStress test completed with errors\n")
 		for _, err := range result.Errors {
-			fmt.Printf("  - %v\n", err)
+			console.Summaryf("  - %v\n", err)
 		}
 	}
 }
 
-// Close cleans up pipeline resources
+// printStageMetrics prints the structured metrics contributed by each stage
+func (p *Pipeline) printStageMetrics(stages *StageMetrics) {
+	if stages == nil {
+		return
+	}
+
+	console.Summaryf("\nStage Metrics:\n")
+	if d := stages.Distribute; d != nil {
+		moved := d.WeiMoved + " wei"
+		if wei, ok := new(big.Int).SetString(d.WeiMoved, 10); ok {
+			moved = p.units().Format(wei)
+		}
+		console.Summaryf("  DISTRIBUTE: %d ready, %d funded, %d unfunded, %s moved\n",
+			d.AccountsReady, d.AccountsFunded, d.AccountsUnfunded, moved)
+	}
+	if b := stages.Build; b != nil {
+		console.Summaryf("  BUILD:      %d txs built (%.2f tx/s)\n", b.TxsBuilt, b.TxsPerSecond)
+		if b.TxsInvalid > 0 {
+			console.Summaryf("              %d invalid, %d pruned\n", b.TxsInvalid, b.TxsPruned)
+		}
+	}
+	if s := stages.Send; s != nil {
+		console.Summaryf("  SEND:       %d sent, %d failed via %s (%.2f tx/s, %s/s)\n",
+			s.TxsSent, s.TxsFailed, s.Method, s.RPCThroughput, units.FormatBytes(s.Bandwidth))
+		if s.TxsExpired > 0 {
+			console.Summaryf("              %d expired before send\n", s.TxsExpired)
+		}
+		if s.Connections > 1 {
+			console.Summaryf("              %d connections (%.2f tx/s each)\n", s.Connections, s.RPCThroughput/float64(s.Connections))
+		}
+		if first, failing := firstFailingBatch(s.Batches); first != nil {
+			console.Summaryf("              %d of %d batches had failures, first at +%s (batch %d)\n",
+				failing, len(s.Batches), first.Offset, first.Index)
+		}
+		if len(s.Tranches) > 0 {
+			console.Summaryf("              %d tranches after blocks #%d to #%d, %d heads missed\n",
+				len(s.Tranches), s.Tranches[0].AfterBlock, s.Tranches[len(s.Tranches)-1].AfterBlock, missedHeads(s.Tranches))
+		}
+		printTransports(s.Transports)
+		printRedundancy(s.Redundancy)
+		printBalance(s.Balance)
+	}
+	if c := stages.Collect; c != nil {
+		console.Summaryf("  COLLECT:    %d confirmed, %d failed, %d timeout, %d evicted (%.2f tx/s)\n",
+			c.Confirmed, c.Failed, c.Timeout, c.Evicted, c.ConfirmThroughput)
+		if c.ContractsCreated > 0 || c.ContractsDestroyed > 0 {
+			console.Summaryf("              %d contracts created, %d destroyed\n", c.ContractsCreated, c.ContractsDestroyed)
+		}
+	}
+}
+
+// Close cleans up pipeline resources and zeroizes wallet keys
 func (p *Pipeline) Close() {
+	if p.collector != nil {
+		p.collector.Stop()
+	}
+	for _, conn := range p.streamConns {
+		conn.Close()
+	}
+	for _, conn := range p.endpointConns {
+		conn.Close()
+	}
 	if p.client != nil {
 		p.client.Close()
 	}
+	if p.wallet != nil {
+		p.wallet.Close()
+	}
+}
+
+// dialStreamConnections opens --connections-per-worker dedicated connections
+// for each streaming worker
+func (p *Pipeline) dialStreamConnections(workers int) error {
+	n := workers * p.runCfg.ConnectionsPerWorker
+	if n == 0 {
+		return nil
+	}
+
+	conns := make([]batcher.StreamClient, 0, n)
+	for i := 0; i < n; i++ {
+		conn, err := client.NewDedicated(p.cfg.URL)
+		if err != nil {
+			return fmt.Errorf("failed to open streaming connection: %w", err)
+		}
+		p.streamConns = append(p.streamConns, conn)
+		conns = append(conns, conn)
+	}
+	p.streamer.WithWorkerClients(conns)
+	console.Printf("Opened %d streaming connections (%d per worker)\n", n, p.runCfg.ConnectionsPerWorker)
+	return nil
 }
 
 // executeAnalyzeBlocks runs the block analyzer mode
 func (p *Pipeline) executeAnalyzeBlocks(ctx context.Context, result *Result) (*Result, error) {
-	fmt.Println("Running Block Analyzer mode...")
+	console.Println("Running Block Analyzer mode...")
 
 	// Create analyzer config
 	analyzerCfg := &analyzer.Config{
@@ -588,6 +1588,13 @@ func (p *Pipeline) executeAnalyzeBlocks(ctx context.Context, result *Result) (*R
 		EndBlock:    p.cfg.BlockEnd,
 		BlockRange:  p.cfg.BlockRange,
 		Concurrency: 50,
+		TableLimit:  p.cfg.TableLimit,
+		TablePage:   p.cfg.TablePage,
+		Anomalies: analyzer.AnomalyThresholds{
+			EmptyStreak:      p.cfg.EmptyStreak,
+			BlockTimeSpike:   p.cfg.BlockTimeSpike,
+			UtilizationCliff: p.cfg.UtilizationCliff,
+		},
 	}
 
 	// Create and run analyzer
@@ -599,147 +1606,356 @@ func (p *Pipeline) executeAnalyzeBlocks(ctx context.Context, result *Result) (*R
 		return result, fmt.Errorf("block analysis failed: %w", err)
 	}
 
-	// Print results
-	blockAnalyzer.PrintTable(analysisResult)
+	outputs := make(map[config.AnalyzeFormat]bool, len(p.cfg.AnalyzeOutput))
+	for _, name := range p.cfg.AnalyzeOutput {
+		outputs[config.AnalyzeFormat(name)] = true
+	}
 
-	// Export to CSV if output directory is configured
+	// Print results; the table already ends with the summary
+	switch {
+	case outputs[config.AnalyzeTable]:
+		blockAnalyzer.PrintTable(analysisResult)
+	case outputs[config.AnalyzeSummary]:
+		blockAnalyzer.PrintSummary(analysisResult)
+	}
+
+	// Export the requested files if output directory is configured
 	if p.runCfg.OutputDir != "" {
-		csvFile := fmt.Sprintf("%s/block_analysis_%d_%d.csv", p.runCfg.OutputDir, analysisResult.StartBlock, analysisResult.EndBlock)
-		if err := blockAnalyzer.ExportCSV(analysisResult, csvFile); err != nil {
-			fmt.Printf("[WARN] Failed to export CSV: %v\n", err)
-		} else {
-			fmt.Printf("\nAnalysis exported to: %s\n", csvFile)
+		base := filepath.Join(p.runCfg.OutputDir, fmt.Sprintf("block_analysis_%d_%d", analysisResult.StartBlock, analysisResult.EndBlock))
+		if outputs[config.AnalyzeCSV] {
+			p.exportAnalysis("CSV", base+".csv", analysisResult, blockAnalyzer.ExportCSV)
+		}
+		if outputs[config.AnalyzeJSON] {
+			p.exportAnalysis("JSON", base+".json", analysisResult, blockAnalyzer.ExportJSON)
 		}
 	}
 
 	result.Finalize()
-	fmt.Println("\nBlock analysis completed successfully!")
+	console.Summaryln("\nBlock analysis completed successfully!")
 	return result, nil
 }
 
+// exportAnalysis writes one analyzer export, reporting the file or the failure
+func (p *Pipeline) exportAnalysis(kind, filename string, result *analyzer.AnalysisResult,
+	export func(*analyzer.AnalysisResult, string) error) {
+	if err := os.MkdirAll(p.runCfg.OutputDir, 0o755); err != nil {
+		console.Warnf("Failed to export %s: %v\n", kind, err)
+		return
+	}
+	if err := export(result, filename); err != nil {
+		console.Warnf("Failed to export %s: %v\n", kind, err)
+		return
+	}
+	p.artifacts = append(p.artifacts, filename)
+	console.Summaryf("\nAnalysis exported to: %s\n", filename)
+}
+
 // executeLongSender runs the long sender mode
 func (p *Pipeline) executeLongSender(ctx context.Context, result *Result, metricsServer *metrics.Metrics) (*Result, error) {
-	fmt.Println("Running Long Sender mode...")
-
-	// Get chain ID
-	chainID, err := p.client.ChainID(ctx)
+	console.Println("Running Long Sender mode...")
+	run, err := p.setupLongSender(ctx, result, metricsServer, true, func() {
+		console.Printf("  Target TPS:         %.2f\n", p.cfg.TargetTPS)
+	})
 	if err != nil {
 		result.Finalize()
-		return result, fmt.Errorf("failed to get chain ID: %w", err)
+		return result, err
 	}
+	defer run.stop()
 
-	fmt.Printf("\nConfiguration:\n")
-	fmt.Printf("  URL:            %s\n", p.cfg.URL)
-	fmt.Printf("  Chain ID:       %d\n", chainID.Uint64())
-	fmt.Printf("  Duration:       %s\n", p.cfg.Duration)
-	fmt.Printf("  Target TPS:     %.2f\n", p.cfg.TargetTPS)
-	fmt.Printf("  Workers:        %d\n", p.cfg.Workers)
-	fmt.Printf("  Accounts:       %d\n", p.cfg.SubAccounts)
+	// Start monitor display in background
+	monCtx, monCancel := context.WithCancel(ctx)
+	go run.mon.Display(monCtx)
 
-	// Get keys and initial nonces
-	keys := p.wallet.SubKeys()
-	initialNonces := make([]uint64, len(keys))
+	console.Println("\nStarting continuous transaction sending...")
+	console.Println("Press Ctrl+C to stop")
+	sendResult, err := p.sendLongSender(ctx, run)
+	monCancel()
 
-	var nonce uint64
-	for i, key := range keys {
-		addr := crypto.PubkeyToAddress(key.PublicKey)
-		nonce, err = p.client.PendingNonceAt(ctx, addr)
-		if err != nil {
-			result.Finalize()
-			return result, fmt.Errorf("failed to get nonce for %s: %w", addr.Hex(), err)
+	console.Summaryln()
+	console.Summaryln("╔══════════════════════════════════════════════════════════════╗")
+	console.Summaryln("║                     Long Sender Results                       ║")
+	console.Summaryln("╚══════════════════════════════════════════════════════════════╝")
+	console.Summaryln()
+	p.finishLongSender(result, sendResult, func() {
+		if sendResult == nil {
+			return
 		}
-		initialNonces[i] = nonce
+		console.Summaryf("  Success Rate:        %.2f%%\n", float64(sendResult.TotalSent)/float64(sendResult.TotalSent+sendResult.TotalFailed)*100)
+		if len(sendResult.Errors) > 0 {
+			console.Summaryf("\n  Sample Errors (last %d):\n", len(sendResult.Errors))
+			for i, e := range sendResult.Errors {
+				if i >= 5 {
+					console.Summaryf("    ... and %d more\n", len(sendResult.Errors)-5)
+					break
+				}
+				console.Summaryf("    - %v\n", e)
+			}
+		}
+	})
+	return longSenderOutcome(ctx, result, err, "Long sender")
+}
+
+// executeTargetUtilization runs the long sender under a controller that
+// steers the send rate toward a target average block utilization
+func (p *Pipeline) executeTargetUtilization(ctx context.Context, result *Result, metricsServer *metrics.Metrics) (*Result, error) {
+	console.Println("Running Target Utilization mode...")
+	run, err := p.setupLongSender(ctx, result, metricsServer, false, func() {
+		console.Printf("  Target Utilization: %.2f%%\n", p.cfg.TargetUtilization)
+		console.Printf("  Initial TPS:        %.2f\n", p.cfg.TargetTPS)
+	})
+	if err != nil {
+		result.Finalize()
+		return result, err
+	}
+	defer run.stop()
+	run.sender.WithGasLimit(p.cfg.GasLimit)
+
+	ctrlCfg := utiltarget.DefaultConfig()
+	ctrlCfg.TargetUtilization = p.cfg.TargetUtilization
+	ctrlCfg.InitialTPS = p.cfg.TargetTPS
+	controller := utiltarget.New(p.client, ctrlCfg)
+
+	// The controller runs for as long as the sender does
+	ctrlCtx, ctrlCancel := context.WithCancel(ctx)
+	type ctrlOutcome struct {
+		res *utiltarget.Result
+		err error
+	}
+	ctrlDone := make(chan ctrlOutcome, 1)
+	go func() {
+		res, err := controller.Run(ctrlCtx, run.sender)
+		ctrlDone <- ctrlOutcome{res: res, err: err}
+	}()
+
+	console.Println("\nStarting utilization-targeted sending...")
+	console.Println("Press Ctrl+C to stop")
+	sendResult, err := p.sendLongSender(ctx, run)
+	ctrlCancel()
+	outcome := <-ctrlDone
+
+	console.Summaryln()
+	console.Summaryln("╔══════════════════════════════════════════════════════════════╗")
+	console.Summaryln("║                 Target Utilization Results                    ║")
+	console.Summaryln("╚══════════════════════════════════════════════════════════════╝")
+	console.Summaryln()
+	p.finishLongSender(result, sendResult, func() {
+		if outcome.res == nil {
+			return
+		}
+		console.Summaryf("  Target Utilization:  %.2f%%\n", outcome.res.TargetUtilization)
+		console.Summaryf("  Avg Utilization:     %.2f%%\n", outcome.res.AvgUtilization)
+		console.Summaryf("  Blocks Observed:     %d (%d in band)\n", outcome.res.BlocksObserved, outcome.res.BlocksInBand)
+		console.Summaryf("  Rate Adjustments:    %d\n", outcome.res.Adjustments)
+		console.Summaryf("  Final Send TPS:      %.2f\n", outcome.res.FinalTPS)
+		if outcome.res.BlocksInBand > 0 {
+			console.Summaryf("  Equilibrium TPS:     %.2f (input %.2f tx/s)\n", outcome.res.EquilibriumTPS, outcome.res.EquilibriumInput)
+		} else {
+			console.Summaryf("  Equilibrium TPS:     not reached\n")
+		}
+	})
+
+	if outcome.err != nil {
+		return result, fmt.Errorf("utilization controller failed: %w", outcome.err)
+	}
+	return longSenderOutcome(ctx, result, err, "Target utilization run")
+}
+
+// longSenderRun is a long sender set up for LONG_SENDER or TARGET_UTILIZATION
+type longSenderRun struct {
+	sender *longsender.LongSender
+	keys   []*ecdsa.PrivateKey
+	nonces []uint64
+	mon    *monitor.Monitor // Nil unless requested
+	stops  []func()         // Stop the watchers started for the run, in reverse order
+}
+
+// stop stops the watchers started for the run
+func (r *longSenderRun) stop() {
+	for i := len(r.stops) - 1; i >= 0; i-- {
+		r.stops[i]()
 	}
+}
 
-	// Create monitor
-	mon := monitor.New(monitor.DefaultConfig())
-	mon.Start()
+// setupLongSender runs the preflight checks of the long sender modes, prints
+// their configuration with the mode's own lines from printConfig, and builds
+// a sender from the sub-accounts' current nonces. The sender reports to the
+// metrics, alerts, flight recorder and, with withMonitor, a live monitor.
+// The caller stops the run once it is over.
+func (p *Pipeline) setupLongSender(
+	ctx context.Context,
+	result *Result,
+	metricsServer *metrics.Metrics,
+	withMonitor bool,
+	printConfig func(),
+) (*longSenderRun, error) {
+	result.RateLimit = p.longSenderLimit()
+	chainID, err := p.client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if p.cfg.ChainID == 0 {
+		p.cfg.ChainID = chainID.Uint64()
+	}
 
-	// Create long sender config
-	senderCfg := &longsender.Config{
-		Duration: p.cfg.Duration,
-		TPS:      p.cfg.TargetTPS,
-		Burst:    int(p.cfg.TargetTPS / 10),
-		Workers:  p.cfg.Workers,
+	console.Printf("\nConfiguration:\n")
+	console.Printf("  URL:                %s\n", p.cfg.URL)
+	console.Printf("  Chain ID:           %d\n", chainID.Uint64())
+	console.Printf("  Duration:           %s\n", p.cfg.Duration)
+	printConfig()
+	console.Printf("  Burst:              %d\n", result.RateLimit.Burst)
+	console.Printf("  Workers:            %d\n", p.cfg.Workers)
+	console.Printf("  Accounts:           %d\n", p.cfg.SubAccounts)
+
+	if err = p.loadNonceSnapshot(chainID.Uint64()); err != nil {
+		return nil, err
+	}
+	if err = p.preflightNode(ctx); err != nil {
+		return nil, err
+	}
+	if err = p.detectZeroFee(ctx); err != nil {
+		return nil, err
+	}
+	if err = p.preflightCapacity(ctx); err != nil {
+		return nil, err
 	}
-	if senderCfg.Burst < 10 {
-		senderCfg.Burst = 10
+	if err = p.checkStopBlock(ctx); err != nil {
+		return nil, err
 	}
 
-	// Create long sender with callbacks
-	sender := longsender.New(p.client, senderCfg)
+	run := &longSenderRun{keys: p.wallet.SubKeys()}
+	if run.nonces, err = p.fetchNonces(ctx, run.keys); err != nil {
+		return nil, err
+	}
+	sendClient, err := p.openLongSender()
+	if err != nil {
+		return nil, err
+	}
+	run.sender = longsender.New(sendClient, &longsender.Config{
+		Duration:        p.cfg.Duration,
+		TPS:             result.RateLimit.Rate,
+		Burst:           result.RateLimit.Burst,
+		Workers:         p.cfg.Workers,
+		QuarantineAfter: p.runCfg.QuarantineAfter,
+	}).WithValue(p.cfg.ValueWei()).WithRecipients(p.recipients(run.keys))
+	if p.zeroFee {
+		run.sender.WithGasPrice(new(big.Int))
+	}
+	if err = p.initBudget(); err != nil {
+		return nil, err
+	}
+	if p.budget != nil {
+		run.sender.WithBudget(p.budget)
+	}
 
-	// Setup callbacks for metrics and monitoring
-	callbacks := &longsender.Callbacks{
+	run.stops = append(run.stops, p.startWatchdog(ctx, metricsServer))
+	if p.watchdog != nil {
+		run.sender.WithGate(p.watchdog)
+	}
+	run.stops = append(run.stops, p.startAlert(ctx))
+	p.startFlightRecorder(ctx)
+	run.stops = append(run.stops, func() { p.stopFlightRecorder(ctx) })
+	if withMonitor {
+		run.mon = monitor.New(monitor.DefaultConfig())
+		run.mon.Start()
+	}
+
+	run.sender.WithCallbacks(&longsender.Callbacks{
 		OnSent: func(common.Hash) {
-			mon.RecordSent(1)
+			if run.mon != nil {
+				run.mon.RecordSent(1)
+			}
 			if metricsServer != nil {
 				metricsServer.RecordTxSent()
 			}
+			if p.alert != nil {
+				p.alert.Sent()
+			}
+			if p.flight != nil {
+				p.flight.Sent()
+			}
 		},
-		OnFailed: func(error) {
-			mon.RecordFailed(1)
+		OnFailed: func(err error) {
+			if run.mon != nil {
+				run.mon.RecordFailed(1)
+			}
 			if metricsServer != nil {
 				metricsServer.RecordTxFailed()
 			}
+			if p.alert != nil {
+				p.alert.Failed(err)
+			}
+			if p.flight != nil {
+				p.flight.Failed()
+			}
 		},
 		OnTPS: func(currentTPS float64) {
 			if metricsServer != nil {
 				metricsServer.SetCurrentTPS(currentTPS)
 			}
 		},
-	}
-	sender.WithCallbacks(callbacks)
-
-	// Start monitor display in background
-	monCtx, monCancel := context.WithCancel(ctx)
-	go mon.Display(monCtx)
-
-	fmt.Println("\nStarting continuous transaction sending...")
-	fmt.Println("Press Ctrl+C to stop")
-
-	// Run the long sender
-	sendResult, err := sender.Run(ctx, keys, initialNonces)
-
-	// Stop monitor display
-	monCancel()
+		OnQuarantine: warnQuarantine,
+	})
+	return run, nil
+}
 
-	// Print final results
-	fmt.Println()
-	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                     Long Sender Results                       ║")
-	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
-	fmt.Println()
+// sendLongSender runs the sender of run, until the stop block if one is set,
+// and saves the sub-accounts' nonces
+func (p *Pipeline) sendLongSender(ctx context.Context, run *longSenderRun) (*longsender.Result, error) {
+	run.stops = append(run.stops, p.startBlockStop(ctx))
+	runCtx, runCancel := p.untilBlockStop(ctx)
+	defer runCancel()
+	sendResult, err := run.sender.Run(runCtx, run.keys, run.nonces)
+	p.saveLongSenderNonces(run.keys, sendResult)
+	return sendResult, err
+}
 
+// finishLongSender prints the results of a long sender run below the mode's
+// banner, with the mode's own lines from printDetails, and records them in result
+func (p *Pipeline) finishLongSender(result *Result, sendResult *longsender.Result, printDetails func()) {
 	if sendResult != nil {
-		fmt.Printf("  Total Duration:     %s\n", sendResult.TotalDuration)
-		fmt.Printf("  Transactions Sent:  %d\n", sendResult.TotalSent)
-		fmt.Printf("  Transactions Failed: %d\n", sendResult.TotalFailed)
-		fmt.Printf("  Average TPS:        %.2f\n", sendResult.AverageTPS)
-		fmt.Printf("  Success Rate:       %.2f%%\n", float64(sendResult.TotalSent)/float64(sendResult.TotalSent+sendResult.TotalFailed)*100)
-
-		if len(sendResult.Errors) > 0 {
-			fmt.Printf("\n  Sample Errors (last %d):\n", len(sendResult.Errors))
-			for i, e := range sendResult.Errors {
-				if i >= 5 {
-					fmt.Printf("    ... and %d more\n", len(sendResult.Errors)-5)
-					break
-				}
-				fmt.Printf("    - %v\n", e)
-			}
-		}
+		console.Summaryf("  Total Duration:      %s\n", sendResult.TotalDuration)
+		console.Summaryf("  Transactions Sent:   %d\n", sendResult.TotalSent)
+		console.Summaryf("  Transactions Failed: %d\n", sendResult.TotalFailed)
+		console.Summaryf("  Average Send TPS:    %.2f\n", sendResult.AverageTPS)
+		console.Summaryf("  Bandwidth:           %s sent (%s/s)\n", units.FormatBytes(float64(sendResult.TotalBytes)), units.FormatBytes(sendResult.BytesPerSec))
+	}
+	printDetails()
+	if sendResult != nil {
+		result.Quarantined = sendResult.Quarantined
+		printQuarantined(result.Quarantined)
+		printBalance(p.balanceStats())
+		printRedundancy(p.redundancyStats())
 	}
 
+	if p.watchdog != nil {
+		result.Halts = p.watchdog.Halts()
+		printHalts(result.Halts)
+	}
+	p.recordAlerts(result)
+	printAlerts(result.Alerts)
+	p.recordThrottling(result)
+	printThrottling(result.Throttling, nil, time.Since(result.StartTime))
+	if p.budget != nil {
+		result.BudgetStop = p.budget.Stopped()
+		p.printBudget(result.BudgetStop)
+	}
+	if p.blockStop != nil {
+		result.BlockStop = p.blockStop.Stopped()
+		printBlockStop(result.BlockStop, 0)
+	}
 	result.Finalize()
+}
 
+// longSenderOutcome returns the result of a long sender run of the named
+// mode and reports how it ended
+func longSenderOutcome(ctx context.Context, result *Result, err error, name string) (*Result, error) {
 	if err != nil {
 		if ctx.Err() != nil {
-			fmt.Println("\nLong sender stopped by user")
+			console.Summaryf("\n%s stopped by user\n", name)
 			return result, ctx.Err()
 		}
 		return result, fmt.Errorf("long sender failed: %w", err)
 	}
-
-	fmt.Println("\nLong sender completed successfully!")
+	console.Summaryf("\n%s completed successfully!\n", name)
 	return result, nil
 }
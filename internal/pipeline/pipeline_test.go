@@ -1,8 +1,40 @@
 package pipeline
 
 import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/buildinfo"
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/longsender"
+	"github.com/0xmhha/txhammer/internal/manifest"
+	"github.com/0xmhha/txhammer/internal/mix"
+	"github.com/0xmhha/txhammer/internal/noncesnap"
+	"github.com/0xmhha/txhammer/internal/scenario"
+	"github.com/0xmhha/txhammer/internal/trace"
+	"github.com/0xmhha/txhammer/internal/wallet"
 )
 
 func TestStage_String(t *testing.T) {
@@ -218,6 +250,28 @@ func TestRunConfig_Customization(t *testing.T) {
 	}
 }
 
+func TestRunConfig_MaxSpend(t *testing.T) {
+	cfg := DefaultRunConfig()
+	cfg.MaxSpend = "half an ether"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for an unparseable max-spend")
+	}
+
+	cfg.MaxSpend = "0.5ether"
+	if err := cfg.Validate(); err != nil || cfg.MaxSpend != "500000000000000000" {
+		t.Errorf("Validate() = %v, MaxSpend = %q, want it in wei", err, cfg.MaxSpend)
+	}
+
+	p := &Pipeline{runCfg: &RunConfig{MaxSpend: "lots"}}
+	if err := p.initBudget(); err == nil || p.budget != nil {
+		t.Error("initBudget() should fail for an unparseable max-spend")
+	}
+
+	if !budgeted(config.ModeTransfer) || !budgeted(config.ModeLongSender) || budgeted(config.ModeConflict) {
+		t.Error("budgeted() should cover the batch and long-sender modes only")
+	}
+}
+
 func TestStageResult_Fields(t *testing.T) {
 	sr := &StageResult{
 		Stage:    StageSend,
@@ -244,9 +298,940 @@ func TestStageResult_Fields(t *testing.T) {
 	}
 }
 
+func TestResult_ApplyReport(t *testing.T) {
+	result := NewResult()
+	report := collector.NewReport("test")
+	report.Metrics.TotalSent = 100
+	report.Metrics.TotalConfirmed = 95
+	report.Metrics.TotalFailed = 3
+	report.Metrics.TotalTimeout = 2
+	report.Metrics.ChainTPS = 50
+	report.Metrics.WallClockConfirmedTPS = 47.5
+	report.Metrics.TotalGasCost = big.NewInt(12345)
+
+	result.ApplyReport(report)
+
+	if result.Report != report {
+		t.Error("Report should be set")
+	}
+	if result.TotalTransactions != 100 || result.SuccessfulTxs != 95 || result.FailedTxs != 3 || result.TimeoutTxs != 2 {
+		t.Errorf("unexpected counts: %+v", result)
+	}
+	if result.ChainTPS != 50 {
+		t.Errorf("ChainTPS = %v, want 50", result.ChainTPS)
+	}
+	if result.WallClockConfirmedTPS != 47.5 {
+		t.Errorf("WallClockConfirmedTPS = %v, want 47.5", result.WallClockConfirmedTPS)
+	}
+	if result.TotalGasCost != "12345" {
+		t.Errorf("TotalGasCost = %v, want 12345", result.TotalGasCost)
+	}
+}
+
+func TestResult_Summary(t *testing.T) {
+	result := NewResult()
+	result.AddStageResult(&StageResult{Stage: StageInit, Success: true, Duration: time.Second})
+	result.AddStageResult(&StageResult{Stage: StageSend, Success: false, Error: errors.New("send failed")})
+	result.Stages.Build = &BuildMetrics{Builder: "transfer", TxsBuilt: 10}
+	report := collector.NewReport("test")
+	report.Metrics.TotalSent = 10
+	result.ApplyReport(report)
+	result.Finalize()
+
+	data, err := json.Marshal(result.Summary(nil))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded["success"] != false {
+		t.Error("a failed stage should fail the summary")
+	}
+	stages, _ := decoded["stages"].([]any)
+	if len(stages) != 2 || stages[1].(map[string]any)["error"] != "send failed" {
+		t.Errorf("stages = %v, want INITIALIZE and the failed SEND", stages)
+	}
+	if _, ok := decoded["stage_metrics"].(map[string]any)["build"]; !ok {
+		t.Error("stage_metrics.build missing")
+	}
+	summary, _ := decoded["report"].(map[string]any)["summary"].(map[string]any)
+	if summary["total_sent"] != float64(10) {
+		t.Errorf("report.summary = %v, want total_sent 10", summary)
+	}
+
+	// The error Execute returned fails a run whose stages all succeeded
+	ok := NewResult()
+	ok.AddStageResult(&StageResult{Stage: StageInit, Success: true})
+	if s := ok.Summary(nil); !s.Success || s.Report != nil || s.StageMetrics != nil {
+		t.Errorf("Summary(nil) = %+v, want success without report or stage metrics", s)
+	}
+	if s := ok.Summary(errors.New("boom")); s.Success || s.Error != "boom" {
+		t.Errorf("Summary(err) success = %v, error = %q; want false, boom", s.Success, s.Error)
+	}
+}
+
+func TestExportStageMetrics(t *testing.T) {
+	stages := &StageMetrics{
+		Build: &BuildMetrics{Builder: "TRANSFER", TxsBuilt: 10, TxsPerSecond: 100},
+		Send:  &SendMetrics{Method: "batch", TxsSent: 10, RPCThroughput: 50},
+	}
+
+	file, err := exportStageMetrics(t.TempDir(), stages)
+	if err != nil {
+		t.Fatalf("exportStageMetrics() error = %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read exported file: %v", err)
+	}
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, ok := decoded["build"]; !ok {
+		t.Error("build section missing")
+	}
+	if _, ok := decoded["distribute"]; ok {
+		t.Error("distribute section should be omitted when the stage did not run")
+	}
+}
+
+func TestSaveLongSenderNonces(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	path := filepath.Join(t.TempDir(), "nonces.json")
+	p := &Pipeline{cfg: &config.Config{ChainID: 1337}, runCfg: &RunConfig{NonceSnapshot: path}}
+
+	p.saveLongSenderNonces([]*ecdsa.PrivateKey{key}, nil)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("snapshot written without a send result: %v", err)
+	}
+
+	p.saveLongSenderNonces([]*ecdsa.PrivateKey{key}, &longsender.Result{NextNonces: []uint64{42}})
+	snap, err := noncesnap.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := snap.Nonces[crypto.PubkeyToAddress(key.PublicKey)]; got != 42 {
+		t.Errorf("snapshot nonce = %d, want 42", got)
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	dir := t.TempDir()
+	file, err := exportStageMetrics(dir, &StageMetrics{Build: &BuildMetrics{TxsBuilt: 1}})
+	if err != nil {
+		t.Fatalf("exportStageMetrics() error = %v", err)
+	}
+
+	p := &Pipeline{
+		cfg:       &config.Config{Mode: "TRANSFER", ChainID: 1337},
+		runCfg:    &RunConfig{OutputDir: dir},
+		buildInfo: buildinfo.Info{Version: "v1.0.0", Commit: "abc1234"},
+		artifacts: []string{file},
+	}
+	p.writeManifest()
+
+	manifests, _ := filepath.Glob(filepath.Join(dir, "manifest_*.json"))
+	if len(manifests) != 1 {
+		t.Fatalf("manifests = %v, want 1", manifests)
+	}
+	m, problems, err := manifest.Verify(manifests[0])
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("Verify() = %v, %v", problems, err)
+	}
+	if m.Version != "v1.0.0" || m.Mode != "TRANSFER" || m.ChainID != 1337 || len(m.Artifacts) != 1 {
+		t.Errorf("manifest = %+v", m)
+	}
+}
+
 // Test error type for testing
 type testError struct{}
 
 func (e testError) Error() string { return "test error" }
 
 var errTestError = testError{}
+
+func TestPoisonCount(t *testing.T) {
+	tests := []struct {
+		valid int
+		rate  float64
+		want  int
+	}{
+		{1000, 0, 0},
+		{1000, 5, 50},
+		{10, 5, 1},
+		{0, 5, 0},
+	}
+	for _, tt := range tests {
+		if got := poisonCount(tt.valid, tt.rate); got != tt.want {
+			t.Errorf("poisonCount(%d, %v) = %d, want %d", tt.valid, tt.rate, got, tt.want)
+		}
+	}
+
+	cfg := DefaultRunConfig()
+	cfg.PoisonRate = 150
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for poison rate above 100")
+	}
+}
+
+func TestRunConfig_Region(t *testing.T) {
+	cfg := DefaultRunConfig()
+	cfg.Region = "eu-west-1.worker_2"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	for _, region := range []string{"eu west", "us/east", "ap=1"} {
+		cfg.Region = region
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() expected error for region %q", region)
+		}
+	}
+}
+
+func TestRunConfig_FeeBounds(t *testing.T) {
+	cfg := DefaultRunConfig()
+	cfg.MinTip = "1gwei"
+	cfg.MaxFeeCap = "0.000001 ether"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	bounds := cfg.FeeBounds()
+	if bounds.MinTipCap.Cmp(big.NewInt(1000000000)) != 0 {
+		t.Errorf("MinTipCap = %s, want 1000000000", bounds.MinTipCap)
+	}
+	if bounds.MaxFeeCap.Cmp(big.NewInt(1000000000000)) != 0 {
+		t.Errorf("MaxFeeCap = %s, want 1000000000000", bounds.MaxFeeCap)
+	}
+	if bounds.MaxTipCap != nil || bounds.MinFeeCap != nil {
+		t.Errorf("unset bounds should be nil, got %v and %v", bounds.MaxTipCap, bounds.MinFeeCap)
+	}
+
+	cfg = DefaultRunConfig()
+	cfg.MinTip = "2gwei"
+	cfg.MaxTip = "1gwei"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error when min-tip exceeds max-tip")
+	}
+
+	cfg = DefaultRunConfig()
+	cfg.MinFeeCap = "lots"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for an invalid fee cap bound")
+	}
+
+	cfg = DefaultRunConfig()
+	cfg.ZeroFee = true
+	cfg.MaxFeeCap = "1gwei"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v for zero-fee with a fee ceiling", err)
+	}
+	cfg.MinTip = "1"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for zero-fee with a tip floor")
+	}
+}
+
+func TestExpectedEvents(t *testing.T) {
+	p := &Pipeline{cfg: &config.Config{Mode: "ERC20_TRANSFER"}, runCfg: DefaultRunConfig()}
+	events := p.expectedEvents()
+	if len(events) != 1 || events[0].Signature != "Transfer(address,address,uint256)" {
+		t.Errorf("ERC20_TRANSFER events = %+v", events)
+	}
+
+	p.cfg.Mode = "CONTRACT_CALL"
+	if events := p.expectedEvents(); events != nil {
+		t.Errorf("CONTRACT_CALL events = %+v, want none by default", events)
+	}
+	p.runCfg.ExpectEvents = []string{"TransferSingle(address,address,address,uint256,uint256)"}
+	if events := p.expectedEvents(); len(events) != 1 {
+		t.Errorf("--expect-event events = %+v", events)
+	}
+
+	p.cfg.Mode = "SWAP"
+	p.runCfg.ExpectEvents = []string{"none"}
+	if err := p.runCfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if events := p.expectedEvents(); events != nil {
+		t.Errorf("--expect-event none events = %+v", events)
+	}
+
+	for _, bad := range [][]string{{"Transfer"}, {"(uint256)"}, {"none", "Transfer(address,address,uint256)"}} {
+		p.runCfg.ExpectEvents = bad
+		if err := p.runCfg.Validate(); err == nil {
+			t.Errorf("Validate() expected error for --expect-event %q", bad)
+		}
+	}
+}
+
+func TestRunConfig_FundOverrides(t *testing.T) {
+	cfg := DefaultRunConfig()
+	if amount, extra := cfg.FundOverrides(); amount != nil || extra != nil {
+		t.Errorf("FundOverrides() = %v, %v; want nil by default", amount, extra)
+	}
+
+	cfg.FundAmount = "0.5ether"
+	cfg.FundExtra = "100"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	amount, extra := cfg.FundOverrides()
+	if amount.String() != "500000000000000000" || extra.Int64() != 100 {
+		t.Errorf("FundOverrides() = %s, %s", amount, extra)
+	}
+
+	cfg.FundExtra = "-1"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for a negative fund-extra")
+	}
+}
+
+func TestCompareTransports(t *testing.T) {
+	rpcAccount := common.HexToAddress("0x01")
+	gossipAccount := common.HexToAddress("0x02")
+
+	p := &Pipeline{
+		gossipAccounts: map[common.Address]bool{gossipAccount: true},
+		stages: &StageMetrics{Send: combineTransports(
+			&TransportMetrics{Method: "batch", Accounts: 1, TxsSent: 3, Throughput: 30},
+			&TransportMetrics{Method: "devp2p", Accounts: 1, TxsSent: 2, TxsFailed: 1, Throughput: 20},
+		)},
+	}
+	if s := p.stages.Send; s.TxsSent != 5 || s.TxsFailed != 1 || s.RPCThroughput != 50 {
+		t.Errorf("combined send = %d sent, %d failed, %.0f tx/s; want 5, 1, 50", s.TxsSent, s.TxsFailed, s.RPCThroughput)
+	}
+
+	report := collector.NewReport("test")
+	report.Transactions = []*collector.TxInfo{
+		{From: rpcAccount, Status: collector.TxConfirmSuccess, Latency: 2 * time.Second},
+		{From: rpcAccount, Status: collector.TxConfirmSuccess, Latency: 4 * time.Second},
+		{From: rpcAccount, Status: collector.TxConfirmTimeout},
+		{From: gossipAccount, Status: collector.TxConfirmSuccess, Latency: time.Second},
+		{From: gossipAccount, Status: collector.TxConfirmFailed},
+	}
+	p.compareTransports(report)
+
+	rpcPath, gossipPath := p.stages.Send.Transports[0], p.stages.Send.Transports[1]
+	if rpcPath.Confirmed != 2 || rpcPath.AvgLatency != "3s" {
+		t.Errorf("batch path = %d confirmed, avg %s; want 2, 3s", rpcPath.Confirmed, rpcPath.AvgLatency)
+	}
+	if gossipPath.Confirmed != 1 || gossipPath.AvgLatency != "1s" {
+		t.Errorf("devp2p path = %d confirmed, avg %s; want 1, 1s", gossipPath.Confirmed, gossipPath.AvgLatency)
+	}
+}
+
+func TestRunConfig_P2P(t *testing.T) {
+	cfg := DefaultRunConfig()
+	cfg.P2PCompare = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for p2p-compare without p2p-enode")
+	}
+
+	cfg.P2PEnode = "enode://abc@127.0.0.1:30303"
+	cfg.MaxSpend = "1 ether"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for p2p-enode with max-spend")
+	}
+
+	cfg.MaxSpend = ""
+	cfg.StopAtBlock = 100
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for p2p-enode with stop-at-block")
+	}
+}
+
+func TestRunConfig_ReplayTxs(t *testing.T) {
+	cfg := DefaultRunConfig()
+	cfg.SaveTxs = "txs.bin"
+	cfg.ReplayTxs = "txs.bin"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for save-txs with replay-txs")
+	}
+
+	cfg.SaveTxs = ""
+	cfg.Workloads = []string{"TRANSFER:10"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for replay-txs with workload")
+	}
+
+	cfg.Workloads = nil
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestNewBatchMetrics(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := []*batcher.BatchResult{
+		{BatchIndex: 0, TxCount: 10, StartTime: start, Duration: time.Second},
+		{BatchIndex: 1, TxCount: 10, FailedCount: 10, StartTime: start.Add(3 * time.Second), Error: errors.New("txpool is full")},
+		{BatchIndex: 2, TxCount: 5, FailedCount: 2, StartTime: start.Add(2 * time.Second)},
+	}
+
+	batches := newBatchMetrics(results)
+	if len(batches) != 3 {
+		t.Fatalf("got %d batches, want 3", len(batches))
+	}
+	if b := batches[1]; b.Offset != "3s" || b.Failed != 10 || b.Error != "txpool is full" || b.Start != "2024-01-01T00:00:03Z" {
+		t.Errorf("batch 1 = %+v", b)
+	}
+	if b := batches[0]; b.Duration != "1s" || b.Failed != 0 || b.Error != "" {
+		t.Errorf("batch 0 = %+v", b)
+	}
+
+	first, failing := firstFailingBatch(batches)
+	if failing != 2 || first == nil || first.Index != 2 {
+		t.Errorf("firstFailingBatch() = %+v, %d; want batch 2 of 2 failing", first, failing)
+	}
+	if first, failing := firstFailingBatch(batches[:1]); first != nil || failing != 0 {
+		t.Errorf("firstFailingBatch() without failures = %+v, %d", first, failing)
+	}
+}
+
+func TestTraceBatches(t *testing.T) {
+	dir := t.TempDir()
+	p := &Pipeline{runCfg: &RunConfig{TraceFile: filepath.Join(dir, "trace.json")}}
+	p.startTrace()
+
+	start := time.Now()
+	p.traceBatches([]*batcher.BatchResult{
+		{BatchIndex: 0, TxCount: 10, SuccessCount: 10, StartTime: start, Duration: time.Second},
+		{BatchIndex: 1, TxCount: 10, FailedCount: 10, StartTime: start.Add(500 * time.Millisecond), Duration: time.Second, Error: errors.New("txpool is full")},
+		{BatchIndex: 2, TxCount: 5, SuccessCount: 5, StartTime: start.Add(1200 * time.Millisecond), Duration: time.Second},
+		nil,
+	})
+	p.writeTrace()
+
+	data, err := os.ReadFile(p.runCfg.TraceFile)
+	if err != nil {
+		t.Fatalf("trace not written: %v", err)
+	}
+	var file trace.File
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("invalid trace: %v", err)
+	}
+
+	lanes := map[string]int{}
+	for _, e := range file.TraceEvents {
+		if e.Cat == "batch" {
+			lanes[e.Name] = e.Tid
+		}
+	}
+	if len(lanes) != 3 {
+		t.Fatalf("batch spans = %v, want 3", lanes)
+	}
+	if lanes["batch 0"] == lanes["batch 1"] || lanes["batch 0"] != lanes["batch 2"] {
+		t.Errorf("batch lanes = %v, want overlapping batches 0 and 1 apart and batch 2 reusing lane of batch 0", lanes)
+	}
+	if len(p.artifacts) != 1 {
+		t.Errorf("artifacts = %v, want the trace file", p.artifacts)
+	}
+}
+
+func TestNewTrancheMetrics(t *testing.T) {
+	released := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tranches := newTrancheMetrics([]*batcher.Tranche{
+		{Index: 0, AfterBlock: 10, ReleasedAt: released, Txs: 5, Duration: time.Second},
+		{Index: 1, AfterBlock: 13, MissedHeads: 2, ReleasedAt: released.Add(3 * time.Second), Txs: 5, Failed: 1},
+	})
+	if t1 := tranches[1]; t1.AfterBlock != 13 || t1.Released != "2024-01-01T00:00:03Z" || t1.Failed != 1 {
+		t.Errorf("tranche 1 = %+v", t1)
+	}
+	if tranches[0].Duration != "1s" {
+		t.Errorf("tranche 0 duration = %s, want 1s", tranches[0].Duration)
+	}
+	if missed := missedHeads(tranches); missed != 2 {
+		t.Errorf("missedHeads() = %d, want 2", missed)
+	}
+}
+
+func TestOpenRedundant(t *testing.T) {
+	p := &Pipeline{
+		cfg:    &config.Config{URL: "https://gw.example.com/v3/secret", Endpoints: []string{"http://localhost:8546"}},
+		runCfg: &RunConfig{Redundancy: 3},
+	}
+	if err := p.openRedundant(); err == nil {
+		t.Error("openRedundant() should fail with fewer endpoints than copies")
+	}
+
+	p.runCfg.Redundancy = 1
+	if err := p.openRedundant(); err != nil || p.redundant != nil || p.redundancyStats() != nil {
+		t.Errorf("openRedundant() without redundancy = %v, %v", p.redundant, err)
+	}
+
+	if got := endpointName(p.cfg.URL); got != "https://gw.example.com" {
+		t.Errorf("endpointName() = %q, want the API key stripped", got)
+	}
+}
+
+func TestOpenBalanced_MultiURL(t *testing.T) {
+	p := &Pipeline{
+		cfg:    &config.Config{URL: "http://localhost:8545", Endpoints: []string{"http://localhost:8546"}, MultiURL: true},
+		runCfg: &RunConfig{},
+	}
+	if got := p.balancePolicy(); got != "round-robin" {
+		t.Errorf("balancePolicy() = %q, want round-robin for a comma-separated --url", got)
+	}
+	p.runCfg.Balance = "least-latency"
+	if got := p.balancePolicy(); got != "least-latency" {
+		t.Errorf("balancePolicy() = %q, want the --balance policy", got)
+	}
+	p.runCfg = &RunConfig{Redundancy: 2}
+	if got := p.balancePolicy(); got != "" {
+		t.Errorf("balancePolicy() = %q, want none with --redundancy", got)
+	}
+}
+
+// stubSendClient hands each raw tx to send
+type stubSendClient struct {
+	send func(raw []byte)
+}
+
+func (s *stubSendClient) BatchSendRawTransactions(_ context.Context, rawTxs [][]byte) ([]common.Hash, error) {
+	hashes := make([]common.Hash, len(rawTxs))
+	for i, raw := range rawTxs {
+		hashes[i], _ = s.SendRawTransaction(context.Background(), raw)
+	}
+	return hashes, nil
+}
+
+func (s *stubSendClient) SendRawTransaction(_ context.Context, raw []byte) (common.Hash, error) {
+	s.send(raw)
+	return crypto.Keccak256Hash(raw), nil
+}
+
+func (s *stubSendClient) BatchCall([]rpc.BatchElem) error { return nil }
+
+func TestLongSendClient_SendsThroughSender(t *testing.T) {
+	var sent [][]byte
+	stub := &stubSendClient{send: func(raw []byte) { sent = append(sent, raw) }}
+	c := &longSendClient{sender: stub}
+
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1)})
+	if err := c.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("SendTransaction() error = %v", err)
+	}
+	want, _ := tx.MarshalBinary()
+	if len(sent) != 1 || !bytes.Equal(sent[0], want) {
+		t.Errorf("sent = %x, want the encoded tx", sent)
+	}
+}
+
+func TestOpenBalanced(t *testing.T) {
+	p := &Pipeline{
+		cfg:    &config.Config{URL: "https://gw.example.com/v3/secret", Endpoints: []string{"http://localhost:8546"}},
+		runCfg: &RunConfig{},
+	}
+	if err := p.openBalanced(); err != nil || p.balanced != nil || p.balanceStats() != nil {
+		t.Errorf("openBalanced() without balance = %v, %v", p.balanced, err)
+	}
+
+	p.runCfg.Balance = "round-robin"
+	if err := p.openBalanced(); err != nil {
+		t.Fatalf("openBalanced() error = %v", err)
+	}
+	defer p.endpointConns[0].Close()
+	stats := p.balanceStats()
+	if stats == nil || len(stats.Endpoints) != 2 || stats.Endpoints[0].Name != "https://gw.example.com" {
+		t.Errorf("balanceStats() = %+v, want both endpoints", stats)
+	}
+	if p.sender() != p.balanced {
+		t.Error("sender() should be the balanced sender")
+	}
+
+	runCfg := DefaultRunConfig()
+	runCfg.Balance, runCfg.Redundancy = "round-robin", 2
+	if err := runCfg.Validate(); err == nil || !strings.Contains(err.Error(), "balance") {
+		t.Errorf("Validate() = %v, want balance rejected with redundancy", err)
+	}
+}
+
+func TestPipeline_Workload(t *testing.T) {
+	newPipeline := func(url, key string) *Pipeline {
+		return &Pipeline{
+			cfg: &config.Config{
+				URL:          url,
+				PrivateKey:   key,
+				Mode:         "transfer",
+				Transactions: 1000,
+				SubAccounts:  10,
+				BatchSize:    100,
+				Value:        "1",
+				ChurnCount:   10,
+			},
+			runCfg: DefaultRunConfig(),
+		}
+	}
+
+	p := newPipeline("http://localhost:8545", "0x01")
+	w := p.workload()
+	want := map[string]string{"mode": "TRANSFER", "transactions": "1000", "sub-accounts": "10", "batch": "100", "value": "1"}
+	for name, value := range want {
+		if w.Parameters[name] != value {
+			t.Errorf("parameter %s = %q, want %q", name, w.Parameters[name], value)
+		}
+	}
+	if _, ok := w.Parameters["churn-count"]; ok {
+		t.Error("flags of other modes should not be part of the workload")
+	}
+
+	// Another endpoint and key send the same workload
+	if other := newPipeline("https://rpc.example.org", "0x02").workload(); other.Hash != w.Hash {
+		t.Errorf("hash changed with the endpoint: %s vs %s", other.Hash, w.Hash)
+	}
+
+	p.cfg.TxType = "eip1559"
+	if p.workload().Hash == w.Hash {
+		t.Error("hash should change with --tx-type")
+	}
+}
+
+func TestRunSummary(t *testing.T) {
+	result := &Result{
+		Duration:          2 * time.Minute,
+		TotalTransactions: 200,
+		SuccessfulTxs:     150,
+		ChainTPS:          42.5,
+		P95Latency:        3 * time.Second,
+		TotalGasUsed:      4_200_000,
+	}
+	s := runSummary(result)
+	if s.Duration != 2*time.Minute || s.FinalTPS != 42.5 || s.SuccessRate != 0.75 || s.P95Latency != 3*time.Second || s.GasUsed != 4_200_000 {
+		t.Errorf("runSummary() = %+v", s)
+	}
+
+	// An unfinished run without transactions reports its elapsed time and no rate
+	s = runSummary(&Result{StartTime: time.Now().Add(-time.Second)})
+	if s.Duration < time.Second || s.SuccessRate != 0 {
+		t.Errorf("runSummary() of unfinished run = %+v", s)
+	}
+}
+
+func TestResult_Verdict(t *testing.T) {
+	result := &Result{
+		StageResults:      []*StageResult{{Stage: StageSend, Success: true}},
+		TotalTransactions: 1_000_000,
+		SuccessfulTxs:     999_700,
+		ChainTPS:          842.4,
+		P95Latency:        1824 * time.Millisecond,
+		Report:            &collector.Report{Metrics: &collector.Metrics{SuccessRate: 99.97}},
+	}
+	if got, want := result.Verdict(), "PASS: 1,000,000 tx, 842 TPS confirmed, 99.97% success, p95 1.8s"; got != want {
+		t.Errorf("Verdict() = %q, want %q", got, want)
+	}
+	if got := result.verdict(errors.New("reclaim failed")); !strings.HasPrefix(got, "FAIL: ") {
+		t.Errorf("verdict() with a run error = %q, want FAIL", got)
+	}
+	result.Partial = true
+	if got := result.Verdict(); !strings.HasPrefix(got, "PARTIAL: ") {
+		t.Errorf("Verdict() of a partial run = %q, want PARTIAL", got)
+	}
+
+	// Without collection only the sends are known
+	result = &Result{Stages: &StageMetrics{Send: &SendMetrics{TxsSent: 1234}}}
+	if got, want := result.Verdict(), "PASS: 1,234 tx sent, receipts not collected"; got != want {
+		t.Errorf("Verdict() without collection = %q, want %q", got, want)
+	}
+
+	for n, want := range map[int]string{0: "0", 999: "999", 1000: "1,000", -12345: "-12,345", 123456789: "123,456,789"} {
+		if got := groupDigits(n); got != want {
+			t.Errorf("groupDigits(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	p := &Pipeline{cfg: &config.Config{TargetTPS: 50}, runCfg: &RunConfig{StreamingRate: 1000}}
+	if got := p.longSenderLimit(); got.Burst != 10 || !got.Derived {
+		t.Errorf("longSenderLimit() at 50 tx/s = %+v, want the minimum burst of 10", got)
+	}
+	p.cfg.TargetTPS = 2000
+	if got := p.longSenderLimit(); got.Burst != 200 || got.Rate != 2000 {
+		t.Errorf("longSenderLimit() at 2000 tx/s = %+v, want a burst of 200", got)
+	}
+	if got := p.streamLimit(); got.Burst != 100 || !got.Derived {
+		t.Errorf("streamLimit() = %+v, want the default burst of 100", got)
+	}
+
+	p.runCfg.Burst = 5
+	if got := p.longSenderLimit(); got.Burst != 5 || got.Derived {
+		t.Errorf("longSenderLimit() with --burst = %+v", got)
+	}
+	if got := p.streamLimit(); got.Burst != 5 || got.Rate != 1000 || got.Derived {
+		t.Errorf("streamLimit() with --burst = %+v", got)
+	}
+}
+
+func TestMixGate(t *testing.T) {
+	gate := &mixGate{}
+	gate.ready.Add(2)
+	setUp := &Pipeline{gate: gate}
+	failed := &Pipeline{gate: gate}
+
+	// A workload that fails during setup releases the gate without waiting
+	failed.enterGate()
+	failed.passGate(false)
+
+	done := make(chan struct{})
+	go func() {
+		setUp.enterGate()
+		setUp.passGate(true)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("passGate(true) blocked after every workload passed")
+	}
+
+	// Passing again is a no-op
+	setUp.passGate(false)
+}
+
+func TestMixResult_Summary(t *testing.T) {
+	ok := NewResult()
+	ok.AddStageResult(&StageResult{Stage: StageSend, Success: true})
+	result := &MixResult{Workloads: []*WorkloadResult{
+		{Name: "1-transfer", Workload: mix.Workload{Mode: "TRANSFER", TPS: 500}, Transactions: 1000, SubAccounts: 10, Result: ok},
+		{Name: "2-contract_call", Workload: mix.Workload{Mode: "CONTRACT_CALL", TPS: 50}, Err: errors.New("init failed")},
+	}}
+	if result.Success() {
+		t.Error("Success() = true with a failed workload")
+	}
+	s := result.Summary()
+	if s.Success || len(s.Workloads) != 2 {
+		t.Fatalf("Summary() = %+v", s)
+	}
+	if w := s.Workloads[0]; w.Mode != "TRANSFER" || w.TargetTPS != 500 || w.Summary == nil || !w.Summary.Success {
+		t.Errorf("Summary().Workloads[0] = %+v", w)
+	}
+	if s.Workloads[1].Summary != nil {
+		t.Error("a workload without a result should have no run summary")
+	}
+
+	if got := workloadPath("runs/journal.jsonl", "1-transfer"); got != "runs/journal-1-transfer.jsonl" {
+		t.Errorf("workloadPath() = %q", got)
+	}
+	if got := workloadPath("", "1-transfer"); got != "" {
+		t.Errorf("workloadPath(\"\") = %q", got)
+	}
+}
+
+func TestIncludedFrom(t *testing.T) {
+	report := &collector.Report{Blocks: []*collector.BlockInfo{
+		{Number: 98, OurTxCount: 40},
+		{Number: 99, OurTxCount: 30},
+		{Number: 100, OurTxCount: 20},
+		{Number: 101, OurTxCount: 5},
+	}}
+	if got := includedFrom(report, 100); got != 25 {
+		t.Errorf("includedFrom() = %d, want 25", got)
+	}
+	if got := includedFrom(nil, 100); got != 0 {
+		t.Errorf("includedFrom(nil) = %d, want 0", got)
+	}
+}
+
+func TestScenarioResult_Summary(t *testing.T) {
+	ok := NewResult()
+	ok.AddStageResult(&StageResult{Stage: StageSend, Success: true})
+	result := &ScenarioResult{Name: "ramp", Phases: []*PhaseResult{
+		{Phase: scenario.Phase{Name: "1-transfer", Mode: "TRANSFER", TPS: 200, Transactions: 1000}, Result: ok},
+		{Phase: scenario.Phase{Name: "2-erc20_transfer", Mode: "ERC20_TRANSFER", TPS: 500, Duration: time.Minute}, Err: errors.New("init failed")},
+		{Phase: scenario.Phase{Name: "spike", Mode: "TRANSFER", TPS: 2000, Transactions: 10}, Skipped: true},
+	}}
+	if result.Success() {
+		t.Error("Success() = true with a failed phase")
+	}
+	s := result.Summary()
+	if s.Name != "ramp" || s.Success || len(s.Phases) != 3 {
+		t.Fatalf("Summary() = %+v", s)
+	}
+	if p := s.Phases[0]; p.Mode != "TRANSFER" || p.TargetTPS != 200 || p.Transactions != 1000 || p.Summary == nil || !p.Summary.Success {
+		t.Errorf("Summary().Phases[0] = %+v", p)
+	}
+	if p := s.Phases[1]; p.Transactions != 30000 || p.Error != "init failed" || p.Summary != nil {
+		t.Errorf("Summary().Phases[1] = %+v", p)
+	}
+	if !s.Phases[2].Skipped {
+		t.Error("Summary().Phases[2] should be skipped")
+	}
+}
+
+func TestPhaseConfig(t *testing.T) {
+	cfg := &config.Config{
+		URL:          "http://localhost:8545",
+		PrivateKey:   "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Mode:         "SCENARIO",
+		Scenario:     "ramp.yaml",
+		SubAccounts:  10,
+		Transactions: 100,
+		BatchSize:    50,
+		GasPrice:     "1000000000",
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	phase := scenario.Phase{Name: "tokens", Mode: "ERC20_TRANSFER", TPS: 50, Duration: 10 * time.Second,
+		Contract: "0x1234567890123456789012345678901234567890"}
+	c, err := phaseConfig(cfg, phase)
+	if err != nil {
+		t.Fatalf("phaseConfig() error: %v", err)
+	}
+	if c.Mode != "ERC20_TRANSFER" || c.Transactions != 500 || c.Contract != phase.Contract || c.Scenario != "" {
+		t.Errorf("phaseConfig() = mode %s, %d txs, contract %s, scenario %q", c.Mode, c.Transactions, c.Contract, c.Scenario)
+	}
+	if c.GasLimit != 65000 || c.GasPrice != "1000000000" {
+		t.Errorf("phaseConfig() gas limit %d, gas price %s; want the ERC20 default and the shared price", c.GasLimit, c.GasPrice)
+	}
+	if cfg.Mode != "SCENARIO" || cfg.GasLimit != 0 {
+		t.Error("phaseConfig() changed the scenario config")
+	}
+
+	// A phase is validated with its own mode
+	if _, err := phaseConfig(cfg, scenario.Phase{Name: "calls", Mode: "CONTRACT_CALL", TPS: 1, Transactions: 1}); err == nil {
+		t.Error("phaseConfig() accepted a CONTRACT_CALL phase without a contract")
+	}
+}
+
+func TestRunPhase_SharedMetrics(t *testing.T) {
+	// The metrics register with the default registry; give the test its own
+	registerer := prometheus.DefaultRegisterer
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	t.Cleanup(func() { prometheus.DefaultRegisterer = registerer })
+
+	// A node that is down fails each phase at initialization, after its metrics are set up
+	node := httptest.NewServer(nil)
+	node.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	cfg := &config.Config{
+		URL:            node.URL,
+		PrivateKey:     "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		Mode:           "TRANSFER",
+		SubAccounts:    2,
+		Transactions:   2,
+		BatchSize:      1,
+		GasPrice:       "1000000000",
+		MetricsEnabled: true,
+		MetricsPort:    port,
+		PushGateway:    node.URL,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	runCfg := DefaultRunConfig()
+	runCfg.ExportReport = false
+
+	ctx := context.Background()
+	shared := startMetrics(ctx, cfg, runCfg, buildinfo.Info{})
+	if shared == nil {
+		t.Fatal("startMetrics() = nil")
+	}
+	defer shared.Stop(ctx)
+
+	for phase := 1; phase <= 2; phase++ {
+		if _, err := runPhase(ctx, cfg, runCfg, buildinfo.Info{}, shared); err == nil {
+			t.Errorf("phase %d: runPhase() succeeded against a node that is down", phase)
+		}
+	}
+}
+
+// refusingNode answers every eth_sendRawTransaction with refusal and every
+// receipt lookup with null, like a node facing the concurrency probe
+func refusingNode(refusal string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		type request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		answer := func(req request) map[string]any {
+			resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+			if req.Method == "eth_sendRawTransaction" {
+				resp["error"] = map[string]any{"code": -32000, "message": refusal}
+			} else {
+				resp["result"] = nil
+			}
+			return resp
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		var batch []request
+		if json.Unmarshal(body, &batch) == nil {
+			out := make([]map[string]any, len(batch))
+			for i, req := range batch {
+				out[i] = answer(req)
+			}
+			_ = json.NewEncoder(w).Encode(out)
+			return
+		}
+		var req request
+		_ = json.Unmarshal(body, &req)
+		_ = json.NewEncoder(w).Encode(answer(req))
+	}))
+}
+
+func TestConcurrencyProbeCalls(t *testing.T) {
+	tests := []struct {
+		name    string
+		refusal string
+		wantErr bool
+	}{
+		{"refused by the node", "insufficient funds for gas * price + value", false},
+		{"throttled", "rate limit exceeded", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := refusingNode(tt.refusal)
+			defer node.Close()
+			c, err := client.NewDedicated(node.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			p := &Pipeline{cfg: &config.Config{BatchSize: 10}, client: c, chainID: big.NewInt(1)}
+
+			send, err := p.sendProbe()
+			if err != nil {
+				t.Fatalf("sendProbe() error = %v", err)
+			}
+			if err := send(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("send probe error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := p.receiptProbe()(context.Background()); err != nil {
+				t.Errorf("receipt probe error = %v, want a missing receipt to count as served", err)
+			}
+		})
+	}
+}
+
+func TestHandleSpecialModes_SkipDistributionOnEphemeralAccounts(t *testing.T) {
+	// Both --ephemeral-accounts and --master-kms without --key-file generate them
+	w, err := wallet.NewEphemeral(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	p := &Pipeline{
+		cfg:    &config.Config{Mode: "TRANSFER", MasterKMS: "awskms://alias/treasury"},
+		runCfg: &RunConfig{SkipDistribution: true},
+		wallet: w,
+	}
+	if _, handled, err := p.handleSpecialModes(context.Background(), &Result{}, nil); !handled || !errors.Is(err, errEphemeralUnfunded) {
+		t.Errorf("handleSpecialModes() = %v, %v, want the run refused", handled, err)
+	}
+}
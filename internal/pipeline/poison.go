@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// PoisonStats holds how the node treated one class of deliberately invalid transactions
+type PoisonStats struct {
+	Class    txbuilder.PoisonClass `json:"class"`
+	Sent     int                   `json:"sent"`
+	Rejected int                   `json:"rejected"`
+	Accepted int                   `json:"accepted"` // Invalid txs the node took into its pool
+	Errors   map[string]int        `json:"errors,omitempty"`
+}
+
+// poisonCount returns how many poison txs accompany validCount valid txs at the given rate (percent)
+func poisonCount(validCount int, rate float64) int {
+	if rate <= 0 || validCount == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(validCount) * rate / 100))
+}
+
+// buildPoison creates the poison txs for the current build at the configured rate
+func (p *Pipeline) buildPoison() error {
+	p.poisonTxs = nil
+	count := poisonCount(len(p.signedTxs), p.runCfg.PoisonRate)
+	if count == 0 {
+		return nil
+	}
+
+	gasPrice := p.signedTxs[0].Tx.GasFeeCap()
+	if gasPrice == nil || gasPrice.Sign() == 0 {
+		gasPrice = big.NewInt(1)
+	}
+
+	var err error
+	p.poisonTxs, err = txbuilder.BuildPoison(p.chainID, gasPrice, count)
+	if err != nil {
+		return fmt.Errorf("failed to build poison transactions: %w", err)
+	}
+	console.Printf("  Poison Built:      %d (%.2f%%)\n", len(p.poisonTxs), p.runCfg.PoisonRate)
+	return nil
+}
+
+// sendPoison sends each poison tx individually so the node's per-tx verdict is
+// visible, and tallies the outcome per class
+func (p *Pipeline) sendPoison(ctx context.Context, txs []*txbuilder.PoisonTx) []*PoisonStats {
+	byClass := make(map[txbuilder.PoisonClass]*PoisonStats)
+	for _, tx := range txs {
+		if ctx.Err() != nil {
+			break
+		}
+		stats, ok := byClass[tx.Class]
+		if !ok {
+			stats = &PoisonStats{Class: tx.Class, Errors: make(map[string]int)}
+			byClass[tx.Class] = stats
+		}
+
+		stats.Sent++
+		if _, err := p.client.SendRawTransaction(ctx, tx.RawTx); err != nil {
+			stats.Rejected++
+			stats.Errors[err.Error()]++
+		} else {
+			stats.Accepted++
+		}
+	}
+
+	result := make([]*PoisonStats, 0, len(byClass))
+	for _, class := range txbuilder.PoisonClasses {
+		if stats, ok := byClass[class]; ok {
+			result = append(result, stats)
+		}
+	}
+	return result
+}
+
+// printPoison prints the node's behavior per poison class
+func printPoison(stats []*PoisonStats) {
+	console.Printf("\nPoison Injection:\n")
+	console.Printf("  %-22s %8s %9s %9s\n", "Class", "Sent", "Rejected", "Accepted")
+	for _, s := range stats {
+		console.Printf("  %-22s %8d %9d %9d\n", s.Class, s.Sent, s.Rejected, s.Accepted)
+
+		errs := make([]string, 0, len(s.Errors))
+		for msg := range s.Errors {
+			errs = append(errs, msg)
+		}
+		sort.Slice(errs, func(i, j int) bool { return s.Errors[errs[i]] > s.Errors[errs[j]] })
+		for _, msg := range errs {
+			console.Printf("    %6d  %s\n", s.Errors[msg], msg)
+		}
+	}
+	for _, s := range stats {
+		if s.Accepted > 0 {
+			console.Warnf("Node accepted %d %s transaction(s) that should have been rejected\n", s.Accepted, s.Class)
+		}
+	}
+}
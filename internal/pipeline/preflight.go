@@ -0,0 +1,261 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// blockTimeWindow is the number of recent blocks the block time is averaged over
+const blockTimeWindow = 10
+
+// headerReader reads block headers for the preflight
+type headerReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// BlockCapacity describes how much gas the chain can include per second
+type BlockCapacity struct {
+	BlockGasLimit uint64
+	BlockTime     time.Duration // Average over recent blocks (0 = unknown)
+}
+
+// MaxTPS returns the highest rate at which transactions with the given gas
+// limit fit into blocks, or 0 when the block time is unknown
+func (c BlockCapacity) MaxTPS(gasLimit uint64) float64 {
+	if c.BlockTime <= 0 || gasLimit == 0 {
+		return 0
+	}
+	perBlock := c.BlockGasLimit / gasLimit
+	return float64(perBlock) / c.BlockTime.Seconds()
+}
+
+// Check reports why the load cannot fit into the chain's blocks, or nil if
+// it can. A target TPS of 0 only checks that one transaction fits a block.
+func (c BlockCapacity) Check(gasLimit uint64, targetTPS float64) error {
+	if gasLimit > c.BlockGasLimit {
+		return fmt.Errorf("gas limit %d exceeds the block gas limit %d; no transaction can be included", gasLimit, c.BlockGasLimit)
+	}
+	if targetTPS <= 0 || c.BlockTime <= 0 {
+		return nil
+	}
+	if maxTPS := c.MaxTPS(gasLimit); targetTPS > maxTPS {
+		inFlight := targetTPS * c.BlockTime.Seconds()
+		return fmt.Errorf("target %.0f tx/s needs %.0f txs × %d gas = %.0f gas per %s block, but blocks hold %d gas (at most %.1f tx/s)",
+			targetTPS, inFlight, gasLimit, inFlight*float64(gasLimit), c.BlockTime, c.BlockGasLimit, maxTPS)
+	}
+	return nil
+}
+
+// readBlockCapacity reads the latest block gas limit and averages the block
+// time over the last blockTimeWindow blocks
+func readBlockCapacity(ctx context.Context, reader headerReader) (BlockCapacity, error) {
+	latest, err := reader.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return BlockCapacity{}, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	capacity := BlockCapacity{BlockGasLimit: latest.GasLimit}
+
+	window := min(latest.Number.Uint64(), blockTimeWindow)
+	if window == 0 {
+		return capacity, nil
+	}
+	past, err := reader.HeaderByNumber(ctx, new(big.Int).Sub(latest.Number, new(big.Int).SetUint64(window)))
+	if err != nil {
+		return BlockCapacity{}, fmt.Errorf("failed to get block for block time: %w", err)
+	}
+	if latest.Time > past.Time {
+		capacity.BlockTime = time.Duration(latest.Time-past.Time) * time.Second / time.Duration(window)
+	}
+	return capacity, nil
+}
+
+// targetTPS returns the send rate the run aims for, or 0 when the mode sends
+// as fast as it can
+func (p *Pipeline) targetTPS() float64 {
+	switch p.cfg.GetMode() {
+	case config.ModeLongSender, config.ModeTargetUtilization:
+		return p.cfg.TargetTPS
+	}
+	if p.runCfg.StreamingMode {
+		return p.runCfg.StreamingRate
+	}
+	return 0
+}
+
+// preflightCapacity warns, or fails with --strict, when the configured gas
+// limit and target rate cannot fit into the chain's blocks
+func (p *Pipeline) preflightCapacity(ctx context.Context) error {
+	capacity, err := readBlockCapacity(ctx, p.client)
+	if err != nil {
+		return err
+	}
+
+	console.Printf("  Block Gas Limit: %d\n", capacity.BlockGasLimit)
+	if capacity.BlockTime > 0 {
+		console.Printf("  Block Time:      %s (max %.1f tx/s at this gas limit)\n", capacity.BlockTime, capacity.MaxTPS(p.cfg.GasLimit))
+	}
+
+	if err := capacity.Check(p.cfg.GasLimit, p.targetTPS()); err != nil {
+		if p.runCfg.Strict {
+			return fmt.Errorf("preflight failed: %w", err)
+		}
+		console.Warnf("Preflight: %v\n", err)
+	}
+	return nil
+}
+
+// feePayerBufferPercent is the margin the fee payer balance must hold on top
+// of the planned max fee, as for sub-account funding
+const feePayerBufferPercent = 20
+
+// feePayerRequirement returns the balance the fee payer needs to pay the max
+// fee of txs transactions with the given gas limit and fee cap, plus the buffer
+func feePayerRequirement(txs, gasLimit uint64, feeCap *big.Int) *big.Int {
+	required := new(big.Int).SetUint64(txs)
+	required.Mul(required, new(big.Int).SetUint64(gasLimit))
+	required.Mul(required, feeCap)
+	required.Mul(required, big.NewInt(100+feePayerBufferPercent))
+	return required.Div(required, big.NewInt(100))
+}
+
+// preflightFeePayer fails when the fee payer of a FEE_DELEGATION run cannot
+// pay the gas of every planned transaction. The fee cap is the one the
+// builder will use, so the check runs before any funds are moved.
+func (p *Pipeline) preflightFeePayer(ctx context.Context) error {
+	if p.cfg.GetMode() != config.ModeFeeDelegation {
+		return nil
+	}
+	key, err := p.parseFeePayerKey()
+	if err != nil {
+		return fmt.Errorf("invalid fee payer key: %w", err)
+	}
+	feePayer := crypto.PubkeyToAddress(key.PublicKey)
+
+	_, feeCap, err := txbuilder.NewBaseBuilder(p.builderConfig(), p.client).GetGasSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get gas settings for the fee payer check: %w", err)
+	}
+	if feeCap == nil {
+		return nil
+	}
+	gasLimit := p.cfg.GasLimit
+	if gasLimit == 0 {
+		gasLimit = 21000
+	}
+	required := feePayerRequirement(p.cfg.Transactions, gasLimit, feeCap)
+
+	balance, err := p.client.BalanceAt(ctx, feePayer, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get fee payer balance: %w", err)
+	}
+	console.Printf("Fee Payer Balance: %s (%s needed)\n", p.units().Format(balance), p.units().Format(required))
+
+	if balance.Cmp(required) < 0 {
+		missing := new(big.Int).Sub(required, balance)
+		return fmt.Errorf("fee payer %s cannot cover the run: %d txs × %d gas × %s fee cap plus %d%% buffer needs %s, balance is %s (missing %s)",
+			feePayer.Hex(), p.cfg.Transactions, gasLimit, p.units().Format(feeCap), feePayerBufferPercent,
+			p.units().Format(required), p.units().Format(balance), p.units().Format(missing))
+	}
+	return nil
+}
+
+// nodeCaller performs the raw JSON-RPC calls of the node preflight
+type nodeCaller interface {
+	CallContext(ctx context.Context, result any, method string, args ...any) error
+}
+
+// NodeStatus is the sync state and connectivity the node reports
+type NodeStatus struct {
+	Syncing      bool
+	CurrentBlock uint64 // Sync progress while syncing
+	HighestBlock uint64
+	Peers        uint64
+	PeersKnown   bool // False when net_peerCount is unavailable
+}
+
+// syncProgress is the part of an eth_syncing result object the preflight reads
+type syncProgress struct {
+	CurrentBlock hexutil.Uint64 `json:"currentBlock"`
+	HighestBlock hexutil.Uint64 `json:"highestBlock"`
+}
+
+// readNodeStatus asks the node whether it is syncing and how many peers it has.
+// A node without the net API is reported with PeersKnown unset.
+func readNodeStatus(ctx context.Context, caller nodeCaller) (NodeStatus, error) {
+	var status NodeStatus
+
+	var syncing json.RawMessage
+	if err := caller.CallContext(ctx, &syncing, "eth_syncing"); err != nil {
+		return status, fmt.Errorf("failed to get sync status: %w", err)
+	}
+	if string(syncing) != "false" {
+		var progress syncProgress
+		if err := json.Unmarshal(syncing, &progress); err != nil {
+			return status, fmt.Errorf("failed to decode sync status: %w", err)
+		}
+		status.Syncing = true
+		status.CurrentBlock = uint64(progress.CurrentBlock)
+		status.HighestBlock = uint64(progress.HighestBlock)
+	}
+
+	var peers hexutil.Uint64
+	if err := caller.CallContext(ctx, &peers, "net_peerCount"); err == nil {
+		status.Peers = uint64(peers)
+		status.PeersKnown = true
+	}
+	return status, nil
+}
+
+// Check reports why the node is not fit to take load, or nil if it is
+func (s NodeStatus) Check(minPeers uint64, allowSyncing bool) error {
+	if s.Syncing && !allowSyncing {
+		return fmt.Errorf("node is still syncing (block %d of %d); results would be misleading, pass --allow-syncing to run anyway",
+			s.CurrentBlock, s.HighestBlock)
+	}
+	if minPeers == 0 {
+		return nil
+	}
+	if !s.PeersKnown {
+		return fmt.Errorf("node does not report its peer count (net_peerCount); use --min-peers 0 to skip the peer check")
+	}
+	if s.Peers < minPeers {
+		return fmt.Errorf("node has %d peers, --min-peers requires %d", s.Peers, minPeers)
+	}
+	return nil
+}
+
+// preflightNode fails when the node is syncing or has too few peers
+func (p *Pipeline) preflightNode(ctx context.Context) error {
+	status, err := readNodeStatus(ctx, p.client)
+	if err != nil {
+		return fmt.Errorf("preflight failed: %w", err)
+	}
+
+	switch {
+	case status.Syncing:
+		console.Printf("  Node:            syncing (block %d of %d)\n", status.CurrentBlock, status.HighestBlock)
+	case status.PeersKnown:
+		console.Printf("  Node:            synced, %d peers\n", status.Peers)
+	default:
+		console.Printf("  Node:            synced\n")
+	}
+
+	if err := status.Check(p.runCfg.MinPeers, p.runCfg.AllowSyncing); err != nil {
+		return fmt.Errorf("preflight failed: %w", err)
+	}
+	if status.Syncing {
+		console.Warnf("Preflight: node is still syncing; running anyway because of --allow-syncing\n")
+	}
+	return nil
+}
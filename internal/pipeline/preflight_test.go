@@ -0,0 +1,153 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeHeaders serves headers with a fixed gas limit and block interval
+type fakeHeaders struct {
+	latest   uint64
+	gasLimit uint64
+	interval uint64 // seconds
+}
+
+func (f *fakeHeaders) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	n := f.latest
+	if number != nil {
+		n = number.Uint64()
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(n), GasLimit: f.gasLimit, Time: 1000 + n*f.interval}, nil
+}
+
+func TestReadBlockCapacity(t *testing.T) {
+	capacity, err := readBlockCapacity(context.Background(), &fakeHeaders{latest: 100, gasLimit: 30000000, interval: 2})
+	if err != nil {
+		t.Fatalf("readBlockCapacity() error = %v", err)
+	}
+	if capacity.BlockGasLimit != 30000000 {
+		t.Errorf("BlockGasLimit = %d, want 30000000", capacity.BlockGasLimit)
+	}
+	if capacity.BlockTime != 2*time.Second {
+		t.Errorf("BlockTime = %s, want 2s", capacity.BlockTime)
+	}
+
+	// A chain still at genesis has no block time yet
+	capacity, err = readBlockCapacity(context.Background(), &fakeHeaders{latest: 0, gasLimit: 30000000, interval: 2})
+	if err != nil {
+		t.Fatalf("readBlockCapacity() error = %v", err)
+	}
+	if capacity.BlockTime != 0 {
+		t.Errorf("BlockTime = %s, want 0", capacity.BlockTime)
+	}
+}
+
+func TestBlockCapacity_Check(t *testing.T) {
+	capacity := BlockCapacity{BlockGasLimit: 30000000, BlockTime: 2 * time.Second}
+
+	if got := capacity.MaxTPS(21000); got != 714 {
+		t.Errorf("MaxTPS(21000) = %.1f, want 714", got)
+	}
+
+	tests := []struct {
+		name      string
+		gasLimit  uint64
+		targetTPS float64
+		wantErr   bool
+	}{
+		{"fits", 21000, 700, false},
+		{"too fast", 21000, 1000, true},
+		{"no target rate", 21000, 0, false},
+		{"tx larger than block", 40000000, 0, true},
+		{"large txs at modest rate", 1000000, 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := capacity.Check(tt.gasLimit, tt.targetTPS); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	unknown := BlockCapacity{BlockGasLimit: 30000000}
+	if err := unknown.Check(21000, 1e9); err != nil {
+		t.Errorf("Check() with unknown block time should only check the block gas limit, got %v", err)
+	}
+}
+
+func TestFeePayerRequirement(t *testing.T) {
+	// 1000 txs × 21000 gas × 2 gwei = 0.042 ether, plus 20%
+	got := feePayerRequirement(1000, 21000, big.NewInt(2000000000))
+	if want := big.NewInt(50400000000000000); got.Cmp(want) != 0 {
+		t.Errorf("feePayerRequirement() = %s, want %s", got, want)
+	}
+	if got := feePayerRequirement(0, 21000, big.NewInt(1)); got.Sign() != 0 {
+		t.Errorf("feePayerRequirement() with no txs = %s, want 0", got)
+	}
+}
+
+// fakeNode answers eth_syncing and net_peerCount with raw JSON results
+type fakeNode struct {
+	syncing string
+	peers   string // "" = net API unavailable
+}
+
+func (f *fakeNode) CallContext(_ context.Context, result any, method string, _ ...any) error {
+	raw := f.syncing
+	if method == "net_peerCount" {
+		if f.peers == "" {
+			return errors.New("the method net_peerCount does not exist")
+		}
+		raw = f.peers
+	}
+	return json.Unmarshal([]byte(raw), result)
+}
+
+func TestReadNodeStatus(t *testing.T) {
+	status, err := readNodeStatus(context.Background(), &fakeNode{syncing: "false", peers: `"0x5"`})
+	if err != nil {
+		t.Fatalf("readNodeStatus() error = %v", err)
+	}
+	if status.Syncing || !status.PeersKnown || status.Peers != 5 {
+		t.Errorf("status = %+v, want synced with 5 peers", status)
+	}
+
+	status, err = readNodeStatus(context.Background(), &fakeNode{syncing: `{"startingBlock":"0x0","currentBlock":"0x64","highestBlock":"0x3e8"}`})
+	if err != nil {
+		t.Fatalf("readNodeStatus() error = %v", err)
+	}
+	if !status.Syncing || status.CurrentBlock != 100 || status.HighestBlock != 1000 || status.PeersKnown {
+		t.Errorf("status = %+v, want syncing at 100 of 1000 without peer count", status)
+	}
+}
+
+func TestNodeStatus_Check(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       NodeStatus
+		minPeers     uint64
+		allowSyncing bool
+		wantErr      bool
+	}{
+		{"synced", NodeStatus{}, 0, false, false},
+		{"syncing", NodeStatus{Syncing: true}, 0, false, true},
+		{"syncing allowed", NodeStatus{Syncing: true}, 0, true, false},
+		{"enough peers", NodeStatus{Peers: 3, PeersKnown: true}, 3, false, false},
+		{"too few peers", NodeStatus{Peers: 2, PeersKnown: true}, 3, false, true},
+		{"peer count unknown", NodeStatus{}, 1, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.status.Check(tt.minPeers, tt.allowSyncing)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
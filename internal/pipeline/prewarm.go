@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// streamWorkers is the number of streaming workers
+const streamWorkers = 10
+
+// prewarm pins the RPC host's address and opens the connections the send
+// stage will use: one per concurrent batch, one per streaming worker on the
+// shared client, or one per dedicated streaming connection. Failures only
+// warn; the send stage then dials as usual.
+func (p *Pipeline) prewarm(ctx context.Context) {
+	console.Printf("\nPre-warming RPC connections\n\n")
+	start := time.Now()
+	defer func() { p.trace.Span(traceStages, "send", "prewarm", start, time.Since(start), nil) }()
+
+	if ip, err := client.PinDNS(ctx, p.cfg.URL); err != nil {
+		console.Warnf("DNS pinning skipped: %v\n", err)
+	} else if ip != "" {
+		u, _ := url.Parse(p.cfg.URL)
+		console.Printf("Pinned %s to %s\n", u.Hostname(), ip)
+	}
+
+	conns := p.runCfg.MaxConcurrent
+	if p.runCfg.StreamingMode {
+		conns = streamWorkers
+	}
+	if !client.IsHTTP(p.cfg.URL) {
+		conns = 1 // A WebSocket or IPC client holds a single connection
+	}
+
+	calls := p.runCfg.PrewarmCalls
+	if len(p.streamConns) > 0 {
+		p.prewarmDedicated(ctx, calls)
+		return
+	}
+
+	result, err := p.client.Prewarm(ctx, conns, calls)
+	if err != nil {
+		console.Warnf("Pre-warm failed: %v\n", err)
+		return
+	}
+	console.OKf("Warmed %d connections in %s (first call %s, warm call %s)\n",
+		result.Connections, result.Duration, result.FirstCall, result.WarmCall)
+}
+
+// prewarmDedicated warms each dedicated streaming connection in parallel
+func (p *Pipeline) prewarmDedicated(ctx context.Context, calls int) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		warmed int
+		errs   int
+	)
+	for _, conn := range p.streamConns {
+		wg.Add(1)
+		go func(conn *client.Client) {
+			defer wg.Done()
+			_, err := conn.Prewarm(ctx, 1, calls)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs++
+				return
+			}
+			warmed++
+		}(conn)
+	}
+	wg.Wait()
+
+	if errs > 0 {
+		console.Warnf("Pre-warm failed on %d of %d streaming connections\n", errs, len(p.streamConns))
+	}
+	console.OKf("Warmed %d dedicated streaming connections\n", warmed)
+}
@@ -0,0 +1,22 @@
+package pipeline
+
+import (
+	"github.com/0xmhha/txhammer/internal/longsender"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// warnQuarantine reports an account the long sender took out of the rotation
+func warnQuarantine(q longsender.Quarantine) {
+	console.Warnf("\nQuarantined %s after %d consecutive failures: %s\n", q.Account.Hex(), q.Failures, q.LastError)
+}
+
+// printQuarantined lists the accounts quarantined during the run
+func printQuarantined(quarantined []longsender.Quarantine) {
+	if len(quarantined) == 0 {
+		return
+	}
+	console.Warnf("\n  Quarantined Accounts: %d\n", len(quarantined))
+	for _, q := range quarantined {
+		console.Summaryf("    - %s after %d sent, %d failures in a row: %s\n", q.Account.Hex(), q.Sent, q.Failures, q.LastError)
+	}
+}
@@ -0,0 +1,32 @@
+package pipeline
+
+// streamBurst is the burst of the streaming rate limiter when --burst is unset
+const streamBurst = 100
+
+// minLongSenderBurst is the smallest burst derived for the long sender
+const minLongSenderBurst = 10
+
+// RateLimit is the effective token bucket of a streaming or long sender run
+type RateLimit struct {
+	Rate    float64 `json:"rate"` // tx/s; the starting rate in TARGET_UTILIZATION mode
+	Burst   int     `json:"burst"`
+	Derived bool    `json:"derived,omitempty"` // Burst derived from the rate rather than set with --burst
+}
+
+// streamLimit returns the rate limiter of the streaming sender
+func (p *Pipeline) streamLimit() *RateLimit {
+	if p.runCfg.Burst > 0 {
+		return &RateLimit{Rate: p.runCfg.StreamingRate, Burst: p.runCfg.Burst}
+	}
+	return &RateLimit{Rate: p.runCfg.StreamingRate, Burst: streamBurst, Derived: true}
+}
+
+// longSenderLimit returns the rate limiter of the long sender. Without
+// --burst it allows a tenth of a second of sends at once, but at least
+// minLongSenderBurst.
+func (p *Pipeline) longSenderLimit() *RateLimit {
+	if p.runCfg.Burst > 0 {
+		return &RateLimit{Rate: p.cfg.TargetTPS, Burst: p.runCfg.Burst}
+	}
+	return &RateLimit{Rate: p.cfg.TargetTPS, Burst: max(int(p.cfg.TargetTPS/10), minLongSenderBurst), Derived: true}
+}
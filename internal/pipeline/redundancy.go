@@ -0,0 +1,207 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// sendClient is the client the batcher and streamer send through
+type sendClient interface {
+	batcher.Client
+	batcher.StreamClient
+}
+
+// sender returns the redundant sender with --redundancy, the balanced client
+// with --balance, else the main client
+func (p *Pipeline) sender() sendClient {
+	if p.redundant != nil {
+		return p.redundant
+	}
+	if p.balanced != nil {
+		return p.balanced
+	}
+	return p.client
+}
+
+// longSendClient sends the transactions of the long sender through sender(),
+// so that --balance and --redundancy apply, and reads state from --url
+type longSendClient struct {
+	*client.Client
+	sender sendClient
+}
+
+// SendTransaction sends tx through the balanced or redundant sender
+func (c *longSendClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	_, err = c.sender.SendRawTransaction(ctx, raw)
+	return err
+}
+
+// openLongSender sets up --redundancy and --balance for the LONG_SENDER and
+// TARGET_UTILIZATION modes and returns the client their sends go through
+func (p *Pipeline) openLongSender() (*longSendClient, error) {
+	if err := p.openSenders(); err != nil {
+		return nil, err
+	}
+	return &longSendClient{Client: p.client, sender: p.sender()}, nil
+}
+
+// openSenders builds the clients that sender() picks from: the redundant
+// sender with --redundancy, the balanced client with --balance
+func (p *Pipeline) openSenders() error {
+	if err := p.openRedundant(); err != nil {
+		return err
+	}
+	return p.openBalanced()
+}
+
+// balancePolicy returns the --balance policy, or round-robin when --url lists
+// several nodes and neither --redundancy nor --connections-per-worker is set
+func (p *Pipeline) balancePolicy() string {
+	if p.runCfg.Balance != "" {
+		return p.runCfg.Balance
+	}
+	if p.cfg.MultiURL && p.runCfg.Redundancy <= 1 && p.runCfg.ConnectionsPerWorker == 0 {
+		return client.BalanceRoundRobin
+	}
+	return ""
+}
+
+// dialEndpoints connects to --endpoints once and returns the clients in flag
+// order. They are closed with the pipeline.
+func (p *Pipeline) dialEndpoints() ([]*client.Client, error) {
+	for _, u := range p.cfg.Endpoints[len(p.endpointConns):] {
+		cli, err := client.New(u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for endpoint %s: %w", endpointName(u), err)
+		}
+		p.endpointConns = append(p.endpointConns, cli)
+	}
+	return p.endpointConns, nil
+}
+
+// openRedundant connects to --endpoints and sets up the redundant sender
+// when --redundancy asks for more than one copy of each tx
+func (p *Pipeline) openRedundant() error {
+	if p.runCfg.Redundancy <= 1 {
+		return nil
+	}
+	if n := 1 + len(p.cfg.Endpoints); p.runCfg.Redundancy > n {
+		return fmt.Errorf("redundancy of %d needs at least %d endpoints (--url and --endpoints), got %d",
+			p.runCfg.Redundancy, p.runCfg.Redundancy, n)
+	}
+
+	conns, err := p.dialEndpoints()
+	if err != nil {
+		return err
+	}
+	endpoints := []batcher.RedundantEndpoint{{Name: endpointName(p.cfg.URL), Client: p.client}}
+	for i, u := range p.cfg.Endpoints {
+		endpoints = append(endpoints, batcher.RedundantEndpoint{Name: endpointName(u), Client: conns[i]})
+	}
+
+	redundant, err := batcher.NewRedundant(endpoints, p.runCfg.Redundancy)
+	if err != nil {
+		return err
+	}
+	p.redundant = redundant
+	console.Printf("Submitting each tx to %d of %d endpoints\n", p.runCfg.Redundancy, len(endpoints))
+	return nil
+}
+
+// openBalanced connects to --endpoints and sets up the balanced sender with
+// --balance, or a comma-separated --url, so that sends spread over --url and
+// --endpoints and fail over when one of them errors or times out
+func (p *Pipeline) openBalanced() error {
+	policy := p.balancePolicy()
+	if policy == "" || p.balanced != nil {
+		return nil
+	}
+	if len(p.cfg.Endpoints) == 0 {
+		console.Warnf("--balance has only --url to send to; add nodes with --endpoints or a comma-separated --url\n")
+	}
+
+	conns, err := p.dialEndpoints()
+	if err != nil {
+		return err
+	}
+	endpoints := []client.Endpoint{{Name: endpointName(p.cfg.URL), Client: p.client}}
+	for i, u := range p.cfg.Endpoints {
+		endpoints = append(endpoints, client.Endpoint{Name: endpointName(u), Client: conns[i]})
+	}
+
+	balanced, err := client.NewBalanced(endpoints, policy, client.DefaultFailoverTimeout)
+	if err != nil {
+		return err
+	}
+	p.balanced = balanced
+	console.Printf("Balancing sends over %d endpoints (%s)\n", len(endpoints), policy)
+	return nil
+}
+
+// balanceStats returns the per-endpoint send statistics, or nil without --balance
+func (p *Pipeline) balanceStats() *client.BalanceStats {
+	if p.balanced == nil {
+		return nil
+	}
+	return p.balanced.Stats()
+}
+
+// redundancyStats returns the duplicate-acceptance statistics, or nil
+// without --redundancy
+func (p *Pipeline) redundancyStats() *batcher.RedundancyStats {
+	if p.redundant == nil {
+		return nil
+	}
+	return p.redundant.Stats()
+}
+
+// endpointName labels an endpoint by scheme and host only, so API keys in
+// the URL path or query stay out of reports
+func endpointName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "endpoint"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// printRedundancy prints the duplicate-acceptance statistics of a --redundancy run
+func printRedundancy(stats *batcher.RedundancyStats) {
+	if stats == nil || stats.Txs == 0 {
+		return
+	}
+	console.Summaryf("              %d copies per tx: %d acked by all, %d by some, %d by none, %d duplicate acks\n",
+		stats.Copies, stats.AckedByAll, stats.AckedBySome, stats.AckedByNone, stats.DuplicateAcks)
+	console.Summaryf("              first ack after %s, last after %s on average\n", stats.MeanFirstAck, stats.MeanLastAck)
+	for _, e := range stats.Endpoints {
+		console.Summaryf("              %s: %d accepted, %d already known, %d failed, first for %d\n",
+			e.Name, e.Accepted, e.AlreadyKnown, e.Failed, e.FirstAck)
+	}
+}
+
+// printBalance prints the per-endpoint statistics of a --balance run
+func printBalance(stats *client.BalanceStats) {
+	if stats == nil {
+		return
+	}
+	console.Summaryf("              %s over %d endpoints, %d failovers\n", stats.Policy, len(stats.Endpoints), stats.Failovers)
+	for _, e := range stats.Endpoints {
+		latency := e.MeanLatency
+		if latency == "" {
+			latency = "-"
+		}
+		console.Summaryf("              %s: %d sent, %d failed, %d call errors, %s per call\n",
+			e.Name, e.Sent, e.Failed, e.CallErrors, latency)
+	}
+}
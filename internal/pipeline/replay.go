@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/txfile"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// loadReplay reads the --replay-txs file. The run then funds and sends its
+// transactions instead of building new ones, so --transactions follows the
+// file.
+func (p *Pipeline) loadReplay() error {
+	if p.runCfg.ReplayTxs == "" {
+		return nil
+	}
+	start := time.Now()
+	txs, err := txfile.Load(p.runCfg.ReplayTxs)
+	if err != nil {
+		return err
+	}
+	if len(txs) == 0 {
+		return fmt.Errorf("replay file %s holds no transactions", p.runCfg.ReplayTxs)
+	}
+	p.replayLoad = time.Since(start)
+
+	subAccounts := make(map[common.Address]bool)
+	for _, key := range p.subKeys() {
+		subAccounts[txbuilder.AddressFromKey(key)] = true
+	}
+	foreign := 0
+	for i, tx := range txs {
+		if tx.Tx != nil && tx.Tx.Protected() && tx.Tx.ChainId().Cmp(p.chainID) != 0 {
+			return fmt.Errorf("replay tx %d was signed for chain %s, but the node is chain %s", i, tx.Tx.ChainId(), p.chainID)
+		}
+		if !subAccounts[tx.From] {
+			foreign++
+		}
+	}
+	if foreign > 0 {
+		console.Warnf("%d replayed transactions are not from a sub-account of this run and are not funded by it\n", foreign)
+	}
+
+	p.replayTxs = txs
+	p.cfg.Transactions = uint64(len(txs))
+	console.Printf("Loaded %d transactions from %s in %s\n", len(txs), p.runCfg.ReplayTxs, p.replayLoad.Round(time.Millisecond))
+	return nil
+}
+
+// replay takes the transactions of the --replay-txs file as the build
+func (p *Pipeline) replay() error {
+	p.signedTxs = p.replayTxs
+	p.stages.Build = &BuildMetrics{
+		Builder:  "replay",
+		TxsBuilt: len(p.signedTxs),
+	}
+	if p.replayLoad.Seconds() > 0 {
+		p.stages.Build.TxsPerSecond = float64(len(p.signedTxs)) / p.replayLoad.Seconds()
+	}
+
+	console.Printf("\nBuild Summary:\n")
+	console.Printf("  Builder:           replay (%s)\n", p.runCfg.ReplayTxs)
+	console.Printf("  Total Loaded:      %d\n", len(p.signedTxs))
+
+	p.validateTxs()
+	if err := p.checkPlanBudget(); err != nil {
+		return err
+	}
+	return p.buildPoison()
+}
+
+// saveTxs writes the built transactions to the --save-txs file
+func (p *Pipeline) saveTxs() error {
+	if p.runCfg.SaveTxs == "" {
+		return nil
+	}
+	if err := txfile.Save(p.runCfg.SaveTxs, p.signedTxs); err != nil {
+		return err
+	}
+	p.artifacts = append(p.artifacts, p.runCfg.SaveTxs)
+	console.Printf("  Saved To:          %s\n", p.runCfg.SaveTxs)
+	return nil
+}
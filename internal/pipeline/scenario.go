@@ -0,0 +1,250 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/buildinfo"
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/metrics"
+	"github.com/0xmhha/txhammer/internal/scenario"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// PhaseResult is the outcome of one phase of a scenario run
+type PhaseResult struct {
+	Phase   scenario.Phase
+	Result  *Result
+	Err     error // Error Execute returned
+	Skipped bool  // Not run because an earlier phase failed
+}
+
+// ScenarioResult holds the outcome of every phase of a scenario run
+type ScenarioResult struct {
+	Name   string
+	Phases []*PhaseResult
+}
+
+// Success reports whether every phase ran and completed successfully
+func (r *ScenarioResult) Success() bool {
+	for _, p := range r.Phases {
+		if p.Skipped || p.Err != nil || p.Result == nil || !p.Result.Success() {
+			return false
+		}
+	}
+	return true
+}
+
+// ScenarioSummary is the machine-readable result of a scenario run
+type ScenarioSummary struct {
+	Name    string         `json:"name,omitempty"`
+	Success bool           `json:"success"`
+	Phases  []PhaseSummary `json:"phases"`
+}
+
+// PhaseSummary is one phase of a scenario run
+type PhaseSummary struct {
+	Name         string      `json:"name"`
+	Mode         string      `json:"mode"`
+	TargetTPS    float64     `json:"target_tps"`
+	Transactions uint64      `json:"transactions"`
+	Skipped      bool        `json:"skipped,omitempty"`
+	Error        string      `json:"error,omitempty"` // Why the phase could not start
+	Summary      *RunSummary `json:"summary,omitempty"`
+}
+
+// Summary returns the machine-readable summary of the scenario run
+func (r *ScenarioResult) Summary() *ScenarioSummary {
+	s := &ScenarioSummary{Name: r.Name, Success: r.Success(), Phases: make([]PhaseSummary, 0, len(r.Phases))}
+	for _, p := range r.Phases {
+		ps := PhaseSummary{
+			Name:         p.Phase.Name,
+			Mode:         p.Phase.Mode,
+			TargetTPS:    p.Phase.TPS,
+			Transactions: p.Phase.Count(),
+			Skipped:      p.Skipped,
+		}
+		switch {
+		case p.Result != nil:
+			ps.Summary = p.Result.Summary(p.Err)
+		case p.Err != nil:
+			ps.Error = p.Err.Error()
+		}
+		s.Phases = append(s.Phases, ps)
+	}
+	return s
+}
+
+// ExecuteScenario runs the phases of the --scenario file of cfg one after
+// the other, each as its own streaming pipeline at the phase's rate from the
+// same sub-accounts. A phase funds the sub-accounts for its own transactions
+// before it sends. Reports of each phase go to a subdirectory of the output
+// directory, and a scenario summary combines them. The phases after one that
+// fails are skipped.
+func ExecuteScenario(ctx context.Context, cfg *config.Config, runCfg *RunConfig, build buildinfo.Info) (*ScenarioResult, error) {
+	sc, err := scenario.Load(cfg.Scenario)
+	if err != nil {
+		return nil, err
+	}
+	if len(runCfg.Workloads) > 0 {
+		return nil, fmt.Errorf("scenario phases cannot be combined with workload: use one phase per mode")
+	}
+
+	// Validate every phase before the first starts
+	cfgs := make([]*config.Config, len(sc.Phases))
+	runCfgs := make([]*RunConfig, len(sc.Phases))
+	result := &ScenarioResult{Name: sc.Name, Phases: make([]*PhaseResult, len(sc.Phases))}
+	for i, phase := range sc.Phases {
+		if !mixable(config.Mode(phase.Mode)) {
+			return nil, fmt.Errorf("phase %s: %s mode runs its own send loop and cannot be a scenario phase", phase.Name, phase.Mode)
+		}
+		c, err := phaseConfig(cfg, phase)
+		if err != nil {
+			return nil, fmt.Errorf("phase %s: %w", phase.Name, err)
+		}
+		cfgs[i] = c
+
+		r := *runCfg
+		r.StreamingMode = true
+		r.StreamingRate = phase.TPS
+		if r.OutputDir != "" {
+			r.OutputDir = filepath.Join(r.OutputDir, phase.Name)
+		}
+		r.Journal = workloadPath(r.Journal, phase.Name)
+		r.NonceSnapshot = workloadPath(r.NonceSnapshot, phase.Name)
+		r.TraceFile = workloadPath(r.TraceFile, phase.Name)
+		runCfgs[i] = &r
+
+		result.Phases[i] = &PhaseResult{Phase: phase}
+	}
+
+	// Metrics register once per process, so the phases share them
+	var shared *metrics.Metrics
+	if cfg.MetricsEnabled || cfg.PushGateway != "" {
+		shared = startMetrics(ctx, cfg, runCfg, build)
+		if shared == nil {
+			for _, c := range cfgs {
+				c.MetricsEnabled = false
+				c.PushGateway = ""
+			}
+		} else {
+			defer func() {
+				if err := shared.Stop(context.Background()); err != nil {
+					console.Warnf("Failed to stop metrics server: %v\n", err)
+				}
+			}()
+		}
+	}
+
+	console.Printf("\nStarting Scenario %s\n\n", sc.Name)
+	for _, p := range result.Phases {
+		console.Printf("  %-22s %-20s %8.1f tx/s  %8d txs\n", p.Phase.Name, p.Phase.Mode, p.Phase.TPS, p.Phase.Count())
+	}
+
+	failed := false
+	for i, p := range result.Phases {
+		if failed || ctx.Err() != nil {
+			p.Skipped = true
+			continue
+		}
+		console.Printf("\nPhase %d/%d: %s\n", i+1, len(result.Phases), p.Phase.Name)
+		p.Result, p.Err = runPhase(ctx, cfgs[i], runCfgs[i], build, shared)
+		failed = p.Err != nil
+	}
+
+	if !runCfg.JSONSummary {
+		printScenario(result)
+	}
+	if runCfg.ExportReport && runCfg.OutputDir != "" {
+		file, err := writeScenario(runCfg.OutputDir, result.Summary())
+		if err != nil {
+			console.Warnf("Failed to export scenario summary: %v\n", err)
+		} else {
+			console.Printf("Scenario summary exported to: %s\n", file)
+		}
+	}
+	return result, ctx.Err()
+}
+
+// phaseConfig returns cfg with the mode, transactions and settings of phase
+func phaseConfig(cfg *config.Config, phase scenario.Phase) (*config.Config, error) {
+	c := *cfg
+	c.Scenario = ""
+	c.Mode = phase.Mode
+	c.Transactions = phase.Count()
+	if phase.Contract != "" {
+		c.Contract = phase.Contract
+	}
+	if phase.Method != "" {
+		c.Method = phase.Method
+	}
+	if phase.Args != "" {
+		c.Args = phase.Args
+	}
+	if phase.Value != "" {
+		c.Value = phase.Value
+	}
+	if phase.GasLimit > 0 {
+		c.GasLimit = phase.GasLimit
+	}
+	if phase.GasPrice != "" {
+		c.GasPrice = phase.GasPrice
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// runPhase runs one phase of a scenario as a pipeline of its own, recording
+// to the metrics of the scenario
+func runPhase(ctx context.Context, cfg *config.Config, runCfg *RunConfig, build buildinfo.Info, shared *metrics.Metrics) (*Result, error) {
+	p, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+	p.WithRunConfig(runCfg).WithBuildInfo(build).WithMetrics(shared)
+	return p.Execute(ctx)
+}
+
+// printScenario prints the combined table of a scenario run
+func printScenario(result *ScenarioResult) {
+	rows := make([]scenario.Row, 0, len(result.Phases))
+	for _, p := range result.Phases {
+		row := scenario.Row{Name: p.Phase.Name, Mode: p.Phase.Mode, TargetTPS: p.Phase.TPS, Err: p.Err, Skipped: p.Skipped}
+		if r := p.Result; r != nil {
+			row.Sent = r.TotalTransactions
+			row.Confirmed = r.SuccessfulTxs
+			row.Failed = r.FailedTxs
+			row.ConfirmedTPS = r.WallClockConfirmedTPS
+			row.AvgLatency = r.AvgLatency
+			row.P95Latency = r.P95Latency
+			row.Duration = r.Duration
+		}
+		rows = append(rows, row)
+	}
+	scenario.PrintTable(result.Name, rows)
+}
+
+// writeScenario writes the summary of a scenario run to the output directory
+func writeScenario(outputDir string, summary *ScenarioSummary) (string, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal scenario summary: %w", err)
+	}
+
+	filename := filepath.Join(outputDir, fmt.Sprintf("scenario_%s.json", time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write scenario summary: %w", err)
+	}
+	return filename, nil
+}
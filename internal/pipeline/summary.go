@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/alert"
+	"github.com/0xmhha/txhammer/internal/blockstop"
+	"github.com/0xmhha/txhammer/internal/budget"
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/collector"
+)
+
+// RunSummary is the machine-readable result printed by --json-summary
+type RunSummary struct {
+	Success      bool                      `json:"success"`
+	Verdict      string                    `json:"verdict"`           // One-line outcome, as printed last
+	Partial      bool                      `json:"partial,omitempty"` // Cut short by --max-runtime
+	Region       string                    `json:"region,omitempty"`
+	Workload     *collector.JSONWorkload   `json:"workload,omitempty"` // Parameters and hash of the workload sent
+	StartTime    string                    `json:"start_time"`         // RFC3339
+	EndTime      string                    `json:"end_time"`           // RFC3339
+	Duration     string                    `json:"duration"`           // Go duration string
+	Stages       []StageSummary            `json:"stages"`
+	StageMetrics *StageMetrics             `json:"stage_metrics,omitempty"`
+	BudgetStop   *budget.Stop              `json:"budget_stop,omitempty"`
+	BlockStop    *blockstop.Stop           `json:"block_stop,omitempty"`
+	RateLimit    *RateLimit                `json:"rate_limit,omitempty"`
+	Quarantined  []QuarantineSummary       `json:"quarantined,omitempty"` // Accounts taken out of the rotation
+	Alerts       []alert.Alert             `json:"alerts,omitempty"`      // Periods above --alert-failure-rate
+	Throttling   []client.EndpointThrottle `json:"throttling,omitempty"`  // Endpoints that throttled requests
+	Report       *collector.JSONReport     `json:"report,omitempty"`      // Same document as report_<timestamp>.json
+	Error        string                    `json:"error,omitempty"`       // Error that ended the run
+	Errors       []string                  `json:"errors,omitempty"`      // Stage errors
+}
+
+// StageSummary is the outcome of one pipeline stage
+type StageSummary struct {
+	Stage    string `json:"stage"`
+	Success  bool   `json:"success"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// QuarantineSummary is an account the long sender quarantined
+type QuarantineSummary struct {
+	Account   string `json:"account"`
+	Failures  int    `json:"failures"` // Consecutive failures that triggered it
+	LastError string `json:"last_error"`
+	Sent      int64  `json:"sent"` // Transactions it sent before
+	At        string `json:"at"`   // RFC3339
+}
+
+// Summary returns the machine-readable summary of the result. runErr is the
+// error Execute returned, which fails the run even if every stage succeeded.
+func (r *Result) Summary(runErr error) *RunSummary {
+	end := r.EndTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	s := &RunSummary{
+		Success:    runErr == nil && r.Success(),
+		Verdict:    r.verdict(runErr),
+		Partial:    r.Partial,
+		Region:     r.Region,
+		Workload:   r.Workload,
+		StartTime:  r.StartTime.Format(time.RFC3339),
+		EndTime:    end.Format(time.RFC3339),
+		Duration:   end.Sub(r.StartTime).String(),
+		Stages:     make([]StageSummary, 0, len(r.StageResults)),
+		BudgetStop: r.BudgetStop,
+		BlockStop:  r.BlockStop,
+		RateLimit:  r.RateLimit,
+		Alerts:     r.Alerts,
+		Throttling: r.Throttling,
+	}
+	for _, sr := range r.StageResults {
+		stage := StageSummary{Stage: sr.Stage.String(), Success: sr.Success, Duration: sr.Duration.String()}
+		if sr.Error != nil {
+			stage.Error = sr.Error.Error()
+		}
+		s.Stages = append(s.Stages, stage)
+	}
+	if m := r.Stages; m != nil && (m.Distribute != nil || m.Approve != nil || m.Build != nil || m.Send != nil || m.Collect != nil) {
+		s.StageMetrics = m
+	}
+	if r.Report != nil && r.Report.Metrics != nil {
+		s.Report = collector.NewJSONReport(r.Report)
+	}
+	for _, q := range r.Quarantined {
+		s.Quarantined = append(s.Quarantined, QuarantineSummary{
+			Account:   q.Account.Hex(),
+			Failures:  q.Failures,
+			LastError: q.LastError,
+			Sent:      q.Sent,
+			At:        q.At.Format(time.RFC3339),
+		})
+	}
+	if runErr != nil {
+		s.Error = runErr.Error()
+	}
+	for _, err := range r.Errors {
+		s.Errors = append(s.Errors, err.Error())
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// recordThrottling stores the endpoints that throttled requests in the
+// result and the throttled sends in the send metrics
+func (p *Pipeline) recordThrottling(result *Result) {
+	result.Throttling = client.Throttling()
+	if p.stages == nil || p.stages.Send == nil {
+		return
+	}
+	p.failuresMu.Lock()
+	p.stages.Send.TxsThrottled = p.throttledSends
+	p.failuresMu.Unlock()
+}
+
+// printThrottling prints how long each endpoint held requests back after
+// throttling them, so that time lost to the provider is not mistaken for
+// chain capacity. send may be nil.
+func printThrottling(throttling []client.EndpointThrottle, send *SendMetrics, runtime time.Duration) {
+	if len(throttling) == 0 {
+		return
+	}
+	console.Warnf("\nProvider Throttling: %d endpoints rate-limited requests\n", len(throttling))
+	for _, t := range throttling {
+		share := 0.0
+		if runtime > 0 {
+			share = float64(t.HeldBack) / float64(runtime) * 100
+		}
+		console.Summaryf("  - %s: %d responses, held back %s (%.1f%% of the run)\n",
+			t.Endpoint, t.Responses, t.HeldBack.Round(time.Millisecond), share)
+		console.Summaryf("    last: %s\n", t.LastError)
+	}
+	if send != nil && send.TxsThrottled > 0 {
+		console.Summaryf("  Sends refused by throttling: %d of %d failed (the rest are node rejections)\n", send.TxsThrottled, send.TxsFailed)
+	}
+	console.Summaryf("  Throughput was limited by the provider, not only by chain capacity\n")
+}
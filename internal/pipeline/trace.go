@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/trace"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Trace tracks, in display order. Steps within a stage nest under it on the
+// stages track. Batches take one track per concurrent lane, starting at
+// traceBatchLanes.
+const (
+	traceStages     = 1
+	traceCollect    = 2
+	traceBatchLanes = 10
+)
+
+// startTrace starts recording the run timeline if --trace is set
+func (p *Pipeline) startTrace() {
+	if p.runCfg.TraceFile == "" {
+		return
+	}
+	p.trace = trace.New()
+	p.trace.Track(traceStages, "stages")
+	p.trace.Track(traceCollect, "receipt polling")
+}
+
+// writeTrace writes the recorded timeline and lists it in the run manifest
+func (p *Pipeline) writeTrace() {
+	if p.trace == nil {
+		return
+	}
+	if err := p.trace.WriteFile(p.runCfg.TraceFile); err != nil {
+		console.Warnf("Failed to write trace: %v\n", err)
+		return
+	}
+	p.artifacts = append(p.artifacts, p.runCfg.TraceFile)
+	console.Printf("Trace written to: %s (open in https://ui.perfetto.dev)\n", p.runCfg.TraceFile)
+}
+
+// traceStage records a pipeline stage
+func (p *Pipeline) traceStage(stage Stage, start time.Time, duration time.Duration, err error) {
+	args := map[string]any{"success": err == nil}
+	if err != nil {
+		args["error"] = err.Error()
+	}
+	p.trace.Span(traceStages, "stage", stage.String(), start, duration, args)
+}
+
+// traceBatches records every sent batch, spreading concurrent batches over
+// separate lanes
+func (p *Pipeline) traceBatches(results []*batcher.BatchResult) {
+	if p.trace == nil {
+		return
+	}
+	sent := make([]*batcher.BatchResult, 0, len(results))
+	starts := make([]time.Time, 0, len(results))
+	durs := make([]time.Duration, 0, len(results))
+	for _, r := range results {
+		if r == nil || r.StartTime.IsZero() {
+			continue
+		}
+		sent = append(sent, r)
+		starts = append(starts, r.StartTime)
+		durs = append(durs, r.Duration)
+	}
+
+	lanes, n := trace.Lanes(starts, durs)
+	for lane := 0; lane < n; lane++ {
+		p.trace.Track(traceBatchLanes+lane, fmt.Sprintf("batch lane %d", lane+1))
+	}
+	for i, r := range sent {
+		args := map[string]any{"txs": r.TxCount, "sent": r.SuccessCount, "failed": r.FailedCount}
+		if r.ExpiredCount > 0 {
+			args["expired"] = r.ExpiredCount
+		}
+		if r.Error != nil {
+			args["error"] = r.Error.Error()
+		}
+		p.trace.Span(traceBatchLanes+lanes[i], "batch", fmt.Sprintf("batch %d", r.BatchIndex), r.StartTime, r.Duration, args)
+	}
+}
+
+// collectorCallbacks returns the collector callbacks, recording polling
+// cycles in the trace next to onReceipt (nil = none)
+func (p *Pipeline) collectorCallbacks(onReceipt func(*types.Receipt)) *collector.Callbacks {
+	callbacks := &collector.Callbacks{OnReceipt: onReceipt}
+	if p.trace != nil {
+		callbacks.OnCycle = func(start time.Time, duration time.Duration, finished int) {
+			p.trace.Span(traceCollect, "collect", "poll", start, duration, map[string]any{"finished": finished})
+		}
+	}
+	return callbacks
+}
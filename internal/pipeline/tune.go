@@ -0,0 +1,134 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xmhha/txhammer/internal/client"
+	"github.com/0xmhha/txhammer/internal/tune"
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/mathutil"
+)
+
+// ConcurrencyProbe is the outcome of --auto-concurrency
+type ConcurrencyProbe struct {
+	Send    *tune.Result `json:"send"`    // Batches of eth_sendRawTransaction, sizing --max-concurrent
+	Receipt *tune.Result `json:"receipt"` // eth_getTransactionReceipt calls, sizing --confirm-concurrency
+}
+
+// tuneConcurrency probes the node with --auto-concurrency and sets the send
+// and receipt polling concurrency from the result. Each is probed with the
+// requests it caps: batches of the run's size of transactions the node must
+// refuse, and receipt lookups of a hash that is not on chain.
+func (p *Pipeline) tuneConcurrency(ctx context.Context) {
+	if !p.runCfg.AutoConcurrency {
+		return
+	}
+	send, err := p.sendProbe()
+	if err != nil {
+		console.Warnf("Concurrency probe failed (%v); keeping --max-concurrent %d and --confirm-concurrency %d\n",
+			err, p.runCfg.MaxConcurrent, p.runCfg.ConfirmConcurrency)
+		return
+	}
+	probe := &ConcurrencyProbe{}
+	p.stages.ConcurrencyProbe = probe
+
+	console.Printf("\nProbing send concurrency (batches of %d)...\n", p.cfg.BatchSize)
+	probe.Send = tune.Probe(ctx, send, tune.DefaultConfig())
+	if best, ok := reportProbe(probe.Send, "--max-concurrent", p.runCfg.MaxConcurrent); ok {
+		p.runCfg.MaxConcurrent = best
+	}
+
+	console.Printf("\nProbing receipt polling concurrency...\n")
+	probe.Receipt = tune.Probe(ctx, p.receiptProbe(), tune.DefaultConfig())
+	if best, ok := reportProbe(probe.Receipt, "--confirm-concurrency", p.runCfg.ConfirmConcurrency); ok {
+		p.runCfg.ConfirmConcurrency = best
+	}
+}
+
+// reportProbe prints the levels of a probe and returns the concurrency it
+// found for flag, or false if the probe failed and current is kept
+func reportProbe(result *tune.Result, flag string, current int) (int, bool) {
+	for _, l := range result.Levels {
+		console.Printf("  %4d concurrent: %8.0f calls/s, p50 %s, p95 %s, %.1f%% errors\n",
+			l.Concurrency, l.Throughput, l.P50, l.P95, l.ErrorRate())
+	}
+	if result.Best == 0 {
+		console.Warnf("Probe failed (%s); keeping %s %d\n", result.Reason, flag, current)
+		return 0, false
+	}
+	stopped := result.Reason
+	if stopped == "" {
+		stopped = "probe limit reached"
+	}
+	console.OKf("Tuned to %s %d (%s)\n", flag, result.Best, stopped)
+	return result.Best, true
+}
+
+// sendProbe returns a call that sends a batch of --batch transactions
+// like the batcher does. They come from a fresh key without funds, so the
+// node decodes, recovers and checks each one against state, then refuses it.
+// Refusals count as served; only failed requests and throttling count as errors.
+func (p *Pipeline) sendProbe() (tune.Call, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(p.chainID), &types.LegacyTx{
+		To:       &from,
+		Value:    big.NewInt(1), // Never affordable, even at a zero gas price
+		Gas:      21000,
+		GasPrice: big.NewInt(1000000000),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign probe transaction: %w", err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	encoded := "0x" + common.Bytes2Hex(raw)
+
+	batchSize, err := mathutil.Uint64ToInt(p.cfg.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid batch size: %w", err)
+	}
+	batchSize = max(batchSize, 1)
+	return func(ctx context.Context) error {
+		batch := make([]rpc.BatchElem, batchSize)
+		for i := range batch {
+			batch[i] = rpc.BatchElem{Method: "eth_sendRawTransaction", Args: []any{encoded}, Result: new(common.Hash)}
+		}
+		if err := p.client.BatchCallContext(ctx, batch); err != nil {
+			return err
+		}
+		for _, elem := range batch {
+			if client.IsThrottled(elem.Error) {
+				return elem.Error
+			}
+		}
+		return nil
+	}, nil
+}
+
+// receiptProbe returns a call that looks up the receipt of a transaction
+// like the collector does. The hash is not on chain, so a null result is served.
+func (p *Pipeline) receiptProbe() tune.Call {
+	hash := crypto.Keccak256Hash([]byte("txhammer concurrency probe"))
+	return func(ctx context.Context) error {
+		_, err := p.client.TransactionReceipt(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			return nil
+		}
+		return err
+	}
+}
@@ -1,9 +1,27 @@
 package pipeline
 
 import (
+	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
+	"github.com/0xmhha/txhammer/internal/alert"
+	"github.com/0xmhha/txhammer/internal/batcher"
+	"github.com/0xmhha/txhammer/internal/blockstop"
+	"github.com/0xmhha/txhammer/internal/budget"
+	"github.com/0xmhha/txhammer/internal/client"
 	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/consistency"
+	"github.com/0xmhha/txhammer/internal/distributor"
+	"github.com/0xmhha/txhammer/internal/longsender"
+	"github.com/0xmhha/txhammer/internal/mempool"
+	"github.com/0xmhha/txhammer/internal/mix"
+	"github.com/0xmhha/txhammer/internal/noncewatch"
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/compress"
+	"github.com/0xmhha/txhammer/internal/util/units"
+	"github.com/0xmhha/txhammer/internal/watchdog"
 )
 
 // Stage represents a pipeline stage
@@ -49,43 +67,632 @@ type StageResult struct {
 	Error    error
 }
 
+// DistributeMetrics holds metrics contributed by the DISTRIBUTE stage
+type DistributeMetrics struct {
+	AccountsReady    int    `json:"accounts_ready"`
+	AccountsFunded   int    `json:"accounts_funded"`
+	AccountsUnfunded int    `json:"accounts_unfunded"`
+	WeiMoved         string `json:"wei_moved"`
+
+	// Funding retries of accounts still short after the distribution
+	Retries []*distributor.RetryAttempt `json:"retries,omitempty"`
+}
+
+// ApproveMetrics holds the approve() phase of APPROVE_TRANSFERFROM, sent and
+// mined during the BUILD stage before the transferFrom() calls are built
+type ApproveMetrics struct {
+	TxsSent    int     `json:"txs_sent"`
+	TxsFailed  int     `json:"txs_failed"` // Refused by the node
+	Confirmed  int     `json:"confirmed"`
+	Reverted   int     `json:"reverted"`
+	Pending    int     `json:"pending"`  // Not mined within --timeout
+	Duration   string  `json:"duration"` // Go duration from the first send to the last receipt
+	Throughput float64 `json:"throughput"`
+	AvgLatency string  `json:"avg_latency,omitempty"`
+}
+
+// BuildMetrics holds metrics contributed by the BUILD stage
+type BuildMetrics struct {
+	Builder      string  `json:"builder"`
+	TxsBuilt     int     `json:"txs_built"`
+	TxsPerSecond float64 `json:"txs_per_second"`
+	TxsInvalid   int     `json:"txs_invalid,omitempty"` // Duplicates, nonce conflicts and chain ID mismatches
+	TxsPruned    int     `json:"txs_pruned,omitempty"`  // Invalid txs dropped by --prune-invalid
+}
+
+// SendMetrics holds metrics contributed by the SEND stage
+type SendMetrics struct {
+	Method        string  `json:"method"`
+	TxsSent       int     `json:"txs_sent"`
+	TxsFailed     int     `json:"txs_failed"`
+	TxsExpired    int     `json:"txs_expired,omitempty"` // Dropped unsent at --send-deadline
+	RPCThroughput float64 `json:"rpc_throughput"`
+	BytesSent     int64   `json:"bytes_sent"`
+	Bandwidth     float64 `json:"bandwidth"` // Bytes per second
+	Connections   int     `json:"connections,omitempty"`
+
+	// Duplicate-acceptance statistics of a --redundancy run
+	Redundancy *batcher.RedundancyStats `json:"redundancy,omitempty"`
+
+	// Per-endpoint statistics of a --balance run
+	Balance *client.BalanceStats `json:"balance,omitempty"`
+
+	// Per-transport breakdown of a --p2p-compare run
+	Transports []*TransportMetrics `json:"transports,omitempty"`
+
+	Poison     []*PoisonStats  `json:"poison,omitempty"`
+	BudgetStop *budget.Stop    `json:"budget_stop,omitempty"`
+	BlockStop  *blockstop.Stop `json:"block_stop,omitempty"`
+	TxsUnsent  int             `json:"txs_unsent,omitempty"` // Left unsent at --stop-at-block
+
+	// Sends refused by provider throttling rather than by the node
+	TxsThrottled int `json:"txs_throttled,omitempty"`
+
+	// Per-batch outcome in batch order, for plotting when the node started
+	// rejecting batches (batch sends only)
+	Batches []*BatchMetrics `json:"batches,omitempty"`
+
+	// Per-tranche outcome of a --per-block send
+	Tranches []*TrancheMetrics `json:"tranches,omitempty"`
+}
+
+// TrancheMetrics is the send outcome of the txs released after one head
+type TrancheMetrics struct {
+	Index       int    `json:"index"`
+	AfterBlock  uint64 `json:"after_block"`            // Head the tranche was released after
+	MissedHeads uint64 `json:"missed_heads,omitempty"` // Heads passed while the previous tranche was sending
+	Released    string `json:"released"`               // RFC3339 time the head was seen
+	Duration    string `json:"duration"`               // Go duration string
+	Txs         int    `json:"txs"`
+	Failed      int    `json:"failed"`
+}
+
+// BatchMetrics is the send outcome of one batch
+type BatchMetrics struct {
+	Index    int    `json:"index"`
+	Start    string `json:"start"`    // RFC3339 time the batch started sending
+	Offset   string `json:"offset"`   // Go duration from the first batch start
+	Duration string `json:"duration"` // Go duration string
+	Txs      int    `json:"txs"`
+	Failed   int    `json:"failed"`
+	Error    string `json:"error,omitempty"` // Set if the whole batch failed
+}
+
+// TransportMetrics compares one send path of a --p2p-compare run. Each path
+// sends from its own half of the accounts, so confirmations are attributed
+// by sender.
+type TransportMetrics struct {
+	Method     string  `json:"method"`
+	Accounts   int     `json:"accounts"`
+	TxsSent    int     `json:"txs_sent"`
+	TxsFailed  int     `json:"txs_failed"`
+	Throughput float64 `json:"throughput"`
+	Bandwidth  float64 `json:"bandwidth"` // Bytes per second
+	Confirmed  int     `json:"confirmed"`
+	AvgLatency string  `json:"avg_latency,omitempty"`
+}
+
+// CollectMetrics holds metrics contributed by the COLLECT stage
+type CollectMetrics struct {
+	Confirmed         int     `json:"confirmed"`
+	Failed            int     `json:"failed"`
+	Timeout           int     `json:"timeout"`
+	Evicted           int     `json:"evicted"`
+	ConfirmThroughput float64 `json:"confirm_throughput"`
+
+	// CREATE2_CHURN mode only
+	ContractsCreated   int64 `json:"contracts_created,omitempty"`
+	ContractsDestroyed int64 `json:"contracts_destroyed,omitempty"`
+}
+
+// StageMetrics groups the structured metrics contributed by each stage.
+// Stages that did not run leave their section nil.
+type StageMetrics struct {
+	Distribute *DistributeMetrics `json:"distribute,omitempty"`
+	Approve    *ApproveMetrics    `json:"approve,omitempty"` // APPROVE_TRANSFERFROM mode only
+	Build      *BuildMetrics      `json:"build,omitempty"`
+	Send       *SendMetrics       `json:"send,omitempty"`
+	Collect    *CollectMetrics    `json:"collect,omitempty"`
+
+	// Foreign transaction pool impact, measured around the run with --mempool-diff
+	Mempool *mempool.Impact `json:"mempool,omitempty"`
+
+	// Sub-accounts whose nonce stood still mid-run, sampled with --nonce-sample-interval
+	NonceStalls []noncewatch.Stall `json:"nonce_stalls,omitempty"`
+
+	// RPC concurrency levels tried with --auto-concurrency
+	ConcurrencyProbe *ConcurrencyProbe `json:"concurrency_probe,omitempty"`
+
+	// Agreement of --url and --endpoints on the blocks holding our txs, with --consistency-check
+	Consistency *consistency.Result `json:"consistency,omitempty"`
+}
+
 // RunConfig holds runtime configuration for the pipeline
 type RunConfig struct {
 	// Skip distribution if accounts already funded
 	SkipDistribution bool
 
+	// Event signatures a successful tx should emit, replacing the mode's default ("none" = unchecked)
+	ExpectEvents []string
+
+	// Per-account funding target in wei, replacing the computed one ("" = computed)
+	FundAmount string
+
+	// Headroom in wei added to each account's funding target ("" = none)
+	FundExtra string
+
+	// Times sub-accounts still short after the distribution are funded again
+	FundRetries int
+
+	// Percent a funding retry raises the gas price at least, to meet the
+	// node's price bump rule for replacements
+	PriceBump int
+
 	// Skip collection (fire-and-forget mode)
 	SkipCollection bool
 
+	// Collect receipts while sending, tracking each tx once the node acknowledges it
+	CollectDuringSend bool
+
+	// Collect receipts per block on a newHeads subscription instead of polling
+	// each pending tx; HTTP endpoints keep polling
+	SubscribeReceipts bool
+
 	// Export report to files
 	ExportReport bool
 
 	// Output directory for reports
 	OutputDir string
 
+	// Format for the transaction and block datasets (csv or parquet)
+	DatasetFormat string
+
+	// Compression of the CSV datasets and nonce samples (none, zstd, gzip)
+	Compression string
+
 	// Use streaming mode instead of batch mode
 	StreamingMode bool
 
+	// MODE:TPS workloads sent concurrently from separate sub-accounts (empty = the single --mode)
+	Workloads []string
+
+	// Stop sending once the chain head reaches this block; receipts split at it in the report (0 = disabled)
+	StopAtBlock uint64
+
+	// Enode URL to send transactions to over devp2p instead of JSON-RPC (experimental)
+	P2PEnode string
+
+	// Split accounts between JSON-RPC and devp2p to compare the two paths
+	P2PCompare bool
+
 	// Rate limit for streaming mode (tx/s)
 	StreamingRate float64
 
+	// Rate limiter burst of streaming and long sender modes (0 = derived from the rate)
+	Burst int
+
+	// Dedicated RPC connections per streaming worker (0 = workers share the main client)
+	ConnectionsPerWorker int
+
+	// Submit every tx to this many of --url and --endpoints at once (0 or 1 = --url only)
+	Redundancy int
+
+	// Spread sends over --url and --endpoints by this policy with failover ("" = --url only)
+	Balance string
+
+	// Release this many txs right after each new head instead of free-running (0 = disabled)
+	TxsPerBlock int
+
+	// Consecutive account failures after which the long sender quarantines an account (0 = never)
+	QuarantineAfter int
+
+	// Resolve DNS once and open and warm the send connections before the send stage
+	Prewarm bool
+
+	// No-op calls made on each connection when pre-warming
+	PrewarmCalls int
+
+	// Longest pause of an endpoint after it throttled requests (0 = record throttling only)
+	ThrottleBackoff time.Duration
+
+	// Max concurrent batch requests in batch mode
+	MaxConcurrent int
+
+	// Max concurrent receipt queries of the collector
+	ConfirmConcurrency int
+
+	// Probe the node at start and set MaxConcurrent and ConfirmConcurrency from the result
+	AutoConcurrency bool
+
 	// Dry run (build transactions but don't send)
 	DryRun bool
+
+	// File the built transactions are written to for a later --replay-txs run ("" = disabled)
+	SaveTxs string
+
+	// File of signed transactions sent instead of building new ones ("" = build)
+	ReplayTxs string
+
+	// File recording deployed helper contracts per chain ID ("" = always deploy)
+	FixtureCache string
+
+	// Deploy helper contracts even if the fixture cache holds a usable deployment
+	Redeploy bool
+
+	// Nonce snapshot file, written at the end of the run
+	NonceSnapshot string
+
+	// Take initial nonces from the snapshot instead of querying the node
+	TrustNonceSnapshot bool
+
+	// Write-ahead journal file each acknowledged tx is appended to ("" = disabled)
+	Journal string
+
+	// No new block for this long pauses sending as a chain halt (0 = disabled)
+	HaltWindow time.Duration
+
+	// Number of slowest and fastest confirmed txs listed in the report (0 = disabled)
+	TopTxs int
+
+	// Length of chain time over which the peak TPS is reported (0 = disabled)
+	PeakWindow time.Duration
+
+	// Confirmation time interval of the latency heatmap in the JSON report (0 = disabled)
+	HeatmapInterval time.Duration
+
+	// Chrome trace-event file of stage, batch and collection cycle timings ("" = disabled)
+	TraceFile string
+
+	// Failed eth_sendRawTransaction exchanges to record in full (0 = disabled)
+	CaptureFailures int
+
+	// Send failure rate in percent over AlertWindow that raises an alert during the run (0 = disabled)
+	AlertFailureRate float64
+
+	// Sliding window the alert failure rate is measured over
+	AlertWindow time.Duration
+
+	// Compare hashes and state roots of the blocks holding our txs across --url and --endpoints
+	ConsistencyCheck bool
+
+	// Interval at which the nonce of every sub-account is sampled during send and collection (0 = disabled)
+	NonceSampleInterval time.Duration
+
+	// Append the live counters to a CSV in the output directory once per second
+	FlightRecorder bool
+
+	// Label of the region or worker running the test, attached to reports and metrics ("" = none)
+	Region string
+
+	// Blocks after which an unmined tx unknown to the node counts as evicted (0 = disabled)
+	EvictionBlocks uint64
+
+	// Percent of extra, deliberately invalid txs sent alongside the load (0 = disabled)
+	PoisonRate float64
+
+	// Unit for wei amounts in console output (wei, gwei, ether, auto)
+	Denomination string
+
+	// Decimal places for gwei and ether amounts in console output
+	Decimals int
+
+	// Fee budget in wei; sending stops before it would be exceeded ("" = unlimited)
+	MaxSpend string
+
+	// Decode the L1 data fee of rollup receipts and include it in costs
+	L1Fees bool
+
+	// Send every tx with a zero gas price and fund sub-accounts for value only
+	// (zero-fee networks are also detected without it)
+	ZeroFee bool
+
+	// Bounds for the tip and fee cap of built transactions, in wei ("" = unbounded)
+	MinTip    string
+	MaxTip    string
+	MinFeeCap string
+	MaxFeeCap string
+
+	// Tx records the collector keeps in memory before spilling finished ones to disk (0 = unlimited)
+	MemoryCap int
+
+	// Parent directory for the collector's spill store (empty = system temp dir)
+	SpillDir string
+
+	// Fail instead of warning when the preflight finds the load cannot fit into blocks
+	Strict bool
+
+	// Start even when the node reports it is still syncing
+	AllowSyncing bool
+
+	// Peers the node must report before the run starts (0 = no peer check)
+	MinPeers uint64
+
+	// Drop duplicate, nonce-conflicting and wrong-chain txs before sending instead of only reporting them
+	PruneInvalid bool
+
+	// Print the result as one JSON document on stdout instead of the summary box
+	JSONSummary bool
+
+	// Detail of the printed summary: minimal, normal or full
+	SummaryLevel string
+
+	// Snapshot the txpool before and after the run and report the impact on foreign txs
+	MempoolDiff bool
+
+	// Deadline for the whole run, including distribution and build (0 = unlimited)
+	MaxRuntime time.Duration
+
+	// How long a tx may wait for RPC acceptance before it is dropped unsent (0 = no deadline)
+	SendDeadline time.Duration
 }
 
 // DefaultRunConfig returns default run configuration
 func DefaultRunConfig() *RunConfig {
 	return &RunConfig{
-		SkipDistribution: false,
-		SkipCollection:   false,
-		ExportReport:     true,
-		OutputDir:        "./reports",
-		StreamingMode:    false,
-		StreamingRate:    1000,
-		DryRun:           false,
+		SkipDistribution:   false,
+		SkipCollection:     false,
+		ExportReport:       true,
+		FlightRecorder:     true,
+		OutputDir:          "./reports",
+		DatasetFormat:      string(collector.FormatCSV),
+		SummaryLevel:       string(collector.SummaryNormal),
+		StreamingMode:      false,
+		StreamingRate:      1000,
+		MaxConcurrent:      100,
+		ConfirmConcurrency: 20,
+		PrewarmCalls:       3,
+		FundRetries:        distributor.DefaultFundRetries,
+		PriceBump:          distributor.DefaultPriceBump,
+		ThrottleBackoff:    client.DefaultThrottleBackoff,
+		AlertWindow:        10 * time.Second,
+		DryRun:             false,
+		FixtureCache:       ".txhammer-fixtures.json",
+		TopTxs:             10,
+		PeakWindow:         10 * time.Second,
+		HeatmapInterval:    10 * time.Second,
+		Denomination:       string(units.Auto),
+		Decimals:           6,
 	}
 }
 
+// Validate validates the run configuration
+func (c *RunConfig) Validate() error {
+	if c.DatasetFormat != "" {
+		format, err := collector.ParseDatasetFormat(c.DatasetFormat)
+		if err != nil {
+			return err
+		}
+		c.DatasetFormat = string(format)
+	}
+	if c.SummaryLevel != "" {
+		level, err := collector.ParseSummaryLevel(c.SummaryLevel)
+		if err != nil {
+			return err
+		}
+		c.SummaryLevel = string(level)
+	}
+	if c.Compression != "" {
+		codec, err := compress.ParseCodec(c.Compression)
+		if err != nil {
+			return err
+		}
+		c.Compression = string(codec)
+	}
+	if c.Denomination != "" {
+		denom, err := units.ParseDenomination(c.Denomination)
+		if err != nil {
+			return err
+		}
+		c.Denomination = string(denom)
+	}
+	if !validRegion(c.Region) {
+		return fmt.Errorf("invalid region %q: use letters, digits, '.', '_' and '-' only", c.Region)
+	}
+	if c.AlertFailureRate < 0 || c.AlertFailureRate >= 100 {
+		return fmt.Errorf("alert-failure-rate must be at least 0 and below 100")
+	}
+	if c.AlertFailureRate > 0 && c.AlertWindow < time.Second {
+		return fmt.Errorf("alert-window must be at least 1s")
+	}
+	if c.MaxConcurrent < 0 || c.ConfirmConcurrency < 0 {
+		return fmt.Errorf("max-concurrent and confirm-concurrency must not be negative")
+	}
+	if c.CaptureFailures < 0 {
+		return fmt.Errorf("capture-failures must not be negative")
+	}
+	if c.Burst < 0 {
+		return fmt.Errorf("burst must not be negative")
+	}
+	if c.FundRetries < 0 {
+		return fmt.Errorf("fund-retries must not be negative")
+	}
+	if c.PriceBump < 0 {
+		return fmt.Errorf("price-bump must not be negative")
+	}
+	if len(c.Workloads) > 0 {
+		if _, err := mix.ParseAll(c.Workloads); err != nil {
+			return err
+		}
+		if c.CaptureFailures > 0 {
+			return fmt.Errorf("workload cannot be combined with capture-failures")
+		}
+	}
+	if c.Decimals < 0 || c.Decimals > 18 {
+		return fmt.Errorf("decimals must be between 0 and 18")
+	}
+	if c.MaxSpend != "" {
+		limit, err := units.ParseAmount(c.MaxSpend)
+		if err != nil {
+			return fmt.Errorf("invalid max-spend: %w", err)
+		}
+		c.MaxSpend = limit.String()
+	}
+	if err := c.validateFeeBounds(); err != nil {
+		return err
+	}
+	for _, signature := range c.ExpectEvents {
+		if signature == noEvents {
+			if len(c.ExpectEvents) > 1 {
+				return fmt.Errorf("expect-event none cannot be combined with event signatures")
+			}
+			continue
+		}
+		if strings.Index(signature, "(") < 1 || !strings.HasSuffix(signature, ")") {
+			return fmt.Errorf("invalid expect-event %q: want a signature such as Transfer(address,address,uint256)", signature)
+		}
+	}
+	for _, fund := range []struct {
+		name  string
+		field *string
+	}{
+		{"fund-amount", &c.FundAmount},
+		{"fund-extra", &c.FundExtra},
+	} {
+		if *fund.field == "" {
+			continue
+		}
+		amount, err := units.ParseAmount(*fund.field)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", fund.name, err)
+		}
+		*fund.field = amount.String()
+	}
+	if c.CollectDuringSend && c.SkipCollection {
+		return fmt.Errorf("collect-during-send cannot be combined with skip-collection")
+	}
+	if c.SubscribeReceipts && c.SkipCollection {
+		return fmt.Errorf("subscribe-receipts cannot be combined with skip-collection")
+	}
+	if c.SaveTxs != "" && c.ReplayTxs != "" {
+		return fmt.Errorf("save-txs cannot be combined with replay-txs")
+	}
+	if c.ReplayTxs != "" && len(c.Workloads) > 0 {
+		return fmt.Errorf("replay-txs cannot be combined with workload")
+	}
+	if c.MempoolDiff && c.DryRun {
+		return fmt.Errorf("mempool-diff cannot be combined with dry-run")
+	}
+	if c.P2PCompare && c.P2PEnode == "" {
+		return fmt.Errorf("p2p-compare requires p2p-enode")
+	}
+	if c.P2PEnode != "" && c.MaxSpend != "" {
+		return fmt.Errorf("p2p-enode cannot be combined with max-spend")
+	}
+	if c.P2PEnode != "" && c.StopAtBlock > 0 {
+		return fmt.Errorf("p2p-enode cannot be combined with stop-at-block")
+	}
+	if c.MaxRuntime < 0 {
+		return fmt.Errorf("max-runtime must not be negative")
+	}
+	if c.SendDeadline < 0 {
+		return fmt.Errorf("send-deadline must not be negative")
+	}
+	if c.PeakWindow < 0 {
+		return fmt.Errorf("peak-window must not be negative")
+	}
+	if c.HeatmapInterval < 0 {
+		return fmt.Errorf("heatmap-interval must not be negative")
+	}
+	if c.ConnectionsPerWorker < 0 {
+		return fmt.Errorf("connections-per-worker must not be negative")
+	}
+	if c.Redundancy < 0 {
+		return fmt.Errorf("redundancy must not be negative")
+	}
+	if c.Redundancy > 1 && c.ConnectionsPerWorker > 0 {
+		return fmt.Errorf("redundancy cannot be combined with connections-per-worker")
+	}
+	if c.Balance != "" {
+		if c.Balance != client.BalanceRoundRobin && c.Balance != client.BalanceLeastLatency {
+			return fmt.Errorf("invalid balance %q: must be %s or %s", c.Balance, client.BalanceRoundRobin, client.BalanceLeastLatency)
+		}
+		if c.Redundancy > 1 {
+			return fmt.Errorf("balance cannot be combined with redundancy")
+		}
+		if c.ConnectionsPerWorker > 0 {
+			return fmt.Errorf("balance cannot be combined with connections-per-worker")
+		}
+	}
+	if c.QuarantineAfter < 0 {
+		return fmt.Errorf("quarantine-after must not be negative")
+	}
+	if c.TxsPerBlock < 0 {
+		return fmt.Errorf("per-block must not be negative")
+	}
+	if c.TxsPerBlock > 0 && c.StreamingMode {
+		return fmt.Errorf("per-block cannot be combined with streaming")
+	}
+	if c.TxsPerBlock > 0 && c.P2PEnode != "" && !c.P2PCompare {
+		return fmt.Errorf("per-block requires JSON-RPC sending and cannot be combined with p2p-enode alone")
+	}
+	if c.Prewarm && c.PrewarmCalls <= 0 {
+		return fmt.Errorf("prewarm-calls must be positive")
+	}
+	if c.MemoryCap < 0 {
+		return fmt.Errorf("collector-memory-cap must not be negative")
+	}
+	if c.ZeroFee && (c.MinTip != "" || c.MinFeeCap != "") {
+		return fmt.Errorf("zero-fee cannot be combined with min-tip or min-fee-cap")
+	}
+	if c.PoisonRate < 0 || c.PoisonRate > 100 {
+		return fmt.Errorf("poison-rate must be between 0 and 100")
+	}
+	return nil
+}
+
+// validateFeeBounds normalizes the tip and fee cap bounds to wei and checks
+// that each floor is not above its ceiling
+func (c *RunConfig) validateFeeBounds() error {
+	for _, bound := range []struct {
+		name     string
+		min, max *string
+	}{
+		{"tip", &c.MinTip, &c.MaxTip},
+		{"fee-cap", &c.MinFeeCap, &c.MaxFeeCap},
+	} {
+		var limits [2]*big.Int
+		for i, field := range []*string{bound.min, bound.max} {
+			if *field == "" {
+				continue
+			}
+			amount, err := units.ParseAmount(*field)
+			if err != nil {
+				return fmt.Errorf("invalid %s bound: %w", bound.name, err)
+			}
+			*field = amount.String()
+			limits[i] = amount
+		}
+		if limits[0] != nil && limits[1] != nil && limits[0].Cmp(limits[1]) > 0 {
+			return fmt.Errorf("min-%s must not be greater than max-%s", bound.name, bound.name)
+		}
+	}
+	return nil
+}
+
+// FeeBounds returns the validated tip and fee cap bounds
+func (c *RunConfig) FeeBounds() txbuilder.FeeBounds {
+	return txbuilder.FeeBounds{
+		MinTipCap: parseWei(c.MinTip),
+		MaxTipCap: parseWei(c.MaxTip),
+		MinFeeCap: parseWei(c.MinFeeCap),
+		MaxFeeCap: parseWei(c.MaxFeeCap),
+	}
+}
+
+// FundOverrides returns the validated funding target and headroom (nil if unset)
+func (c *RunConfig) FundOverrides() (amount, extra *big.Int) {
+	return parseWei(c.FundAmount), parseWei(c.FundExtra)
+}
+
+// parseWei parses a validated amount in wei ("" = nil)
+func parseWei(s string) *big.Int {
+	if s == "" {
+		return nil
+	}
+	v, _ := new(big.Int).SetString(s, 10)
+	return v
+}
+
 // Result represents the complete pipeline execution result
 type Result struct {
 	// Execution info
@@ -101,13 +708,17 @@ type Result struct {
 	SuccessfulTxs     int
 	FailedTxs         int
 	TimeoutTxs        int
+	EvictedTxs        int
 
 	// Performance metrics
-	TPS          float64
-	ConfirmedTPS float64
-	AvgLatency   time.Duration
-	P95Latency   time.Duration
-	P99Latency   time.Duration
+	ChainTPS              float64
+	PeakTPS               float64
+	PeakWindow            time.Duration
+	WallClockTPS          float64
+	WallClockConfirmedTPS float64
+	AvgLatency            time.Duration
+	P95Latency            time.Duration
+	P99Latency            time.Duration
 
 	// Gas metrics
 	TotalGasUsed uint64
@@ -116,6 +727,39 @@ type Result struct {
 	// Detailed report
 	Report *collector.Report
 
+	// Per-stage structured metrics
+	Stages *StageMetrics
+
+	// Chain halt windows observed during the run
+	Halts []watchdog.Halt
+
+	// Point at which the spend budget stopped sending (nil if never reached)
+	BudgetStop *budget.Stop
+
+	// Point at which the chain reached --stop-at-block (nil if never reached)
+	BlockStop *blockstop.Stop
+
+	// Accounts the long sender took out of the rotation after repeated failures
+	Quarantined []longsender.Quarantine
+
+	// Periods in which the send failure rate exceeded --alert-failure-rate
+	Alerts []alert.Alert
+
+	// Endpoints that throttled requests during the run
+	Throttling []client.EndpointThrottle
+
+	// Effective rate limiter of a streaming or long sender run
+	RateLimit *RateLimit
+
+	// The run hit --max-runtime; the report covers only what finished before it
+	Partial bool
+
+	// Label of the region or worker that ran the test ("" = none)
+	Region string
+
+	// Parameters and hash of the workload the run sent
+	Workload *collector.JSONWorkload
+
 	// Errors encountered
 	Errors []error
 }
@@ -125,6 +769,7 @@ func NewResult() *Result {
 	return &Result{
 		StartTime:    time.Now(),
 		StageResults: make([]*StageResult, 0),
+		Stages:       &StageMetrics{},
 		Errors:       make([]error, 0),
 	}
 }
@@ -137,6 +782,45 @@ func (r *Result) AddStageResult(sr *StageResult) {
 	}
 }
 
+// ApplyReport copies the summary and performance metrics from a collector report
+func (r *Result) ApplyReport(report *collector.Report) {
+	r.Report = report
+	if report == nil || report.Metrics == nil {
+		return
+	}
+	m := report.Metrics
+	r.TotalTransactions = m.TotalSent
+	r.SuccessfulTxs = m.TotalConfirmed
+	r.FailedTxs = m.TotalFailed
+	r.TimeoutTxs = m.TotalTimeout
+	r.EvictedTxs = m.TotalEvicted
+	r.ChainTPS = m.ChainTPS
+	r.PeakTPS = m.PeakTPS
+	r.PeakWindow = m.PeakWindow
+	r.WallClockTPS = m.WallClockTPS
+	r.WallClockConfirmedTPS = m.WallClockConfirmedTPS
+	r.AvgLatency = m.AvgLatency
+	r.P95Latency = m.P95Latency
+	r.P99Latency = m.P99Latency
+	r.TotalGasUsed = m.TotalGasUsed
+	if m.TotalGasCost != nil {
+		r.TotalGasCost = m.TotalGasCost.String()
+	}
+}
+
+// validRegion reports whether region is usable as a Prometheus label value
+// and Pushgateway grouping key without escaping
+func validRegion(region string) bool {
+	for _, r := range region {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // Finalize completes the result
 func (r *Result) Finalize() {
 	r.EndTime = time.Now()
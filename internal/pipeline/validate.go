@@ -0,0 +1,53 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// invalidTxExamples is the number of invalid txs listed in the console output
+const invalidTxExamples = 5
+
+// validateTxs reports duplicate hashes, nonce conflicts and chain ID
+// mismatches in the built txs before they are sent, and drops the
+// offenders with --prune-invalid
+func (p *Pipeline) validateTxs() {
+	issues := txbuilder.ValidateTxs(p.signedTxs, p.chainID)
+	if len(issues) == 0 {
+		return
+	}
+
+	counts := make(map[txbuilder.IssueKind]int)
+	for _, issue := range issues {
+		counts[issue.Kind]++
+	}
+	var parts []string
+	for _, kind := range txbuilder.IssueKinds {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	console.Warnf("%d of %d built txs are invalid (%s)\n", len(issues), len(p.signedTxs), strings.Join(parts, ", "))
+	for _, issue := range issues[:min(len(issues), invalidTxExamples)] {
+		console.Summaryf("  tx #%d %s: %s\n", issue.Index, issue.Tx.Hash.Hex(), issue.Detail)
+	}
+	if len(issues) > invalidTxExamples {
+		console.Summaryf("  ... %d more\n", len(issues)-invalidTxExamples)
+	}
+
+	if p.stages.Build != nil {
+		p.stages.Build.TxsInvalid = len(issues)
+	}
+	if !p.runCfg.PruneInvalid {
+		console.Summaryf("  Sending them anyway; use --prune-invalid to drop them\n")
+		return
+	}
+	p.signedTxs = txbuilder.PruneTxs(p.signedTxs, issues)
+	if p.stages.Build != nil {
+		p.stages.Build.TxsPruned = len(issues)
+	}
+	console.Printf("  Pruned %d invalid txs, %d left to send\n", len(issues), len(p.signedTxs))
+}
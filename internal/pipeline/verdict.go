@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Verdict returns the one-line outcome of the run printed last, such as
+// "PASS: 1,000,000 tx, 842 TPS confirmed, 99.97% success, p95 1.8s"
+func (r *Result) Verdict() string {
+	return r.verdict(nil)
+}
+
+// verdict returns the verdict of a run that Execute ended with runErr
+func (r *Result) verdict(runErr error) string {
+	status := "PASS"
+	switch {
+	case r.Partial:
+		status = "PARTIAL"
+	case runErr != nil || !r.Success():
+		status = "FAIL"
+	}
+
+	if r.Report == nil || r.Report.Metrics == nil {
+		sent := 0
+		if r.Stages != nil && r.Stages.Send != nil {
+			sent = r.Stages.Send.TxsSent
+		}
+		return fmt.Sprintf("%s: %s tx sent, receipts not collected", status, groupDigits(sent))
+	}
+
+	tps := r.ChainTPS
+	if tps == 0 {
+		tps = r.WallClockConfirmedTPS
+	}
+	verdict := fmt.Sprintf("%s: %s tx, %s TPS confirmed, %.2f%% success", status,
+		groupDigits(r.TotalTransactions), formatTPS(tps), r.Report.Metrics.SuccessRate)
+	if r.SuccessfulTxs > 0 {
+		verdict += ", p95 " + roundLatency(r.P95Latency).String()
+	}
+	return verdict
+}
+
+// groupDigits formats n with a comma between each group of three digits
+func groupDigits(n int) string {
+	s := strconv.Itoa(n)
+	start := 0
+	if n < 0 {
+		start = 1
+	}
+	for i := len(s) - 3; i > start; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatTPS formats a rate with one decimal below 100 tx/s and none above
+func formatTPS(tps float64) string {
+	if tps >= 100 {
+		return strconv.FormatFloat(tps, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(tps, 'f', 1, 64)
+}
+
+// roundLatency rounds a latency to tenths of a second from one second up and
+// to milliseconds below
+func roundLatency(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(100 * time.Millisecond)
+	}
+	return d.Round(time.Millisecond)
+}
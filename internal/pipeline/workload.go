@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0xmhha/txhammer/internal/collector"
+	"github.com/0xmhha/txhammer/internal/config"
+	schema "github.com/0xmhha/txhammer/pkg/report"
+)
+
+// workload describes the transactions of the run by the flags that define
+// them: what is built, how many, with which gas and fee settings, and at what
+// pace. Accounts, endpoints and the chain are left out, so the same workload
+// run elsewhere has the same hash. txhammer draws no random values, so the
+// flags fully determine the transactions.
+func (p *Pipeline) workload() *collector.JSONWorkload {
+	params := make(map[string]string)
+	put := func(name string, value any) {
+		// Unset flags are left out, as WorkloadHash does with empty values
+		switch s := fmt.Sprint(value); s {
+		case "", "0", "false", "0s":
+		default:
+			params[name] = s
+		}
+	}
+
+	mode := config.Mode(strings.ToUpper(p.cfg.Mode))
+	put("mode", mode)
+	put("workload", strings.Join(p.runCfg.Workloads, ","))
+	put("transactions", p.cfg.Transactions)
+	put("sub-accounts", p.cfg.SubAccounts)
+	put("batch", p.cfg.BatchSize)
+	put("rate-limit", p.cfg.RateLimit)
+	put("streaming", p.runCfg.StreamingMode)
+	if p.runCfg.StreamingMode {
+		put("streaming-rate", p.runCfg.StreamingRate)
+	}
+	put("burst", p.runCfg.Burst)
+	put("per-block", p.runCfg.TxsPerBlock)
+	put("poison-rate", p.runCfg.PoisonRate)
+
+	put("gas-limit", p.cfg.GasLimit)
+	put("gas-price", p.cfg.GasPrice)
+	put("tx-type", p.cfg.TxType)
+	put("min-tip", p.runCfg.MinTip)
+	put("max-tip", p.runCfg.MaxTip)
+	put("min-fee-cap", p.runCfg.MinFeeCap)
+	put("max-fee-cap", p.runCfg.MaxFeeCap)
+	put("zero-fee", p.runCfg.ZeroFee)
+
+	put("value", p.cfg.Value)
+	put("recipient", strings.ToLower(p.cfg.Recipient))
+	put("recipient-strategy", p.cfg.RecipientStrategy)
+	put("contract", strings.ToLower(p.cfg.Contract))
+	put("method", p.cfg.Method)
+	put("args", p.cfg.Args)
+
+	switch mode {
+	case config.ModeLongSender, config.ModeTargetUtilization:
+		put("duration", p.cfg.Duration)
+		put("tps", p.cfg.TargetTPS)
+		put("workers", p.cfg.Workers)
+		if mode == config.ModeTargetUtilization {
+			put("target-utilization", p.cfg.TargetUtilization)
+		}
+	case config.ModeAccountGrowth:
+		put("duration", p.cfg.Duration)
+		put("growth-rate", p.cfg.GrowthRate)
+		put("growth-acceleration", p.cfg.GrowthAcceleration)
+		put("growth-txs", p.cfg.GrowthTxs)
+	case config.ModeERC721Mint:
+		put("nft-name", p.cfg.NFTName)
+		put("nft-symbol", p.cfg.NFTSymbol)
+		put("token-uri", p.cfg.TokenURI)
+	case config.ModeConflict:
+		put("conflict-variants", p.cfg.ConflictVariants)
+	case config.ModeCreate2Churn:
+		put("churn-count", p.cfg.ChurnCount)
+	case config.ModeDeployThenCall:
+		put("deploy-count", p.cfg.DeployCount)
+	case config.ModeSwap:
+		put("swap-path", strings.ToLower(strings.Join(p.cfg.SwapPath, ",")))
+		put("swap-amount", p.cfg.SwapAmount)
+	}
+
+	return schema.NewWorkload(params)
+}
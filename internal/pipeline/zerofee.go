@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// detectZeroFee decides whether the run sends its transactions with a zero
+// gas price. --zero-fee and --gas-price 0 ask for it; otherwise it applies
+// when the node suggests a zero gas price and blocks carry no base fee, as on
+// private deployments that charge no fees at all.
+func (p *Pipeline) detectZeroFee(ctx context.Context) error {
+	p.zeroFee = p.runCfg.ZeroFee
+	if p.cfg.GasPrice != "" {
+		price, ok := new(big.Int).SetString(p.cfg.GasPrice, 10)
+		if ok && price.Sign() == 0 {
+			p.zeroFee = true
+		} else if p.zeroFee {
+			return fmt.Errorf("--zero-fee conflicts with --gas-price %s", p.cfg.GasPrice)
+		}
+	} else if !p.zeroFee && p.runCfg.MinTip == "" && p.runCfg.MinFeeCap == "" {
+		p.zeroFee = p.chainChargesNoFees(ctx)
+	}
+
+	if p.zeroFee {
+		console.Printf("\nZero-fee network: transactions are sent with a gas price of 0\n")
+	}
+	return nil
+}
+
+// chainChargesNoFees reports whether the node suggests a zero gas price and
+// the latest block has a zero or no base fee. Errors count as fees charged.
+func (p *Pipeline) chainChargesNoFees(ctx context.Context) bool {
+	price, err := p.client.SuggestGasPrice(ctx)
+	if err != nil || price.Sign() != 0 {
+		return false
+	}
+	header, err := p.client.HeaderByNumber(ctx, nil)
+	if err != nil || header == nil {
+		return false
+	}
+	return header.BaseFee == nil || header.BaseFee.Sign() == 0
+}
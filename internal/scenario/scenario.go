@@ -0,0 +1,180 @@
+package scenario
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Scenario is a sequence of phases run one after the other
+type Scenario struct {
+	Name   string  `yaml:"name"`
+	Phases []Phase `yaml:"phases"`
+}
+
+// Phase is one workload of a scenario, sent at a fixed rate for a duration
+// or a number of transactions. The optional settings replace the flags of
+// the same name for the phase.
+type Phase struct {
+	Name         string        `yaml:"name"` // "" = its number and mode, such as 2-erc20_transfer
+	Mode         string        `yaml:"mode"`
+	TPS          float64       `yaml:"tps"`
+	Duration     time.Duration `yaml:"duration"`     // Send time; sets the transactions to TPS × Duration
+	Transactions uint64        `yaml:"transactions"` // Alternative to Duration
+
+	Contract string `yaml:"contract"`
+	Method   string `yaml:"method"`
+	Args     string `yaml:"args"`
+	Value    string `yaml:"value"`
+	GasLimit uint64 `yaml:"gas-limit"`
+	GasPrice string `yaml:"gas-price"`
+}
+
+// Load reads and validates a YAML scenario file
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("scenario %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Parse parses and validates a YAML scenario. Unknown keys are an error.
+func Parse(data []byte) (*Scenario, error) {
+	var s Scenario
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, err
+	}
+	for i := range s.Phases {
+		s.Phases[i].Mode = strings.ToUpper(strings.TrimSpace(s.Phases[i].Mode))
+		if s.Phases[i].Name == "" {
+			s.Phases[i].Name = fmt.Sprintf("%d-%s", i+1, strings.ToLower(s.Phases[i].Mode))
+		}
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate validates the scenario. The modes are checked when it runs.
+func (s *Scenario) Validate() error {
+	if len(s.Phases) == 0 {
+		return fmt.Errorf("no phases")
+	}
+	names := make(map[string]bool, len(s.Phases))
+	for _, p := range s.Phases {
+		if names[p.Name] {
+			return fmt.Errorf("phase %s: name used twice", p.Name)
+		}
+		names[p.Name] = true
+
+		if strings.ContainsAny(p.Name, `/\`) {
+			return fmt.Errorf("phase %s: name must not contain a path separator", p.Name)
+		}
+		if p.Mode == "" {
+			return fmt.Errorf("phase %s: mode is required", p.Name)
+		}
+		if p.TPS <= 0 {
+			return fmt.Errorf("phase %s: tps must be greater than 0", p.Name)
+		}
+		if (p.Duration > 0) == (p.Transactions > 0) {
+			return fmt.Errorf("phase %s: set either duration or transactions", p.Name)
+		}
+		if p.Duration < 0 {
+			return fmt.Errorf("phase %s: duration must not be negative", p.Name)
+		}
+		if p.Count() == 0 {
+			return fmt.Errorf("phase %s: duration is too short to send a transaction at %.1f tx/s", p.Name, p.TPS)
+		}
+	}
+	return nil
+}
+
+// Count returns the transactions the phase sends
+func (p Phase) Count() uint64 {
+	if p.Transactions > 0 {
+		return p.Transactions
+	}
+	return uint64(math.Round(p.TPS * p.Duration.Seconds()))
+}
+
+// Total returns the transactions of every phase together
+func (s *Scenario) Total() uint64 {
+	var total uint64
+	for _, p := range s.Phases {
+		total += p.Count()
+	}
+	return total
+}
+
+// Row is the outcome of one phase in the combined table
+type Row struct {
+	Name         string
+	Mode         string
+	TargetTPS    float64
+	Sent         int
+	Confirmed    int
+	Failed       int
+	ConfirmedTPS float64 // Confirmed transactions per wall-clock second
+	AvgLatency   time.Duration
+	P95Latency   time.Duration
+	Duration     time.Duration // Wall-clock time of the phase
+	Err          error         // Set if the phase did not complete
+	Skipped      bool          // Not run because an earlier phase failed
+}
+
+// PrintTable prints one row per phase and the totals of the whole scenario
+func PrintTable(name string, rows []Row) {
+	title := "Scenario"
+	if name != "" {
+		title += ": " + name
+	}
+	console.Summaryf("\n%s\n\n", title)
+	console.Summaryf("  %-22s %-20s %10s %9s %10s %8s %14s %12s %12s %10s\n",
+		"Phase", "Mode", "Target TPS", "Sent", "Confirmed", "Failed", "Confirmed TPS", "Avg Latency", "P95 Latency", "Duration")
+
+	var total Row
+	var latencySum time.Duration
+	for _, r := range rows {
+		if r.Skipped {
+			console.Summaryf("  %-22s %-20s %10.1f %9s\n", r.Name, r.Mode, r.TargetTPS, "skipped")
+			continue
+		}
+		console.Summaryf("  %-22s %-20s %10.1f %9d %10d %8d %14.2f %12s %12s %10s\n",
+			r.Name, r.Mode, r.TargetTPS, r.Sent, r.Confirmed, r.Failed, r.ConfirmedTPS,
+			r.AvgLatency.Round(time.Millisecond), r.P95Latency.Round(time.Millisecond), r.Duration.Round(time.Second))
+		if r.Err != nil {
+			console.Failf("  %-22s %s\n", "", r.Err)
+		}
+		total.Sent += r.Sent
+		total.Confirmed += r.Confirmed
+		total.Failed += r.Failed
+		total.Duration += r.Duration
+		latencySum += r.AvgLatency * time.Duration(r.Confirmed)
+	}
+	if total.Confirmed > 0 {
+		total.AvgLatency = latencySum / time.Duration(total.Confirmed)
+	}
+	if total.Duration > 0 {
+		total.ConfirmedTPS = float64(total.Confirmed) / total.Duration.Seconds()
+	}
+
+	// Percentiles of the parts do not combine into one of the whole
+	console.Summaryf("  %-22s %-20s %10s %9d %10d %8d %14.2f %12s %12s %10s\n",
+		"Total", "", "-", total.Sent, total.Confirmed, total.Failed, total.ConfirmedTPS,
+		total.AvgLatency.Round(time.Millisecond), "-", total.Duration.Round(time.Second))
+}
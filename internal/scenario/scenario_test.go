@@ -0,0 +1,55 @@
+package scenario
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(`
+name: ramp
+phases:
+  - mode: transfer
+    tps: 200
+    duration: 5m
+  - name: tokens
+    mode: ERC20_TRANSFER
+    tps: 500
+    transactions: 1000
+    contract: "0x1234567890123456789012345678901234567890"
+`))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if s.Name != "ramp" || len(s.Phases) != 2 {
+		t.Fatalf("Parse() = %+v", s)
+	}
+	first, second := s.Phases[0], s.Phases[1]
+	if first.Name != "1-transfer" || first.Mode != "TRANSFER" || first.Duration != 5*time.Minute || first.Count() != 60000 {
+		t.Errorf("first phase = %+v, count %d", first, first.Count())
+	}
+	if second.Name != "tokens" || second.Count() != 1000 || second.Contract == "" {
+		t.Errorf("second phase = %+v", second)
+	}
+	if got := s.Total(); got != 61000 {
+		t.Errorf("Total() = %d, want 61000", got)
+	}
+
+	invalid := map[string]string{
+		"no phases":    "name: empty\n",
+		"unknown key":  "phases:\n  - mode: TRANSFER\n    tps: 1\n    duration: 1m\n    rate: 5\n",
+		"no mode":      "phases:\n  - tps: 1\n    duration: 1m\n",
+		"no rate":      "phases:\n  - mode: TRANSFER\n    duration: 1m\n",
+		"no length":    "phases:\n  - mode: TRANSFER\n    tps: 1\n",
+		"both lengths": "phases:\n  - mode: TRANSFER\n    tps: 1\n    duration: 1m\n    transactions: 10\n",
+		"too short":    "phases:\n  - mode: TRANSFER\n    tps: 1\n    duration: 100ms\n",
+		"same names":   "phases:\n  - {name: a, mode: TRANSFER, tps: 1, transactions: 1}\n  - {name: a, mode: TRANSFER, tps: 1, transactions: 1}\n",
+		"path in name": "phases:\n  - {name: a/b, mode: TRANSFER, tps: 1, transactions: 1}\n",
+		"bad duration": "phases:\n  - mode: TRANSFER\n    tps: 1\n    duration: soon\n",
+	}
+	for name, data := range invalid {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: Parse() should fail", name)
+		}
+	}
+}
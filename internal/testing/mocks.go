@@ -39,6 +39,9 @@ type MockClient struct {
 	// Receipts storage
 	Receipts map[common.Hash]*types.Receipt
 
+	// Transactions known to the mock mempool
+	PendingTxs map[common.Hash]*types.Transaction
+
 	// Sent transactions tracking
 	SentTransactions []*types.Transaction
 	SentRawTxs       [][]byte
@@ -59,6 +62,7 @@ func NewMockClient() *MockClient {
 		EstimateGasValue:   21000,
 		BlockGasLimitValue: 30000000,
 		Receipts:           make(map[common.Hash]*types.Receipt),
+		PendingTxs:         make(map[common.Hash]*types.Transaction),
 		SentTransactions:   make([]*types.Transaction, 0),
 		SentRawTxs:         make([][]byte, 0),
 		CallCounts:         make(map[string]int),
@@ -182,6 +186,20 @@ func (m *MockClient) TransactionReceipt(_ context.Context, txHash common.Hash) (
 	return receipt, nil
 }
 
+// TransactionByHash returns a pending transaction from the mock mempool
+func (m *MockClient) TransactionByHash(_ context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	m.incrementCallCount("TransactionByHash")
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if tx, ok := m.PendingTxs[txHash]; ok {
+		return tx, true, nil
+	}
+	if _, ok := m.Receipts[txHash]; ok {
+		return types.NewTx(&types.LegacyTx{}), false, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
 // HeaderByNumber returns a mock header
 func (m *MockClient) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
 	m.incrementCallCount("HeaderByNumber")
@@ -0,0 +1,155 @@
+// Package trace records the timing of a run as Chrome trace-event JSON,
+// which Perfetto (ui.perfetto.dev) and chrome://tracing open as a timeline.
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Event is a trace event in the Chrome trace-event format. Timestamps and
+// durations are in microseconds since the start of the recording.
+type Event struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  int64          `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// File is the JSON object written to a trace file
+type File struct {
+	TraceEvents     []Event `json:"traceEvents"`
+	DisplayTimeUnit string  `json:"displayTimeUnit"`
+}
+
+// pid is the process id of all events; a run is a single process
+const pid = 1
+
+// Recorder collects complete events ("X") on named tracks. A nil Recorder
+// records nothing, so callers need not check whether tracing is enabled.
+type Recorder struct {
+	start  time.Time
+	mu     sync.Mutex
+	events []Event
+	tracks map[int]string
+}
+
+// New starts a recording at the current time
+func New() *Recorder {
+	return &Recorder{
+		start:  time.Now(),
+		tracks: make(map[int]string),
+	}
+}
+
+// Track names the track with id tid
+func (r *Recorder) Track(tid int, name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.tracks[tid] = name
+	r.mu.Unlock()
+}
+
+// Span records a complete event of duration dur starting at start on track tid
+func (r *Recorder) Span(tid int, cat, name string, start time.Time, dur time.Duration, args map[string]any) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.events = append(r.events, Event{
+		Name: name,
+		Cat:  cat,
+		Ph:   "X",
+		Ts:   start.Sub(r.start).Microseconds(),
+		Dur:  max(dur.Microseconds(), 1),
+		Pid:  pid,
+		Tid:  tid,
+		Args: args,
+	})
+	r.mu.Unlock()
+}
+
+// Events returns the track name metadata followed by the recorded events in
+// start order
+func (r *Recorder) Events() []Event {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tids := make([]int, 0, len(r.tracks))
+	for tid := range r.tracks {
+		tids = append(tids, tid)
+	}
+	sort.Ints(tids)
+
+	events := make([]Event, 0, 2*len(tids)+len(r.events))
+	for _, tid := range tids {
+		events = append(events,
+			Event{Name: "thread_name", Ph: "M", Pid: pid, Tid: tid, Args: map[string]any{"name": r.tracks[tid]}},
+			Event{Name: "thread_sort_index", Ph: "M", Pid: pid, Tid: tid, Args: map[string]any{"sort_index": tid}},
+		)
+	}
+	spans := append([]Event(nil), r.events...)
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Ts < spans[j].Ts })
+	return append(events, spans...)
+}
+
+// WriteFile writes the recording to path
+func (r *Recorder) WriteFile(path string) error {
+	data, err := json.Marshal(File{TraceEvents: r.Events(), DisplayTimeUnit: "ms"})
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create trace directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write trace: %w", err)
+	}
+	return nil
+}
+
+// Lanes assigns each interval the lowest lane not taken by an overlapping
+// earlier interval, so concurrent spans are drawn on separate tracks. It
+// returns the lane of each interval and the number of lanes used.
+func Lanes(starts []time.Time, durs []time.Duration) ([]int, int) {
+	order := make([]int, len(starts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return starts[order[a]].Before(starts[order[b]]) })
+
+	lanes := make([]int, len(starts))
+	var ends []time.Time // End of the last interval in each lane
+	for _, i := range order {
+		lane := -1
+		for l, end := range ends {
+			if !starts[i].Before(end) {
+				lane = l
+				break
+			}
+		}
+		if lane < 0 {
+			lane = len(ends)
+			ends = append(ends, time.Time{})
+		}
+		ends[lane] = starts[i].Add(durs[i])
+		lanes[i] = lane
+	}
+	return lanes, len(ends)
+}
@@ -0,0 +1,76 @@
+package trace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecorder_WriteFile(t *testing.T) {
+	r := New()
+	r.Track(1, "stages")
+	start := r.start.Add(2 * time.Millisecond)
+	r.Span(1, "stage", "SEND", start, 5*time.Millisecond, map[string]any{"success": true})
+	r.Span(1, "stage", "BUILD", r.start, time.Millisecond, nil)
+
+	path := filepath.Join(t.TempDir(), "out", "trace.json")
+	if err := r.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("trace is not valid JSON: %v", err)
+	}
+
+	events := file.TraceEvents
+	if len(events) != 4 {
+		t.Fatalf("events = %d, want 2 metadata + 2 spans", len(events))
+	}
+	if events[0].Ph != "M" || events[0].Args["name"] != "stages" {
+		t.Errorf("first event = %+v, want thread_name metadata", events[0])
+	}
+	build, send := events[2], events[3]
+	if build.Name != "BUILD" || send.Name != "SEND" {
+		t.Fatalf("spans = %s, %s, want BUILD, SEND in start order", build.Name, send.Name)
+	}
+	if send.Ph != "X" || send.Ts != 2000 || send.Dur != 5000 || send.Tid != 1 {
+		t.Errorf("SEND = %+v, want complete event at 2000us for 5000us", send)
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var r *Recorder
+	r.Track(1, "stages")
+	r.Span(1, "stage", "SEND", time.Now(), time.Second, nil)
+	if events := r.Events(); events != nil {
+		t.Errorf("Events() = %v, want nil", events)
+	}
+}
+
+func TestLanes(t *testing.T) {
+	base := time.Now()
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+	// 0: [0,10) 1: [5,15) 2: [10,20) 3: [12,14)
+	starts := []time.Time{at(0), at(5), at(10), at(12)}
+	durs := []time.Duration{ms(10), ms(10), ms(10), ms(2)}
+
+	lanes, n := Lanes(starts, durs)
+	want := []int{0, 1, 0, 2}
+	if n != 3 {
+		t.Errorf("lanes used = %d, want 3", n)
+	}
+	for i := range want {
+		if lanes[i] != want[i] {
+			t.Errorf("lane[%d] = %d, want %d", i, lanes[i], want[i])
+		}
+	}
+}
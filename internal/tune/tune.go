@@ -0,0 +1,148 @@
+package tune
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Call is one request the probe times; it returns an error only if the node
+// did not serve the request
+type Call func(ctx context.Context) error
+
+// Config holds configuration for the concurrency probe
+type Config struct {
+	MaxConcurrency int           // Highest concurrency tried; levels double from 1 up to it
+	LevelDuration  time.Duration // How long each level runs
+	MaxErrorRate   float64       // Error rate (percent) above which a level fails
+	MinGain        float64       // Smallest throughput gain (percent) that still counts as scaling
+	LatencyFactor  float64       // Median latency growth over the first level at which a level fails
+}
+
+// DefaultConfig returns default probe configuration
+func DefaultConfig() *Config {
+	return &Config{
+		MaxConcurrency: 256,
+		LevelDuration:  500 * time.Millisecond,
+		MaxErrorRate:   1,
+		MinGain:        10,
+		LatencyFactor:  4,
+	}
+}
+
+// Level is the outcome of one concurrency level
+type Level struct {
+	Concurrency int           `json:"concurrency"`
+	Calls       int           `json:"calls"`
+	Errors      int           `json:"errors"`
+	Throughput  float64       `json:"throughput"` // Calls per second
+	P50         time.Duration `json:"p50"`
+	P95         time.Duration `json:"p95"`
+}
+
+// ErrorRate returns the percentage of calls that failed
+func (l *Level) ErrorRate() float64 {
+	if l.Calls == 0 {
+		return 0
+	}
+	return float64(l.Errors) / float64(l.Calls) * 100
+}
+
+// Result is the outcome of a probe
+type Result struct {
+	Levels []*Level `json:"levels"`
+	Best   int      `json:"best"`             // Concurrency past which the node stopped scaling (0 = none worked)
+	Reason string   `json:"reason,omitempty"` // Why the probe stopped before MaxConcurrency
+}
+
+// Probe finds the concurrency at which the node stops serving more calls per
+// second. It runs back-to-back calls at doubling concurrency and stops at the
+// first level that errs too often, slows the median call down too much, or
+// gains too little throughput over the best level so far.
+func Probe(ctx context.Context, call Call, cfg *Config) *Result {
+	result := &Result{}
+	var best, first *Level
+	for c := 1; c <= max(cfg.MaxConcurrency, 1); c *= 2 {
+		if ctx.Err() != nil {
+			result.Reason = "interrupted"
+			break
+		}
+		level := runLevel(ctx, call, c, cfg.LevelDuration)
+		result.Levels = append(result.Levels, level)
+		if first == nil {
+			first = level
+		}
+
+		if reason := judge(level, best, first, cfg); reason != "" {
+			result.Reason = reason
+			break
+		}
+		best = level
+	}
+	if best != nil {
+		result.Best = best.Concurrency
+	}
+	return result
+}
+
+// judge returns why level does not improve on best, or "" if it does
+func judge(level, best, first *Level, cfg *Config) string {
+	switch {
+	case level.Calls == 0 || level.Errors == level.Calls:
+		return "calls failed"
+	case level.ErrorRate() > cfg.MaxErrorRate:
+		return "error rate above limit"
+	case level != first && cfg.LatencyFactor > 0 && float64(level.P50) > cfg.LatencyFactor*float64(first.P50):
+		return "latency rose"
+	case best != nil && level.Throughput < best.Throughput*(1+cfg.MinGain/100):
+		return "throughput stopped scaling"
+	}
+	return ""
+}
+
+// runLevel makes back-to-back calls from concurrency workers for duration
+func runLevel(ctx context.Context, call Call, concurrency int, duration time.Duration) *Level {
+	levelCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		latencies []time.Duration
+		errs      int
+	)
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for levelCtx.Err() == nil {
+				callStart := time.Now()
+				err := call(levelCtx)
+				elapsed := time.Since(callStart)
+				if levelCtx.Err() != nil {
+					return // Cut off by the end of the level, not by the node
+				}
+				mu.Lock()
+				latencies = append(latencies, elapsed)
+				if err != nil {
+					errs++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	level := &Level{Concurrency: concurrency, Calls: len(latencies), Errors: errs}
+	if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+		level.Throughput = float64(level.Calls-level.Errors) / elapsed
+	}
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		level.P50 = latencies[len(latencies)/2]
+		level.P95 = latencies[len(latencies)*95/100]
+	}
+	return level
+}
@@ -0,0 +1,57 @@
+package tune
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// limitedNode serves calls in 1ms and refuses calls beyond limit in flight
+type limitedNode struct {
+	limit    int64
+	inFlight atomic.Int64
+}
+
+func (n *limitedNode) call(context.Context) error {
+	defer n.inFlight.Add(-1)
+	if n.inFlight.Add(1) > n.limit {
+		return errors.New("too many requests")
+	}
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func TestProbe(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LevelDuration = 100 * time.Millisecond
+	result := Probe(context.Background(), (&limitedNode{limit: 2}).call, cfg)
+	if result.Best != 2 || result.Reason != "error rate above limit" {
+		t.Errorf("Probe() best = %d (%s), want 2 stopped by errors", result.Best, result.Reason)
+	}
+	if len(result.Levels) != 3 {
+		t.Errorf("Probe() ran %d levels, want 3", len(result.Levels))
+	}
+}
+
+func TestJudge(t *testing.T) {
+	cfg := DefaultConfig()
+	first := &Level{Concurrency: 1, Calls: 100, Throughput: 100, P50: 10 * time.Millisecond}
+	tests := []struct {
+		name  string
+		level *Level
+		want  string
+	}{
+		{"scales", &Level{Calls: 200, Throughput: 200, P50: 10 * time.Millisecond}, ""},
+		{"all failed", &Level{Calls: 5, Errors: 5}, "calls failed"},
+		{"errors", &Level{Calls: 200, Errors: 10, Throughput: 190, P50: 10 * time.Millisecond}, "error rate above limit"},
+		{"slow", &Level{Calls: 200, Throughput: 200, P50: 50 * time.Millisecond}, "latency rose"},
+		{"flat", &Level{Calls: 105, Throughput: 105, P50: 10 * time.Millisecond}, "throughput stopped scaling"},
+	}
+	for _, tt := range tests {
+		if got := judge(tt.level, first, first, cfg); got != tt.want {
+			t.Errorf("%s: judge() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package txbuilder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/progress"
+)
+
+// ApproveTransferFromBuilder builds the two phases of APPROVE_TRANSFERFROM.
+// Accounts form a ring: each account approves the next one as its spender,
+// which then pulls tokens from it with transferFrom() to itself. Every
+// account gives and receives the same amount, so token balances end where
+// they started.
+type ApproveTransferFromBuilder struct {
+	*BaseBuilder
+	tokenAddr common.Address
+	amount    *big.Int
+}
+
+// NewApproveTransferFromBuilder creates a new approve + transferFrom builder
+func NewApproveTransferFromBuilder(config *BuilderConfig, estimator GasEstimator, tokenAddr common.Address) *ApproveTransferFromBuilder {
+	return &ApproveTransferFromBuilder{
+		BaseBuilder: NewBaseBuilder(config, estimator),
+		tokenAddr:   tokenAddr,
+		amount:      big.NewInt(1), // Default 1 token unit
+	}
+}
+
+// WithAmount sets the amount each transferFrom() moves
+func (b *ApproveTransferFromBuilder) WithAmount(amount *big.Int) *ApproveTransferFromBuilder {
+	b.amount = amount
+	return b
+}
+
+// Name returns the builder name
+func (b *ApproveTransferFromBuilder) Name() string {
+	return "APPROVE_TRANSFERFROM"
+}
+
+// EstimateGas estimates gas for one transferFrom(), which also writes the allowance
+func (b *ApproveTransferFromBuilder) EstimateGas(_ context.Context) (uint64, error) {
+	return 80000, nil
+}
+
+// GetApproveTransactions returns one approve() per signer, allowing the next
+// signer in the ring to spend exactly what its share of count transferFrom()
+// calls will pull. Signers with no transferFrom() pulling from them approve
+// nothing and send no transaction.
+func (b *ApproveTransferFromBuilder) GetApproveTransactions(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if err := b.checkInputs(signers, nonces); err != nil {
+		return nil, err
+	}
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := DistributeTransactions(len(signers), count)
+	approveTxs := make([]*SignedTx, 0, len(signers))
+	for i, signer := range signers {
+		spenderIdx := (i + 1) % len(signers)
+		if distribution[spenderIdx] == 0 {
+			continue
+		}
+		allowance := new(big.Int).Mul(b.amount, big.NewInt(int64(distribution[spenderIdx])))
+		data := buildERC20ApproveData(signers[spenderIdx].Address(), allowance)
+
+		signedTx, err := b.sign(signer, nonces[i], gasTipCap, gasFeeCap, data)
+		if err != nil {
+			return nil, err
+		}
+		approveTxs = append(approveTxs, signedTx)
+	}
+	return approveTxs, nil
+}
+
+// Build creates the transferFrom() calls, each pulling the amount from the
+// previous signer in the ring to the calling signer
+func (b *ApproveTransferFromBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if err := b.checkInputs(signers, nonces); err != nil {
+		return nil, err
+	}
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := DistributeTransactions(len(signers), count)
+	totalTxs := 0
+	for _, n := range distribution {
+		totalTxs += n
+	}
+
+	console.Printf("\nBuilding ERC20 transferFrom Transactions\n\n")
+	console.Printf("Token: %s\n", b.tokenAddr.Hex())
+	bar := progress.New(int64(totalTxs), "txs built")
+
+	signedTxs := make([]*SignedTx, 0, totalTxs)
+	for accountIdx, txCount := range distribution {
+		signer := signers[accountIdx]
+		owner := signers[(accountIdx+len(signers)-1)%len(signers)].Address()
+		data := buildERC20TransferFromData(owner, signer.Address(), b.amount)
+
+		nonce := nonces[accountIdx]
+		for i := 0; i < txCount; i++ {
+			signedTx, err := b.sign(signer, nonce, gasTipCap, gasFeeCap, data)
+			if err != nil {
+				return nil, err
+			}
+			signedTxs = append(signedTxs, signedTx)
+			nonce++
+			progress.Add(bar, 1)
+		}
+	}
+
+	console.OKf("\nSuccessfully built %d ERC20 transferFrom transactions\n", len(signedTxs))
+	return signedTxs, nil
+}
+
+// checkInputs validates the signers, nonces and token address
+func (b *ApproveTransferFromBuilder) checkInputs(signers []Signer, nonces []uint64) error {
+	if len(signers) == 0 {
+		return fmt.Errorf("no signers provided")
+	}
+	if len(signers) != len(nonces) {
+		return fmt.Errorf("signers and nonces length mismatch")
+	}
+	if b.tokenAddr == (common.Address{}) {
+		return fmt.Errorf("token address is required")
+	}
+	return nil
+}
+
+// sign builds and signs one call to the token
+func (b *ApproveTransferFromBuilder) sign(signer Signer, nonce uint64, gasTipCap, gasFeeCap *big.Int, data []byte) (*SignedTx, error) {
+	gasLimit := b.config.GasLimit
+	if gasLimit == 0 {
+		gasLimit = 80000
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   b.config.ChainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       gasLimit,
+		To:        &b.tokenAddr,
+		Value:     big.NewInt(0),
+		Data:      data,
+	})
+	signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	rawTx, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+	return &SignedTx{
+		Tx:       signedTx,
+		RawTx:    rawTx,
+		Hash:     signedTx.Hash(),
+		From:     signer.Address(),
+		Nonce:    nonce,
+		GasLimit: gasLimit,
+	}, nil
+}
+
+// buildERC20ApproveData builds the calldata for ERC20 approve(address,uint256)
+func buildERC20ApproveData(spender common.Address, amount *big.Int) []byte {
+	data := buildERC20TransferData(spender, amount)
+	copy(data[0:4], ERC20ApproveSelector)
+	return data
+}
+
+// buildERC20TransferFromData builds the calldata for ERC20 transferFrom(address,address,uint256)
+func buildERC20TransferFromData(from, to common.Address, amount *big.Int) []byte {
+	data := make([]byte, 4+32+32+32)
+	copy(data[0:4], ERC20TransferFromSelector)
+	copy(data[4+12:4+32], from.Bytes())
+	copy(data[4+32+12:4+64], to.Bytes())
+	amountBytes := amount.Bytes()
+	copy(data[4+64+(32-len(amountBytes)):4+96], amountBytes)
+	return data
+}
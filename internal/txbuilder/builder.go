@@ -3,18 +3,19 @@ package txbuilder
 import (
 	"context"
 	"crypto/ecdsa"
-	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/config"
 )
 
 // Builder interface defines the contract for transaction builders
 type Builder interface {
 	// Build creates transactions for the given accounts
-	Build(ctx context.Context, keys []*ecdsa.PrivateKey, nonces []uint64, count int) ([]*SignedTx, error)
+	Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error)
 	// EstimateGas estimates gas for a single transaction
 	EstimateGas(ctx context.Context) (uint64, error)
 	// Name returns the builder name
@@ -41,28 +42,26 @@ func NewBaseBuilder(config *BuilderConfig, estimator GasEstimator) *BaseBuilder
 	}
 }
 
-// GetGasSettings returns gas settings, fetching from network if not configured
+// GetGasSettings returns gas settings, fetching from network if not configured.
+// Both are then held within the configured fee bounds.
 func (b *BaseBuilder) GetGasSettings(ctx context.Context) (gasTipCap, gasFeeCap *big.Int, err error) {
 	gasTipCap = b.config.GasTipCap
 	gasFeeCap = b.config.GasFeeCap
 
 	if gasTipCap == nil && b.estimator != nil {
-		tip, err := b.estimator.SuggestGasTipCap(ctx)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
-		}
-		gasTipCap = tip
+		gasTipCap = b.suggestTipCap(ctx)
 	}
 
 	if gasFeeCap == nil && b.estimator != nil {
-		price, err := b.estimator.SuggestGasPrice(ctx)
+		gasFeeCap, err = b.suggestFeeCap(ctx, gasTipCap)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to suggest gas price: %w", err)
+			return nil, nil, err
 		}
-		// gasFeeCap = baseFee + gasTipCap (approximate with 2x suggested price)
-		gasFeeCap = new(big.Int).Mul(price, big.NewInt(2))
 	}
 
+	gasTipCap = clampFee("Tip", gasTipCap, b.config.Fees.MinTipCap, b.config.Fees.MaxTipCap)
+	gasFeeCap = clampFee("Fee cap", gasFeeCap, b.config.Fees.MinFeeCap, b.config.Fees.MaxFeeCap)
+
 	// Ensure gasTipCap is not greater than gasFeeCap
 	if gasTipCap != nil && gasFeeCap != nil && gasTipCap.Cmp(gasFeeCap) > 0 {
 		gasTipCap = gasFeeCap
@@ -71,10 +70,48 @@ func (b *BaseBuilder) GetGasSettings(ctx context.Context) (gasTipCap, gasFeeCap
 	return gasTipCap, gasFeeCap, nil
 }
 
-// SignTransaction signs a transaction with the given private key
-func SignTransaction(tx *types.Transaction, chainID *big.Int, key *ecdsa.PrivateKey) (*types.Transaction, error) {
-	signer := types.NewLondonSigner(chainID)
-	return types.SignTx(tx, signer, key)
+// newTx returns the unsigned transaction of req in the configured --tx-type,
+// or in fallback when none is configured. Legacy and access-list
+// transactions pay the fee cap as their gas price, and the access list is
+// left empty so the gas limit of the mode still covers it.
+func (b *BaseBuilder) newTx(fallback config.TxType, req *TxRequest) *types.Transaction {
+	txType := b.config.TxType
+	if txType == "" {
+		txType = fallback
+	}
+	switch txType {
+	case config.TxTypeLegacy:
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    req.Nonce,
+			GasPrice: req.GasFeeCap,
+			Gas:      req.Gas,
+			To:       req.To,
+			Value:    req.Value,
+			Data:     req.Data,
+		})
+	case config.TxTypeEIP2930:
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    b.config.ChainID,
+			Nonce:      req.Nonce,
+			GasPrice:   req.GasFeeCap,
+			Gas:        req.Gas,
+			To:         req.To,
+			Value:      req.Value,
+			Data:       req.Data,
+			AccessList: types.AccessList{},
+		})
+	default:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   b.config.ChainID,
+			Nonce:     req.Nonce,
+			GasTipCap: req.GasTipCap,
+			GasFeeCap: req.GasFeeCap,
+			Gas:       req.Gas,
+			To:        req.To,
+			Value:     req.Value,
+			Data:      req.Data,
+		})
+	}
 }
 
 // AddressFromKey returns the address for a private key
@@ -1,12 +1,18 @@
 package txbuilder
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/0xmhha/txhammer/internal/config"
@@ -171,7 +177,7 @@ func TestSignTransaction(t *testing.T) {
 	builder := NewTransferBuilder(cfg, nil)
 	addr := crypto.PubkeyToAddress(key.PublicKey)
 
-	signedTx, err := builder.BuildSingle(context.Background(), key, 0, addr, big.NewInt(1))
+	signedTx, err := builder.BuildSingle(context.Background(), NewKeySigner(key), 0, addr, big.NewInt(1))
 	if err != nil {
 		t.Fatalf("BuildSingle() failed: %v", err)
 	}
@@ -235,7 +241,7 @@ func TestTransferBuilder_Build_NoKeys(t *testing.T) {
 
 	_, err := builder.Build(context.Background(), nil, nil, 10)
 	if err == nil {
-		t.Error("Build() expected error for no keys")
+		t.Error("Build() expected error for no signers")
 	}
 }
 
@@ -248,10 +254,10 @@ func TestTransferBuilder_Build_MismatchedLengths(t *testing.T) {
 	}
 	builder := NewTransferBuilder(cfg, nil)
 
-	keys := []*ecdsa.PrivateKey{newTestKey()}
+	signers := []Signer{NewKeySigner(newTestKey())}
 	nonces := []uint64{0, 1} // Mismatched length
 
-	_, err := builder.Build(context.Background(), keys, nonces, 10)
+	_, err := builder.Build(context.Background(), signers, nonces, 10)
 	if err == nil {
 		t.Error("Build() expected error for mismatched lengths")
 	}
@@ -270,10 +276,10 @@ func TestTransferBuilder_Build_WithGasEstimator(t *testing.T) {
 
 	builder := NewTransferBuilder(cfg, estimator)
 
-	keys := []*ecdsa.PrivateKey{newTestKey()}
+	signers := []Signer{NewKeySigner(newTestKey())}
 	nonces := []uint64{0}
 
-	txs, err := builder.Build(context.Background(), keys, nonces, 5)
+	txs, err := builder.Build(context.Background(), signers, nonces, 5)
 	if err != nil {
 		t.Fatalf("Build() error: %v", err)
 	}
@@ -298,7 +304,7 @@ func TestTransferBuilder_Build_WithGasEstimator(t *testing.T) {
 
 func TestFeeDelegationBuilder_Name(t *testing.T) {
 	cfg := &BuilderConfig{ChainID: big.NewInt(1)}
-	builder := NewFeeDelegationBuilder(cfg, nil, newFeePayerKey())
+	builder := NewFeeDelegationBuilder(cfg, nil, NewKeySigner(newFeePayerKey()))
 
 	if name := builder.Name(); name != "FEE_DELEGATION" {
 		t.Errorf("Name() = %s, want FEE_DELEGATION", name)
@@ -307,7 +313,7 @@ func TestFeeDelegationBuilder_Name(t *testing.T) {
 
 func TestFeeDelegationBuilder_EstimateGas(t *testing.T) {
 	cfg := &BuilderConfig{ChainID: big.NewInt(1)}
-	builder := NewFeeDelegationBuilder(cfg, nil, newFeePayerKey())
+	builder := NewFeeDelegationBuilder(cfg, nil, NewKeySigner(newFeePayerKey()))
 
 	gas, err := builder.EstimateGas(context.Background())
 	if err != nil {
@@ -328,10 +334,10 @@ func TestFeeDelegationBuilder_Build_NoFeePayerKey(t *testing.T) {
 	}
 	builder := NewFeeDelegationBuilder(cfg, nil, nil)
 
-	keys := []*ecdsa.PrivateKey{newTestKey()}
+	signers := []Signer{NewKeySigner(newTestKey())}
 	nonces := []uint64{0}
 
-	_, err := builder.Build(context.Background(), keys, nonces, 10)
+	_, err := builder.Build(context.Background(), signers, nonces, 10)
 	if err == nil {
 		t.Error("Build() expected error for no fee payer key")
 	}
@@ -345,12 +351,12 @@ func TestFeeDelegationBuilder_Build(t *testing.T) {
 		GasFeeCap: big.NewInt(1000000000),
 	}
 	feePayerKey := newFeePayerKey()
-	builder := NewFeeDelegationBuilder(cfg, nil, feePayerKey)
+	builder := NewFeeDelegationBuilder(cfg, nil, NewKeySigner(feePayerKey))
 
-	keys := []*ecdsa.PrivateKey{newTestKey()}
+	signers := []Signer{NewKeySigner(newTestKey())}
 	nonces := []uint64{0}
 
-	txs, err := builder.Build(context.Background(), keys, nonces, 3)
+	txs, err := builder.Build(context.Background(), signers, nonces, 3)
 	if err != nil {
 		t.Fatalf("Build() error: %v", err)
 	}
@@ -416,7 +422,7 @@ func TestFactory_CreateBuilder_FeeDelegation(t *testing.T) {
 	}
 
 	// With fee payer key - should succeed
-	builder, err := factory.CreateBuilder(config.ModeFeeDelegation, WithFeePayerKey(newFeePayerKey()))
+	builder, err := factory.CreateBuilder(config.ModeFeeDelegation, WithFeePayer(NewKeySigner(newFeePayerKey())))
 	if err != nil {
 		t.Fatalf("CreateBuilder() error: %v", err)
 	}
@@ -426,6 +432,118 @@ func TestFactory_CreateBuilder_FeeDelegation(t *testing.T) {
 	}
 }
 
+func TestContractCallBuilder_Value(t *testing.T) {
+	signers := []Signer{NewKeySigner(newTestKey())}
+	contract := common.HexToAddress(testContractAddr)
+
+	builder := NewContractCallBuilder(&BuilderConfig{ChainID: big.NewInt(1)}, &mockGasEstimator{}, contract).WithMethod("ping()")
+	txs, err := builder.Build(context.Background(), signers, []uint64{0}, 1)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if txs[0].Tx.Value().Sign() != 0 {
+		t.Errorf("default call value = %s, want 0", txs[0].Tx.Value())
+	}
+
+	builder = NewContractCallBuilder(&BuilderConfig{ChainID: big.NewInt(1), Value: big.NewInt(500)}, &mockGasEstimator{}, contract).WithMethod("ping()")
+	txs, err = builder.Build(context.Background(), signers, []uint64{0}, 1)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if txs[0].Tx.Value().Int64() != 500 {
+		t.Errorf("call value = %s, want 500", txs[0].Tx.Value())
+	}
+}
+
+func TestBuilders_TxType(t *testing.T) {
+	signers := []Signer{NewKeySigner(newTestKey())}
+	contract := common.HexToAddress(testContractAddr)
+	token := common.HexToAddress(testTokenAddr)
+
+	builders := map[string]func(*BuilderConfig) Builder{
+		"transfer": func(cfg *BuilderConfig) Builder { return NewTransferBuilder(cfg, &mockGasEstimator{}) },
+		"call": func(cfg *BuilderConfig) Builder {
+			return NewContractCallBuilder(cfg, &mockGasEstimator{}, contract).WithMethod("ping()")
+		},
+		"erc20": func(cfg *BuilderConfig) Builder { return NewERC20TransferBuilder(cfg, &mockGasEstimator{}, token) },
+	}
+	defaults := map[string]uint8{"transfer": types.LegacyTxType, "call": types.DynamicFeeTxType, "erc20": types.DynamicFeeTxType}
+	want := map[config.TxType]uint8{
+		config.TxTypeLegacy:  types.LegacyTxType,
+		config.TxTypeEIP2930: types.AccessListTxType,
+		config.TxTypeEIP1559: types.DynamicFeeTxType,
+	}
+
+	for name, newBuilder := range builders {
+		for _, txType := range append([]config.TxType{""}, config.TxTypes()...) {
+			builder := newBuilder(&BuilderConfig{ChainID: big.NewInt(1), TxType: txType})
+			txs, err := builder.Build(context.Background(), signers, []uint64{0}, 1)
+			if err != nil {
+				t.Fatalf("%s %q Build() error = %v", name, txType, err)
+			}
+			wantType, ok := want[txType]
+			if !ok {
+				wantType = defaults[name]
+			}
+			tx := txs[0].Tx
+			if tx.Type() != wantType {
+				t.Errorf("%s %q type = %d, want %d", name, txType, tx.Type(), wantType)
+			}
+			if tx.GasFeeCap().Sign() <= 0 {
+				t.Errorf("%s %q fee cap = %s, want the suggested fee", name, txType, tx.GasFeeCap())
+			}
+			if from, err := types.Sender(types.NewLondonSigner(big.NewInt(1)), tx); err != nil || from != txs[0].From {
+				t.Errorf("%s %q sender = %s, %v, want %s", name, txType, from.Hex(), err, txs[0].From.Hex())
+			}
+		}
+	}
+}
+
+func TestRecipients(t *testing.T) {
+	a, b, c := common.HexToAddress("0xa"), common.HexToAddress("0xb"), common.HexToAddress("0xc")
+	fixed := common.HexToAddress(testContractAddr)
+	senders := []common.Address{a, b, c}
+
+	tests := []struct {
+		strategy config.RecipientStrategy
+		fixed    common.Address
+		want     []common.Address
+	}{
+		{"", common.Address{}, []common.Address{a, b, c}},
+		{"", fixed, []common.Address{fixed, fixed, fixed}},
+		{config.RecipientSelf, common.Address{}, []common.Address{a, b, c}},
+		{config.RecipientFixed, fixed, []common.Address{fixed, fixed, fixed}},
+		{config.RecipientRing, common.Address{}, []common.Address{b, c, a}},
+	}
+	for _, tt := range tests {
+		got := Recipients(tt.strategy, senders, tt.fixed)
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Recipients(%q)[%d] = %s, want %s", tt.strategy, i, got[i].Hex(), tt.want[i].Hex())
+			}
+		}
+	}
+}
+
+func TestTransferBuilder_Build_RecipientRing(t *testing.T) {
+	signers := KeySigners([]*ecdsa.PrivateKey{newTestKey(), newFeePayerKey()})
+	builder := NewTransferBuilder(&BuilderConfig{ChainID: big.NewInt(1)}, &mockGasEstimator{}).WithRecipientStrategy(config.RecipientRing)
+
+	txs, err := builder.Build(context.Background(), signers, []uint64{0, 0}, 2)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for _, tx := range txs {
+		want := signers[0].Address()
+		if tx.From == want {
+			want = signers[1].Address()
+		}
+		if *tx.Tx.To() != want {
+			t.Errorf("tx from %s sent to %s, want %s", tx.From.Hex(), tx.Tx.To().Hex(), want.Hex())
+		}
+	}
+}
+
 func TestFactory_CreateBuilder_ContractCall_RequiresAddress(t *testing.T) {
 	cfg := &BuilderConfig{
 		ChainID:  big.NewInt(1001),
@@ -570,6 +688,91 @@ func TestBaseBuilder_GetGasSettings(t *testing.T) {
 	}
 }
 
+// headerGasEstimator adds a latest header with a base fee to mockGasEstimator
+type headerGasEstimator struct {
+	mockGasEstimator
+	baseFee *big.Int
+}
+
+func (m *headerGasEstimator) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: m.baseFee}, nil
+}
+
+func TestBaseBuilder_GetGasSettings_Fallbacks(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1000000000)) }
+
+	tests := []struct {
+		name      string
+		estimator GasEstimator
+		fees      FeeBounds
+		wantTip   *big.Int
+		wantCap   *big.Int
+	}{
+		{
+			name:      "zero tip falls back to gas price minus base fee",
+			estimator: &headerGasEstimator{mockGasEstimator{gasPrice: gwei(5), gasTipCap: big.NewInt(0)}, gwei(3)},
+			wantTip:   gwei(2),
+			wantCap:   gwei(10),
+		},
+		{
+			name:      "zero tip without header uses the tip floor",
+			estimator: &mockGasEstimator{gasPrice: gwei(5), gasTipCap: big.NewInt(0)},
+			fees:      FeeBounds{MinTipCap: gwei(2)},
+			wantTip:   gwei(2),
+			wantCap:   gwei(10),
+		},
+		{
+			name:      "zero tip without header or floor uses 1 gwei",
+			estimator: &mockGasEstimator{gasPrice: gwei(5), gasTipCap: big.NewInt(0)},
+			wantTip:   gwei(1),
+			wantCap:   gwei(10),
+		},
+		{
+			name:      "zero gas price falls back to base fee",
+			estimator: &headerGasEstimator{mockGasEstimator{gasPrice: big.NewInt(0), gasTipCap: gwei(1)}, gwei(3)},
+			wantTip:   gwei(1),
+			wantCap:   gwei(7),
+		},
+		{
+			name:      "absurd suggestions are clamped",
+			estimator: &mockGasEstimator{gasPrice: gwei(1000), gasTipCap: gwei(500)},
+			fees:      FeeBounds{MaxTipCap: gwei(3), MaxFeeCap: gwei(50)},
+			wantTip:   gwei(3),
+			wantCap:   gwei(50),
+		},
+		{
+			name:      "low suggestions are raised to the floors",
+			estimator: &mockGasEstimator{gasPrice: big.NewInt(10), gasTipCap: big.NewInt(1)},
+			fees:      FeeBounds{MinTipCap: gwei(1), MinFeeCap: gwei(20)},
+			wantTip:   gwei(1),
+			wantCap:   gwei(20),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewBaseBuilder(&BuilderConfig{ChainID: big.NewInt(1), Fees: tt.fees}, tt.estimator)
+			tip, feeCap, err := builder.GetGasSettings(context.Background())
+			if err != nil {
+				t.Fatalf("GetGasSettings() error = %v", err)
+			}
+			if tip.Cmp(tt.wantTip) != 0 {
+				t.Errorf("tip = %s, want %s", tip, tt.wantTip)
+			}
+			if feeCap.Cmp(tt.wantCap) != 0 {
+				t.Errorf("fee cap = %s, want %s", feeCap, tt.wantCap)
+			}
+		})
+	}
+}
+
+func TestBaseBuilder_GetGasSettings_NoFeeCap(t *testing.T) {
+	builder := NewBaseBuilder(&BuilderConfig{ChainID: big.NewInt(1)}, &mockGasEstimator{err: errors.New("node down")})
+	if _, _, err := builder.GetGasSettings(context.Background()); err == nil {
+		t.Error("GetGasSettings() expected error when no fee cap source works")
+	}
+}
+
 func TestBuilderOptions(t *testing.T) {
 	// Test all builder options
 	key := newTestKey()
@@ -583,7 +786,7 @@ func TestBuilderOptions(t *testing.T) {
 		option BuilderOption
 	}{
 		{"WithRecipient", WithRecipient(addr)},
-		{"WithFeePayerKey", WithFeePayerKey(key)},
+		{"WithFeePayer", WithFeePayer(NewKeySigner(key))},
 		{"WithContractAddress", WithContractAddress(addr)},
 		{"WithTokenAddress", WithTokenAddress(tokenAddr)},
 		{"WithBytecode", WithBytecode(bytecode)},
@@ -622,3 +825,565 @@ func TestPrefixedRlpHash(t *testing.T) {
 		t.Error("Different prefix should produce different hash")
 	}
 }
+
+func TestBuildPoison(t *testing.T) {
+	chainID := big.NewInt(1337)
+	poison, err := BuildPoison(chainID, big.NewInt(1), 6)
+	if err != nil {
+		t.Fatalf("BuildPoison() error = %v", err)
+	}
+	if len(poison) != 6 {
+		t.Fatalf("expected 6 poison txs, got %d", len(poison))
+	}
+
+	signer := types.NewLondonSigner(chainID)
+	for i, tx := range poison {
+		if tx.Class != PoisonClasses[i%len(PoisonClasses)] {
+			t.Errorf("tx %d class = %s, want %s", i, tx.Class, PoisonClasses[i%len(PoisonClasses)])
+		}
+		decoded := new(types.Transaction)
+		if err := decoded.UnmarshalBinary(tx.RawTx); err != nil {
+			t.Fatalf("tx %d does not decode: %v", i, err)
+		}
+
+		_, senderErr := types.Sender(signer, decoded)
+		switch tx.Class {
+		case PoisonBadSignature:
+			if senderErr == nil {
+				t.Errorf("tx %d: expected unrecoverable sender", i)
+			}
+		case PoisonInsufficientBalance:
+			if senderErr != nil || decoded.Gas() != 21000 {
+				t.Errorf("tx %d: expected valid 21000 gas transfer, err = %v", i, senderErr)
+			}
+		case PoisonIntrinsicGas:
+			if decoded.Gas() >= 21000 {
+				t.Errorf("tx %d: gas %d is not below intrinsic gas", i, decoded.Gas())
+			}
+		}
+	}
+}
+
+func TestCreate2ChurnFactory_Execution(t *testing.T) {
+	cfg := &runtime.Config{GasLimit: 10_000_000}
+	_, factory, _, err := runtime.Create(common.FromHex(Create2ChurnFactoryBytecode), cfg)
+	if err != nil {
+		t.Fatalf("deploy factory: %v", err)
+	}
+
+	const count = 5
+	_, leftOver, err := runtime.Call(factory, ChurnCallData(big.NewInt(1), count), cfg)
+	if err != nil {
+		t.Fatalf("churn call: %v", err)
+	}
+	if used := cfg.GasLimit - leftOver; used > count*churnGasPerContract {
+		t.Errorf("churn used %d gas, above the %d estimate", used, count*churnGasPerContract)
+	}
+
+	receipt := &types.Receipt{Logs: cfg.State.Logs()}
+	created, destroyed := ChurnCounts(receipt)
+	if created != count || destroyed != count {
+		t.Errorf("ChurnCounts() = %d created, %d destroyed, want %d each", created, destroyed, count)
+	}
+
+	// Children self-destruct in the transaction that created them; the
+	// child init code is the last 40 bytes of the factory bytecode
+	code := common.FromHex(Create2ChurnFactoryBytecode)
+	child := crypto.CreateAddress2(factory, common.BigToHash(big.NewInt(1)), crypto.Keccak256(code[len(code)-40:]))
+	if !cfg.State.HasSelfDestructed(child) {
+		t.Errorf("child %s was not self-destructed", child.Hex())
+	}
+
+	// A wrong selector reverts
+	if _, _, err := runtime.Call(factory, []byte{1, 2, 3, 4}, cfg); err == nil {
+		t.Error("expected revert for unknown selector")
+	}
+}
+
+func TestSimpleStorageBytecode_Deploys(t *testing.T) {
+	code, err := hex.DecodeString(SimpleStorageBytecode)
+	if err != nil {
+		t.Fatalf("decode bytecode: %v", err)
+	}
+
+	cfg := &runtime.Config{GasLimit: 10_000_000}
+	deployed, addr, _, err := runtime.Create(code, cfg)
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	if len(deployed) == 0 {
+		t.Fatal("deployment left no code")
+	}
+
+	set := append(common.FromHex("0x60fe47b1"), common.LeftPadBytes(big.NewInt(42).Bytes(), 32)...)
+	if _, _, err := runtime.Call(addr, set, cfg); err != nil {
+		t.Fatalf("set(42): %v", err)
+	}
+	ret, _, err := runtime.Call(addr, common.FromHex("0x6d4ce63c"), cfg)
+	if err != nil {
+		t.Fatalf("get(): %v", err)
+	}
+	if got := new(big.Int).SetBytes(ret); got.Int64() != 42 {
+		t.Errorf("get() = %s, want 42", got)
+	}
+}
+
+func TestCreate2ChurnBuilder_Build(t *testing.T) {
+	builder := NewCreate2ChurnBuilder(&BuilderConfig{ChainID: big.NewInt(1)}, &mockGasEstimator{}).WithChurnCount(3)
+
+	if _, err := builder.Build(context.Background(), []Signer{NewKeySigner(newTestKey())}, []uint64{0}, 2); err == nil {
+		t.Fatal("expected error without a factory address")
+	}
+
+	factory := common.HexToAddress(testContractAddr)
+	builder.WithFactory(factory)
+	txs, err := builder.Build(context.Background(), []Signer{NewKeySigner(newTestKey())}, []uint64{7}, 2)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("built %d txs, want 2", len(txs))
+	}
+	wantGas, _ := builder.EstimateGas(context.Background())
+	if txs[0].GasLimit != wantGas {
+		t.Errorf("GasLimit = %d, want %d", txs[0].GasLimit, wantGas)
+	}
+	if *txs[0].Tx.To() != factory {
+		t.Errorf("To = %s, want factory", txs[0].Tx.To().Hex())
+	}
+	// Consecutive nonces get disjoint salt ranges
+	salt0 := new(big.Int).SetBytes(txs[0].Tx.Data()[4:36])
+	salt1 := new(big.Int).SetBytes(txs[1].Tx.Data()[4:36])
+	if diff := new(big.Int).Sub(salt1, salt0); diff.Int64() != 3 {
+		t.Errorf("salt step = %s, want 3", diff)
+	}
+}
+
+func TestDeployThenCallBuilder_Build(t *testing.T) {
+	builder := NewDeployThenCallBuilder(&BuilderConfig{ChainID: big.NewInt(1)}, &mockGasEstimator{})
+	key := newTestKey()
+
+	if _, err := builder.Build(context.Background(), []Signer{NewKeySigner(key)}, []uint64{0}, 3); err == nil {
+		t.Fatal("expected error without deployed contracts")
+	}
+
+	deployTxs, err := builder.GetDeployTransactions(context.Background(), NewKeySigner(key), 4, 2)
+	if err != nil {
+		t.Fatalf("GetDeployTransactions() error = %v", err)
+	}
+	if len(deployTxs) != 2 || deployTxs[0].Nonce != 4 || deployTxs[1].Nonce != 5 || deployTxs[0].Tx.To() != nil {
+		t.Fatalf("unexpected deployments: %+v", deployTxs)
+	}
+
+	contracts := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+	builder.WithContracts(contracts)
+	txs, err := builder.Build(context.Background(), []Signer{NewKeySigner(key)}, []uint64{6}, 3)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for i, tx := range txs {
+		if want := contracts[i%2]; *tx.Tx.To() != want {
+			t.Errorf("tx %d: To = %s, want %s", i, tx.Tx.To().Hex(), want.Hex())
+		}
+	}
+
+	// Default calls run set(uint256) with a distinct value against a deployed SimpleStorage
+	cfg := &runtime.Config{GasLimit: 10_000_000}
+	_, storage, _, err := runtime.Create(deployTxs[0].Tx.Data(), cfg)
+	if err != nil {
+		t.Fatalf("deploy SimpleStorage: %v", err)
+	}
+	for _, tx := range txs {
+		if _, _, err := runtime.Call(storage, tx.Tx.Data(), cfg); err != nil {
+			t.Fatalf("set call: %v", err)
+		}
+	}
+	if got := cfg.State.GetState(storage, common.Hash{}); got != common.BigToHash(big.NewInt(3)) {
+		t.Errorf("stored value = %s, want 3", got.Hex())
+	}
+
+	builder.WithMethod("get()")
+	txs, err = builder.Build(context.Background(), []Signer{NewKeySigner(key)}, []uint64{9}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := txs[0].Tx.Data(); len(data) != 4 {
+		t.Errorf("custom method calldata = %x, want selector only", data)
+	}
+}
+
+func TestApproveTransferFromBuilder_Build(t *testing.T) {
+	token := common.HexToAddress(testTokenAddr)
+	builder := NewApproveTransferFromBuilder(&BuilderConfig{ChainID: big.NewInt(1)}, &mockGasEstimator{}, token)
+	keys := []*ecdsa.PrivateKey{newTestKey(), newFeePayerKey()}
+	third, _ := crypto.GenerateKey()
+	signers := KeySigners(append(keys, third))
+	nonces := []uint64{0, 3, 7}
+
+	// 5 calls over 3 signers pull 2, 2 and 1 times
+	approvals, err := builder.GetApproveTransactions(context.Background(), signers, nonces, 5)
+	if err != nil {
+		t.Fatalf("GetApproveTransactions() error = %v", err)
+	}
+	if len(approvals) != 3 {
+		t.Fatalf("approvals = %d, want 3", len(approvals))
+	}
+	wantAllowance := []int64{2, 1, 2}
+	for i, tx := range approvals {
+		data := tx.Tx.Data()
+		spender := common.BytesToAddress(data[4:36])
+		if tx.From != signers[i].Address() || tx.Nonce != nonces[i] || *tx.Tx.To() != token {
+			t.Errorf("approval %d: from %s nonce %d", i, tx.From.Hex(), tx.Nonce)
+		}
+		if !bytes.Equal(data[:4], ERC20ApproveSelector) || spender != signers[(i+1)%3].Address() {
+			t.Errorf("approval %d approves %s, want the next signer", i, spender.Hex())
+		}
+		if got := new(big.Int).SetBytes(data[36:68]); got.Int64() != wantAllowance[i] {
+			t.Errorf("approval %d allowance = %s, want %d", i, got, wantAllowance[i])
+		}
+	}
+
+	txs, err := builder.Build(context.Background(), signers, []uint64{1, 4, 8}, 5)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(txs) != 5 {
+		t.Fatalf("Build() = %d txs, want 5", len(txs))
+	}
+	for _, tx := range txs {
+		data := tx.Tx.Data()
+		from, to := common.BytesToAddress(data[4:36]), common.BytesToAddress(data[36:68])
+		if !bytes.Equal(data[:4], ERC20TransferFromSelector) || to != tx.From {
+			t.Errorf("tx from %s pulls to %s, want itself", tx.From.Hex(), to.Hex())
+		}
+		for i, signer := range signers {
+			if signer.Address() == tx.From && from != signers[(i+2)%3].Address() {
+				t.Errorf("signer %d pulls from %s, want the previous signer", i, from.Hex())
+			}
+		}
+	}
+
+	// One signer approves and pulls from itself
+	approvals, err = builder.GetApproveTransactions(context.Background(), signers[:1], []uint64{0}, 2)
+	if err != nil || len(approvals) != 1 || common.BytesToAddress(approvals[0].Tx.Data()[4:36]) != signers[0].Address() {
+		t.Errorf("single signer approvals = %v, %v", approvals, err)
+	}
+}
+
+func TestValidateTxs(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(1337)
+
+	sign := func(chain int64, nonce uint64, value int64) *SignedTx {
+		tx, err := types.SignNewTx(key, types.NewLondonSigner(big.NewInt(chain)), &types.DynamicFeeTx{
+			ChainID:   big.NewInt(chain),
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(2),
+			Gas:       21000,
+			To:        &from,
+			Value:     big.NewInt(value),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &SignedTx{Tx: tx, Hash: tx.Hash(), From: from, Nonce: nonce, GasLimit: 21000}
+	}
+
+	txs := []*SignedTx{
+		sign(1337, 0, 1),
+		sign(1337, 1, 1),
+		sign(1337, 0, 1), // Same tx as #0
+		sign(1337, 1, 2), // Different tx for nonce 1
+		sign(1, 2, 1),    // Wrong chain
+		{Hash: common.HexToHash("0x16"), From: from, Nonce: 3}, // Fee delegation, no decoded tx
+	}
+
+	issues := ValidateTxs(txs, chainID)
+	want := []struct {
+		kind  IssueKind
+		index int
+	}{{IssueDuplicate, 2}, {IssueNonceConflict, 3}, {IssueChainID, 4}}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %v", len(issues), len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Kind != w.kind || issues[i].Index != w.index || issues[i].Tx != txs[w.index] {
+			t.Errorf("issue %d = %s at #%d, want %s at #%d", i, issues[i].Kind, issues[i].Index, w.kind, w.index)
+		}
+	}
+
+	kept := PruneTxs(txs, issues)
+	if len(kept) != 3 || kept[0] != txs[0] || kept[1] != txs[1] || kept[2] != txs[5] {
+		t.Errorf("PruneTxs() kept %d txs, want #0, #1 and #5", len(kept))
+	}
+	if len(ValidateTxs(kept, chainID)) != 0 {
+		t.Error("pruned set should validate")
+	}
+}
+
+// remoteSigner stands in for an external signing service that only exposes
+// an address and signs digests on request
+type remoteSigner struct {
+	inner *KeySigner
+	calls int
+	err   error
+}
+
+func (s *remoteSigner) Address() common.Address { return s.inner.Address() }
+
+func (s *remoteSigner) SignHash(hash []byte) ([]byte, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.inner.SignHash(hash)
+}
+
+func TestSigner_External(t *testing.T) {
+	remote := &remoteSigner{inner: NewKeySigner(newTestKey())}
+	cfg := &BuilderConfig{ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)}
+
+	txs, err := NewTransferBuilder(cfg, nil).Build(context.Background(), []Signer{remote}, []uint64{0}, 3)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if remote.calls != 3 {
+		t.Errorf("SignHash calls = %d, want 3", remote.calls)
+	}
+	for _, tx := range txs {
+		sender, err := types.Sender(types.NewLondonSigner(cfg.ChainID), tx.Tx)
+		if err != nil || sender != remote.Address() {
+			t.Errorf("recovered sender = %s, %v; want %s", sender.Hex(), err, remote.Address().Hex())
+		}
+	}
+
+	// The fee payer signs through the same interface
+	feePayer := &remoteSigner{inner: NewKeySigner(newFeePayerKey())}
+	if _, err := NewFeeDelegationBuilder(cfg, nil, feePayer).Build(context.Background(), []Signer{remote}, []uint64{0}, 2); err != nil {
+		t.Fatalf("fee delegation Build() error = %v", err)
+	}
+	if feePayer.calls != 2 {
+		t.Errorf("fee payer SignHash calls = %d, want 2", feePayer.calls)
+	}
+
+	remote.err = errors.New("signing service unavailable")
+	if _, err := NewTransferBuilder(cfg, nil).Build(context.Background(), []Signer{remote}, []uint64{0}, 1); err == nil {
+		t.Error("expected error when the signer fails")
+	}
+}
+
+func TestSwapPair_Execution(t *testing.T) {
+	cfg := &runtime.Config{GasLimit: 10_000_000}
+	_, pair, _, err := runtime.Create(common.FromHex(SwapPairBytecode), cfg)
+	if err != nil {
+		t.Fatalf("deploy pair: %v", err)
+	}
+
+	reserve := new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil)
+	ret, _, err := runtime.Call(pair, crypto.Keccak256([]byte("getReserves()"))[:4], cfg)
+	if err != nil {
+		t.Fatalf("getReserves: %v", err)
+	}
+	if len(ret) != 64 || new(big.Int).SetBytes(ret[:32]).Cmp(reserve) != 0 || new(big.Int).SetBytes(ret[32:]).Cmp(reserve) != 0 {
+		t.Fatalf("getReserves() = %x, want 10^24 each", ret)
+	}
+
+	// out = in*997*reserveOut / (reserveIn*1000 + in*997)
+	amountIn := big.NewInt(1_000_000_000_000_000_000)
+	inWithFee := new(big.Int).Mul(amountIn, big.NewInt(997))
+	wantOut := new(big.Int).Div(
+		new(big.Int).Mul(inWithFee, reserve),
+		new(big.Int).Add(new(big.Int).Mul(reserve, big.NewInt(1000)), inWithFee))
+
+	_, leftOver, err := runtime.Call(pair, PairSwapCallData(amountIn, true), cfg)
+	if err != nil {
+		t.Fatalf("swap: %v", err)
+	}
+	if used := cfg.GasLimit - leftOver; used > 150000 {
+		t.Errorf("swap used %d gas, above the 150000 estimate", used)
+	}
+
+	reserve0 := new(big.Int).SetBytes(cfg.State.GetState(pair, common.Hash{}).Bytes())
+	reserve1 := new(big.Int).SetBytes(cfg.State.GetState(pair, common.BigToHash(big.NewInt(1))).Bytes())
+	if want := new(big.Int).Add(reserve, amountIn); reserve0.Cmp(want) != 0 {
+		t.Errorf("reserve0 = %s, want %s", reserve0, want)
+	}
+	if want := new(big.Int).Sub(reserve, wantOut); reserve1.Cmp(want) != 0 {
+		t.Errorf("reserve1 = %s, want %s", reserve1, want)
+	}
+
+	// The caller (cfg.Origin) is credited the token1 output
+	balanceSlot := new(big.Int).Add(new(big.Int).SetBytes(cfg.Origin.Bytes()), new(big.Int).Lsh(big.NewInt(1), 160))
+	if got := new(big.Int).SetBytes(cfg.State.GetState(pair, common.BigToHash(balanceSlot)).Bytes()); got.Cmp(wantOut) != 0 {
+		t.Errorf("token1 balance = %s, want %s", got, wantOut)
+	}
+
+	logs := cfg.State.Logs()
+	if len(logs) != 2 || logs[0].Topics[0] != SwapTopic || logs[1].Topics[0] != SyncTopic {
+		t.Fatalf("expected Swap and Sync logs, got %d logs", len(logs))
+	}
+	if got := new(big.Int).SetBytes(logs[0].Data[96:128]); got.Cmp(wantOut) != 0 {
+		t.Errorf("Swap amount1Out = %s, want %s", got, wantOut)
+	}
+
+	// The reverse direction moves reserves back
+	if _, _, err := runtime.Call(pair, PairSwapCallData(amountIn, false), cfg); err != nil {
+		t.Fatalf("reverse swap: %v", err)
+	}
+	if got := new(big.Int).SetBytes(cfg.State.GetState(pair, common.BigToHash(big.NewInt(1))).Bytes()); got.Cmp(reserve1) <= 0 {
+		t.Errorf("reserve1 = %s after the reverse swap, want above %s", got, reserve1)
+	}
+
+	// A zero output and a wrong selector revert
+	if _, _, err := runtime.Call(pair, PairSwapCallData(big.NewInt(0), true), cfg); err == nil {
+		t.Error("expected revert for a zero-output swap")
+	}
+	if _, _, err := runtime.Call(pair, []byte{1, 2, 3, 4}, cfg); err == nil {
+		t.Error("expected revert for unknown selector")
+	}
+}
+
+func TestSwapBuilder_Build(t *testing.T) {
+	builder := NewSwapBuilder(&BuilderConfig{ChainID: big.NewInt(1)}, &mockGasEstimator{})
+	key := newTestKey()
+
+	if _, err := builder.Build(context.Background(), []Signer{NewKeySigner(key)}, []uint64{0}, 2); err == nil {
+		t.Fatal("expected error without a pair or router")
+	}
+
+	pair := common.HexToAddress(testContractAddr)
+	builder.WithPair(pair)
+	txs, err := builder.Build(context.Background(), []Signer{NewKeySigner(key)}, []uint64{0}, 2)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(txs) != 2 || *txs[0].Tx.To() != pair {
+		t.Fatalf("expected 2 swaps against the pair, got %d", len(txs))
+	}
+	if !bytes.Equal(txs[0].Tx.Data(), PairSwapCallData(DefaultSwapAmount, true)) ||
+		!bytes.Equal(txs[1].Tx.Data(), PairSwapCallData(DefaultSwapAmount, false)) {
+		t.Error("consecutive swaps should alternate direction")
+	}
+
+	// Through a router, odd swaps take the reversed path
+	router := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tokenA := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	tokenB := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	builder.WithRouter(router, []common.Address{tokenA, tokenB})
+	txs, err = builder.Build(context.Background(), []Signer{NewKeySigner(key)}, []uint64{0}, 2)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	from := NewKeySigner(key).Address()
+	if *txs[0].Tx.To() != router {
+		t.Errorf("To = %s, want router", txs[0].Tx.To().Hex())
+	}
+	if !bytes.Equal(txs[0].Tx.Data(), RouterSwapCallData(DefaultSwapAmount, []common.Address{tokenA, tokenB}, from, maxDeadline)) ||
+		!bytes.Equal(txs[1].Tx.Data(), RouterSwapCallData(DefaultSwapAmount, []common.Address{tokenB, tokenA}, from, maxDeadline)) {
+		t.Error("router swaps should alternate the path direction")
+	}
+	if data := txs[0].Tx.Data(); len(data) != 4+32*8 || common.BytesToAddress(data[4+32*6:4+32*7]) != tokenA {
+		t.Errorf("router calldata has unexpected layout: %x", data)
+	}
+}
+
+// customFactory builds plain transfers for a registered test mode
+type customFactory struct {
+	opts Options
+}
+
+func (f *customFactory) GasLimit() uint64 { return 30000 }
+
+func (f *customFactory) New(cfg *BuilderConfig, estimator GasEstimator, opts Options) (Builder, error) {
+	f.opts = opts
+	return NewTransferBuilder(cfg, estimator), nil
+}
+
+func TestRegister(t *testing.T) {
+	custom := &customFactory{}
+	Register("custom_builder_test", custom)
+
+	if !config.IsCustomMode("CUSTOM_BUILDER_TEST") {
+		t.Fatal("registered mode is not known to config")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic registering a mode twice")
+			}
+		}()
+		Register("CUSTOM_BUILDER_TEST", custom)
+	}()
+
+	contract := common.HexToAddress(testContractAddr)
+	factory := NewFactory(&BuilderConfig{ChainID: big.NewInt(1)}, &mockGasEstimator{})
+	builder, err := factory.CreateBuilder("CUSTOM_BUILDER_TEST", WithContractAddress(contract), WithMethod("poke()"))
+	if err != nil {
+		t.Fatalf("CreateBuilder() error = %v", err)
+	}
+	if builder.Name() != "TRANSFER" {
+		t.Errorf("Name() = %s, want the custom factory's builder", builder.Name())
+	}
+	if custom.opts.Contract != contract || custom.opts.Method != "poke()" || custom.opts.Value.Sign() != 0 {
+		t.Errorf("Options = %+v, want the contract, method and a zero value", custom.opts)
+	}
+}
+
+func TestIntrinsicGas(t *testing.T) {
+	to := common.HexToAddress("0x01")
+	initcode := make([]byte, 33)
+	for i := range initcode {
+		initcode[i] = 0xff
+	}
+	tests := []struct {
+		name string
+		tx   types.TxData
+		want uint64
+	}{
+		{"transfer", &types.LegacyTx{To: &to}, 21000},
+		{"calldata", &types.LegacyTx{To: &to, Data: []byte{0, 1, 2, 0}}, 21000 + (2*4+2)*10}, // EIP-7623 floor
+		{"large calldata", &types.LegacyTx{To: &to, Data: initcode[:32]}, 21000 + 32*4*10},
+		{"creation", &types.LegacyTx{Data: initcode}, 53000 + 2*2 + 33*16},
+		{"access list", &types.AccessListTx{To: &to, AccessList: types.AccessList{
+			{Address: to, StorageKeys: []common.Hash{{}, {1}}},
+		}}, 21000 + 2400 + 2*1900},
+	}
+	for _, tt := range tests {
+		if got := IntrinsicGas(types.NewTx(tt.tx)); got != tt.want {
+			t.Errorf("%s: IntrinsicGas() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTxs_IntrinsicGas(t *testing.T) {
+	key, _ := crypto.HexToECDSA(testPrivateKey)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(1337)
+
+	sign := func(nonce uint64, data []byte) *SignedTx {
+		tx, err := types.SignNewTx(key, types.NewLondonSigner(chainID), &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(2),
+			Gas:       21000,
+			To:        &from,
+			Data:      data,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &SignedTx{Tx: tx, Hash: tx.Hash(), From: from, Nonce: nonce, GasLimit: 21000}
+	}
+
+	txs := []*SignedTx{sign(0, nil), sign(1, []byte("padding"))}
+	issues := ValidateTxs(txs, chainID)
+	if len(issues) != 1 || issues[0].Kind != IssueIntrinsicGas || issues[0].Index != 1 {
+		t.Fatalf("ValidateTxs() = %v, want an intrinsic gas issue for #1", issues)
+	}
+	if !strings.Contains(issues[0].Detail, "21280") {
+		t.Errorf("Detail = %q, want the intrinsic gas of 21280", issues[0].Detail)
+	}
+}
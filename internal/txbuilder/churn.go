@@ -0,0 +1,241 @@
+package txbuilder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/progress"
+)
+
+// Create2ChurnFactoryBytecode deploys a hand-assembled factory for CREATE2_CHURN.
+//
+// churn(uint256 salt, uint256 count) runs CREATE2 count times with salts
+// salt..salt+count-1, then emits ChurnCreated(created) with the number of
+// successful creations. Each child's init code emits ChurnDestroyed() and
+// self-destructs, so every child is created and deleted within the same
+// transaction and the address can never collide across runs.
+const Create2ChurnFactoryBytecode = "0x6099600c60003960996000f360003560e01c63502e100b1461001457600080fd5b602861007160003960243560043560005b82156100465781602860006000f51515019060010190916001900391610025565b6000527fe3fea65b0e0fa96dbe6bdea2a33189193664cb37ef910dd06f79889cefd52c4d60206000a1007f6aefeedb4155038f3c78378467e7f497c41f9e9ba6f62fda0715b17dced3039560006000a133ff"
+
+var (
+	// ChurnCreatedTopic is the topic of the factory's ChurnCreated(uint256) event
+	ChurnCreatedTopic = crypto.Keccak256Hash([]byte("ChurnCreated(uint256)"))
+
+	// ChurnDestroyedTopic is the topic of the ChurnDestroyed() event each child emits before self-destructing
+	ChurnDestroyedTopic = crypto.Keccak256Hash([]byte("ChurnDestroyed()"))
+
+	// churnSelector is the selector of churn(uint256,uint256)
+	churnSelector = crypto.Keccak256([]byte("churn(uint256,uint256)"))[:4]
+)
+
+// DefaultChurnCount is the number of contracts created and destroyed per transaction
+const DefaultChurnCount = 10
+
+// churnGasPerContract approximates the gas of one CREATE2 plus the child's self-destruct
+const churnGasPerContract = 45000
+
+// Create2ChurnBuilder builds transactions that create and self-destruct
+// contracts through the churn factory
+type Create2ChurnBuilder struct {
+	*BaseBuilder
+	factory common.Address
+	count   int
+}
+
+// NewCreate2ChurnBuilder creates a new CREATE2 churn builder
+func NewCreate2ChurnBuilder(config *BuilderConfig, estimator GasEstimator) *Create2ChurnBuilder {
+	return &Create2ChurnBuilder{
+		BaseBuilder: NewBaseBuilder(config, estimator),
+		count:       DefaultChurnCount,
+	}
+}
+
+// WithFactory sets the address of an already deployed churn factory
+func (b *Create2ChurnBuilder) WithFactory(addr common.Address) *Create2ChurnBuilder {
+	b.factory = addr
+	return b
+}
+
+// WithChurnCount sets the number of contracts churned per transaction
+func (b *Create2ChurnBuilder) WithChurnCount(count int) *Create2ChurnBuilder {
+	if count > 0 {
+		b.count = count
+	}
+	return b
+}
+
+// Factory returns the churn factory address
+func (b *Create2ChurnBuilder) Factory() common.Address {
+	return b.factory
+}
+
+// Name returns the builder name
+func (b *Create2ChurnBuilder) Name() string {
+	return "CREATE2_CHURN"
+}
+
+// EstimateGas estimates gas for one churn transaction
+func (b *Create2ChurnBuilder) EstimateGas(_ context.Context) (uint64, error) {
+	return 21000 + uint64(b.count)*churnGasPerContract, nil
+}
+
+// GetDeployTransaction returns the signed factory deployment transaction
+func (b *Create2ChurnBuilder) GetDeployTransaction(ctx context.Context, signer Signer, nonce uint64) (*SignedTx, error) {
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := uint64(200000)
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   b.config.ChainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       gasLimit,
+		To:        nil, // Contract creation
+		Value:     big.NewInt(0),
+		Data:      common.FromHex(Create2ChurnFactoryBytecode),
+	})
+
+	signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign deployment transaction: %w", err)
+	}
+
+	rawTx, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	return &SignedTx{
+		Tx:       signedTx,
+		RawTx:    rawTx,
+		Hash:     signedTx.Hash(),
+		From:     signer.Address(),
+		Nonce:    nonce,
+		GasLimit: gasLimit,
+	}, nil
+}
+
+// ChurnCallData packs churn(salt, count)
+func ChurnCallData(salt *big.Int, count int) []byte {
+	data := make([]byte, 0, 4+64)
+	data = append(data, churnSelector...)
+	data = append(data, common.LeftPadBytes(salt.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(count)).Bytes(), 32)...)
+	return data
+}
+
+// churnSalt returns the first salt of a transaction. The sender address in the
+// high bits and the nonce below keep salts unique across every transaction.
+func churnSalt(from common.Address, nonce uint64, count int) *big.Int {
+	salt := new(big.Int).Lsh(new(big.Int).SetBytes(from.Bytes()), 96)
+	offset := new(big.Int).Mul(new(big.Int).SetUint64(nonce), big.NewInt(int64(count)))
+	return salt.Add(salt, offset)
+}
+
+// Build creates churn transactions
+func (b *Create2ChurnBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
+	}
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch")
+	}
+	if b.factory == (common.Address{}) {
+		return nil, fmt.Errorf("churn factory address is required")
+	}
+
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := b.config.GasLimit
+	if gasLimit == 0 {
+		gasLimit, _ = b.EstimateGas(ctx)
+	}
+
+	distribution := DistributeTransactions(len(signers), count)
+
+	totalTxs := 0
+	for _, n := range distribution {
+		totalTxs += n
+	}
+
+	console.Printf("\nBuilding CREATE2 Churn Transactions\n\n")
+	console.Printf("Factory: %s\n", b.factory.Hex())
+	console.Printf("Contracts per tx: %d\n", b.count)
+	bar := progress.New(int64(totalTxs), "txs built")
+
+	signedTxs := make([]*SignedTx, 0, totalTxs)
+
+	for accountIdx, txCount := range distribution {
+		signer := signers[accountIdx]
+		nonce := nonces[accountIdx]
+		from := signer.Address()
+
+		for i := 0; i < txCount; i++ {
+			tx := types.NewTx(&types.DynamicFeeTx{
+				ChainID:   b.config.ChainID,
+				Nonce:     nonce,
+				GasTipCap: gasTipCap,
+				GasFeeCap: gasFeeCap,
+				Gas:       gasLimit,
+				To:        &b.factory,
+				Value:     big.NewInt(0),
+				Data:      ChurnCallData(churnSalt(from, nonce, b.count), b.count),
+			})
+
+			signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign transaction: %w", err)
+			}
+
+			rawTx, err := signedTx.MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+			}
+
+			signedTxs = append(signedTxs, &SignedTx{
+				Tx:       signedTx,
+				RawTx:    rawTx,
+				Hash:     signedTx.Hash(),
+				From:     from,
+				Nonce:    nonce,
+				GasLimit: gasLimit,
+			})
+
+			nonce++
+			progress.Add(bar, 1)
+		}
+	}
+
+	console.OKf("\nSuccessfully built %d CREATE2 churn transactions\n", len(signedTxs))
+	return signedTxs, nil
+}
+
+// ChurnCounts returns the contracts created and destroyed according to a receipt's logs
+func ChurnCounts(receipt *types.Receipt) (created, destroyed int) {
+	if receipt == nil {
+		return 0, 0
+	}
+	for _, log := range receipt.Logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		switch log.Topics[0] {
+		case ChurnCreatedTopic:
+			created += int(new(big.Int).SetBytes(log.Data).Int64())
+		case ChurnDestroyedTopic:
+			destroyed++
+		}
+	}
+	return created, destroyed
+}
@@ -2,7 +2,6 @@ package txbuilder
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 	"strings"
@@ -11,14 +10,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/schollz/progressbar/v3"
 
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/progress"
 )
 
 // SimpleStorageBytecode is a simple storage contract bytecode for testing
 // contract SimpleStorage { uint256 value; function set(uint256 v) { value = v; } function get() view returns (uint256) { return value; } }
-const SimpleStorageBytecode = "608060405234801561001057600080fd5b5060c78061001f6000396000f3fe6080604052348015600f57600080fd5b506004361060325760003560e01c806360fe47b11460375780636d4ce63c146049575b600080fd5b60476042366004605e565b600055565b005b60005460405190815260200160405180910390f35b600060208284031215606f57600080fd5b503591905056fea264697066735822122041c6fd36c2a89c8d6d6ee3b8d14a6a05a4f7a6f25c6e4a7b3c8d9e0f1a2b3c4d564736f6c63430008130033"
+const SimpleStorageBytecode = "608060405234801561001057600080fd5b5060ac8061001f6000396000f3fe6080604052348015600f57600080fd5b506004361060325760003560e01c806360fe47b11460375780636d4ce63c146049575b600080fd5b60476042366004605e565b600055565b005b60005460405190815260200160405180910390f35b600060208284031215606f57600080fd5b503591905056fea264697066735822122041c6fd36c2a89c8d6d6ee3b8d14a6a05a4f7a6f25c6e4a7b3c8d9e0f1a2b3c4d64736f6c63430008130033"
 
 // ContractDeployBuilder builds contract deployment transactions
 type ContractDeployBuilder struct {
@@ -55,12 +55,12 @@ func (b *ContractDeployBuilder) EstimateGas(_ context.Context) (uint64, error) {
 }
 
 // Build creates contract deployment transactions
-func (b *ContractDeployBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, nonces []uint64, count int) ([]*SignedTx, error) {
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no keys provided")
+func (b *ContractDeployBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
 	}
-	if len(keys) != len(nonces) {
-		return nil, fmt.Errorf("keys and nonces length mismatch")
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch")
 	}
 
 	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
@@ -73,22 +73,22 @@ func (b *ContractDeployBuilder) Build(ctx context.Context, keys []*ecdsa.Private
 		gasLimit = 200000
 	}
 
-	distribution := DistributeTransactions(len(keys), count)
+	distribution := DistributeTransactions(len(signers), count)
 
 	totalTxs := 0
 	for _, n := range distribution {
 		totalTxs += n
 	}
 
-	fmt.Printf("\nBuilding Contract Deploy Transactions\n\n")
-	bar := progressbar.Default(int64(totalTxs), "txs built")
+	console.Printf("\nBuilding Contract Deploy Transactions\n\n")
+	bar := progress.New(int64(totalTxs), "txs built")
 
 	signedTxs := make([]*SignedTx, 0, totalTxs)
 
 	for accountIdx, txCount := range distribution {
-		key := keys[accountIdx]
+		signer := signers[accountIdx]
 		nonce := nonces[accountIdx]
-		from := crypto.PubkeyToAddress(key.PublicKey)
+		from := signer.Address()
 
 		for i := 0; i < txCount; i++ {
 			// Contract deployment: to = nil
@@ -103,7 +103,7 @@ func (b *ContractDeployBuilder) Build(ctx context.Context, keys []*ecdsa.Private
 				Data:      b.bytecode,
 			})
 
-			signedTx, err := SignTransaction(tx, b.config.ChainID, key)
+			signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
 			if err != nil {
 				return nil, fmt.Errorf("failed to sign transaction: %w", err)
 			}
@@ -127,7 +127,7 @@ func (b *ContractDeployBuilder) Build(ctx context.Context, keys []*ecdsa.Private
 		}
 	}
 
-	fmt.Printf("\n[OK] Successfully built %d contract deploy transactions\n", len(signedTxs))
+	console.OKf("\nSuccessfully built %d contract deploy transactions\n", len(signedTxs))
 	return signedTxs, nil
 }
 
@@ -177,12 +177,12 @@ func (b *ContractCallBuilder) EstimateGas(_ context.Context) (uint64, error) {
 }
 
 // Build creates contract call transactions
-func (b *ContractCallBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, nonces []uint64, count int) ([]*SignedTx, error) {
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no keys provided")
+func (b *ContractCallBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
 	}
-	if len(keys) != len(nonces) {
-		return nil, fmt.Errorf("keys and nonces length mismatch")
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch")
 	}
 	if b.contractAddr == (common.Address{}) {
 		return nil, fmt.Errorf("contract address is required")
@@ -204,38 +204,43 @@ func (b *ContractCallBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKe
 		gasLimit = 100000
 	}
 
-	distribution := DistributeTransactions(len(keys), count)
+	distribution := DistributeTransactions(len(signers), count)
 
 	totalTxs := 0
 	for _, n := range distribution {
 		totalTxs += n
 	}
 
-	fmt.Printf("\nBuilding Contract Call Transactions\n\n")
-	fmt.Printf("Contract: %s\n", b.contractAddr.Hex())
-	fmt.Printf("Method: %s\n", b.methodSig)
-	bar := progressbar.Default(int64(totalTxs), "txs built")
+	// Value-bearing calls need a payable method (default: no value)
+	value := b.config.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	console.Printf("\nBuilding Contract Call Transactions\n\n")
+	console.Printf("Contract: %s\n", b.contractAddr.Hex())
+	console.Printf("Method: %s\n", b.methodSig)
+	bar := progress.New(int64(totalTxs), "txs built")
 
 	signedTxs := make([]*SignedTx, 0, totalTxs)
 
 	for accountIdx, txCount := range distribution {
-		key := keys[accountIdx]
+		signer := signers[accountIdx]
 		nonce := nonces[accountIdx]
-		from := crypto.PubkeyToAddress(key.PublicKey)
+		from := signer.Address()
 
 		for i := 0; i < txCount; i++ {
-			tx := types.NewTx(&types.DynamicFeeTx{
-				ChainID:   b.config.ChainID,
+			tx := b.newTx(config.TxTypeEIP1559, &TxRequest{
+				To:        &b.contractAddr,
+				Value:     value,
+				Data:      callData,
 				Nonce:     nonce,
+				Gas:       gasLimit,
 				GasTipCap: gasTipCap,
 				GasFeeCap: gasFeeCap,
-				Gas:       gasLimit,
-				To:        &b.contractAddr,
-				Value:     big.NewInt(0),
-				Data:      callData,
 			})
 
-			signedTx, err := SignTransaction(tx, b.config.ChainID, key)
+			signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
 			if err != nil {
 				return nil, fmt.Errorf("failed to sign transaction: %w", err)
 			}
@@ -259,7 +264,7 @@ func (b *ContractCallBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKe
 		}
 	}
 
-	fmt.Printf("\n[OK] Successfully built %d contract call transactions\n", len(signedTxs))
+	console.OKf("\nSuccessfully built %d contract call transactions\n", len(signedTxs))
 	return signedTxs, nil
 }
 
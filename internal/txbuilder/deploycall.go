@@ -0,0 +1,200 @@
+package txbuilder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/progress"
+)
+
+// simpleStorageSetSelector is the selector of SimpleStorage.set(uint256)
+var simpleStorageSetSelector = crypto.Keccak256([]byte("set(uint256)"))[:4]
+
+// DeployThenCallBuilder builds calls spread round-robin over contracts that
+// the pipeline deploys right before building
+type DeployThenCallBuilder struct {
+	*BaseBuilder
+	contracts []common.Address
+	methodSig string // Selector-only method; empty calls set(uint256) with a value per tx
+}
+
+// NewDeployThenCallBuilder creates a new deploy-then-call builder
+func NewDeployThenCallBuilder(config *BuilderConfig, estimator GasEstimator) *DeployThenCallBuilder {
+	return &DeployThenCallBuilder{
+		BaseBuilder: NewBaseBuilder(config, estimator),
+	}
+}
+
+// WithMethod sets a method without arguments to call instead of set(uint256)
+func (b *DeployThenCallBuilder) WithMethod(methodSig string) *DeployThenCallBuilder {
+	b.methodSig = methodSig
+	return b
+}
+
+// WithContracts sets the deployed contracts the calls are spread over
+func (b *DeployThenCallBuilder) WithContracts(addrs []common.Address) *DeployThenCallBuilder {
+	b.contracts = addrs
+	return b
+}
+
+// Contracts returns the contracts the calls are spread over
+func (b *DeployThenCallBuilder) Contracts() []common.Address {
+	return b.contracts
+}
+
+// Name returns the builder name
+func (b *DeployThenCallBuilder) Name() string {
+	return "DEPLOY_THEN_CALL"
+}
+
+// EstimateGas estimates gas for one call
+func (b *DeployThenCallBuilder) EstimateGas(_ context.Context) (uint64, error) {
+	return 100000, nil
+}
+
+// GetDeployTransactions returns count signed SimpleStorage deployments from
+// key, using consecutive nonces starting at nonce
+func (b *DeployThenCallBuilder) GetDeployTransactions(ctx context.Context, signer Signer, nonce uint64, count int) ([]*SignedTx, error) {
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := uint64(200000)
+	from := signer.Address()
+	bytecode := common.FromHex(SimpleStorageBytecode)
+
+	deployTxs := make([]*SignedTx, 0, count)
+	for i := 0; i < count; i++ {
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   b.config.ChainID,
+			Nonce:     nonce,
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       gasLimit,
+			To:        nil, // Contract creation
+			Value:     big.NewInt(0),
+			Data:      bytecode,
+		})
+
+		signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign deployment transaction: %w", err)
+		}
+
+		rawTx, err := signedTx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+		}
+
+		deployTxs = append(deployTxs, &SignedTx{
+			Tx:       signedTx,
+			RawTx:    rawTx,
+			Hash:     signedTx.Hash(),
+			From:     from,
+			Nonce:    nonce,
+			GasLimit: gasLimit,
+		})
+		nonce++
+	}
+	return deployTxs, nil
+}
+
+// callData returns the calldata of the n-th call
+func (b *DeployThenCallBuilder) callData(n int) []byte {
+	if b.methodSig != "" {
+		return crypto.Keccak256([]byte(b.methodSig))[:4]
+	}
+	data := make([]byte, 0, 4+32)
+	data = append(data, simpleStorageSetSelector...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(n+1)).Bytes(), 32)...)
+	return data
+}
+
+// Build creates call transactions; the n-th transaction targets contract n mod K
+func (b *DeployThenCallBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
+	}
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch")
+	}
+	if len(b.contracts) == 0 {
+		return nil, fmt.Errorf("no deployed contracts to call")
+	}
+
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := b.config.GasLimit
+	if gasLimit == 0 {
+		gasLimit, _ = b.EstimateGas(ctx)
+	}
+
+	distribution := DistributeTransactions(len(signers), count)
+
+	totalTxs := 0
+	for _, n := range distribution {
+		totalTxs += n
+	}
+
+	console.Printf("\nBuilding Deploy-Then-Call Transactions\n\n")
+	console.Printf("Contracts: %d\n", len(b.contracts))
+	bar := progress.New(int64(totalTxs), "txs built")
+
+	signedTxs := make([]*SignedTx, 0, totalTxs)
+
+	for accountIdx, txCount := range distribution {
+		signer := signers[accountIdx]
+		nonce := nonces[accountIdx]
+		from := signer.Address()
+
+		for i := 0; i < txCount; i++ {
+			n := len(signedTxs)
+			to := b.contracts[n%len(b.contracts)]
+			tx := types.NewTx(&types.DynamicFeeTx{
+				ChainID:   b.config.ChainID,
+				Nonce:     nonce,
+				GasTipCap: gasTipCap,
+				GasFeeCap: gasFeeCap,
+				Gas:       gasLimit,
+				To:        &to,
+				Value:     big.NewInt(0),
+				Data:      b.callData(n),
+			})
+
+			signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign transaction: %w", err)
+			}
+
+			rawTx, err := signedTx.MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+			}
+
+			signedTxs = append(signedTxs, &SignedTx{
+				Tx:       signedTx,
+				RawTx:    rawTx,
+				Hash:     signedTx.Hash(),
+				From:     from,
+				Nonce:    nonce,
+				GasLimit: gasLimit,
+			})
+
+			nonce++
+			progress.Add(bar, 1)
+		}
+	}
+
+	console.OKf("\nSuccessfully built %d deploy-then-call transactions\n", len(signedTxs))
+	return signedTxs, nil
+}
@@ -2,15 +2,13 @@ package txbuilder
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/schollz/progressbar/v3"
 
+	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/progress"
 )
 
@@ -22,6 +20,8 @@ var (
 	ERC20BalanceOfSelector = common.FromHex("0x70a08231")
 	// approve(address,uint256) = 0x095ea7b3
 	ERC20ApproveSelector = common.FromHex("0x095ea7b3")
+	// transferFrom(address,address,uint256) = 0x23b872dd
+	ERC20TransferFromSelector = common.FromHex("0x23b872dd")
 )
 
 // ERC20TransferBuilder builds ERC20 transfer transactions
@@ -65,12 +65,12 @@ func (b *ERC20TransferBuilder) EstimateGas(_ context.Context) (uint64, error) {
 }
 
 // Build creates ERC20 transfer transactions
-func (b *ERC20TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, nonces []uint64, count int) ([]*SignedTx, error) {
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no keys provided")
+func (b *ERC20TransferBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
 	}
-	if len(keys) != len(nonces) {
-		return nil, fmt.Errorf("keys and nonces length mismatch")
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch")
 	}
 	if b.tokenAddr == (common.Address{}) {
 		return nil, fmt.Errorf("token address is required")
@@ -86,23 +86,23 @@ func (b *ERC20TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateK
 		gasLimit = 65000
 	}
 
-	distribution := DistributeTransactions(len(keys), count)
+	distribution := DistributeTransactions(len(signers), count)
 
 	totalTxs := 0
 	for _, n := range distribution {
 		totalTxs += n
 	}
 
-	fmt.Printf("\nBuilding ERC20 Transfer Transactions\n\n")
-	fmt.Printf("Token: %s\n", b.tokenAddr.Hex())
-	bar := progressbar.Default(int64(totalTxs), "txs built")
+	console.Printf("\nBuilding ERC20 Transfer Transactions\n\n")
+	console.Printf("Token: %s\n", b.tokenAddr.Hex())
+	bar := progress.New(int64(totalTxs), "txs built")
 
 	signedTxs := make([]*SignedTx, 0, totalTxs)
 
 	for accountIdx, txCount := range distribution {
-		key := keys[accountIdx]
+		signer := signers[accountIdx]
 		nonce := nonces[accountIdx]
-		from := crypto.PubkeyToAddress(key.PublicKey)
+		from := signer.Address()
 
 		for i := 0; i < txCount; i++ {
 			// Determine recipient (self-transfer if not specified)
@@ -114,18 +114,17 @@ func (b *ERC20TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateK
 			// Build ERC20 transfer data
 			data := buildERC20TransferData(recipient, b.amount)
 
-			tx := types.NewTx(&types.DynamicFeeTx{
-				ChainID:   b.config.ChainID,
-				Nonce:     nonce,
-				GasTipCap: gasTipCap,
-				GasFeeCap: gasFeeCap,
-				Gas:       gasLimit,
+			tx := b.newTx(config.TxTypeEIP1559, &TxRequest{
 				To:        &b.tokenAddr,
 				Value:     big.NewInt(0), // No native value for ERC20 transfer
 				Data:      data,
+				Nonce:     nonce,
+				Gas:       gasLimit,
+				GasTipCap: gasTipCap,
+				GasFeeCap: gasFeeCap,
 			})
 
-			signedTx, err := SignTransaction(tx, b.config.ChainID, key)
+			signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
 			if err != nil {
 				return nil, fmt.Errorf("failed to sign transaction: %w", err)
 			}
@@ -149,7 +148,7 @@ func (b *ERC20TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateK
 		}
 	}
 
-	fmt.Printf("\n[OK] Successfully built %d ERC20 transfer transactions\n", len(signedTxs))
+	console.OKf("\nSuccessfully built %d ERC20 transfer transactions\n", len(signedTxs))
 	return signedTxs, nil
 }
 
@@ -2,7 +2,6 @@ package txbuilder
 
 import (
 	"context"
-	"crypto/ecdsa"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -13,8 +12,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/schollz/progressbar/v3"
 
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/progress"
 )
 
@@ -107,7 +106,7 @@ func (b *ERC721MintBuilder) GetContractAddress() common.Address {
 }
 
 // DeployContract deploys the NFT contract and returns the contract address
-func (b *ERC721MintBuilder) DeployContract(ctx context.Context, key *ecdsa.PrivateKey, nonce uint64) (common.Address, common.Hash, error) {
+func (b *ERC721MintBuilder) DeployContract(ctx context.Context, signer Signer, nonce uint64) (common.Address, common.Hash, error) {
 	// Pack constructor arguments
 	constructorArgs, err := b.contractABI.Pack("", b.nftName, b.nftSymbol)
 	if err != nil {
@@ -138,20 +137,20 @@ func (b *ERC721MintBuilder) DeployContract(ctx context.Context, key *ecdsa.Priva
 		Data:      deployData,
 	})
 
-	signedTx, err := SignTransaction(tx, b.config.ChainID, key)
+	signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
 	if err != nil {
 		return common.Address{}, common.Hash{}, fmt.Errorf("failed to sign deployment transaction: %w", err)
 	}
 
 	// Calculate contract address
-	from := crypto.PubkeyToAddress(key.PublicKey)
+	from := signer.Address()
 	contractAddr := crypto.CreateAddress(from, nonce)
 
 	return contractAddr, signedTx.Hash(), nil
 }
 
 // GetDeployTransaction returns the signed deployment transaction
-func (b *ERC721MintBuilder) GetDeployTransaction(ctx context.Context, key *ecdsa.PrivateKey, nonce uint64) (*SignedTx, error) {
+func (b *ERC721MintBuilder) GetDeployTransaction(ctx context.Context, signer Signer, nonce uint64) (*SignedTx, error) {
 	// Pack constructor arguments
 	constructorArgs, err := b.contractABI.Pack("", b.nftName, b.nftSymbol)
 	if err != nil {
@@ -182,7 +181,7 @@ func (b *ERC721MintBuilder) GetDeployTransaction(ctx context.Context, key *ecdsa
 		Data:      deployData,
 	})
 
-	signedTx, err := SignTransaction(tx, b.config.ChainID, key)
+	signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign deployment transaction: %w", err)
 	}
@@ -192,7 +191,7 @@ func (b *ERC721MintBuilder) GetDeployTransaction(ctx context.Context, key *ecdsa
 		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
 	}
 
-	from := crypto.PubkeyToAddress(key.PublicKey)
+	from := signer.Address()
 
 	return &SignedTx{
 		Tx:       signedTx,
@@ -205,12 +204,12 @@ func (b *ERC721MintBuilder) GetDeployTransaction(ctx context.Context, key *ecdsa
 }
 
 // Build creates ERC721 mint transactions (createNFT calls)
-func (b *ERC721MintBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, nonces []uint64, count int) ([]*SignedTx, error) {
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no keys provided")
+func (b *ERC721MintBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
 	}
-	if len(keys) != len(nonces) {
-		return nil, fmt.Errorf("keys and nonces length mismatch")
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch")
 	}
 	if b.nftContract == (common.Address{}) {
 		return nil, fmt.Errorf("NFT contract address is required")
@@ -226,25 +225,25 @@ func (b *ERC721MintBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey,
 		gasLimit = 150000
 	}
 
-	distribution := DistributeTransactions(len(keys), count)
+	distribution := DistributeTransactions(len(signers), count)
 
 	totalTxs := 0
 	for _, n := range distribution {
 		totalTxs += n
 	}
 
-	fmt.Printf("\nBuilding ERC721 Mint Transactions\n\n")
-	fmt.Printf("NFT Contract: %s\n", b.nftContract.Hex())
-	fmt.Printf("Token URI Base: %s\n", b.tokenURI)
-	bar := progressbar.Default(int64(totalTxs), "txs built")
+	console.Printf("\nBuilding ERC721 Mint Transactions\n\n")
+	console.Printf("NFT Contract: %s\n", b.nftContract.Hex())
+	console.Printf("Token URI Base: %s\n", b.tokenURI)
+	bar := progress.New(int64(totalTxs), "txs built")
 
 	signedTxs := make([]*SignedTx, 0, totalTxs)
 	tokenID := uint64(0)
 
 	for accountIdx, txCount := range distribution {
-		key := keys[accountIdx]
+		signer := signers[accountIdx]
 		nonce := nonces[accountIdx]
-		from := crypto.PubkeyToAddress(key.PublicKey)
+		from := signer.Address()
 
 		for i := 0; i < txCount; i++ {
 			// Build createNFT call data with unique token URI
@@ -265,7 +264,7 @@ func (b *ERC721MintBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey,
 				Data:      callData,
 			})
 
-			signedTx, err := SignTransaction(tx, b.config.ChainID, key)
+			signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
 			if err != nil {
 				return nil, fmt.Errorf("failed to sign transaction: %w", err)
 			}
@@ -290,6 +289,6 @@ func (b *ERC721MintBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey,
 		}
 	}
 
-	fmt.Printf("\n[OK] Successfully built %d ERC721 mint transactions\n", len(signedTxs))
+	console.OKf("\nSuccessfully built %d ERC721 mint transactions\n", len(signedTxs))
 	return signedTxs, nil
 }
@@ -1,7 +1,6 @@
 package txbuilder
 
 import (
-	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 
@@ -46,17 +45,28 @@ func (f *Factory) buildBuilder(mode config.Mode, options *builderOptions) (Build
 		return f.buildContractCall(options)
 	case config.ModeERC20Transfer:
 		return f.buildERC20Transfer(options)
+	case config.ModeApproveTransferFrom:
+		return f.buildApproveTransferFrom(options)
 	case config.ModeERC721Mint:
 		return f.buildERC721Mint(options)
-	case config.ModeLongSender, config.ModeAnalyzeBlocks:
+	case config.ModeCreate2Churn:
+		return f.buildCreate2Churn(options), nil
+	case config.ModeDeployThenCall:
+		return f.buildDeployThenCall(options), nil
+	case config.ModeSwap:
+		return f.buildSwap(options), nil
+	case config.ModeLongSender, config.ModeAnalyzeBlocks, config.ModeTargetUtilization, config.ModeConflict:
 		return nil, fmt.Errorf("mode %s does not use a transaction builder", mode)
 	default:
+		if custom, ok := registered(mode); ok {
+			return f.buildCustom(custom, options)
+		}
 		return nil, fmt.Errorf("unsupported mode: %s", mode)
 	}
 }
 
 func (f *Factory) buildTransfer(options *builderOptions) *TransferBuilder {
-	builder := NewTransferBuilder(f.cfg, f.estimator)
+	builder := NewTransferBuilder(f.cfg, f.estimator).WithRecipientStrategy(options.recipientStrategy)
 	if options.recipient != (common.Address{}) {
 		builder.WithRecipient(options.recipient)
 	}
@@ -64,10 +74,10 @@ func (f *Factory) buildTransfer(options *builderOptions) *TransferBuilder {
 }
 
 func (f *Factory) buildFeeDelegation(options *builderOptions) (Builder, error) {
-	if options.feePayerKey == nil {
-		return nil, fmt.Errorf("fee payer key is required for FEE_DELEGATION mode")
+	if options.feePayer == nil {
+		return nil, fmt.Errorf("fee payer is required for FEE_DELEGATION mode")
 	}
-	builder := NewFeeDelegationBuilder(f.cfg, f.estimator, options.feePayerKey)
+	builder := NewFeeDelegationBuilder(f.cfg, f.estimator, options.feePayer).WithRecipientStrategy(options.recipientStrategy)
 	if options.recipient != (common.Address{}) {
 		builder.WithRecipient(options.recipient)
 	}
@@ -114,6 +124,17 @@ func (f *Factory) buildERC20Transfer(options *builderOptions) (Builder, error) {
 	return builder, nil
 }
 
+func (f *Factory) buildApproveTransferFrom(options *builderOptions) (Builder, error) {
+	if options.tokenAddr == (common.Address{}) {
+		return nil, fmt.Errorf("token address is required for APPROVE_TRANSFERFROM mode")
+	}
+	builder := NewApproveTransferFromBuilder(f.cfg, f.estimator, options.tokenAddr)
+	if options.amount != nil {
+		builder.WithAmount(options.amount)
+	}
+	return builder, nil
+}
+
 func (f *Factory) buildERC721Mint(options *builderOptions) (Builder, error) {
 	builder, err := NewERC721MintBuilder(f.cfg, f.estimator)
 	if err != nil {
@@ -134,24 +155,70 @@ func (f *Factory) buildERC721Mint(options *builderOptions) (Builder, error) {
 	return builder, nil
 }
 
+func (f *Factory) buildCreate2Churn(options *builderOptions) *Create2ChurnBuilder {
+	builder := NewCreate2ChurnBuilder(f.cfg, f.estimator)
+	if options.contractAddr != (common.Address{}) {
+		builder.WithFactory(options.contractAddr)
+	}
+	if options.churnCount > 0 {
+		builder.WithChurnCount(options.churnCount)
+	}
+	return builder
+}
+
+func (f *Factory) buildDeployThenCall(options *builderOptions) *DeployThenCallBuilder {
+	builder := NewDeployThenCallBuilder(f.cfg, f.estimator)
+	if options.method != "" {
+		builder.WithMethod(options.method)
+	}
+	return builder
+}
+
+func (f *Factory) buildSwap(options *builderOptions) *SwapBuilder {
+	builder := NewSwapBuilder(f.cfg, f.estimator).WithAmount(options.amount)
+	if len(options.swapPath) > 0 {
+		builder.WithRouter(options.contractAddr, options.swapPath)
+	} else if options.contractAddr != (common.Address{}) {
+		builder.WithPair(options.contractAddr)
+	}
+	return builder
+}
+
+func (f *Factory) buildCustom(custom BuilderFactory, options *builderOptions) (Builder, error) {
+	value := options.amount
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return custom.New(f.cfg, f.estimator, Options{
+		Contract: options.contractAddr,
+		Method:   options.method,
+		Value:    value,
+	})
+}
+
 // BuilderOption is a functional option for builder configuration
 type BuilderOption func(*builderOptions)
 
 type builderOptions struct {
-	recipient    common.Address
-	feePayerKey  *ecdsa.PrivateKey
-	contractAddr common.Address
-	tokenAddr    common.Address
-	bytecode     []byte
-	method       string
-	methodArgs   []interface{}
-	abiJSON      string
-	amount       *big.Int
+	recipient         common.Address
+	recipientStrategy config.RecipientStrategy
+	feePayer          Signer
+	contractAddr      common.Address
+	tokenAddr         common.Address
+	bytecode          []byte
+	method            string
+	methodArgs        []interface{}
+	abiJSON           string
+	amount            *big.Int
 	// ERC721 options
 	nftContract common.Address
 	tokenURI    string
 	nftName     string
 	nftSymbol   string
+	// CREATE2 churn options
+	churnCount int
+	// Swap options
+	swapPath []common.Address
 }
 
 // WithRecipient sets the recipient address
@@ -161,10 +228,17 @@ func WithRecipient(addr common.Address) BuilderOption {
 	}
 }
 
-// WithFeePayerKey sets the fee payer key for fee delegation
-func WithFeePayerKey(key *ecdsa.PrivateKey) BuilderOption {
+// WithRecipientStrategy sets how transfer builders choose each sender's recipient
+func WithRecipientStrategy(strategy config.RecipientStrategy) BuilderOption {
 	return func(o *builderOptions) {
-		o.feePayerKey = key
+		o.recipientStrategy = strategy
+	}
+}
+
+// WithFeePayer sets the signer of the fee payer for fee delegation
+func WithFeePayer(signer Signer) BuilderOption {
+	return func(o *builderOptions) {
+		o.feePayer = signer
 	}
 }
 
@@ -238,3 +312,17 @@ func WithNFTSymbol(symbol string) BuilderOption {
 		o.nftSymbol = symbol
 	}
 }
+
+// WithChurnCount sets the contracts churned per transaction in CREATE2_CHURN mode
+func WithChurnCount(count int) BuilderOption {
+	return func(o *builderOptions) {
+		o.churnCount = count
+	}
+}
+
+// WithSwapPath routes SWAP mode through the router set with WithContractAddress along path
+func WithSwapPath(path []common.Address) BuilderOption {
+	return func(o *builderOptions) {
+		o.swapPath = path
+	}
+}
@@ -3,7 +3,6 @@ package txbuilder
 import (
 	"bytes"
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 
@@ -11,9 +10,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/schollz/progressbar/v3"
 
 	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/progress"
 )
 
@@ -26,15 +25,16 @@ const (
 // This is a StableNet-specific transaction type where a fee payer pays gas on behalf of the sender
 type FeeDelegationBuilder struct {
 	*BaseBuilder
-	feePayerKey *ecdsa.PrivateKey
-	recipient   common.Address
+	feePayer  Signer
+	recipient common.Address
+	strategy  config.RecipientStrategy
 }
 
 // NewFeeDelegationBuilder creates a new fee delegation builder
-func NewFeeDelegationBuilder(config *BuilderConfig, estimator GasEstimator, feePayerKey *ecdsa.PrivateKey) *FeeDelegationBuilder {
+func NewFeeDelegationBuilder(config *BuilderConfig, estimator GasEstimator, feePayer Signer) *FeeDelegationBuilder {
 	return &FeeDelegationBuilder{
 		BaseBuilder: NewBaseBuilder(config, estimator),
-		feePayerKey: feePayerKey,
+		feePayer:    feePayer,
 	}
 }
 
@@ -44,6 +44,12 @@ func (b *FeeDelegationBuilder) WithRecipient(addr common.Address) *FeeDelegation
 	return b
 }
 
+// WithRecipientStrategy sets how the recipient of each sender is chosen
+func (b *FeeDelegationBuilder) WithRecipientStrategy(strategy config.RecipientStrategy) *FeeDelegationBuilder {
+	b.strategy = strategy
+	return b
+}
+
 // Name returns the builder name
 func (b *FeeDelegationBuilder) Name() string {
 	return string(config.ModeFeeDelegation)
@@ -56,15 +62,15 @@ func (b *FeeDelegationBuilder) EstimateGas(_ context.Context) (uint64, error) {
 }
 
 // Build creates fee delegation transactions for the given accounts
-func (b *FeeDelegationBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, nonces []uint64, count int) ([]*SignedTx, error) {
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no keys provided")
+func (b *FeeDelegationBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
 	}
-	if len(keys) != len(nonces) {
-		return nil, fmt.Errorf("keys and nonces length mismatch: %d vs %d", len(keys), len(nonces))
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch: %d vs %d", len(signers), len(nonces))
 	}
-	if b.feePayerKey == nil {
-		return nil, fmt.Errorf("fee payer key is required for fee delegation")
+	if b.feePayer == nil {
+		return nil, fmt.Errorf("fee payer is required for fee delegation")
 	}
 
 	// Get gas settings
@@ -79,38 +85,42 @@ func (b *FeeDelegationBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateK
 	}
 
 	// Distribute transactions across accounts
-	distribution := DistributeTransactions(len(keys), count)
+	distribution := DistributeTransactions(len(signers), count)
 
 	totalTxs := 0
 	for _, n := range distribution {
 		totalTxs += n
 	}
 
-	fmt.Printf("\nBuilding Fee Delegation Transactions\n\n")
-	fmt.Printf("Fee Payer: %s\n", crypto.PubkeyToAddress(b.feePayerKey.PublicKey).Hex())
-	bar := progressbar.Default(int64(totalTxs), "txs built")
+	console.Printf("\nBuilding Fee Delegation Transactions\n\n")
+	console.Printf("Fee Payer: %s\n", b.feePayer.Address().Hex())
+	bar := progress.New(int64(totalTxs), "txs built")
 
 	signedTxs := make([]*SignedTx, 0, totalTxs)
-	feePayer := crypto.PubkeyToAddress(b.feePayerKey.PublicKey)
+
+	recipients := Recipients(b.strategy, senderAddresses(signers), b.recipient)
+
+	// Determine transfer value (default: 1 wei)
+	value := b.config.Value
+	if value == nil {
+		value = big.NewInt(1)
+	}
 
 	for accountIdx, txCount := range distribution {
-		key := keys[accountIdx]
+		signer := signers[accountIdx]
 		nonce := nonces[accountIdx]
-		from := crypto.PubkeyToAddress(key.PublicKey)
+		from := signer.Address()
 
 		for i := 0; i < txCount; i++ {
-			to := b.recipient
-			if to == (common.Address{}) {
-				to = from
-			}
+			to := recipients[accountIdx]
 
 			// Build and sign fee delegation transaction
 			rawTx, txHash, err := b.buildFeeDelegationTx(
-				key,
-				b.feePayerKey,
+				signer,
+				b.feePayer,
 				nonce,
 				to,
-				big.NewInt(1), // 1 wei
+				value,
 				gasLimit,
 				gasTipCap,
 				gasFeeCap,
@@ -133,16 +143,16 @@ func (b *FeeDelegationBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateK
 		}
 	}
 
-	fmt.Printf("\n[OK] Successfully built %d fee delegation transactions\n", len(signedTxs))
-	fmt.Printf("   Fee Payer: %s\n", feePayer.Hex())
+	console.OKf("\nSuccessfully built %d fee delegation transactions\n", len(signedTxs))
+	console.Printf("   Fee Payer: %s\n", b.feePayer.Address().Hex())
 	return signedTxs, nil
 }
 
 // buildFeeDelegationTx creates a single fee delegation transaction
 // This follows the StableNet FeeDelegateDynamicFeeTx structure (Type 0x16)
 func (b *FeeDelegationBuilder) buildFeeDelegationTx(
-	senderKey *ecdsa.PrivateKey,
-	feePayerKey *ecdsa.PrivateKey,
+	sender Signer,
+	feePayerSigner Signer,
 	nonce uint64,
 	to common.Address,
 	value *big.Int,
@@ -151,7 +161,7 @@ func (b *FeeDelegationBuilder) buildFeeDelegationTx(
 	gasFeeCap *big.Int,
 ) ([]byte, common.Hash, error) {
 	chainID := b.config.ChainID
-	feePayer := crypto.PubkeyToAddress(feePayerKey.PublicKey)
+	feePayer := feePayerSigner.Address()
 
 	// Step 1: Create sender transaction hash (same as EIP-1559)
 	// Hash = keccak256(0x02 || rlp([chainId, nonce, gasTipCap, gasFeeCap, gas, to, value, data, accessList]))
@@ -170,7 +180,7 @@ func (b *FeeDelegationBuilder) buildFeeDelegationTx(
 	senderHash := prefixedRlpHash(0x02, senderTxData)
 
 	// Step 2: Sign sender transaction
-	senderSig, err := crypto.Sign(senderHash[:], senderKey)
+	senderSig, err := sender.SignHash(senderHash[:])
 	if err != nil {
 		return nil, common.Hash{}, fmt.Errorf("failed to sign sender tx: %w", err)
 	}
@@ -204,7 +214,7 @@ func (b *FeeDelegationBuilder) buildFeeDelegationTx(
 	feePayerHash := prefixedRlpHash(FeeDelegationTxType, feePayerHashData)
 
 	// Step 4: Sign fee payer transaction
-	feePayerSig, err := crypto.Sign(feePayerHash[:], feePayerKey)
+	feePayerSig, err := feePayerSigner.SignHash(feePayerHash[:])
 	if err != nil {
 		return nil, common.Hash{}, fmt.Errorf("failed to sign fee payer tx: %w", err)
 	}
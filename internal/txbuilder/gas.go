@@ -0,0 +1,113 @@
+package txbuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// fallbackTipCap is the last resort tip when no estimation method yields one
+var fallbackTipCap = big.NewInt(1000000000) // 1 Gwei
+
+// HeaderSource is implemented by estimators that can return the latest
+// header, whose base fee backs the tip and fee cap fallbacks
+type HeaderSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// FeeBounds limits the tip and fee cap of built transactions. A nil bound is
+// not enforced.
+type FeeBounds struct {
+	MinTipCap *big.Int
+	MaxTipCap *big.Int
+	MinFeeCap *big.Int
+	MaxFeeCap *big.Int
+}
+
+// suggestTipCap estimates the tip, trying in turn eth_maxPriorityFeePerGas,
+// the suggested gas price minus the base fee, the tip floor and finally a
+// fixed 1 Gwei. Zero suggestions count as failures.
+func (b *BaseBuilder) suggestTipCap(ctx context.Context) *big.Int {
+	tip, err := b.estimator.SuggestGasTipCap(ctx)
+	if err == nil && tip.Sign() > 0 {
+		return tip
+	}
+	reason := "returned zero"
+	if err != nil {
+		reason = err.Error()
+	}
+
+	if baseFee := b.baseFee(ctx); baseFee != nil {
+		if price, err := b.estimator.SuggestGasPrice(ctx); err == nil && price.Cmp(baseFee) > 0 {
+			tip = new(big.Int).Sub(price, baseFee)
+			console.Warnf("eth_maxPriorityFeePerGas %s, using gas price minus base fee as tip: %s wei\n", reason, tip)
+			return tip
+		}
+	}
+
+	if b.config.Fees.MinTipCap != nil && b.config.Fees.MinTipCap.Sign() > 0 {
+		console.Warnf("eth_maxPriorityFeePerGas %s, using the tip floor: %s wei\n", reason, b.config.Fees.MinTipCap)
+		return new(big.Int).Set(b.config.Fees.MinTipCap)
+	}
+
+	console.Warnf("eth_maxPriorityFeePerGas %s, using a fallback tip of %s wei\n", reason, fallbackTipCap)
+	return new(big.Int).Set(fallbackTipCap)
+}
+
+// suggestFeeCap estimates the fee cap as twice the suggested gas price,
+// falling back to twice the base fee plus the tip
+func (b *BaseBuilder) suggestFeeCap(ctx context.Context, tip *big.Int) (*big.Int, error) {
+	price, err := b.estimator.SuggestGasPrice(ctx)
+	if err == nil && price.Sign() > 0 {
+		// gasFeeCap = baseFee + gasTipCap (approximate with 2x suggested price)
+		return new(big.Int).Mul(price, big.NewInt(2)), nil
+	}
+	if err == nil {
+		err = errors.New("suggested gas price is zero")
+	}
+
+	baseFee := b.baseFee(ctx)
+	if baseFee == nil {
+		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+	}
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+	if tip != nil {
+		feeCap.Add(feeCap, tip)
+	}
+	console.Warnf("Gas price suggestion failed (%v), using 2x base fee plus tip as fee cap: %s wei\n", err, feeCap)
+	return feeCap, nil
+}
+
+// baseFee returns the latest base fee, or nil when it is unavailable
+func (b *BaseBuilder) baseFee(ctx context.Context) *big.Int {
+	source, ok := b.estimator.(HeaderSource)
+	if !ok {
+		return nil
+	}
+	header, err := source.HeaderByNumber(ctx, nil)
+	if err != nil || header == nil || header.BaseFee == nil {
+		return nil
+	}
+	return header.BaseFee
+}
+
+// clampFee bounds a fee to [min, max], logging when it is changed
+func clampFee(name string, fee, min, max *big.Int) *big.Int {
+	if fee == nil {
+		return nil
+	}
+	if min != nil && fee.Cmp(min) < 0 {
+		console.Warnf("%s %s wei is below the floor, raised to %s wei\n", name, fee, min)
+		return new(big.Int).Set(min)
+	}
+	if max != nil && fee.Cmp(max) > 0 {
+		console.Warnf("%s %s wei is above the ceiling, lowered to %s wei\n", name, fee, max)
+		return new(big.Int).Set(max)
+	}
+	return fee
+}
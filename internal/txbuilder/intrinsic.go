@@ -0,0 +1,24 @@
+package txbuilder
+
+import (
+	"math"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// IntrinsicGas returns the least gas limit a node admits a transaction with:
+// its intrinsic gas under the Istanbul (EIP-2028) and Shanghai (EIP-3860)
+// rules, or the Prague calldata floor (EIP-7623) if that is higher. On chains
+// before Prague the floor only makes calldata-heavy limits err high.
+func IntrinsicGas(tx *types.Transaction) uint64 {
+	gas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.SetCodeAuthorizations(), tx.To() == nil, true, true, true)
+	if err != nil {
+		return math.MaxUint64 // Overflows, so no gas limit covers it
+	}
+	floor, err := core.FloorDataGas(tx.Data())
+	if err != nil {
+		return math.MaxUint64
+	}
+	return max(gas, floor)
+}
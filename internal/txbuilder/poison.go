@@ -0,0 +1,92 @@
+package txbuilder
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// PoisonClass identifies the way a poison transaction is invalid
+type PoisonClass string
+
+const (
+	// PoisonBadSignature is a transaction whose signature has a zero R value
+	PoisonBadSignature PoisonClass = "bad-signature"
+	// PoisonInsufficientBalance is a transaction from an account with no funds
+	PoisonInsufficientBalance PoisonClass = "insufficient-balance"
+	// PoisonIntrinsicGas is a transfer whose gas limit is below the 21000 intrinsic gas
+	PoisonIntrinsicGas PoisonClass = "intrinsic-gas"
+)
+
+// PoisonClasses lists every poison class in the order they are generated
+var PoisonClasses = []PoisonClass{PoisonBadSignature, PoisonInsufficientBalance, PoisonIntrinsicGas}
+
+// PoisonTx is a deliberately invalid transaction the node is expected to reject
+type PoisonTx struct {
+	*SignedTx
+	Class PoisonClass
+}
+
+// BuildPoison creates count invalid transactions, cycling through the poison classes.
+// Each transaction is signed by a fresh throwaway key so it never shares a nonce
+// with the valid load.
+func BuildPoison(chainID, gasPrice *big.Int, count int) ([]*PoisonTx, error) {
+	signer := types.NewLondonSigner(chainID)
+	poison := make([]*PoisonTx, 0, count)
+
+	for i := 0; i < count; i++ {
+		class := PoisonClasses[i%len(PoisonClasses)]
+
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate poison key: %w", err)
+		}
+		from := crypto.PubkeyToAddress(key.PublicKey)
+
+		gas := uint64(21000)
+		if class == PoisonIntrinsicGas {
+			gas = 20000
+		}
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    0,
+			GasPrice: gasPrice,
+			Gas:      gas,
+			To:       &from,
+			Value:    big.NewInt(1),
+		})
+
+		sig, err := crypto.Sign(signer.Hash(tx).Bytes(), key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign poison transaction: %w", err)
+		}
+		if class == PoisonBadSignature {
+			// Keep the recovery id but zero R so the sender cannot be recovered
+			clear(sig[:32])
+		}
+		signedTx, err := tx.WithSignature(signer, sig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign poison transaction: %w", err)
+		}
+
+		rawTx, err := signedTx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode poison transaction: %w", err)
+		}
+
+		poison = append(poison, &PoisonTx{
+			SignedTx: &SignedTx{
+				Tx:       signedTx,
+				RawTx:    rawTx,
+				Hash:     signedTx.Hash(),
+				From:     from,
+				Nonce:    0,
+				GasLimit: gas,
+			},
+			Class: class,
+		})
+	}
+
+	return poison, nil
+}
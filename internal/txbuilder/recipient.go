@@ -0,0 +1,40 @@
+package txbuilder
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xmhha/txhammer/internal/config"
+)
+
+// senderAddresses returns the address of each signer
+func senderAddresses(signers []Signer) []common.Address {
+	addrs := make([]common.Address, len(signers))
+	for i, signer := range signers {
+		addrs[i] = signer.Address()
+	}
+	return addrs
+}
+
+// Recipients returns the recipient of each sender under the strategy. An
+// empty strategy sends to fixed if it is set and to self otherwise.
+func Recipients(strategy config.RecipientStrategy, senders []common.Address, fixed common.Address) []common.Address {
+	if strategy == "" {
+		strategy = config.RecipientSelf
+		if fixed != (common.Address{}) {
+			strategy = config.RecipientFixed
+		}
+	}
+
+	recipients := make([]common.Address, len(senders))
+	for i, sender := range senders {
+		switch strategy {
+		case config.RecipientFixed:
+			recipients[i] = fixed
+		case config.RecipientRing:
+			recipients[i] = senders[(i+1)%len(senders)]
+		default:
+			recipients[i] = sender
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,59 @@
+package txbuilder
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xmhha/txhammer/internal/config"
+)
+
+// Options are the run settings passed to a custom builder
+type Options struct {
+	Contract common.Address // --contract (zero when not set)
+	Method   string         // --method
+	Value    *big.Int       // --value in wei
+}
+
+// BuilderFactory creates the builder of a custom workload mode
+type BuilderFactory interface {
+	// GasLimit returns the mode's default gas limit per transaction, used to
+	// fund sub-accounts when --gas-limit is not set
+	GasLimit() uint64
+	// New creates the builder for one run
+	New(cfg *BuilderConfig, estimator GasEstimator, opts Options) (Builder, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[config.Mode]BuilderFactory)
+)
+
+// Register adds a custom workload mode built by factory, so it can be
+// compiled in without changing the Factory. It is meant to be called from an
+// init function and panics when the name is empty, built in or already
+// registered, like database/sql.Register.
+func Register(name string, factory BuilderFactory) {
+	if factory == nil {
+		panic("txbuilder: Register factory is nil")
+	}
+	mode := config.Mode(strings.ToUpper(strings.TrimSpace(name)))
+	if err := config.RegisterMode(mode, factory.GasLimit()); err != nil {
+		panic(fmt.Sprintf("txbuilder: %v", err))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[mode] = factory
+}
+
+// registered returns the factory of a custom mode
+func registered(mode config.Mode) (BuilderFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[mode]
+	return factory, ok
+}
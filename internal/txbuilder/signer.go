@@ -0,0 +1,69 @@
+package txbuilder
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs for one account. Builders only see this interface, so
+// remote signing services, KMS/HSM keys (AWS KMS, GCP KMS) or threshold
+// signers can stand in for a local private key.
+type Signer interface {
+	// Address returns the account the signer signs for
+	Address() common.Address
+	// SignHash signs a 32-byte digest and returns the 65-byte [R || S || V]
+	// signature with V as 0 or 1
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// KeySigner signs with a private key held in memory
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner creates a signer for a private key
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// KeySigners creates a signer for each private key
+func KeySigners(keys []*ecdsa.PrivateKey) []Signer {
+	signers := make([]Signer, len(keys))
+	for i, key := range keys {
+		signers[i] = NewKeySigner(key)
+	}
+	return signers
+}
+
+// Address returns the address of the key
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignHash signs the digest with the key
+func (s *KeySigner) SignHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+// SignTransaction signs a transaction for the London signer of the chain
+func SignTransaction(tx *types.Transaction, chainID *big.Int, s Signer) (*types.Transaction, error) {
+	signer := types.NewLondonSigner(chainID)
+	sig, err := s.SignHash(signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	signed, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from %s: %w", s.Address().Hex(), err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,278 @@
+package txbuilder
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+	"github.com/0xmhha/txhammer/internal/util/progress"
+)
+
+// SwapPairBytecode deploys a hand-assembled constant-product pair for SWAP mode.
+//
+// The constructor seeds both reserves (slots 0 and 1) with 10^24. swap(uint256
+// amountIn, bool zeroForOne) prices the output like a Uniswap V2 pair with the
+// 0.3% fee, writes both reserves and the caller's output balance (slot
+// caller + side<<160), and emits the Uniswap V2 Swap and Sync events. The
+// pair holds no real tokens: the input is credited without a transfer.
+// getReserves() returns both reserves. Any other calldata, or a swap whose
+// output rounds to zero, reverts.
+const SwapPairBytecode = "0x69d3c21bcecceda100000060005569d3c21bcecceda10000006001556100dd61002b6000396100dd6000f360003560e01c80632aea66051461003157630902f1ac1461001f57600080fd5b60005460005260015460205260406000f35b5060243515801560043582548254826103e50280820281846103e80201900490508061005c57600080fd5b838301865580820385558460a01b3301805482019055838660051b52808560051b6040015233337fd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d82260806000a36000546000526001546020527f1c411e9a96e071241c2f21f7726b17ae89e3cab4c78be50e062b03a9fffbbad160406000a100"
+
+var (
+	// SwapTopic is the topic of the Uniswap V2 Swap(address,uint256,uint256,uint256,uint256,address) event
+	SwapTopic = crypto.Keccak256Hash([]byte("Swap(address,uint256,uint256,uint256,uint256,address)"))
+
+	// SyncTopic is the topic of the Uniswap V2 Sync(uint112,uint112) event
+	SyncTopic = crypto.Keccak256Hash([]byte("Sync(uint112,uint112)"))
+
+	// pairSwapSelector is the selector of the embedded pair's swap(uint256,bool)
+	pairSwapSelector = crypto.Keccak256([]byte("swap(uint256,bool)"))[:4]
+
+	// routerSwapSelector is the selector of the Uniswap V2 router's
+	// swapExactTokensForTokens(uint256,uint256,address[],address,uint256)
+	routerSwapSelector = crypto.Keccak256([]byte("swapExactTokensForTokens(uint256,uint256,address[],address,uint256)"))[:4]
+
+	// DefaultSwapAmount is the input amount of each swap, in token base units
+	DefaultSwapAmount = big.NewInt(1_000_000_000_000_000)
+
+	// maxDeadline never expires, so router swaps do not depend on block time
+	maxDeadline = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+)
+
+// SwapBuilder builds token swaps, either against the embedded pair or
+// through a Uniswap V2 router. Each sender alternates the swap direction, so
+// reserves stay balanced over a run.
+type SwapBuilder struct {
+	*BaseBuilder
+	pair   common.Address
+	router common.Address
+	path   []common.Address
+	amount *big.Int
+}
+
+// NewSwapBuilder creates a new swap builder
+func NewSwapBuilder(config *BuilderConfig, estimator GasEstimator) *SwapBuilder {
+	return &SwapBuilder{
+		BaseBuilder: NewBaseBuilder(config, estimator),
+		amount:      DefaultSwapAmount,
+	}
+}
+
+// WithPair sets the address of an already deployed embedded pair
+func (b *SwapBuilder) WithPair(addr common.Address) *SwapBuilder {
+	b.pair = addr
+	return b
+}
+
+// WithRouter swaps through a Uniswap V2 router along path instead of the
+// embedded pair. Odd transactions of each sender swap along the reversed path.
+func (b *SwapBuilder) WithRouter(router common.Address, path []common.Address) *SwapBuilder {
+	b.router = router
+	b.path = path
+	return b
+}
+
+// WithAmount sets the input amount of each swap
+func (b *SwapBuilder) WithAmount(amount *big.Int) *SwapBuilder {
+	if amount != nil && amount.Sign() > 0 {
+		b.amount = amount
+	}
+	return b
+}
+
+// Pair returns the embedded pair address
+func (b *SwapBuilder) Pair() common.Address {
+	return b.pair
+}
+
+// Router returns the router address, or the zero address when swapping against the embedded pair
+func (b *SwapBuilder) Router() common.Address {
+	return b.router
+}
+
+// Name returns the builder name
+func (b *SwapBuilder) Name() string {
+	return "SWAP"
+}
+
+// EstimateGas estimates gas for one swap
+func (b *SwapBuilder) EstimateGas(_ context.Context) (uint64, error) {
+	return 150000, nil
+}
+
+// GetDeployTransaction returns the signed pair deployment transaction
+func (b *SwapBuilder) GetDeployTransaction(ctx context.Context, signer Signer, nonce uint64) (*SignedTx, error) {
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := uint64(300000)
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   b.config.ChainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       gasLimit,
+		To:        nil, // Contract creation
+		Value:     big.NewInt(0),
+		Data:      common.FromHex(SwapPairBytecode),
+	})
+
+	signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign deployment transaction: %w", err)
+	}
+
+	rawTx, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	return &SignedTx{
+		Tx:       signedTx,
+		RawTx:    rawTx,
+		Hash:     signedTx.Hash(),
+		From:     signer.Address(),
+		Nonce:    nonce,
+		GasLimit: gasLimit,
+	}, nil
+}
+
+// PairSwapCallData packs swap(amountIn, zeroForOne) for the embedded pair
+func PairSwapCallData(amountIn *big.Int, zeroForOne bool) []byte {
+	data := make([]byte, 0, 4+64)
+	data = append(data, pairSwapSelector...)
+	data = append(data, common.LeftPadBytes(amountIn.Bytes(), 32)...)
+	direction := make([]byte, 32)
+	if zeroForOne {
+		direction[31] = 1
+	}
+	return append(data, direction...)
+}
+
+// RouterSwapCallData packs swapExactTokensForTokens(amountIn, 0, path, to, deadline)
+func RouterSwapCallData(amountIn *big.Int, path []common.Address, to common.Address, deadline *big.Int) []byte {
+	data := make([]byte, 0, 4+32*(6+len(path)))
+	data = append(data, routerSwapSelector...)
+	data = append(data, common.LeftPadBytes(amountIn.Bytes(), 32)...)
+	data = append(data, make([]byte, 32)...)                                  // amountOutMin
+	data = append(data, common.LeftPadBytes(big.NewInt(5*32).Bytes(), 32)...) // Offset of path
+	data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(deadline.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(path))).Bytes(), 32)...)
+	for _, addr := range path {
+		data = append(data, common.LeftPadBytes(addr.Bytes(), 32)...)
+	}
+	return data
+}
+
+// callData returns the target and calldata of one swap; forward selects the
+// direction
+func (b *SwapBuilder) callData(from common.Address, forward bool) (common.Address, []byte) {
+	if b.router == (common.Address{}) {
+		return b.pair, PairSwapCallData(b.amount, forward)
+	}
+	path := b.path
+	if !forward {
+		path = make([]common.Address, len(b.path))
+		for i, addr := range b.path {
+			path[len(path)-1-i] = addr
+		}
+	}
+	return b.router, RouterSwapCallData(b.amount, path, from, maxDeadline)
+}
+
+// Build creates swap transactions
+func (b *SwapBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
+	}
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch")
+	}
+	if b.pair == (common.Address{}) && b.router == (common.Address{}) {
+		return nil, fmt.Errorf("swap pair or router address is required")
+	}
+	if b.router != (common.Address{}) && len(b.path) < 2 {
+		return nil, fmt.Errorf("swap path needs at least two tokens")
+	}
+
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := b.config.GasLimit
+	if gasLimit == 0 {
+		gasLimit, _ = b.EstimateGas(ctx)
+	}
+
+	distribution := DistributeTransactions(len(signers), count)
+
+	totalTxs := 0
+	for _, n := range distribution {
+		totalTxs += n
+	}
+
+	console.Printf("\nBuilding Swap Transactions\n\n")
+	if b.router != (common.Address{}) {
+		console.Printf("Router: %s (%d-token path)\n", b.router.Hex(), len(b.path))
+	} else {
+		console.Printf("Pair: %s\n", b.pair.Hex())
+	}
+	bar := progress.New(int64(totalTxs), "txs built")
+
+	signedTxs := make([]*SignedTx, 0, totalTxs)
+
+	for accountIdx, txCount := range distribution {
+		signer := signers[accountIdx]
+		nonce := nonces[accountIdx]
+		from := signer.Address()
+
+		for i := 0; i < txCount; i++ {
+			to, data := b.callData(from, i%2 == 0)
+			tx := types.NewTx(&types.DynamicFeeTx{
+				ChainID:   b.config.ChainID,
+				Nonce:     nonce,
+				GasTipCap: gasTipCap,
+				GasFeeCap: gasFeeCap,
+				Gas:       gasLimit,
+				To:        &to,
+				Value:     big.NewInt(0),
+				Data:      data,
+			})
+
+			signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign transaction: %w", err)
+			}
+
+			rawTx, err := signedTx.MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+			}
+
+			signedTxs = append(signedTxs, &SignedTx{
+				Tx:       signedTx,
+				RawTx:    rawTx,
+				Hash:     signedTx.Hash(),
+				From:     from,
+				Nonce:    nonce,
+				GasLimit: gasLimit,
+			})
+
+			nonce++
+			progress.Add(bar, 1)
+		}
+	}
+
+	console.OKf("\nSuccessfully built %d swap transactions\n", len(signedTxs))
+	return signedTxs, nil
+}
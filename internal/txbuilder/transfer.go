@@ -2,23 +2,21 @@ package txbuilder
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/schollz/progressbar/v3"
 
 	"github.com/0xmhha/txhammer/internal/config"
+	"github.com/0xmhha/txhammer/internal/util/console"
 	"github.com/0xmhha/txhammer/internal/util/progress"
 )
 
-// TransferBuilder builds simple native coin transfer transactions (EIP-1559)
+// TransferBuilder builds simple native coin transfer transactions (legacy unless --tx-type says otherwise)
 type TransferBuilder struct {
 	*BaseBuilder
 	recipient common.Address // If zero, transfers to self
+	strategy  config.RecipientStrategy
 }
 
 // NewTransferBuilder creates a new transfer builder
@@ -34,6 +32,12 @@ func (b *TransferBuilder) WithRecipient(addr common.Address) *TransferBuilder {
 	return b
 }
 
+// WithRecipientStrategy sets how the recipient of each sender is chosen
+func (b *TransferBuilder) WithRecipientStrategy(strategy config.RecipientStrategy) *TransferBuilder {
+	b.strategy = strategy
+	return b
+}
+
 // Name returns the builder name
 func (b *TransferBuilder) Name() string {
 	return string(config.ModeTransfer)
@@ -46,16 +50,15 @@ func (b *TransferBuilder) EstimateGas(_ context.Context) (uint64, error) {
 }
 
 // Build creates transfer transactions for the given accounts
-func (b *TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, nonces []uint64, count int) ([]*SignedTx, error) {
-	if len(keys) == 0 {
-		return nil, fmt.Errorf("no keys provided")
+func (b *TransferBuilder) Build(ctx context.Context, signers []Signer, nonces []uint64, count int) ([]*SignedTx, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no signers provided")
 	}
-	if len(keys) != len(nonces) {
-		return nil, fmt.Errorf("keys and nonces length mismatch: %d vs %d", len(keys), len(nonces))
+	if len(signers) != len(nonces) {
+		return nil, fmt.Errorf("signers and nonces length mismatch: %d vs %d", len(signers), len(nonces))
 	}
 
-	// Get gas settings (only need gasFeeCap for legacy transactions)
-	_, gasFeeCap, err := b.GetGasSettings(ctx)
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +70,7 @@ func (b *TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, n
 	}
 
 	// Distribute transactions across accounts
-	distribution := DistributeTransactions(len(keys), count)
+	distribution := DistributeTransactions(len(signers), count)
 
 	// Calculate total transactions
 	totalTxs := 0
@@ -75,23 +78,21 @@ func (b *TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, n
 		totalTxs += n
 	}
 
-	fmt.Printf("\nBuilding Transfer Transactions\n\n")
-	bar := progressbar.Default(int64(totalTxs), "txs built")
+	recipients := Recipients(b.strategy, senderAddresses(signers), b.recipient)
+
+	console.Printf("\nBuilding Transfer Transactions\n\n")
+	bar := progress.New(int64(totalTxs), "txs built")
 
 	signedTxs := make([]*SignedTx, 0, totalTxs)
 
 	// Build transactions for each account
 	for accountIdx, txCount := range distribution {
-		key := keys[accountIdx]
+		signer := signers[accountIdx]
 		nonce := nonces[accountIdx]
-		from := crypto.PubkeyToAddress(key.PublicKey)
+		from := signer.Address()
 
 		for i := 0; i < txCount; i++ {
-			// Determine recipient (self-transfer if not specified)
-			to := b.recipient
-			if to == (common.Address{}) {
-				to = from
-			}
+			to := recipients[accountIdx]
 
 			// Determine transfer value (default: 1 wei)
 			value := b.config.Value
@@ -99,18 +100,18 @@ func (b *TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, n
 				value = big.NewInt(1)
 			}
 
-			// Create legacy transaction (type 0) for better compatibility
-			tx := types.NewTx(&types.LegacyTx{
-				Nonce:    nonce,
-				GasPrice: gasFeeCap, // Use gasFeeCap as legacy gas price
-				Gas:      gasLimit,
-				To:       &to,
-				Value:    value,
-				Data:     nil,
+			// Legacy transactions (type 0) by default for better compatibility
+			tx := b.newTx(config.TxTypeLegacy, &TxRequest{
+				To:        &to,
+				Value:     value,
+				Nonce:     nonce,
+				Gas:       gasLimit,
+				GasTipCap: gasTipCap,
+				GasFeeCap: gasFeeCap,
 			})
 
 			// Sign the transaction
-			signedTx, err := SignTransaction(tx, b.config.ChainID, key)
+			signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
 			if err != nil {
 				return nil, fmt.Errorf("failed to sign transaction: %w", err)
 			}
@@ -135,19 +136,19 @@ func (b *TransferBuilder) Build(ctx context.Context, keys []*ecdsa.PrivateKey, n
 		}
 	}
 
-	fmt.Printf("\n[OK] Successfully built %d transactions\n", len(signedTxs))
+	console.OKf("\nSuccessfully built %d transactions\n", len(signedTxs))
 	return signedTxs, nil
 }
 
 // BuildSingle creates a single transfer transaction
 func (b *TransferBuilder) BuildSingle(
 	ctx context.Context,
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	nonce uint64,
 	to common.Address,
 	value *big.Int,
 ) (*SignedTx, error) {
-	_, gasFeeCap, err := b.GetGasSettings(ctx)
+	gasTipCap, gasFeeCap, err := b.GetGasSettings(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -157,18 +158,18 @@ func (b *TransferBuilder) BuildSingle(
 		gasLimit = 21000
 	}
 
-	from := crypto.PubkeyToAddress(key.PublicKey)
+	from := signer.Address()
 
-	tx := types.NewTx(&types.LegacyTx{
-		Nonce:    nonce,
-		GasPrice: gasFeeCap, // Use gasFeeCap as legacy gas price
-		Gas:      gasLimit,
-		To:       &to,
-		Value:    value,
-		Data:     nil,
+	tx := b.newTx(config.TxTypeLegacy, &TxRequest{
+		To:        &to,
+		Value:     value,
+		Nonce:     nonce,
+		Gas:       gasLimit,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
 	})
 
-	signedTx, err := SignTransaction(tx, b.config.ChainID, key)
+	signedTx, err := SignTransaction(tx, b.config.ChainID, signer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/config"
 )
 
 // TxType represents the transaction type
@@ -88,7 +90,9 @@ type BuilderConfig struct {
 	GasPrice  *big.Int
 	GasTipCap *big.Int
 	GasFeeCap *big.Int
-	Value     *big.Int // Transfer value (default: 1 wei)
+	Value     *big.Int // Value of transfers, fee delegation and contract calls (default: 1 wei, 0 for calls)
+	Fees      FeeBounds
+	TxType    config.TxType // Envelope of transfers, contract calls and ERC20 transfers ("" = the builder's default)
 }
 
 // ContractCallRequest represents a contract call request
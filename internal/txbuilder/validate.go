@@ -0,0 +1,116 @@
+package txbuilder
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// IssueKind identifies why a built transaction should not be sent
+type IssueKind string
+
+const (
+	// IssueDuplicate is a transaction whose hash appears earlier in the set
+	IssueDuplicate IssueKind = "duplicate"
+	// IssueNonceConflict is a different transaction for a sender and nonce used earlier in the set
+	IssueNonceConflict IssueKind = "nonce-conflict"
+	// IssueChainID is a transaction signed for another chain
+	IssueChainID IssueKind = "chain-id"
+	// IssueIntrinsicGas is a transaction whose gas limit is below its intrinsic gas
+	IssueIntrinsicGas IssueKind = "intrinsic-gas"
+)
+
+// IssueKinds lists every issue kind in the order they are checked
+var IssueKinds = []IssueKind{IssueDuplicate, IssueNonceConflict, IssueChainID, IssueIntrinsicGas}
+
+// TxIssue is a transaction found invalid by ValidateTxs
+type TxIssue struct {
+	Kind   IssueKind
+	Index  int // Position in the validated set
+	Tx     *SignedTx
+	Detail string
+}
+
+// senderNonce identifies a nonce slot of one sender
+type senderNonce struct {
+	from  common.Address
+	nonce uint64
+}
+
+// ValidateTxs checks a transaction set before sending for duplicate hashes,
+// several transactions for the same sender and nonce, chain ID mismatches,
+// and gas limits below the intrinsic gas. The first transaction of a hash or
+// nonce slot is kept; the later ones are reported. Transactions without a
+// decoded types.Transaction (fee delegation) are not checked for their chain
+// ID or intrinsic gas.
+func ValidateTxs(txs []*SignedTx, chainID *big.Int) []*TxIssue {
+	var issues []*TxIssue
+	hashes := make(map[common.Hash]int, len(txs))
+	slots := make(map[senderNonce]int, len(txs))
+
+	for i, tx := range txs {
+		if first, ok := hashes[tx.Hash]; ok {
+			issues = append(issues, &TxIssue{
+				Kind:   IssueDuplicate,
+				Index:  i,
+				Tx:     tx,
+				Detail: fmt.Sprintf("same hash as tx #%d", first),
+			})
+			continue
+		}
+		hashes[tx.Hash] = i
+
+		slot := senderNonce{from: tx.From, nonce: tx.Nonce}
+		if first, ok := slots[slot]; ok {
+			issues = append(issues, &TxIssue{
+				Kind:   IssueNonceConflict,
+				Index:  i,
+				Tx:     tx,
+				Detail: fmt.Sprintf("nonce %d of %s already used by tx #%d", tx.Nonce, tx.From.Hex(), first),
+			})
+			continue
+		}
+		slots[slot] = i
+
+		if chainID != nil && tx.Tx != nil && tx.Tx.Protected() && tx.Tx.ChainId().Cmp(chainID) != 0 {
+			issues = append(issues, &TxIssue{
+				Kind:   IssueChainID,
+				Index:  i,
+				Tx:     tx,
+				Detail: fmt.Sprintf("signed for chain %s, not %s", tx.Tx.ChainId(), chainID),
+			})
+			continue
+		}
+
+		if tx.Tx != nil {
+			if need := IntrinsicGas(tx.Tx); tx.Tx.Gas() < need {
+				issues = append(issues, &TxIssue{
+					Kind:   IssueIntrinsicGas,
+					Index:  i,
+					Tx:     tx,
+					Detail: fmt.Sprintf("gas limit %d is below the intrinsic gas of %d (%d bytes of data)", tx.Tx.Gas(), need, len(tx.Tx.Data())),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// PruneTxs returns txs without the transactions reported in issues
+func PruneTxs(txs []*SignedTx, issues []*TxIssue) []*SignedTx {
+	if len(issues) == 0 {
+		return txs
+	}
+	drop := make(map[int]bool, len(issues))
+	for _, issue := range issues {
+		drop[issue.Index] = true
+	}
+	kept := make([]*SignedTx, 0, len(txs)-len(drop))
+	for i, tx := range txs {
+		if !drop[i] {
+			kept = append(kept, tx)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,446 @@
+package txchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Tester measures how inclusion latency grows along a chain of transfers in
+// which each sender is funded only by the previous transfer, against the same
+// number of independent transfers
+type Tester struct {
+	client Client
+	config *Config
+}
+
+// New creates a new Tester instance
+func New(client Client, config *Config) *Tester {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Tester{
+		client: client,
+		config: config,
+	}
+}
+
+// linkCost returns the wei one link pays for gas
+func (t *Tester) linkCost() *big.Int {
+	return new(big.Int).Mul(t.config.GasPrice, new(big.Int).SetUint64(t.config.GasLimit))
+}
+
+// Cost returns the wei the chain burns in gas, all of it paid by the master:
+// the first link carries exactly the gas of the links after it
+func (t *Tester) Cost() *big.Int {
+	return new(big.Int).Mul(t.linkCost(), big.NewInt(int64(t.config.Length)))
+}
+
+// Run sends the independent transfers from the sub-accounts, waits for them,
+// and then sends the chain from the master account through fresh accounts
+// back to the master
+func (t *Tester) Run(ctx context.Context, master txbuilder.Signer, keys []*ecdsa.PrivateKey) (*Result, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys provided")
+	}
+	if t.config.Length <= 0 {
+		return nil, fmt.Errorf("chain length must be positive")
+	}
+
+	result := &Result{}
+	var err error
+	console.Printf("\nSending %d independent transfers...\n", t.config.Length)
+	if result.Independent, err = t.runIndependent(ctx, keys); err != nil {
+		return result, err
+	}
+	console.Printf("Sending a chain of %d transfers...\n", t.config.Length)
+	if result.Chain, err = t.runChain(ctx, master); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// buildChain signs every link up front. Link i sends from account i to
+// account i+1, where account 0 and the last recipient are the master and the
+// rest are fresh keys; each link carries the gas of the links after it.
+func (t *Tester) buildChain(ctx context.Context, master txbuilder.Signer) ([]*Link, [][]byte, error) {
+	n := t.config.Length
+	signers := make([]txbuilder.Signer, n+1)
+	signers[0], signers[n] = master, master
+	for i := 1; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate chain account: %w", err)
+		}
+		signers[i] = txbuilder.NewKeySigner(key)
+	}
+
+	masterNonce, err := t.client.PendingNonceAt(ctx, master.Address())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get nonce of %s: %w", master.Address().Hex(), err)
+	}
+
+	cost := t.linkCost()
+	links := make([]*Link, n)
+	raws := make([][]byte, n)
+	for i := range links {
+		nonce := uint64(0)
+		if i == 0 {
+			nonce = masterNonce
+		}
+		to := signers[i+1].Address()
+		value := new(big.Int).Mul(cost, big.NewInt(int64(n-1-i)))
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: t.config.GasPrice,
+			Gas:      t.config.GasLimit,
+			To:       &to,
+			Value:    value,
+		})
+		signedTx, err := txbuilder.SignTransaction(tx, t.config.ChainID, signers[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to sign link %d: %w", i, err)
+		}
+		if raws[i], err = signedTx.MarshalBinary(); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode link %d: %w", i, err)
+		}
+		links[i] = &Link{Index: i, Hash: signedTx.Hash(), From: signers[i].Address(), To: to}
+	}
+	return links, raws, nil
+}
+
+// runChain sends each link as soon as the node accepts it, retrying refused
+// links every poll interval, and records when each link is mined. It stops
+// when every link is mined or nothing moved for the stall timeout.
+func (t *Tester) runChain(ctx context.Context, master txbuilder.Signer) (*ChainResult, error) {
+	links, raws, err := t.buildChain(ctx, master)
+	if err != nil {
+		return nil, err
+	}
+	result := &ChainResult{Length: len(links)}
+
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	lastProgress := start
+	next := 0
+	for result.Mined < len(links) {
+		progress := false
+		for next < len(links) {
+			if _, err := t.client.SendRawTransaction(ctx, raws[next]); err != nil {
+				links[next].Deferred++
+				result.Deferred++
+				result.LastError = err.Error()
+				break
+			}
+			links[next].Sent = time.Since(start)
+			next++
+			progress = true
+		}
+
+		for _, link := range links[:next] {
+			if link.Mined != 0 {
+				continue
+			}
+			receipt, err := t.client.TransactionReceipt(ctx, link.Hash)
+			if err != nil || receipt == nil {
+				continue
+			}
+			link.Mined = time.Since(start)
+			link.Reverted = receipt.Status != types.ReceiptStatusSuccessful
+			if receipt.BlockNumber != nil {
+				link.Block = receipt.BlockNumber.Uint64()
+			}
+			result.Mined++
+			progress = true
+		}
+		if result.Mined == len(links) {
+			break
+		}
+
+		if progress {
+			lastProgress = time.Now()
+		} else if time.Since(lastProgress) > t.config.StallTimeout {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if next == len(links) {
+		result.LastError = ""
+	}
+	result.Links = links[:next]
+	SummarizeChain(result)
+	return result, ctx.Err()
+}
+
+// SummarizeChain fills in the timing of a chain from its mined links
+func SummarizeChain(result *ChainResult) {
+	var xs, ys []float64
+	var total time.Duration
+	for _, link := range result.Links {
+		if link.Mined == 0 {
+			continue
+		}
+		result.Duration = max(result.Duration, link.Mined)
+		total += link.Latency()
+		if result.FirstBlock == 0 || link.Block < result.FirstBlock {
+			result.FirstBlock = link.Block
+		}
+		result.LastBlock = max(result.LastBlock, link.Block)
+		xs = append(xs, float64(link.Index))
+		ys = append(ys, link.Mined.Seconds())
+	}
+	if len(xs) == 0 {
+		return
+	}
+	result.AvgLatency = total / time.Duration(len(xs))
+	result.PerLink = time.Duration(slope(xs, ys) * float64(time.Second))
+}
+
+// slope returns the least-squares slope of ys over xs
+func slope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
+// sentTx is an accepted transfer waiting for its receipt
+type sentTx struct {
+	hash   common.Hash
+	sentAt time.Time
+}
+
+// runIndependent sends Length zero-value self-transfers spread over the keys,
+// each sender in nonce order, and waits for their receipts
+func (t *Tester) runIndependent(ctx context.Context, keys []*ecdsa.PrivateKey) (*IndependentResult, error) {
+	result := &IndependentResult{}
+	start := time.Now()
+
+	perKey := make([][]sentTx, len(keys))
+	errs := make([]error, len(keys))
+	rejected := make([]int, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		count := t.config.Length / len(keys)
+		if i < t.config.Length%len(keys) {
+			count++
+		}
+		if count == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, key *ecdsa.PrivateKey, count int) {
+			defer wg.Done()
+			perKey[i], rejected[i], errs[i] = t.sendTransfers(ctx, key, count)
+		}(i, key, count)
+	}
+	wg.Wait()
+
+	var sent []sentTx
+	for i := range keys {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		sent = append(sent, perKey[i]...)
+		result.Rejected += rejected[i]
+	}
+	result.Sent = len(sent)
+
+	latencies, last := t.waitReceipts(ctx, sent, result)
+	if len(latencies) > 0 {
+		result.Duration = last.Sub(start)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		var total time.Duration
+		for _, l := range latencies {
+			total += l
+		}
+		result.AvgLatency = total / time.Duration(len(latencies))
+		result.P95Latency = latencies[(len(latencies)-1)*95/100]
+		result.MaxLatency = latencies[len(latencies)-1]
+	}
+	return result, ctx.Err()
+}
+
+// sendTransfers sends count self-transfers from one account in nonce order
+func (t *Tester) sendTransfers(ctx context.Context, key *ecdsa.PrivateKey, count int) ([]sentTx, int, error) {
+	signer := txbuilder.NewKeySigner(key)
+	from := signer.Address()
+	nonce, err := t.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get nonce of %s: %w", from.Hex(), err)
+	}
+
+	sent := make([]sentTx, 0, count)
+	rejected := 0
+	for i := 0; i < count && ctx.Err() == nil; i++ {
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: t.config.GasPrice,
+			Gas:      t.config.GasLimit,
+			To:       &from,
+			Value:    big.NewInt(0),
+		})
+		signedTx, err := txbuilder.SignTransaction(tx, t.config.ChainID, signer)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to sign transfer: %w", err)
+		}
+		raw, err := signedTx.MarshalBinary()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode transfer: %w", err)
+		}
+
+		sentAt := time.Now()
+		if _, err := t.client.SendRawTransaction(ctx, raw); err != nil {
+			// Later nonces would only queue behind the gap
+			rejected += count - i
+			break
+		}
+		sent = append(sent, sentTx{hash: signedTx.Hash(), sentAt: sentAt})
+		nonce++
+	}
+	return sent, rejected, nil
+}
+
+// waitReceipts polls the receipts of sent until all are mined or the stall
+// timeout elapses. It returns the latency of each mined transfer and when the
+// last receipt was seen.
+func (t *Tester) waitReceipts(ctx context.Context, sent []sentTx, result *IndependentResult) ([]time.Duration, time.Time) {
+	waitCtx, cancel := context.WithTimeout(ctx, t.config.StallTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+
+	latencies := make([]time.Duration, 0, len(sent))
+	var last time.Time
+	pending := sent
+	for len(pending) > 0 {
+		waiting := pending[:0]
+		for _, tx := range pending {
+			receipt, err := t.client.TransactionReceipt(waitCtx, tx.hash)
+			if err != nil || receipt == nil {
+				waiting = append(waiting, tx)
+				continue
+			}
+			last = time.Now()
+			latencies = append(latencies, last.Sub(tx.sentAt))
+			result.Mined++
+		}
+		pending = waiting
+		if len(pending) == 0 {
+			break
+		}
+
+		select {
+		case <-waitCtx.Done():
+			result.Pending = len(pending)
+			return latencies, last
+		case <-ticker.C:
+		}
+	}
+	return latencies, last
+}
+
+// PrintResult prints the independent transfers against the chain and how
+// inclusion time grew along the chain
+func PrintResult(result *Result) {
+	console.Summaryf("\nChained Transfer Results\n\n")
+	ind, chain := result.Independent, result.Chain
+	console.Summaryf("  %-14s %14s %14s\n", "", "independent", "chain")
+	row := func(label string, independent, chained func() string) {
+		a, b := "-", "-"
+		if ind != nil {
+			a = independent()
+		}
+		if chain != nil {
+			b = chained()
+		}
+		console.Summaryf("  %-14s %14s %14s\n", label, a, b)
+	}
+	row("Sent:", func() string { return fmt.Sprint(ind.Sent) }, func() string { return fmt.Sprint(len(chain.Links)) })
+	row("Mined:", func() string { return fmt.Sprint(ind.Mined) }, func() string { return fmt.Sprint(chain.Mined) })
+	row("Duration:", func() string { return ind.Duration.Round(time.Millisecond).String() },
+		func() string { return chain.Duration.Round(time.Millisecond).String() })
+	row("Avg latency:", func() string { return ind.AvgLatency.Round(time.Millisecond).String() },
+		func() string { return chain.AvgLatency.Round(time.Millisecond).String() })
+
+	if chain == nil {
+		return
+	}
+	console.Summaryf("\n  Time per link:      %s\n", chain.PerLink.Round(time.Millisecond))
+	if chain.Mined > 0 {
+		console.Summaryf("  Blocks spanned:     %d (%d to %d)\n", chain.LastBlock-chain.FirstBlock+1, chain.FirstBlock, chain.LastBlock)
+	}
+	console.Summaryf("  Deferred sends:     %d\n", chain.Deferred)
+	if slowdown := result.Slowdown(); slowdown > 0 {
+		console.Summaryf("  Slowdown:           %.1fx the independent transfers\n", slowdown)
+	}
+
+	if len(chain.Links) > 0 {
+		console.Summaryf("\n  %-10s %12s %12s %10s\n", "Position", "Sent", "Mined", "Block")
+		for _, link := range samplePositions(chain.Links) {
+			mined, block := "-", "-"
+			if link.Mined != 0 {
+				mined = link.Mined.Round(time.Millisecond).String()
+				block = fmt.Sprint(link.Block)
+			}
+			console.Summaryf("  %-10d %12s %12s %10s\n", link.Index+1, link.Sent.Round(time.Millisecond), mined, block)
+		}
+	}
+
+	if len(chain.Links) < chain.Length {
+		console.Warnf("\nChain stalled after %d of %d links: %s\n", len(chain.Links), chain.Length, chain.LastError)
+	}
+	reverted := 0
+	for _, link := range chain.Links {
+		if link.Reverted {
+			reverted++
+		}
+	}
+	if reverted > 0 {
+		console.Warnf("\n%d links reverted\n", reverted)
+	}
+}
+
+// samplePositions returns the first, last and quartile links of a chain
+func samplePositions(links []*Link) []*Link {
+	var sample []*Link
+	last := -1
+	for _, q := range []int{0, 25, 50, 75, 100} {
+		i := (len(links) - 1) * q / 100
+		if i != last {
+			sample = append(sample, links[i])
+			last = i
+		}
+	}
+	return sample
+}
@@ -0,0 +1,208 @@
+package txchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+)
+
+var chainID = big.NewInt(1337)
+
+// mockClient mines every transaction its sender can pay for in its own block
+// and refuses the rest, like a pool that checks balances against the head state
+type mockClient struct {
+	mu       sync.Mutex
+	balances map[common.Address]*big.Int
+	nonces   map[common.Address]uint64
+	mined    map[common.Hash]uint64
+	block    uint64
+	refused  int
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{
+		balances: make(map[common.Address]*big.Int),
+		nonces:   make(map[common.Address]uint64),
+		mined:    make(map[common.Hash]uint64),
+	}
+}
+
+func (m *mockClient) balance(addr common.Address) *big.Int {
+	if b, ok := m.balances[addr]; ok {
+		return b
+	}
+	return new(big.Int)
+}
+
+func (m *mockClient) SendRawTransaction(_ context.Context, rawTx []byte) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return common.Hash{}, err
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.balance(from).Cmp(tx.Cost()) < 0 {
+		m.refused++
+		return common.Hash{}, errors.New("insufficient funds for gas * price + value")
+	}
+	m.balances[from] = new(big.Int).Sub(m.balance(from), tx.Cost())
+	m.balances[*tx.To()] = new(big.Int).Add(m.balance(*tx.To()), tx.Value())
+	m.nonces[from]++
+	m.block++
+	m.mined[tx.Hash()] = m.block
+	return tx.Hash(), nil
+}
+
+func (m *mockClient) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nonces[account], nil
+}
+
+func (m *mockClient) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	block, ok := m.mined[hash]
+	if !ok {
+		return nil, nil
+	}
+	return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, BlockNumber: new(big.Int).SetUint64(block)}, nil
+}
+
+func newKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func testConfig(length int) *Config {
+	return &Config{
+		ChainID:      chainID,
+		GasPrice:     big.NewInt(1_000_000_000),
+		GasLimit:     21000,
+		Length:       length,
+		StallTimeout: 200 * time.Millisecond,
+		PollInterval: time.Millisecond,
+	}
+}
+
+func TestTester_Run(t *testing.T) {
+	client := newMockClient()
+	keys := newKeys(t, 3)
+	master := txbuilder.NewKeySigner(newKeys(t, 1)[0])
+	funds := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	client.balances[master.Address()] = new(big.Int).Set(funds)
+	for _, key := range keys {
+		client.balances[crypto.PubkeyToAddress(key.PublicKey)] = new(big.Int).Set(funds)
+	}
+
+	tester := New(client, testConfig(10))
+	result, err := tester.Run(context.Background(), master, keys)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if result.Independent.Sent != 10 || result.Independent.Mined != 10 {
+		t.Errorf("independent = %+v, want 10 sent and mined", result.Independent)
+	}
+	chain := result.Chain
+	if len(chain.Links) != 10 || chain.Mined != 10 || chain.LastError != "" {
+		t.Fatalf("chain = %+v, want 10 links mined", chain)
+	}
+	if chain.Links[0].From != master.Address() || chain.Links[9].To != master.Address() {
+		t.Error("chain should start and end at the master account")
+	}
+	for i := 1; i < len(chain.Links); i++ {
+		if chain.Links[i].From != chain.Links[i-1].To {
+			t.Fatalf("link %d sends from %s, want the previous recipient %s", i, chain.Links[i].From.Hex(), chain.Links[i-1].To.Hex())
+		}
+	}
+	if chain.LastBlock-chain.FirstBlock != 9 {
+		t.Errorf("chain spans blocks %d to %d, want 10 blocks", chain.FirstBlock, chain.LastBlock)
+	}
+
+	// The master pays exactly the chain's gas; no value is stranded in the fresh accounts
+	spent := new(big.Int).Sub(funds, client.balance(master.Address()))
+	if spent.Cmp(tester.Cost()) != 0 {
+		t.Errorf("master spent %s, want %s", spent, tester.Cost())
+	}
+	for _, link := range chain.Links[1:] {
+		if b := client.balance(link.From); b.Sign() != 0 {
+			t.Errorf("chain account %s kept %s wei", link.From.Hex(), b)
+		}
+	}
+}
+
+func TestTester_RunChainStalls(t *testing.T) {
+	client := newMockClient()
+	master := txbuilder.NewKeySigner(newKeys(t, 1)[0])
+
+	// The master cannot pay for the first link
+	tester := New(client, testConfig(5))
+	chain, err := tester.runChain(context.Background(), master)
+	if err != nil {
+		t.Fatalf("runChain() error = %v", err)
+	}
+	if len(chain.Links) != 0 || chain.Deferred == 0 || chain.LastError == "" {
+		t.Errorf("chain = %+v, want a stall before the first link", chain)
+	}
+}
+
+func TestTester_RunNoKeys(t *testing.T) {
+	tester := New(newMockClient(), testConfig(5))
+	master := txbuilder.NewKeySigner(newKeys(t, 1)[0])
+	if _, err := tester.Run(context.Background(), master, nil); err == nil {
+		t.Error("Run() should fail without keys")
+	}
+}
+
+func TestSummarizeChain(t *testing.T) {
+	chain := &ChainResult{Length: 4}
+	for i := range 4 {
+		chain.Links = append(chain.Links, &Link{
+			Index: i,
+			Sent:  time.Duration(i) * 2 * time.Second,
+			Mined: time.Duration(i+1) * 2 * time.Second,
+			Block: uint64(100 + i),
+		})
+	}
+	chain.Mined = 4
+	SummarizeChain(chain)
+
+	if chain.PerLink != 2*time.Second {
+		t.Errorf("PerLink = %s, want 2s", chain.PerLink)
+	}
+	if chain.AvgLatency != 2*time.Second || chain.Duration != 8*time.Second {
+		t.Errorf("AvgLatency = %s, Duration = %s", chain.AvgLatency, chain.Duration)
+	}
+	if chain.FirstBlock != 100 || chain.LastBlock != 103 {
+		t.Errorf("blocks = %d to %d, want 100 to 103", chain.FirstBlock, chain.LastBlock)
+	}
+
+	result := &Result{Independent: &IndependentResult{Duration: 2 * time.Second}, Chain: chain}
+	if got := result.Slowdown(); math.Abs(got-4) > 1e-9 {
+		t.Errorf("Slowdown() = %v, want 4", got)
+	}
+}
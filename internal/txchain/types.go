@@ -0,0 +1,102 @@
+package txchain
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Client defines the interface for sending chained transfers and waiting for their receipts
+type Client interface {
+	// SendRawTransaction sends a signed, encoded transaction
+	SendRawTransaction(ctx context.Context, rawTx []byte) (common.Hash, error)
+	// PendingNonceAt returns the next nonce of an account including pending txs
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	// TransactionReceipt returns the receipt of a mined transaction
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Config holds configuration for chained transfer testing
+type Config struct {
+	ChainID      *big.Int
+	GasPrice     *big.Int
+	GasLimit     uint64
+	Length       int           // Links in the chain, and independent transfers in the baseline
+	StallTimeout time.Duration // How long to wait without a send or receipt before giving up
+	PollInterval time.Duration // How often to retry the next link and poll for receipts
+}
+
+// DefaultConfig returns default chained transfer configuration
+func DefaultConfig() *Config {
+	return &Config{
+		GasLimit:     21000,
+		Length:       100,
+		StallTimeout: 60 * time.Second,
+		PollInterval: 500 * time.Millisecond,
+	}
+}
+
+// Link is one transfer of the chain, from the previous link's recipient to the next account
+type Link struct {
+	Index    int
+	Hash     common.Hash
+	From     common.Address
+	To       common.Address
+	Deferred int           // Sends the node refused before accepting the link, usually while its sender was unfunded
+	Sent     time.Duration // From the chain start until the node accepted the link
+	Mined    time.Duration // From the chain start until its receipt was seen (0 = not mined)
+	Block    uint64
+	Reverted bool
+}
+
+// Latency returns the time from the link's acceptance to its receipt
+func (l *Link) Latency() time.Duration {
+	if l.Mined == 0 {
+		return 0
+	}
+	return l.Mined - l.Sent
+}
+
+// ChainResult is the outcome of sending the chain
+type ChainResult struct {
+	Links      []*Link       // Every link the node accepted, in chain order
+	Length     int           // Links planned
+	Mined      int           // Accepted links whose receipt was seen
+	Deferred   int           // Sends refused across all links
+	Duration   time.Duration // From the chain start to the last receipt
+	PerLink    time.Duration // Least-squares growth of inclusion time per chain position
+	AvgLatency time.Duration // Mean time from a link's acceptance to its receipt
+	FirstBlock uint64
+	LastBlock  uint64
+	LastError  string // Last send error, when the chain stalled
+}
+
+// IndependentResult is the outcome of sending the same number of unrelated transfers
+type IndependentResult struct {
+	Sent       int
+	Rejected   int
+	Mined      int
+	Pending    int           // Accepted transfers not mined before the stall timeout
+	Duration   time.Duration // From the first send to the last receipt
+	AvgLatency time.Duration // Mean time from send to receipt
+	P95Latency time.Duration
+	MaxLatency time.Duration
+}
+
+// Result holds the outcome of a chained transfer test
+type Result struct {
+	Independent *IndependentResult
+	Chain       *ChainResult
+}
+
+// Slowdown returns how many times longer the chain took to be mined than the
+// same number of independent transfers, or 0 when either did not finish
+func (r *Result) Slowdown() float64 {
+	if r.Independent == nil || r.Chain == nil || r.Independent.Duration == 0 || r.Chain.Mined < r.Chain.Length {
+		return 0
+	}
+	return float64(r.Chain.Duration) / float64(r.Independent.Duration)
+}
@@ -0,0 +1,528 @@
+// Package txfile stores signed transactions in a compact columnar file that
+// a later run can replay without building or signing again
+package txfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+	"github.com/0xmhha/txhammer/internal/util/compress"
+)
+
+// magic opens a signed-tx file, followed by the format version
+var magic = []byte("TXHF")
+
+const version = 1
+
+// Record kinds
+const (
+	kindFields = 0 // Stored field by field and re-signed from the stored signature
+	kindRaw    = 1 // Stored as raw bytes (fee delegation, access lists, blobs, ...)
+)
+
+// The file holds the transaction count followed by one column per field.
+// Fields that repeat across transactions (chain ID, type, sender, gas, fees,
+// recipient, value, method selector, signature V) are dictionaries of
+// distinct values with a per-tx index. Nonces are stored as the difference to
+// the previous nonce of the same sender, which is 0 for a sender's consecutive
+// transactions, and the calldata after the selector as the length of the
+// prefix it shares with the previous call of the same method on the same
+// recipient, followed by the rest. Only R and S are stored in full.
+
+// Save writes txs to path. A .zst or .gz suffix compresses the file.
+func Save(path string, txs []*txbuilder.SignedTx) (err error) {
+	codec := compress.None
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".zst":
+		codec = compress.Zstd
+	case ".gz":
+		codec = compress.Gzip
+	}
+	f, err := compress.Create(path, codec)
+	if err != nil {
+		return fmt.Errorf("failed to create signed-tx file: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close signed-tx file: %w", cerr)
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	if err := Write(w, txs); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// Load reads the transactions of a file written by Save, compressed or not
+func Load(path string) ([]*txbuilder.SignedTx, error) {
+	f, err := compress.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signed-tx file: %w", err)
+	}
+	defer f.Close()
+
+	txs, err := Read(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("signed-tx file %s: %w", path, err)
+	}
+	return txs, nil
+}
+
+// columns are the encoded fields of a set of transactions
+type columns struct {
+	kind    column
+	from    dict
+	nonce   column
+	gas     dict
+	chainID dict
+	txType  dict
+	tip     dict
+	feeCap  dict // Gas price of legacy transactions
+	to      dict // Empty for contract creation
+	value   dict
+	method  dict // First 4 bytes of the calldata
+	data    column
+	v       dict
+	rs      column
+	raw     column // Raw bytes and hash of raw records
+}
+
+// list returns the columns in file order
+func (c *columns) list() []encoder {
+	return []encoder{&c.kind, &c.from, &c.nonce, &c.gas, &c.chainID, &c.txType,
+		&c.tip, &c.feeCap, &c.to, &c.value, &c.method, &c.data, &c.v, &c.rs, &c.raw}
+}
+
+// Write encodes txs to w
+func Write(w io.Writer, txs []*txbuilder.SignedTx) error {
+	var c columns
+	lastNonce := make(map[common.Address]uint64)
+	prevData := make(map[string][]byte) // By recipient and method
+
+	for i, st := range txs {
+		tx := st.Tx
+		raw := st.RawTx
+		if len(raw) == 0 && tx != nil {
+			var err error
+			if raw, err = tx.MarshalBinary(); err != nil {
+				return fmt.Errorf("tx %d: %w", i, err)
+			}
+		}
+
+		c.from.add(st.From.Bytes())
+		last, seen := lastNonce[st.From]
+		delta := int64(st.Nonce)
+		if seen {
+			delta = int64(st.Nonce - last - 1)
+		}
+		c.nonce.varint(delta)
+		lastNonce[st.From] = st.Nonce
+		c.gas.add(uintBytes(st.GasLimit))
+
+		if !decomposable(tx) {
+			c.kind.uvarint(kindRaw)
+			c.raw.bytes(raw)
+			c.raw.Write(st.Hash.Bytes())
+			continue
+		}
+		c.kind.uvarint(kindFields)
+		c.chainID.add(bigBytes(tx.ChainId()))
+		c.txType.add([]byte{tx.Type()})
+		if tx.Type() == types.DynamicFeeTxType {
+			c.tip.add(bigBytes(tx.GasTipCap()))
+			c.feeCap.add(bigBytes(tx.GasFeeCap()))
+		} else {
+			c.feeCap.add(bigBytes(tx.GasPrice()))
+		}
+		var to []byte
+		if tx.To() != nil {
+			to = tx.To().Bytes()
+		}
+		c.to.add(to)
+		c.value.add(bigBytes(tx.Value()))
+
+		method, data := splitSelector(tx.Data())
+		c.method.add(method)
+		key := string(to) + string(method)
+		shared := commonPrefix(prevData[key], data)
+		c.data.uvarint(uint64(shared))
+		c.data.bytes(data[shared:])
+		prevData[key] = data
+
+		v, r, s := tx.RawSignatureValues()
+		c.v.add(bigBytes(v))
+		c.rs.Write(common.LeftPadBytes(r.Bytes(), 32))
+		c.rs.Write(common.LeftPadBytes(s.Bytes(), 32))
+	}
+
+	var header column
+	header.Write(magic)
+	header.WriteByte(version)
+	header.uvarint(uint64(len(txs)))
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return fmt.Errorf("failed to write signed-tx file: %w", err)
+	}
+	for _, col := range c.list() {
+		if err := col.encode(w); err != nil {
+			return fmt.Errorf("failed to write signed-tx file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Read decodes the transactions written by Write
+func Read(r io.Reader) ([]*txbuilder.SignedTx, error) {
+	br := bufio.NewReader(r)
+	head := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(br, head); err != nil || !bytes.Equal(head[:len(magic)], magic) {
+		return nil, errors.New("not a signed-tx file")
+	}
+	if head[len(magic)] != version {
+		return nil, fmt.Errorf("unsupported format version %d", head[len(magic)])
+	}
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction count: %w", err)
+	}
+
+	var c columns
+	for _, col := range c.list() {
+		if err := col.decode(br); err != nil {
+			return nil, fmt.Errorf("failed to read column: %w", err)
+		}
+	}
+
+	txs := make([]*txbuilder.SignedTx, 0, min(count, 1<<20))
+	lastNonce := make(map[common.Address]uint64)
+	prevData := make(map[string][]byte)
+	for i := uint64(0); i < count; i++ {
+		st, err := c.next(lastNonce, prevData)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+		txs = append(txs, st)
+	}
+	return txs, nil
+}
+
+// next decodes the next transaction from the columns
+func (c *columns) next(lastNonce map[common.Address]uint64, prevData map[string][]byte) (*txbuilder.SignedTx, error) {
+	kind, err := c.kind.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	fromBytes, err := c.from.next()
+	if err != nil {
+		return nil, err
+	}
+	from := common.BytesToAddress(fromBytes)
+	delta, err := c.nonce.readVarint()
+	if err != nil {
+		return nil, err
+	}
+	nonce := uint64(delta)
+	if last, seen := lastNonce[from]; seen {
+		nonce = last + 1 + uint64(delta)
+	}
+	lastNonce[from] = nonce
+	gasBytes, err := c.gas.next()
+	if err != nil {
+		return nil, err
+	}
+	st := &txbuilder.SignedTx{From: from, Nonce: nonce, GasLimit: new(big.Int).SetBytes(gasBytes).Uint64()}
+
+	if kind == kindRaw {
+		if st.RawTx, err = c.raw.readBytes(); err != nil {
+			return nil, err
+		}
+		hash := make([]byte, common.HashLength)
+		if _, err := io.ReadFull(&c.raw, hash); err != nil {
+			return nil, err
+		}
+		st.Hash = common.BytesToHash(hash)
+		// Standard types decode; others, such as fee delegation, stay raw
+		var tx types.Transaction
+		if tx.UnmarshalBinary(st.RawTx) == nil {
+			st.Tx = &tx
+		}
+		return st, nil
+	}
+	if kind != kindFields {
+		return nil, fmt.Errorf("unknown record kind %d", kind)
+	}
+
+	fields := make([][]byte, 0, 5)
+	for _, d := range []*dict{&c.chainID, &c.txType} {
+		b, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, b)
+	}
+	chainID, txType := new(big.Int).SetBytes(fields[0]), fields[1]
+	if len(txType) != 1 {
+		return nil, errors.New("invalid transaction type")
+	}
+	var tip *big.Int
+	if txType[0] == types.DynamicFeeTxType {
+		b, err := c.tip.next()
+		if err != nil {
+			return nil, err
+		}
+		tip = new(big.Int).SetBytes(b)
+	}
+	feeCapBytes, err := c.feeCap.next()
+	if err != nil {
+		return nil, err
+	}
+	feeCap := new(big.Int).SetBytes(feeCapBytes)
+	toBytes, err := c.to.next()
+	if err != nil {
+		return nil, err
+	}
+	var to *common.Address
+	if len(toBytes) > 0 {
+		addr := common.BytesToAddress(toBytes)
+		to = &addr
+	}
+	valueBytes, err := c.value.next()
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Int).SetBytes(valueBytes)
+
+	method, err := c.method.next()
+	if err != nil {
+		return nil, err
+	}
+	shared, err := c.data.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	key := string(toBytes) + string(method)
+	prev := prevData[key]
+	if shared > uint64(len(prev)) {
+		return nil, errors.New("invalid calldata delta")
+	}
+	suffix, err := c.data.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	rest := append(append([]byte(nil), prev[:shared]...), suffix...)
+	prevData[key] = rest
+	data := append(append([]byte(nil), method...), rest...)
+
+	vBytes, err := c.v.next()
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]byte, 64)
+	if _, err := io.ReadFull(&c.rs, rs); err != nil {
+		return nil, err
+	}
+	v, r, s := new(big.Int).SetBytes(vBytes), new(big.Int).SetBytes(rs[:32]), new(big.Int).SetBytes(rs[32:])
+
+	var inner types.TxData
+	switch txType[0] {
+	case types.LegacyTxType:
+		inner = &types.LegacyTx{Nonce: nonce, GasPrice: feeCap, Gas: st.GasLimit, To: to, Value: value, Data: data, V: v, R: r, S: s}
+	case types.AccessListTxType:
+		inner = &types.AccessListTx{ChainID: chainID, Nonce: nonce, GasPrice: feeCap, Gas: st.GasLimit, To: to, Value: value, Data: data, V: v, R: r, S: s}
+	case types.DynamicFeeTxType:
+		inner = &types.DynamicFeeTx{ChainID: chainID, Nonce: nonce, GasTipCap: tip, GasFeeCap: feeCap, Gas: st.GasLimit, To: to, Value: value, Data: data, V: v, R: r, S: s}
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", txType[0])
+	}
+	st.Tx = types.NewTx(inner)
+	if st.RawTx, err = st.Tx.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	st.Hash = st.Tx.Hash()
+	return st, nil
+}
+
+// decomposable reports whether tx can be stored field by field
+func decomposable(tx *types.Transaction) bool {
+	if tx == nil {
+		return false
+	}
+	switch tx.Type() {
+	case types.LegacyTxType, types.DynamicFeeTxType:
+		return true
+	case types.AccessListTxType:
+		return len(tx.AccessList()) == 0
+	default:
+		return false
+	}
+}
+
+// splitSelector splits calldata into its method selector and the rest
+func splitSelector(data []byte) (method, rest []byte) {
+	if len(data) < 4 {
+		return data, nil
+	}
+	return data[:4], data[4:]
+}
+
+// commonPrefix returns the length of the prefix a and b share
+func commonPrefix(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+func bigBytes(v *big.Int) []byte {
+	if v == nil {
+		return nil
+	}
+	return v.Bytes()
+}
+
+func uintBytes(v uint64) []byte {
+	return new(big.Int).SetUint64(v).Bytes()
+}
+
+// encoder is a column of the file
+type encoder interface {
+	encode(w io.Writer) error
+	decode(r *bufio.Reader) error
+}
+
+// column is a length-prefixed byte stream
+type column struct {
+	bytes.Buffer
+}
+
+func (c *column) uvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	c.Write(buf[:binary.PutUvarint(buf[:], v)])
+}
+
+func (c *column) varint(v int64) {
+	var buf [binary.MaxVarintLen64]byte
+	c.Write(buf[:binary.PutVarint(buf[:], v)])
+}
+
+func (c *column) bytes(b []byte) {
+	c.uvarint(uint64(len(b)))
+	c.Write(b)
+}
+
+func (c *column) readUvarint() (uint64, error) {
+	return binary.ReadUvarint(c)
+}
+
+func (c *column) readVarint() (int64, error) {
+	return binary.ReadVarint(c)
+}
+
+func (c *column) readBytes() ([]byte, error) {
+	n, err := c.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(c.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return append([]byte(nil), c.Next(int(n))...), nil
+}
+
+func (c *column) encode(w io.Writer) error {
+	var head column
+	head.uvarint(uint64(c.Len()))
+	if _, err := w.Write(head.Bytes()); err != nil {
+		return err
+	}
+	_, err := w.Write(c.Bytes())
+	return err
+}
+
+func (c *column) decode(r *bufio.Reader) error {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	c.Reset()
+	_, err = io.CopyN(c, r, int64(n))
+	return err
+}
+
+// dict is a dictionary of distinct values and a column of indexes into it
+type dict struct {
+	index  map[string]uint64
+	values [][]byte
+	column
+}
+
+func (d *dict) add(b []byte) {
+	if d.index == nil {
+		d.index = make(map[string]uint64)
+	}
+	i, ok := d.index[string(b)]
+	if !ok {
+		i = uint64(len(d.values))
+		d.index[string(b)] = i
+		d.values = append(d.values, b)
+	}
+	d.uvarint(i)
+}
+
+func (d *dict) next() ([]byte, error) {
+	i, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if i >= uint64(len(d.values)) {
+		return nil, errors.New("dictionary index out of range")
+	}
+	return d.values[i], nil
+}
+
+func (d *dict) encode(w io.Writer) error {
+	var values column
+	values.uvarint(uint64(len(d.values)))
+	for _, v := range d.values {
+		values.bytes(v)
+	}
+	if err := values.encode(w); err != nil {
+		return err
+	}
+	return d.column.encode(w)
+}
+
+func (d *dict) decode(r *bufio.Reader) error {
+	var values column
+	if err := values.decode(r); err != nil {
+		return err
+	}
+	n, err := values.readUvarint()
+	if err != nil {
+		return err
+	}
+	d.values = make([][]byte, 0, min(n, uint64(values.Len())))
+	for i := uint64(0); i < n; i++ {
+		v, err := values.readBytes()
+		if err != nil {
+			return err
+		}
+		d.values = append(d.values, v)
+	}
+	return d.column.decode(r)
+}
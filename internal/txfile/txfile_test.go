@@ -0,0 +1,119 @@
+package txfile
+
+import (
+	"bytes"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xmhha/txhammer/internal/txbuilder"
+)
+
+// signedTxs returns n transactions of each type from two senders, with
+// ERC20-style calldata that differs only in its last bytes
+func signedTxs(t *testing.T, n int) []*txbuilder.SignedTx {
+	t.Helper()
+	chainID := big.NewInt(1337)
+	signer := types.LatestSignerForChainID(chainID)
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	var txs []*txbuilder.SignedTx
+	for k := 0; k < 2; k++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		from := crypto.PubkeyToAddress(key.PublicKey)
+		for i := 0; i < n; i++ {
+			nonce := uint64(5 + 3*i)
+			data := append(common.FromHex("0xa9059cbb"), common.LeftPadBytes(big.NewInt(int64(i)).Bytes(), 64)...)
+			inner := []types.TxData{
+				&types.DynamicFeeTx{ChainID: chainID, Nonce: nonce, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(2e9), Gas: 60000, To: &to, Value: new(big.Int), Data: data},
+				&types.LegacyTx{Nonce: nonce + 1, GasPrice: big.NewInt(2e9), Gas: 21000, To: &to, Value: big.NewInt(1)},
+				&types.DynamicFeeTx{ChainID: chainID, Nonce: nonce + 2, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(2e9), Gas: 500000, Data: []byte{0x60, 0x80}},
+			}
+			for _, d := range inner {
+				tx, err := types.SignNewTx(key, signer, d)
+				if err != nil {
+					t.Fatal(err)
+				}
+				raw, err := tx.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+				txs = append(txs, &txbuilder.SignedTx{Tx: tx, RawTx: raw, Hash: tx.Hash(), From: from, Nonce: tx.Nonce(), GasLimit: tx.Gas()})
+			}
+		}
+	}
+	// A fee delegation tx is kept as raw bytes
+	txs = append(txs, &txbuilder.SignedTx{
+		RawTx:    []byte{0x16, 0x01, 0x02, 0x03},
+		Hash:     common.HexToHash("0xfd"),
+		From:     txs[0].From,
+		Nonce:    1000,
+		GasLimit: 30000,
+	})
+	return txs
+}
+
+func TestWriteRead(t *testing.T) {
+	txs := signedTxs(t, 50)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, txs); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var rawSize int
+	for _, tx := range txs {
+		rawSize += len(tx.RawTx)
+	}
+	// R and S alone are 64 bytes of each signed tx
+	if buf.Len() >= rawSize {
+		t.Errorf("encoded %d bytes, raw transactions are %d", buf.Len(), rawSize)
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(got) != len(txs) {
+		t.Fatalf("Read() returned %d txs, want %d", len(got), len(txs))
+	}
+	for i, want := range txs {
+		g := got[i]
+		if !bytes.Equal(g.RawTx, want.RawTx) || g.Hash != want.Hash || g.From != want.From ||
+			g.Nonce != want.Nonce || g.GasLimit != want.GasLimit {
+			t.Fatalf("tx %d = %+v, want %+v", i, g, want)
+		}
+		if (g.Tx == nil) != (want.Tx == nil) {
+			t.Errorf("tx %d: decoded Tx = %v, want %v", i, g.Tx != nil, want.Tx != nil)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	txs := signedTxs(t, 10)
+	for _, name := range []string{"txs.bin", "txs.bin.zst", "txs.bin.gz"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := Save(path, txs); err != nil {
+			t.Fatalf("Save(%s) error = %v", name, err)
+		}
+		got, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", name, err)
+		}
+		if len(got) != len(txs) || got[len(got)-1].Hash != txs[len(txs)-1].Hash {
+			t.Errorf("Load(%s) did not round-trip", name)
+		}
+	}
+}
+
+func TestRead_NotATxFile(t *testing.T) {
+	if _, err := Read(bytes.NewReader([]byte("{}"))); err == nil {
+		t.Error("Read() of a JSON file succeeded")
+	}
+}
@@ -0,0 +1,143 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec is the compression applied to an artifact file
+type Codec string
+
+const (
+	None Codec = "none"
+	Zstd Codec = "zstd"
+	Gzip Codec = "gzip"
+)
+
+// Magic numbers that open a compressed stream
+var (
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// ParseCodec parses a codec name (case-insensitive); "" means None
+func ParseCodec(s string) (Codec, error) {
+	switch c := Codec(strings.ToLower(strings.TrimSpace(s))); c {
+	case "", None:
+		return None, nil
+	case Zstd, Gzip:
+		return c, nil
+	default:
+		return "", fmt.Errorf("invalid compression %q: must be none, zstd or gzip", s)
+	}
+}
+
+// Ext returns the file name suffix of the codec, e.g. ".zst"
+func (c Codec) Ext() string {
+	switch c {
+	case Zstd:
+		return ".zst"
+	case Gzip:
+		return ".gz"
+	default:
+		return ""
+	}
+}
+
+// file closes the compressor before the file it writes to
+type file struct {
+	io.WriteCloser
+	file *os.File
+}
+
+func (f *file) Close() error {
+	err := f.WriteCloser.Close()
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Create creates the file at path and returns a writer that compresses with
+// c. Close must be called to flush the compressed stream.
+func Create(path string, c Codec) (io.WriteCloser, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	var w io.WriteCloser
+	switch c {
+	case Zstd:
+		w, err = zstd.NewWriter(f)
+	case Gzip:
+		w = gzip.NewWriter(f)
+	default:
+		return f, nil
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &file{WriteCloser: w, file: f}, nil
+}
+
+// reader closes the decompressor and the file it reads from
+type reader struct {
+	io.Reader
+	close func() error
+}
+
+func (r *reader) Close() error {
+	return r.close()
+}
+
+// Open opens the file at path for reading, decompressing zstd and gzip
+// content transparently. The codec is detected from the content, not the
+// file name.
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &reader{Reader: r, close: func() error {
+		if c, ok := r.(io.Closer); ok {
+			_ = c.Close()
+		}
+		return f.Close()
+	}}, nil
+}
+
+// NewReader returns a reader of r's content, decompressing it if it starts
+// with a zstd or gzip header. The returned reader is an io.Closer when it
+// holds decompressor state.
+func NewReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(head, zstdMagic):
+		dec, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	case bytes.HasPrefix(head, gzipMagic):
+		return gzip.NewReader(br)
+	default:
+		return br, nil
+	}
+}
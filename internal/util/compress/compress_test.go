@@ -0,0 +1,54 @@
+package compress
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCodec(t *testing.T) {
+	for in, want := range map[string]Codec{"": None, "none": None, "ZSTD": Zstd, " gzip ": Gzip} {
+		if got, err := ParseCodec(in); err != nil || got != want {
+			t.Errorf("ParseCodec(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseCodec("brotli"); err == nil {
+		t.Error("ParseCodec(brotli) should fail")
+	}
+}
+
+func TestCreateOpen(t *testing.T) {
+	const content = "Hash,From,Nonce\n0x01,0xaa,1\n"
+	for _, codec := range []Codec{None, Zstd, Gzip} {
+		path := filepath.Join(t.TempDir(), "transactions.csv"+codec.Ext())
+		w, err := Create(path, codec)
+		if err != nil {
+			t.Fatalf("Create(%s) error = %v", codec, err)
+		}
+		if _, err := io.WriteString(w, content); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close(%s) error = %v", codec, err)
+		}
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if compressed := string(raw) != content; compressed != (codec != None) {
+			t.Errorf("%s: file compressed = %v", codec, compressed)
+		}
+
+		r, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open(%s) error = %v", codec, err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil || string(got) != content {
+			t.Errorf("%s: read back %q, %v", codec, got, err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Package console writes the tool's leveled, optionally colored output.
+//
+// Progress output is dropped in quiet mode; warnings, failures and the final
+// summary are always written. Status markers ([OK], [WARN], [FAIL]) are
+// colored when color is enabled.
+package console
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// ColorMode selects when status markers are colored
+type ColorMode string
+
+const (
+	ColorAuto   ColorMode = "auto"   // Color when stdout is a terminal and NO_COLOR is unset
+	ColorAlways ColorMode = "always" // Always color
+	ColorNever  ColorMode = "never"  // Never color
+)
+
+// ParseColorMode parses a color mode name (case-insensitive)
+func ParseColorMode(s string) (ColorMode, error) {
+	switch mode := ColorMode(strings.ToLower(s)); mode {
+	case ColorAuto, ColorAlways, ColorNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid color mode %q: must be auto, always or never", s)
+	}
+}
+
+// ANSI escape sequences for the status markers
+const (
+	ansiReset  = "\033[0m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiRed    = "\033[31m"
+)
+
+var (
+	mu    sync.Mutex
+	out   io.Writer = os.Stdout
+	quiet bool
+	color bool
+)
+
+// Configure sets quiet mode and the color mode for all subsequent output
+func Configure(quietMode bool, mode ColorMode) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	quiet = quietMode
+	switch mode {
+	case ColorAlways:
+		color = true
+	case ColorNever:
+		color = false
+	default:
+		color = os.Getenv("NO_COLOR") == "" && isTerminal(out)
+	}
+}
+
+// SetOutput redirects output, mainly for tests. Auto color is re-evaluated
+// by the next Configure call.
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	out = w
+}
+
+// Quiet reports whether progress output is suppressed
+func Quiet() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return quiet
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Printf writes progress output; dropped in quiet mode
+func Printf(format string, args ...any) {
+	write(false, fmt.Sprintf(format, args...))
+}
+
+// Println writes progress output; dropped in quiet mode
+func Println(args ...any) {
+	write(false, fmt.Sprintln(args...))
+}
+
+// Summaryf writes final summary output and error details, which quiet mode keeps
+func Summaryf(format string, args ...any) {
+	write(true, fmt.Sprintf(format, args...))
+}
+
+// Summaryln writes final summary output and error details, which quiet mode keeps
+func Summaryln(args ...any) {
+	write(true, fmt.Sprintln(args...))
+}
+
+// OKf writes a progress line with a green [OK] marker; dropped in quiet mode.
+// Leading newlines and indentation in format are kept before the marker.
+func OKf(format string, args ...any) {
+	writeStatus(false, "[OK]", format, args...)
+}
+
+// Warnf writes a line with a yellow [WARN] marker, also in quiet mode
+func Warnf(format string, args ...any) {
+	writeStatus(true, "[WARN]", format, args...)
+}
+
+// Failf writes a line with a red [FAIL] marker, also in quiet mode
+func Failf(format string, args ...any) {
+	writeStatus(true, "[FAIL]", format, args...)
+}
+
+// Marker returns a status marker, colored when color is enabled, for use
+// inside summary lines. Unknown markers are returned as is.
+func Marker(marker string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	return paint(marker)
+}
+
+func writeStatus(always bool, marker, format string, args ...any) {
+	body := strings.TrimLeft(format, "\n ")
+	lead := format[:len(format)-len(body)]
+
+	mu.Lock()
+	defer mu.Unlock()
+	if quiet && !always {
+		return
+	}
+	fmt.Fprint(out, lead+paint(marker)+" "+fmt.Sprintf(body, args...))
+}
+
+func write(always bool, s string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if quiet && !always {
+		return
+	}
+	fmt.Fprint(out, s)
+}
+
+// paint colors a known marker; the caller must hold mu
+func paint(marker string) string {
+	if !color {
+		return marker
+	}
+	switch marker {
+	case "[OK]":
+		return ansiGreen + marker + ansiReset
+	case "[WARN]":
+		return ansiYellow + marker + ansiReset
+	case "[FAIL]":
+		return ansiRed + marker + ansiReset
+	default:
+		return marker
+	}
+}
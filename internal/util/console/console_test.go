@@ -0,0 +1,72 @@
+package console
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func capture(t *testing.T, quietMode bool, mode ColorMode) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	Configure(quietMode, mode)
+	t.Cleanup(func() {
+		SetOutput(os.Stdout)
+		Configure(false, ColorNever)
+	})
+	return &buf
+}
+
+func TestQuietKeepsSummaryAndWarnings(t *testing.T) {
+	buf := capture(t, true, ColorNever)
+
+	Printf("progress %d\n", 1)
+	OKf("\nstage done\n")
+	Warnf("\nsomething off\n")
+	Failf("stage failed\n")
+	Summaryf("total %d\n", 3)
+
+	want := "\n[WARN] something off\n[FAIL] stage failed\ntotal 3\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestStatusMarkers(t *testing.T) {
+	buf := capture(t, false, ColorNever)
+
+	OKf("\n   funded %d\n", 2)
+	if got, want := buf.String(), "\n   [OK] funded 2\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestColor(t *testing.T) {
+	buf := capture(t, false, ColorAlways)
+
+	Warnf("careful\n")
+	if got, want := buf.String(), ansiYellow+"[WARN]"+ansiReset+" careful\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if got := Marker("[DONE]"); got != "[DONE]" {
+		t.Errorf("Marker() = %q, unknown markers must not be colored", got)
+	}
+}
+
+func TestColorAutoDisabledWithoutTerminal(t *testing.T) {
+	capture(t, false, ColorAuto)
+
+	if got := Marker("[OK]"); got != "[OK]" {
+		t.Errorf("Marker() = %q, want no color for a non-terminal writer", got)
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	if mode, err := ParseColorMode("ALWAYS"); err != nil || mode != ColorAlways {
+		t.Errorf("ParseColorMode(ALWAYS) = %q, %v", mode, err)
+	}
+	if _, err := ParseColorMode("rainbow"); err == nil {
+		t.Error("expected error for unknown color mode")
+	}
+}
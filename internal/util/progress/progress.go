@@ -4,8 +4,18 @@ import (
 	"log"
 
 	"github.com/schollz/progressbar/v3"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
 )
 
+// New creates a progress bar, hidden when console output is quiet.
+func New(max int64, description string) *progressbar.ProgressBar {
+	if console.Quiet() {
+		return progressbar.DefaultSilent(max, description)
+	}
+	return progressbar.Default(max, description)
+}
+
 // Add increments the progress bar while safely handling errors.
 func Add(bar *progressbar.ProgressBar, n int) {
 	if bar == nil || n == 0 {
@@ -0,0 +1,65 @@
+package progress
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// Ticker advances a progress bar from a counter on its own goroutine, so hot
+// paths pay only for an atomic add instead of redrawing the bar.
+type Ticker struct {
+	bar     *progressbar.ProgressBar
+	count   atomic.Int64
+	shown   int64
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewTicker starts redrawing bar every interval
+func NewTicker(bar *progressbar.ProgressBar, interval time.Duration) *Ticker {
+	t := &Ticker{
+		bar:     bar,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go t.run(interval)
+	return t
+}
+
+// Add counts n finished items; it is safe for concurrent use
+func (t *Ticker) Add(n int) {
+	t.count.Add(int64(n))
+}
+
+// Stop flushes the remaining count to the bar and stops redrawing
+func (t *Ticker) Stop() {
+	t.once.Do(func() {
+		close(t.done)
+		<-t.stopped
+	})
+}
+
+func (t *Ticker) run(interval time.Duration) {
+	defer close(t.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.flush()
+		case <-t.done:
+			t.flush()
+			return
+		}
+	}
+}
+
+func (t *Ticker) flush() {
+	count := t.count.Load()
+	Add(t.bar, int(count-t.shown))
+	t.shown = count
+}
@@ -0,0 +1,123 @@
+package units
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Denomination is the unit wei amounts are displayed in
+type Denomination string
+
+const (
+	Wei   Denomination = "wei"
+	Gwei  Denomination = "gwei"
+	Ether Denomination = "ether"
+	// Auto picks ether, gwei or wei depending on the magnitude of each amount
+	Auto Denomination = "auto"
+)
+
+var (
+	gweiWei  = big.NewInt(1e9)
+	etherWei = big.NewInt(1e18)
+
+	// Auto switches to a larger unit once the amount is at least 0.001 of it
+	autoEtherMin = big.NewInt(1e15)
+	autoGweiMin  = big.NewInt(1e6)
+)
+
+// ParseDenomination parses a denomination name (case-insensitive)
+func ParseDenomination(s string) (Denomination, error) {
+	switch d := Denomination(strings.ToLower(s)); d {
+	case Wei, Gwei, Ether, Auto:
+		return d, nil
+	default:
+		return "", fmt.Errorf("invalid denomination %q: must be wei, gwei, ether or auto", s)
+	}
+}
+
+// ParseAmount parses an amount such as "1000000", "250gwei" or "0.5 ether" into wei.
+// A bare number is read as wei.
+func ParseAmount(in string) (*big.Int, error) {
+	s := strings.TrimSpace(strings.ToLower(in))
+	unit := big.NewInt(1)
+	for _, u := range []struct {
+		suffix string
+		wei    *big.Int
+	}{{string(Ether), etherWei}, {string(Gwei), gweiWei}, {string(Wei), big.NewInt(1)}} {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			unit = u.wei
+			break
+		}
+	}
+
+	amount, ok := new(big.Rat).SetString(s)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q: expected a non-negative number with an optional wei, gwei or ether suffix", in)
+	}
+	amount.Mul(amount, new(big.Rat).SetInt(unit))
+	if !amount.IsInt() {
+		return nil, fmt.Errorf("invalid amount %q: fractions of a wei are not allowed", in)
+	}
+	return new(big.Int).Set(amount.Num()), nil
+}
+
+// Formatter renders wei amounts for the console. The zero value prints raw wei.
+type Formatter struct {
+	Denomination Denomination
+	Decimals     int // Digits after the decimal point for gwei and ether
+}
+
+// DefaultFormatter returns a formatter that picks the unit automatically with 6 decimals
+func DefaultFormatter() Formatter {
+	return Formatter{Denomination: Auto, Decimals: 6}
+}
+
+// Format renders a wei amount in the formatter's denomination, e.g. "0.020958 ether"
+func (f Formatter) Format(wei *big.Int) string {
+	if wei == nil {
+		return "-"
+	}
+
+	denom := f.Denomination
+	if denom == Auto {
+		abs := new(big.Int).Abs(wei)
+		switch {
+		case abs.Cmp(autoEtherMin) >= 0:
+			denom = Ether
+		case abs.Cmp(autoGweiMin) >= 0:
+			denom = Gwei
+		default:
+			denom = Wei
+		}
+	}
+
+	var unit *big.Int
+	switch denom {
+	case Gwei:
+		unit = gweiWei
+	case Ether:
+		unit = etherWei
+	default:
+		return wei.String() + " wei"
+	}
+
+	decimals := max(f.Decimals, 0)
+	return new(big.Rat).SetFrac(wei, unit).FloatString(decimals) + " " + string(denom)
+}
+
+// FormatBytes renders a byte count with a decimal (SI) unit, e.g. "1.25 MB",
+// matching how network bandwidth is usually quoted
+func FormatBytes(n float64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%.0f B", n)
+	}
+	exp := 0
+	for n >= unit*unit && exp < 3 {
+		n /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %cB", n/unit, "kMGT"[exp])
+}
@@ -0,0 +1,84 @@
+package units
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Formatter
+		wei  *big.Int
+		want string
+	}{
+		{"zero value prints wei", Formatter{}, big.NewInt(20958000000000000), "20958000000000000 wei"},
+		{"ether", Formatter{Denomination: Ether, Decimals: 6}, big.NewInt(20958000000000000), "0.020958 ether"},
+		{"ether rounds", Formatter{Denomination: Ether, Decimals: 2}, big.NewInt(15e15), "0.02 ether"},
+		{"gwei", Formatter{Denomination: Gwei, Decimals: 3}, big.NewInt(1500000000), "1.500 gwei"},
+		{"auto ether", DefaultFormatter(), big.NewInt(2e18), "2.000000 ether"},
+		{"auto gwei", DefaultFormatter(), big.NewInt(25e9), "25.000000 gwei"},
+		{"auto wei", DefaultFormatter(), big.NewInt(21000), "21000 wei"},
+		{"nil", DefaultFormatter(), nil, "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Format(tt.wei); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDenomination(t *testing.T) {
+	if d, err := ParseDenomination("GWEI"); err != nil || d != Gwei {
+		t.Errorf("ParseDenomination(GWEI) = %q, %v", d, err)
+	}
+	if _, err := ParseDenomination("finney"); err == nil {
+		t.Error("expected error for unknown denomination")
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"1000000", "1000000", false},
+		{"250gwei", "250000000000", false},
+		{"0.5 ether", "500000000000000000", false},
+		{"1.5wei", "", true},
+		{"-1ether", "", true},
+		{"lots", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAmount(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("ParseAmount(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    float64
+		want string
+	}{
+		{0, "0 B"},
+		{999, "999 B"},
+		{1500, "1.50 kB"},
+		{2_500_000, "2.50 MB"},
+		{3e9, "3.00 GB"},
+		{4e15, "4000.00 TB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%v) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
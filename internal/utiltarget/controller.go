@@ -0,0 +1,192 @@
+package utiltarget
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/mathutil"
+)
+
+// Controller adjusts the send rate so that average block utilization
+// converges on a target percentage
+type Controller struct {
+	client Client
+	config *Config
+
+	tps       float64
+	lastBlock uint64
+	lastTime  time.Time
+	window    []float64
+
+	// Accumulators
+	blocksObserved   int
+	totalUtilization float64
+	adjustments      int
+	inBandBlocks     int
+	inBandTxs        int
+	inBandTime       time.Duration
+	inBandInput      float64
+}
+
+// New creates a new Controller instance
+func New(client Client, config *Config) *Controller {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.Window <= 0 {
+		config.Window = 1
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	return &Controller{
+		client: client,
+		config: config,
+		tps:    config.InitialTPS,
+		window: make([]float64, 0, config.Window),
+	}
+}
+
+// Run polls new blocks and steers the setter until the context is done
+func (c *Controller) Run(ctx context.Context, setter RateSetter) (*Result, error) {
+	latest, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	c.lastBlock = latest
+
+	setter.SetTPS(c.tps)
+
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return c.result(), nil
+		case <-ticker.C:
+			c.poll(ctx, setter)
+		}
+	}
+}
+
+// poll fetches blocks produced since the last poll and adjusts the rate
+func (c *Controller) poll(ctx context.Context, setter RateSetter) {
+	latest, err := c.client.BlockNumber(ctx)
+	if err != nil || latest <= c.lastBlock {
+		return
+	}
+
+	for num := c.lastBlock + 1; num <= latest; num++ {
+		block, err := c.client.BlockByNumber(ctx, new(big.Int).SetUint64(num))
+		if err != nil {
+			return
+		}
+		timestamp, err := mathutil.Uint64ToInt64(block.Time())
+		if err != nil {
+			return
+		}
+
+		utilization := float64(0)
+		if block.GasLimit() > 0 {
+			utilization = float64(block.GasUsed()) / float64(block.GasLimit()) * 100
+		}
+		c.observe(utilization, len(block.Transactions()), time.Unix(timestamp, 0))
+		c.lastBlock = num
+	}
+
+	next := NextTPS(c.tps, c.windowAverage(), c.config.TargetUtilization, c.config.MinTPS, c.config.MaxTPS)
+	if next != c.tps {
+		c.tps = next
+		c.adjustments++
+		setter.SetTPS(next)
+	}
+}
+
+// observe records a single block in the moving window and accumulators
+func (c *Controller) observe(utilization float64, txCount int, timestamp time.Time) {
+	c.window = append(c.window, utilization)
+	if len(c.window) > c.config.Window {
+		c.window = c.window[len(c.window)-c.config.Window:]
+	}
+	c.blocksObserved++
+	c.totalUtilization += utilization
+
+	if c.InBand() && !c.lastTime.IsZero() {
+		c.inBandBlocks++
+		c.inBandTxs += txCount
+		c.inBandTime += timestamp.Sub(c.lastTime)
+		c.inBandInput += c.tps
+	}
+	c.lastTime = timestamp
+}
+
+// windowAverage returns the moving average utilization
+func (c *Controller) windowAverage() float64 {
+	if len(c.window) == 0 {
+		return 0
+	}
+	var total float64
+	for _, u := range c.window {
+		total += u
+	}
+	return total / float64(len(c.window))
+}
+
+// InBand returns true if the moving average is within tolerance of the target
+func (c *Controller) InBand() bool {
+	if len(c.window) == 0 {
+		return false
+	}
+	return math.Abs(c.windowAverage()-c.config.TargetUtilization) <= c.config.Tolerance
+}
+
+// CurrentTPS returns the send rate currently requested from the sender
+func (c *Controller) CurrentTPS() float64 {
+	return c.tps
+}
+
+// result builds the final result from the accumulators
+func (c *Controller) result() *Result {
+	r := &Result{
+		TargetUtilization: c.config.TargetUtilization,
+		BlocksObserved:    c.blocksObserved,
+		BlocksInBand:      c.inBandBlocks,
+		Adjustments:       c.adjustments,
+		FinalTPS:          c.tps,
+	}
+	if c.blocksObserved > 0 {
+		r.AvgUtilization = c.totalUtilization / float64(c.blocksObserved)
+	}
+	if c.inBandTime.Seconds() > 0 {
+		r.EquilibriumTPS = float64(c.inBandTxs) / c.inBandTime.Seconds()
+	}
+	if c.inBandBlocks > 0 {
+		r.EquilibriumInput = c.inBandInput / float64(c.inBandBlocks)
+	}
+	return r
+}
+
+// NextTPS computes the next send rate from the observed utilization.
+// The rate moves halfway toward the proportional ideal to damp oscillation,
+// doubles when blocks are empty, and is clamped to [minTPS, maxTPS].
+func NextTPS(current, observed, target, minTPS, maxTPS float64) float64 {
+	var next float64
+	if observed <= 0 {
+		next = current * 2
+	} else {
+		ideal := current * target / observed
+		next = current + (ideal-current)/2
+	}
+
+	if next < minTPS {
+		next = minTPS
+	}
+	if maxTPS > 0 && next > maxTPS {
+		next = maxTPS
+	}
+	return next
+}
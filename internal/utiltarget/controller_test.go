@@ -0,0 +1,115 @@
+package utiltarget
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type mockClient struct {
+	blocks map[uint64]*types.Block
+	latest uint64
+}
+
+func (m *mockClient) BlockNumber(_ context.Context) (uint64, error) {
+	return m.latest, nil
+}
+
+func (m *mockClient) BlockByNumber(_ context.Context, number *big.Int) (*types.Block, error) {
+	return m.blocks[number.Uint64()], nil
+}
+
+func (m *mockClient) addBlock(num, ts, gasUsed uint64) {
+	header := &types.Header{
+		Number:   new(big.Int).SetUint64(num),
+		Time:     ts,
+		GasLimit: 1000,
+		GasUsed:  gasUsed,
+	}
+	m.blocks[num] = types.NewBlock(header, nil, nil, nil)
+	m.latest = num
+}
+
+type recordingSetter struct {
+	rates []float64
+}
+
+func (r *recordingSetter) SetTPS(tps float64) {
+	r.rates = append(r.rates, tps)
+}
+
+func TestNextTPS(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  float64
+		observed float64
+		minTPS   float64
+		want     float64
+	}{
+		{"empty blocks double the rate", 100, 0, 1, 200},
+		{"under target increases", 100, 40, 1, 150},
+		{"at target holds", 100, 80, 1, 100},
+		{"over target decreases", 100, 160, 1, 75},
+		{"clamped to min", 2, 100, 1.9, 1.9},
+		{"clamped to max", 900, 10, 1, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextTPS(tt.current, tt.observed, 80, tt.minTPS, 1000)
+			if got != tt.want {
+				t.Errorf("NextTPS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestController_Poll(t *testing.T) {
+	client := &mockClient{blocks: make(map[uint64]*types.Block)}
+	client.addBlock(1, 1000, 400)
+	client.addBlock(2, 1001, 400)
+
+	cfg := DefaultConfig()
+	cfg.Window = 2
+	ctrl := New(client, cfg)
+	ctrl.lastBlock = 0
+
+	setter := &recordingSetter{}
+	ctrl.poll(context.Background(), setter)
+
+	if ctrl.blocksObserved != 2 {
+		t.Fatalf("blocksObserved = %d, want 2", ctrl.blocksObserved)
+	}
+	if len(setter.rates) != 1 || setter.rates[0] != 150 {
+		t.Errorf("rates = %v, want [150]", setter.rates)
+	}
+	if ctrl.InBand() {
+		t.Error("40%% utilization should not be in band for an 80%% target")
+	}
+}
+
+func TestController_EquilibriumTPS(t *testing.T) {
+	client := &mockClient{blocks: make(map[uint64]*types.Block)}
+	cfg := DefaultConfig()
+	cfg.Window = 1
+	ctrl := New(client, cfg)
+
+	base := time.Unix(1000, 0)
+	ctrl.observe(80, 100, base)
+	ctrl.observe(82, 200, base.Add(2*time.Second))
+	ctrl.observe(78, 200, base.Add(4*time.Second))
+
+	res := ctrl.result()
+	if res.BlocksInBand != 2 {
+		t.Errorf("BlocksInBand = %d, want 2", res.BlocksInBand)
+	}
+	if res.EquilibriumTPS != 100 {
+		t.Errorf("EquilibriumTPS = %v, want 100", res.EquilibriumTPS)
+	}
+	if res.AvgUtilization != 80 {
+		t.Errorf("AvgUtilization = %v, want 80", res.AvgUtilization)
+	}
+}
@@ -0,0 +1,58 @@
+package utiltarget
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Client defines the interface for observing block utilization
+type Client interface {
+	// BlockNumber returns the latest block number
+	BlockNumber(ctx context.Context) (uint64, error)
+	// BlockByNumber returns a block by its number
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+}
+
+// RateSetter adjusts the send rate of a running sender
+type RateSetter interface {
+	SetTPS(tps float64)
+}
+
+// Config holds configuration for the utilization controller
+type Config struct {
+	TargetUtilization float64       // Target average gas utilization (percent)
+	Tolerance         float64       // Allowed deviation from target (percentage points)
+	InitialTPS        float64       // Send rate used before any blocks are observed
+	MinTPS            float64       // Lower bound for the send rate
+	MaxTPS            float64       // Upper bound for the send rate
+	Window            int           // Number of recent blocks in the moving average
+	PollInterval      time.Duration // How often to poll for new blocks
+}
+
+// DefaultConfig returns default controller configuration
+func DefaultConfig() *Config {
+	return &Config{
+		TargetUtilization: 80,
+		Tolerance:         5,
+		InitialTPS:        100,
+		MinTPS:            1,
+		MaxTPS:            100000,
+		Window:            5,
+		PollInterval:      time.Second,
+	}
+}
+
+// Result holds the outcome of a utilization targeting run
+type Result struct {
+	TargetUtilization float64
+	AvgUtilization    float64 // Average utilization over all observed blocks
+	BlocksObserved    int
+	BlocksInBand      int     // Blocks observed while the moving average was within tolerance
+	Adjustments       int     // Number of send rate changes
+	FinalTPS          float64 // Send rate at the end of the run
+	EquilibriumTPS    float64 // On-chain TPS of blocks produced while in band
+	EquilibriumInput  float64 // Average send rate while in band
+}
@@ -0,0 +1,106 @@
+package wallet
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NewFromKeyFile creates a wallet whose sub-accounts are loaded from a key file
+// instead of derived from the master key. The file is either a JSON array of hex
+// private keys or one hex key per line; blank lines and lines starting with # are ignored.
+// An empty privateKeyHex leaves the master key out, for a master account that
+// signs remotely.
+func NewFromKeyFile(privateKeyHex, keyFile string) (*Wallet, error) {
+	var masterKey *ecdsa.PrivateKey
+	if privateKeyHex != "" {
+		var err error
+		masterKey, err = crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	subKeys, err := ParseKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", keyFile, err)
+	}
+	if len(subKeys) == 0 {
+		return nil, fmt.Errorf("key file %s contains no keys", keyFile)
+	}
+
+	if masterKey != nil {
+		masterAddr := crypto.PubkeyToAddress(masterKey.PublicKey)
+		for i, key := range subKeys {
+			if crypto.PubkeyToAddress(key.PublicKey) == masterAddr {
+				return nil, fmt.Errorf("key file %s: key %d is the master account", keyFile, i+1)
+			}
+		}
+	}
+
+	return &Wallet{
+		masterKey:   masterKey,
+		subKeys:     subKeys,
+		useMnemonic: false,
+	}, nil
+}
+
+// ParseKeys parses a JSON array or newline-delimited list of hex private keys,
+// rejecting invalid and duplicate keys
+func ParseKeys(data []byte) ([]*ecdsa.PrivateKey, error) {
+	type entry struct {
+		line int
+		hex  string
+	}
+	var entries []entry
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var hexKeys []string
+		if err := json.Unmarshal(trimmed, &hexKeys); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		for i, h := range hexKeys {
+			entries = append(entries, entry{line: i + 1, hex: strings.TrimSpace(h)})
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			entries = append(entries, entry{line: line, hex: text})
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]*ecdsa.PrivateKey, 0, len(entries))
+	seen := make(map[common.Address]int, len(entries))
+	for _, e := range entries {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(e.hex, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: invalid private key: %w", e.line, err)
+		}
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		if first, ok := seen[addr]; ok {
+			return nil, fmt.Errorf("entry %d: duplicate of entry %d (%s)", e.line, first, addr.Hex())
+		}
+		seen[addr] = e.line
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,74 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func writeKeyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	return path
+}
+
+func TestNewFromKeyFile(t *testing.T) {
+	key1 := "0x" + strings.Repeat("11", 32)
+	key2 := strings.Repeat("22", 32)
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr string
+	}{
+		{"newline delimited", "# funded accounts\n" + key1 + "\n\n" + key2 + "\n", 2, ""},
+		{"json array", `["` + key1 + `", "` + key2 + `"]`, 2, ""},
+		{"duplicate", key1 + "\n" + strings.TrimPrefix(key1, "0x") + "\n", 0, "duplicate of entry 1"},
+		{"invalid key", key1 + "\nnot-a-key\n", 0, "entry 2: invalid private key"},
+		{"master key", testPrivateKey + "\n", 0, "master account"},
+		{"empty", "# nothing here\n", 0, "contains no keys"},
+		{"invalid json", `["` + key1 + `",]`, 0, "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewFromKeyFile(testPrivateKey, writeKeyFile(t, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewFromKeyFile() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewFromKeyFile() error = %v", err)
+			}
+			if len(w.SubKeys()) != tt.want {
+				t.Fatalf("SubKeys() count = %d, want %d", len(w.SubKeys()), tt.want)
+			}
+			if got := hex.EncodeToString(crypto.FromECDSA(w.SubKeys()[1])); got != key2 {
+				t.Errorf("second key = %s, want %s", got, key2)
+			}
+		})
+	}
+}
+
+func TestNewFromKeyFile_NoMaster(t *testing.T) {
+	w, err := NewFromKeyFile("", writeKeyFile(t, testPrivateKey+"\n"))
+	if err != nil {
+		t.Fatalf("NewFromKeyFile() error = %v", err)
+	}
+	if w.MasterKey() != nil {
+		t.Error("MasterKey() should be nil without a private key")
+	}
+	if len(w.SubKeys()) != 1 || len(w.AllKeys()) != 1 {
+		t.Errorf("SubKeys() = %d, AllKeys() = %d, want 1", len(w.SubKeys()), len(w.AllKeys()))
+	}
+}
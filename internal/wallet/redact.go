@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RedactedKey wraps a private key so that formatting it with any verb prints
+// the account address instead of key material
+type RedactedKey struct {
+	key *ecdsa.PrivateKey
+}
+
+// Redact wraps key for safe logging
+func Redact(key *ecdsa.PrivateKey) RedactedKey {
+	return RedactedKey{key: key}
+}
+
+// Key returns the wrapped private key
+func (k RedactedKey) Key() *ecdsa.PrivateKey {
+	return k.key
+}
+
+// String implements fmt.Stringer
+func (k RedactedKey) String() string {
+	if k.key == nil || k.key.D == nil || k.key.D.Sign() == 0 {
+		return "PrivateKey(<empty>)"
+	}
+	return fmt.Sprintf("PrivateKey(%s, REDACTED)", crypto.PubkeyToAddress(k.key.PublicKey).Hex())
+}
+
+// GoString implements fmt.GoStringer so %#v is redacted too
+func (k RedactedKey) GoString() string {
+	return k.String()
+}
+
+// Format implements fmt.Formatter; every verb, including %x and %d, prints String
+func (k RedactedKey) Format(f fmt.State, _ rune) {
+	fmt.Fprint(f, k.String())
+}
+
+// ZeroKey overwrites the private scalar of key in place. The key cannot sign
+// afterwards.
+func ZeroKey(key *ecdsa.PrivateKey) {
+	if key == nil || key.D == nil {
+		return
+	}
+	words := key.D.Bits()
+	clear(words)
+	key.D.SetInt64(0)
+}
+
+// ContainsKey reports whether s contains the private scalar of key in any
+// of the forms fmt or hex encoding would print it: hex in either case, with
+// or without 0x, or decimal
+func ContainsKey(s string, key *ecdsa.PrivateKey) bool {
+	if key == nil || key.D == nil || key.D.Sign() == 0 {
+		return false
+	}
+	keyHex := hex.EncodeToString(crypto.FromECDSA(key))
+	lower := strings.ToLower(s)
+	return strings.Contains(lower, keyHex) ||
+		strings.Contains(lower, key.D.Text(16)) ||
+		strings.Contains(s, key.D.Text(10))
+}
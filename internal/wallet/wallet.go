@@ -3,6 +3,8 @@ package wallet
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -10,16 +12,26 @@ import (
 	hdwallet "github.com/miguelmota/go-ethereum-hdwallet"
 )
 
-// Wallet manages accounts for stress testing
+// Wallet manages accounts for stress testing. Keys are safe to read
+// concurrently; Close zeroizes them and later reads return nothing.
 type Wallet struct {
+	mu          sync.RWMutex
 	masterKey   *ecdsa.PrivateKey
 	subKeys     []*ecdsa.PrivateKey
 	hdWallet    *hdwallet.Wallet
 	useMnemonic bool
+	ephemeral   bool // Sub-accounts were generated for this run
+	closed      bool
 }
 
 // NewFromPrivateKey creates a wallet from a private key hex string
 func NewFromPrivateKey(privateKeyHex string, subAccounts uint64) (*Wallet, error) {
+	return NewFromPrivateKeyAt(privateKeyHex, 0, subAccounts)
+}
+
+// NewFromPrivateKeyAt creates a wallet from a private key hex string whose
+// sub-accounts are the derived accounts offset to offset+subAccounts-1
+func NewFromPrivateKeyAt(privateKeyHex string, offset, subAccounts uint64) (*Wallet, error) {
 	// Remove 0x prefix if present
 	if len(privateKeyHex) >= 2 && privateKeyHex[:2] == "0x" {
 		privateKeyHex = privateKeyHex[2:]
@@ -34,13 +46,16 @@ func NewFromPrivateKey(privateKeyHex string, subAccounts uint64) (*Wallet, error
 	subKeys := make([]*ecdsa.PrivateKey, subAccounts)
 	for i := uint64(0); i < subAccounts; i++ {
 		// Use master key hash + index to derive sub-keys
+		masterBytes := crypto.FromECDSA(masterKey)
 		seed := crypto.Keccak256(
-			crypto.FromECDSA(masterKey),
-			[]byte(fmt.Sprintf("subaccount-%d", i)),
+			masterBytes,
+			[]byte(fmt.Sprintf("subaccount-%d", offset+i)),
 		)
 		subKey, err := crypto.ToECDSA(seed)
+		clear(masterBytes)
+		clear(seed)
 		if err != nil {
-			return nil, fmt.Errorf("failed to derive sub-account %d: %w", i, err)
+			return nil, fmt.Errorf("failed to derive sub-account %d: %w", offset+i, err)
 		}
 		subKeys[i] = subKey
 	}
@@ -52,8 +67,24 @@ func NewFromPrivateKey(privateKeyHex string, subAccounts uint64) (*Wallet, error
 	}, nil
 }
 
+// NewEphemeral creates a wallet of freshly generated sub-accounts and no master
+// key, for a master account that signs remotely
+func NewEphemeral(subAccounts uint64) (*Wallet, error) {
+	w := &Wallet{}
+	if err := w.GenerateSubKeys(subAccounts); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
 // NewFromMnemonic creates a wallet from a BIP39 mnemonic
 func NewFromMnemonic(mnemonic string, subAccounts uint64) (*Wallet, error) {
+	return NewFromMnemonicAt(mnemonic, 0, subAccounts)
+}
+
+// NewFromMnemonicAt creates a wallet from a BIP39 mnemonic whose sub-accounts
+// are the derived accounts offset to offset+subAccounts-1
+func NewFromMnemonicAt(mnemonic string, offset, subAccounts uint64) (*Wallet, error) {
 	wallet, err := hdwallet.NewFromMnemonic(mnemonic)
 	if err != nil {
 		return nil, fmt.Errorf("invalid mnemonic: %w", err)
@@ -74,15 +105,15 @@ func NewFromMnemonic(mnemonic string, subAccounts uint64) (*Wallet, error) {
 	// Derive sub-accounts
 	subKeys := make([]*ecdsa.PrivateKey, subAccounts)
 	for i := uint64(0); i < subAccounts; i++ {
-		path := hdwallet.MustParseDerivationPath(fmt.Sprintf("m/44'/60'/0'/0/%d", i+1))
+		path := hdwallet.MustParseDerivationPath(fmt.Sprintf("m/44'/60'/0'/0/%d", offset+i+1))
 		account, err := wallet.Derive(path, false)
 		if err != nil {
-			return nil, fmt.Errorf("failed to derive sub-account %d: %w", i, err)
+			return nil, fmt.Errorf("failed to derive sub-account %d: %w", offset+i, err)
 		}
 
 		subKey, err := wallet.PrivateKey(account)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get sub-account %d private key: %w", i, err)
+			return nil, fmt.Errorf("failed to get sub-account %d private key: %w", offset+i, err)
 		}
 		subKeys[i] = subKey
 	}
@@ -95,23 +126,67 @@ func NewFromMnemonic(mnemonic string, subAccounts uint64) (*Wallet, error) {
 	}, nil
 }
 
-// MasterKey returns the master private key
+// GenerateSubKeys replaces the sub-accounts with freshly generated keys, so a
+// run does not reuse addresses earlier runs have sent from. The replaced keys
+// are zeroized. Generated keys only live for the run; their funds must be
+// reclaimed before exit.
+func (w *Wallet) GenerateSubKeys(subAccounts uint64) error {
+	subKeys := make([]*ecdsa.PrivateKey, subAccounts)
+	for i := range subKeys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return fmt.Errorf("failed to generate sub-account %d: %w", i, err)
+		}
+		subKeys[i] = key
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, key := range w.subKeys {
+		ZeroKey(key)
+	}
+	w.subKeys = subKeys
+	w.ephemeral = true
+	return nil
+}
+
+// Ephemeral reports whether the sub-accounts were generated for this run
+func (w *Wallet) Ephemeral() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.ephemeral
+}
+
+// MasterKey returns the master private key (nil after Close or when the master signs remotely)
 func (w *Wallet) MasterKey() *ecdsa.PrivateKey {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
 	return w.masterKey
 }
 
-// MasterAddress returns the master account address
+// MasterAddress returns the master account address (zero after Close)
 func (w *Wallet) MasterAddress() common.Address {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.masterKey == nil {
+		return common.Address{}
+	}
 	return crypto.PubkeyToAddress(w.masterKey.PublicKey)
 }
 
-// SubKeys returns all sub-account private keys
+// SubKeys returns a copy of the sub-account private keys (nil after Close).
+// The keys themselves are shared: GenerateSubKeys and Close zeroize them in
+// place, so callers must not sign with them after either.
 func (w *Wallet) SubKeys() []*ecdsa.PrivateKey {
-	return w.subKeys
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Clone(w.subKeys)
 }
 
 // SubAddresses returns all sub-account addresses
 func (w *Wallet) SubAddresses() []common.Address {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
 	addresses := make([]common.Address, len(w.subKeys))
 	for i, key := range w.subKeys {
 		addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
@@ -119,24 +194,66 @@ func (w *Wallet) SubAddresses() []common.Address {
 	return addresses
 }
 
-// AllKeys returns all keys (master + sub-accounts)
+// AllKeys returns all keys (master + sub-accounts, nil after Close)
 func (w *Wallet) AllKeys() []*ecdsa.PrivateKey {
-	keys := make([]*ecdsa.PrivateKey, 1+len(w.subKeys))
-	keys[0] = w.masterKey
-	copy(keys[1:], w.subKeys)
-	return keys
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return nil
+	}
+	keys := make([]*ecdsa.PrivateKey, 0, 1+len(w.subKeys))
+	if w.masterKey != nil {
+		keys = append(keys, w.masterKey)
+	}
+	return append(keys, w.subKeys...)
 }
 
 // AllAddresses returns all addresses (master + sub-accounts)
 func (w *Wallet) AllAddresses() []common.Address {
-	addresses := make([]common.Address, 1+len(w.subKeys))
-	addresses[0] = w.MasterAddress()
-	copy(addresses[1:], w.SubAddresses())
-	return addresses
+	addresses := []common.Address{w.MasterAddress()}
+	return append(addresses, w.SubAddresses()...)
+}
+
+// Close zeroizes all private key material held by the wallet. Keys handed
+// out earlier are zeroized in place, so Close must only be called once
+// nothing signs with them anymore. Close is idempotent.
+//
+// A mnemonic wallet's seed and master extended key live in unexported fields
+// of the HD wallet library and cannot be wiped; Close only drops the
+// reference, so they stay in memory until garbage collected.
+func (w *Wallet) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	ZeroKey(w.masterKey)
+	for _, key := range w.subKeys {
+		ZeroKey(key)
+	}
+	w.masterKey = nil
+	w.subKeys = nil
+	w.hdWallet = nil
+	w.closed = true
+}
+
+// Format implements fmt.Formatter so that printing a wallet with any verb
+// shows its addresses, never its keys
+func (w *Wallet) Format(f fmt.State, _ rune) {
+	w.mu.RLock()
+	closed, subs := w.closed, len(w.subKeys)
+	w.mu.RUnlock()
+	if closed {
+		fmt.Fprint(f, "Wallet(closed)")
+		return
+	}
+	fmt.Fprintf(f, "Wallet(master=%s, subAccounts=%d)", w.MasterAddress().Hex(), subs)
 }
 
 // GetAccount returns an account by index (0 = master, 1+ = sub-accounts)
 func (w *Wallet) GetAccount(index int) (accounts.Account, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
 	if w.hdWallet == nil {
 		return accounts.Account{}, fmt.Errorf("account retrieval only available for mnemonic-based wallets")
 	}
@@ -145,6 +262,25 @@ func (w *Wallet) GetAccount(index int) (accounts.Account, error) {
 	return w.hdWallet.Derive(path, false)
 }
 
+// DeriveKey derives the private key at an account index (0 = master, 1+ = sub-accounts)
+func (w *Wallet) DeriveKey(index uint64) (*ecdsa.PrivateKey, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return nil, fmt.Errorf("wallet is closed")
+	}
+	if w.hdWallet == nil {
+		return nil, fmt.Errorf("key derivation only available for mnemonic-based wallets")
+	}
+
+	path := hdwallet.MustParseDerivationPath(fmt.Sprintf("m/44'/60'/0'/0/%d", index))
+	account, err := w.hdWallet.Derive(path, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive account %d: %w", index, err)
+	}
+	return w.hdWallet.PrivateKey(account)
+}
+
 // SignHash signs a hash with the specified key
 func SignHash(key *ecdsa.PrivateKey, hash []byte) ([]byte, error) {
 	return crypto.Sign(hash, key)
@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -239,6 +241,73 @@ func TestWallet_MnemonicDeterministicDerivation(t *testing.T) {
 	}
 }
 
+func TestWallet_SubAccountOffset(t *testing.T) {
+	full, err := NewFromPrivateKey(testPrivateKey, 5)
+	if err != nil {
+		t.Fatalf("NewFromPrivateKey() failed: %v", err)
+	}
+	fullMnemonic, err := NewFromMnemonic(testMnemonic, 5)
+	if err != nil {
+		t.Fatalf("NewFromMnemonic() failed: %v", err)
+	}
+
+	tail, err := NewFromPrivateKeyAt(testPrivateKey, 3, 2)
+	if err != nil {
+		t.Fatalf("NewFromPrivateKeyAt() failed: %v", err)
+	}
+	tailMnemonic, err := NewFromMnemonicAt(testMnemonic, 3, 2)
+	if err != nil {
+		t.Fatalf("NewFromMnemonicAt() failed: %v", err)
+	}
+
+	// Offset wallets share the master and continue the derivation sequence
+	for _, tc := range []struct {
+		name       string
+		full, tail *Wallet
+	}{
+		{"private key", full, tail},
+		{"mnemonic", fullMnemonic, tailMnemonic},
+	} {
+		if tc.tail.MasterAddress() != tc.full.MasterAddress() {
+			t.Errorf("%s: master address differs with an offset", tc.name)
+		}
+		want := tc.full.SubAddresses()[3:]
+		got := tc.tail.SubAddresses()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d sub-accounts, want %d", tc.name, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: SubAddresses()[%d] = %s, want %s", tc.name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestWallet_DeriveKey(t *testing.T) {
+	w, err := NewFromMnemonic(testMnemonic, 2)
+	if err != nil {
+		t.Fatalf("NewFromMnemonic() failed: %v", err)
+	}
+
+	// Index 1 is the first sub-account
+	key, err := w.DeriveKey(1)
+	if err != nil {
+		t.Fatalf("DeriveKey() failed: %v", err)
+	}
+	if AddressFromPrivateKey(key) != w.SubAddresses()[0] {
+		t.Error("DeriveKey(1) should match the first sub-account")
+	}
+
+	pkWallet, err := NewFromPrivateKey(testPrivateKey, 2)
+	if err != nil {
+		t.Fatalf("NewFromPrivateKey() failed: %v", err)
+	}
+	if _, err := pkWallet.DeriveKey(3); err == nil {
+		t.Error("DeriveKey() should fail for private-key wallets")
+	}
+}
+
 func TestSignHash(t *testing.T) {
 	w, err := NewFromPrivateKey(testPrivateKey, 0)
 	if err != nil {
@@ -299,3 +368,124 @@ func TestWallet_UniqueSubAccounts(t *testing.T) {
 		t.Error("Master address found in sub accounts")
 	}
 }
+
+func TestWallet_SubKeysCopy(t *testing.T) {
+	w, err := NewFromPrivateKey(testPrivateKey, 2)
+	if err != nil {
+		t.Fatalf("NewFromPrivateKey() failed: %v", err)
+	}
+	defer w.Close()
+
+	keys := w.SubKeys()
+	keys[0] = nil
+	if w.SubKeys()[0] == nil {
+		t.Error("SubKeys() returned the wallet's own slice")
+	}
+}
+
+func TestWallet_Close(t *testing.T) {
+	w, err := NewFromMnemonic(testMnemonic, 3)
+	if err != nil {
+		t.Fatalf("NewFromMnemonic() failed: %v", err)
+	}
+	keys := w.AllKeys()
+
+	w.Close()
+	w.Close() // idempotent
+
+	for i, key := range keys {
+		if key.D.Sign() != 0 {
+			t.Errorf("key %d was not zeroized", i)
+		}
+	}
+	if w.MasterKey() != nil || w.SubKeys() != nil || w.AllKeys() != nil {
+		t.Error("keys still available after Close()")
+	}
+	if w.MasterAddress() != (common.Address{}) {
+		t.Errorf("MasterAddress() after Close() = %s, want zero", w.MasterAddress().Hex())
+	}
+	if _, err := w.DeriveKey(1); err == nil {
+		t.Error("DeriveKey() after Close() should fail")
+	}
+}
+
+func TestNewEphemeral(t *testing.T) {
+	w, err := NewEphemeral(3)
+	if err != nil {
+		t.Fatalf("NewEphemeral() failed: %v", err)
+	}
+	if w.MasterKey() != nil || w.MasterAddress() != (common.Address{}) {
+		t.Error("ephemeral wallet should have no master key")
+	}
+	if len(w.SubKeys()) != 3 || len(w.AllKeys()) != 3 {
+		t.Fatalf("SubKeys() = %d, AllKeys() = %d, want 3", len(w.SubKeys()), len(w.AllKeys()))
+	}
+
+	other, err := NewEphemeral(3)
+	if err != nil {
+		t.Fatalf("NewEphemeral() failed: %v", err)
+	}
+	if w.SubAddresses()[0] == other.SubAddresses()[0] {
+		t.Error("ephemeral wallets should not share keys")
+	}
+	if !w.Ephemeral() {
+		t.Error("Ephemeral() = false, want true")
+	}
+	w.Close()
+}
+
+func TestWallet_GenerateSubKeys(t *testing.T) {
+	w, err := NewFromPrivateKey(testPrivateKey, 2)
+	if err != nil {
+		t.Fatalf("NewFromPrivateKey() failed: %v", err)
+	}
+	derived := w.SubKeys()
+	if w.Ephemeral() {
+		t.Error("derived wallet should not be ephemeral")
+	}
+
+	if err := w.GenerateSubKeys(4); err != nil {
+		t.Fatalf("GenerateSubKeys() failed: %v", err)
+	}
+	if !w.Ephemeral() || len(w.SubKeys()) != 4 {
+		t.Fatalf("Ephemeral() = %v, SubKeys() = %d, want true and 4", w.Ephemeral(), len(w.SubKeys()))
+	}
+	for i, key := range derived {
+		if key.D.Sign() != 0 {
+			t.Errorf("derived key %d was not zeroized", i)
+		}
+	}
+	if w.MasterKey() == nil {
+		t.Error("GenerateSubKeys() should keep the master key")
+	}
+	w.Close()
+}
+
+func TestRedact(t *testing.T) {
+	w, err := NewFromPrivateKey(testPrivateKey, 1)
+	if err != nil {
+		t.Fatalf("NewFromPrivateKey() failed: %v", err)
+	}
+	key := w.MasterKey()
+
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%x", "%d"} {
+		out := fmt.Sprintf(verb, Redact(key))
+		if ContainsKey(out, key) {
+			t.Errorf("Sprintf(%q, Redact(key)) leaks the key: %s", verb, out)
+		}
+		if !strings.Contains(out, w.MasterAddress().Hex()) {
+			t.Errorf("Sprintf(%q, Redact(key)) = %s, want the address", verb, out)
+		}
+		if out := fmt.Sprintf(verb, w); ContainsKey(out, key) {
+			t.Errorf("Sprintf(%q, wallet) leaks the master key: %s", verb, out)
+		}
+	}
+
+	// Sanity check that the detector sees unredacted keys
+	if !ContainsKey(fmt.Sprintf("%+v", *key), key) {
+		t.Error("ContainsKey() missed a key printed with a plus-flag verb")
+	}
+	if !ContainsKey(strings.ToUpper(testPrivateKey), key) {
+		t.Error("ContainsKey() missed an upper-case hex key")
+	}
+}
@@ -0,0 +1,26 @@
+// Package wallettest provides test helpers that guard against private keys
+// leaking into logs, reports and other formatted output.
+package wallettest
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/0xmhha/txhammer/internal/wallet"
+)
+
+// AssertNoKeys fails the test if output contains any of keys
+func AssertNoKeys(t testing.TB, output string, keys ...*ecdsa.PrivateKey) {
+	t.Helper()
+	for i, key := range keys {
+		if wallet.ContainsKey(output, key) {
+			t.Errorf("output leaks private key %d (%s)", i, wallet.Redact(key))
+		}
+	}
+}
+
+// AssertNoWalletKeys fails the test if output contains any key held by w
+func AssertNoWalletKeys(t testing.TB, output string, w *wallet.Wallet) {
+	t.Helper()
+	AssertNoKeys(t, output, w.AllKeys()...)
+}
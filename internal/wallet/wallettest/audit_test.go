@@ -0,0 +1,25 @@
+package wallettest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0xmhha/txhammer/internal/wallet"
+)
+
+const testPrivateKey = "0x0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestAssertNoWalletKeys(t *testing.T) {
+	w, err := wallet.NewFromPrivateKey(testPrivateKey, 2)
+	if err != nil {
+		t.Fatalf("NewFromPrivateKey() failed: %v", err)
+	}
+
+	AssertNoWalletKeys(t, fmt.Sprintf("%+v %v", w, wallet.Redact(w.SubKeys()[1])), w)
+
+	leaky := &testing.T{}
+	AssertNoWalletKeys(leaky, fmt.Sprintf("sub key: %v", w.SubKeys()[1].D), w)
+	if !leaky.Failed() {
+		t.Error("AssertNoWalletKeys() did not fail on a leaked sub-account key")
+	}
+}
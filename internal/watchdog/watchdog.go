@@ -0,0 +1,192 @@
+package watchdog
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xmhha/txhammer/internal/util/console"
+)
+
+// Client defines the interface for observing block progress
+type Client interface {
+	// BlockNumber returns the latest block number
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Config holds configuration for the watchdog
+type Config struct {
+	Window       time.Duration // No new block for this long counts as a halt
+	PollInterval time.Duration // How often to poll the block number
+}
+
+// DefaultConfig returns default watchdog configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Window:       60 * time.Second,
+		PollInterval: time.Second,
+	}
+}
+
+// Halt describes a period during which block production stopped
+type Halt struct {
+	Start     time.Time     `json:"start"`
+	End       *time.Time    `json:"end,omitempty"` // Nil if the chain never resumed
+	LastBlock uint64        `json:"last_block"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Callbacks for halt notifications
+type Callbacks struct {
+	OnHalt   func(h Halt)
+	OnResume func(h Halt)
+}
+
+// Watchdog detects chain halts and pauses senders while the chain is halted
+type Watchdog struct {
+	client    Client
+	config    *Config
+	callbacks *Callbacks
+
+	mu           sync.Mutex
+	lastBlock    uint64
+	lastProgress time.Time
+	halted       bool
+	resume       chan struct{}
+	halts        []Halt
+}
+
+// New creates a new Watchdog instance
+func New(client Client, config *Config) *Watchdog {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	return &Watchdog{
+		client: client,
+		config: config,
+		halts:  make([]Halt, 0),
+	}
+}
+
+// WithCallbacks sets the halt notification callbacks
+func (w *Watchdog) WithCallbacks(callbacks *Callbacks) *Watchdog {
+	w.callbacks = callbacks
+	return w
+}
+
+// Run polls block progress until the context is done
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	w.mu.Lock()
+	w.lastProgress = time.Now()
+	w.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.release()
+			return
+		case <-ticker.C:
+			blockNum, err := w.client.BlockNumber(ctx)
+			if err != nil {
+				continue
+			}
+			w.observe(blockNum, time.Now())
+		}
+	}
+}
+
+// observe updates the halt state from a block number observation
+func (w *Watchdog) observe(blockNum uint64, now time.Time) {
+	w.mu.Lock()
+
+	if blockNum > w.lastBlock {
+		w.lastBlock = blockNum
+		w.lastProgress = now
+		if !w.halted {
+			w.mu.Unlock()
+			return
+		}
+
+		// Chain resumed
+		w.halted = false
+		h := &w.halts[len(w.halts)-1]
+		h.End = &now
+		h.Duration = now.Sub(h.Start)
+		resumed := *h
+		close(w.resume)
+		w.mu.Unlock()
+
+		console.OKf("\nBlock production resumed at #%d after %s, resuming sending\n", blockNum, resumed.Duration)
+		if w.callbacks != nil && w.callbacks.OnResume != nil {
+			w.callbacks.OnResume(resumed)
+		}
+		return
+	}
+
+	if w.halted || w.lastBlock == 0 || now.Sub(w.lastProgress) < w.config.Window {
+		w.mu.Unlock()
+		return
+	}
+
+	// Chain halted
+	w.halted = true
+	w.resume = make(chan struct{})
+	halt := Halt{Start: w.lastProgress, LastBlock: w.lastBlock}
+	w.halts = append(w.halts, halt)
+	w.mu.Unlock()
+
+	console.Warnf("\nNo new block since #%d for %s, chain appears halted; pausing sending\n", halt.LastBlock, now.Sub(halt.Start))
+	if w.callbacks != nil && w.callbacks.OnHalt != nil {
+		w.callbacks.OnHalt(halt)
+	}
+}
+
+// release unblocks any waiters when the watchdog stops
+func (w *Watchdog) release() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.halted {
+		w.halted = false
+		close(w.resume)
+	}
+}
+
+// Wait blocks while the chain is halted
+func (w *Watchdog) Wait(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.halted {
+		w.mu.Unlock()
+		return nil
+	}
+	resume := w.resume
+	w.mu.Unlock()
+
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Halted returns true if the chain is currently considered halted
+func (w *Watchdog) Halted() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.halted
+}
+
+// Halts returns the halt windows observed so far
+func (w *Watchdog) Halts() []Halt {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	halts := make([]Halt, len(w.halts))
+	copy(halts, w.halts)
+	return halts
+}
@@ -0,0 +1,73 @@
+package watchdog
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchdog_Observe(t *testing.T) {
+	w := New(nil, &Config{Window: 10 * time.Second, PollInterval: time.Second})
+	start := time.Now()
+
+	var halted, resumed int
+	w.WithCallbacks(&Callbacks{
+		OnHalt:   func(Halt) { halted++ },
+		OnResume: func(Halt) { resumed++ },
+	})
+
+	w.observe(100, start)
+	w.observe(100, start.Add(5*time.Second))
+	if w.Halted() {
+		t.Fatal("should not be halted before window elapses")
+	}
+
+	w.observe(100, start.Add(11*time.Second))
+	if !w.Halted() {
+		t.Fatal("should be halted after window elapses")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := w.Wait(ctx); err == nil {
+		t.Error("Wait() should block while halted")
+	}
+
+	w.observe(101, start.Add(20*time.Second))
+	if w.Halted() {
+		t.Fatal("should resume after a new block")
+	}
+	if err := w.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() error = %v", err)
+	}
+
+	halts := w.Halts()
+	if len(halts) != 1 {
+		t.Fatalf("expected 1 halt, got %d", len(halts))
+	}
+	if halts[0].LastBlock != 100 || halts[0].Duration != 20*time.Second {
+		t.Errorf("unexpected halt: %+v", halts[0])
+	}
+	if halted != 1 || resumed != 1 {
+		t.Errorf("callbacks: halted=%d resumed=%d, want 1/1", halted, resumed)
+	}
+}
+
+func TestHalt_JSON(t *testing.T) {
+	h := Halt{Start: time.Now(), LastBlock: 100}
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"end"`) {
+		t.Errorf("open halt = %s, want no end", data)
+	}
+
+	end := h.Start.Add(time.Minute)
+	h.End = &end
+	if data, _ = json.Marshal(h); !strings.Contains(string(data), `"end"`) {
+		t.Errorf("resumed halt = %s, want its end", data)
+	}
+}
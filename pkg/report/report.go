@@ -0,0 +1,301 @@
+// Package report defines the JSON report format written by txhammer.
+//
+// The format is versioned through the schema_version field. Within a schema
+// version fields are only ever added, never renamed or removed, so consumers
+// can safely ignore unknown fields. Any incompatible change bumps
+// SchemaVersion and Parse upgrades older documents to the current layout.
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// SchemaVersion is the version of the report format written by this build
+	SchemaVersion = 3
+
+	// SchemaVersionLegacy is the implicit version of reports written before
+	// schema_version existed
+	SchemaVersionLegacy = 1
+)
+
+// ErrUnsupportedVersion is returned when a report is newer than this build understands
+var ErrUnsupportedVersion = errors.New("unsupported report schema version")
+
+// Report is the JSON report written to report_<timestamp>.json
+type Report struct {
+	SchemaVersion int       `json:"schema_version"`
+	TestName      string    `json:"test_name"`
+	StartTime     string    `json:"start_time"`        // RFC3339
+	EndTime       string    `json:"end_time"`          // RFC3339
+	Duration      string    `json:"duration"`          // Go duration string
+	Partial       bool      `json:"partial,omitempty"` // Cut short by the run deadline
+	Build         *Build    `json:"build,omitempty"`   // txhammer build that wrote the report
+	Region        string    `json:"region,omitempty"`  // Region or worker that submitted the txs
+	Workload      *Workload `json:"workload,omitempty"`
+	Summary       Summary   `json:"summary"`
+	Latency       Latency   `json:"latency"`
+	Gas           Gas       `json:"gas"`
+	Blocks        Blocks    `json:"blocks"`
+	Halts         []Halt    `json:"halts,omitempty"` // Added in version 2
+	SlowestTxs    []Tx      `json:"slowest_txs,omitempty"`
+	FastestTxs    []Tx      `json:"fastest_txs,omitempty"`
+
+	UtilizationLatency []UtilizationBin `json:"utilization_latency,omitempty"`
+	Fairness           *Fairness        `json:"fairness,omitempty"`
+	LatencyHeatmap     *LatencyHeatmap  `json:"latency_heatmap,omitempty"`
+	Activation         *Activation      `json:"activation,omitempty"`
+	Producers          []Producer       `json:"producers,omitempty"`
+	Events             *Events          `json:"events,omitempty"`
+}
+
+// Build identifies the txhammer binary that wrote a report
+type Build struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date,omitempty"` // Build time as set by the release, usually RFC3339
+	GoVersion string `json:"go_version"`
+}
+
+// Producer holds the inclusion statistics of our txs in the blocks of one
+// block producer
+type Producer struct {
+	Miner      string  `json:"miner"`           // Coinbase of its blocks
+	Label      string  `json:"label,omitempty"` // Printable extraData
+	Blocks     int     `json:"blocks"`          // Blocks including our txs
+	Txs        int     `json:"txs"`             // Confirmed txs
+	Share      float64 `json:"share"`           // Percent of all confirmed txs
+	AvgLatency string  `json:"avg_latency"`
+	P95Latency string  `json:"p95_latency"`
+}
+
+// Events counts the successful txs that emitted an expected event log, so
+// that a tx that succeeded without doing its work is not counted as a success
+type Events struct {
+	Checked int          `json:"checked"` // Successful txs
+	Emitted int          `json:"emitted"` // Of them, emitted at least one expected event
+	Missing int          `json:"missing"` // Of them, emitted none
+	Rate    float64      `json:"rate"`    // Percent of checked txs that emitted one
+	Events  []EventCount `json:"events"`
+}
+
+// EventCount is the number of logs of one expected event
+type EventCount struct {
+	Signature string `json:"signature"`
+	Logs      int    `json:"logs"`
+}
+
+// Activation splits the included txs at a block height, such as a fork
+// activation given with --stop-at-block
+type Activation struct {
+	Block  uint64          `json:"block"`
+	Before ActivationPhase `json:"before"` // Included below block
+	After  ActivationPhase `json:"after"`  // Included at or above block
+}
+
+// ActivationPhase holds the metrics of the txs included on one side of the activation block
+type ActivationPhase struct {
+	Confirmed  int     `json:"confirmed"`
+	Reverted   int     `json:"reverted"`
+	Blocks     int     `json:"blocks"` // Blocks including our txs
+	AvgLatency string  `json:"avg_latency"`
+	P95Latency string  `json:"p95_latency"`
+	ChainTPS   float64 `json:"chain_tps"`
+}
+
+// LatencyHeatmap counts confirmed txs per interval of confirmation time and
+// latency bucket, for showing how the latency distribution evolved
+type LatencyHeatmap struct {
+	Interval string       `json:"interval"` // Go duration string
+	Start    string       `json:"start"`    // RFC3339 time of the first confirmation
+	Buckets  []string     `json:"buckets"`  // Latency bucket labels, as in latency.histogram
+	Rows     []HeatmapRow `json:"rows"`     // One per interval, including empty ones
+}
+
+// HeatmapRow holds the latency bucket counts of one interval
+type HeatmapRow struct {
+	Offset string `json:"offset"` // Go duration from start to the interval start
+	Txs    int    `json:"txs"`
+	Counts []int  `json:"counts"` // Parallel to buckets
+}
+
+// Fairness relates inclusion order to submission time, tip and sender,
+// counting only pairs of transactions from different senders
+type Fairness struct {
+	Txs           int              `json:"txs"`
+	Score         float64          `json:"score"` // Percent of pairs included in submission order
+	Pairs         int64            `json:"pairs"`
+	Inversions    int64            `json:"inversions"`
+	TipOrdered    float64          `json:"tip_ordered,omitempty"` // Percent of same-block pairs with the higher tip first
+	TipPairs      int64            `json:"tip_pairs,omitempty"`
+	SenderBias    float64          `json:"sender_bias,omitempty"` // 0-1 spread of mean relative in-block position
+	FavoredSender string           `json:"favored_sender,omitempty"`
+	Examples      []OrderInversion `json:"examples,omitempty"`
+}
+
+// OrderInversion is a transaction included after one another sender submitted later
+type OrderInversion struct {
+	Early      string `json:"early"` // Submitted first, included last
+	EarlyFrom  string `json:"early_from"`
+	EarlyBlock uint64 `json:"early_block"`
+	EarlyIndex uint   `json:"early_index"`
+	Late       string `json:"late"`
+	LateFrom   string `json:"late_from"`
+	LateBlock  uint64 `json:"late_block"`
+	LateIndex  uint   `json:"late_index"`
+	SentGap    string `json:"sent_gap"` // Go duration string
+}
+
+// UtilizationBin is the latency of txs confirmed while the fuller of their
+// inclusion block and the block before it was [low, high) percent utilized
+type UtilizationBin struct {
+	Low           float64 `json:"low"`
+	High          float64 `json:"high"`
+	Txs           int     `json:"txs"`
+	MedianLatency string  `json:"median_latency"`
+	P95Latency    string  `json:"p95_latency"`
+}
+
+// Tx is a confirmed transaction listed among the latency outliers
+type Tx struct {
+	Hash        string `json:"hash"`
+	From        string `json:"from"`
+	Nonce       uint64 `json:"nonce"`
+	BlockNumber uint64 `json:"block_number"`
+	GasPrice    string `json:"gas_price"` // Effective gas price in wei
+	Latency     string `json:"latency"`
+}
+
+// Halt is a chain halt window observed during the run
+type Halt struct {
+	Start     string `json:"start"`
+	End       string `json:"end,omitempty"` // Empty if the chain never resumed
+	LastBlock uint64 `json:"last_block"`
+	Duration  string `json:"duration,omitempty"`
+}
+
+// Summary holds transaction counts and throughput
+type Summary struct {
+	TotalSent      int     `json:"total_sent"`
+	TotalConfirmed int     `json:"total_confirmed"`
+	TotalFailed    int     `json:"total_failed"`
+	TotalTimeout   int     `json:"total_timeout"`
+	TotalEvicted   int     `json:"total_evicted"` // Added in version 2
+	TotalPending   int     `json:"total_pending"`
+	SuccessRate    float64 `json:"success_rate"` // Percent
+
+	// ChainTPS is confirmed txs over the block-timestamp window of their inclusion
+	// blocks; it is the canonical throughput figure. Added in version 3.
+	ChainTPS    float64 `json:"chain_tps"`
+	ChainWindow string  `json:"chain_window"` // Added in version 3
+
+	// Wall-clock rates over the collection duration, including polling overhead.
+	// Named tps and confirmed_tps before version 3.
+	WallClockTPS          float64 `json:"wall_clock_tps"`
+	WallClockConfirmedTPS float64 `json:"wall_clock_confirmed_tps"`
+
+	// Peak TPS over the best peak_window of chain time and the blocks it spans;
+	// omitted when the run was shorter than the window
+	PeakTPS        float64 `json:"peak_tps,omitempty"`
+	PeakWindow     string  `json:"peak_window,omitempty"` // Go duration string
+	PeakStartBlock uint64  `json:"peak_start_block,omitempty"`
+	PeakEndBlock   uint64  `json:"peak_end_block,omitempty"`
+}
+
+// summaryV2 holds the summary fields renamed in version 3
+type summaryV2 struct {
+	TPS          float64 `json:"tps"`
+	ConfirmedTPS float64 `json:"confirmed_tps"`
+}
+
+// Latency holds confirmation latency statistics as Go duration strings
+type Latency struct {
+	Average   string         `json:"average"`
+	Min       string         `json:"min"`
+	Max       string         `json:"max"`
+	P50       string         `json:"p50"`
+	P95       string         `json:"p95"`
+	P99       string         `json:"p99"`
+	Histogram map[string]int `json:"histogram"`
+}
+
+// Gas holds gas usage; costs are decimal wei strings
+type Gas struct {
+	TotalUsed   uint64 `json:"total_used"`
+	AverageUsed uint64 `json:"average_used"`
+	TotalCost   string `json:"total_cost"` // Execution cost plus L1 data fees
+	AverageCost string `json:"average_cost"`
+
+	// Split of total_cost on rollups reporting L1 data fees (empty elsewhere)
+	ExecutionCost string `json:"execution_cost,omitempty"`
+	L1Fee         string `json:"l1_fee,omitempty"`
+}
+
+// Blocks holds block-level statistics
+type Blocks struct {
+	Observed         int     `json:"observed"`
+	AvgBlockTime     string  `json:"avg_block_time"`
+	AvgTxPerBlock    float64 `json:"avg_tx_per_block"`
+	AvgUtilization   float64 `json:"avg_utilization"`
+	FirstBlockWithTx uint64  `json:"first_block_with_tx,omitempty"`
+	LastBlockWithTx  uint64  `json:"last_block_with_tx,omitempty"`
+	BlockSpan        int     `json:"block_span,omitempty"`
+	BlocksWithOurTx  int     `json:"blocks_with_our_tx,omitempty"`
+	BlockBasedTPS    float64 `json:"block_based_tps,omitempty"`
+}
+
+// New returns an empty report stamped with the current schema version
+func New() *Report {
+	return &Report{SchemaVersion: SchemaVersion}
+}
+
+// Parse decodes a report of any supported version and upgrades it to the current layout
+func Parse(data []byte) (*Report, error) {
+	var probe struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+
+	version := probe.SchemaVersion
+	if version == 0 {
+		version = SchemaVersionLegacy
+	}
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("%w: %d (max %d)", ErrUnsupportedVersion, version, SchemaVersion)
+	}
+
+	r := &Report{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+
+	// Version 1 is a strict subset of version 2; missing fields decode as zero values.
+	// Version 3 renamed the wall-clock TPS fields.
+	if version < 3 {
+		var legacy struct {
+			Summary summaryV2 `json:"summary"`
+		}
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, fmt.Errorf("failed to parse report: %w", err)
+		}
+		r.Summary.WallClockTPS = legacy.Summary.TPS
+		r.Summary.WallClockConfirmedTPS = legacy.Summary.ConfirmedTPS
+	}
+
+	r.SchemaVersion = SchemaVersion
+	return r, nil
+}
+
+// Load reads and parses a report file
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	return Parse(data)
+}
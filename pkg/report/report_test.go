@@ -0,0 +1,97 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// legacyReport is a report as written before schema_version existed
+const legacyReport = `{
+  "test_name": "stress-test",
+  "start_time": "2024-01-15T14:30:52+09:00",
+  "end_time": "2024-01-15T14:31:07+09:00",
+  "duration": "15.234s",
+  "summary": {
+    "total_sent": 1000,
+    "total_confirmed": 998,
+    "total_failed": 2,
+    "total_timeout": 0,
+    "total_pending": 0,
+    "success_rate": 99.8,
+    "tps": 65.64,
+    "confirmed_tps": 65.51
+  },
+  "latency": {"average": "234ms", "p99": "890ms"},
+  "gas": {"total_used": 20958000, "average_used": 21000, "total_cost": "20958000000000000"},
+  "blocks": {"observed": 10}
+}`
+
+func TestParse_Legacy(t *testing.T) {
+	r, err := Parse([]byte(legacyReport))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if r.SchemaVersion != SchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", r.SchemaVersion, SchemaVersion)
+	}
+	if r.Summary.TotalConfirmed != 998 || r.Latency.P99 != "890ms" || r.Blocks.Observed != 10 {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	if r.Summary.WallClockTPS != 65.64 || r.Summary.WallClockConfirmedTPS != 65.51 {
+		t.Errorf("renamed TPS fields not migrated: %+v", r.Summary)
+	}
+	if r.Summary.TotalEvicted != 0 || len(r.Halts) != 0 {
+		t.Error("fields added in version 2 should be zero for legacy reports")
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	want := New()
+	want.TestName = "roundtrip"
+	want.Summary.TotalEvicted = 3
+	want.Halts = []Halt{{Start: "2024-01-15T14:30:52Z", LastBlock: 42}}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.TestName != "roundtrip" || got.Summary.TotalEvicted != 3 || len(got.Halts) != 1 {
+		t.Errorf("unexpected report: %+v", got)
+	}
+}
+
+func TestParse_UnsupportedVersion(t *testing.T) {
+	_, err := Parse([]byte(`{"schema_version": 999}`))
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Parse() error = %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+func TestWorkloadHash(t *testing.T) {
+	params := map[string]string{"mode": "TRANSFER", "transactions": "1000"}
+	w := NewWorkload(params)
+	if len(w.Hash) != 64 {
+		t.Fatalf("Hash = %q, want 64 hex digits", w.Hash)
+	}
+
+	// Unset parameters do not change the hash
+	if got := WorkloadHash(map[string]string{"transactions": "1000", "mode": "TRANSFER", "tx-type": ""}); got != w.Hash {
+		t.Errorf("WorkloadHash() with an empty parameter = %s, want %s", got, w.Hash)
+	}
+	if got := WorkloadHash(map[string]string{"mode": "TRANSFER", "transactions": "1001"}); got == w.Hash {
+		t.Error("WorkloadHash() should change with a parameter value")
+	}
+}
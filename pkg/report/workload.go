@@ -0,0 +1,44 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// Workload identifies the transactions of a run by the parameters that
+// define them. Two reports with the same hash sent the same workload, even
+// from other accounts, endpoints or chains.
+type Workload struct {
+	Hash       string            `json:"hash"`       // See WorkloadHash
+	Parameters map[string]string `json:"parameters"` // Flag name to value
+}
+
+// NewWorkload returns the workload defined by params
+func NewWorkload(params map[string]string) *Workload {
+	return &Workload{Hash: WorkloadHash(params), Parameters: params}
+}
+
+// WorkloadHash returns the hex SHA-256 of params written as "name=value"
+// lines sorted by name. Empty values are left out, so a parameter added in a
+// later version does not change the hash of runs that do not set it.
+func WorkloadHash(params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name, value := range params {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(params[name])
+		b.WriteByte('\n')
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}